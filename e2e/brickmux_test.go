@@ -77,6 +77,7 @@ func TestBrickMux(t *testing.T) {
 	for _, b := range bstatus {
 		r.Equal(pid, b.Pid)
 		r.Equal(port, b.Port)
+		r.Equal(3, b.BricksInProc)
 	}
 
 	// create another compatible volume
@@ -114,6 +115,7 @@ func TestBrickMux(t *testing.T) {
 	for _, b := range bstatus {
 		r.Equal(pid, b.Pid)
 		r.Equal(port, b.Port)
+		r.Equal(5, b.BricksInProc)
 	}
 
 	// kill the brick from first volume into which all the brick have been multiplexed
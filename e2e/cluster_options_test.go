@@ -0,0 +1,101 @@
+package e2e
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/require"
+)
+
+func clusterOptionValue(t *testing.T, opts []api.ClusterOptionsResp, key string) api.ClusterOptionsResp {
+	for _, opt := range opts {
+		if opt.Key == key {
+			return opt
+		}
+	}
+	t.Fatalf("cluster option %s not found", key)
+	return api.ClusterOptionsResp{}
+}
+
+// TestClusterOptions sets and resets cluster options through one peer and
+// checks them through another
+func TestClusterOptions(t *testing.T) {
+	r := require.New(t)
+
+	tc, err := setupCluster(t, "./config/1.toml", "./config/2.toml")
+	r.Nil(err)
+	defer teardownCluster(tc)
+
+	client1, err := initRestclient(tc.gds[0])
+	r.Nil(err)
+	client2, err := initRestclient(tc.gds[1])
+	r.Nil(err)
+
+	err = client1.ClusterOptionSet(api.ClusterOptionReq{
+		Options: map[string]string{"cluster.max-bricks-per-process": "100"},
+	})
+	r.Nil(err)
+
+	opts, err := client2.ClusterOptionsGet()
+	r.Nil(err)
+	opt := clusterOptionValue(t, opts, "cluster.max-bricks-per-process")
+	r.Equal("100", opt.Value)
+	r.True(opt.Modified)
+	r.NotEmpty(opt.Description)
+
+	// values are validated against the type of the option
+	err = client1.ClusterOptionSet(api.ClusterOptionReq{
+		Options: map[string]string{"cluster.brick-multiplex": "maybe"},
+	})
+	r.NotNil(err)
+	err = client1.ClusterOptionSet(api.ClusterOptionReq{
+		Options: map[string]string{"cluster.no-such-option": "on"},
+	})
+	r.NotNil(err)
+	err = client1.ClusterOptionSet(api.ClusterOptionReq{
+		Options: map[string]string{"cluster.op-version": "1"},
+	})
+	r.NotNil(err)
+
+	err = client2.ClusterOptionReset(api.ClusterOptionResetReq{
+		Options: []string{"cluster.max-bricks-per-process"},
+	})
+	r.Nil(err)
+
+	opts, err = client1.ClusterOptionsGet()
+	r.Nil(err)
+	opt = clusterOptionValue(t, opts, "cluster.max-bricks-per-process")
+	r.Equal(opt.DefaultValue, opt.Value)
+	r.False(opt.Modified)
+}
+
+// TestClusterOpVersion checks the op-version of the cluster can be raised to
+// the op-version supported by all peers, but not lowered
+func TestClusterOpVersion(t *testing.T) {
+	r := require.New(t)
+
+	tc, err := setupCluster(t, "./config/1.toml", "./config/2.toml")
+	r.Nil(err)
+	defer teardownCluster(tc)
+
+	client, err := initRestclient(tc.gds[0])
+	r.Nil(err)
+
+	resp, err := client.ClusterOpVersion()
+	r.Nil(err)
+	r.Len(resp.Peers, 2)
+	for _, p := range resp.Peers {
+		r.True(p.OpVersion >= resp.MaxOpVersion)
+	}
+	r.True(resp.OpVersion <= resp.MaxOpVersion)
+
+	resp, err = client.ClusterOpVersionSet(0)
+	r.Nil(err)
+	r.Equal(resp.MaxOpVersion, resp.OpVersion)
+
+	_, err = client.ClusterOpVersionSet(resp.OpVersion - 1)
+	r.NotNil(err)
+	_, err = client.ClusterOpVersionSet(resp.MaxOpVersion + 1)
+	r.NotNil(err)
+}
@@ -96,6 +96,46 @@ func TestAddRemovePeer(t *testing.T) {
 		r.Len(peers, 0)
 	}
 
+	// put g3 under maintenance and end it
+	peer3, err := client.PeerMaintenanceStart(peerinfo.ID.String(), "disk replacement")
+	r.Nil(err)
+	r.Equal("disk replacement", peer3.Metadata["_maintenance"])
+	_, err = client.PeerMaintenanceStart(peerinfo.ID.String(), "")
+	r.NotNil(err)
+
+	peer3, err = client.PeerMaintenanceStop(peerinfo.ID.String())
+	r.Nil(err)
+	r.NotContains(peer3.Metadata, "_maintenance")
+	_, err = client.PeerMaintenanceStop(peerinfo.ID.String())
+	r.NotNil(err)
+
+	// set the zone, rack and tags of g3
+	zone, rack := "zone1", "rack1"
+	peer3, err = client.PeerPatch(peerinfo.ID.String(), api.PeerPatchReq{
+		Zone: &zone,
+		Rack: &rack,
+		Tags: map[string]string{"ssd": "yes", "nvme": "no"},
+	})
+	r.Nil(err)
+	r.Equal(zone, peer3.Zone)
+	r.Equal(rack, peer3.Rack)
+	r.Len(peer3.Tags, 2)
+
+	peer3, err = client.PeerPatch(peerinfo.ID.String(), api.PeerPatchReq{
+		Tags: map[string]string{"nvme": ""},
+	})
+	r.Nil(err)
+	r.Equal(zone, peer3.Zone)
+	r.Equal(map[string]string{"ssd": "yes"}, peer3.Tags)
+
+	badZone := "bad zone"
+	_, err = client.PeerPatch(peerinfo.ID.String(), api.PeerPatchReq{Zone: &badZone})
+	r.NotNil(err)
+
+	// only a failed peer can be replaced
+	_, err = client.PeerReplace(g2.PeerID(), api.PeerReplaceReq{PeerID: peerinfo.ID.String()})
+	r.NotNil(err)
+
 	// remove peer: ask g1 to remove g2 as peer
 	err = client.PeerRemove(g2.PeerID())
 	r.Nil(err)
@@ -1,6 +1,7 @@
 package e2e
 
 import (
+	"net/http"
 	"os"
 	"testing"
 
@@ -246,6 +247,50 @@ func testSmartVolumeDisperseLoop(t *testing.T) {
 	r.Nil(client.VolumeDelete(smartvolname))
 }
 
+func testSnapshotLoop(t *testing.T) {
+	r := require.New(t)
+
+	for _, createReq := range []api.VolCreateReq{
+		{
+			Name:            formatVolName(t.Name() + "Disperse"),
+			Size:            40 * gutils.MiB,
+			DisperseCount:   3,
+			ProvisionerType: api.ProvisionerTypeLoop,
+		},
+		{
+			Name:            formatVolName(t.Name() + "Arbiter"),
+			Size:            20 * gutils.MiB,
+			ReplicaCount:    2,
+			ArbiterCount:    1,
+			ProvisionerType: api.ProvisionerTypeLoop,
+		},
+	} {
+		volname := createReq.Name
+		_, err := client.VolumeCreate(createReq)
+		r.Nil(err)
+		r.Nil(client.VolumeStart(volname, true))
+
+		snapname := volname + "_snap"
+		_, err = client.SnapshotCreate(api.SnapCreateReq{
+			VolName:  volname,
+			SnapName: snapname,
+		})
+		r.Nil(err)
+		r.Nil(client.SnapshotActivate(api.SnapActivateReq{}, snapname))
+
+		clonename := volname + "_clone"
+		_, err = client.SnapshotClone(snapname, api.SnapCloneReq{CloneName: clonename})
+		r.Nil(err)
+		r.Nil(client.VolumeDelete(clonename))
+
+		r.Nil(client.SnapshotDeactivate(snapname))
+		r.Nil(client.SnapshotDelete(snapname))
+
+		r.Nil(client.VolumeStop(volname))
+		r.Nil(client.VolumeDelete(volname))
+	}
+}
+
 func testSmartVolumeDistributeReplicateLoop(t *testing.T) {
 	r := require.New(t)
 
@@ -388,6 +433,99 @@ func testSmartVolumeAutoDistributeDisperseLoop(t *testing.T) {
 	r.Nil(client.VolumeDelete(smartvolname))
 }
 
+func removeDeviceLoop(t *testing.T) {
+	r := require.New(t)
+
+	peerList, err := client.Peers()
+	r.Nil(err)
+
+	peerID := peerList[0].ID.String()
+	device := testTempDir(t, "exports")
+	_, err = client.DeviceAdd(peerID, device, api.ProvisionerTypeLoop)
+	r.Nil(err)
+
+	r.Nil(client.DeviceRemove(peerID, device))
+	_, err = client.DeviceList(peerID, device)
+	r.NotNil(err)
+
+	// Removing it again fails as it is no longer registered
+	r.NotNil(client.DeviceRemove(peerID, device))
+}
+
+func availableDevices(t *testing.T) {
+	r := require.New(t)
+
+	peerList, err := client.Peers()
+	r.Nil(err)
+
+	for _, peer := range peerList {
+		devices, err := client.DevicesAvailable(peer.ID.String())
+		r.Nil(err)
+
+		registered, err := client.DeviceList(peer.ID.String(), "")
+		r.Nil(err)
+		for _, d := range devices {
+			for _, reg := range registered {
+				r.NotEqual(reg.Device, d.Device)
+			}
+		}
+	}
+}
+
+func bulkAddDeviceLoop(t *testing.T) {
+	r := require.New(t)
+
+	peerList, err := client.Peers()
+	r.Nil(err)
+
+	peerID := peerList[0].ID.String()
+	device1 := testTempDir(t, "exports")
+	device2 := testTempDir(t, "exports")
+
+	req := deviceapi.BulkAddDeviceReq{
+		{PeerID: peerID, Device: device1, ProvisionerType: api.ProvisionerTypeLoop},
+		{PeerID: peerID, Device: device2, ProvisionerType: api.ProvisionerTypeLoop},
+		{PeerID: peerID, Device: device1, ProvisionerType: api.ProvisionerTypeLoop},
+		{PeerID: "invalid", Device: device1, ProvisionerType: api.ProvisionerTypeLoop},
+	}
+	resp, err := client.DeviceBulkAdd(req)
+	r.Nil(err)
+	r.Len(resp, 4)
+
+	r.Equal(http.StatusCreated, resp[0].Status)
+	r.Equal(device1, resp[0].Info.Device)
+	r.Equal(http.StatusCreated, resp[1].Status)
+	// Adding the same device again fails
+	r.Equal(http.StatusBadRequest, resp[2].Status)
+	r.NotEmpty(resp[2].Error)
+	r.Equal(http.StatusBadRequest, resp[3].Status)
+
+	r.Nil(client.DeviceRemove(peerID, device1))
+	r.Nil(client.DeviceRemove(peerID, device2))
+}
+
+func drainDeviceLoop(t *testing.T) {
+	r := require.New(t)
+
+	peerList, err := client.Peers()
+	r.Nil(err)
+
+	peerID := peerList[0].ID.String()
+	device := testTempDir(t, "exports")
+	_, err = client.DeviceAdd(peerID, device, api.ProvisionerTypeLoop)
+	r.Nil(err)
+
+	_, err = client.DeviceDrain(peerID, device)
+	r.Nil(err)
+
+	deviceList, err := client.DeviceList(peerID, device)
+	r.Nil(err)
+	r.Len(deviceList, 1)
+	r.False(deviceList[0].Schedulable())
+
+	r.Nil(client.DeviceRemove(peerID, device))
+}
+
 func editDeviceLoop(t *testing.T) {
 	r := require.New(t)
 	peerList, err := client.Peers()
@@ -515,8 +653,13 @@ func TestSmartVolumeLoop(t *testing.T) {
 	t.Run("Smartvol Distributed-Disperse Volume Loop", testSmartVolumeDistributeDisperseLoop)
 	t.Run("Smartvol Auto Distributed-Replicate Volume Loop", testSmartVolumeAutoDistributeReplicateLoop)
 	t.Run("Smartvol Auto Distributed-Disperse Volume Loop", testSmartVolumeAutoDistributeDisperseLoop)
+	t.Run("Snapshot Loop", testSnapshotLoop)
 	t.Run("Replace Brick Loop", testReplaceBrickLoop)
 	t.Run("Edit device Loop", editDeviceLoop)
+	t.Run("Available devices", availableDevices)
+	t.Run("Bulk add device Loop", bulkAddDeviceLoop)
+	t.Run("Drain device Loop", drainDeviceLoop)
+	t.Run("Remove device Loop", removeDeviceLoop)
 
 	// // Device Cleanup
 	cleanupAllBrickMounts(t)
@@ -20,6 +20,7 @@ import (
 var (
 	snapname     = "snaptest"
 	clonename    = "clonevolume"
+	restoredname = "restoredvolume"
 	snapTestName string
 )
 
@@ -80,6 +81,7 @@ func TestSnapshot(t *testing.T) {
 	t.Run("StatusAndForceActivate", testSnapshotStatusForceActivate)
 	t.Run("Info", testSnapshotInfo)
 	t.Run("Clone", testSnapshotClone)
+	t.Run("RestoreAsNewVolume", testSnapshotRestoreAsNewVolume)
 	t.Run("Restore", testSnapshotRestore)
 	t.Run("MountRestoredVolume", tc.wrap(testRestoredVolumeMount))
 	t.Run("Validate", testSnapshotValidation)
@@ -165,6 +167,27 @@ func testSnapshotClone(t *testing.T) {
 	r.Len(volumes, 2)
 }
 
+func testSnapshotRestoreAsNewVolume(t *testing.T) {
+	r := require.New(t)
+
+	vol, err := client.SnapshotRestoreAsNewVolume(snapname, restoredname)
+	r.Nil(err, "snapshot restore to new volume failed")
+	r.Equal(restoredname, vol.Name)
+	r.Equal(api.VolStarted, vol.State)
+
+	// The parent volume is left as it is
+	parent, err := client.Volumes(snapTestName)
+	r.Nil(err)
+	r.Equal(api.VolStarted, parent[0].State)
+
+	// Restoring to an existing volume fails
+	_, err = client.SnapshotRestoreAsNewVolume(snapname, clonename)
+	r.NotNil(err)
+
+	r.Nil(client.VolumeStop(restoredname))
+	r.Nil(client.VolumeDelete(restoredname))
+}
+
 func testSnapshotList(t *testing.T) {
 	r := require.New(t)
 
@@ -53,6 +53,7 @@ func TestVolume(t *testing.T) {
 
 	// Run tests that depend on this volume
 	t.Run("Start", testVolumeStart)
+	t.Run("PeerRemoveWithBricks", tc.wrap(testPeerRemoveWithBricks))
 	t.Run("Mount", tc.wrap(testVolumeMount))
 	t.Run("Status", testVolumeStatus)
 	t.Run("Statedump", testVolumeStatedump)
@@ -83,6 +84,23 @@ func TestVolume(t *testing.T) {
 	t.Run("VolumeProfile", tc.wrap(testVolumeProfileInfo))
 }
 
+// testPeerRemoveWithBricks checks that a peer hosting bricks is not removed,
+// and that the bricks of volumes which are not auto provisioned are not
+// migrated
+func testPeerRemoveWithBricks(t *testing.T, tc *testCluster) {
+	r := require.New(t)
+
+	err := client.PeerRemove(tc.gds[1].PeerID())
+	r.NotNil(err)
+
+	_, err = client.PeerRemoveMigrate(tc.gds[1].PeerID())
+	r.NotNil(err)
+
+	peers, err := client.Peers()
+	r.Nil(err)
+	r.Len(peers, 2)
+}
+
 func testVolumeCreate(t *testing.T, tc *testCluster) {
 	r := require.New(t)
 
@@ -1098,6 +1116,48 @@ func testVolumeProfileInfo(t *testing.T, tc *testCluster) {
 	_, err = client.VolumeProfileInfo(volname, "info-clear")
 	r.NotNil(err)
 
+	r.Nil(client.VolumeProfileStart(volname))
+	r.NotNil(client.VolumeProfileStart(volname))
+
+	_, err = client.VolumeProfileInfo(volname, "info")
+	r.Nil(err)
+
+	_, err = client.VolumeProfileInfo(volname, "info-peek")
+	r.Nil(err)
+
+	_, err = client.VolumeProfileInfo(volname, "info-incremental")
+	r.Nil(err)
+
+	_, err = client.VolumeProfileInfo(volname, "info-incremental-peek")
+	r.Nil(err)
+
+	_, err = client.VolumeProfileInfo(volname, "info-cumulative")
+	r.Nil(err)
+
+	_, err = client.VolumeProfileInfo(volname, "info-clear")
+	r.Nil(err)
+
+	r.Nil(client.VolumeProfileStop(volname))
+	r.NotNil(client.VolumeProfileStop(volname))
+
+	_, err = client.VolumeProfileInfo(volname, "info")
+	r.NotNil(err)
+
+	_, err = client.VolumeProfileInfo(volname, "info-peek")
+	r.NotNil(err)
+
+	_, err = client.VolumeProfileInfo(volname, "info-incremental")
+	r.NotNil(err)
+
+	_, err = client.VolumeProfileInfo(volname, "info-incremental-peek")
+	r.NotNil(err)
+
+	_, err = client.VolumeProfileInfo(volname, "info-cumulative")
+	r.NotNil(err)
+
+	_, err = client.VolumeProfileInfo(volname, "info-clear")
+	r.NotNil(err)
+
 	r.Nil(client.VolumeStop(volname))
 
 	r.Nil(client.VolumeDelete(volname))
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strconv"
 
+	bitrotapi "github.com/gluster/glusterd2/plugins/bitrot/api"
+
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -88,13 +90,7 @@ var bitrotScrubThrottleCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		volname := args[0]
-		var option []string
-		option = append(option, "bit-rot.scrub-throttle")
-		option = append(option, args[1])
-
-		// Set Option set flag to advanced
-		flagSetAdv = true
-		err := volumeOptionJSONHandler(cmd, volname, option)
+		err := client.BitrotScrubConfig(volname, bitrotapi.ScrubConfigReq{Throttle: args[1]})
 		if err != nil {
 			if GlobalFlag.Verbose {
 				log.WithError(err).WithFields(log.Fields{
@@ -114,13 +110,7 @@ var bitrotScrubFrequencyCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		volname := args[0]
-		var option []string
-		option = append(option, "bit-rot.scrub-freq")
-		option = append(option, args[1])
-
-		// Set Option set flag to advanced
-		flagSetAdv = true
-		err := volumeOptionJSONHandler(cmd, volname, option)
+		err := client.BitrotScrubConfig(volname, bitrotapi.ScrubConfigReq{Frequency: args[1]})
 		if err != nil {
 			if GlobalFlag.Verbose {
 				log.WithError(err).WithFields(log.Fields{
@@ -140,16 +130,10 @@ var bitrotScrubCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		volname := args[0]
-		var option []string
 
 		switch scrubCmd := args[1]; scrubCmd {
 		case scrubPause, scrubResume:
-			option = append(option, "bit-rot.scrub-state")
-			option = append(option, args[1])
-
-			// Set Option set flag to advanced
-			flagSetAdv = true
-			err := volumeOptionJSONHandler(cmd, volname, option)
+			err := client.BitrotScrubConfig(volname, bitrotapi.ScrubConfigReq{State: args[1]})
 			if err != nil {
 				if GlobalFlag.Verbose {
 					log.WithError(err).WithFields(log.Fields{
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var clusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Cluster operations",
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterBackupCmd)
+}
+
+var clusterBackupCmd = &cobra.Command{
+	Use:   "backup <file>",
+	Short: "Take a backup of the cluster metadata",
+	Long:  "CLI command to save a backup of the peers, volumes and other metadata of the cluster kept in the store to a file, which can be restored by starting glusterd2 with --restore-backup",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file := args[0]
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0600)
+		if err != nil {
+			failure("Failed to create backup file", err, 1)
+		}
+
+		err = client.ClusterBackup(f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(file)
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("file", file).Error("cluster backup failed")
+			}
+			failure("Failed to take cluster backup", err, 1)
+		}
+		fmt.Printf("Cluster backup saved to %s\n", file)
+	},
+}
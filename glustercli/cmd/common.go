@@ -10,6 +10,8 @@ import (
 	"github.com/gluster/glusterd2/pkg/restclient"
 )
 
+const unixEndpointPrefix = "unix://"
+
 var (
 	client                       *restclient.Client
 	logWriter                    io.WriteCloser
@@ -19,13 +21,27 @@ var (
 `
 )
 
-func initRESTClient(hostname, user, secret, cacert string, insecure bool) {
+func initRESTClient(hostname, user, secret string, tlsOpts *restclient.TLSOptions) {
+	// Endpoints like unix:///var/run/glusterd2/glusterd2.sock are served by
+	// the local REST API of glusterd2, over a Unix socket
+	connOpt := restclient.WithTLSConfig(tlsOpts)
+	if strings.HasPrefix(hostname, unixEndpointPrefix) {
+		connOpt = restclient.WithUnixSocket(strings.TrimPrefix(hostname, unixEndpointPrefix))
+		hostname = "http://localhost"
+	}
+
 	var err error
-	client, err = restclient.New(hostname, user, secret, cacert, insecure)
+	client, err = restclient.NewClientWithOpts(
+		restclient.WithBaseURL(hostname),
+		connOpt,
+		restclient.WithUsername(user),
+		restclient.WithPassword(secret),
+		restclient.WithTimeOut(time.Duration(GlobalFlag.Timeout)*time.Second),
+		restclient.WithDebugRoundTripper(),
+	)
 	if err != nil {
 		failure("failed to setup client", err, 1)
 	}
-	client.SetTimeout(time.Duration(GlobalFlag.Timeout) * time.Second)
 }
 
 func isConnectionRefusedErr(err error) bool {
@@ -12,17 +12,29 @@ import (
 )
 
 const (
-	helpDeviceCmd     = "Gluster Devices Management"
-	helpDeviceAddCmd  = "Add device"
-	helpDeviceInfoCmd = "Get device info"
+	helpDeviceCmd       = "Gluster Devices Management"
+	helpDeviceAddCmd    = "Add device"
+	helpDeviceInfoCmd   = "Get device info"
+	helpDeviceRemoveCmd = "Remove device"
+	helpDeviceResizeCmd = "Update the size of a device after its disk has grown"
+	helpDeviceDrainCmd  = "Disable device and migrate the bricks on it to other devices"
+	helpDeviceAvailCmd  = "List the block devices of a peer which can be added"
 )
 
-var flagDeviceAddProvisioner string
+var (
+	flagDeviceAddProvisioner string
+	flagDeviceRemoveMigrate  bool
+)
 
 func init() {
 	deviceAddCmd.Flags().StringVar(&flagDeviceAddProvisioner, "provisioner", "lvm", "Provisioner Type(lvm, loop)")
 	deviceCmd.AddCommand(deviceAddCmd)
 	deviceCmd.AddCommand(deviceInfoCmd)
+	deviceRemoveCmd.Flags().BoolVar(&flagDeviceRemoveMigrate, "migrate", false, "Migrate the bricks on the device to other devices")
+	deviceCmd.AddCommand(deviceRemoveCmd)
+	deviceCmd.AddCommand(deviceResizeCmd)
+	deviceCmd.AddCommand(deviceDrainCmd)
+	deviceCmd.AddCommand(deviceAvailableCmd)
 }
 
 var deviceCmd = &cobra.Command{
@@ -93,14 +105,19 @@ func deviceListDisplay(peerID, peerName string, deviceList []api.Info) {
 	fmt.Println("Peer Name:", peerName)
 	fmt.Println("Peer ID:", peerID)
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Device", "State", "Total Size", "Free Size", "Used Size", "Used %"})
+	table.SetHeader([]string{"Device", "State", "Health", "Total Size", "Free Size", "Used Size", "Used %"})
 	for _, d := range deviceList {
 		var usedPer float64
 		if d.UsedSize > 0 {
 			usedPer = float64(d.UsedSize) / float64(d.TotalSize) * 100
 		}
 
-		table.Append([]string{d.Device, d.State, humanReadable(d.TotalSize),
+		health := "-"
+		if d.Health != nil {
+			health = d.Health.Status
+		}
+
+		table.Append([]string{d.Device, d.State, health, humanReadable(d.TotalSize),
 			humanReadable(d.AvailableSize), humanReadable(d.UsedSize), fmt.Sprintf("%.2f", usedPer)})
 	}
 	table.Render()
@@ -128,3 +145,112 @@ var deviceAddCmd = &cobra.Command{
 		fmt.Println("Device add successful")
 	},
 }
+
+var deviceRemoveCmd = &cobra.Command{
+	Use:   "remove <PeerID> <DEVICE> [--migrate]",
+	Short: helpDeviceRemoveCmd,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		peerid := args[0]
+		devname := args[1]
+
+		if !flagDeviceRemoveMigrate {
+			if err := client.DeviceRemove(peerid, devname); err != nil {
+				if GlobalFlag.Verbose {
+					log.WithError(err).WithFields(log.Fields{
+						"device": devname,
+						"peerid": peerid,
+					}).Error("device remove failed")
+				}
+				failure("Device remove failed", err, 1)
+			}
+			fmt.Println("Device remove successful")
+			return
+		}
+
+		job, err := client.DeviceRemoveMigrate(peerid, devname)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithFields(log.Fields{
+					"device": devname,
+					"peerid": peerid,
+				}).Error("device remove failed")
+			}
+			failure("Device remove failed", err, 1)
+		}
+		fmt.Printf("Migrating bricks off the device, it will be removed once done. Job ID: %s\n", job.ID)
+	},
+}
+
+var deviceResizeCmd = &cobra.Command{
+	Use:   "resize <PeerID> <DEVICE>",
+	Short: helpDeviceResizeCmd,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		peerid := args[0]
+		devname := args[1]
+
+		dev, err := client.DeviceResize(peerid, devname)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithFields(log.Fields{
+					"device": devname,
+					"peerid": peerid,
+				}).Error("device resize failed")
+			}
+			failure("Device resize failed", err, 1)
+		}
+		fmt.Printf("Device resize successful. Total size: %s, Free size: %s\n",
+			humanReadable(dev.TotalSize), humanReadable(dev.AvailableSize))
+	},
+}
+
+var deviceDrainCmd = &cobra.Command{
+	Use:   "drain <PeerID> <DEVICE>",
+	Short: helpDeviceDrainCmd,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		peerid := args[0]
+		devname := args[1]
+
+		job, err := client.DeviceDrain(peerid, devname)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithFields(log.Fields{
+					"device": devname,
+					"peerid": peerid,
+				}).Error("device drain failed")
+			}
+			failure("Device drain failed", err, 1)
+		}
+		fmt.Printf("Device disabled, migrating bricks off the device. Job ID: %s\n", job.ID)
+	},
+}
+
+var deviceAvailableCmd = &cobra.Command{
+	Use:   "available <PeerID>",
+	Short: helpDeviceAvailCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		peerid := args[0]
+
+		devices, err := client.DevicesAvailable(peerid)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("peerid", peerid).Error("failed to list available devices")
+			}
+			failure("Failed to list available devices", err, 1)
+		}
+		if len(devices) == 0 {
+			fmt.Println("No available devices")
+			return
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Device", "Size", "Model", "Serial", "Rotational"})
+		for _, d := range devices {
+			table.Append([]string{d.Device, humanReadable(d.Size), d.Model, d.Serial, fmt.Sprintf("%t", d.Rotational)})
+		}
+		table.Render()
+	},
+}
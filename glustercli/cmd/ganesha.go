@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	ganeshaapi "github.com/gluster/glusterd2/plugins/ganesha/api"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// NFS-Ganesha export flags
+	flagGaneshaPseudo     string
+	flagGaneshaAccessType string
+	flagGaneshaSquash     string
+)
+
+var ganeshaCmd = &cobra.Command{
+	Use:   "nfs-ganesha",
+	Short: "NFS-Ganesha",
+}
+
+func init() {
+	ganeshaExportCmd.Flags().StringVar(&flagGaneshaPseudo, "pseudo", "", "Path of the export in the NFSv4 pseudo filesystem (default /<volname>)")
+	ganeshaExportCmd.Flags().StringVar(&flagGaneshaAccessType, "access-type", "", "Access type of the export: RW, RO or None (default RW)")
+	ganeshaExportCmd.Flags().StringVar(&flagGaneshaSquash, "squash", "", "Squash of the export: No_Root_Squash, Root_Squash, Root_Id_Squash or All_Squash (default No_Root_Squash)")
+	ganeshaCmd.AddCommand(ganeshaEnableCmd)
+	ganeshaCmd.AddCommand(ganeshaDisableCmd)
+	ganeshaCmd.AddCommand(ganeshaStatusCmd)
+	ganeshaCmd.AddCommand(ganeshaExportCmd)
+	ganeshaCmd.AddCommand(ganeshaUnexportCmd)
+}
+
+var ganeshaEnableCmd = &cobra.Command{
+	Use:   "enable <peerid> [<peerid>]...",
+	Short: "Enable NFS-Ganesha",
+	Long:  "CLI command to start NFS-Ganesha on the given peers and export the exported volumes from them",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, err := client.NfsGaneshaEnable(ganeshaapi.EnableReq{Peers: args}); err != nil {
+			failure("Failed to enable NFS-Ganesha", err, 1)
+		}
+		fmt.Printf("NFS-Ganesha enabled on %s\n", strings.Join(args, ", "))
+	},
+}
+
+var ganeshaDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Disable NFS-Ganesha",
+	Long:  "CLI command to stop NFS-Ganesha on all the peers it is enabled on",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := client.NfsGaneshaDisable(); err != nil {
+			failure("Failed to disable NFS-Ganesha", err, 1)
+		}
+		fmt.Println("NFS-Ganesha disabled")
+	},
+}
+
+var ganeshaStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "NFS-Ganesha Status",
+	Long:  "CLI command to show the peers NFS-Ganesha runs on and the exported volumes",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		status, err := client.NfsGaneshaStatus()
+		if err != nil {
+			failure("Failed to get NFS-Ganesha status", err, 1)
+		}
+
+		if !status.Enabled {
+			fmt.Println("NFS-Ganesha is not enabled")
+		} else {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Peer ID", "Running", "Error"})
+			for _, p := range status.Peers {
+				table.Append([]string{p.PeerID.String(), yesNo(p.Running), p.Error})
+			}
+			table.Render()
+		}
+
+		if len(status.Exports) == 0 {
+			return
+		}
+		fmt.Println()
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Volume", "Export ID", "Pseudo", "Access Type", "Squash"})
+		for _, e := range status.Exports {
+			table.Append([]string{e.Volume, fmt.Sprint(e.ExportID), e.Pseudo, e.AccessType, e.Squash})
+		}
+		table.Render()
+	},
+}
+
+var ganeshaExportCmd = &cobra.Command{
+	Use:   "export <volname> [--pseudo=<path>] [--access-type=<type>] [--squash=<squash>]",
+	Short: "Export Volume over NFS-Ganesha",
+	Long:  "CLI command to export a volume from all the peers NFS-Ganesha is enabled on",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname := args[0]
+		req := ganeshaapi.ExportReq{
+			Pseudo:     flagGaneshaPseudo,
+			AccessType: flagGaneshaAccessType,
+			Squash:     flagGaneshaSquash,
+		}
+		export, err := client.NfsGaneshaExport(volname, req)
+		if err != nil {
+			failure(fmt.Sprintf("Failed to export volume %s over NFS-Ganesha\n", volname), err, 1)
+		}
+		fmt.Printf("Volume %s exported over NFS-Ganesha as %s\n", volname, export.Pseudo)
+	},
+}
+
+var ganeshaUnexportCmd = &cobra.Command{
+	Use:   "unexport <volname>",
+	Short: "Unexport Volume from NFS-Ganesha",
+	Long:  "CLI command to stop exporting a volume over NFS-Ganesha",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname := args[0]
+		if err := client.NfsGaneshaUnexport(volname); err != nil {
+			failure(fmt.Sprintf("Failed to unexport volume %s from NFS-Ganesha\n", volname), err, 1)
+		}
+		fmt.Printf("Volume %s unexported from NFS-Ganesha\n", volname)
+	},
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// glusterd1 import flags
+	flagGlusterd1Workdir  string
+	flagGlusterd1AddPeers bool
+	flagGlusterd1DryRun   bool
+)
+
+var glusterd1Cmd = &cobra.Command{
+	Use:   "glusterd1",
+	Short: "Migration from glusterd1",
+}
+
+func init() {
+	glusterd1ImportCmd.Flags().StringVar(&flagGlusterd1Workdir, "workdir", "", "Working directory of glusterd1 on the peer (default /var/lib/glusterd)")
+	glusterd1ImportCmd.Flags().BoolVar(&flagGlusterd1AddPeers, "add-peers", false, "Add the glusterd1 peers which are not peers of the cluster")
+	glusterd1ImportCmd.Flags().BoolVar(&flagGlusterd1DryRun, "dry-run", false, "Only check what would be imported")
+	glusterd1Cmd.AddCommand(glusterd1ImportCmd)
+}
+
+var glusterd1ImportCmd = &cobra.Command{
+	Use:   "import [--workdir <dir>] [--add-peers] [--dry-run]",
+	Short: "Import a glusterd1 cluster",
+	Long:  "CLI command to import the peers and volumes of a glusterd1 cluster from the working directory of glusterd1 on the peer the command is sent to",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		resp, err := client.Glusterd1Import(api.Glusterd1ImportReq{
+			Workdir:  flagGlusterd1Workdir,
+			AddPeers: flagGlusterd1AddPeers,
+			DryRun:   flagGlusterd1DryRun,
+		})
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("glusterd1 import failed")
+			}
+			failure("Failed to import glusterd1 cluster", err, 1)
+		}
+
+		fmt.Println("Peers:")
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"glusterd1 UUID", "Hostnames", "Peer ID", "State", "Error"})
+		for _, p := range resp.Peers {
+			table.Append([]string{p.UUID.String(), strings.Join(p.Hostnames, ", "), p.PeerID.String(), p.State, p.Error})
+		}
+		table.Render()
+
+		fmt.Println("Volumes:")
+		table = tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "ID", "Started", "State", "Skipped Options", "Error"})
+		for _, v := range resp.Volumes {
+			table.Append([]string{v.Name, v.ID.String(), yesNo(v.Started), v.State, strings.Join(v.SkippedOptions, ", "), v.Error})
+		}
+		table.Render()
+
+		for _, v := range resp.Volumes {
+			if v.Started && v.State == api.Glusterd1VolumeImported {
+				fmt.Println("Volumes which were started in glusterd1 have to be started again with 'glustercli volume start'")
+				break
+			}
+		}
+	},
+}
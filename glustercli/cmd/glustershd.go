@@ -36,6 +36,8 @@ func init() {
 	selfHealCmd.AddCommand(selfHealInfoCmd)
 	selfHealCmd.AddCommand(selfHealIndexCmd)
 	selfHealCmd.AddCommand(selfHealFullCmd)
+	selfHealCmd.AddCommand(selfHealEnableCmd)
+	selfHealCmd.AddCommand(selfHealDisableCmd)
 
 	selfHealSplitBrainCmd.Flags().BoolVar(&flagSplitBrainBiggerFile, "bigger-file", false, "Use bigger-file to resolve split-brain")
 	selfHealSplitBrainCmd.Flags().BoolVar(&flagSplitBrainLatestMtime, "latest-mtime", false, "Use latest-mtime to resolve split-brain")
@@ -96,6 +98,34 @@ var selfHealInfoCmd = &cobra.Command{
 	},
 }
 
+var selfHealEnableCmd = &cobra.Command{
+	Use:   "enable <volname>",
+	Short: "Enable Self Heal Daemon",
+	Long:  "CLI command to turn on the self-heal daemon of a volume",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname := args[0]
+		if err := client.SelfHealEnable(volname); err != nil {
+			failure(fmt.Sprintf("Failed to enable self-heal daemon for volume %s\n", volname), err, 1)
+		}
+		fmt.Printf("Self-heal daemon enabled for volume %s\n", volname)
+	},
+}
+
+var selfHealDisableCmd = &cobra.Command{
+	Use:   "disable <volname>",
+	Short: "Disable Self Heal Daemon",
+	Long:  "CLI command to turn off the self-heal daemon of a volume",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname := args[0]
+		if err := client.SelfHealDisable(volname); err != nil {
+			failure(fmt.Sprintf("Failed to disable self-heal daemon for volume %s\n", volname), err, 1)
+		}
+		fmt.Printf("Self-heal daemon disabled for volume %s\n", volname)
+	},
+}
+
 var selfHealIndexCmd = &cobra.Command{
 	Use:   "index <volname>",
 	Short: "Index Heal",
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/gluster/glusterd2/pkg/api"
@@ -20,17 +21,40 @@ const (
 	helpPeerRemoveCmd = "remove peer specified by <PeerID>"
 	helpPeerStatusCmd = "list status of peers"
 	helpPeerListCmd   = "list all the nodes in the pool (including localhost)"
+
+	helpPeerEditCmd    = "edit the zone, rack or tags of peer specified by <PeerID>"
+	helpPeerReplaceCmd = "replace failed peer specified by <PeerID> by a new peer"
+
+	helpPeerMaintenanceCmd      = "Peer maintenance"
+	helpPeerMaintenanceStartCmd = "put peer specified by <PeerID> under maintenance"
+	helpPeerMaintenanceStopCmd  = "end the maintenance of peer specified by <PeerID>"
 )
 
 var (
 	// Peer Remove Command Flags
-	flagPeerRemoveForce bool
+	flagPeerRemoveForce         bool
+	flagPeerRemoveMigrateBricks bool
+
+	// Peer Edit Command Flags
+	flagPeerEditZone string
+	flagPeerEditRack string
+	flagPeerEditTags []string
+
+	// Peer Replace Command Flags
+	flagPeerReplaceAddress string
+	flagPeerReplaceZone    string
+	flagPeerReplacePeerID  string
+	flagPeerReplaceForce   bool
+
+	// Peer Maintenance Start Command Flags
+	flagPeerMaintenanceReason string
 )
 
 func init() {
 	peerCmd.AddCommand(peerAddCmd)
 
 	peerRemoveCmd.Flags().BoolVarP(&flagPeerRemoveForce, "force", "f", false, "Force")
+	peerRemoveCmd.Flags().BoolVar(&flagPeerRemoveMigrateBricks, "migrate-bricks", false, "Move the bricks of the peer to other peers before removing it")
 
 	peerCmd.AddCommand(peerRemoveCmd)
 
@@ -39,6 +63,22 @@ func init() {
 	peerListCmd.Flags().StringVar(&flagCmdFilterKey, "key", "", "Filter by metadata key")
 	peerListCmd.Flags().StringVar(&flagCmdFilterValue, "value", "", "Filter by metadata value")
 	peerCmd.AddCommand(peerListCmd)
+
+	peerEditCmd.Flags().StringVar(&flagPeerEditZone, "zone", "", "Zone of the peer")
+	peerEditCmd.Flags().StringVar(&flagPeerEditRack, "rack", "", "Rack of the peer")
+	peerEditCmd.Flags().StringSliceVar(&flagPeerEditTags, "tag", nil, "Tags of the peer as <key>=<value>, an empty value removes the tag")
+	peerCmd.AddCommand(peerEditCmd)
+
+	peerReplaceCmd.Flags().StringVar(&flagPeerReplaceAddress, "address", "", "Address of the new node to add in place of the peer")
+	peerReplaceCmd.Flags().StringVar(&flagPeerReplaceZone, "zone", "", "Zone of the new node")
+	peerReplaceCmd.Flags().StringVar(&flagPeerReplacePeerID, "peer-id", "", "ID of an existing peer to use in place of the peer")
+	peerReplaceCmd.Flags().BoolVarP(&flagPeerReplaceForce, "force", "f", false, "Skip the checks of the new brick paths")
+	peerCmd.AddCommand(peerReplaceCmd)
+
+	peerMaintenanceStartCmd.Flags().StringVar(&flagPeerMaintenanceReason, "reason", "", "Reason for the maintenance")
+	peerMaintenanceCmd.AddCommand(peerMaintenanceStartCmd)
+	peerMaintenanceCmd.AddCommand(peerMaintenanceStopCmd)
+	peerCmd.AddCommand(peerMaintenanceCmd)
 }
 
 var peerCmd = &cobra.Command{
@@ -71,7 +111,7 @@ var peerAddCmd = &cobra.Command{
 }
 
 var peerRemoveCmd = &cobra.Command{
-	Use:   "remove <PeerID>",
+	Use:   "remove <PeerID> [--migrate-bricks]",
 	Short: helpPeerRemoveCmd,
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
@@ -80,6 +120,14 @@ var peerRemoveCmd = &cobra.Command{
 		if uuid.Parse(peerID) == nil {
 			err = errors.New("failed to parse peerID")
 		}
+		if err == nil && flagPeerRemoveMigrateBricks {
+			var job api.JobGetResp
+			job, err = client.PeerRemoveMigrate(peerID)
+			if err == nil {
+				fmt.Printf("Moving the bricks of the peer, it will be removed once done. Job ID: %s\n", job.ID)
+				return
+			}
+		}
 		if err == nil {
 			err = client.PeerRemove(peerID)
 		}
@@ -139,3 +187,112 @@ var peerListCmd = &cobra.Command{
 		peerStatusHandler(cmd)
 	},
 }
+
+var peerEditCmd = &cobra.Command{
+	Use:   "edit <PeerID> [--zone <ZONE>] [--rack <RACK>] [--tag <KEY>=<VALUE>]...",
+	Short: helpPeerEditCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		peerID := args[0]
+		var req api.PeerPatchReq
+		if cmd.Flags().Changed("zone") {
+			req.Zone = &flagPeerEditZone
+		}
+		if cmd.Flags().Changed("rack") {
+			req.Rack = &flagPeerEditRack
+		}
+		for _, tag := range flagPeerEditTags {
+			kv := strings.SplitN(tag, "=", 2)
+			if len(kv) != 2 {
+				failure("Invalid tag "+tag, errors.New("tags must be of the form <key>=<value>"), 1)
+			}
+			if req.Tags == nil {
+				req.Tags = make(map[string]string)
+			}
+			req.Tags[kv[0]] = kv[1]
+		}
+
+		peer, err := client.PeerPatch(peerID, req)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("peerID", peerID).Error("peer edit failed")
+			}
+			failure("Peer edit failed", err, 1)
+		}
+		fmt.Println("Peer edit successful")
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"ID", "Name", "Zone", "Rack", "Tags"})
+		var tags []string
+		for k, v := range peer.Tags {
+			tags = append(tags, k+"="+v)
+		}
+		sort.Strings(tags)
+		table.Append([]string{peer.ID.String(), peer.Name, peer.Zone, peer.Rack, strings.Join(tags, "\n")})
+		table.Render()
+	},
+}
+
+var peerMaintenanceCmd = &cobra.Command{
+	Use:   "maintenance",
+	Short: helpPeerMaintenanceCmd,
+}
+
+var peerMaintenanceStartCmd = &cobra.Command{
+	Use:   "start <PeerID>",
+	Short: helpPeerMaintenanceStartCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		peerID := args[0]
+		_, err := client.PeerMaintenanceStart(peerID, flagPeerMaintenanceReason)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("peerID", peerID).Error("peer maintenance start failed")
+			}
+			failure("Failed to put peer under maintenance", err, 1)
+		}
+		fmt.Println("Peer is under maintenance")
+	},
+}
+
+var peerMaintenanceStopCmd = &cobra.Command{
+	Use:   "stop <PeerID>",
+	Short: helpPeerMaintenanceStopCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		peerID := args[0]
+		_, err := client.PeerMaintenanceStop(peerID)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("peerID", peerID).Error("peer maintenance stop failed")
+			}
+			failure("Failed to end peer maintenance", err, 1)
+		}
+		fmt.Println("Peer maintenance ended")
+	},
+}
+
+var peerReplaceCmd = &cobra.Command{
+	Use:   "replace <PeerID> (--address <HOSTNAME> | --peer-id <PeerID>)",
+	Short: helpPeerReplaceCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		peerID := args[0]
+		req := api.PeerReplaceReq{
+			Zone:   flagPeerReplaceZone,
+			PeerID: flagPeerReplacePeerID,
+			Force:  flagPeerReplaceForce,
+		}
+		if flagPeerReplaceAddress != "" {
+			req.Addresses = []string{flagPeerReplaceAddress}
+		}
+
+		job, err := client.PeerReplace(peerID, req)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("peerID", peerID).Error("peer replace failed")
+			}
+			failure("Peer replace failed", err, 1)
+		}
+		fmt.Printf("Replacing the bricks of the peer. Job ID: %s\n", job.ID)
+	},
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	quotaapi "github.com/gluster/glusterd2/plugins/quota/api"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Quota Limit Flags
+	flagQuotaSize        string
+	flagQuotaSoftLimit   int
+	flagQuotaObjectCount uint64
+
+	// Quota List Flags
+	flagQuotaListObjects bool
+)
+
+var quotaCmd = &cobra.Command{
+	Use:   "quota",
+	Short: "Gluster Quota",
+	Args:  cobra.MinimumNArgs(2),
+}
+
+func init() {
+	quotaLimitCmd.Flags().StringVar(&flagQuotaSize, "size", "", "Usage limit of the directory (e.g. 10GiB)")
+	quotaLimitCmd.Flags().IntVar(&flagQuotaSoftLimit, "soft-limit", 0, "Soft limit as a percentage of the usage limit")
+	quotaLimitCmd.Flags().Uint64Var(&flagQuotaObjectCount, "objects", 0, "Limit of the number of files and directories")
+	quotaListCmd.Flags().BoolVar(&flagQuotaListObjects, "objects", false, "List the object count limits")
+	quotaCmd.AddCommand(quotaEnableCmd)
+	quotaCmd.AddCommand(quotaDisableCmd)
+	quotaCmd.AddCommand(quotaLimitCmd)
+	quotaCmd.AddCommand(quotaRemoveCmd)
+	quotaCmd.AddCommand(quotaListCmd)
+
+	volumeCmd.AddCommand(quotaCmd)
+}
+
+var quotaEnableCmd = &cobra.Command{
+	Use:   "enable <volname>",
+	Short: "Enable Quota",
+	Long:  "CLI command to enable quota on a volume",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname := args[0]
+		if err := client.QuotaEnable(volname); err != nil {
+			failure(fmt.Sprintf("Failed to enable quota on volume %s\n", volname), err, 1)
+		}
+		fmt.Printf("Quota enabled on volume %s\n", volname)
+	},
+}
+
+var quotaDisableCmd = &cobra.Command{
+	Use:   "disable <volname>",
+	Short: "Disable Quota",
+	Long:  "CLI command to disable quota on a volume",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname := args[0]
+		if err := client.QuotaDisable(volname); err != nil {
+			failure(fmt.Sprintf("Failed to disable quota on volume %s\n", volname), err, 1)
+		}
+		fmt.Printf("Quota disabled on volume %s\n", volname)
+	},
+}
+
+var quotaLimitCmd = &cobra.Command{
+	Use:   "limit <volname> <path> [--size=<size>] [--soft-limit=<percent>] [--objects=<count>]",
+	Short: "Set Quota Limit",
+	Long:  "CLI command to set the usage limit of a directory of a volume",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname, dir := args[0], args[1]
+		req := quotaapi.SetLimitReq{
+			Path:             dir,
+			SoftLimitPercent: flagQuotaSoftLimit,
+			ObjectCountLimit: flagQuotaObjectCount,
+		}
+		if flagQuotaSize != "" {
+			size, err := sizeToBytes(flagQuotaSize)
+			if err != nil {
+				failure("Invalid size", err, 1)
+			}
+			req.SizeUsageLimit = size
+		}
+
+		if _, err := client.QuotaSetLimit(volname, req); err != nil {
+			failure(fmt.Sprintf("Failed to set quota limit on %s of volume %s\n", dir, volname), err, 1)
+		}
+		fmt.Printf("Quota limit set on %s of volume %s\n", dir, volname)
+	},
+}
+
+var quotaRemoveCmd = &cobra.Command{
+	Use:   "remove <volname> <path>",
+	Short: "Remove Quota Limit",
+	Long:  "CLI command to remove the usage limit of a directory of a volume",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname, dir := args[0], args[1]
+		if err := client.QuotaRemoveLimit(volname, dir); err != nil {
+			failure(fmt.Sprintf("Failed to remove quota limit on %s of volume %s\n", dir, volname), err, 1)
+		}
+		fmt.Printf("Quota limit removed on %s of volume %s\n", dir, volname)
+	},
+}
+
+var quotaListCmd = &cobra.Command{
+	Use:   "list <volname> [--objects]",
+	Short: "List Quota Limits",
+	Long:  "CLI command to show the usage of the directories of a volume against their limits",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname := args[0]
+		if flagQuotaListObjects {
+			quotaObjectList(volname)
+			return
+		}
+
+		limits, err := client.QuotaLimits(volname)
+		if err != nil {
+			failure(fmt.Sprintf("Failed to get quota limits of volume %s\n", volname), err, 1)
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Path", "Hard Limit", "Soft Limit", "Used", "Available", "Soft Limit Exceeded", "Hard Limit Exceeded"})
+		for _, l := range limits {
+			table.Append([]string{l.Path, humanReadable(l.HardLimit), humanReadable(l.SoftLimit),
+				humanReadable(l.Used), humanReadable(l.Available), yesNo(l.SoftLimitExceeded),
+				yesNo(l.HardLimitExceeded)})
+		}
+		table.Render()
+	},
+}
+
+func quotaObjectList(volname string) {
+	limits, err := client.QuotaObjectLimits(volname)
+	if err != nil {
+		failure(fmt.Sprintf("Failed to get quota object limits of volume %s\n", volname), err, 1)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Path", "Hard Limit", "Soft Limit", "Files", "Dirs", "Available", "Soft Limit Exceeded", "Hard Limit Exceeded"})
+	for _, l := range limits {
+		table.Append([]string{l.Path, fmt.Sprint(l.HardLimit), fmt.Sprint(l.SoftLimit),
+			fmt.Sprint(l.Files), fmt.Sprint(l.Dirs), fmt.Sprint(l.Available),
+			yesNo(l.SoftLimitExceeded), yesNo(l.HardLimitExceeded)})
+	}
+	table.Render()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
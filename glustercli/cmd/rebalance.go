@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	rebalanceapi "github.com/gluster/glusterd2/plugins/rebalance/api"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Rebalance Start Flags
+	flagRebalanceFixLayout bool
+	flagRebalanceForce     bool
+	flagRebalanceThrottle  string
+)
+
+var rebalanceCmd = &cobra.Command{
+	Use:   "rebalance",
+	Short: "Gluster Rebalance",
+	Args:  cobra.MinimumNArgs(2),
+}
+
+func init() {
+	rebalanceStartCmd.Flags().BoolVar(&flagRebalanceFixLayout, "fix-layout", false, "Only fix the layout of the directories")
+	rebalanceStartCmd.Flags().BoolVar(&flagRebalanceForce, "force", false, "Force start rebalance")
+	rebalanceStartCmd.Flags().StringVar(&flagRebalanceThrottle, "throttle", "", "Throttle level <lazy|normal|aggressive>")
+	rebalanceCmd.AddCommand(rebalanceStartCmd)
+	rebalanceCmd.AddCommand(rebalanceStopCmd)
+	rebalanceCmd.AddCommand(rebalanceStatusCmd)
+	rebalanceCmd.AddCommand(rebalanceThrottleCmd)
+
+	volumeCmd.AddCommand(rebalanceCmd)
+}
+
+var rebalanceStartCmd = &cobra.Command{
+	Use:   "start <volname> [--fix-layout|--force] [--throttle=<level>]",
+	Short: "Start Rebalance",
+	Long:  "CLI command to start rebalance on a volume",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname := args[0]
+		req := rebalanceapi.StartReq{
+			Throttle: flagRebalanceThrottle,
+		}
+		if flagRebalanceFixLayout {
+			req.Option = rebalanceapi.OptionFixLayout
+		} else if flagRebalanceForce {
+			req.Option = rebalanceapi.OptionForce
+		}
+
+		id, err := client.RebalanceStart(volname, req)
+		if err != nil {
+			failure(fmt.Sprintf("Failed to start rebalance on volume %s\n", volname), err, 1)
+		}
+		fmt.Printf("Rebalance started on volume %s with ID: %s\n", volname, id)
+	},
+}
+
+var rebalanceStopCmd = &cobra.Command{
+	Use:   "stop <volname>",
+	Short: "Stop Rebalance",
+	Long:  "CLI command to stop the rebalance running on a volume",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname := args[0]
+		if _, err := client.RebalanceStop(volname); err != nil {
+			failure(fmt.Sprintf("Failed to stop rebalance on volume %s\n", volname), err, 1)
+		}
+		fmt.Printf("Rebalance stopped on volume %s\n", volname)
+	},
+}
+
+var rebalanceThrottleCmd = &cobra.Command{
+	Use:   "throttle <volname> <lazy|normal|aggressive>",
+	Short: "Change Rebalance Throttle",
+	Long:  "CLI command to change the throttle level of the rebalance running on a volume",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname, throttle := args[0], args[1]
+		if err := client.RebalanceThrottle(volname, throttle); err != nil {
+			failure(fmt.Sprintf("Failed to change rebalance throttle of volume %s\n", volname), err, 1)
+		}
+		fmt.Printf("Rebalance throttle of volume %s set to %s\n", volname, throttle)
+	},
+}
+
+var rebalanceStatusCmd = &cobra.Command{
+	Use:   "status <volname>",
+	Short: "Rebalance Status",
+	Long:  "CLI command to show the status of the rebalance of a volume",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname := args[0]
+		status, err := client.RebalanceStatus(volname)
+		if err != nil {
+			failure(fmt.Sprintf("Failed to get rebalance status of volume %s\n", volname), err, 1)
+		}
+
+		fmt.Println()
+		fmt.Println("Volume:", status.Volname)
+		fmt.Println("Rebalance ID:", status.RebalanceID)
+		fmt.Println("State:", status.State)
+		fmt.Println("Mode:", status.Mode)
+		fmt.Println("Throttle:", status.Throttle)
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Peer ID", "Status", "Rebalanced Files", "Size", "Lookedup", "Skipped", "Failed", "Run Time", "Time Left"})
+		for _, n := range status.Nodes {
+			table.Append([]string{n.PeerID.String(), n.Status, n.RebalancedFiles, n.RebalancedSize,
+				n.LookedupFiles, n.SkippedFiles, n.RebalanceFailures, n.ElapsedTime, n.TimeLeft})
+		}
+		t := status.Totals
+		table.SetFooter([]string{"Total", "", fmt.Sprint(t.RebalancedFiles), humanReadable(t.RebalancedSize),
+			fmt.Sprint(t.LookedupFiles), fmt.Sprint(t.SkippedFiles), fmt.Sprint(t.RebalanceFailures), "", fmt.Sprint(t.TimeLeft)})
+		table.Render()
+	},
+}
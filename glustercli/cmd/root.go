@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/gluster/glusterd2/pkg/logging"
+	"github.com/gluster/glusterd2/pkg/restclient"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -55,6 +56,11 @@ func addSubCommands(rootCmd *cobra.Command) {
 	rootCmd.AddCommand(snapshotCmd)
 	rootCmd.AddCommand(volumeCmd)
 	rootCmd.AddCommand(traceCmd)
+	rootCmd.AddCommand(ganeshaCmd)
+	rootCmd.AddCommand(smbCmd)
+	rootCmd.AddCommand(glusterd1Cmd)
+	rootCmd.AddCommand(clusterCmd)
+	rootCmd.AddCommand(storeCmd)
 }
 
 // GlustercliOption will have all global flags set during run time
@@ -66,6 +72,8 @@ type GlustercliOption struct {
 	Insecure   bool
 	Verbose    bool
 	Cacert     string
+	Cert       string
+	Key        string
 	LogLevel   string
 	User       string
 	Secret     string
@@ -95,6 +103,8 @@ func (gOpt *GlustercliOption) AddPersistentFlag(flagSet *pflag.FlagSet) {
 
 	// SSL/TLS options
 	flagSet.StringVarP(&gOpt.Cacert, "cacert", "", "", "Path to CA certificate")
+	flagSet.StringVar(&gOpt.Cert, "cert", "", "Path to client certificate, if glusterd2 verifies client certificates")
+	flagSet.StringVar(&gOpt.Key, "key", "", "Path to private key of the client certificate")
 	flagSet.BoolVarP(&gOpt.Insecure, "insecure", "", false,
 		"Skip server certificate validation")
 }
@@ -112,7 +122,12 @@ func (gOpt *GlustercliOption) Init() {
 	gOpt.SetEndpoints()
 
 	//Initializing Rest Client
-	initRESTClient(gOpt.Endpoints[0], gOpt.User, gOpt.Secret, gOpt.Cacert, gOpt.Insecure)
+	initRESTClient(gOpt.Endpoints[0], gOpt.User, gOpt.Secret, &restclient.TLSOptions{
+		CaCertFile:         gOpt.Cacert,
+		CertFile:           gOpt.Cert,
+		KeyFile:            gOpt.Key,
+		InsecureSkipVerify: gOpt.Insecure,
+	})
 
 }
 
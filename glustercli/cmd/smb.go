@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	smbapi "github.com/gluster/glusterd2/plugins/smb/api"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var smbCmd = &cobra.Command{
+	Use:   "smb",
+	Short: "SMB",
+}
+
+func init() {
+	smbCmd.AddCommand(smbEnableCmd)
+	smbCmd.AddCommand(smbDisableCmd)
+	smbCmd.AddCommand(smbStatusCmd)
+	smbCmd.AddCommand(smbShareCmd)
+}
+
+var smbEnableCmd = &cobra.Command{
+	Use:   "enable <peerid> [<peerid>]...",
+	Short: "Enable SMB",
+	Long:  "CLI command to share the volumes with user.smb enabled over Samba running on the given peers",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, err := client.SmbEnable(smbapi.EnableReq{Peers: args}); err != nil {
+			failure("Failed to enable SMB", err, 1)
+		}
+		fmt.Printf("SMB enabled on %s\n", strings.Join(args, ", "))
+	},
+}
+
+var smbDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Disable SMB",
+	Long:  "CLI command to remove the shares of the volumes from all the peers SMB is enabled on",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := client.SmbDisable(); err != nil {
+			failure("Failed to disable SMB", err, 1)
+		}
+		fmt.Println("SMB disabled")
+	},
+}
+
+func printSmbShares(shares []smbapi.Share) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Volume", "Share", "Peer ID", "Configured", "Samba Running", "Error"})
+	for _, s := range shares {
+		for _, p := range s.Peers {
+			table.Append([]string{s.Volume, s.Name, p.PeerID.String(), yesNo(p.Configured), yesNo(p.Running), p.Error})
+		}
+	}
+	table.Render()
+}
+
+var smbStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "SMB Status",
+	Long:  "CLI command to show the peers SMB is enabled on and the shares of the volumes",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		status, err := client.SmbStatus()
+		if err != nil {
+			failure("Failed to get SMB status", err, 1)
+		}
+
+		if !status.Enabled {
+			fmt.Println("SMB is not enabled")
+			return
+		}
+
+		var peers []string
+		for _, id := range status.Peers {
+			peers = append(peers, id.String())
+		}
+		fmt.Printf("SMB is enabled on %s\n", strings.Join(peers, ", "))
+
+		if len(status.Shares) == 0 {
+			fmt.Println("No volumes are shared")
+			return
+		}
+		fmt.Println()
+		printSmbShares(status.Shares)
+	},
+}
+
+var smbShareCmd = &cobra.Command{
+	Use:   "share <volname>",
+	Short: "Show SMB Share of Volume",
+	Long:  "CLI command to show the state of the SMB share of a volume on the peers SMB is enabled on",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname := args[0]
+		share, err := client.SmbShareInfo(volname)
+		if err != nil {
+			failure(fmt.Sprintf("Failed to get SMB share of volume %s\n", volname), err, 1)
+		}
+		printSmbShares([]smbapi.Share{share})
+	},
+}
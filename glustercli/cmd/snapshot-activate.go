@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/gluster/glusterd2/pkg/api"
 
@@ -11,11 +12,12 @@ import (
 
 const (
 	snapshotActivateHelpShort = "Activate a Gluster Snapshot"
-	snapshotActivateHelpLong  = "Activate a Gluster snapshot. Force flag can be used to activate a snapshot forcefully. It will override some checks."
+	snapshotActivateHelpLong  = "Activate a Gluster snapshot. Force flag can be used to activate a snapshot forcefully. It will override some checks. With a TTL the snapshot is deactivated once it expires."
 )
 
 var (
 	flagSnapshotActivateCmdForce bool
+	flagSnapshotActivateCmdTTL   time.Duration
 
 	snapshotActivateCmd = &cobra.Command{
 		Use:   "activate <snapname>",
@@ -28,6 +30,7 @@ var (
 
 func init() {
 	snapshotActivateCmd.Flags().BoolVarP(&flagSnapshotActivateCmdForce, "force", "f", false, "Force")
+	snapshotActivateCmd.Flags().DurationVar(&flagSnapshotActivateCmdTTL, "ttl", 0, "Time after which the snapshot is deactivated")
 	snapshotCmd.AddCommand(snapshotActivateCmd)
 }
 
@@ -35,6 +38,7 @@ func snapshotActivateCmdRun(cmd *cobra.Command, args []string) {
 	snapname := cmd.Flags().Args()[0]
 	req := api.SnapActivateReq{
 		Force: flagSnapshotActivateCmdForce,
+		TTL:   int(flagSnapshotActivateCmdTTL.Seconds()),
 	}
 	if err := client.SnapshotActivate(req, snapname); err != nil {
 		if GlobalFlag.Verbose {
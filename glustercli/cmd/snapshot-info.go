@@ -46,6 +46,9 @@ func snapshotInfoDisplay(snap api.SnapGetResp) {
 	fmt.Println("Snapshot Name:", vol.Name)
 	fmt.Println("Snapshot Volume ID:", vol.ID)
 	fmt.Println("State:", vol.State)
+	if !snap.ExpiresAt.IsZero() {
+		fmt.Println("Activation Expiry Time:", snap.ExpiresAt.Format("Mon Jan _2 2006 15:04:05 GMT"))
+	}
 	fmt.Println("Origin Volume name:", snap.ParentVolName)
 	fmt.Println("Snap Creation Time:", snap.CreatedAt.Format("Mon Jan _2 2006 15:04:05 GMT"))
 	if vol.Capacity != 0 {
@@ -3,15 +3,19 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/gluster/glusterd2/pkg/api"
+
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 const (
 	snapshotRestoreHelpShort = "Restore a Gluster Snapshot to it's parent volume"
-	snapshotRestoreHelpLong  = "Parent volume will be restored from the snap, which include data as well as the configuration"
+	snapshotRestoreHelpLong  = "Parent volume will be restored from the snap, which include data as well as the configuration. With --as-new-volume, a new volume is created from the snap and started instead, leaving the parent volume untouched."
 )
 
+var flagSnapshotRestoreAsNewVolume string
+
 var (
 	snapshotRestoreCmd = &cobra.Command{
 		Use:   "restore <snapname>",
@@ -23,12 +27,21 @@ var (
 )
 
 func init() {
+	snapshotRestoreCmd.Flags().StringVar(&flagSnapshotRestoreAsNewVolume, "as-new-volume", "", "Restore the snapshot to a new volume with the given name")
 	snapshotCmd.AddCommand(snapshotRestoreCmd)
 }
 
 func snapshotRestoreCmdRun(cmd *cobra.Command, args []string) {
 	snapname := cmd.Flags().Args()[0]
-	vol, err := client.SnapshotRestore(snapname)
+	var (
+		vol api.VolumeGetResp
+		err error
+	)
+	if flagSnapshotRestoreAsNewVolume != "" {
+		vol, err = client.SnapshotRestoreAsNewVolume(snapname, flagSnapshotRestoreAsNewVolume)
+	} else {
+		vol, err = client.SnapshotRestore(snapname)
+	}
 	if err != nil {
 		if GlobalFlag.Verbose {
 			log.WithError(err).WithFields(log.Fields{
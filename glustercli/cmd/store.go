@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// store compact flags
+	flagStoreCompactRevision int64
+)
+
+var storeCmd = &cobra.Command{
+	Use:   "store",
+	Short: "Maintenance of the store",
+}
+
+var storeAlarmCmd = &cobra.Command{
+	Use:   "alarm",
+	Short: "Alarms of the embedded store",
+}
+
+func init() {
+	storeCompactCmd.Flags().Int64Var(&flagStoreCompactRevision, "revision", 0, "Revision to compact the history up to (default current revision)")
+
+	storeAlarmCmd.AddCommand(storeAlarmListCmd)
+	storeAlarmCmd.AddCommand(storeAlarmClearCmd)
+
+	storeCmd.AddCommand(storeMembersCmd)
+	storeCmd.AddCommand(storeDefragCmd)
+	storeCmd.AddCommand(storeCompactCmd)
+	storeCmd.AddCommand(storeAlarmCmd)
+	storeCmd.AddCommand(storeMoveLeaderCmd)
+	storeCmd.AddCommand(storeReloadCmd)
+}
+
+var storeMembersCmd = &cobra.Command{
+	Use:   "members",
+	Short: "Show the members of the embedded store",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		members, err := client.StoreMembers()
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("get store members failed")
+			}
+			failure("Failed to get store members", err, 1)
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"ID", "Name", "Client URLs", "Healthy", "Leader", "Version", "DB Size", "Raft Index", "Error"})
+		for _, m := range members {
+			table.Append([]string{m.ID, m.Name, strings.Join(m.ClientURLs, ", "), yesNo(m.Healthy), yesNo(m.Leader),
+				m.Version, humanReadable(uint64(m.DBSize)), strconv.FormatUint(m.RaftIndex, 10), m.Error})
+		}
+		table.Render()
+	},
+}
+
+var storeDefragCmd = &cobra.Command{
+	Use:   "defrag [<member>...]",
+	Short: "Defragment the database of members of the embedded store",
+	Long:  "CLI command to defragment the database of the given members of the embedded store, by name or ID, or of all the members. Members are defragmented one at a time and don't serve requests while they are defragmented.",
+	Run: func(cmd *cobra.Command, args []string) {
+		results, err := client.StoreDefrag(args)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("defragment store failed")
+			}
+			failure("Failed to defragment store", err, 1)
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "DB Size Before", "DB Size After", "Error"})
+		for _, r := range results {
+			table.Append([]string{r.Name, humanReadable(uint64(r.DBSizeBefore)), humanReadable(uint64(r.DBSizeAfter)), r.Error})
+		}
+		table.Render()
+	},
+}
+
+var storeCompactCmd = &cobra.Command{
+	Use:   "compact [--revision <revision>]",
+	Short: "Compact the history of the embedded store",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		resp, err := client.StoreCompact(flagStoreCompactRevision)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("compact store failed")
+			}
+			failure("Failed to compact store", err, 1)
+		}
+		fmt.Printf("Store compacted up to revision %d\n", resp.Revision)
+	},
+}
+
+var storeAlarmListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the alarms of the embedded store",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		alarms, err := client.StoreAlarmList()
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("list store alarms failed")
+			}
+			failure("Failed to list store alarms", err, 1)
+		}
+		if len(alarms) == 0 {
+			fmt.Println("No alarms")
+			return
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Member ID", "Name", "Alarm"})
+		for _, a := range alarms {
+			table.Append([]string{a.MemberID, a.Name, a.Alarm})
+		}
+		table.Render()
+	},
+}
+
+var storeAlarmClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Clear the alarms of the embedded store",
+	Long:  "CLI command to clear all the alarms of the embedded store. A NOSPACE alarm has to be cleared once space has been released, by compacting and defragmenting the store.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		alarms, err := client.StoreAlarmClear()
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("clear store alarms failed")
+			}
+			failure("Failed to clear store alarms", err, 1)
+		}
+		for _, a := range alarms {
+			fmt.Printf("Cleared %s alarm of %s\n", a.Alarm, a.Name)
+		}
+		fmt.Println("Store alarms cleared")
+	},
+}
+
+var storeMoveLeaderCmd = &cobra.Command{
+	Use:   "move-leader <member>",
+	Short: "Transfer the leadership of the embedded store to a member",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		resp, err := client.StoreMoveLeader(args[0])
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("move store leader failed")
+			}
+			failure("Failed to move store leader", err, 1)
+		}
+		fmt.Printf("Store leader is %s\n", resp.Leader)
+	},
+}
+
+var storeReloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Reload the configuration of the external etcd cluster",
+	Long:  "CLI command to apply the changes of the etcd endpoints and of the etcd client certificate, key and CA files made to the config files of all the peers, without restarting glusterd2",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		resp, err := client.StoreReload()
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).Error("store reload failed")
+			}
+			failure("Failed to reload store configuration", err, 1)
+		}
+		fmt.Printf("Store configuration reloaded, endpoints: %s\n", strings.Join(resp.Endpoints, ", "))
+	},
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	helpVolumeClientsCmd = "List the clients connected to a Gluster Volume"
+)
+
+var (
+	// Volume Clients Flags
+	flagClientsCmdAll bool
+)
+
+func init() {
+	volumeClientsCmd.Flags().BoolVar(&flagClientsCmdAll, "all", false, "Also list gluster daemons like self-heal and rebalance")
+	volumeCmd.AddCommand(volumeClientsCmd)
+}
+
+var volumeClientsCmd = &cobra.Command{
+	Use:   "clients <volname> [--all]",
+	Short: helpVolumeClientsCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname := args[0]
+		resp, err := client.VolumeClients(volname)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("volume", volname).Error("error getting volume clients")
+			}
+			failure(fmt.Sprintf("Failed to get clients of volume %s\n", volname), err, 1)
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Hostname", "PID", "Protocol", "Op-Version", "Bytes Read", "Bytes Written", "Bricks"})
+		for _, c := range resp.Clients {
+			if c.Internal && !flagClientsCmdAll {
+				continue
+			}
+			pid := "-"
+			if c.PID != 0 {
+				pid = strconv.Itoa(c.PID)
+			}
+			table.Append([]string{c.Hostname, pid, c.MountProtocol, fmt.Sprint(c.OpVersion),
+				humanReadable(c.BytesRead), humanReadable(c.BytesWritten), strings.Join(c.Bricks, "\n")})
+		}
+		table.Render()
+	},
+}
@@ -43,6 +43,10 @@ var (
 	flagAverageFileSize             string
 	flagCreateMaxBrickSize          string
 	flagProvisionerType             string
+	flagCreateValidateOnly          bool
+	flagCreateProfile               string
+	flagCreateStart                 bool
+	flagCreateNamespace             string
 
 	volumeCreateCmd = &cobra.Command{
 		Use:   "create <volname> [<brick> [<brick>]...|--size <size>]",
@@ -64,6 +68,10 @@ func init() {
 	volumeCreateCmd.Flags().IntVar(&flagCreateDisperseRedundancyCount, "redundancy", 0, "Redundancy Count")
 	volumeCreateCmd.Flags().StringVar(&flagCreateTransport, "transport", "tcp", "Transport")
 	volumeCreateCmd.Flags().BoolVar(&flagCreateForce, "force", false, "Force")
+	volumeCreateCmd.Flags().StringVar(&flagCreateProfile, "profile", "", "Name of the volume profile to apply")
+	volumeCreateCmd.Flags().BoolVar(&flagCreateValidateOnly, "validate", false, "Only validate the request and show the resolved brick layout")
+	volumeCreateCmd.Flags().BoolVar(&flagCreateStart, "start", false, "Start the volume after it is created")
+	volumeCreateCmd.Flags().StringVar(&flagCreateNamespace, "namespace", "", "Namespace of the volume")
 	volumeCreateCmd.Flags().StringSliceVar(&flagCreateVolumeOptions, "options", nil,
 		"Volume options in the format option:value,option:value")
 
@@ -148,18 +156,12 @@ func smartVolumeCreate(cmd *cobra.Command, args []string) {
 		SubvolZonesOverlap:      flagCreateSubvolZoneOverlap,
 		Force:                   flagCreateForce,
 		ProvisionerType:         flagProvisionerType,
+		Profile:                 flagCreateProfile,
+		StartVolume:             flagCreateStart,
+		Namespace:               flagCreateNamespace,
 	}
 
-	vol, err := client.VolumeCreate(req)
-	if err != nil {
-		if GlobalFlag.Verbose {
-			log.WithError(err).WithField(
-				"volume", args[0]).Error("volume creation failed")
-		}
-		failure("Volume creation failed", err, 1)
-	}
-	fmt.Printf("%s Volume created successfully\n", vol.Name)
-	fmt.Println("Volume ID: ", vol.ID)
+	submitVolumeCreate(req)
 }
 
 func volumeCreateCmdRun(cmd *cobra.Command, args []string) {
@@ -295,9 +297,12 @@ func volumeCreateCmdRun(cmd *cobra.Command, args []string) {
 	}
 
 	req := api.VolCreateReq{
-		Name:    volname,
-		Subvols: subvols,
-		Force:   flagCreateForce,
+		Name:        volname,
+		Namespace:   flagCreateNamespace,
+		Subvols:     subvols,
+		Force:       flagCreateForce,
+		Profile:     flagCreateProfile,
+		StartVolume: flagCreateStart,
 		VolOptionReq: api.VolOptionReq{
 			Options: options,
 			VolOptionFlags: api.VolOptionFlags{
@@ -322,13 +327,38 @@ func volumeCreateCmdRun(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	submitVolumeCreate(req)
+}
+
+func submitVolumeCreate(req api.VolCreateReq) {
+	if flagCreateValidateOnly {
+		resolved, err := client.VolumeCreateValidate(req)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("volume", req.Name).Error("volume create validation failed")
+			}
+			failure("Volume create validation failed", err, 1)
+		}
+		fmt.Printf("Volume create request for %s is valid\n", resolved.Name)
+		for idx, subvol := range resolved.Subvols {
+			fmt.Printf("Subvolume %d (%s):\n", idx+1, subvol.Type)
+			for _, b := range subvol.Bricks {
+				fmt.Printf("  %s:%s\n", b.PeerID, b.Path)
+			}
+		}
+		return
+	}
+
 	vol, err := client.VolumeCreate(req)
 	if err != nil {
 		if GlobalFlag.Verbose {
-			log.WithError(err).WithField("volume", volname).Error("volume creation failed")
+			log.WithError(err).WithField("volume", req.Name).Error("volume creation failed")
 		}
 		failure("Volume creation failed", err, 1)
 	}
 	fmt.Printf("%s Volume created successfully\n", vol.Name)
+	if req.StartVolume {
+		fmt.Printf("%s Volume started successfully\n", vol.Name)
+	}
 	fmt.Println("Volume ID: ", vol.ID)
 }
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	helpVolumeExportCmd = "Export the configuration of a Gluster Volume"
+	helpVolumeImportCmd = "Recreate a Gluster Volume from an exported configuration"
+)
+
+func init() {
+	volumeCmd.AddCommand(volumeExportCmd)
+	volumeCmd.AddCommand(volumeImportCmd)
+}
+
+var volumeExportCmd = &cobra.Command{
+	Use:   "export <volname> [<file>]",
+	Short: helpVolumeExportCmd,
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname := args[0]
+		export, err := client.VolumeExport(volname)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("volume", volname).Error("volume export failed")
+			}
+			failure("Volume export failed", err, 1)
+		}
+
+		data, err := json.MarshalIndent(export, "", "  ")
+		if err != nil {
+			failure("Failed to encode volume export", err, 1)
+		}
+
+		if len(args) < 2 {
+			fmt.Println(string(data))
+			return
+		}
+		if err := ioutil.WriteFile(args[1], data, 0600); err != nil {
+			failure("Failed to write volume export", err, 1)
+		}
+		fmt.Printf("Volume %s exported to %s\n", volname, args[1])
+	},
+}
+
+var volumeImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: helpVolumeImportCmd,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := ioutil.ReadFile(args[0])
+		if err != nil {
+			failure("Failed to read volume export", err, 1)
+		}
+
+		var req api.VolumeImportReq
+		if err := json.Unmarshal(data, &req); err != nil {
+			failure("Failed to parse volume export", err, 1)
+		}
+
+		vol, err := client.VolumeImport(req)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("volume", req.Name).Error("volume import failed")
+			}
+			failure("Volume import failed", err, 1)
+		}
+		fmt.Printf("Volume %s imported successfully\n", vol.Name)
+		fmt.Println("Volume ID: ", vol.ID)
+	},
+}
@@ -35,11 +35,43 @@ func init() {
 	volumeProfileInfoCmd.Flags().BoolVar(&flagProfileInfoCumulative, "cumulative", false, "Volume Profile Info Cumulative")
 	volumeProfileInfoCmd.Flags().BoolVar(&flagProfileInfoClear, "clear", false, "Volume Profile Info Clear")
 
+	volumeProfileCmd.AddCommand(volumeProfileStartCmd)
+	volumeProfileCmd.AddCommand(volumeProfileStopCmd)
 	volumeProfileCmd.AddCommand(volumeProfileInfoCmd)
 
 	volumeCmd.AddCommand(volumeProfileCmd)
 }
 
+var volumeProfileStartCmd = &cobra.Command{
+	Use:   "start <volname>",
+	Short: "Start Volume Profile",
+	Long:  "Start collecting stats like latency and no of fops performed on the bricks of the volume",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname := args[0]
+		if err := client.VolumeProfileStart(volname); err != nil {
+			log.WithError(err).WithField("volname", volname).Error("failed to start volume profile")
+			failure(fmt.Sprintf("Failed to start volume profile for volume %s\n", volname), err, 1)
+		}
+		fmt.Printf("Volume profile started for volume %s\n", volname)
+	},
+}
+
+var volumeProfileStopCmd = &cobra.Command{
+	Use:   "stop <volname>",
+	Short: "Stop Volume Profile",
+	Long:  "Stop collecting stats on the bricks of the volume",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname := args[0]
+		if err := client.VolumeProfileStop(volname); err != nil {
+			log.WithError(err).WithField("volname", volname).Error("failed to stop volume profile")
+			failure(fmt.Sprintf("Failed to stop volume profile for volume %s\n", volname), err, 1)
+		}
+		fmt.Printf("Volume profile stopped for volume %s\n", volname)
+	},
+}
+
 var volumeProfileInfoCmd = &cobra.Command{
 	Use:   "info <volname> [--peek|--incremental|--incremental-peek|--cumulative|--clear]",
 	Short: "Volume Profile Info",
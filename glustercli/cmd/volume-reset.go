@@ -12,11 +12,12 @@ import (
 
 const (
 	volumeResetCmdHelpShort = "Reset volume options"
-	volumeResetCmdHelpLong  = "Reset options on a specified gluster volume. Needs a volume name and at least one option"
+	volumeResetCmdHelpLong  = "Reset options on a specified gluster volume. Needs a volume name and at least one option, an option group or the --all flag"
 )
 
 var (
 	flagForce, flagResetAll bool
+	flagResetGroup          string
 )
 
 var volumeResetCmd = &cobra.Command{
@@ -30,8 +31,9 @@ var volumeResetCmd = &cobra.Command{
 		req := api.VolOptionResetReq{
 			Force: flagForce,
 			All:   flagResetAll,
+			Group: flagResetGroup,
 		}
-		if flagResetAll {
+		if flagResetAll || flagResetGroup != "" {
 			req.Options = []string{}
 		} else {
 			if len(args) < 2 {
@@ -40,7 +42,15 @@ var volumeResetCmd = &cobra.Command{
 				req.Options = options
 			}
 		}
-		err := client.VolumeReset(volname, req)
+		var err error
+		if volname == "all" {
+			err = client.ClusterOptionReset(api.ClusterOptionResetReq{
+				Options: req.Options,
+				All:     req.All,
+			})
+		} else {
+			err = client.VolumeReset(volname, req)
+		}
 		if err != nil {
 			if GlobalFlag.Verbose {
 				log.WithError(err).WithField("volume", volname).Error("volume reset failed")
@@ -54,5 +64,6 @@ var volumeResetCmd = &cobra.Command{
 func init() {
 	volumeResetCmd.Flags().BoolVar(&flagForce, "force", false, "Force reset the volume option")
 	volumeResetCmd.Flags().BoolVar(&flagResetAll, "all", false, "Reset all the volume options")
+	volumeResetCmd.Flags().StringVar(&flagResetGroup, "group", "", "Reset the options of an option group")
 	volumeCmd.AddCommand(volumeResetCmd)
 }
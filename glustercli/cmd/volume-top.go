@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	helpVolumeTopCmd = "Show the files of the bricks of a Gluster Volume with the highest counts for a metric"
+)
+
+var (
+	// Volume Top Flags
+	flagTopCmdCount int
+)
+
+func init() {
+	volumeTopCmd.Flags().IntVar(&flagTopCmdCount, "count", 0, "Number of files to list for each brick (default 10)")
+	volumeCmd.AddCommand(volumeTopCmd)
+}
+
+var volumeTopCmd = &cobra.Command{
+	Use:   "top <volname> {open|read|write|opendir|readdir} [--count=<n>]",
+	Short: helpVolumeTopCmd,
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname, metric := args[0], args[1]
+		resp, err := client.VolumeTop(volname, metric, flagTopCmdCount)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithField("volume", volname).Error("error getting volume top")
+			}
+			failure(fmt.Sprintf("Failed to get top %s of volume %s\n", metric, volname), err, 1)
+		}
+
+		for _, b := range resp.Bricks {
+			fmt.Printf("Brick: %s:%s\n", b.Brick.Hostname, b.Brick.Path)
+			if metric == "open" {
+				fmt.Println("Current open fds:", b.CurrentOpenFds)
+				fmt.Printf("Max open fds: %d (at %s)\n", b.MaxOpenFds, b.MaxOpenFdsTime)
+			}
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Count", "Name"})
+			for _, e := range b.Entries {
+				table.Append([]string{fmt.Sprint(e.Count), e.Name})
+			}
+			table.Render()
+			fmt.Println()
+		}
+	},
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	helpVolumeVolfileCmd = "Show the volfile generated for a Gluster Volume"
+)
+
+var (
+	// Volume Volfile Flags
+	flagVolfileCmdBrick string
+)
+
+func init() {
+	volumeVolfileCmd.Flags().StringVar(&flagVolfileCmdBrick, "brick", "", "ID of the brick, for brick volfiles")
+	volumeCmd.AddCommand(volumeVolfileCmd)
+}
+
+var volumeVolfileCmd = &cobra.Command{
+	Use:   "volfile <volname> <client|brick|shd|rebalance> [<file>] [--brick=<brick-id>]",
+	Short: helpVolumeVolfileCmd,
+	Args:  cobra.RangeArgs(2, 3),
+	Run: func(cmd *cobra.Command, args []string) {
+		volname, volfileType := args[0], args[1]
+		resp, err := client.VolumeVolfile(volname, volfileType, flagVolfileCmdBrick)
+		if err != nil {
+			if GlobalFlag.Verbose {
+				log.WithError(err).WithFields(log.Fields{
+					"volume": volname,
+					"type":   volfileType,
+				}).Error("error getting volfile")
+			}
+			failure(fmt.Sprintf("Failed to get %s volfile of volume %s", volfileType, volname), err, 1)
+		}
+
+		if len(args) < 3 {
+			fmt.Print(resp.Content)
+			return
+		}
+		if err := ioutil.WriteFile(args[2], []byte(resp.Content), 0644); err != nil {
+			failure("Failed to write volfile", err, 1)
+		}
+		fmt.Printf("%s volfile of volume %s saved to %s\n", volfileType, volname, args[2])
+	},
+}
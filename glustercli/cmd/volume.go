@@ -6,6 +6,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gluster/glusterd2/pkg/api"
 
@@ -35,17 +36,27 @@ var (
 	flagStartCmdForce bool
 
 	// Stop Command Flags
-	flagStopCmdForce bool
+	flagStopCmdForce    bool
+	flagStopCmdGraceful bool
+	flagStopCmdTimeout  time.Duration
 
 	// Expand Command Flags
 	flagExpandCmdReplicaCount    int
 	flagExpandCmdForce           bool
 	flagExpandCmdDistributeCount int
 	flagExpandCmdSize            string
+	flagExpandCmdRebalance       string
 
 	// Filter Volume Info/List command flags
 	flagCmdFilterKey   string
 	flagCmdFilterValue string
+	flagCmdFilterState string
+	flagCmdFilterType  string
+	flagCmdFilterLabel string
+	flagCmdListSort    string
+	flagCmdListOrder   string
+	flagCmdListOffset  int
+	flagCmdListLimit   int
 
 	//Filter Volume Get command flags
 	flagGetAdv bool
@@ -67,6 +78,8 @@ func init() {
 
 	// Volume Stop
 	volumeStopCmd.Flags().BoolVarP(&flagStopCmdForce, "force", "f", false, "Force")
+	volumeStopCmd.Flags().BoolVar(&flagStopCmdGraceful, "graceful", false, "Stop the volume only if no clients are connected")
+	volumeStopCmd.Flags().DurationVar(&flagStopCmdTimeout, "timeout", 0, "Time to wait for clients to disconnect with --graceful")
 	volumeCmd.AddCommand(volumeStopCmd)
 
 	// Volume Delete
@@ -87,6 +100,13 @@ func init() {
 
 	volumeListCmd.Flags().StringVar(&flagCmdFilterKey, "key", "", "Filter by metadata Key")
 	volumeListCmd.Flags().StringVar(&flagCmdFilterValue, "value", "", "Filter by metadata value")
+	volumeListCmd.Flags().StringVar(&flagCmdFilterState, "state", "", "Filter by volume state")
+	volumeListCmd.Flags().StringVar(&flagCmdFilterType, "type", "", "Filter by volume type")
+	volumeListCmd.Flags().StringVar(&flagCmdFilterLabel, "labels", "", "Filter by label selector, e.g. tenant=acme,app")
+	volumeListCmd.Flags().StringVar(&flagCmdListSort, "sort", "", "Sort by name, size or created")
+	volumeListCmd.Flags().StringVar(&flagCmdListOrder, "order", "", "Sort order, asc or desc")
+	volumeListCmd.Flags().IntVar(&flagCmdListOffset, "offset", 0, "Number of volumes to skip")
+	volumeListCmd.Flags().IntVar(&flagCmdListLimit, "limit", 0, "Maximum number of volumes to list")
 	volumeCmd.AddCommand(volumeListCmd)
 
 	// Volume Expand
@@ -94,6 +114,7 @@ func init() {
 	volumeExpandCmd.Flags().IntVar(&flagExpandCmdDistributeCount, "distribute", 0, "Distribute Count")
 	volumeExpandCmd.Flags().StringVar(&flagExpandCmdSize, "size", "", "Size by which volume needs to be expanded.")
 	volumeExpandCmd.Flags().BoolVarP(&flagExpandCmdForce, "force", "f", false, "Force")
+	volumeExpandCmd.Flags().StringVar(&flagExpandCmdRebalance, "rebalance", "", "Start rebalance after expansion <start|fix-layout|force>")
 	volumeExpandCmd.Flags().BoolVar(&flagReuseBricks, "reuse-bricks", false, "Reuse Bricks")
 	volumeExpandCmd.Flags().BoolVar(&flagAllowRootDir, "allow-root-dir", false, "Allow Root Directory")
 	volumeExpandCmd.Flags().BoolVar(&flagAllowMountAsBrick, "allow-mount-as-brick", false, "Allow Mount as Bricks")
@@ -199,7 +220,12 @@ var volumeStopCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		volname := cmd.Flags().Args()[0]
-		err := client.VolumeStop(volname)
+		var err error
+		if flagStopCmdGraceful {
+			err = client.VolumeStopGraceful(volname, flagStopCmdTimeout)
+		} else {
+			err = client.VolumeStop(volname)
+		}
 		if err != nil {
 			if GlobalFlag.Verbose {
 				log.WithError(err).WithField("volume", volname).Error("volume stop failed")
@@ -327,17 +353,27 @@ func volumeInfoHandler2(cmd *cobra.Command, isInfo bool) error {
 		volname = cmd.Flags().Args()[0]
 	}
 	if volname == "" {
-		if flagCmdFilterKey == "" && flagCmdFilterValue == "" {
-			vols, err = client.Volumes("")
-		} else if flagCmdFilterKey != "" && flagCmdFilterValue == "" {
-			vols, err = client.Volumes("", map[string]string{"key": flagCmdFilterKey})
-		} else if flagCmdFilterKey == "" && flagCmdFilterValue != "" {
-			vols, err = client.Volumes("", map[string]string{"value": flagCmdFilterValue})
-		} else if flagCmdFilterKey != "" && flagCmdFilterValue != "" {
-			vols, err = client.Volumes("", map[string]string{"key": flagCmdFilterKey,
-				"value": flagCmdFilterValue,
-			})
+		filterParams := make(map[string]string)
+		for k, v := range map[string]string{
+			"key":    flagCmdFilterKey,
+			"value":  flagCmdFilterValue,
+			"state":  flagCmdFilterState,
+			"type":   flagCmdFilterType,
+			"labels": flagCmdFilterLabel,
+			"sort":   flagCmdListSort,
+			"order":  flagCmdListOrder,
+		} {
+			if v != "" {
+				filterParams[k] = v
+			}
+		}
+		if flagCmdListOffset > 0 {
+			filterParams["offset"] = strconv.Itoa(flagCmdListOffset)
+		}
+		if flagCmdListLimit > 0 {
+			filterParams["limit"] = strconv.Itoa(flagCmdListLimit)
 		}
+		vols, err = client.Volumes("", filterParams)
 	} else {
 		if flagCmdFilterKey != "" || flagCmdFilterValue != "" {
 			return errors.New("invalid command. Cannot give filter arguments when providing volname")
@@ -410,10 +446,15 @@ var volumeListCmd = &cobra.Command{
 
 func volumeStatusDisplay(vol api.BricksStatusResp) {
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Brick ID", "Host", "Path", "Online", "Port", "Pid"})
+	table.SetHeader([]string{"Brick ID", "Host", "Path", "Online", "Port", "Pid", "Bricks In Process"})
 	for _, b := range vol {
+		online := strconv.FormatBool(b.Online)
+		if b.Failed {
+			online = "failed"
+		}
 		table.Append([]string{b.Info.ID.String(), b.Info.Hostname, b.Info.Path,
-			strconv.FormatBool(b.Online), strconv.Itoa(b.Port), strconv.Itoa(b.Pid)})
+			online, strconv.Itoa(b.Port), strconv.Itoa(b.Pid),
+			strconv.Itoa(b.BricksInProc)})
 	}
 	table.Render()
 }
@@ -521,14 +562,20 @@ var volumeExpandCmd = &cobra.Command{
 		flags["allow-root-dir"] = flagAllowRootDir
 		flags["allow-mount-as-brick"] = flagAllowMountAsBrick
 		flags["create-brick-dir"] = flagCreateBrickDir
-		vol, err := client.VolumeExpand(volname, api.VolExpandReq{
+		req := api.VolExpandReq{
 			ReplicaCount:    flagExpandCmdReplicaCount,
 			Bricks:          bricks, // string of format <UUID>:<path>
 			Force:           flagExpandCmdForce,
 			Flags:           flags,
 			DistributeCount: flagExpandCmdDistributeCount,
 			Size:            uint64(size),
-		})
+		}
+		var vol api.VolumeExpandResp
+		if flagExpandCmdRebalance != "" {
+			vol, err = client.VolumeExpandWithRebalance(volname, req, flagExpandCmdRebalance)
+		} else {
+			vol, err = client.VolumeExpand(volname, req)
+		}
 		if err != nil {
 			if GlobalFlag.Verbose {
 				log.WithError(err).WithField("volume", volname).Error("volume expansion failed")
@@ -536,6 +583,9 @@ var volumeExpandCmd = &cobra.Command{
 			failure("Addition of brick failed", err, 1)
 		}
 		fmt.Printf("%s Volume expanded successfully\n", vol.Name)
+		if vol.RebalanceID != nil {
+			fmt.Printf("Rebalance started with ID: %s\n", vol.RebalanceID)
+		}
 	},
 }
 
@@ -0,0 +1,212 @@
+// Package audit keeps a record of the mutating requests served by the REST
+// server: who made them, from where, with which body, the transactions they
+// started and how they ended. Records are written as JSON lines to the audit
+// log file of the peer and, optionally, to the store, from where the records
+// of all the peers can be queried.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+const (
+	auditPrefix = "audit/"
+
+	// LogFileFlag is the flag to set the name of the audit log file
+	LogFileFlag = "auditlogfile"
+	// StoreFlag is the flag to enable keeping audit records in the store
+	StoreFlag = "auditstore"
+
+	defaultAuditStoreTTL int64 = 7 * 86400
+
+	// auditLeaseBucket is the time during which the records saved in the
+	// store share a lease
+	auditLeaseBucket = time.Hour
+)
+
+var (
+	mu      sync.Mutex
+	logFile *os.File
+	logger  *log.Logger
+
+	// storeLease is the lease of the records of the current time bucket,
+	// so that a lease isn't granted for every record
+	storeLease struct {
+		sync.Mutex
+		bucket int64
+		id     clientv3.LeaseID
+	}
+
+	// grantLeaseF grants a lease of the given TTL in the store, tests
+	// replace it
+	grantLeaseF = grantLease
+)
+
+// Init opens the audit log file, closing it first if it is open so that it
+// can be rotated. The audit log file is not used if its name is configured
+// to be empty.
+func Init() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if logFile != nil {
+		logFile.Close()
+		logFile, logger = nil, nil
+	}
+
+	name := config.GetString(LogFileFlag)
+	if name == "" {
+		return nil
+	}
+
+	filepath := path.Join(config.GetString("logdir"), name)
+	f, err := os.OpenFile(filepath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log file %s: %s", filepath, err)
+	}
+
+	logFile = f
+	logger = log.New()
+	logger.Out = f
+	logger.Formatter = &log.JSONFormatter{TimestampFormat: time.RFC3339Nano}
+	return nil
+}
+
+// StoreEnabled returns true if audit records are kept in the store
+func StoreEnabled() bool {
+	return config.GetBool(StoreFlag)
+}
+
+// auditStoreTTL returns the number of seconds audit records are kept in the
+// store
+func auditStoreTTL() int64 {
+	ttl := config.GetInt64("auditstorettl")
+	if ttl == 0 {
+		ttl = defaultAuditStoreTTL
+	}
+	return ttl
+}
+
+// recordKey returns the key of a record in the store. Keys sort in the order
+// in which the records were made.
+func recordKey(rec *api.AuditRecord) string {
+	return fmt.Sprintf("%s%020d-%s", auditPrefix, rec.Time.UnixNano(), rec.ReqID)
+}
+
+func grantLease(ttl int64) (clientv3.LeaseID, error) {
+	l, err := store.Store.Grant(store.Store.Ctx(), ttl)
+	if err != nil {
+		return clientv3.NoLease, err
+	}
+	return l.ID, nil
+}
+
+// getLease returns the lease of the time bucket of a record, granting it for
+// the first record of the bucket. The lease outlives the bucket by the TTL of
+// the records, so that every record is kept for at least the TTL.
+func getLease(t time.Time) (clientv3.LeaseID, error) {
+	bucket := t.UnixNano() / int64(auditLeaseBucket)
+
+	storeLease.Lock()
+	defer storeLease.Unlock()
+
+	if storeLease.id != clientv3.NoLease && storeLease.bucket == bucket {
+		return storeLease.id, nil
+	}
+
+	id, err := grantLeaseF(auditStoreTTL() + int64(auditLeaseBucket/time.Second))
+	if err != nil {
+		return clientv3.NoLease, err
+	}
+	storeLease.bucket, storeLease.id = bucket, id
+	return id, nil
+}
+
+// resetLease forgets the lease of the current time bucket, so that a new one
+// is granted for the next record
+func resetLease(id clientv3.LeaseID) {
+	storeLease.Lock()
+	if storeLease.id == id {
+		storeLease.id = clientv3.NoLease
+	}
+	storeLease.Unlock()
+}
+
+// Log writes an audit record to the audit log file and, if enabled, to the
+// store. Failing to write a record must not fail the request, so errors are
+// only logged.
+func Log(rec *api.AuditRecord) {
+	mu.Lock()
+	if logger != nil {
+		logger.WithFields(log.Fields{
+			"reqid":       rec.ReqID,
+			"peer-id":     rec.PeerID,
+			"user":        rec.User,
+			"role":        rec.Role,
+			"source-ip":   rec.SourceIP,
+			"method":      rec.Method,
+			"path":        rec.Path,
+			"body-digest": rec.BodyDigest,
+			"txn-ids":     rec.TxnIDs,
+			"status":      rec.Status,
+			"outcome":     rec.Outcome,
+		}).Info("audit")
+	}
+	mu.Unlock()
+
+	if !StoreEnabled() {
+		return
+	}
+
+	entry := log.WithField("reqid", rec.ReqID)
+
+	v, err := json.Marshal(rec)
+	if err != nil {
+		entry.WithError(err).Error("failed to save audit record, failed to marshal record")
+		return
+	}
+
+	leaseID, err := getLease(rec.Time)
+	if err != nil {
+		entry.WithError(err).Error("failed to save audit record, failed to get lease")
+		return
+	}
+
+	if _, err := store.Put(context.TODO(), recordKey(rec), string(v), clientv3.WithLease(leaseID)); err != nil {
+		// The lease may have been revoked, don't use it again
+		resetLease(leaseID)
+		entry.WithError(err).Error("failed to save audit record, failed to write record to store")
+	}
+}
+
+// GetRecords returns the audit records in the store, oldest first
+func GetRecords() ([]*api.AuditRecord, error) {
+	resp, err := store.Get(context.TODO(), auditPrefix, clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*api.AuditRecord, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var rec api.AuditRecord
+		if err := json.Unmarshal(kv.Value, &rec); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal audit record")
+			continue
+		}
+		records = append(records, &rec)
+	}
+	return records, nil
+}
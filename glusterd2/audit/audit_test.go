@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/testutils"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetLease(t *testing.T) {
+	var grants []int64
+	grantErr := error(nil)
+	defer testutils.Patch(&grantLeaseF, func(ttl int64) (clientv3.LeaseID, error) {
+		if grantErr != nil {
+			return clientv3.NoLease, grantErr
+		}
+		grants = append(grants, ttl)
+		return clientv3.LeaseID(len(grants)), nil
+	}).Restore()
+	storeLease.id = clientv3.NoLease
+
+	hour := time.Date(2018, 10, 1, 10, 0, 0, 0, time.UTC)
+
+	// The records of an hour share a lease, which outlives the hour by the
+	// TTL of the records
+	id, err := getLease(hour.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, clientv3.LeaseID(1), id)
+	assert.Equal(t, []int64{defaultAuditStoreTTL + 3600}, grants)
+
+	id, err = getLease(hour.Add(59 * time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, clientv3.LeaseID(1), id)
+	assert.Len(t, grants, 1)
+
+	// and the next hour gets a new one
+	id, err = getLease(hour.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, clientv3.LeaseID(2), id)
+
+	// A lease which failed to be used is granted again
+	resetLease(clientv3.LeaseID(1))
+	id, _ = getLease(hour.Add(time.Hour))
+	assert.Equal(t, clientv3.LeaseID(2), id)
+	resetLease(id)
+	id, _ = getLease(hour.Add(time.Hour))
+	assert.Equal(t, clientv3.LeaseID(3), id)
+
+	// and a lease which failed to be granted is not kept
+	resetLease(id)
+	grantErr = errors.New("no leader")
+	_, err = getLease(hour.Add(time.Hour))
+	assert.Error(t, err)
+	assert.Equal(t, clientv3.NoLease, storeLease.id)
+}
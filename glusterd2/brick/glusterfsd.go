@@ -14,6 +14,7 @@ import (
 	"github.com/cespare/xxhash"
 	"github.com/gluster/glusterd2/glusterd2/daemon"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/pmap"
 	"github.com/gluster/glusterd2/pkg/api"
 	log "github.com/sirupsen/logrus"
 
@@ -43,6 +44,9 @@ type Glusterfsd struct {
 
 	// For internal use
 	brickinfo Brickinfo
+	// port is the port allocated to the brick process, 0 to let it pick
+	// one
+	port int
 }
 
 // Name returns human-friendly name of the brick process. This is used for logging.
@@ -78,6 +82,9 @@ func (b *Glusterfsd) Args() []string {
 	b.args = append(b.args, "-p", b.PidFile())
 	b.args = append(b.args, "-S", b.SocketFile())
 	b.args = append(b.args, "--brick-name", b.brickinfo.Path)
+	if b.port != 0 {
+		b.args = append(b.args, "--brick-port", strconv.Itoa(b.port))
+	}
 	b.args = append(b.args, "-l", logFile)
 	b.args = append(b.args,
 		"--xlator-option",
@@ -137,6 +144,18 @@ func (b *Glusterfsd) ID() string {
 	return b.brickinfo.Path
 }
 
+// Cgroup returns the cgroup of the brick process. The brick processes of a
+// volume share the cgroup, and so its resource limits.
+func (b *Glusterfsd) Cgroup() string {
+	return CgroupName(b.brickinfo.VolumeName)
+}
+
+// CgroupName returns the name of the cgroup which the brick processes of the
+// volume are run in
+func CgroupName(volname string) string {
+	return path.Join("bricks", volname)
+}
+
 // BrickStartMaxRetries represents maximum no. of attempts that will be made
 // to start brick processes in case of port clashes.
 const BrickStartMaxRetries = 3
@@ -176,6 +195,14 @@ func (b Brickinfo) StartBrick(logger log.FieldLogger) error {
 			return err
 		}
 
+		// a port which is taken by now is replaced on the next attempt,
+		// the port of a running brick is left alone
+		if running, _ := daemon.IsRunning(brickDaemon); !running {
+			if brickDaemon.port, err = pmap.AllocatePort(b.Path); err != nil {
+				return err
+			}
+		}
+
 		err = daemon.Start(brickDaemon, true, logger)
 		if err != nil {
 			if errorContainsErrno(err, syscall.EADDRINUSE) || errorContainsErrno(err, anotherEADDRINUSE) {
@@ -260,13 +287,14 @@ func CreateBrickSizeInfo(size *SizeInfo) api.SizeInfo {
 //CreateBrickInfo parses brick information for response
 func CreateBrickInfo(b *Brickinfo) api.BrickInfo {
 	return api.BrickInfo{
-		ID:         b.ID,
-		Path:       b.Path,
-		VolumeID:   b.VolumeID,
-		VolumeName: b.VolumeName,
-		PeerID:     b.PeerID,
-		Hostname:   b.Hostname,
-		Type:       api.BrickType(b.Type),
+		ID:             b.ID,
+		Path:           b.Path,
+		VolumeID:       b.VolumeID,
+		VolumeName:     b.VolumeName,
+		PeerID:         b.PeerID,
+		Hostname:       b.Hostname,
+		Type:           api.BrickType(b.Type),
+		Decommissioned: b.Decommissioned,
 	}
 }
 
@@ -286,14 +314,23 @@ func CreateBrickStatusRsp(brickStatuses []Brickstatus) []*api.BrickStatus {
 	var brickStatusesRsp []*api.BrickStatus
 	for _, status := range brickStatuses {
 		s := &api.BrickStatus{
-			Info:      CreateBrickInfo(&status.Info),
-			Online:    status.Online,
-			Pid:       status.Pid,
-			Port:      status.Port,
-			FS:        status.FS,
-			MountOpts: status.MountOpts,
-			Device:    status.Device,
-			Size:      CreateBrickSizeInfo(&status.Size),
+			Info:         CreateBrickInfo(&status.Info),
+			Online:       status.Online,
+			Failed:       status.Failed,
+			Pid:          status.Pid,
+			Port:         status.Port,
+			BricksInProc: status.BricksInProc,
+			FS:           status.FS,
+			MountOpts:    status.MountOpts,
+			Device:       status.Device,
+			Size:         CreateBrickSizeInfo(&status.Size),
+		}
+		if status.CgroupUsage != nil {
+			s.Cgroup = &api.CgroupUsage{
+				Name:    status.Cgroup,
+				CPUTime: status.CgroupUsage.CPUTime,
+				Memory:  status.CgroupUsage.Memory,
+			}
 		}
 		brickStatusesRsp = append(brickStatusesRsp, s)
 	}
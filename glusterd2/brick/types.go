@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/gluster/glusterd2/pkg/cgroups"
+
 	"github.com/pborman/uuid"
 	"golang.org/x/sys/unix"
 )
@@ -66,14 +68,24 @@ type SizeInfo struct {
 
 //Brickstatus gives status of brick
 type Brickstatus struct {
-	Info      Brickinfo
-	Online    bool
-	Pid       int
-	Port      int
-	FS        string
-	MountOpts string
-	Device    string
-	Size      SizeInfo
+	Info   Brickinfo
+	Online bool
+	// Failed is set if the brick process died and could not be
+	// restarted
+	Failed bool
+	Pid    int
+	Port   int
+	// BricksInProc is the number of bricks run by the brick process,
+	// which is more than one when bricks are multiplexed
+	BricksInProc int
+	FS           string
+	MountOpts    string
+	Device       string
+	Size         SizeInfo
+	// Cgroup is the cgroup the brick process is run in, if any, and
+	// CgroupUsage the resources used by all the processes in it
+	Cgroup      string
+	CgroupUsage *cgroups.Usage
 }
 
 const (
@@ -12,18 +12,6 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-// IsLastBrickInProc returns true if the brick specified is the last brick
-// present in the brick process.
-func IsLastBrickInProc(b brick.Brickinfo) bool {
-
-	port, err := pmap.RegistrySearch(b.Path)
-	if err != nil {
-		return false
-	}
-
-	return len(pmap.GetBricksOnPort(port)) == 1
-}
-
 // Demultiplex sends a detach request to the brick process which the
 // specified brick is multiplexed onto.
 func Demultiplex(b brick.Brickinfo) error {
@@ -71,3 +59,41 @@ func Demultiplex(b brick.Brickinfo) error {
 
 	return nil
 }
+
+// IsMultiplexed returns true if other bricks are multiplexed onto the process
+// of the specified brick.
+func IsMultiplexed(b brick.Brickinfo) bool {
+	port, err := pmap.RegistrySearch(b.Path)
+	if err != nil {
+		return false
+	}
+
+	return len(pmap.GetBricksOnPort(port)) > 1
+}
+
+// StopBrick stops the specified brick. If other bricks are multiplexed onto
+// the same process, the brick is only detached from the process, which is
+// left running for the other bricks. This holds even if brick multiplexing
+// has been turned off since the bricks were started.
+func StopBrick(b brick.Brickinfo, logger log.FieldLogger) error {
+	if !IsMultiplexed(b) {
+		logger.WithField("brick", b.String()).Info("Stopping brick")
+		return b.StopBrick(logger)
+	}
+
+	brickDaemon, err := brick.NewGlusterfsd(b)
+	if err != nil {
+		return err
+	}
+
+	logger.WithField("brick", b.String()).Info("Calling demultiplex for the brick")
+	if err := Demultiplex(b); err != nil {
+		return err
+	}
+
+	if err := daemon.DelDaemon(brickDaemon); err != nil {
+		logger.WithError(err).WithField("brick", b.String()).Warn(
+			"failed to delete brick entry from store, it may be restarted on GlusterD restart")
+	}
+	return nil
+}
@@ -17,6 +17,10 @@ import (
 const (
 	minBrickSize            = 20 * gutils.MiB
 	defaultMaxLoopBrickSize = 100 * gutils.GiB
+
+	// DevicesLockID is the cluster lock held by operations which plan
+	// bricks on devices, until the planned bricks are provisioned
+	DevicesLockID = "devices"
 )
 
 func handleReplicaSubvolReq(req *api.VolCreateReq) error {
@@ -188,13 +192,42 @@ func getBricksLayout(req *api.VolCreateReq) ([]api.SubvolReq, error) {
 	return subvols, nil
 }
 
+// Reservations tracks the device space already allocated to bricks which are
+// planned but not yet provisioned, keyed by peer ID and VG name
+type Reservations map[string]uint64
+
+func reservationKey(peerID, vgName string) string {
+	return peerID + "/" + vgName
+}
+
 // PlanBricks creates the brick layout with chosen device and size information
 func PlanBricks(req *api.VolCreateReq) error {
+	return PlanBricksWithReservations(req, nil)
+}
+
+// PlanBricksWithReservations creates the brick layout like PlanBricks, but
+// treats the space recorded in reserved as unavailable. The space allocated
+// for the planned bricks is added to reserved so that a single planning pass
+// can be shared by multiple volume create requests.
+func PlanBricksWithReservations(req *api.VolCreateReq, reserved Reservations) error {
 	availableVgs, err := GetAvailableVgs(req)
 	if err != nil {
 		return err
 	}
 
+	for idx := range availableVgs {
+		size, exists := reserved[reservationKey(availableVgs[idx].PeerID, availableVgs[idx].Name)]
+		if !exists {
+			continue
+		}
+		if size >= availableVgs[idx].AvailableSize {
+			availableVgs[idx].AvailableSize = 0
+		} else {
+			availableVgs[idx].AvailableSize -= size
+		}
+		availableVgs[idx].Used = true
+	}
+
 	if len(availableVgs) == 0 {
 		return errors.New("no devices registered or available for allocating bricks")
 	}
@@ -219,7 +252,8 @@ func PlanBricks(req *api.VolCreateReq) error {
 		// with device with expected space available.
 		numBricksAllocated := 0
 		for bidx, b := range sv.Bricks {
-			for _, vg := range availableVgs {
+			for vidx := range availableVgs {
+				vg := &availableVgs[vidx]
 				_, zoneUsed := zones[vg.Zone]
 				if vg.AvailableSize >= b.TotalSize && !zoneUsed && !vg.Used {
 					subvols[idx].Bricks[bidx].PeerID = vg.PeerID
@@ -249,7 +283,8 @@ func PlanBricks(req *api.VolCreateReq) error {
 		// but enough space is available in the devices
 		for bidx := numBricksAllocated; bidx < len(sv.Bricks); bidx++ {
 			b := sv.Bricks[bidx]
-			for _, vg := range availableVgs {
+			for vidx := range availableVgs {
+				vg := &availableVgs[vidx]
 				_, zoneUsed := zones[vg.Zone]
 				if vg.AvailableSize >= b.TotalSize && !zoneUsed {
 					subvols[idx].Bricks[bidx].PeerID = vg.PeerID
@@ -275,6 +310,14 @@ func PlanBricks(req *api.VolCreateReq) error {
 		}
 	}
 
+	if reserved != nil {
+		for _, sv := range subvols {
+			for _, b := range sv.Bricks {
+				reserved[reservationKey(b.PeerID, b.VgName)] += b.TotalSize
+			}
+		}
+	}
+
 	req.Subvols = subvols
 	return nil
 }
@@ -3,7 +3,6 @@ package bricksplanner
 import (
 	"fmt"
 	"sort"
-	"strings"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/peer"
@@ -12,7 +11,6 @@ import (
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/lvmutils"
 	"github.com/gluster/glusterd2/pkg/utils"
-	deviceapi "github.com/gluster/glusterd2/plugins/device/api"
 	"github.com/gluster/glusterd2/plugins/device/deviceutils"
 )
 
@@ -51,11 +49,13 @@ func GetAvailableVgs(req *api.VolCreateReq) ([]Vg, error) {
 			continue
 		}
 
-		peerzone, exists := p.Metadata["_zone"]
-		if !exists || strings.TrimSpace(peerzone) == "" {
-			peerzone = p.ID.String()
+		// Peers under maintenance may go down at any time
+		if p.InMaintenance() {
+			continue
 		}
 
+		peerzone := p.GetZone()
+
 		// If List of Peer IDs specified to limit choosing the bricks from
 		if len(req.LimitPeers) > 0 && !utils.StringInSlice(p.ID.String(), req.LimitPeers) {
 			continue
@@ -88,7 +88,7 @@ func GetAvailableVgs(req *api.VolCreateReq) ([]Vg, error) {
 
 		for _, d := range deviceInfo {
 			// If Device is not enabled to be used for provisioning
-			if d.State == deviceapi.DeviceDisabled {
+			if !d.Schedulable() {
 				continue
 			}
 
@@ -0,0 +1,233 @@
+// Package certs keeps the TLS certificates used by the servers and clients
+// of glusterd2. The certificates are loaded from files and can be reloaded
+// while glusterd2 is running, so that they can be rotated without a restart.
+//
+// There are two sets of certificates. The server set is used by the REST and
+// the TCP SunRPC servers; with a client CA, the clients of these servers
+// must present a certificate signed by it. The peer set is used by both ends
+// of the connections between peers, which authenticate each other with
+// certificates signed by the peer CA.
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+// Set is a certificate with its private key, and the CA which must have
+// signed the certificates of the other ends of the connections
+type Set struct {
+	name                   string
+	certOpt, keyOpt, caOpt string
+	// caRequired is set if the set can't be used without a CA
+	caRequired bool
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+	leaf *x509.Certificate
+	ca   *x509.CertPool
+	// verifyClients is set if a CA was configured when the set was first
+	// loaded. Whether client certificates are asked for can't be changed
+	// by a reload.
+	verifyClients bool
+	loaded        bool
+}
+
+var (
+	// Server is the set used by the REST and SunRPC servers
+	Server = &Set{name: "server", certOpt: "cert-file", keyOpt: "key-file", caOpt: "client-ca-file"}
+	// Peer is the set used by the connections between peers
+	Peer = &Set{name: "peer", certOpt: "peer-cert-file", keyOpt: "peer-key-file", caOpt: "peer-ca-file", caRequired: true}
+)
+
+// Enabled returns true if a certificate is configured for the set
+func (s *Set) Enabled() bool {
+	return config.GetString(s.certOpt) != "" && config.GetString(s.keyOpt) != ""
+}
+
+// Load loads the certificate, key and CA of the set from the configured
+// files. The certificates in use are only replaced if all of them load.
+func (s *Set) Load() error {
+	certfile := config.GetString(s.certOpt)
+	keyfile := config.GetString(s.keyOpt)
+	cafile := config.GetString(s.caOpt)
+
+	cert, err := tls.LoadX509KeyPair(certfile, keyfile)
+	if err != nil {
+		return fmt.Errorf("failed to load %s certificate: %s", s.name, err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("failed to parse %s certificate: %s", s.name, err)
+	}
+
+	var ca *x509.CertPool
+	if cafile != "" {
+		pem, err := ioutil.ReadFile(cafile)
+		if err != nil {
+			return fmt.Errorf("failed to read %s CA: %s", s.name, err)
+		}
+		ca = x509.NewCertPool()
+		if !ca.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %s CA file %s", s.name, cafile)
+		}
+	} else if s.caRequired {
+		return fmt.Errorf("%s is required along with %s", s.caOpt, s.certOpt)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.loaded && s.verifyClients && ca == nil {
+		return fmt.Errorf("%s can't be removed while glusterd2 is running", s.caOpt)
+	}
+	if !s.loaded {
+		s.verifyClients = ca != nil
+	}
+	s.cert, s.leaf, s.ca = &cert, leaf, ca
+	s.loaded = true
+
+	log.WithFields(log.Fields{
+		"set":       s.name,
+		"subject":   leaf.Subject.String(),
+		"not-after": leaf.NotAfter,
+	}).Info("loaded certificates")
+	return nil
+}
+
+func (s *Set) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cert, nil
+}
+
+func (s *Set) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cert, nil
+}
+
+// verify checks that the certificate chain presented by the other end of a
+// connection is signed by the CA of the set
+func (s *Set) verify(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	s.mu.RLock()
+	ca := s.ca
+	s.mu.RUnlock()
+
+	if len(rawCerts) == 0 {
+		return errors.New("no certificate presented")
+	}
+
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certs[i] = cert
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         ca,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err
+}
+
+// ServerConfig returns the TLS config for servers using the set. The
+// certificate and the CA in use are picked up for every new connection.
+func (s *Set) ServerConfig() *tls.Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	conf := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: s.getCertificate,
+	}
+	if s.verifyClients {
+		// The chain is verified against the CA in use by verify, as
+		// ClientCAs can't be changed once the config is in use
+		conf.ClientAuth = tls.RequireAnyClientCert
+		conf.VerifyPeerCertificate = s.verify
+	}
+	return conf
+}
+
+// ClientConfig returns the TLS config for clients using the set. Servers
+// are authenticated by their certificate being signed by the CA of the set
+// and not by their host name, as peers are often known by their addresses.
+func (s *Set) ClientConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:           tls.VersionTLS12,
+		GetClientCertificate: s.getClientCertificate,
+		// The server certificate is verified by VerifyPeerCertificate
+		InsecureSkipVerify:    true,
+		VerifyPeerCertificate: s.verify,
+	}
+}
+
+// Info returns the details of the certificate in use by the set, or nil if
+// the set is not loaded
+func (s *Set) Info() *api.CertInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.loaded {
+		return nil
+	}
+	return &api.CertInfo{
+		File:          config.GetString(s.certOpt),
+		Subject:       s.leaf.Subject.String(),
+		Issuer:        s.leaf.Issuer.String(),
+		SerialNumber:  s.leaf.SerialNumber.String(),
+		NotBefore:     s.leaf.NotBefore,
+		NotAfter:      s.leaf.NotAfter,
+		CAFile:        config.GetString(s.caOpt),
+		VerifyClients: s.verifyClients,
+	}
+}
+
+// Load loads the sets of certificates which are configured
+func Load() error {
+	for _, s := range []*Set{Server, Peer} {
+		if !s.Enabled() {
+			continue
+		}
+		if err := s.Load(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reload loads again the sets of certificates which are in use. A set can't
+// be enabled by a reload, as the servers using it have already started.
+func Reload() error {
+	for _, s := range []*Set{Server, Peer} {
+		s.mu.RLock()
+		loaded := s.loaded
+		s.mu.RUnlock()
+		if !loaded {
+			continue
+		}
+		if err := s.Load(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	config "github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCert creates a certificate signed by parent, or a self signed CA
+// if parent is nil
+func newTestCert(t *testing.T, cn string, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert, key}
+}
+
+func (c *testCert) write(t *testing.T, dir, name string) (certfile, keyfile string) {
+	certfile = path.Join(dir, name+".pem")
+	keyfile = path.Join(dir, name+"-key.pem")
+
+	require.NoError(t, ioutil.WriteFile(certfile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0600))
+	der, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(keyfile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600))
+	return certfile, keyfile
+}
+
+// handshake connects a client using clientConf to a server using serverConf
+// and returns the error seen by the client
+func handshake(t *testing.T, serverConf, clientConf *tls.Config) error {
+	l, err := tls.Listen("tcp", "127.0.0.1:0", serverConf)
+	require.NoError(t, err)
+	defer l.Close()
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		conn.(*tls.Conn).Handshake()
+		conn.Write([]byte{0})
+		conn.Close()
+	}()
+
+	conn, err := tls.Dial("tcp", l.Addr().String(), clientConf)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	// With TLS 1.3 the client learns that its certificate was rejected
+	// only on its first read
+	_, err = conn.Read(make([]byte, 1))
+	return err
+}
+
+func TestPeerCertificates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestPeerCertificates")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ca := newTestCert(t, "peer-ca", nil)
+	cafile, _ := ca.write(t, dir, "ca")
+	certfile, keyfile := newTestCert(t, "peer1", ca).write(t, dir, "peer1")
+
+	s := &Set{name: "test", certOpt: "test-cert-file", keyOpt: "test-key-file", caOpt: "test-ca-file", caRequired: true}
+	assert.False(t, s.Enabled())
+	assert.Nil(t, s.Info())
+
+	config.Set("test-cert-file", certfile)
+	config.Set("test-key-file", keyfile)
+	assert.True(t, s.Enabled())
+	// The CA is required for peers to authenticate each other
+	assert.Error(t, s.Load())
+
+	config.Set("test-ca-file", cafile)
+	require.NoError(t, s.Load())
+	assert.Equal(t, "CN=peer1", s.Info().Subject)
+	assert.True(t, s.Info().VerifyClients)
+
+	assert.NoError(t, handshake(t, s.ServerConfig(), s.ClientConfig()))
+
+	// A client with a certificate signed by another CA is rejected
+	other := newTestCert(t, "other-ca", nil)
+	otherCert := newTestCert(t, "intruder", other)
+	intruder := &tls.Config{
+		InsecureSkipVerify: true,
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{otherCert.cert.Raw},
+			PrivateKey:  otherCert.key,
+		}},
+	}
+	assert.Error(t, handshake(t, s.ServerConfig(), intruder))
+
+	// Rotating the CA and the certificate applies to the configs in use
+	serverConf, clientConf := s.ServerConfig(), s.ClientConfig()
+	otherCA, _ := other.write(t, dir, "ca")
+	otherCertfile, otherKeyfile := otherCert.write(t, dir, "peer1")
+	require.Equal(t, cafile, otherCA)
+	require.Equal(t, certfile, otherCertfile)
+	require.Equal(t, keyfile, otherKeyfile)
+	require.NoError(t, s.Load())
+	assert.Equal(t, "CN=intruder", s.Info().Subject)
+	assert.NoError(t, handshake(t, serverConf, intruder))
+	assert.NoError(t, handshake(t, serverConf, clientConf))
+
+	// A failed reload leaves the certificates in use as they are
+	require.NoError(t, ioutil.WriteFile(keyfile, []byte("garbage"), 0600))
+	assert.Error(t, s.Load())
+	assert.Equal(t, "CN=intruder", s.Info().Subject)
+}
+
+func TestServerCertificatesWithoutClientCA(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestServerCertificates")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ca := newTestCert(t, "ca", nil)
+	certfile, keyfile := newTestCert(t, "server", ca).write(t, dir, "server")
+
+	s := &Set{name: "test-server", certOpt: "test-server-cert-file", keyOpt: "test-server-key-file", caOpt: "test-server-ca-file"}
+	config.Set("test-server-cert-file", certfile)
+	config.Set("test-server-key-file", keyfile)
+	require.NoError(t, s.Load())
+	assert.False(t, s.Info().VerifyClients)
+
+	// Clients without a certificate are accepted
+	assert.NoError(t, handshake(t, s.ServerConfig(), &tls.Config{InsecureSkipVerify: true}))
+}
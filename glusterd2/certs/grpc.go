@@ -0,0 +1,25 @@
+package certs
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// GrpcServerOptions returns the options for the gRPC server of a peer to
+// accept only peers with a certificate signed by the peer CA, if the peer
+// set is enabled
+func GrpcServerOptions() []grpc.ServerOption {
+	if !Peer.Enabled() {
+		return nil
+	}
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(Peer.ServerConfig()))}
+}
+
+// GrpcDialOption returns the option for connections to other peers, which
+// use the peer certificates if the peer set is enabled
+func GrpcDialOption() grpc.DialOption {
+	if !Peer.Enabled() {
+		return grpc.WithInsecure()
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(Peer.ClientConfig()))
+}
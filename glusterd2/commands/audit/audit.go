@@ -0,0 +1,92 @@
+package auditcommands
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/audit"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+)
+
+// parseTimeParam returns the time given in RFC 3339 format as the query
+// parameter name, or the zero time if it isn't given
+func parseTimeParam(r *http.Request, name string) (time.Time, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, gderrors.ErrInvalidTime
+	}
+	return t, nil
+}
+
+// auditListHandler lists the audit records in the store, oldest first,
+// optionally filtered by the user, method, path prefix, outcome, peer and
+// time range given as query parameters
+func auditListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	if !audit.StoreEnabled() {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrAuditStoreDisabled)
+		return
+	}
+
+	page, err := restutils.GetPage(r)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	since, err := parseTimeParam(r, "since")
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	until, err := parseTimeParam(r, "until")
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	records, err := audit.GetRecords()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := make(api.AuditListResp, 0, len(records))
+	for _, rec := range records {
+		if u := query.Get("user"); u != "" && u != rec.User {
+			continue
+		}
+		if m := query.Get("method"); m != "" && !strings.EqualFold(m, rec.Method) {
+			continue
+		}
+		if p := query.Get("path"); p != "" && !strings.HasPrefix(rec.Path, p) {
+			continue
+		}
+		if o := query.Get("outcome"); o != "" && o != rec.Outcome {
+			continue
+		}
+		if p := query.Get("peer"); p != "" && p != rec.PeerID {
+			continue
+		}
+		if !since.IsZero() && rec.Time.Before(since) {
+			continue
+		}
+		if !until.IsZero() && !rec.Time.Before(until) {
+			continue
+		}
+		resp = append(resp, *rec)
+	}
+
+	start, end := page.Apply(w, r, len(resp), func(i int) string { return resp[i].ReqID })
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp[start:end])
+}
@@ -0,0 +1,33 @@
+// Package auditcommands implements the command to query the audit records
+// of the mutating requests served by the peers
+package auditcommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "AuditList",
+			Method:       "GET",
+			Pattern:      "/audit",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.AuditListResp)(nil)),
+			HandlerFunc:  auditListHandler,
+			Role:         api.RoleAdmin,
+		},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
@@ -0,0 +1,34 @@
+package backupcommands
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+)
+
+// backupHandler sends a backup of the keys of the cluster in the store, as a
+// gzip compressed JSON archive
+func backupHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	b, err := store.Store.Backup(ctx)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	filename := fmt.Sprintf("glusterd2-backup-%s-%s.json.gz", b.ClusterID, b.CreatedAt.Format("20060102150405"))
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	if err := store.WriteBackup(w, b); err != nil {
+		logger.WithError(err).Error("failed to send the cluster backup")
+		return
+	}
+
+	logger.WithField("revision", b.Revision).WithField("keys", len(b.Keys)).Info("cluster backup taken")
+}
@@ -0,0 +1,31 @@
+// Package backupcommands implements the command to take a backup of the
+// cluster metadata kept in the store
+package backupcommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:        "ClusterBackup",
+			Method:      "POST",
+			Pattern:     "/cluster/backup",
+			Version:     1,
+			HandlerFunc: backupHandler,
+			Role:        api.RoleAdmin,
+		},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
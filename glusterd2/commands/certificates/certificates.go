@@ -0,0 +1,70 @@
+package certcommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/certs"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+func createCertificatesResp() *api.CertificatesResp {
+	return &api.CertificatesResp{
+		PeerID: gdctx.MyUUID.String(),
+		Server: certs.Server.Info(),
+		Peer:   certs.Peer.Info(),
+	}
+}
+
+func getCertificatesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createCertificatesResp())
+}
+
+// reloadCertificates loads again the certificates in use from their files.
+// New connections use the reloaded certificates, while established ones
+// are left as they are.
+func reloadCertificates(c transaction.TxnCtx) error {
+	if err := certs.Reload(); err != nil {
+		c.Logger().WithError(err).Error("failed to reload certificates")
+		return err
+	}
+	return nil
+}
+
+// reloadCertificatesHandler reloads the certificates on all the peers, after
+// the new certificates have been put in place of the old ones
+func reloadCertificatesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	allNodes, err := peer.GetPeerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "certificates.Reload",
+			Nodes:  allNodes,
+		},
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).Error("certificates reload transaction failed")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.Info("reloaded certificates on all peers")
+	resp := (*api.CertificatesReloadResp)(createCertificatesResp())
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -0,0 +1,42 @@
+// Package certcommands implements the commands to look at and rotate the
+// SSL/TLS certificates of the peers
+package certcommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "GetCertificates",
+			Method:       "GET",
+			Pattern:      "/certificates",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.CertificatesResp)(nil)),
+			HandlerFunc:  getCertificatesHandler,
+		},
+		route.Route{
+			Name:         "ReloadCertificates",
+			Method:       "POST",
+			Pattern:      "/certificates/reload",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.CertificatesReloadResp)(nil)),
+			HandlerFunc:  reloadCertificatesHandler,
+			Role:         api.RoleAdmin,
+		},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	transaction.RegisterStepFunc(reloadCertificates, "certificates.Reload")
+}
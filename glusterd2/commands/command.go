@@ -2,9 +2,20 @@
 package commands
 
 import (
+	"github.com/gluster/glusterd2/glusterd2/commands/audit"
+	"github.com/gluster/glusterd2/glusterd2/commands/backup"
+	"github.com/gluster/glusterd2/glusterd2/commands/certificates"
+	"github.com/gluster/glusterd2/glusterd2/commands/glusterd1"
+	"github.com/gluster/glusterd2/glusterd2/commands/health"
+	"github.com/gluster/glusterd2/glusterd2/commands/jobs"
+	"github.com/gluster/glusterd2/glusterd2/commands/maintenance"
 	"github.com/gluster/glusterd2/glusterd2/commands/options"
 	"github.com/gluster/glusterd2/glusterd2/commands/peers"
+	"github.com/gluster/glusterd2/glusterd2/commands/schedules"
 	"github.com/gluster/glusterd2/glusterd2/commands/snapshot"
+	"github.com/gluster/glusterd2/glusterd2/commands/store"
+	"github.com/gluster/glusterd2/glusterd2/commands/transactions"
+	"github.com/gluster/glusterd2/glusterd2/commands/users"
 	"github.com/gluster/glusterd2/glusterd2/commands/version"
 	"github.com/gluster/glusterd2/glusterd2/commands/volumes"
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
@@ -25,4 +36,15 @@ var Commands = []Command{
 	&snapshotcommands.Command{},
 	&peercommands.Command{},
 	&optionscommands.Command{},
+	&txncommands.Command{},
+	&schedulecommands.Command{},
+	&jobcommands.Command{},
+	&usercommands.Command{},
+	&certcommands.Command{},
+	&auditcommands.Command{},
+	&maintenancecommands.Command{},
+	&healthcommands.Command{},
+	&glusterd1commands.Command{},
+	&backupcommands.Command{},
+	&storecommands.Command{},
 }
@@ -0,0 +1,34 @@
+// Package glusterd1commands implements the command importing the peers and
+// volumes of a glusterd1 (legacy) cluster, for in-place upgrades
+package glusterd1commands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "Glusterd1Import",
+			Method:       "POST",
+			Pattern:      "/glusterd1/import",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.Glusterd1ImportReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.Glusterd1ImportResp)(nil)),
+			HandlerFunc:  glusterd1ImportHandler,
+			Role:         api.RoleAdmin,
+		},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
@@ -0,0 +1,221 @@
+package glusterd1commands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/commands/peers"
+	"github.com/gluster/glusterd2/glusterd2/commands/volumes"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/glusterd1"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	"github.com/pborman/uuid"
+)
+
+// peerMap maps the glusterd1 peers to the peers of the cluster, by their
+// glusterd1 UUID and by their hostnames
+type peerMap struct {
+	byUUID     map[string]uuid.UUID
+	byHostname map[string]uuid.UUID
+}
+
+// peerOf returns the ID of the peer of the brick, or nil if it is unknown
+func (m *peerMap) peerOf(b *api.BrickExport) uuid.UUID {
+	if id, ok := m.byUUID[b.PeerID.String()]; ok {
+		return id
+	}
+	if id, ok := m.byHostname[b.Hostname]; ok {
+		return id
+	}
+	// glusterd1 does not store the hostnames of the local peer
+	if local, _ := utils.IsLocalAddress(b.Hostname); local {
+		return gdctx.MyUUID
+	}
+	return nil
+}
+
+// importPeer finds the peer of the cluster with the addresses of the
+// glusterd1 peer, and adds it if it is missing and addPeers is set
+func importPeer(ctx context.Context, p *glusterd1.Peer, addPeers bool) api.Glusterd1PeerImport {
+	result := api.Glusterd1PeerImport{
+		UUID:      p.UUID,
+		Hostnames: p.Hostnames,
+		State:     api.Glusterd1PeerMissing,
+	}
+	if len(p.Hostnames) == 0 {
+		result.State = api.Glusterd1ImportFailed
+		result.Error = gderrors.ErrNoHostnamesPresent.Error()
+		return result
+	}
+
+	existing, err := peer.GetPeerByAddrs(p.Hostnames)
+	if err == nil {
+		result.PeerID = existing.ID
+		result.State = api.Glusterd1PeerMember
+		return result
+	} else if err != gderrors.ErrPeerNotFound {
+		result.State = api.Glusterd1ImportFailed
+		result.Error = err.Error()
+		return result
+	}
+	if !addPeers {
+		return result
+	}
+
+	added, _, err := peercommands.AddPeer(ctx, &api.PeerAddReq{Addresses: p.Hostnames})
+	if err != nil {
+		result.State = api.Glusterd1ImportFailed
+		result.Error = err.Error()
+		return result
+	}
+	result.PeerID = added.ID
+	result.State = api.Glusterd1PeerAdded
+	return result
+}
+
+// filterOptions removes the glusterd1 options which glusterd2 does not know
+// or which can't be set, and returns them
+func filterOptions(export *api.VolumeExport, v *glusterd1.Volume) []string {
+	var skipped []string
+	for k, val := range v.Options {
+		o, err := xlator.FindOption(k)
+		if err == nil && o.IsSettable() && o.Validate(val) == nil {
+			continue
+		}
+		delete(export.Options, k)
+		skipped = append(skipped, k)
+	}
+	sort.Strings(skipped)
+	return skipped
+}
+
+// importVolume imports the glusterd1 volume, or only checks that it can be
+// imported for dry runs
+func importVolume(ctx context.Context, v *glusterd1.Volume, peers *peerMap, dryRun bool) api.Glusterd1VolumeImport {
+	result := api.Glusterd1VolumeImport{
+		Name:    v.Name,
+		ID:      v.ID,
+		Started: v.Status == glusterd1.StatusStarted,
+	}
+	fail := func(err error) api.Glusterd1VolumeImport {
+		result.State = api.Glusterd1ImportFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	if existing, err := volume.GetVolume(v.Name); err == nil {
+		if !uuid.Equal(existing.ID, v.ID) {
+			return fail(gderrors.ErrVolExists)
+		}
+		result.State = api.Glusterd1VolumeExists
+		return result
+	} else if err != gderrors.ErrVolNotFound {
+		return fail(err)
+	}
+
+	export, err := v.Export()
+	if err != nil {
+		return fail(err)
+	}
+	result.SkippedOptions = filterOptions(export, v)
+	export.CreatedAt = time.Now()
+	for sidx := range export.Subvols {
+		for bidx := range export.Subvols[sidx].Bricks {
+			b := &export.Subvols[sidx].Bricks[bidx]
+			if id := peers.peerOf(b); id != nil {
+				b.PeerID = id
+			}
+		}
+	}
+
+	volinfo, err := volume.NewVolinfoFromExport((*api.VolumeImportReq)(export))
+	if err != nil {
+		return fail(err)
+	}
+	volinfo.Auth = volume.VolAuth{
+		Username: v.Username,
+		Password: v.Password,
+	}
+
+	if dryRun {
+		if _, err := volumecommands.ValidateVolumeImport(volinfo); err != nil {
+			return fail(err)
+		}
+		result.State = api.Glusterd1VolumeImportable
+		return result
+	}
+
+	if _, err := volumecommands.ImportVolume(ctx, volinfo); err != nil {
+		return fail(err)
+	}
+	result.State = api.Glusterd1VolumeImported
+	return result
+}
+
+func glusterd1ImportHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.Glusterd1ImportReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+	if req.Workdir == "" {
+		req.Workdir = glusterd1.DefaultWorkdir
+	}
+
+	workdir, err := glusterd1.Read(req.Workdir)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest,
+			fmt.Errorf("failed to read glusterd1 configuration: %s", err))
+		return
+	}
+
+	resp := &api.Glusterd1ImportResp{
+		UUID:      workdir.UUID,
+		OpVersion: workdir.OpVersion,
+		DryRun:    req.DryRun,
+		Peers:     []api.Glusterd1PeerImport{},
+		Volumes:   []api.Glusterd1VolumeImport{},
+	}
+
+	// The peers are imported first, so that the bricks of the volumes can
+	// be mapped to them
+	peers := &peerMap{
+		byUUID:     map[string]uuid.UUID{workdir.UUID.String(): gdctx.MyUUID},
+		byHostname: make(map[string]uuid.UUID),
+	}
+	for i := range workdir.Peers {
+		p := &workdir.Peers[i]
+		result := importPeer(ctx, p, req.AddPeers && !req.DryRun)
+		if result.PeerID != nil {
+			peers.byUUID[p.UUID.String()] = result.PeerID
+			for _, hostname := range p.Hostnames {
+				peers.byHostname[hostname] = result.PeerID
+			}
+		}
+		resp.Peers = append(resp.Peers, result)
+	}
+
+	for i := range workdir.Volumes {
+		result := importVolume(ctx, &workdir.Volumes[i], peers, req.DryRun)
+		if result.State == api.Glusterd1ImportFailed {
+			logger.WithField("volume", result.Name).WithField("error", result.Error).Warn("failed to import glusterd1 volume")
+		}
+		resp.Volumes = append(resp.Volumes, result)
+	}
+
+	logger.WithField("workdir", req.Workdir).WithField("dry-run", req.DryRun).Info("glusterd1 configuration imported")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
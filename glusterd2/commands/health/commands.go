@@ -0,0 +1,33 @@
+// Package healthcommands implements the health check of a peer or of the
+// whole cluster, meant for load balancers and liveness probes
+package healthcommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "Health",
+			Method:       "GET",
+			Pattern:      "/health",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.HealthResp)(nil)),
+			HandlerFunc:  healthHandler,
+		},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	transaction.RegisterStepFunc(nodeHealthStep, "health.Check")
+}
@@ -0,0 +1,284 @@
+package healthcommands
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/plugins/glustershd"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+)
+
+const (
+	healthTxnKey      = "health"
+	storeProbeTimeout = 2 * time.Second
+
+	levelNode    = "node"
+	levelCluster = "cluster"
+)
+
+// healthRank orders the statuses from the best to the worst
+var healthRank = map[api.HealthStatus]int{
+	api.HealthOK:       0,
+	api.HealthDegraded: 1,
+	api.HealthFailed:   2,
+}
+
+// worst returns the worst of the given statuses
+func worst(statuses ...api.HealthStatus) api.HealthStatus {
+	w := api.HealthOK
+	for _, s := range statuses {
+		if healthRank[s] > healthRank[w] {
+			w = s
+		}
+	}
+	return w
+}
+
+func checkStore() api.StoreHealth {
+	ctx, cancel := context.WithTimeout(context.Background(), storeProbeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := store.Get(ctx, "peers/", clientv3.WithPrefix(), clientv3.WithCountOnly()); err != nil {
+		return api.StoreHealth{Status: api.HealthFailed, Error: err.Error()}
+	}
+	return api.StoreHealth{
+		Status:    api.HealthOK,
+		LatencyMs: int64(time.Since(start) / time.Millisecond),
+	}
+}
+
+func checkQuorum() api.QuorumHealth {
+	peers, err := peer.GetPeers()
+	if err != nil {
+		return api.QuorumHealth{Status: api.HealthFailed}
+	}
+
+	q := api.QuorumHealth{Peers: len(peers)}
+	for _, p := range peers {
+		if _, alive := store.Store.IsNodeAlive(p.ID); alive {
+			q.Online++
+		}
+	}
+	q.Status = quorumStatus(q.Peers, q.Online)
+	return q
+}
+
+// quorumStatus is failed if half or more of the peers are offline, and
+// degraded if any peer is offline
+func quorumStatus(peers, online int) api.HealthStatus {
+	switch {
+	case online*2 <= peers:
+		return api.HealthFailed
+	case online < peers:
+		return api.HealthDegraded
+	}
+	return api.HealthOK
+}
+
+func checkBricks(volumes []*volume.Volinfo) api.BricksHealth {
+	h := api.BricksHealth{
+		Status: api.HealthOK,
+		Bricks: []api.BrickHealth{},
+	}
+	for _, v := range volumes {
+		if v.State != volume.VolStarted {
+			continue
+		}
+		for _, b := range v.GetLocalBricks() {
+			s, err := volume.BrickStatus(b, nil)
+			online := err == nil && s.Online
+			if !online {
+				h.Status = api.HealthDegraded
+			}
+			h.Bricks = append(h.Bricks, api.BrickHealth{
+				Volume: v.Name,
+				Path:   b.Path,
+				Online: online,
+			})
+		}
+	}
+	return h
+}
+
+func isHealable(v *volume.Volinfo) bool {
+	switch v.Type {
+	case volume.Replicate, volume.Disperse, volume.DistReplicate, volume.DistDisperse:
+		return true
+	}
+	return false
+}
+
+func checkHeal(volumes []*volume.Volinfo) api.HealHealth {
+	h := api.HealHealth{
+		Status:  api.HealthOK,
+		Volumes: []api.VolumeHealHealth{},
+	}
+	for _, v := range volumes {
+		if v.State != volume.VolStarted || !isHealable(v) || len(v.GetLocalBricks()) == 0 {
+			continue
+		}
+
+		vh := api.VolumeHealHealth{Volume: v.Name}
+		pending, err := glustershd.PendingHealEntries(v.Name)
+		if err != nil {
+			vh.Pending = -1
+			vh.Error = err.Error()
+		} else {
+			vh.Pending = pending
+		}
+		if vh.Pending != 0 {
+			h.Status = api.HealthDegraded
+		}
+		h.Volumes = append(h.Volumes, vh)
+	}
+	return h
+}
+
+// checkNode checks the health of the components of this peer. The rest of
+// the checks need the store, and are skipped if it doesn't answer.
+func checkNode() api.NodeHealth {
+	n := api.NodeHealth{
+		PeerID: gdctx.MyUUID,
+		Store:  checkStore(),
+	}
+	if n.Store.Status == api.HealthFailed {
+		n.Status = api.HealthFailed
+		return n
+	}
+
+	n.Quorum = checkQuorum()
+
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		n.Status = api.HealthFailed
+		n.Error = err.Error()
+		return n
+	}
+	n.Bricks = checkBricks(volumes)
+	n.Heal = checkHeal(volumes)
+
+	n.Status = worst(n.Store.Status, n.Quorum.Status, n.Bricks.Status, n.Heal.Status)
+	return n
+}
+
+func nodeHealthStep(c transaction.TxnCtx) error {
+	n := checkNode()
+	return c.SetNodeResult(gdctx.MyUUID, healthTxnKey, &n)
+}
+
+// checkCluster collects the health of all the peers. Peers which are down
+// or don't answer are reported as failed, which degrades the cluster.
+func checkCluster(ctx context.Context, local api.NodeHealth) ([]api.NodeHealth, error) {
+	peerIDs, err := peer.GetPeerIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	// This peer has already been checked
+	var others []uuid.UUID
+	for _, id := range peerIDs {
+		if !uuid.Equal(id, gdctx.MyUUID) {
+			others = append(others, id)
+		}
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+
+	if len(others) > 0 {
+		txn.Steps = []*transaction.Step{
+			{
+				DoFunc: "health.Check",
+				Nodes:  others,
+			},
+		}
+		txn.DontCheckAlive = true
+		txn.DisableRollback = true
+
+		if err := txn.Do(); err != nil {
+			gdctx.GetReqLogger(ctx).WithError(err).Warn("failed to collect health from all peers")
+		}
+	}
+
+	nodes := make([]api.NodeHealth, 0, len(peerIDs))
+	for _, id := range peerIDs {
+		if uuid.Equal(id, gdctx.MyUUID) {
+			nodes = append(nodes, local)
+			continue
+		}
+		var n api.NodeHealth
+		if err := txn.Ctx.GetNodeResult(id, healthTxnKey, &n); err != nil {
+			n = api.NodeHealth{
+				PeerID: id,
+				Status: api.HealthFailed,
+				Error:  "failed to get health from peer",
+			}
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
+
+// clusterStatus is the worst status of the peers, except that peers which
+// have failed only degrade the cluster as long as it has quorum
+func clusterStatus(local api.NodeHealth, nodes []api.NodeHealth) api.HealthStatus {
+	status := local.Status
+	for _, n := range nodes {
+		s := n.Status
+		if s == api.HealthFailed {
+			s = api.HealthDegraded
+		}
+		status = worst(status, s)
+	}
+	return status
+}
+
+func healthHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	level := r.URL.Query().Get("level")
+	if level == "" {
+		level = levelNode
+	}
+	if level != levelNode && level != levelCluster {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrInvalidHealthLevel)
+		return
+	}
+
+	local := checkNode()
+	resp := &api.HealthResp{
+		Status: local.Status,
+		Level:  level,
+		Nodes:  []api.NodeHealth{local},
+	}
+
+	// The cluster can't be checked without the store
+	if level == levelCluster && local.Store.Status != api.HealthFailed {
+		nodes, err := checkCluster(ctx, local)
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		resp.Nodes = nodes
+		resp.Status = clusterStatus(local, nodes)
+	}
+
+	// Load balancers and probes only look at the status code
+	status := http.StatusOK
+	if resp.Status == api.HealthFailed {
+		status = http.StatusServiceUnavailable
+	}
+	restutils.SendHTTPResponse(ctx, w, status, resp)
+}
@@ -0,0 +1,38 @@
+package healthcommands
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuorumStatus(t *testing.T) {
+	assert.Equal(t, api.HealthOK, quorumStatus(1, 1))
+	assert.Equal(t, api.HealthOK, quorumStatus(3, 3))
+	assert.Equal(t, api.HealthDegraded, quorumStatus(3, 2))
+	assert.Equal(t, api.HealthFailed, quorumStatus(3, 1))
+	assert.Equal(t, api.HealthFailed, quorumStatus(4, 2))
+	assert.Equal(t, api.HealthFailed, quorumStatus(0, 0))
+}
+
+func TestWorst(t *testing.T) {
+	assert.Equal(t, api.HealthOK, worst())
+	assert.Equal(t, api.HealthOK, worst(api.HealthOK, api.HealthOK))
+	assert.Equal(t, api.HealthDegraded, worst(api.HealthOK, api.HealthDegraded))
+	assert.Equal(t, api.HealthFailed, worst(api.HealthFailed, api.HealthDegraded, api.HealthOK))
+}
+
+func TestClusterStatus(t *testing.T) {
+	local := api.NodeHealth{Status: api.HealthOK}
+	nodes := []api.NodeHealth{local, {Status: api.HealthOK}}
+	assert.Equal(t, api.HealthOK, clusterStatus(local, nodes))
+
+	// a failed peer only degrades the cluster
+	nodes = append(nodes, api.NodeHealth{Status: api.HealthFailed})
+	assert.Equal(t, api.HealthDegraded, clusterStatus(local, nodes))
+
+	local.Status = api.HealthFailed
+	assert.Equal(t, api.HealthFailed, clusterStatus(local, nodes))
+}
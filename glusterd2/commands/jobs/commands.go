@@ -0,0 +1,47 @@
+// Package jobcommands implements the commands to follow and cancel the long
+// running operations run in the background as jobs
+package jobcommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "GetJobs",
+			Method:       "GET",
+			Pattern:      "/jobs",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.JobListResp)(nil)),
+			HandlerFunc:  getJobsHandler,
+		},
+		route.Route{
+			Name:         "GetJob",
+			Method:       "GET",
+			Pattern:      "/jobs/{jobid}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.JobGetResp)(nil)),
+			HandlerFunc:  getJobHandler,
+		},
+		route.Route{
+			Name:        "CancelJob",
+			Method:      "POST",
+			Pattern:     "/jobs/{jobid}/cancel",
+			Version:     1,
+			HandlerFunc: cancelJobHandler,
+		},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
@@ -0,0 +1,92 @@
+package jobcommands
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gluster/glusterd2/glusterd2/jobs"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+// getJobsHandler lists the jobs, optionally filtered by the volume, type
+// and state given as query parameters. Jobs are listed newest first.
+func getJobsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	page, err := restutils.GetPage(r)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	all, err := jobs.GetJobs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := make(api.JobListResp, 0, len(all))
+	for _, j := range all {
+		if v := query.Get("volume"); v != "" && v != j.Volume {
+			continue
+		}
+		if t := query.Get("type"); t != "" && t != j.Type {
+			continue
+		}
+		if s := query.Get("state"); s != "" && api.JobState(s) != j.State {
+			continue
+		}
+		resp = append(resp, *jobs.CreateJobInfoResp(j))
+	}
+
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].CreatedAt.After(resp[j].CreatedAt)
+	})
+	start, end := page.Apply(w, r, len(resp), func(i int) string { return resp[i].ID.String() })
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp[start:end])
+}
+
+func getJobHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := uuid.Parse(mux.Vars(r)["jobid"])
+	if id == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Invalid job id passed")
+		return
+	}
+
+	j, err := jobs.GetJob(id)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := (*api.JobGetResp)(jobs.CreateJobInfoResp(j))
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func cancelJobHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := uuid.Parse(mux.Vars(r)["jobid"])
+	if id == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Invalid job id passed")
+		return
+	}
+
+	if err := jobs.Cancel(id); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	// A running job is stopped asynchronously by the peer running it
+	restutils.SendHTTPResponse(ctx, w, http.StatusAccepted, nil)
+}
@@ -0,0 +1,42 @@
+// Package maintenancecommands implements the commands to put the cluster in
+// maintenance mode and take it out of it
+package maintenancecommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "GetMaintenance",
+			Method:       "GET",
+			Pattern:      "/maintenance",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.MaintenanceResp)(nil)),
+			HandlerFunc:  getMaintenanceHandler,
+		},
+		route.Route{
+			Name:         "SetMaintenance",
+			Method:       "POST",
+			Pattern:      "/maintenance",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.MaintenanceReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.MaintenanceResp)(nil)),
+			HandlerFunc:  setMaintenanceHandler,
+			Role:         api.RoleAdmin,
+		},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
@@ -0,0 +1,50 @@
+package maintenancecommands
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/maintenance"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+)
+
+func getMaintenanceHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	m, err := maintenance.Get(ctx)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, maintenance.CreateMaintenanceResp(m))
+}
+
+func setMaintenanceHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.MaintenanceReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	m := &maintenance.Mode{Enabled: req.Enabled}
+	if req.Enabled {
+		m.Reason = req.Reason
+		m.User = gdctx.GetReqUser(ctx)
+		m.Since = time.Now().UTC()
+	}
+
+	if err := maintenance.Set(m); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	logger.WithField("enabled", m.Enabled).WithField("reason", m.Reason).Info("maintenance mode set")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, maintenance.CreateMaintenanceResp(m))
+}
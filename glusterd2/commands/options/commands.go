@@ -3,6 +3,8 @@ package optionscommands
 
 import (
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
 )
 
 // Command is a holding struct used to implement the GlusterD Command interface
@@ -26,6 +28,32 @@ func (c *Command) Routes() route.Routes {
 			Version:     1,
 			HandlerFunc: getClusterOptionsHandler,
 		},
+		route.Route{
+			Name:        "ResetClusterOptions",
+			Method:      "DELETE",
+			Pattern:     "/cluster/options",
+			Version:     1,
+			RequestType: utils.GetTypeString((*api.ClusterOptionResetReq)(nil)),
+			HandlerFunc: resetClusterOptionsHandler,
+		},
+		route.Route{
+			Name:         "GetClusterOpVersion",
+			Method:       "GET",
+			Pattern:      "/cluster/op-version",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.ClusterOpVersionResp)(nil)),
+			HandlerFunc:  getClusterOpVersionHandler,
+		},
+		route.Route{
+			Name:         "SetClusterOpVersion",
+			Method:       "POST",
+			Pattern:      "/cluster/op-version",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.ClusterOpVersionReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.ClusterOpVersionResp)(nil)),
+			HandlerFunc:  setClusterOpVersionHandler,
+			Role:         api.RoleAdmin,
+		},
 	}
 }
 
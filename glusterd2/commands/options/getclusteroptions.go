@@ -2,8 +2,10 @@ package optionscommands
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/opversion"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/errors"
@@ -19,6 +21,20 @@ func getClusterOptionsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := createClusterOptionsResp(c)
+
+	// The highest op-version the cluster can be raised to depends on the
+	// peers, and is not stored
+	max, err := opversion.Max()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	for i := range resp {
+		if resp[i].Key == maxOpVersionKey {
+			resp[i].Value = strconv.Itoa(max)
+		}
+	}
+
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
 }
 
@@ -40,9 +56,10 @@ func createClusterOptionsResp(c *options.ClusterOptions) []api.ClusterOptionsRes
 
 		resp = append(resp, api.ClusterOptionsResp{
 			Key:          k,
-			Value:        val,
+			Value:        options.RedactClusterOption(k, val),
 			DefaultValue: v.DefaultValue,
 			Modified:     found,
+			Description:  v.Description,
 		})
 	}
 
@@ -0,0 +1,91 @@
+package optionscommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/opversion"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+)
+
+func getClusterOpVersionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resp, err := createClusterOpVersionResp()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func setClusterOpVersionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.ClusterOpVersionReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	target := req.OpVersion
+	if target == 0 {
+		max, err := opversion.Max()
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		target = max
+	}
+
+	if err := opversion.Set(ctx, target); err != nil {
+		switch err {
+		case errors.ErrOpVersionLowered, errors.ErrOpVersionNotSupported:
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		default:
+			status, err := restutils.ErrToStatusCode(err)
+			restutils.SendHTTPError(ctx, w, status, err)
+		}
+		return
+	}
+	logger.WithField("op-version", target).Info("op-version of the cluster raised")
+
+	resp, err := createClusterOpVersionResp()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func createClusterOpVersionResp() (*api.ClusterOpVersionResp, error) {
+	current, err := opversion.Get()
+	if err != nil {
+		return nil, err
+	}
+	max, err := opversion.Max()
+	if err != nil {
+		return nil, err
+	}
+	peers, err := peer.GetPeers()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.ClusterOpVersionResp{
+		OpVersion:    current,
+		MaxOpVersion: max,
+	}
+	for _, p := range peers {
+		resp.Peers = append(resp.Peers, api.PeerOpVersion{
+			ID:        p.ID,
+			Name:      p.Name,
+			OpVersion: p.GetOpVersion(),
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,76 @@
+package optionscommands
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+)
+
+const (
+	// opVersionKey is never reset, as the op-version of a cluster can not
+	// be lowered
+	opVersionKey    = "cluster.op-version"
+	maxOpVersionKey = "cluster.max-op-version"
+)
+
+func resetClusterOptionsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req api.ClusterOptionResetReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	for _, k := range req.Options {
+		if _, found := options.ClusterOptMap[k]; !found {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, fmt.Sprintf("Invalid global option: %s", k))
+			return
+		}
+		if k == opVersionKey {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, fmt.Sprintf("%s can not be reset", k))
+			return
+		}
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, options.ClusterOptionsLockID)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	c, err := options.GetClusterOptions()
+	if err == errors.ErrClusterOptionsNotFound {
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, map[string]string{})
+		return
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if req.All {
+		for k := range c.Options {
+			if k != opVersionKey {
+				delete(c.Options, k)
+			}
+		}
+	}
+	for _, k := range req.Options {
+		delete(c.Options, k)
+	}
+
+	if err := options.UpdateClusterOptions(c); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError,
+			fmt.Sprintf("Failed to update store with cluster attributes %s", err.Error()))
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, c.Options)
+}
@@ -11,10 +11,6 @@ import (
 	"github.com/gluster/glusterd2/pkg/errors"
 )
 
-const (
-	lockKey = "clusteroptions"
-)
-
 func setClusterOptionsHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -24,7 +20,7 @@ func setClusterOptionsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	txn, err := transaction.NewTxnWithLocks(ctx, lockKey)
+	txn, err := transaction.NewTxnWithLocks(ctx, options.ClusterOptionsLockID)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
 		restutils.SendHTTPError(ctx, w, status, err)
@@ -43,18 +39,20 @@ func setClusterOptionsHandler(w http.ResponseWriter, r *http.Request) {
 		c.Options = make(map[string]string)
 	}
 
+	if c.Options == nil {
+		c.Options = make(map[string]string)
+	}
+
 	for k, v := range req.Options {
 		if opt, found := options.ClusterOptMap[k]; found {
-			if opt.ValidateFunc != nil {
-				if err := opt.ValidateFunc(k, v); err != nil {
-					restutils.SendHTTPError(ctx, w, http.StatusBadRequest,
-						fmt.Sprintf("%s failed validation: %s", k, err))
-					return
-				}
+			if err := opt.Validate(v); err != nil {
+				restutils.SendHTTPError(ctx, w, http.StatusBadRequest,
+					fmt.Sprintf("%s failed validation: %s", k, err))
+				return
 			}
 			c.Options[k] = v
 		} else {
-			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, fmt.Sprintf("Invalid global option: %s", k))
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, fmt.Sprintf("Invalid global option: %s", k))
 			return
 		}
 	}
@@ -1,6 +1,7 @@
 package peercommands
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"sort"
@@ -23,7 +24,6 @@ const (
 func addPeerHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
 
 	var req api.PeerAddReq
 	if err := restutils.UnmarshalRequest(r, &req); err != nil {
@@ -31,28 +31,48 @@ func addPeerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.MetadataSize() > maxMetadataSizeLimit {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrMetadataSizeOutOfBounds)
+	newpeer, status, err := AddPeer(ctx, &req)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
 
+	resp := createPeerAddResp(newpeer)
+	restutils.SetLocationHeader(r, w, newpeer.ID.String())
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
+}
+
+// AddPeer asks the peer at the first of the addresses in the request to join
+// the cluster, and returns the new peer, or the HTTP status code and error of
+// the failure
+func AddPeer(ctx context.Context, req *api.PeerAddReq) (*peer.Peer, int, error) {
+	logger := gdctx.GetReqLogger(ctx)
+
+	if req.MetadataSize() > maxMetadataSizeLimit {
+		return nil, http.StatusBadRequest, errors.ErrMetadataSizeOutOfBounds
+	}
+
 	for key := range req.Metadata {
 		if strings.HasPrefix(key, "_") {
-			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrRestrictedKeyFound)
-			return
+			return nil, http.StatusBadRequest, errors.ErrRestrictedKeyFound
 		}
 	}
 
+	if err := validatePlacement(req.Zone, req.Rack, req.Tags); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if len(req.Tags) > maxTags {
+		return nil, http.StatusBadRequest, fmt.Errorf("a peer can have at most %d tags", maxTags)
+	}
+
 	if len(req.Addresses) < 1 {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrNoHostnamesPresent)
-		return
+		return nil, http.StatusBadRequest, errors.ErrNoHostnamesPresent
 	}
 	logger.WithField("addresses", req.Addresses).Debug("received request to add new peer with given addresses")
 
 	p, _ := peer.GetPeerByAddrs(req.Addresses)
 	if p != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusConflict, fmt.Sprintf("Peer exists with given addresses (ID: %s)", p.ID.String()))
-		return
+		return nil, http.StatusConflict, fmt.Errorf("peer exists with given addresses (ID: %s)", p.ID.String())
 	}
 
 	// A peer can have multiple addresses. For now, we use only the first
@@ -60,21 +80,18 @@ func addPeerHandler(w http.ResponseWriter, r *http.Request) {
 	remotePeerAddress, err := utils.FormRemotePeerAddress(req.Addresses[0])
 	if err != nil {
 		logger.WithError(err).WithField("address", req.Addresses[0]).Error("failed to parse peer address")
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "failed to parse remote address")
-		return
+		return nil, http.StatusBadRequest, fmt.Errorf("failed to parse remote address")
 	}
 
 	if err = utils.CheckPeerConnectivity(remotePeerAddress); err != nil {
 		logger.WithError(err).WithField("address", remotePeerAddress).Error("peer is not reachable from this node")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, errors.ErrConnectingHost)
-		return
+		return nil, http.StatusInternalServerError, errors.ErrConnectingHost
 	}
 
 	// TODO: Try all addresses till the first one connects
 	client, err := getPeerServiceClient(remotePeerAddress)
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 	defer client.conn.Close()
 	logger = logger.WithField("peer", remotePeerAddress)
@@ -86,17 +103,14 @@ func addPeerHandler(w http.ResponseWriter, r *http.Request) {
 	rsp, err := client.JoinCluster(newconfig)
 	if err != nil {
 		logger.WithError(err).Error("sending Join request failed")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "failed to send join cluster request")
-		return
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to send join cluster request")
 	} else if Error(rsp.Err) != ErrNone {
 		err = Error(rsp.Err)
 		logger.WithError(err).Error("join request failed")
 		if rsp.Err == int32(ErrAnotherReqInProgress) {
-			restutils.SendHTTPError(ctx, w, http.StatusConflict, err)
-		} else {
-			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return nil, http.StatusConflict, err
 		}
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 	logger = logger.WithField("peerid", rsp.PeerID)
 	logger.Info("new peer joined our cluster")
@@ -105,13 +119,14 @@ func addPeerHandler(w http.ResponseWriter, r *http.Request) {
 	newpeer, err := peer.GetPeer(rsp.PeerID)
 	if err != nil {
 		logger.WithError(err).Error("failed to get peer information")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "new peer was added, but could not find peer in store. Try again later.")
-		return
+		return nil, http.StatusInternalServerError, fmt.Errorf("new peer was added, but could not find peer in store, try again later")
 	}
 
 	if req.Zone != "" {
-		newpeer.Metadata["_zone"] = req.Zone
+		newpeer.SetZone(req.Zone)
 	}
+	newpeer.Rack = req.Rack
+	newpeer.Tags = req.Tags
 
 	for key, value := range req.Metadata {
 		newpeer.Metadata[key] = value
@@ -130,17 +145,15 @@ func addPeerHandler(w http.ResponseWriter, r *http.Request) {
 
 	err = peer.AddOrUpdatePeer(newpeer)
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "Fail to add metadata to peer")
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to add metadata to peer")
 	}
 
-	resp := createPeerAddResp(newpeer)
-	restutils.SetLocationHeader(r, w, newpeer.ID.String())
-	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
-
 	// Save updated store endpoints for restarts
 	store.Store.UpdateEndpoints()
 
 	events.Broadcast(newPeerEvent(eventPeerAdded, newpeer))
+
+	return newpeer, http.StatusCreated, nil
 }
 
 func createPeerAddResp(p *peer.Peer) *api.PeerAddResp {
@@ -151,5 +164,9 @@ func createPeerAddResp(p *peer.Peer) *api.PeerAddResp {
 		PeerAddresses:   p.PeerAddresses,
 		ClientAddresses: p.ClientAddresses,
 		Metadata:        p.Metadata,
+		Zone:            p.GetZone(),
+		Rack:            p.Rack,
+		Tags:            p.Tags,
+		OpVersion:       p.GetOpVersion(),
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/utils"
+	"github.com/gluster/glusterd2/version"
 )
 
 // Command is a holding struct used to implement the GlusterD Command interface
@@ -55,6 +56,41 @@ func (c *Command) Routes() route.Routes {
 			ResponseType: utils.GetTypeString((*api.PeerEditResp)(nil)),
 			HandlerFunc:  editPeer,
 		},
+		route.Route{
+			Name:         "PatchPeer",
+			Method:       "PATCH",
+			Pattern:      "/peers/{peerid}",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.PeerPatchReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.PeerPatchResp)(nil)),
+			HandlerFunc:  patchPeerHandler,
+			OpVersion:    version.OpVersionPeerPlacement,
+		},
+		route.Route{
+			Name:        "PeerReplace",
+			Method:      "POST",
+			Pattern:     "/peers/{peerid}/replace",
+			Version:     1,
+			RequestType: utils.GetTypeString((*api.PeerReplaceReq)(nil)),
+			HandlerFunc: peerReplaceHandler,
+		},
+		route.Route{
+			Name:         "PeerMaintenanceStart",
+			Method:       "POST",
+			Pattern:      "/peers/{peerid}/maintenance",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.PeerMaintenanceReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.PeerGetResp)(nil)),
+			HandlerFunc:  peerMaintenanceStartHandler,
+		},
+		route.Route{
+			Name:         "PeerMaintenanceStop",
+			Method:       "DELETE",
+			Pattern:      "/peers/{peerid}/maintenance",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.PeerGetResp)(nil)),
+			HandlerFunc:  peerMaintenanceStopHandler,
+		},
 	}
 }
 
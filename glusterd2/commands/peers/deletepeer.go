@@ -2,14 +2,20 @@ package peercommands
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/jobs"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/store"
-	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/utils"
 
 	"github.com/gorilla/mux"
@@ -27,9 +33,12 @@ func deletePeerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	migrate := r.URL.Query().Get("migrate-bricks") == "true"
+
 	// Deleting a peer from the cluster happens as follows,
 	// 	- Check if the peer is a member of the cluster
 	// 	- Check if the peer can be removed
+	// 	- Move the bricks of the peer to other peers, if asked to
 	//	- Delete the peer info from the store
 	//	- Send the Leave request
 
@@ -60,27 +69,90 @@ func deletePeerHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check if any volumes exist with bricks on this peer
-	if exists, err := bricksExist(id); err != nil {
+	bricks, err := PeerBricks(ctx, p.ID)
+	if err != nil {
 		logger.WithError(err).Error("failed to check if bricks exist on peer")
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "could not validate delete request")
 		return
-	} else if exists {
+	}
+
+	if len(bricks) == 0 {
+		if status, err := DetachPeer(ctx, p); err != nil {
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+		restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+		return
+	}
+
+	if !migrate {
+		seen := make(map[string]bool)
+		var volumes []string
+		for _, b := range bricks {
+			if !seen[b.VolumeName] {
+				seen[b.VolumeName] = true
+				volumes = append(volumes, b.VolumeName)
+			}
+		}
+		sort.Strings(volumes)
 		logger.Debug("request denied, peer has bricks")
-		restutils.SendHTTPError(ctx, w, http.StatusForbidden, "cannot delete peer, peer has bricks")
+		restutils.SendHTTPError(ctx, w, http.StatusForbidden,
+			fmt.Sprintf("cannot delete peer, peer has bricks of volumes: %s", strings.Join(volumes, ", ")))
+		return
+	}
+
+	if err := checkPeerBricksMigratable(p.ID, bricks); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, err)
+		return
+	}
+
+	// Keep the bricks planner from placing new bricks on the peer while its
+	// bricks are moved away
+	if !p.InMaintenance() {
+		if p.Metadata == nil {
+			p.Metadata = make(map[string]string)
+		}
+		p.Metadata[peer.MaintenanceKey] = "detaching"
+		p.Metadata[peer.MaintenanceSinceKey] = time.Now().UTC().Format(time.RFC3339)
+		if err := peer.AddOrUpdatePeer(p); err != nil {
+			logger.WithError(err).Error("failed to put peer under maintenance")
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		events.Broadcast(newPeerEvent(eventPeerMaintenanceStarted, p))
+	}
+
+	job, err := jobs.Submit(ctx, api.JobTypePeerDetach, "", func(ctx context.Context, h *jobs.Handle) error {
+		if err := migratePeerBricks(ctx, h, p.ID, bricks); err != nil {
+			return err
+		}
+		if _, err := DetachPeer(ctx, p); err != nil {
+			return err
+		}
+		h.Logf("peer %s removed from the cluster", p.ID.String())
+		return nil
+	})
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
 		return
 	}
+	restutils.SendJobAccepted(ctx, w, jobs.CreateJobInfoResp(job))
+}
+
+// DetachPeer asks the peer to leave the cluster and removes it from the
+// store. It returns the HTTP status to respond with on failure.
+func DetachPeer(ctx context.Context, p *peer.Peer) (int, error) {
+	logger := gdctx.GetReqLogger(ctx).WithField("peerid", p.ID.String())
 
 	remotePeerAddress, err := utils.FormRemotePeerAddress(p.PeerAddresses[0])
 	if err != nil {
 		logger.WithError(err).WithField("address", p.PeerAddresses[0]).Error("failed to parse peer address")
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "failed to parse remote address")
-		return
+		return http.StatusBadRequest, errors.New("failed to parse remote address")
 	}
 
 	client, err := getPeerServiceClient(remotePeerAddress)
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return http.StatusInternalServerError, err
 	}
 	defer client.conn.Close()
 
@@ -90,51 +162,26 @@ func deletePeerHandler(w http.ResponseWriter, r *http.Request) {
 	rsp, err := client.LeaveCluster()
 	if err != nil {
 		logger.WithError(err).Error("client.LeaveCluster() failed")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return http.StatusInternalServerError, err
 	} else if Error(rsp.Err) != ErrNone {
 		err = Error(rsp.Err)
 		logger.WithError(err).Error("leave request failed")
 		if rsp.Err == int32(ErrAnotherReqInProgress) {
-			restutils.SendHTTPError(ctx, w, http.StatusConflict, err)
-		} else {
-			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return http.StatusConflict, err
 		}
-		return
+		return http.StatusInternalServerError, err
 	}
 	logger.Debug("peer left cluster")
 
 	// Remove the peer details from the store
-	if err := peer.DeletePeer(id); err != nil {
-		logger.WithError(err).WithField("peer", id).Error("failed to remove peer from the store")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+	if err := peer.DeletePeer(p.ID.String()); err != nil {
+		logger.WithError(err).Error("failed to remove peer from the store")
+		return http.StatusInternalServerError, err
 	}
 
-	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
-
 	// Save updated store endpoints for restarts
 	store.Store.UpdateEndpoints()
 
 	events.Broadcast(newPeerEvent(eventPeerRemoved, p))
-}
-
-// bricksExist checks if the given peer has any bricks on it
-// TODO: Move this to a more appropriate place
-func bricksExist(id string) (bool, error) {
-	pid := uuid.Parse(id)
-
-	vols, err := volume.GetVolumes(context.TODO())
-	if err != nil {
-		return true, err
-	}
-
-	for _, v := range vols {
-		for _, b := range v.GetBricks() {
-			if uuid.Equal(pid, b.PeerID) {
-				return true, nil
-			}
-		}
-	}
-	return false, nil
+	return http.StatusOK, nil
 }
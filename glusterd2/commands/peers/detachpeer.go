@@ -0,0 +1,174 @@
+package peercommands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/commands/volumes"
+	"github.com/gluster/glusterd2/glusterd2/jobs"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+)
+
+// removeBrickPollInterval is how often the data migration of the bricks
+// removed from distribute volumes is checked
+const removeBrickPollInterval = 30 * time.Second
+
+// checkPeerBricksMigratable checks that the bricks of the peer can be moved to
+// other peers. Bricks of replicate and disperse volumes are replaced by bricks
+// chosen by the bricks planner, with self-heal migrating their data, which
+// needs the volumes to be auto provisioned. Bricks of distribute volumes are
+// removed, with rebalance migrating their data to the other bricks of the
+// volume.
+func checkPeerBricksMigratable(peerID uuid.UUID, bricks []brick.Brickinfo) error {
+	checked := make(map[string]bool)
+	for _, b := range bricks {
+		if checked[b.VolumeName] {
+			continue
+		}
+		checked[b.VolumeName] = true
+
+		v, err := volume.GetVolume(b.VolumeName)
+		if err != nil {
+			return err
+		}
+		if v.State != volume.VolStarted {
+			return fmt.Errorf("bricks of volume %s can not be migrated: volume is not started", v.Name)
+		}
+
+		switch v.Type {
+		case volume.Replicate, volume.DistReplicate, volume.Disperse, volume.DistDisperse:
+			if !v.IsAutoProvisioned() {
+				return fmt.Errorf("bricks of volume %s can not be migrated: volume is not auto provisioned", v.Name)
+			}
+		case volume.Distribute:
+			if len(v.DecommissionedBricks()) != 0 {
+				return fmt.Errorf("bricks of volume %s can not be migrated: a remove-brick is in progress", v.Name)
+			}
+			remaining := 0
+			for _, vb := range v.GetBricks() {
+				if !uuid.Equal(vb.PeerID, peerID) {
+					remaining++
+				}
+			}
+			if remaining == 0 {
+				return fmt.Errorf("bricks of volume %s can not be migrated: volume has no bricks on other peers", v.Name)
+			}
+		default:
+			return fmt.Errorf("bricks of volume %s can not be migrated", v.Name)
+		}
+	}
+	return nil
+}
+
+// removeDistributeBricks removes the bricks from the distribute volume once
+// rebalance has migrated their data to the other bricks of the volume
+func removeDistributeBricks(ctx context.Context, h *jobs.Handle, volname string, bricks []brick.Brickinfo) error {
+	req := api.RemoveBrickReq{}
+	for _, b := range bricks {
+		req.Bricks = append(req.Bricks, api.BrickReq{
+			PeerID: b.PeerID.String(),
+			Path:   b.Path,
+		})
+	}
+
+	if _, _, _, err := volumecommands.RemoveBrickStart(ctx, volname, req); err != nil {
+		return fmt.Errorf("failed to start removing bricks of volume %s: %s", volname, err)
+	}
+	h.Logf("migrating data off the bricks of volume %s", volname)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(removeBrickPollInterval):
+		}
+
+		status, err := volumecommands.RemoveBrickStatus(volname)
+		if err != nil {
+			return fmt.Errorf("failed to get remove-brick status of volume %s: %s", volname, err)
+		}
+
+		switch status {
+		case volumecommands.RemoveBrickInProgress:
+			continue
+		case volumecommands.RemoveBrickCompleted:
+		default:
+			return fmt.Errorf("data migration off the bricks of volume %s %s", volname, status)
+		}
+
+		if _, _, err := volumecommands.RemoveBrickCommit(ctx, volname, api.RemoveBrickCommitReq{}); err != nil {
+			return fmt.Errorf("failed to commit removing bricks of volume %s: %s", volname, err)
+		}
+		h.Logf("bricks of volume %s removed", volname)
+		return nil
+	}
+}
+
+// migratePeerBricks moves the bricks of the peer to other peers and waits
+// for their data to be migrated
+func migratePeerBricks(ctx context.Context, h *jobs.Handle, peerID uuid.UUID, bricks []brick.Brickinfo) error {
+	var retireJobs []*jobs.Job
+	distBricks := make(map[string][]brick.Brickinfo)
+
+	for _, b := range bricks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		v, err := volume.GetVolume(b.VolumeName)
+		if err != nil {
+			return err
+		}
+		if v.Type == volume.Distribute {
+			distBricks[v.Name] = append(distBricks[v.Name], b)
+			continue
+		}
+
+		req := api.ReplaceBrickReq{
+			SrcPeerID:    b.PeerID.String(),
+			SrcBrickPath: b.Path,
+			ExcludePeers: []string{peerID.String()},
+		}
+		_, job, _, err := volumecommands.ReplaceBrickWithRetireJob(ctx, b.VolumeName, req)
+		if err != nil {
+			return fmt.Errorf("failed to migrate brick %s: %s", b.String(), err)
+		}
+		h.Logf("brick %s of volume %s replaced", b.String(), b.VolumeName)
+		if job != nil {
+			retireJobs = append(retireJobs, job)
+		}
+	}
+
+	for volname, vbricks := range distBricks {
+		if err := removeDistributeBricks(ctx, h, volname, vbricks); err != nil {
+			return err
+		}
+	}
+
+	// The replaced bricks are retired once self-heal has copied their data
+	// on to the new bricks, which needs the peer to still be around
+	h.Logf("waiting for the data of the replaced bricks to be healed")
+	for _, job := range retireJobs {
+		j, err := jobs.Wait(ctx, job.ID)
+		if err != nil {
+			return err
+		}
+		if j.State == api.JobFailed {
+			return fmt.Errorf("failed to retire replaced brick: %s", j.Error)
+		}
+	}
+
+	remaining, err := PeerBricks(ctx, peerID)
+	if err != nil {
+		return err
+	}
+	if len(remaining) != 0 {
+		return fmt.Errorf("peer still has %d bricks", len(remaining))
+	}
+	return nil
+}
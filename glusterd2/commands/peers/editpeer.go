@@ -41,6 +41,11 @@ func editPeer(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if err := validatePlacement(req.Zone, "", nil); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
 	txn, err := transaction.NewTxnWithLocks(ctx, peerID)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
@@ -116,7 +121,7 @@ func txnPeerEdit(c transaction.TxnCtx) error {
 	}
 
 	if req.Zone != "" {
-		peerInfo.Metadata["_zone"] = req.Zone
+		peerInfo.SetZone(req.Zone)
 	}
 	err = peer.AddOrUpdatePeer(peerInfo)
 	if err != nil {
@@ -150,5 +155,9 @@ func createPeerEditResp(p *peer.Peer) *api.PeerEditResp {
 		PeerAddresses:   p.PeerAddresses,
 		ClientAddresses: p.ClientAddresses,
 		Metadata:        p.Metadata,
+		Zone:            p.GetZone(),
+		Rack:            p.Rack,
+		Tags:            p.Tags,
+		OpVersion:       p.GetOpVersion(),
 	}
 }
@@ -9,8 +9,10 @@ import (
 type peerEvent string
 
 const (
-	eventPeerAdded   peerEvent = "peer.added"
-	eventPeerRemoved           = "peer.removed"
+	eventPeerAdded              peerEvent = "peer.added"
+	eventPeerRemoved                      = "peer.removed"
+	eventPeerMaintenanceStarted           = "peer.maintenancestarted"
+	eventPeerMaintenanceStopped           = "peer.maintenancestopped"
 )
 
 func newPeerEvent(e peerEvent, p *peer.Peer) *api.Event {
@@ -19,5 +21,29 @@ func newPeerEvent(e peerEvent, p *peer.Peer) *api.Event {
 		"peer.name": p.Name,
 	}
 
-	return events.New(string(e), data, true)
+	return events.New(string(e), data, true, api.EventObject{
+		Kind: api.EventObjectPeer,
+		ID:   p.ID.String(),
+		Name: p.Name,
+	})
+}
+
+func init() {
+	types := []struct {
+		name        peerEvent
+		description string
+	}{
+		{eventPeerAdded, "A peer was added to the cluster"},
+		{eventPeerRemoved, "A peer was removed from the cluster"},
+		{eventPeerMaintenanceStarted, "A peer was put in maintenance"},
+		{eventPeerMaintenanceStopped, "A peer was taken out of maintenance"},
+	}
+	for _, t := range types {
+		events.RegisterTypes(api.EventType{
+			Name:        string(t.name),
+			Description: t.description,
+			Objects:     []string{api.EventObjectPeer},
+			Data:        []string{"peer.id", "peer.name"},
+		})
+	}
 }
@@ -41,5 +41,9 @@ func createPeerGetResp(p *peer.Peer) *api.PeerGetResp {
 		Online:          online,
 		PID:             pid,
 		Metadata:        p.Metadata,
+		Zone:            p.GetZone(),
+		Rack:            p.Rack,
+		Tags:            p.Tags,
+		OpVersion:       p.GetOpVersion(),
 	}
 }
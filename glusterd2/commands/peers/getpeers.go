@@ -21,6 +21,12 @@ func getPeersHandler(w http.ResponseWriter, r *http.Request) {
 	if valueFound {
 		filterParams["value"] = values[0]
 	}
+	if version, err := peer.GetPeersVersion(); err == nil {
+		if restutils.CheckNotModified(w, r, version) {
+			return
+		}
+	}
+
 	peers, err := peer.GetPeersF(filterParams)
 	if err != nil {
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
@@ -44,6 +50,10 @@ func createPeerListResp(peers []*peer.Peer) *api.PeerListResp {
 			Online:          online,
 			PID:             pid,
 			Metadata:        p.Metadata,
+			Zone:            p.GetZone(),
+			Rack:            p.Rack,
+			Tags:            p.Tags,
+			OpVersion:       p.GetOpVersion(),
 		})
 	}
 
@@ -0,0 +1,110 @@
+package peercommands
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+func peerMaintenanceStartHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req api.PeerMaintenanceReq
+	// request body is optional
+	if err := restutils.UnmarshalRequest(r, &req); err != nil && err != io.EOF {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	setPeerMaintenance(w, r, true, req.Reason)
+}
+
+func peerMaintenanceStopHandler(w http.ResponseWriter, r *http.Request) {
+	setPeerMaintenance(w, r, false, "")
+}
+
+// setPeerMaintenance serves a request to start or end the maintenance of the
+// peer in the URL
+func setPeerMaintenance(w http.ResponseWriter, r *http.Request, enable bool, reason string) {
+	ctx := r.Context()
+
+	peerID := mux.Vars(r)["peerid"]
+	if uuid.Parse(peerID) == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Invalid peerID passed in url")
+		return
+	}
+
+	peerInfo, status, err := SetPeerMaintenance(ctx, peerID, enable, reason)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createPeerGetResp(peerInfo))
+}
+
+// SetPeerMaintenance puts the peer under maintenance or ends its maintenance.
+// While a peer is under maintenance the bricks planner does not place bricks
+// on it, and it going down or coming up is not reported as an event.
+func SetPeerMaintenance(ctx context.Context, peerID string, enable bool, reason string) (*peer.Peer, int, error) {
+	logger := gdctx.GetReqLogger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, peerID)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		return nil, status, err
+	}
+	defer txn.Done()
+
+	peerInfo, err := peer.GetPeer(peerID)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		return nil, status, err
+	}
+
+	if peerInfo.InMaintenance() == enable {
+		if enable {
+			return nil, http.StatusConflict, errors.New("peer is already under maintenance")
+		}
+		return nil, http.StatusConflict, errors.New("peer is not under maintenance")
+	}
+
+	if peerInfo.Metadata == nil {
+		peerInfo.Metadata = make(map[string]string)
+	}
+	if enable {
+		peerInfo.Metadata[peer.MaintenanceKey] = reason
+		peerInfo.Metadata[peer.MaintenanceSinceKey] = time.Now().UTC().Format(time.RFC3339)
+	} else {
+		delete(peerInfo.Metadata, peer.MaintenanceKey)
+		delete(peerInfo.Metadata, peer.MaintenanceSinceKey)
+	}
+
+	if err := peer.AddOrUpdatePeer(peerInfo); err != nil {
+		logger.WithError(err).WithField("peerid", peerID).Error("Failed to update peer Info")
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if enable {
+		logger.WithField("peerid", peerID).Info("peer put under maintenance")
+		events.Broadcast(newPeerEvent(eventPeerMaintenanceStarted, peerInfo))
+	} else {
+		logger.WithField("peerid", peerID).Info("peer maintenance ended")
+		events.Broadcast(newPeerEvent(eventPeerMaintenanceStopped, peerInfo))
+	}
+
+	return peerInfo, http.StatusOK, nil
+}
@@ -0,0 +1,138 @@
+package peercommands
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+const (
+	maxLabelLength    = 64
+	maxTagValueLength = 256
+	maxTags           = 32
+)
+
+// labelRegex is what zone names, rack names and tag keys must match
+var labelRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+func validateLabel(kind, label string) error {
+	if len(label) > maxLabelLength {
+		return fmt.Errorf("%s %q is longer than %d characters", kind, label, maxLabelLength)
+	}
+	if !labelRegex.MatchString(label) {
+		return fmt.Errorf("%s %q must start with a letter or digit and contain only letters, digits, '.', '_' and '-'", kind, label)
+	}
+	return nil
+}
+
+// validatePlacement validates the zone, rack and tags of a peer. Empty zone,
+// rack and tag values are allowed, they unset the attribute.
+func validatePlacement(zone, rack string, tags map[string]string) error {
+	if zone != "" {
+		if err := validateLabel("zone", zone); err != nil {
+			return err
+		}
+	}
+	if rack != "" {
+		if err := validateLabel("rack", rack); err != nil {
+			return err
+		}
+	}
+	for k, v := range tags {
+		if err := validateLabel("tag", k); err != nil {
+			return err
+		}
+		if len(v) > maxTagValueLength {
+			return fmt.Errorf("value of tag %q is longer than %d characters", k, maxTagValueLength)
+		}
+	}
+	return nil
+}
+
+func patchPeerHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	peerID := mux.Vars(r)["peerid"]
+	if uuid.Parse(peerID) == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Invalid peerID passed in url")
+		return
+	}
+
+	var req api.PeerPatchReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	var zone, rack string
+	if req.Zone != nil {
+		zone = *req.Zone
+	}
+	if req.Rack != nil {
+		rack = *req.Rack
+	}
+	if err := validatePlacement(zone, rack, req.Tags); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, peerID)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	peerInfo, err := peer.GetPeer(peerID)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if req.Zone != nil {
+		peerInfo.SetZone(zone)
+	}
+	if req.Rack != nil {
+		peerInfo.Rack = rack
+	}
+	for k, v := range req.Tags {
+		if v == "" {
+			delete(peerInfo.Tags, k)
+			continue
+		}
+		if peerInfo.Tags == nil {
+			peerInfo.Tags = make(map[string]string)
+		}
+		peerInfo.Tags[k] = v
+	}
+	if len(peerInfo.Tags) > maxTags {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, fmt.Sprintf("a peer can have at most %d tags", maxTags))
+		return
+	}
+
+	if err := peer.AddOrUpdatePeer(peerInfo); err != nil {
+		logger.WithError(err).WithField("peerid", peerID).Error("Failed to update peer Info")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := createPeerPatchResp(peerInfo)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func createPeerPatchResp(p *peer.Peer) *api.PeerPatchResp {
+	return (*api.PeerPatchResp)(createPeerGetResp(p))
+}
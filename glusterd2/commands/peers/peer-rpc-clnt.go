@@ -3,6 +3,7 @@ package peercommands
 import (
 	"context"
 
+	"github.com/gluster/glusterd2/glusterd2/certs"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 
 	log "github.com/sirupsen/logrus"
@@ -22,7 +23,7 @@ type peerSvcClnt struct { // this is not really a good name as it can be confuse
 
 // getPeerServiceClient returns a PeerServiceClient for the given address and the underlying grpc.ClientConn
 func getPeerServiceClient(address string) (*peerSvcClnt, error) {
-	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	conn, err := grpc.Dial(address, certs.GrpcDialOption())
 	if err != nil {
 		return nil, err
 	}
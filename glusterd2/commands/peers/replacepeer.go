@@ -0,0 +1,191 @@
+package peercommands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/commands/volumes"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/jobs"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+// PeerBricks returns the bricks of all the volumes which are on the peer
+func PeerBricks(ctx context.Context, peerID uuid.UUID) ([]brick.Brickinfo, error) {
+	volumes, err := volume.GetVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var bricks []brick.Brickinfo
+	for _, v := range volumes {
+		for _, b := range v.GetBricks() {
+			if uuid.Equal(b.PeerID, peerID) {
+				bricks = append(bricks, b)
+			}
+		}
+	}
+	return bricks, nil
+}
+
+// replacePeerBrick replaces the brick of the failed peer by a brick on the
+// new peer
+func replacePeerBrick(ctx context.Context, b brick.Brickinfo, newPeerID uuid.UUID, force bool) error {
+	vol, err := volume.GetVolume(b.VolumeName)
+	if err != nil {
+		return err
+	}
+
+	req := api.ReplaceBrickReq{
+		SrcPeerID:    b.PeerID.String(),
+		SrcBrickPath: b.Path,
+		Force:        force,
+	}
+	if vol.IsAutoProvisioned() {
+		req.LimitPeers = []string{newPeerID.String()}
+	} else {
+		req.NewBrick = &api.BrickReq{
+			PeerID: newPeerID.String(),
+			Path:   b.Path,
+		}
+	}
+
+	_, _, err = volumecommands.ReplaceBrick(ctx, b.VolumeName, req)
+	return err
+}
+
+// replacePeerBricks replaces each brick of the failed peer by a brick on the
+// new peer. Bricks of auto provisioned volumes are provisioned by the bricks
+// planner on the devices of the new peer, other bricks get the same path on
+// the new peer. The data of the bricks is healed on to the new bricks, after
+// which the old bricks are retired, by the jobs started by replace brick.
+func replacePeerBricks(ctx context.Context, h *jobs.Handle, newPeerID uuid.UUID, bricks []brick.Brickinfo, force bool) error {
+	result := api.PeerReplaceResult{
+		PeerID: newPeerID,
+		Bricks: make([]api.PeerReplaceBrick, 0, len(bricks)),
+	}
+
+	failed := 0
+	for _, b := range bricks {
+		// The bricks which are left are not replaced once the job is
+		// cancelled
+		if ctx.Err() != nil {
+			break
+		}
+
+		r := api.PeerReplaceBrick{
+			Volume:   b.VolumeName,
+			OldBrick: b.String(),
+		}
+
+		err := replacePeerBrick(ctx, b, newPeerID, force)
+		if err != nil {
+			h.Logf("failed to replace brick %s of volume %s: %s", b.String(), b.VolumeName, err)
+			r.Error = err.Error()
+			failed++
+		} else {
+			h.Logf("brick %s of volume %s replaced", b.String(), b.VolumeName)
+		}
+		result.Bricks = append(result.Bricks, r)
+	}
+
+	if err := h.SetResult(result); err != nil {
+		gdctx.GetReqLogger(ctx).WithError(err).Warn("failed to set result of peer replace job")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d bricks could not be replaced", failed, len(bricks))
+	}
+	return nil
+}
+
+func peerReplaceHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	peerID := mux.Vars(r)["peerid"]
+	if uuid.Parse(peerID) == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Invalid peerID passed in url")
+		return
+	}
+
+	var req api.PeerReplaceReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	if (len(req.Addresses) == 0) == (req.PeerID == "") {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "either addresses or peer-id of the new peer must be set")
+		return
+	}
+
+	oldPeer, err := peer.GetPeer(peerID)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	// Only a failed peer is replaced, the bricks of a peer which is up can
+	// be moved with replace brick or by draining its devices
+	if _, online := store.Store.IsNodeAlive(oldPeer.ID); online {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, "peer is online, only a failed peer can be replaced")
+		return
+	}
+
+	bricks, err := PeerBricks(ctx, oldPeer.ID)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var newPeer *peer.Peer
+	if req.PeerID != "" {
+		if uuid.Parse(req.PeerID) == nil || req.PeerID == peerID {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid peer-id of the new peer")
+			return
+		}
+		newPeer, err = peer.GetPeer(req.PeerID)
+		if err != nil {
+			status, err := restutils.ErrToStatusCode(err)
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+		if _, online := store.Store.IsNodeAlive(newPeer.ID); !online {
+			restutils.SendHTTPError(ctx, w, http.StatusConflict, "new peer is not online")
+			return
+		}
+	} else {
+		var status int
+		newPeer, status, err = AddPeer(ctx, &api.PeerAddReq{Addresses: req.Addresses, Zone: req.Zone})
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+	}
+
+	logger.WithField("old-peer", oldPeer.ID.String()).WithField("new-peer", newPeer.ID.String()).Info("replacing peer")
+
+	job, err := jobs.Submit(ctx, api.JobTypePeerReplace, "", func(ctx context.Context, h *jobs.Handle) error {
+		return replacePeerBricks(ctx, h, newPeer.ID, bricks, req.Force)
+	})
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendJobAccepted(ctx, w, jobs.CreateJobInfoResp(job))
+}
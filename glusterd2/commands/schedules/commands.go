@@ -0,0 +1,56 @@
+// Package schedulecommands implements the commands to schedule operations on
+// volumes to run at a later time
+package schedulecommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "ScheduleCreate",
+			Method:       "POST",
+			Pattern:      "/schedules",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.ScheduleCreateReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.ScheduleCreateResp)(nil)),
+			HandlerFunc:  scheduleCreateHandler,
+		},
+		route.Route{
+			Name:         "ScheduleList",
+			Method:       "GET",
+			Pattern:      "/schedules",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.ScheduleListResp)(nil)),
+			HandlerFunc:  scheduleListHandler,
+		},
+		route.Route{
+			Name:         "ScheduleInfo",
+			Method:       "GET",
+			Pattern:      "/schedules/{name}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.ScheduleGetResp)(nil)),
+			HandlerFunc:  scheduleGetHandler,
+		},
+		route.Route{
+			Name:        "ScheduleDelete",
+			Method:      "DELETE",
+			Pattern:     "/schedules/{name}",
+			Version:     1,
+			HandlerFunc: scheduleDeleteHandler,
+		},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
@@ -0,0 +1,84 @@
+package schedulecommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/scheduler"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+func scheduleCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.ScheduleCreateReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	s, err := scheduler.NewSchedule(&req, gdctx.GetReqUser(ctx))
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := scheduler.AddSchedule(s); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithField("schedule", s.Name).WithField("next-run", s.NextRun).Info("operation scheduled")
+
+	resp := (*api.ScheduleCreateResp)(scheduler.CreateScheduleInfoResp(s))
+	restutils.SetLocationHeader(r, w, s.Name)
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
+}
+
+func scheduleListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	schedules, err := scheduler.GetSchedules()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := make(api.ScheduleListResp, len(schedules))
+	for i, s := range schedules {
+		resp[i] = *scheduler.CreateScheduleInfoResp(s)
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func scheduleGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	s, err := scheduler.GetSchedule(mux.Vars(r)["name"])
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := (*api.ScheduleGetResp)(scheduler.CreateScheduleInfoResp(s))
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func scheduleDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := scheduler.DeleteSchedule(mux.Vars(r)["name"]); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
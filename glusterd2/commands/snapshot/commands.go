@@ -3,6 +3,7 @@ package snapshotcommands
 import (
 	"net/http"
 
+	"github.com/gluster/glusterd2/glusterd2/middleware"
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/pkg/api"
@@ -23,7 +24,7 @@ func (c *Command) Routes() route.Routes {
 			Version:      1,
 			RequestType:  utils.GetTypeString((*api.SnapCreateReq)(nil)),
 			ResponseType: utils.GetTypeString((*api.SnapCreateResp)(nil)),
-			HandlerFunc:  snapshotCreateHandler},
+			HandlerFunc:  middleware.Async(api.JobTypeSnapshotCreate, snapshotCreateHandler)},
 		route.Route{
 			Name:         "SnapshotActivate",
 			Method:       "POST",
@@ -98,6 +99,49 @@ func (c *Command) Routes() route.Routes {
 			Pattern:     "/snapshots/config",
 			Version:     1,
 			HandlerFunc: snapshotConfigResetHandler},
+		route.Route{
+			Name:         "SnapshotPolicyCreate",
+			Method:       "POST",
+			Pattern:      "/snapshot-policies",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.SnapPolicyCreateReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.SnapPolicyCreateResp)(nil)),
+			HandlerFunc:  snapPolicyCreateHandler},
+		route.Route{
+			Name:         "SnapshotPolicyList",
+			Method:       "GET",
+			Pattern:      "/snapshot-policies",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.SnapPolicyListResp)(nil)),
+			HandlerFunc:  snapPolicyListHandler},
+		route.Route{
+			Name:         "SnapshotPolicyInfo",
+			Method:       "GET",
+			Pattern:      "/snapshot-policies/{name}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.SnapPolicyGetResp)(nil)),
+			HandlerFunc:  snapPolicyGetHandler},
+		route.Route{
+			Name:        "SnapshotPolicyDelete",
+			Method:      "DELETE",
+			Pattern:     "/snapshot-policies/{name}",
+			Version:     1,
+			HandlerFunc: snapPolicyDeleteHandler},
+		route.Route{
+			Name:         "SnapshotLimitsGet",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/snapshot-limits",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.SnapLimitsResp)(nil)),
+			HandlerFunc:  snapLimitsGetHandler},
+		route.Route{
+			Name:         "SnapshotLimitsSet",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/snapshot-limits",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.SnapLimitsReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.SnapLimitsResp)(nil)),
+			HandlerFunc:  snapLimitsSetHandler},
 	}
 }
 
@@ -126,5 +170,9 @@ func (c *Command) RegisterStepFuncs() {
 	registerSnapshotStatusStepFuncs()
 	registerSnapRestoreStepFuncs()
 	registerSnapCloneStepFuncs()
+	registerSnapScheduleOps()
+	registerSnapPolicyRunner()
+	registerSnapLimitsStepFuncs()
+	registerSnapExpiryRunner()
 	return
 }
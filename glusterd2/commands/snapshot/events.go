@@ -0,0 +1,37 @@
+package snapshotcommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+func init() {
+	events.RegisterTypes(
+		api.EventType{
+			Name:        eventSnapSoftLimitReached,
+			Severity:    api.EventSeverityWarning,
+			Description: "A snapshot of a volume was taken beyond one of its soft limits",
+			Objects:     []string{api.EventObjectVolume},
+			Data:        []string{"volume.name", "volume.id", "limit", "limit-value", "current"},
+		},
+		api.EventType{
+			Name:        eventSnapExpired,
+			Description: "A snapshot activated with a time-to-live was deactivated",
+			Objects:     []string{api.EventObjectSnapshot, api.EventObjectVolume},
+			Data:        []string{"snapshot", "parent-volume"},
+		},
+		api.EventType{
+			Name:        eventSnapPolicySucceeded,
+			Description: "A snapshot policy took a snapshot of a volume",
+			Objects:     []string{api.EventObjectSnapshotPolicy, api.EventObjectVolume},
+			Data:        []string{"policy", "volume", "snapshot"},
+		},
+		api.EventType{
+			Name:        eventSnapPolicyFailed,
+			Severity:    api.EventSeverityError,
+			Description: "A snapshot policy failed to take or prune the snapshots of a volume",
+			Objects:     []string{api.EventObjectSnapshotPolicy, api.EventObjectVolume},
+			Data:        []string{"policy", "volume", "error"},
+		},
+	)
+}
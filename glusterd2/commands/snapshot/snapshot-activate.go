@@ -4,6 +4,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
@@ -126,6 +127,11 @@ func snapshotActivateHandler(w http.ResponseWriter, r *http.Request) {
 		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
 		return
 	}
+	expiresAt, err := snapExpiry(req.TTL, time.Now().UTC())
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
 
 	if err = txn.Ctx.Set("oldsnapinfo", &snapinfo); err != nil {
 		log.WithError(err).Error("failed to set old snapinfo in transaction context")
@@ -134,6 +140,8 @@ func snapshotActivateHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	vol.State = volume.VolStarted
+	// Activating a snapshot again replaces its time-to-live
+	snapinfo.ExpiresAt = expiresAt
 	if err = txn.Ctx.Set("snapinfo", &snapinfo); err != nil {
 		log.WithError(err).Error("failed to set snapinfo in transaction context")
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
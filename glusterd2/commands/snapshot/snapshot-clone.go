@@ -1,6 +1,7 @@
 package snapshotcommands
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -29,7 +30,7 @@ func undoSnapshotClone(c transaction.TxnCtx) error {
 
 	for _, b := range volinfo.GetLocalBricks() {
 		volume.UmountBrick(b)
-		if err := lvmutils.RemoveLVSnapshot(b.MountInfo.DevicePath); err != nil {
+		if err := snapshot.RemoveBrickSnapshot(volinfo.ProvisionerType, b.MountInfo.DevicePath); err != nil {
 			c.Logger().WithError(err).WithField(
 				"brick", b.Path).Debug("Failed to remove snapshotted LVM")
 			return err
@@ -116,7 +117,7 @@ func populateCloneBrickMountData(volinfo *volume.Volinfo, name string) (map[stri
 
 			suffix := fmt.Sprintf("clone_%s_%s_s%d_b%d", name, volinfo.Name, svIdx+1, bIdx+1)
 
-			devicePath, err := lvmutils.CreateDevicePath(mntInfo.FsName, suffix)
+			devicePath, err := snapshot.CreateBrickSnapDevicePath(volinfo.ProvisionerType, &b, mntInfo, suffix)
 			if err != nil {
 				log.WithError(err).WithField(
 					"deviceName", devicePath,
@@ -143,13 +144,6 @@ func validateSnapClone(c transaction.TxnCtx) error {
 		nodeData            map[string]snapshot.BrickMountData
 	)
 
-	if err = lvmutils.CommonPrevalidation(lvmutils.CreateCommand); err != nil {
-		log.WithError(err).WithField(
-			"command", lvmutils.CreateCommand,
-		).Error("Failed to find lvm packages")
-		return err
-	}
-
 	if err := c.Get("snapname", &snapname); err != nil {
 		return err
 	}
@@ -164,6 +158,13 @@ func validateSnapClone(c transaction.TxnCtx) error {
 	}
 	volinfo := &snapinfo.SnapVolinfo
 
+	if err = snapshot.ValidateSnapshotCommands(volinfo.ProvisionerType); err != nil {
+		log.WithError(err).WithField(
+			"command", lvmutils.CreateCommand,
+		).Error("Failed to find lvm packages")
+		return err
+	}
+
 	brickStatuses, err := volume.CheckBricksStatus(volinfo)
 	if err != nil {
 		return err
@@ -270,7 +271,6 @@ func registerSnapCloneStepFuncs() {
 func snapshotCloneHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	req := new(api.SnapCloneReq)
-	logger := gdctx.GetReqLogger(ctx)
 
 	snapname := mux.Vars(r)["snapname"]
 	if snapname == "" {
@@ -283,37 +283,45 @@ func snapshotCloneHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !volume.IsValidName(req.CloneName) {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrInvalidVolName)
+	vol, status, err := cloneSnapshot(ctx, snapname, req.CloneName)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
 
-	txn, err := transaction.NewTxnWithLocks(ctx, req.CloneName, snapname)
+	resp := createSnapshotCloneResp(vol)
+	restutils.SetLocationHeader(r, w, vol.Name)
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
+
+}
+
+// cloneSnapshot creates a new volume with the given name from the bricks of
+// the snapshot, and returns the new volume
+func cloneSnapshot(ctx context.Context, snapname, clonename string) (*volume.Volinfo, int, error) {
+	logger := gdctx.GetReqLogger(ctx)
+
+	if !volume.IsValidName(clonename) {
+		return nil, http.StatusBadRequest, gderrors.ErrInvalidVolName
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, clonename, snapname)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, restutils.ErrToStatusCode(err)
 	}
 	defer txn.Done()
 
 	snapinfo, err := snapshot.GetSnapshot(snapname)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, restutils.ErrToStatusCode(err)
 	}
 	snapVol := &snapinfo.SnapVolinfo
 
-	if volume.Exists(req.CloneName) {
-		errMsg := "A volume with the same clone name exist."
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errMsg)
-		return
+	if volume.Exists(clonename) {
+		return nil, http.StatusBadRequest, fmt.Errorf("volume %s already exists", clonename)
 	}
 
 	if snapVol.State != volume.VolStarted {
-		errMsg := "Snapshot must be in started state before cloning."
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errMsg)
-		return
+		return nil, http.StatusBadRequest, errors.New("snapshot must be in started state before cloning")
 	}
 	txn.Nodes = snapVol.Nodes()
 	txn.Steps = []*transaction.Step{
@@ -340,37 +348,28 @@ func snapshotCloneHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	if err = txn.Ctx.Set("snapname", &snapname); err != nil {
 		logger.WithError(err).Error("failed to set request in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
-	if err = txn.Ctx.Set("clonename", &req.CloneName); err != nil {
+	if err = txn.Ctx.Set("clonename", &clonename); err != nil {
 		logger.WithError(err).Error("failed to set request in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if err = txn.Do(); err != nil {
 		logger.WithError(err).Error("snapshot clone transaction failed")
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, restutils.ErrToStatusCode(err)
 	}
 
-	txn.Ctx.Logger().WithField("CloneName", req.CloneName).Info("new volume cloned from snapshot")
+	txn.Ctx.Logger().WithField("CloneName", clonename).Info("new volume cloned from snapshot")
 
-	vol, err := volume.GetVolume(req.CloneName)
+	vol, err := volume.GetVolume(clonename)
 	if err != nil {
 		// FIXME: If volume was created successfully in the txn above and
 		// then the store goes down by the time we reach here, what do
 		// we return to the client ?
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
-
-	resp := createSnapshotCloneResp(vol)
-	restutils.SetLocationHeader(r, w, vol.Name)
-	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
-
+	return vol, http.StatusCreated, nil
 }
 
 func createSnapshotCloneResp(v *volume.Volinfo) *api.SnapshotCloneResp {
@@ -3,14 +3,12 @@ package snapshotcommands
 /*
 TODO
 *setactiveonskip flag
-*snap max limit
-*snap soft limit
-*snap auto-delete
 *activate-on-create
 */
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -42,6 +40,7 @@ import (
 type txnData struct {
 	Req       api.SnapCreateReq
 	CreatedAt time.Time
+	Policy    string
 }
 
 func barrierActivateDeactivateFunc(volinfo *volume.Volinfo, option string, originUUID uuid.UUID) error {
@@ -180,7 +179,7 @@ func undoBrickSnapshots(c transaction.TxnCtx) error {
 
 	snapVol := snapInfo.SnapVolinfo
 	for _, b := range snapVol.GetLocalBricks() {
-		if err := lvmutils.RemoveLVSnapshot(b.MountInfo.DevicePath); err != nil {
+		if err := snapshot.RemoveBrickSnapshot(snapVol.ProvisionerType, b.MountInfo.DevicePath); err != nil {
 			c.Logger().WithError(err).WithField(
 				"brick", b.Path).Debug("Failed to remove snapshotted LVM")
 			return err
@@ -293,7 +292,7 @@ func populateSnapBrickMountData(volinfo *volume.Volinfo, snapName string) (map[s
 			}
 
 			suffix := fmt.Sprintf("snap_%s_%s_s%d_b%d", snapName, volinfo.Name, svIdx+1, bIdx+1)
-			devicePath, err := lvmutils.CreateDevicePath(mntInfo.FsName, suffix)
+			devicePath, err := snapshot.CreateBrickSnapDevicePath(volinfo.ProvisionerType, &b, mntInfo, suffix)
 			if err != nil {
 				log.WithError(err).WithField(
 					"deviceName", devicePath,
@@ -334,7 +333,7 @@ func validateSnapCreate(c transaction.TxnCtx) error {
 	if err != nil {
 		return err
 	}
-	if err = lvmutils.CommonPrevalidation(lvmutils.CreateCommand); err != nil {
+	if err = snapshot.ValidateSnapshotCommands(volinfo.ProvisionerType); err != nil {
 		log.WithError(err).WithField(
 			"command", lvmutils.CreateCommand,
 		).Error("Failed to find lvm packages")
@@ -393,7 +392,7 @@ func takeVolumeSnapshots(newVol, oldVol *volume.Volinfo) error {
 			}
 			wg.Add(1)
 			snapBrick := newVol.Subvols[subvolCount].Bricks[count]
-			go brickSnapshot(errCh, &wg, snapBrick, b)
+			go brickSnapshot(errCh, &wg, oldVol.ProvisionerType, snapBrick, b)
 
 		}
 	}
@@ -415,7 +414,7 @@ func takeVolumeSnapshots(newVol, oldVol *volume.Volinfo) error {
 	return err
 }
 
-func brickSnapshot(errCh chan error, wg *sync.WaitGroup, snapBrick, b brick.Brickinfo) {
+func brickSnapshot(errCh chan error, wg *sync.WaitGroup, provisionerType string, snapBrick, b brick.Brickinfo) {
 	defer wg.Done()
 
 	mountData := snapBrick.MountInfo
@@ -433,7 +432,7 @@ func brickSnapshot(errCh chan error, wg *sync.WaitGroup, snapBrick, b brick.Bric
 		"Path":        b.Path,
 	}).Debug("Running snapshot create command")
 
-	if err := lvmutils.LVSnapshot(mntInfo.FsName, mountData.DevicePath); err != nil {
+	if err := snapshot.TakeBrickSnapshot(provisionerType, &b, mntInfo, mountData.DevicePath); err != nil {
 		log.WithError(err).WithFields(log.Fields{
 			"mountDevice": mntInfo.FsName,
 			"devicePath":  mountData.DevicePath,
@@ -599,6 +598,7 @@ func createSnapinfo(c transaction.TxnCtx) error {
 
 	snapInfo.Description = req.Description
 	snapInfo.ParentVolume = req.VolName
+	snapInfo.Policy = data.Policy
 	/*
 		Snapshot time would be a good addition ?
 	*/
@@ -613,6 +613,8 @@ func duplicateVolinfo(vol, v *volume.Volinfo) {
 	for key, value := range vol.Options {
 		v.Options[key] = value
 	}
+	v.Namespace = vol.Namespace
+	v.ProvisionerType = vol.ProvisionerType
 	v.Transport = vol.Transport
 	v.DistCount = vol.DistCount
 	v.Type = vol.Type
@@ -680,8 +682,6 @@ func validateOriginNodeSnapCreate(c transaction.TxnCtx) error {
 		*Geo-replication,
 		*rebalance
 		*tier daemon run check
-		*check for hard-limit and soft-limit
-		*auto-delete
 	*/
 
 	return nil
@@ -712,54 +712,75 @@ func snapshotCreateHandler(w http.ResponseWriter, r *http.Request) {
 	defer span.End()
 
 	logger := gdctx.GetReqLogger(ctx)
-	var snapInfo snapshot.Snapinfo
-	var data txnData
-	req := &data.Req
+	var req api.SnapCreateReq
 
-	err := unmarshalSnapCreateRequest(req, r)
+	err := unmarshalSnapCreateRequest(&req, r)
 	if err != nil {
 		logger.WithError(err).Error("Failed to unmarshal snaphot create request")
 		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
 		return
 	}
+
+	snapInfo, status, err := CreateSnapshot(ctx, &req, "")
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := createSnapCreateResp(snapInfo)
+	restutils.SetLocationHeader(r, w, snapInfo.SnapVolinfo.Name)
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
+}
+
+// CreateSnapshot takes a snapshot of a volume as per the request. policy is
+// the name of the snapshot policy taking the snapshot, if any.
+func CreateSnapshot(ctx context.Context, req *api.SnapCreateReq, policy string) (*snapshot.Snapinfo, int, error) {
+	span := trace.FromContext(ctx)
+	logger := gdctx.GetReqLogger(ctx)
+	var snapInfo snapshot.Snapinfo
+	data := txnData{Req: *req, Policy: policy}
+	req = &data.Req
+
 	data.CreatedAt = time.Now().UTC()
 	if req.TimeStamp == true {
 		req.SnapName = req.SnapName + (data.CreatedAt).Format("_GMT_2006_01_02_15_04_05")
 	}
 
 	if !volume.IsValidName(req.SnapName) {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrInvalidSnapName)
-		return
+		return nil, http.StatusBadRequest, gderrors.ErrInvalidSnapName
+	}
+
+	if status, err := enforceSnapLimits(ctx, req.VolName); err != nil {
+		logger.WithError(err).WithField("volume", req.VolName).Error("snapshot limits of volume not met")
+		return nil, status, err
 	}
 
 	txn, err := transaction.NewTxnWithLocks(ctx, req.VolName, req.SnapName)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, restutils.ErrToStatusCode(err)
 	}
 	defer txn.Done()
 
 	if err = txn.Ctx.Set("data", data); err != nil {
 		logger.WithError(err).Error("failed to set request in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if err := validateOriginNodeSnapCreate(txn.Ctx); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusUnprocessableEntity, err)
-		return
+		return nil, http.StatusUnprocessableEntity, err
 	}
 	vol, e := volume.GetVolume(req.VolName)
 	if e != nil {
-		status, err := restutils.ErrToStatusCode(e)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, restutils.ErrToStatusCode(e)
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, vol.GetNamespace()) {
+		return nil, http.StatusNotFound, gderrors.ErrVolNotFound
 	}
 
-	if vol.ProvisionerType != api.ProvisionerTypeLvm && vol.ProvisionerType != "" {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, gderrors.ErrSnapNotSupported)
-		return
+	switch vol.ProvisionerType {
+	case "", api.ProvisionerTypeLvm, api.ProvisionerTypeLoop:
+	default:
+		return nil, http.StatusInternalServerError, gderrors.ErrSnapNotSupported
 	}
 
 	txn.Nodes = vol.Nodes()
@@ -807,22 +828,19 @@ func snapshotCreateHandler(w http.ResponseWriter, r *http.Request) {
 
 	if err = txn.Do(); err != nil {
 		logger.WithError(err).Error("snapshot create transaction failed")
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, restutils.ErrToStatusCode(err)
 	}
 
 	txn.Ctx.Logger().WithField("SnapName", req.SnapName).Info("new snapshot created")
 
 	if err = txn.Ctx.Get("snapinfo", &snapInfo); err != nil {
 		logger.WithError(err).Error("failed to get snap volinfo in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
-	resp := createSnapCreateResp(&snapInfo)
-	restutils.SetLocationHeader(r, w, snapInfo.SnapVolinfo.Name)
-	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
+	warnSnapSoftLimits(ctx, req.VolName)
+
+	return &snapInfo, http.StatusCreated, nil
 }
 
 // createSnapCreateResp functions create resnse for rest utils
@@ -838,5 +856,7 @@ func createSnapInfoResp(snap *snapshot.Snapinfo) *api.SnapInfo {
 		ParentVolName: snap.ParentVolume,
 		Description:   snap.Description,
 		CreatedAt:     snap.CreatedAt,
+		Policy:        snap.Policy,
+		ExpiresAt:     snap.ExpiresAt,
 	}
 }
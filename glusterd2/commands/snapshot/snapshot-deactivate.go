@@ -1,7 +1,9 @@
 package snapshotcommands
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
@@ -112,29 +114,43 @@ func registerSnapDeactivateStepFuncs() {
 func snapshotDeactivateHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	var vol *volume.Volinfo
-
 	snapname := mux.Vars(r)["snapname"]
 
-	txn, err := transaction.NewTxnWithLocks(ctx, snapname)
+	snapinfo, status, err := deactivateSnap(ctx, snapname, false)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
 		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
+
+	resp := createSnapshotDeactivateResp(snapinfo)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// deactivateSnap deactivates the snapshot and returns its latest snapinfo. If
+// expired is set, the snapshot is deactivated only if its activation has
+// expired.
+func deactivateSnap(ctx context.Context, snapname string, expired bool) (*snapshot.Snapinfo, int, error) {
+	var vol *volume.Volinfo
+
+	txn, err := transaction.NewTxnWithLocks(ctx, snapname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		return nil, status, err
+	}
 	defer txn.Done()
 
 	snapinfo, err := snapshot.GetSnapshot(snapname)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, status, err
 	}
 
 	vol = &snapinfo.SnapVolinfo
 	if vol.State != volume.VolStarted {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrSnapDeactivated)
-		return
+		return nil, http.StatusBadRequest, errors.ErrSnapDeactivated
+	}
+	if expired && (snapinfo.ExpiresAt.IsZero() || snapinfo.ExpiresAt.After(time.Now())) {
+		return nil, http.StatusConflict, errors.ErrSnapNotExpired
 	}
 
 	txn.Nodes = vol.Nodes()
@@ -153,33 +169,28 @@ func snapshotDeactivateHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	if err = txn.Ctx.Set("oldsnapinfo", &snapinfo); err != nil {
 		log.WithError(err).Error("failed to set old snapinfo in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	vol.State = volume.VolStopped
+	snapinfo.ExpiresAt = time.Time{}
 	if err = txn.Ctx.Set("snapinfo", &snapinfo); err != nil {
 		log.WithError(err).Error("failed to set snapinfo in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if err = txn.Do(); err != nil {
 		log.WithError(err).WithField("snapshot", snapname).Error("failed to de-activate snap")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	//Fetching latest isnapinfo
 	snapinfo, err = snapshot.GetSnapshot(snapname)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, status, err
 	}
-
-	resp := createSnapshotDeactivateResp(snapinfo)
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+	return snapinfo, http.StatusOK, nil
 }
 
 func createSnapshotDeactivateResp(snap *snapshot.Snapinfo) *api.SnapshotDeactivateResp {
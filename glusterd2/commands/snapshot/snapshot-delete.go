@@ -1,6 +1,7 @@
 package snapshotcommands
 
 import (
+	"context"
 	"net/http"
 	"os"
 	"strings"
@@ -13,7 +14,6 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	"github.com/gluster/glusterd2/glusterd2/volgen"
 	"github.com/gluster/glusterd2/glusterd2/volume"
-	"github.com/gluster/glusterd2/pkg/lvmutils"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/gorilla/mux"
@@ -31,7 +31,7 @@ func snapshotBrickDelete(errCh chan error, wg *sync.WaitGroup, snapVol volume.Vo
 
 		}
 	}
-	if err := lvmutils.RemoveLVSnapshot(b.MountInfo.DevicePath); err != nil {
+	if err := snapshot.RemoveBrickSnapshot(snapVol.ProvisionerType, b.MountInfo.DevicePath); err != nil {
 		log.WithError(err).WithField(
 			"brick", b.Path).Debug("Failed to remove snapshotted LVM")
 		errCh <- err
@@ -126,30 +126,37 @@ func snapshotDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	ctx, span := trace.StartSpan(ctx, "/snapshotDeleteHandler")
 	defer span.End()
 
-	logger := gdctx.GetReqLogger(ctx)
 	snapname := mux.Vars(r)["snapname"]
+	if status, err := DeleteSnapshot(ctx, snapname); err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+// DeleteSnapshot deletes the snapshot with the given name. It returns the
+// HTTP status to respond with on failure.
+func DeleteSnapshot(ctx context.Context, snapname string) (int, error) {
+	span := trace.FromContext(ctx)
+	logger := gdctx.GetReqLogger(ctx)
+
 	//Fetching snapinfo to get the parent volume name. Parent volume has to be locked
 	snapinfo, err := snapshot.GetSnapshot(snapname)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return restutils.ErrToStatusCode(err)
 	}
 
 	txn, err := transaction.NewTxnWithLocks(ctx, snapname, snapinfo.ParentVolume)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return restutils.ErrToStatusCode(err)
 	}
 	defer txn.Done()
 
 	//Fetching snapinfo again, but this time inside a lock
 	snapinfo, err = snapshot.GetSnapshot(snapname)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return restutils.ErrToStatusCode(err)
 	}
 
 	volinfo := &snapinfo.SnapVolinfo
@@ -166,8 +173,7 @@ func snapshotDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := txn.Ctx.Set("snapinfo", snapinfo); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return http.StatusInternalServerError, err
 	}
 
 	span.AddAttributes(
@@ -179,11 +185,9 @@ func snapshotDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	if err := txn.Do(); err != nil {
 		logger.WithError(err).WithField(
 			"snapname", snapname).Error("transaction to delete snapshot failed")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return http.StatusInternalServerError, err
 	}
 
 	logger.WithField("Snapshot-name", snapname).Info("snapshot deleted")
-
-	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+	return http.StatusOK, nil
 }
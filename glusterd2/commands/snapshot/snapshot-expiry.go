@@ -0,0 +1,124 @@
+package snapshotcommands
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/scheduler"
+	"github.com/gluster/glusterd2/glusterd2/snapshot"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// eventSnapExpired is sent when a snapshot activated with a time-to-live is
+// deactivated
+const eventSnapExpired = "snapshot.expired"
+
+// deactivating has the snapshots being deactivated by the expiry runner
+var deactivating = struct {
+	sync.Mutex
+	snaps map[string]bool
+}{snaps: make(map[string]bool)}
+
+// expiredSnapshots returns the names of the activated snapshots whose
+// activation has expired at the given time
+func expiredSnapshots(snaps []*snapshot.Snapinfo, now time.Time) []string {
+	var expired []string
+	for _, s := range snaps {
+		if s == nil || s.SnapVolinfo.State != volume.VolStarted {
+			continue
+		}
+		if s.ExpiresAt.IsZero() || s.ExpiresAt.After(now) {
+			continue
+		}
+		expired = append(expired, s.SnapVolinfo.Name)
+	}
+	return expired
+}
+
+// snapExpiry returns the time at which a snapshot activated with the given
+// time-to-live, in seconds, expires. A snapshot activated without a
+// time-to-live doesn't expire.
+func snapExpiry(ttl int, now time.Time) (time.Time, error) {
+	if ttl < 0 {
+		return time.Time{}, gderrors.ErrSnapInvalidTTL
+	}
+	if ttl == 0 {
+		return time.Time{}, nil
+	}
+	return now.Add(time.Duration(ttl) * time.Second), nil
+}
+
+// deactivateExpiredSnapshots starts deactivating the snapshots whose
+// activation has expired. It is called by the cleanup leader along with the
+// scheduled operations. Snapshots still being deactivated since an earlier
+// call are skipped.
+func deactivateExpiredSnapshots() {
+	snaps, err := snapshot.GetSnapshots()
+	if err != nil {
+		log.WithError(err).Error("failed to get snapshots")
+		return
+	}
+
+	var expired []string
+	deactivating.Lock()
+	for _, snapname := range expiredSnapshots(snaps, time.Now().UTC()) {
+		if !deactivating.snaps[snapname] {
+			deactivating.snaps[snapname] = true
+			expired = append(expired, snapname)
+		}
+	}
+	deactivating.Unlock()
+
+	if len(expired) != 0 {
+		go deactivateSnapshots(expired)
+	}
+}
+
+func deactivateSnapshots(snapnames []string) {
+	reqID := uuid.NewRandom()
+	logger := log.WithField("reqid", reqID.String())
+	ctx := gdctx.WithReqID(context.Background(), reqID)
+	ctx = gdctx.WithReqLogger(ctx, logger)
+
+	for _, snapname := range snapnames {
+		snapinfo, _, err := deactivateSnap(ctx, snapname, true)
+		deactivating.Lock()
+		delete(deactivating.snaps, snapname)
+		deactivating.Unlock()
+		if err != nil {
+			switch err {
+			case gderrors.ErrSnapDeactivated, gderrors.ErrSnapNotExpired:
+				// The snapshot may have been deactivated, or
+				// activated again, while waiting for the lock
+			case transaction.ErrLockTimeout:
+				// Another operation on the snapshot is in
+				// progress, the snapshot is deactivated later
+				logger.WithField("snapshot", snapname).Debug("expired snapshot is locked, retrying later")
+			default:
+				logger.WithError(err).WithField("snapshot", snapname).Error("failed to deactivate expired snapshot")
+			}
+			continue
+		}
+
+		logger.WithField("snapshot", snapname).Info("expired snapshot deactivated")
+		events.Broadcast(events.New(eventSnapExpired, map[string]string{
+			"snapshot":      snapname,
+			"parent-volume": snapinfo.ParentVolume,
+		}, true,
+			api.EventObject{Kind: api.EventObjectSnapshot, ID: snapinfo.SnapVolinfo.ID.String(), Name: snapname},
+			api.EventObject{Kind: api.EventObjectVolume, Name: snapinfo.ParentVolume}))
+	}
+}
+
+func registerSnapExpiryRunner() {
+	scheduler.RegisterDueFunc(deactivateExpiredSnapshots)
+}
@@ -0,0 +1,50 @@
+package snapshotcommands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/snapshot"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSnap(name string, state volume.VolState, expiresAt time.Time) *snapshot.Snapinfo {
+	s := &snapshot.Snapinfo{ExpiresAt: expiresAt}
+	s.SnapVolinfo.Name = name
+	s.SnapVolinfo.State = state
+	return s
+}
+
+func TestExpiredSnapshots(t *testing.T) {
+	now := time.Now().UTC()
+	snaps := []*snapshot.Snapinfo{
+		newTestSnap("expired", volume.VolStarted, now.Add(-time.Minute)),
+		newTestSnap("expiring-now", volume.VolStarted, now),
+		newTestSnap("not-expired", volume.VolStarted, now.Add(time.Minute)),
+		newTestSnap("no-ttl", volume.VolStarted, time.Time{}),
+		newTestSnap("deactivated", volume.VolStopped, now.Add(-time.Minute)),
+		nil,
+	}
+
+	assert.Equal(t, []string{"expired", "expiring-now"}, expiredSnapshots(snaps, now))
+	assert.Empty(t, expiredSnapshots(nil, now))
+}
+
+func TestSnapExpiry(t *testing.T) {
+	now := time.Now().UTC()
+
+	expiresAt, err := snapExpiry(90, now)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(90*time.Second), expiresAt)
+
+	// Without a time-to-live, the snapshot doesn't expire
+	expiresAt, err = snapExpiry(0, now)
+	assert.NoError(t, err)
+	assert.True(t, expiresAt.IsZero())
+
+	_, err = snapExpiry(-1, now)
+	assert.Equal(t, gderrors.ErrSnapInvalidTTL, err)
+}
@@ -0,0 +1,328 @@
+package snapshotcommands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/snapshot"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// eventSnapSoftLimitReached is sent when a snapshot of a volume is
+	// taken beyond one of its soft limits
+	eventSnapSoftLimitReached = "snapshot.soft-limit-reached"
+
+	poolUsageTxnKey = "snapPoolUsage"
+)
+
+func snapLimitsGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	vol, err := volume.GetVolume(mux.Vars(r)["volname"])
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, vol.GetNamespace()) {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, gderrors.ErrVolNotFound)
+		return
+	}
+
+	resp, err := createSnapLimitsResp(ctx, vol)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func snapLimitsSetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req api.SnapLimitsReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	vol, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, vol.GetNamespace()) {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, gderrors.ErrVolNotFound)
+		return
+	}
+
+	limits := vol.SnapLimits
+	if req.MaxCount != nil {
+		limits.MaxCount = *req.MaxCount
+	}
+	if req.SoftMaxCount != nil {
+		limits.SoftMaxCount = *req.SoftMaxCount
+	}
+	if req.CapacityHardLimit != nil {
+		limits.CapacityHardLimit = *req.CapacityHardLimit
+	}
+	if req.CapacitySoftLimit != nil {
+		limits.CapacitySoftLimit = *req.CapacitySoftLimit
+	}
+	if req.AutoDelete != nil {
+		limits.AutoDelete = *req.AutoDelete
+	}
+	if err := limits.Validate(); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	vol.SnapLimits = limits
+	if err := volume.AddOrUpdateVolumeFunc(vol); err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to store volume info")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	logger.WithField("volume", volname).WithField("limits", limits).Info("snapshot limits changed")
+
+	resp, err := createSnapLimitsResp(ctx, vol)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func createSnapLimitsResp(ctx context.Context, vol *volume.Volinfo) (*api.SnapLimitsResp, error) {
+	l := vol.SnapLimits
+	resp := &api.SnapLimitsResp{
+		VolName:           vol.Name,
+		MaxCount:          l.MaxCount,
+		SoftMaxCount:      l.SoftMaxCount,
+		CapacityHardLimit: l.CapacityHardLimit,
+		CapacitySoftLimit: l.CapacitySoftLimit,
+		AutoDelete:        l.AutoDelete,
+		SnapCount:         len(vol.SnapList),
+	}
+
+	if l.CapacityLimited() {
+		used, err := getPoolUsage(ctx, vol)
+		if err != nil {
+			return nil, err
+		}
+		resp.CapacityUsed = used
+	}
+	return resp, nil
+}
+
+func snapPoolUsage(c transaction.TxnCtx) error {
+	var volname string
+	if err := c.Get("volname", &volname); err != nil {
+		return err
+	}
+
+	vol, err := volume.GetVolume(volname)
+	if err != nil {
+		return err
+	}
+
+	usage, err := snapshot.BricksPoolUsage(vol)
+	if err != nil {
+		c.Logger().WithError(err).WithField("volume", volname).Error("failed to get usage of thin pools")
+		return err
+	}
+
+	c.SetNodeResult(gdctx.MyUUID, poolUsageTxnKey, float64(usage))
+	return nil
+}
+
+func registerSnapLimitsStepFuncs() {
+	transaction.RegisterStepFunc(snapPoolUsage, "snap-limits.PoolUsage")
+}
+
+// getPoolUsage returns the highest usage, in percent, of the thin pools of
+// the bricks of the volume across all its peers
+func getPoolUsage(ctx context.Context, vol *volume.Volinfo) (float64, error) {
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "snap-limits.PoolUsage",
+			Nodes:  vol.Nodes(),
+		},
+	}
+	txn.DisableRollback = true
+
+	if err := txn.Ctx.Set("volname", vol.Name); err != nil {
+		return 0, err
+	}
+	if err := txn.Do(); err != nil {
+		return 0, err
+	}
+
+	var usage float64
+	for _, node := range vol.Nodes() {
+		var used float64
+		if err := txn.Ctx.GetNodeResult(node, poolUsageTxnKey, &used); err != nil {
+			return 0, err
+		}
+		if used > usage {
+			usage = used
+		}
+	}
+	return usage, nil
+}
+
+// enforceSnapLimits makes room for a new snapshot of the volume within its
+// hard limits, deleting its oldest snapshots if auto-delete is set. It is
+// called before the volume is locked for the new snapshot, as deleting a
+// snapshot locks its parent volume.
+func enforceSnapLimits(ctx context.Context, volname string) (int, error) {
+	vol, err := volume.GetVolume(volname)
+	if err != nil {
+		return restutils.ErrToStatusCode(err)
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, vol.GetNamespace()) {
+		return http.StatusNotFound, gderrors.ErrVolNotFound
+	}
+	limits := vol.SnapLimits
+
+	if limits.MaxCount != 0 {
+		for excess := len(vol.SnapList) - limits.MaxCount + 1; excess > 0; excess-- {
+			if !limits.AutoDelete {
+				return http.StatusConflict, gderrors.ErrSnapCountLimitReached
+			}
+			deleted, status, err := deleteOldestSnapshot(ctx, volname)
+			if err != nil {
+				return status, err
+			}
+			if !deleted {
+				break
+			}
+		}
+	}
+
+	if limits.CapacityHardLimit != 0 {
+		for {
+			used, err := getPoolUsage(ctx, vol)
+			if err != nil {
+				return http.StatusInternalServerError, err
+			}
+			if used < limits.CapacityHardLimit {
+				break
+			}
+			if !limits.AutoDelete {
+				return http.StatusConflict, gderrors.ErrSnapCapacityLimitReached
+			}
+			deleted, status, err := deleteOldestSnapshot(ctx, volname)
+			if err != nil {
+				return status, err
+			}
+			if !deleted {
+				return http.StatusConflict, gderrors.ErrSnapCapacityLimitReached
+			}
+		}
+	}
+
+	return http.StatusOK, nil
+}
+
+// deleteOldestSnapshot deletes the oldest snapshot of the volume. It returns
+// false if the volume has no snapshots.
+func deleteOldestSnapshot(ctx context.Context, volname string) (bool, int, error) {
+	logger := gdctx.GetReqLogger(ctx)
+
+	snaps, err := snapshot.GetSnapshots()
+	if err != nil {
+		return false, http.StatusInternalServerError, err
+	}
+
+	var oldest *snapshot.Snapinfo
+	for _, s := range snaps {
+		if s == nil || s.ParentVolume != volname {
+			continue
+		}
+		if oldest == nil || s.CreatedAt.Before(oldest.CreatedAt) {
+			oldest = s
+		}
+	}
+	if oldest == nil {
+		return false, http.StatusOK, nil
+	}
+
+	name := oldest.SnapVolinfo.Name
+	if status, err := DeleteSnapshot(ctx, name); err != nil {
+		return false, status, fmt.Errorf("failed to auto-delete snapshot %s: %s", name, err)
+	}
+	logger.WithField("snapshot", name).WithField("volume", volname).Info("snapshot auto-deleted to stay within limits")
+	return true, http.StatusOK, nil
+}
+
+// warnSnapSoftLimits sends an event for each soft limit of the volume which
+// has been reached
+func warnSnapSoftLimits(ctx context.Context, volname string) {
+	logger := gdctx.GetReqLogger(ctx).WithField("volume", volname)
+
+	vol, err := volume.GetVolume(volname)
+	if err != nil {
+		logger.WithError(err).Warn("failed to check snapshot soft limits")
+		return
+	}
+	limits := vol.SnapLimits
+
+	if limits.SoftMaxCount != 0 && len(vol.SnapList) >= limits.SoftMaxCount {
+		logger.WithField("snap-count", len(vol.SnapList)).Warn("snapshot count soft limit reached")
+		events.Broadcast(newSnapSoftLimitEvent(vol, "soft-max-count",
+			fmt.Sprint(limits.SoftMaxCount), fmt.Sprint(len(vol.SnapList))))
+	}
+
+	if limits.CapacitySoftLimit != 0 {
+		used, err := getPoolUsage(ctx, vol)
+		if err != nil {
+			logger.WithError(err).Warn("failed to check snapshot capacity soft limit")
+			return
+		}
+		if used >= limits.CapacitySoftLimit {
+			logger.WithField("capacity-used", used).Warn("snapshot capacity soft limit reached")
+			events.Broadcast(newSnapSoftLimitEvent(vol, "capacity-soft-limit",
+				fmt.Sprint(limits.CapacitySoftLimit), fmt.Sprint(used)))
+		}
+	}
+}
+
+func newSnapSoftLimitEvent(vol *volume.Volinfo, limit, value, current string) *api.Event {
+	data := map[string]string{
+		"volume.name": vol.Name,
+		"volume.id":   vol.ID.String(),
+		"limit":       limit,
+		"limit-value": value,
+		"current":     current,
+	}
+	return events.New(eventSnapSoftLimitReached, data, true, api.EventObject{
+		Kind: api.EventObjectVolume,
+		ID:   vol.ID.String(),
+		Name: vol.Name,
+	})
+}
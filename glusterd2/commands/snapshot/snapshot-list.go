@@ -2,22 +2,34 @@ package snapshotcommands
 
 import (
 	"net/http"
+	"sort"
 
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/snapshot"
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
 )
 
 func snapshotListHandler(w http.ResponseWriter, r *http.Request) {
 
-	snapName := make(map[string][]api.SnapInfo)
+	var snaps []api.SnapInfo
 	ctx := r.Context()
 
+	page, err := restutils.GetPage(r)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
 	volumeName := r.URL.Query().Get("volume")
 
 	if volumeName != "" {
 		vol, err := volume.GetVolume(volumeName)
+		if err == nil && !gdctx.ReqNamespaceAllowed(ctx, vol.GetNamespace()) {
+			err = gderrors.ErrVolNotFound
+		}
 		if err != nil {
 			status, err := restutils.ErrToStatusCode(err)
 			restutils.SendHTTPError(ctx, w, status, err)
@@ -30,33 +42,46 @@ func snapshotListHandler(w http.ResponseWriter, r *http.Request) {
 				restutils.SendHTTPError(ctx, w, status, err)
 				return
 			}
-			snapName[volumeName] = append(snapName[volumeName], *createSnapInfoResp(snapInfo))
+			snaps = append(snaps, *createSnapInfoResp(snapInfo))
 		}
 
 	} else {
-		snaps, err := snapshot.GetSnapshots()
+		snapinfos, err := snapshot.GetSnapshots()
 		if err != nil {
 			status, err := restutils.ErrToStatusCode(err)
 			restutils.SendHTTPError(ctx, w, status, err)
 			return
 		}
-		for _, s := range snaps {
-			snapName[s.ParentVolume] = append(snapName[s.ParentVolume], *createSnapInfoResp(s))
+		for _, s := range snapinfos {
+			// Snapshots belong to the namespace of their parent volume
+			if !gdctx.ReqNamespaceAllowed(ctx, s.SnapVolinfo.GetNamespace()) {
+				continue
+			}
+			snaps = append(snaps, *createSnapInfoResp(s))
 		}
 	}
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createSnapshotListResp(snapName))
+
+	// Snapshots are paginated in the order of their parent volume and name
+	sort.Slice(snaps, func(i, j int) bool {
+		if snaps[i].ParentVolName != snaps[j].ParentVolName {
+			return snaps[i].ParentVolName < snaps[j].ParentVolName
+		}
+		return snaps[i].VolInfo.Name < snaps[j].VolInfo.Name
+	})
+	start, end := page.Apply(w, r, len(snaps), func(i int) string { return snaps[i].VolInfo.Name })
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createSnapshotListResp(snaps[start:end]))
 }
 
-func createSnapshotListResp(volSnaps map[string][]api.SnapInfo) *api.SnapListResp {
-	var resp api.SnapListResp
-	resp = make(api.SnapListResp, 0)
-	for vol, snapList := range volSnaps {
-		var snap api.SnapList
-		snap.ParentName = vol
-		for _, s := range snapList {
-			snap.SnapList = append(snap.SnapList, s)
+// createSnapshotListResp groups the snapshots by their parent volume. The
+// snapshots must be ordered by their parent volume.
+func createSnapshotListResp(snaps []api.SnapInfo) *api.SnapListResp {
+	resp := make(api.SnapListResp, 0)
+	for _, s := range snaps {
+		if n := len(resp); n == 0 || resp[n-1].ParentName != s.ParentVolName {
+			resp = append(resp, api.SnapList{ParentName: s.ParentVolName})
 		}
-		resp = append(resp, snap)
+		resp[len(resp)-1].SnapList = append(resp[len(resp)-1].SnapList, s)
 	}
 	return &resp
 }
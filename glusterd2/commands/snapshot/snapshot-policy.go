@@ -0,0 +1,257 @@
+package snapshotcommands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/scheduler"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/snapshot"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// Events sent for each volume a snapshot policy runs on
+const (
+	eventSnapPolicySucceeded = "snapshot-policy.succeeded"
+	eventSnapPolicyFailed    = "snapshot-policy.failed"
+)
+
+func snapPolicyCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.SnapPolicyCreateReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	p, err := snapshot.NewPolicy(&req, gdctx.GetReqUser(ctx))
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := snapshot.AddPolicy(p); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithField("policy", p.Name).WithField("next-run", p.NextRun).Info("snapshot policy created")
+
+	resp := (*api.SnapPolicyCreateResp)(snapshot.CreatePolicyInfoResp(p))
+	restutils.SetLocationHeader(r, w, p.Name)
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
+}
+
+func snapPolicyListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	policies, err := snapshot.GetPolicies()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := make(api.SnapPolicyListResp, len(policies))
+	for i, p := range policies {
+		resp[i] = *snapshot.CreatePolicyInfoResp(p)
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func snapPolicyGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p, err := snapshot.GetPolicy(mux.Vars(r)["name"])
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := (*api.SnapPolicyGetResp)(snapshot.CreatePolicyInfoResp(p))
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func snapPolicyDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := snapshot.DeletePolicy(mux.Vars(r)["name"]); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+// runDuePolicies starts the runs of the snapshot policies which are due. It
+// is called by the cleanup leader along with the scheduled operations, and
+// like them, a policy is moved to its next run before it is run.
+func runDuePolicies() {
+	policies, err := snapshot.GetPolicies()
+	if err != nil {
+		log.WithError(err).Error("failed to get snapshot policies")
+		return
+	}
+
+	now := time.Now().UTC()
+	for _, p := range policies {
+		if p.NextRun.IsZero() || p.NextRun.After(now) {
+			continue
+		}
+
+		var due snapshot.Policy
+		ok, err := snapshot.UpdatePolicy(p.Name, func(p *snapshot.Policy) bool {
+			if p.NextRun.IsZero() || p.NextRun.After(now) {
+				return false
+			}
+			p.NextRun, _ = scheduler.NextRun(p.Cron, now)
+			due = *p
+			return true
+		})
+		if err != nil {
+			log.WithError(err).WithField("policy", p.Name).Error("failed to update snapshot policy")
+		}
+		if ok {
+			go runPolicy(&due)
+		}
+	}
+}
+
+// runPolicy takes a snapshot of every started volume selected by the policy
+// and prunes the snapshots the policy took of it beyond its retention
+func runPolicy(p *snapshot.Policy) {
+	reqID := uuid.NewRandom()
+	logger := log.WithFields(log.Fields{
+		"reqid":  reqID.String(),
+		"policy": p.Name,
+	})
+	ctx := gdctx.WithReqID(context.Background(), reqID)
+	ctx = gdctx.WithReqLogger(ctx, logger)
+	ctx = gdctx.WithReqUser(ctx, p.CreatedBy)
+
+	start := time.Now().UTC()
+	var errs []string
+
+	vols, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		logger.WithError(err).Error("failed to get volumes")
+		errs = append(errs, err.Error())
+	}
+
+	for _, v := range vols {
+		if !p.Selects(v) {
+			continue
+		}
+		if v.State != volume.VolStarted {
+			logger.WithField("volume", v.Name).Debug("volume is not started, not taking snapshot")
+			continue
+		}
+
+		snapname, err := runPolicyOnVolume(ctx, p, v.Name)
+		if err != nil {
+			logger.WithError(err).WithField("volume", v.Name).Error("snapshot policy failed")
+			errs = append(errs, fmt.Sprintf("%s: %s", v.Name, err))
+			events.Broadcast(newSnapPolicyEvent(eventSnapPolicyFailed, p.Name, v.Name, map[string]string{
+				"error": err.Error(),
+			}))
+			continue
+		}
+		events.Broadcast(newSnapPolicyEvent(eventSnapPolicySucceeded, p.Name, v.Name, map[string]string{
+			"snapshot": snapname,
+		}))
+	}
+
+	for i := 0; i < 3; i++ {
+		ok, uerr := snapshot.UpdatePolicy(p.Name, func(p *snapshot.Policy) bool {
+			p.LastRun = start
+			p.LastStatus = scheduler.StatusSuccess
+			p.LastError = ""
+			if len(errs) != 0 {
+				p.LastStatus = scheduler.StatusFailed
+				p.LastError = strings.Join(errs, "; ")
+			}
+			return true
+		})
+		if uerr != nil {
+			logger.WithError(uerr).Error("failed to record result of snapshot policy")
+			return
+		}
+		if ok {
+			return
+		}
+	}
+}
+
+// runPolicyOnVolume takes a snapshot of the volume and removes the oldest
+// snapshots taken of it by the policy, keeping as many as its retention. It
+// returns the name of the new snapshot.
+func runPolicyOnVolume(ctx context.Context, p *snapshot.Policy, volname string) (string, error) {
+	logger := gdctx.GetReqLogger(ctx).WithField("volume", volname)
+
+	req := api.SnapCreateReq{
+		VolName:     volname,
+		SnapName:    p.Name + "_" + volname,
+		TimeStamp:   true,
+		Description: fmt.Sprintf("taken by snapshot policy %s", p.Name),
+	}
+	snap, _, err := CreateSnapshot(ctx, &req, p.Name)
+	if err != nil {
+		return "", err
+	}
+	logger.WithField("snapshot", snap.SnapVolinfo.Name).Info("snapshot taken by policy")
+
+	snaps, err := snapshot.GetSnapshots()
+	if err != nil {
+		return snap.SnapVolinfo.Name, err
+	}
+
+	var taken []*snapshot.Snapinfo
+	for _, s := range snaps {
+		if s != nil && s.ParentVolume == volname && s.Policy == p.Name {
+			taken = append(taken, s)
+		}
+	}
+	if len(taken) <= p.Retention {
+		return snap.SnapVolinfo.Name, nil
+	}
+
+	sort.Slice(taken, func(i, j int) bool {
+		return taken[i].CreatedAt.Before(taken[j].CreatedAt)
+	})
+	for _, s := range taken[:len(taken)-p.Retention] {
+		name := s.SnapVolinfo.Name
+		if _, err := DeleteSnapshot(ctx, name); err != nil {
+			return snap.SnapVolinfo.Name, fmt.Errorf("failed to prune snapshot %s: %s", name, err)
+		}
+		logger.WithField("snapshot", name).Info("snapshot pruned by policy")
+	}
+	return snap.SnapVolinfo.Name, nil
+}
+
+func newSnapPolicyEvent(name, policy, volname string, data map[string]string) *api.Event {
+	data["policy"] = policy
+	data["volume"] = volname
+	return events.New(name, data, true,
+		api.EventObject{Kind: api.EventObjectSnapshotPolicy, Name: policy},
+		api.EventObject{Kind: api.EventObjectVolume, Name: volname})
+}
+
+func registerSnapPolicyRunner() {
+	scheduler.RegisterDueFunc(runDuePolicies)
+}
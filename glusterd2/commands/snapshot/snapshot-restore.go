@@ -1,17 +1,22 @@
 package snapshotcommands
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/commands/volumes"
+	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/jobs"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/snapshot"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	"github.com/gluster/glusterd2/glusterd2/volgen"
 	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/errors"
 
 	"github.com/gorilla/mux"
@@ -223,7 +228,7 @@ func createRestoreVolinfo(snapinfo *snapshot.Snapinfo, vol *volume.Volinfo) volu
 		bricks := createVolumeBrickFromSnap(subvol.Bricks, vol)
 		s := volume.Subvol{
 			ArbiterCount:    subvol.ArbiterCount,
-			DisperseCount:   subvol.ArbiterCount,
+			DisperseCount:   subvol.DisperseCount,
 			ID:              subvol.ID,
 			Name:            name,
 			RedundancyCount: subvol.RedundancyCount,
@@ -295,6 +300,9 @@ func cleanParentBricks(c transaction.TxnCtx) error {
 		return err
 	}
 
+	if volinfo.ProvisionerType == api.ProvisionerTypeLoop {
+		return volume.CleanBricksLoop(&volinfo)
+	}
 	return volume.CleanBricksLvm(&volinfo)
 }
 
@@ -316,9 +324,16 @@ func registerSnapRestoreStepFuncs() {
 
 func snapshotRestoreHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
 	snapname := mux.Vars(r)["snapname"]
 
+	// The snapshot is restored to a new volume instead of its parent volume
+	// if a name is given for it
+	newVolname := r.URL.Query().Get("as-new-volume")
+	if newVolname != "" && !volume.IsValidName(newVolname) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrInvalidVolName)
+		return
+	}
+
 	snapinfo, err := snapshot.GetSnapshot(snapname)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
@@ -326,33 +341,114 @@ func snapshotRestoreHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	txn, err := transaction.NewTxnWithLocks(ctx, snapname, snapinfo.ParentVolume)
+	jobVolname := snapinfo.ParentVolume
+	if newVolname != "" {
+		jobVolname = newVolname
+	}
+
+	// The restore is run as a job, so that it is not interrupted if the
+	// client goes away and can be followed by clients asking for it to be
+	// run asynchronously
+	var (
+		vol        *volume.Volinfo
+		status     int
+		restoreErr error
+	)
+	job, err := jobs.Submit(ctx, api.JobTypeSnapshotRestore, jobVolname, func(ctx context.Context, h *jobs.Handle) error {
+		if newVolname != "" {
+			vol, status, restoreErr = restoreSnapshotAsNewVolume(ctx, snapname, newVolname)
+		} else {
+			vol, status, restoreErr = restoreSnapshot(ctx, snapname)
+		}
+		if restoreErr != nil {
+			return restoreErr
+		}
+		h.Logf("snapshot %s restored to volume %s", snapname, vol.Name)
+		if err := h.SetResult(volume.CreateVolumeInfoResp(vol)); err != nil {
+			gdctx.GetReqLogger(ctx).WithError(err).Warn("failed to set result of snapshot restore job")
+		}
+		return nil
+	})
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if restutils.IsAsyncRequest(r) {
+		restutils.SendJobAccepted(ctx, w, jobs.CreateJobInfoResp(job))
 		return
 	}
+
+	job, err = jobs.Wait(ctx, job.ID)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if restoreErr != nil {
+		restutils.SendHTTPError(ctx, w, status, restoreErr)
+		return
+	}
+	if job.State != api.JobDone {
+		// The job was cancelled before it could run
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, job.Error)
+		return
+	}
+
+	resp := volume.CreateVolumeInfoResp(vol)
+	restutils.SendHTTPResponse(ctx, w, status, resp)
+}
+
+// restoreSnapshotAsNewVolume creates a new volume from the bricks of the
+// snapshot and starts it, leaving the parent volume of the snapshot as it is
+func restoreSnapshotAsNewVolume(ctx context.Context, snapname, volname string) (*volume.Volinfo, int, error) {
+	vol, status, err := cloneSnapshot(ctx, snapname, volname)
+	if err != nil {
+		return nil, status, err
+	}
+
+	vol, status, err = volumecommands.StartVolume(ctx, vol.Name, api.VolumeStartReq{})
+	if err != nil {
+		return nil, status, fmt.Errorf("volume %s was created from snapshot %s, but failed to start: %s",
+			volname, snapname, err)
+	}
+	events.Broadcast(volume.NewEvent(volume.EventVolumeStarted, vol))
+
+	gdctx.GetReqLogger(ctx).WithFields(log.Fields{
+		"snapshot": snapname,
+		"volume":   volname,
+	}).Info("snapshot restored to new volume")
+	return vol, http.StatusCreated, nil
+}
+
+// restoreSnapshot restores the parent volume of a snapshot to the snapshot
+// and returns the restored volume
+func restoreSnapshot(ctx context.Context, snapname string) (*volume.Volinfo, int, error) {
+	logger := gdctx.GetReqLogger(ctx)
+
+	snapinfo, err := snapshot.GetSnapshot(snapname)
+	if err != nil {
+		return nil, restutils.ErrToStatusCode(err)
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, snapname, snapinfo.ParentVolume)
+	if err != nil {
+		return nil, restutils.ErrToStatusCode(err)
+	}
 	defer txn.Done()
 
 	//Fetching snapinfo again, but this time inside a lock
 	snapinfo, err = snapshot.GetSnapshot(snapname)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, restutils.ErrToStatusCode(err)
 	}
 	snapvolinfo := &snapinfo.SnapVolinfo
 
 	vol, err := volume.GetVolume(snapinfo.ParentVolume)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, restutils.ErrToStatusCode(err)
 	}
 	if vol.State == volume.VolStarted {
-		errMsg := fmt.Sprintf("Volume %s must be in stopped state before restoring.", vol.Name)
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errMsg)
-		return
+		return nil, http.StatusBadRequest, fmt.Errorf("volume %s must be in stopped state before restoring", vol.Name)
 	}
 
 	bricksAutoProvisioned := vol.IsAutoProvisioned() || vol.IsSnapshotProvisioned()
@@ -369,32 +465,29 @@ func snapshotRestoreHandler(w http.ResponseWriter, r *http.Request) {
 			Sync:     true,
 		},
 		{
-			DoFunc: "snap-restore.CleanBricks",
-			Nodes:  vol.Nodes(),
-			Skip:   !bricksAutoProvisioned,
+			DoFunc:  "snap-restore.CleanBricks",
+			Nodes:   vol.Nodes(),
+			Skip:    !bricksAutoProvisioned,
+			Timeout: transaction.BrickCleanTimeout,
 		},
 	}
 	if err = txn.Ctx.Set("snapname", snapname); err != nil {
 		logger.WithError(err).Error("failed to set request in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 	if err = txn.Ctx.Set("snapinfo", snapinfo); err != nil {
 		logger.WithError(err).Error("failed to set request in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if err = txn.Ctx.Set("volinfo", vol); err != nil {
 		logger.WithError(err).Error("failed to set request in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if err = txn.Do(); err != nil {
 		logger.WithError(err).Error("snapshot restore transaction failed")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	msg := fmt.Sprintf("Snapshot %s restored to volume %s", snapvolinfo.Name, vol.Name)
@@ -403,12 +496,8 @@ func snapshotRestoreHandler(w http.ResponseWriter, r *http.Request) {
 	//Get the updated volinfo
 	vol, err = volume.GetVolume(vol.Name)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, restutils.ErrToStatusCode(err)
 	}
 
-	resp := volume.CreateVolumeInfoResp(vol)
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
-
+	return vol, http.StatusOK, nil
 }
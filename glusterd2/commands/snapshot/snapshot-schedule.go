@@ -0,0 +1,44 @@
+package snapshotcommands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/gluster/glusterd2/glusterd2/scheduler"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+func validateScheduledSnapCreate(volname string, args json.RawMessage, recurring bool) error {
+	var req api.SnapCreateReq
+	if len(args) != 0 {
+		if err := json.Unmarshal(args, &req); err != nil {
+			return err
+		}
+	}
+	if req.SnapName == "" {
+		return errors.New("snapname is required")
+	}
+	// Snapshots of a recurring schedule need a unique name for each run
+	if recurring && !req.TimeStamp {
+		return errors.New("timestamp must be set for snapshots taken on a recurring schedule")
+	}
+	return nil
+}
+
+func runScheduledSnapCreate(ctx context.Context, volname string, args json.RawMessage) error {
+	var req api.SnapCreateReq
+	if len(args) != 0 {
+		if err := json.Unmarshal(args, &req); err != nil {
+			return err
+		}
+	}
+	req.VolName = volname
+
+	_, _, err := CreateSnapshot(ctx, &req, "")
+	return err
+}
+
+func registerSnapScheduleOps() {
+	scheduler.RegisterOp(api.ScheduleOpSnapshotCreate, validateScheduledSnapCreate, runScheduledSnapCreate)
+}
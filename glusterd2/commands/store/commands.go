@@ -0,0 +1,92 @@
+// Package storecommands implements the maintenance commands of the etcd
+// cluster embedded in glusterd2, and the reload of the configuration of an
+// external etcd cluster
+package storecommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "StoreMembers",
+			Method:       "GET",
+			Pattern:      "/store/members",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.StoreMembersResp)(nil)),
+			HandlerFunc:  membersHandler,
+			Role:         api.RoleAdmin,
+		},
+		route.Route{
+			Name:         "StoreDefrag",
+			Method:       "POST",
+			Pattern:      "/store/defrag",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.StoreDefragReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.StoreDefragResp)(nil)),
+			HandlerFunc:  defragHandler,
+			Role:         api.RoleAdmin,
+		},
+		route.Route{
+			Name:         "StoreCompact",
+			Method:       "POST",
+			Pattern:      "/store/compact",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.StoreCompactReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.StoreCompactResp)(nil)),
+			HandlerFunc:  compactHandler,
+			Role:         api.RoleAdmin,
+		},
+		route.Route{
+			Name:         "StoreAlarmList",
+			Method:       "GET",
+			Pattern:      "/store/alarms",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.StoreAlarmsResp)(nil)),
+			HandlerFunc:  alarmListHandler,
+			Role:         api.RoleAdmin,
+		},
+		route.Route{
+			Name:         "StoreAlarmClear",
+			Method:       "DELETE",
+			Pattern:      "/store/alarms",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.StoreAlarmsResp)(nil)),
+			HandlerFunc:  alarmClearHandler,
+			Role:         api.RoleAdmin,
+		},
+		route.Route{
+			Name:         "StoreMoveLeader",
+			Method:       "POST",
+			Pattern:      "/store/leader",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.StoreLeaderReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.StoreLeaderResp)(nil)),
+			HandlerFunc:  moveLeaderHandler,
+			Role:         api.RoleAdmin,
+		},
+		route.Route{
+			Name:         "StoreReload",
+			Method:       "POST",
+			Pattern:      "/store/reload",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.StoreConfigResp)(nil)),
+			HandlerFunc:  reloadHandler,
+			Role:         api.RoleAdmin,
+		},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	transaction.RegisterStepFunc(reloadStore, "store.Reload")
+}
@@ -0,0 +1,70 @@
+package storecommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// reloadStore applies the changes made to the configuration of the external
+// etcd cluster in the config file of the peer
+func reloadStore(c transaction.TxnCtx) error {
+	if err := store.Store.Reload(); err != nil {
+		c.Logger().WithError(err).Error("failed to reload store configuration")
+		return err
+	}
+	return nil
+}
+
+// reloadHandler reloads the configuration of the external etcd cluster on
+// all the peers, after their config files have been changed
+func reloadHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	if store.Store.IsEmbedded() {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, store.ErrStoreEmbedded)
+		return
+	}
+
+	allNodes, err := peer.GetPeerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "store.Reload",
+			Nodes:  allNodes,
+		},
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).Error("store reload transaction failed")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.Info("reloaded store configuration on all peers")
+	conf := store.Store.Config()
+	resp := &api.StoreConfigResp{
+		PeerID:    gdctx.MyUUID.String(),
+		Endpoints: conf.Endpoints,
+		UseTLS:    conf.UseTLS,
+	}
+	if conf.UseTLS {
+		resp.ClientCertFile = conf.ClntCertFile
+		resp.ClientCAFile = conf.ClntCAFile
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -0,0 +1,338 @@
+package storecommands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/etcdserver/api/v3rpc/rpctypes"
+	"github.com/coreos/etcd/etcdserver/etcdserverpb"
+)
+
+const (
+	statusTimeout = 5 * time.Second
+	// defragmentation blocks the member and takes time with large
+	// databases
+	defragTimeout  = 60 * time.Second
+	requestTimeout = 30 * time.Second
+)
+
+// memberID formats the ID of a member like etcdctl does
+func memberID(id uint64) string {
+	return strconv.FormatUint(id, 16)
+}
+
+// checkEmbedded sends an error and returns false if the store is not the
+// embedded etcd. An external etcd cluster is maintained by its operators.
+func checkEmbedded(ctx context.Context, w http.ResponseWriter) bool {
+	if !store.Store.IsEmbedded() {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, gderrors.ErrStoreNotEmbedded)
+		return false
+	}
+	return true
+}
+
+// findMember returns the member with the given name or ID, or nil
+func findMember(members []*etcdserverpb.Member, nameOrID string) *etcdserverpb.Member {
+	for _, m := range members {
+		if m.Name == nameOrID || memberID(m.ID) == nameOrID {
+			return m
+		}
+	}
+	return nil
+}
+
+// selectMembers returns the members with the given names or IDs, or all the
+// members if none are given
+func selectMembers(members []*etcdserverpb.Member, names []string) ([]*etcdserverpb.Member, error) {
+	if len(names) == 0 {
+		return members, nil
+	}
+
+	selected := make([]*etcdserverpb.Member, 0, len(names))
+	for _, name := range names {
+		m := findMember(members, name)
+		if m == nil {
+			return nil, fmt.Errorf("%s: %s", gderrors.ErrStoreMemberNotFound, name)
+		}
+		selected = append(selected, m)
+	}
+	return selected, nil
+}
+
+// memberStatus asks the member for its status
+func memberStatus(ctx context.Context, m *etcdserverpb.Member) api.StoreMember {
+	sm := api.StoreMember{
+		ID:         memberID(m.ID),
+		Name:       m.Name,
+		PeerURLs:   m.PeerURLs,
+		ClientURLs: m.ClientURLs,
+	}
+	if len(m.ClientURLs) == 0 {
+		sm.Error = "member has not started"
+		return sm
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, statusTimeout)
+	defer cancel()
+	status, err := store.Store.Client.Status(ctx, m.ClientURLs[0])
+	if err != nil {
+		sm.Error = err.Error()
+		return sm
+	}
+	sm.Healthy = true
+	sm.Leader = status.Leader == m.ID
+	sm.Version = status.Version
+	sm.DBSize = status.DbSize
+	sm.RaftIndex = status.RaftIndex
+	sm.RaftTerm = status.RaftTerm
+	return sm
+}
+
+func membersHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !checkEmbedded(ctx, w) {
+		return
+	}
+
+	members, err := store.Store.Client.MemberList(ctx)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := make(api.StoreMembersResp, 0, len(members.Members))
+	for _, m := range members.Members {
+		resp = append(resp, memberStatus(ctx, m))
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// defragment defragments the database of the member, one member at a time as
+// the member can't serve requests while it is defragmented
+func defragment(ctx context.Context, m *etcdserverpb.Member) api.StoreDefragResult {
+	result := api.StoreDefragResult{Name: m.Name}
+	before := memberStatus(ctx, m)
+	if !before.Healthy {
+		result.Error = before.Error
+		return result
+	}
+	result.DBSizeBefore = before.DBSize
+
+	dctx, cancel := context.WithTimeout(ctx, defragTimeout)
+	defer cancel()
+	if _, err := store.Store.Client.Defragment(dctx, m.ClientURLs[0]); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	after := memberStatus(ctx, m)
+	result.DBSizeAfter = after.DBSize
+	result.Error = after.Error
+	return result
+}
+
+func defragHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	if !checkEmbedded(ctx, w) {
+		return
+	}
+
+	var req api.StoreDefragReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil && err != io.EOF {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	members, err := store.Store.Client.MemberList(ctx)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	selected, err := selectMembers(members.Members, req.Members)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+
+	resp := make(api.StoreDefragResp, 0, len(selected))
+	for _, m := range selected {
+		result := defragment(ctx, m)
+		logger.WithField("member", result.Name).WithField("db-size-before", result.DBSizeBefore).
+			WithField("db-size-after", result.DBSizeAfter).WithField("error", result.Error).
+			Info("store member defragmented")
+		resp = append(resp, result)
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// compactErrStatus returns the status sent when compacting fails. Revisions
+// which are already compacted or not created yet are errors of the request.
+func compactErrStatus(err error) int {
+	if err == rpctypes.ErrCompacted || err == rpctypes.ErrFutureRev {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func compactHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	if !checkEmbedded(ctx, w) {
+		return
+	}
+
+	var req api.StoreCompactReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil && err != io.EOF {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	rev := req.Revision
+	if rev == 0 {
+		resp, err := store.Get(ctx, "health")
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		rev = resp.Header.Revision
+	}
+
+	// Physical compaction returns once the compacted revisions have been
+	// removed from the database, so that a defragmentation after it
+	// releases their space
+	if _, err := store.Store.Client.Compact(ctx, rev, clientv3.WithCompactPhysical()); err != nil {
+		restutils.SendHTTPError(ctx, w, compactErrStatus(err), err)
+		return
+	}
+
+	logger.WithField("revision", rev).Info("store compacted")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.StoreCompactResp{Revision: rev})
+}
+
+// createAlarmsResp names the members of the alarms
+func createAlarmsResp(ctx context.Context, alarms []*etcdserverpb.AlarmMember) api.StoreAlarmsResp {
+	names := make(map[uint64]string)
+	if members, err := store.Store.Client.MemberList(ctx); err == nil {
+		for _, m := range members.Members {
+			names[m.ID] = m.Name
+		}
+	}
+
+	resp := make(api.StoreAlarmsResp, 0, len(alarms))
+	for _, a := range alarms {
+		resp = append(resp, api.StoreAlarm{
+			MemberID: memberID(a.MemberID),
+			Name:     names[a.MemberID],
+			Alarm:    a.Alarm.String(),
+		})
+	}
+	return resp
+}
+
+func alarmListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !checkEmbedded(ctx, w) {
+		return
+	}
+
+	alarms, err := store.Store.Client.AlarmList(ctx)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createAlarmsResp(ctx, alarms.Alarms))
+}
+
+func alarmClearHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	if !checkEmbedded(ctx, w) {
+		return
+	}
+
+	// Disarming without a member and an alarm disarms all the alarms
+	alarms, err := store.Store.Client.AlarmDisarm(ctx, &clientv3.AlarmMember{})
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	logger.WithField("alarms", len(alarms.Alarms)).Info("store alarms cleared")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createAlarmsResp(ctx, alarms.Alarms))
+}
+
+func moveLeaderHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	if !checkEmbedded(ctx, w) {
+		return
+	}
+
+	var req api.StoreLeaderReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+	if req.Member == "" {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrStoreMemberRequired)
+		return
+	}
+
+	members, err := store.Store.Client.MemberList(ctx)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	transferee := findMember(members.Members, req.Member)
+	if transferee == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, gderrors.ErrStoreMemberNotFound)
+		return
+	}
+
+	var leader *etcdserverpb.Member
+	for _, m := range members.Members {
+		if memberStatus(ctx, m).Leader {
+			leader = m
+			break
+		}
+	}
+	if leader == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusServiceUnavailable, rpctypes.ErrNoLeader)
+		return
+	}
+
+	if leader.ID != transferee.ID {
+		// The leadership can only be moved by the leader
+		cli, err := store.Store.NewMemberClient(leader.ClientURLs)
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		defer cli.Close()
+
+		mctx, cancel := context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+		if _, err := cli.MoveLeader(mctx, transferee.ID); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		logger.WithField("from", leader.Name).WithField("to", transferee.Name).Info("store leadership moved")
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.StoreLeaderResp{Leader: transferee.Name})
+}
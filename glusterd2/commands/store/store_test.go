@@ -0,0 +1,87 @@
+package storecommands
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/etcdserver/api/v3rpc/rpctypes"
+	"github.com/coreos/etcd/etcdserver/etcdserverpb"
+	"github.com/stretchr/testify/assert"
+)
+
+var testMembers = []*etcdserverpb.Member{
+	{ID: 0x8e9e05c52164694d, Name: "peer1", ClientURLs: []string{"http://peer1:2379"}},
+	{ID: 0x91bc3c398fb3c146, Name: "peer2", ClientURLs: []string{"http://peer2:2379"}},
+	{ID: 0xfd422379fda50e48, Name: ""},
+}
+
+func TestMemberID(t *testing.T) {
+	assert.Equal(t, "8e9e05c52164694d", memberID(0x8e9e05c52164694d))
+	assert.Equal(t, "1", memberID(1))
+}
+
+func TestFindMember(t *testing.T) {
+	tests := []struct {
+		nameOrID string
+		member   *etcdserverpb.Member
+	}{
+		{"peer1", testMembers[0]},
+		{"91bc3c398fb3c146", testMembers[1]},
+		{"fd422379fda50e48", testMembers[2]},
+		{"peer3", nil},
+		{"8E9E05C52164694D", nil},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.member, findMember(testMembers, tt.nameOrID), tt.nameOrID)
+	}
+}
+
+func TestSelectMembers(t *testing.T) {
+	tests := []struct {
+		names    []string
+		selected []*etcdserverpb.Member
+		valid    bool
+	}{
+		{nil, testMembers, true},
+		{[]string{"peer2"}, testMembers[1:2], true},
+		{[]string{"peer2", "8e9e05c52164694d"}, []*etcdserverpb.Member{testMembers[1], testMembers[0]}, true},
+		{[]string{"peer1", "peer3"}, nil, false},
+	}
+
+	for _, tt := range tests {
+		selected, err := selectMembers(testMembers, tt.names)
+		if !tt.valid {
+			assert.NotNil(t, err, "%v", tt.names)
+			assert.Contains(t, err.Error(), gderrors.ErrStoreMemberNotFound.Error())
+			continue
+		}
+		assert.Nil(t, err, "%v", tt.names)
+		assert.Equal(t, tt.selected, selected, "%v", tt.names)
+	}
+}
+
+func TestCompactErrStatus(t *testing.T) {
+	assert.Equal(t, http.StatusBadRequest, compactErrStatus(rpctypes.ErrCompacted))
+	assert.Equal(t, http.StatusBadRequest, compactErrStatus(rpctypes.ErrFutureRev))
+	assert.Equal(t, http.StatusInternalServerError, compactErrStatus(errors.New("etcdserver: request timed out")))
+}
+
+func TestMemberNotStarted(t *testing.T) {
+	// A member which has not started has no client URL to ask for its
+	// status, and is not defragmented
+	m := testMembers[2]
+
+	status := memberStatus(context.Background(), m)
+	assert.Equal(t, "fd422379fda50e48", status.ID)
+	assert.False(t, status.Healthy)
+	assert.Equal(t, "member has not started", status.Error)
+
+	result := defragment(context.Background(), m)
+	assert.Equal(t, "member has not started", result.Error)
+	assert.Equal(t, int64(0), result.DBSizeBefore)
+}
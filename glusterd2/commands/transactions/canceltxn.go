@@ -0,0 +1,37 @@
+package txncommands
+
+import (
+	"net/http"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+func cancelTxnHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := uuid.Parse(mux.Vars(r)["txnid"])
+	if id == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Invalid transaction id passed")
+		return
+	}
+
+	err := transaction.CancelTxn(id)
+	if err == errors.ErrTxnNotFound {
+		err = transactionv2.CancelTxn(id)
+	}
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	// The transaction is rolled back and its locks are released
+	// asynchronously by the peer which initiated it
+	restutils.SendHTTPResponse(ctx, w, http.StatusAccepted, nil)
+}
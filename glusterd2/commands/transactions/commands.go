@@ -0,0 +1,63 @@
+// Package txncommands implements the commands to look at the progress of
+// transactions and the locks held by them
+package txncommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/middleware"
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "GetTransactions",
+			Method:       "GET",
+			Pattern:      "/transactions",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.TxnListResp)(nil)),
+			HandlerFunc:  getTxnsHandler,
+		},
+		route.Route{
+			Name:         "GetTransaction",
+			Method:       "GET",
+			Pattern:      "/transactions/{txnid}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.TxnGetResp)(nil)),
+			HandlerFunc:  getTxnHandler,
+		},
+		route.Route{
+			Name:        "CancelTransaction",
+			Method:      "POST",
+			Pattern:     "/transactions/{txnid}/cancel",
+			Version:     1,
+			HandlerFunc: cancelTxnHandler,
+		},
+		route.Route{
+			Name:         "GetLocks",
+			Method:       "GET",
+			Pattern:      "/locks",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.LockListResp)(nil)),
+			HandlerFunc:  getLocksHandler,
+		},
+		route.Route{
+			Name:        "ForceUnlock",
+			Method:      "DELETE",
+			Pattern:     "/locks/{name}",
+			Version:     1,
+			HandlerFunc: middleware.AdminOnly(forceUnlockHandler),
+		},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
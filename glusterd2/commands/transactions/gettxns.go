@@ -0,0 +1,59 @@
+package txncommands
+
+import (
+	"net/http"
+	"sort"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+func getTxnsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	txns, err := transaction.GetTxnRecords()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Transactions run by the new transaction engine are tracked in the
+	// pending-transaction namespace until they succeed or are rolled back
+	txns = append(txns, transactionv2.GetTxnInfos()...)
+
+	sort.Slice(txns, func(i, j int) bool {
+		return txns[i].StartTime.After(txns[j].StartTime)
+	})
+
+	resp := api.TxnListResp(txns)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func getTxnHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id := uuid.Parse(mux.Vars(r)["txnid"])
+	if id == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Invalid transaction id passed")
+		return
+	}
+
+	txn, err := transaction.GetTxnRecord(id)
+	if err == errors.ErrTxnNotFound {
+		txn, err = transactionv2.GetTxnInfo(id)
+	}
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := (*api.TxnGetResp)(txn)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
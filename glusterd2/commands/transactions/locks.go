@@ -0,0 +1,40 @@
+package txncommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/gorilla/mux"
+)
+
+func getLocksHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	locks, err := transaction.GetLocks()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := api.LockListResp(locks)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func forceUnlockHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	name := mux.Vars(r)["name"]
+
+	if err := transaction.ForceUnlock(name); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithField("lock", name).Warn("lock force released on request")
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
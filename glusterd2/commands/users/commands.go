@@ -0,0 +1,78 @@
+// Package usercommands implements the commands to manage the users of the
+// REST API and their roles
+package usercommands
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Command is a holding struct used to implement the GlusterD Command interface
+type Command struct {
+}
+
+// Routes returns command routes. Required for the Command interface.
+func (c *Command) Routes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "UserCreate",
+			Method:       "POST",
+			Pattern:      "/users",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.UserCreateReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.UserCreateResp)(nil)),
+			HandlerFunc:  userCreateHandler,
+			Role:         api.RoleAdmin,
+		},
+		route.Route{
+			Name:         "UserList",
+			Method:       "GET",
+			Pattern:      "/users",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.UserListResp)(nil)),
+			HandlerFunc:  userListHandler,
+			Role:         api.RoleAdmin,
+		},
+		route.Route{
+			Name:         "UserInfo",
+			Method:       "GET",
+			Pattern:      "/users/{username}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.UserGetResp)(nil)),
+			HandlerFunc:  userGetHandler,
+			Role:         api.RoleAdmin,
+		},
+		route.Route{
+			Name:         "UserEdit",
+			Method:       "POST",
+			Pattern:      "/users/{username}/edit",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.UserEditReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.UserEditResp)(nil)),
+			HandlerFunc:  userEditHandler,
+			Role:         api.RoleAdmin,
+		},
+		route.Route{
+			Name:        "UserDelete",
+			Method:      "DELETE",
+			Pattern:     "/users/{username}",
+			Version:     1,
+			HandlerFunc: userDeleteHandler,
+			Role:        api.RoleAdmin,
+		},
+		route.Route{
+			Name:         "RoleList",
+			Method:       "GET",
+			Pattern:      "/roles",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.RoleListResp)(nil)),
+			HandlerFunc:  roleListHandler,
+		},
+	}
+}
+
+// RegisterStepFuncs implements a required function for the Command interface
+func (c *Command) RegisterStepFuncs() {
+	return
+}
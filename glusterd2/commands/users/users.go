@@ -0,0 +1,121 @@
+package usercommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/users"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+func userCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.UserCreateReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	u, err := users.NewUser(&req)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := users.AddUser(u); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithField("user", u.Name).WithField("role", u.Role).Info("user added")
+
+	resp := &api.UserCreateResp{
+		UserInfo: *users.CreateUserInfoResp(u),
+		Secret:   u.Secret,
+	}
+	restutils.SetLocationHeader(r, w, u.Name)
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
+}
+
+func userListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	all, err := users.GetUsers()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := make(api.UserListResp, len(all))
+	for i, u := range all {
+		resp[i] = *users.CreateUserInfoResp(u)
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func userGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	u, err := users.GetUser(mux.Vars(r)["username"])
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := (*api.UserGetResp)(users.CreateUserInfoResp(u))
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func userEditHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.UserEditReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	u, err := users.EditUser(mux.Vars(r)["username"], &req)
+	if err == gderrors.ErrInvalidRole || err == gderrors.ErrInvalidNamespace {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithField("user", u.Name).WithField("role", u.Role).Info("user changed")
+
+	resp := (*api.UserEditResp)(users.CreateUserInfoResp(u))
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func userDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	name := mux.Vars(r)["username"]
+	if err := users.DeleteUser(name); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithField("user", name).Info("user deleted")
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+func roleListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.RoleListResp(users.Roles()))
+}
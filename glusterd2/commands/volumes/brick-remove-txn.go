@@ -0,0 +1,167 @@
+package volumecommands
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/pborman/uuid"
+)
+
+// decommissionBricks marks the requested bricks of the volume as
+// decommissioned. Bricks of a lone distribute subvol can be removed one by
+// one, otherwise only whole subvols can be removed as the subvols are the
+// children of the distribute xlator.
+func decommissionBricks(volinfo *volume.Volinfo, bricks []api.BrickReq) error {
+	for _, req := range bricks {
+		found := false
+		for sidx := range volinfo.Subvols {
+			for bidx, b := range volinfo.Subvols[sidx].Bricks {
+				if b.PeerID.String() == req.PeerID && b.Path == filepath.Clean(req.Path) {
+					volinfo.Subvols[sidx].Bricks[bidx].Decommissioned = true
+					found = true
+				}
+			}
+		}
+		if !found {
+			return gderrors.ErrBrickNotInVolume
+		}
+	}
+
+	brickChildren := len(volinfo.Subvols) == 1 && volinfo.Subvols[0].Type == volume.SubvolDistribute
+
+	remaining := 0
+	for _, sv := range volinfo.Subvols {
+		decommissioned := 0
+		for _, b := range sv.Bricks {
+			if b.Decommissioned {
+				decommissioned++
+			}
+		}
+
+		if brickChildren {
+			remaining += len(sv.Bricks) - decommissioned
+			continue
+		}
+
+		if decommissioned != 0 && decommissioned != len(sv.Bricks) {
+			return gderrors.ErrRemoveBrickPartialSubvol
+		}
+		if decommissioned == 0 {
+			remaining++
+		}
+	}
+
+	if remaining == 0 {
+		return gderrors.ErrRemoveBrickAllBricks
+	}
+
+	return nil
+}
+
+// recommissionBricks clears the decommissioned flag on all the bricks of the
+// volume
+func recommissionBricks(volinfo *volume.Volinfo) {
+	for sidx := range volinfo.Subvols {
+		for bidx := range volinfo.Subvols[sidx].Bricks {
+			volinfo.Subvols[sidx].Bricks[bidx].Decommissioned = false
+		}
+	}
+	delete(volinfo.Metadata, volume.RemoveBrickRebalanceID)
+}
+
+// removeDecommissionedBricks drops the decommissioned bricks from the volume
+// and returns them. The remaining subvols are renamed after their new
+// position, which is what the volfiles name them after.
+func removeDecommissionedBricks(volinfo *volume.Volinfo) []brick.Brickinfo {
+	var removed []brick.Brickinfo
+	var subvols []volume.Subvol
+
+	for _, sv := range volinfo.Subvols {
+		var bricks []brick.Brickinfo
+		for _, b := range sv.Bricks {
+			if b.Decommissioned {
+				removed = append(removed, b)
+				continue
+			}
+			bricks = append(bricks, b)
+		}
+		if len(bricks) == 0 {
+			continue
+		}
+		sv.Bricks = bricks
+		subvols = append(subvols, sv)
+	}
+
+	for idx := range subvols {
+		subvols[idx].Name = fmt.Sprintf("%s-%s-%d", volinfo.Name, strings.ToLower(subvols[idx].Type.String()), idx)
+	}
+
+	volinfo.Subvols = subvols
+	volinfo.DistCount = len(subvols)
+
+	if len(subvols) == 1 {
+		switch volinfo.Type {
+		case volume.DistReplicate:
+			volinfo.Type = volume.Replicate
+		case volume.DistDisperse:
+			volinfo.Type = volume.Disperse
+		}
+	}
+
+	delete(volinfo.Metadata, volume.RemoveBrickRebalanceID)
+
+	return removed
+}
+
+func stopRemovedBricks(c transaction.TxnCtx) error {
+	var bricks []brick.Brickinfo
+	if err := c.Get("removed-bricks", &bricks); err != nil {
+		return err
+	}
+
+	for _, b := range bricks {
+		if !uuid.Equal(b.PeerID, gdctx.MyUUID) {
+			continue
+		}
+		if err := stopRetiredBrick(c, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func cleanRemovedBricks(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	var bricks []brick.Brickinfo
+	if err := c.Get("removed-bricks", &bricks); err != nil {
+		return err
+	}
+
+	// Only the removed bricks are passed so that no other brick of the
+	// volume on this peer gets cleaned
+	removed := &volume.Volinfo{
+		ID:              volinfo.ID,
+		Name:            volinfo.Name,
+		ProvisionerType: volinfo.ProvisionerType,
+		Subvols:         []volume.Subvol{{Bricks: bricks}},
+	}
+
+	if volinfo.ProvisionerType == api.ProvisionerTypeLoop {
+		return volume.CleanBricksLoop(removed)
+	}
+
+	return volume.CleanBricksLvm(removed)
+}
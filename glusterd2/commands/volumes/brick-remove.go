@@ -0,0 +1,613 @@
+package volumecommands
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/plugins/rebalance"
+	rebalanceapi "github.com/gluster/glusterd2/plugins/rebalance/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	"go.opencensus.io/trace"
+)
+
+// Statuses of the data migration of a remove brick operation
+const (
+	RemoveBrickInProgress = "in progress"
+	RemoveBrickCompleted  = "completed"
+	RemoveBrickStopped    = "stopped"
+	RemoveBrickFailed     = "failed"
+)
+
+func registerRemoveBrickStepFuncs() {
+	var sfs = []struct {
+		name string
+		sf   transaction.StepFunc
+	}{
+		{"brick-remove.StoreVolume", storeVolume},
+		{"brick-remove.StoreVolume.Undo", undoStoreVolume},
+		{"brick-remove.StopBricks", stopRemovedBricks},
+		{"brick-remove.CleanBricks", cleanRemovedBricks},
+	}
+	for _, sf := range sfs {
+		transaction.RegisterStepFunc(sf.sf, sf.name)
+	}
+}
+
+// getRemoveBrickRebalanceInfo returns the info of the rebalance which
+// migrates data off the decommissioned bricks of the volume
+func getRemoveBrickRebalanceInfo(volinfo *volume.Volinfo) (*rebalanceapi.RebalInfo, error) {
+	rinfo, err := rebalance.GetRebalanceInfo(volinfo.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if rinfo.RebalanceID.String() != volinfo.Metadata[volume.RemoveBrickRebalanceID] {
+		return nil, gderrors.ErrRemoveBrickNotStarted
+	}
+
+	return rinfo, nil
+}
+
+// removeBrickMigrationFailed returns true if any node failed to migrate
+// some of the files off the decommissioned bricks
+func removeBrickMigrationFailed(rinfo *rebalanceapi.RebalInfo) bool {
+	for _, s := range rinfo.RebalStats {
+		if failures, err := strconv.Atoi(s.RebalanceFailures); err == nil && failures != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func bricksString(bricks []brick.Brickinfo) string {
+	var s []string
+	for _, b := range bricks {
+		s = append(s, b.String())
+	}
+	return strings.Join(s, ",")
+}
+
+func volumeRemoveBrickStartHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, span := trace.StartSpan(ctx, "/volumeRemoveBrickStartHandler")
+	defer span.End()
+
+	volname := mux.Vars(r)["volname"]
+
+	var req api.RemoveBrickReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	volinfo, rebalanceID, status, err := RemoveBrickStart(ctx, volname, req)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := &api.RemoveBrickStartResp{
+		VolumeInfo:  *volume.CreateVolumeInfoResp(volinfo),
+		RebalanceID: rebalanceID,
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// RemoveBrickStart decommissions bricks of a volume and starts migrating
+// their data on to the other bricks of the volume. It returns the updated
+// volume and the ID of the rebalance which migrates the data.
+func RemoveBrickStart(ctx context.Context, volname string, req api.RemoveBrickReq) (*volume.Volinfo, uuid.UUID, int, error) {
+	span := trace.FromContext(ctx)
+	logger := gdctx.GetReqLogger(ctx)
+
+	if len(req.Bricks) == 0 {
+		return nil, nil, http.StatusBadRequest, gderrors.ErrEmptyBrickList
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		return nil, nil, status, err
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		return nil, nil, status, err
+	}
+
+	if volinfo.IsDeletionProtected() {
+		return nil, nil, http.StatusForbidden, gderrors.ErrVolDeletionProtected
+	}
+
+	// Data is migrated off the bricks by the rebalance process, which
+	// needs the volume to be started
+	if volinfo.State != volume.VolStarted {
+		return nil, nil, http.StatusBadRequest, gderrors.ErrVolNotStarted
+	}
+
+	if len(volinfo.DecommissionedBricks()) != 0 {
+		return nil, nil, http.StatusConflict, gderrors.ErrRemoveBrickInProgress
+	}
+
+	if rinfo, err := rebalance.GetRebalanceInfo(volname); err == nil && rinfo.State == rebalanceapi.Started {
+		return nil, nil, http.StatusConflict, rebalance.ErrRebalanceInProgress
+	}
+
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+
+	if err := decommissionBricks(volinfo, req.Bricks); err != nil {
+		return nil, nil, http.StatusBadRequest, err
+	}
+
+	// Files are migrated with the rebalance start force command, like
+	// glusterd does, so that they are moved even if the destination brick
+	// has less free space than the decommissioned one
+	rinfo := rebalance.NewRebalanceInfo(volname, &rebalanceapi.StartReq{Option: "force"})
+
+	if volinfo.Metadata == nil {
+		volinfo.Metadata = make(map[string]string)
+	}
+	volinfo.Metadata[volume.RemoveBrickRebalanceID] = rinfo.RebalanceID.String()
+
+	allNodes, err := peer.GetPeerIDs()
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+
+	// The volinfo with the decommissioned bricks is stored before starting
+	// the rebalance processes as they fetch the rebalance volfile
+	// generated from it
+	txn.Nodes = allNodes
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "brick-remove.StoreVolume",
+			UndoFunc: "brick-remove.StoreVolume.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Sync:     true,
+		},
+	}
+	txn.Steps = append(txn.Steps, rebalance.StartSteps(volinfo.Nodes())...)
+	txn.Steps = append(txn.Steps, &transaction.Step{
+		DoFunc: "vol-expand.NotifyClients",
+		Nodes:  allNodes,
+	})
+
+	if err := txn.Ctx.Set("volname", volname); err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+	if err := txn.Ctx.Set("rinfo", rinfo); err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+
+	bricksToRemove := bricksString(volinfo.DecommissionedBricks())
+
+	span.AddAttributes(
+		trace.StringAttribute("reqID", txn.Ctx.GetTxnReqID()),
+		trace.StringAttribute("volName", volname),
+		trace.StringAttribute("bricksToRemove", bricksToRemove),
+	)
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volume-name", volname).Error("remove-brick start transaction failed")
+		status, err := restutils.ErrToStatusCode(err)
+		return nil, nil, status, err
+	}
+
+	recordVolumeHistory(ctx, volname, volume.EventRemoveBrickStarted, txn.Ctx.GetTxnID(), map[string]string{
+		"bricks":       bricksToRemove,
+		"rebalance-id": rinfo.RebalanceID.String(),
+	})
+
+	logger.WithField("volume-name", volname).Info("remove-brick started")
+	events.Broadcast(volume.NewEvent(volume.EventRemoveBrickStarted, volinfo))
+
+	return volinfo, rinfo.RebalanceID, http.StatusOK, nil
+}
+
+func volumeRemoveBrickStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if len(volinfo.DecommissionedBricks()) == 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, gderrors.ErrRemoveBrickNotStarted)
+		return
+	}
+
+	rinfo, err := getRemoveBrickRebalanceInfo(volinfo)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Nodes = volinfo.Nodes()
+	txn.Steps = rebalance.StatusSteps(txn.Nodes)
+
+	if err := txn.Ctx.Set("volname", volname); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := txn.Ctx.Set("rinfo", rinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Nodes on which the migration has completed don't have a process to
+	// query, their status is taken from the rebalance info
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volume-name", volname).Error("failed to query remove-brick status")
+	}
+
+	rstatus, err := rebalance.CreateRebalanceStatusResp(txn.Ctx, volinfo)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := createRemoveBrickStatusResp(volinfo, rinfo, rstatus)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// removeBrickStatus returns the status of the data migration of a remove
+// brick operation
+func removeBrickStatus(rinfo *rebalanceapi.RebalInfo) string {
+	switch rinfo.State {
+	case rebalanceapi.Complete:
+		if removeBrickMigrationFailed(rinfo) {
+			return RemoveBrickFailed
+		}
+		return RemoveBrickCompleted
+	case rebalanceapi.Stopped:
+		return RemoveBrickStopped
+	case rebalanceapi.Failed:
+		return RemoveBrickFailed
+	default:
+		return RemoveBrickInProgress
+	}
+}
+
+// RemoveBrickStatus returns the status of the data migration of the remove
+// brick operation in progress on a volume
+func RemoveBrickStatus(volname string) (string, error) {
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		return "", err
+	}
+	if len(volinfo.DecommissionedBricks()) == 0 {
+		return "", gderrors.ErrRemoveBrickNotStarted
+	}
+	rinfo, err := getRemoveBrickRebalanceInfo(volinfo)
+	if err != nil {
+		return "", err
+	}
+	return removeBrickStatus(rinfo), nil
+}
+
+func createRemoveBrickStatusResp(volinfo *volume.Volinfo, rinfo *rebalanceapi.RebalInfo, rstatus *rebalanceapi.RebalStatus) *api.RemoveBrickStatusResp {
+	resp := &api.RemoveBrickStatusResp{
+		Volname:     volinfo.Name,
+		RebalanceID: rinfo.RebalanceID,
+	}
+
+	resp.Status = removeBrickStatus(rinfo)
+
+	for _, b := range volinfo.DecommissionedBricks() {
+		resp.Bricks = append(resp.Bricks, brick.CreateBrickInfo(&b))
+	}
+
+	for _, n := range rstatus.Nodes {
+		resp.Nodes = append(resp.Nodes, api.RemoveBrickNodeStatus{
+			PeerID:        n.PeerID,
+			Status:        n.Status,
+			MigratedFiles: n.RebalancedFiles,
+			MigratedSize:  n.RebalancedSize,
+			ScannedFiles:  n.LookedupFiles,
+			SkippedFiles:  n.SkippedFiles,
+			Failures:      n.RebalanceFailures,
+			ElapsedTime:   n.ElapsedTime,
+			TimeLeft:      n.TimeLeft,
+		})
+	}
+
+	return resp
+}
+
+func volumeRemoveBrickCommitHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, span := trace.StartSpan(ctx, "/volumeRemoveBrickCommitHandler")
+	defer span.End()
+
+	volname := mux.Vars(r)["volname"]
+
+	var req api.RemoveBrickCommitReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil && err != io.EOF {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	volinfo, status, err := RemoveBrickCommit(ctx, volname, req)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := (*api.RemoveBrickCommitResp)(volume.CreateVolumeInfoResp(volinfo))
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// RemoveBrickCommit removes the decommissioned bricks from the volume once
+// their data has been migrated, or right away if forced, and returns the
+// updated volume
+func RemoveBrickCommit(ctx context.Context, volname string, req api.RemoveBrickCommitReq) (*volume.Volinfo, int, error) {
+	span := trace.FromContext(ctx)
+	logger := gdctx.GetReqLogger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		return nil, restutils.ErrToStatusCode(err)
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		return nil, restutils.ErrToStatusCode(err)
+	}
+
+	if volinfo.IsDeletionProtected() {
+		return nil, http.StatusForbidden, gderrors.ErrVolDeletionProtected
+	}
+
+	if len(volinfo.DecommissionedBricks()) == 0 {
+		return nil, http.StatusBadRequest, gderrors.ErrRemoveBrickNotStarted
+	}
+
+	rinfo, err := getRemoveBrickRebalanceInfo(volinfo)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Committing before all the data is migrated loses the files left on
+	// the removed bricks, which is only done when forced
+	if !req.Force {
+		if rinfo.State != rebalanceapi.Complete {
+			return nil, http.StatusBadRequest, gderrors.ErrRemoveBrickNotComplete
+		}
+		if removeBrickMigrationFailed(rinfo) {
+			return nil, http.StatusBadRequest, gderrors.ErrRemoveBrickMigrationFailed
+		}
+	}
+
+	migrationRunning := rinfo.State == rebalanceapi.Started
+	rebalanceNodes := volinfo.Nodes()
+
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	removed := removeDecommissionedBricks(volinfo)
+
+	var removedNodes []uuid.UUID
+	seen := make(map[string]bool)
+	for _, b := range removed {
+		if !seen[b.PeerID.String()] {
+			seen[b.PeerID.String()] = true
+			removedNodes = append(removedNodes, b.PeerID)
+		}
+	}
+
+	allNodes, err := peer.GetPeerIDs()
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	// Clients are notified of the new volfile before the removed bricks
+	// are stopped so that they don't see the bricks go down
+	txn.Nodes = allNodes
+	txn.Steps = rebalance.StopSteps(rebalanceNodes)
+	for _, step := range txn.Steps {
+		step.Skip = !migrationRunning
+	}
+	txn.Steps = append(txn.Steps,
+		&transaction.Step{
+			DoFunc:   "brick-remove.StoreVolume",
+			UndoFunc: "brick-remove.StoreVolume.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Sync:     true,
+		},
+		&transaction.Step{
+			DoFunc: "vol-expand.NotifyClients",
+			Nodes:  allNodes,
+		},
+		&transaction.Step{
+			DoFunc: "brick-remove.StopBricks",
+			Nodes:  removedNodes,
+		},
+		&transaction.Step{
+			DoFunc:  "brick-remove.CleanBricks",
+			Nodes:   removedNodes,
+			Skip:    !volinfo.IsAutoProvisioned(),
+			Timeout: transaction.BrickCleanTimeout,
+		},
+	)
+
+	rinfo.State = rebalanceapi.Stopped
+	rinfo.Cmd = rebalanceapi.CmdStop
+
+	if err := txn.Ctx.Set("volname", volname); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if err := txn.Ctx.Set("rinfo", rinfo); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if err := txn.Ctx.Set("removed-bricks", removed); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	span.AddAttributes(
+		trace.StringAttribute("reqID", txn.Ctx.GetTxnReqID()),
+		trace.StringAttribute("volName", volname),
+		trace.BoolAttribute("force", req.Force),
+	)
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volume-name", volname).Error("remove-brick commit transaction failed")
+		return nil, restutils.ErrToStatusCode(err)
+	}
+
+	recordVolumeHistory(ctx, volname, volume.EventRemoveBrickCommitted, txn.Ctx.GetTxnID(), map[string]string{
+		"bricks": bricksString(removed),
+		"force":  strconv.FormatBool(req.Force),
+	})
+
+	logger.WithField("volume-name", volname).Info("remove-brick committed")
+	events.Broadcast(volume.NewEvent(volume.EventRemoveBrickCommitted, volinfo))
+
+	return volinfo, http.StatusOK, nil
+}
+
+func volumeRemoveBrickStopHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, span := trace.StartSpan(ctx, "/volumeRemoveBrickStopHandler")
+	defer span.End()
+
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	decommissioned := volinfo.DecommissionedBricks()
+	if len(decommissioned) == 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrRemoveBrickNotStarted)
+		return
+	}
+
+	rinfo, err := getRemoveBrickRebalanceInfo(volinfo)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	migrationRunning := rinfo.State == rebalanceapi.Started
+
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Files which were already migrated stay on the remaining bricks
+	recommissionBricks(volinfo)
+
+	allNodes, err := peer.GetPeerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Nodes = allNodes
+	txn.Steps = rebalance.StopSteps(volinfo.Nodes())
+	for _, step := range txn.Steps {
+		step.Skip = !migrationRunning
+	}
+	txn.Steps = append(txn.Steps,
+		&transaction.Step{
+			DoFunc:   "brick-remove.StoreVolume",
+			UndoFunc: "brick-remove.StoreVolume.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Sync:     true,
+		},
+		&transaction.Step{
+			DoFunc: "vol-expand.NotifyClients",
+			Nodes:  allNodes,
+		},
+	)
+
+	rinfo.State = rebalanceapi.Stopped
+	rinfo.Cmd = rebalanceapi.CmdStop
+
+	if err := txn.Ctx.Set("volname", volname); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := txn.Ctx.Set("rinfo", rinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	span.AddAttributes(
+		trace.StringAttribute("reqID", txn.Ctx.GetTxnReqID()),
+		trace.StringAttribute("volName", volname),
+	)
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volume-name", volname).Error("remove-brick stop transaction failed")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	recordVolumeHistory(ctx, volname, volume.EventRemoveBrickStopped, txn.Ctx.GetTxnID(), map[string]string{
+		"bricks": bricksString(decommissioned),
+	})
+
+	logger.WithField("volume-name", volname).Info("remove-brick stopped")
+	events.Broadcast(volume.NewEvent(volume.EventRemoveBrickStopped, volinfo))
+
+	resp := (*api.RemoveBrickStopResp)(volume.CreateVolumeInfoResp(volinfo))
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
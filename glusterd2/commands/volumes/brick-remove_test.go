@@ -0,0 +1,98 @@
+package volumecommands
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func getSampleReplicateVolinfo(peerID uuid.UUID, numSubvols int) *volume.Volinfo {
+	v := &volume.Volinfo{
+		Name:     "vol",
+		Type:     volume.DistReplicate,
+		Metadata: map[string]string{volume.RemoveBrickRebalanceID: uuid.NewRandom().String()},
+	}
+	for i := 0; i < numSubvols; i++ {
+		sv := volume.Subvol{Type: volume.SubvolReplicate, ReplicaCount: 2}
+		for j := 0; j < 2; j++ {
+			sv.Bricks = append(sv.Bricks, brick.Brickinfo{
+				ID:     uuid.NewRandom(),
+				PeerID: peerID,
+				Path:   fmt.Sprintf("/bricks/%c%d", 'a'+i, j),
+			})
+		}
+		v.Subvols = append(v.Subvols, sv)
+	}
+	v.DistCount = numSubvols
+	return v
+}
+
+// TestDecommissionBricks validates decommissionBricks()
+func TestDecommissionBricks(t *testing.T) {
+	u := uuid.NewRandom()
+
+	v := getSampleReplicateVolinfo(u, 2)
+	err := decommissionBricks(v, []api.BrickReq{{PeerID: u.String(), Path: "/bricks/a0"}})
+	assert.Equal(t, gderrors.ErrRemoveBrickPartialSubvol, err)
+
+	v = getSampleReplicateVolinfo(u, 2)
+	err = decommissionBricks(v, []api.BrickReq{{PeerID: u.String(), Path: "/bricks/z0"}})
+	assert.Equal(t, gderrors.ErrBrickNotInVolume, err)
+
+	v = getSampleReplicateVolinfo(u, 1)
+	err = decommissionBricks(v, []api.BrickReq{
+		{PeerID: u.String(), Path: "/bricks/a0"},
+		{PeerID: u.String(), Path: "/bricks/a1"},
+	})
+	assert.Equal(t, gderrors.ErrRemoveBrickAllBricks, err)
+
+	v = getSampleReplicateVolinfo(u, 2)
+	err = decommissionBricks(v, []api.BrickReq{
+		{PeerID: u.String(), Path: "/bricks/a0"},
+		{PeerID: u.String(), Path: "/bricks/a1/"},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, v.DecommissionedBricks(), 2)
+
+	recommissionBricks(v)
+	assert.Empty(t, v.DecommissionedBricks())
+	assert.NotContains(t, v.Metadata, volume.RemoveBrickRebalanceID)
+
+	// Bricks of a distribute volume can be removed one by one
+	v = &volume.Volinfo{Name: "vol", Type: volume.Distribute}
+	v.Subvols = []volume.Subvol{{Type: volume.SubvolDistribute, Bricks: []brick.Brickinfo{
+		{PeerID: u, Path: "/bricks/a0"},
+		{PeerID: u, Path: "/bricks/a1"},
+	}}}
+	err = decommissionBricks(v, []api.BrickReq{{PeerID: u.String(), Path: "/bricks/a1"}})
+	assert.Nil(t, err)
+	assert.Len(t, v.DecommissionedBricks(), 1)
+}
+
+// TestRemoveDecommissionedBricks validates removeDecommissionedBricks()
+func TestRemoveDecommissionedBricks(t *testing.T) {
+	u := uuid.NewRandom()
+
+	v := getSampleReplicateVolinfo(u, 2)
+	err := decommissionBricks(v, []api.BrickReq{
+		{PeerID: u.String(), Path: "/bricks/a0"},
+		{PeerID: u.String(), Path: "/bricks/a1"},
+	})
+	assert.Nil(t, err)
+
+	removed := removeDecommissionedBricks(v)
+	assert.Len(t, removed, 2)
+	assert.Len(t, v.Subvols, 1)
+	assert.Equal(t, 1, v.DistCount)
+	assert.Equal(t, volume.Replicate, v.Type)
+	assert.Equal(t, "vol-replicate-0", v.Subvols[0].Name)
+	assert.Equal(t, "/bricks/b0", v.Subvols[0].Bricks[0].Path)
+	assert.NotContains(t, v.Metadata, volume.RemoveBrickRebalanceID)
+}
@@ -2,7 +2,9 @@ package volumecommands
 
 import (
 	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/brickmux"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/pkg/api"
 )
@@ -56,23 +58,11 @@ func replaceVolinfo(c transaction.TxnCtx) error {
 	// Retaining the brick position same as old brick
 	volInfo.Subvols[subVolIndex].Bricks[brickIndex] = newBrickInfo
 
-	// Setting bricks Info in transaction context
+	// Only the new brick needs to be validated and initialized
 	if err = c.Set("bricks", newBrickInfos); err != nil {
 		return err
 	}
 
-	// Setting checks in transaction context
-	//TODO: Ask for flags and force
-	checks := brick.PrepareChecks(true, make(map[string]bool))
-	err = c.Set("brick-checks", checks)
-	if err != nil {
-		return err
-	}
-
-	if err = c.Set("bricks", volInfo.GetBricks()); err != nil {
-		return err
-	}
-
 	var allBricks []brick.Brickinfo
 	// Validate brick paths only if it is not a smart volume
 	if newBrick.Size == 0 {
@@ -104,3 +94,22 @@ func startBrick(c transaction.TxnCtx) error {
 	err := newBrickInfo[0].StartBrick(c.Logger())
 	return err
 }
+
+func stopSrcBrick(c transaction.TxnCtx) error {
+	var srcBrickInfo brick.Brickinfo
+	if err := c.Get("srcBrickInfo", &srcBrickInfo); err != nil {
+		return err
+	}
+
+	return stopRetiredBrick(c, srcBrickInfo)
+}
+
+// stopRetiredBrick stops a brick which is no longer part of the volume and
+// deletes its volfile
+func stopRetiredBrick(c transaction.TxnCtx, b brick.Brickinfo) error {
+	if err := volgen.DeleteBricksVolfiles([]brick.Brickinfo{b}); err != nil {
+		return err
+	}
+
+	return brickmux.StopBrick(b, c.Logger())
+}
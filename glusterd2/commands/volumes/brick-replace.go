@@ -1,20 +1,35 @@
 package volumecommands
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"path/filepath"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/bricksplanner"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/jobs"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/pkg/api"
 	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/plugins/glustershd"
 
 	"github.com/gorilla/mux"
 	"github.com/pborman/uuid"
+	"go.opencensus.io/trace"
+)
+
+const (
+	// replaceBrickHealPollInterval is how often the heal status of a volume
+	// is checked before retiring a replaced brick
+	replaceBrickHealPollInterval = time.Minute
 )
 
 func registerReplaceBrickStepFuncs() {
@@ -25,79 +40,38 @@ func registerReplaceBrickStepFuncs() {
 		{"brick-replace.PrepareBricks", prepareBricks},
 		{"brick-replace.ReplaceVolinfo", replaceVolinfo},
 		{"brick-replace.StartBrick", startBrick},
+		{"brick-replace.StopSrcBrick", stopSrcBrick},
+		{"brick-replace.StoreVolume", storeVolume},
+		{"brick-replace.StoreVolume.Undo", undoStoreVolume},
 	}
 	for _, sf := range sfs {
 		transaction.RegisterStepFunc(sf.sf, sf.name)
 	}
 }
 
-func replaceBrickHandler(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
-	volname := mux.Vars(r)["volname"]
-
-	if !volume.IsValidName(volname) {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrInvalidVolName)
-		return
-	}
-
-	// Unmarshal ReplaceBrickReq
-	var req api.ReplaceBrickReq
-	if err := restutils.UnmarshalRequest(r, &req); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
-		return
-	}
-
-	if uuid.Parse(req.SrcPeerID) == nil {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid peerID passed in url")
-		return
-	}
-
-	if err := validateVolumeFlags(req.Flags); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
-		return
-	}
-
-	// Get Volume Info
-	vol, err := volume.GetVolume(volname)
-	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
-	}
-
-	subVols := vol.Subvols
-
-	var srcBrickInfo brick.Brickinfo
-	subVolIndex := 0
-	brickIndex := 0
-LOOP:
-	for index := range subVols {
-		for i, brick := range subVols[index].Bricks {
-			// Get Source Brick Info
-			if brick.PeerID.String() == req.SrcPeerID && brick.Path == req.SrcBrickPath {
-				subVolIndex = index
-				brickIndex = i
-				srcBrickInfo = brick
-				break LOOP
-			}
-		}
-	}
+// planReplacementBrick uses the bricks planner to pick a new brick for the
+// auto provisioned brick at the given position in the volume
+func planReplacementBrick(vol *volume.Volinfo, req *api.ReplaceBrickReq, srcBrickInfo brick.Brickinfo, subVolIndex, brickIndex int) (api.BrickReq, int, error) {
+	var newBrick api.BrickReq
 
 	excludeZones := make([]string, 0)
-	for svIndex, sv := range subVols {
+	for svIndex, sv := range vol.Subvols {
 		// if SubvolZonesOverlap is true then bricks of only that particular
 		// subvolume will be considered.
 		if req.SubvolZonesOverlap && subVolIndex != svIndex {
 			continue
 		}
 		for _, b := range sv.Bricks {
+			// The new brick takes the place of the source brick,
+			// so it may be in the same zone
+			if uuid.Equal(b.ID, srcBrickInfo.ID) {
+				continue
+			}
 			p, err := peer.GetPeer(b.PeerID.String())
 			if err != nil {
-				restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-				return
+				return newBrick, http.StatusInternalServerError, err
 			}
-			excludeZones = append(excludeZones, p.Metadata["_zone"])
+			excludeZones = append(excludeZones, p.GetZone())
 		}
 	}
 	req.ExcludeZones = append(req.ExcludeZones, excludeZones...)
@@ -114,111 +88,431 @@ LOOP:
 	}
 	availableVgs, err := bricksplanner.GetAvailableVgs(&volreq)
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return newBrick, http.StatusInternalServerError, err
 	}
 	// TODO: check for available vgs in zones already being used in volume.
 	if len(availableVgs) == 0 {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "No volume groups are available")
-		return
+		return newBrick, http.StatusInternalServerError, fmt.Errorf("no volume groups are available")
 	}
 
 	mtabEntries, err := volume.GetMounts()
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return newBrick, http.StatusInternalServerError, err
 	}
 
 	// Get source brick information like size etc
 	brickInfo, err := volume.BrickStatus(srcBrickInfo, mtabEntries)
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return newBrick, http.StatusInternalServerError, err
 	}
 
 	// Get new brick from the available vgs
-	newBrick := bricksplanner.GetNewBrick(availableVgs, brickInfo, vol, subVolIndex, brickIndex)
+	newBrick = bricksplanner.GetNewBrick(availableVgs, brickInfo, vol, subVolIndex, brickIndex)
+	return newBrick, http.StatusOK, nil
+}
 
-	peerID := uuid.Parse(newBrick.PeerID)
-	if peerID == nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "peer id of new brick could not be parsed")
+// validateReplaceBrick checks that the brick of the volume can be replaced as
+// per the request, and returns the source brick and its position in the
+// volume. If it can't be replaced, the HTTP status to respond with is
+// returned along with the error.
+func validateReplaceBrick(vol *volume.Volinfo, req *api.ReplaceBrickReq) (brick.Brickinfo, int, int, int, error) {
+	var srcBrickInfo brick.Brickinfo
+
+	// Data is migrated to the new brick by self-heal, which needs the
+	// redundancy of a replicate or disperse volume
+	switch vol.Type {
+	case volume.Replicate, volume.DistReplicate, volume.Disperse, volume.DistDisperse:
+	default:
+		return srcBrickInfo, 0, 0, http.StatusBadRequest, gderrors.ErrVolTypeNotInReplicateOrDisperse
+	}
+
+	if vol.State != volume.VolStarted {
+		return srcBrickInfo, 0, 0, http.StatusBadRequest, gderrors.ErrVolNotStarted
+	}
+
+	srcFound := false
+	subVolIndex := 0
+	brickIndex := 0
+LOOP:
+	for index := range vol.Subvols {
+		for i, b := range vol.Subvols[index].Bricks {
+			// Get Source Brick Info
+			if b.PeerID.String() == req.SrcPeerID && b.Path == filepath.Clean(req.SrcBrickPath) {
+				subVolIndex = index
+				brickIndex = i
+				srcBrickInfo = b
+				srcFound = true
+				break LOOP
+			}
+		}
+	}
+	if !srcFound {
+		return srcBrickInfo, 0, 0, http.StatusNotFound, gderrors.ErrSrcBrickNotFound
+	}
+
+	if vol.IsAutoProvisioned() {
+		if req.NewBrick != nil {
+			return srcBrickInfo, 0, 0, http.StatusBadRequest, gderrors.ErrNewBrickNotAllowed
+		}
+	} else {
+		if req.NewBrick == nil {
+			return srcBrickInfo, 0, 0, http.StatusBadRequest, gderrors.ErrNewBrickRequired
+		}
+		for _, b := range vol.GetBricks() {
+			if b.PeerID.String() == req.NewBrick.PeerID && b.Path == filepath.Clean(req.NewBrick.Path) {
+				return srcBrickInfo, 0, 0, http.StatusBadRequest, gderrors.ErrDuplicateBrickPath
+			}
+		}
+	}
+
+	return srcBrickInfo, subVolIndex, brickIndex, http.StatusOK, nil
+}
+
+func replaceBrickHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, span := trace.StartSpan(ctx, "/replaceBrickHandler")
+	defer span.End()
+
+	volname := mux.Vars(r)["volname"]
+
+	if !volume.IsValidName(volname) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrInvalidVolName)
 		return
 	}
-	allPeerIDs := vol.Nodes()
-	nodes := []uuid.UUID{peerID}
-	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+
+	// Unmarshal ReplaceBrickReq
+	var req api.ReplaceBrickReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	vol, status, err := ReplaceBrick(ctx, volname, req)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
 		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
+
+	resp := createReplaceBrickResp(vol)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// ReplaceBrick replaces a brick of a volume with a new brick, chosen by the
+// bricks planner for auto provisioned volumes, and returns the updated
+// volinfo. The data of the brick is migrated to the new brick by self-heal.
+func ReplaceBrick(ctx context.Context, volname string, req api.ReplaceBrickReq) (*volume.Volinfo, int, error) {
+	vol, _, status, err := ReplaceBrickWithRetireJob(ctx, volname, req)
+	return vol, status, err
+}
+
+// ReplaceBrickWithRetireJob replaces a brick like ReplaceBrick, and also
+// returns the job which heals the new brick and retires the replaced one. The
+// job is nil if it could not be started.
+func ReplaceBrickWithRetireJob(ctx context.Context, volname string, req api.ReplaceBrickReq) (*volume.Volinfo, *jobs.Job, int, error) {
+	span := trace.FromContext(ctx)
+	logger := gdctx.GetReqLogger(ctx)
+
+	if uuid.Parse(req.SrcPeerID) == nil {
+		return nil, nil, http.StatusBadRequest, errors.New("invalid peerID passed in url")
+	}
+
+	if err := validateVolumeFlags(req.Flags); err != nil {
+		return nil, nil, http.StatusBadRequest, err
+	}
+
+	// The new brick of an auto provisioned volume is planned on the
+	// devices. Whether a volume is auto provisioned never changes, so it
+	// can be checked before the volume is locked.
+	if vol, err := volume.GetVolume(volname); err == nil && vol.IsAutoProvisioned() {
+		locks, status, err := lockDevices()
+		if err != nil {
+			return nil, nil, status, err
+		}
+		defer locks.UnLock(ctx)
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		return nil, nil, status, err
+	}
 	defer txn.Done()
 
+	// Get Volume Info
+	vol, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		return nil, nil, status, err
+	}
+
+	srcBrickInfo, subVolIndex, brickIndex, status, err := validateReplaceBrick(vol, &req)
+	if err != nil {
+		return nil, nil, status, err
+	}
+
+	autoProvisioned := vol.IsAutoProvisioned()
+	var newBrick api.BrickReq
+	if autoProvisioned {
+		newBrick, status, err = planReplacementBrick(vol, &req, srcBrickInfo, subVolIndex, brickIndex)
+		if err != nil {
+			return nil, nil, status, err
+		}
+	} else {
+		newBrick = *req.NewBrick
+	}
+
+	// The replacement takes over the role of the source brick
+	if srcBrickInfo.Type == brick.Arbiter {
+		newBrick.Type = "arbiter"
+	}
+
+	peerID := uuid.Parse(newBrick.PeerID)
+	if peerID == nil {
+		return nil, nil, http.StatusBadRequest, errors.New("peer id of new brick could not be parsed")
+	}
+	if _, err := peer.GetPeer(peerID.String()); err != nil {
+		return nil, nil, http.StatusBadRequest, gderrors.ErrPeerNotFound
+	}
+
+	// A brick is usually replaced because its peer is gone. Skip the steps
+	// which need the source peer if it isn't reachable.
+	_, srcOnline := store.Store.IsNodeAlive(srcBrickInfo.PeerID)
+	notifyNodes := []uuid.UUID{peerID}
+	for _, node := range vol.Nodes() {
+		if uuid.Equal(node, peerID) || (!srcOnline && uuid.Equal(node, srcBrickInfo.PeerID)) {
+			continue
+		}
+		notifyNodes = append(notifyNodes, node)
+	}
+
+	nodes := []uuid.UUID{peerID}
 	txn.Steps = []*transaction.Step{
 		{
-			DoFunc: "brick-replace.PrepareBricks",
-			Nodes:  nodes,
+			DoFunc:  "brick-replace.PrepareBricks",
+			Nodes:   nodes,
+			Skip:    !autoProvisioned,
+			Timeout: transaction.BrickPrepareTimeout,
 		},
 		{
 			DoFunc: "brick-replace.ReplaceVolinfo",
 			Nodes:  []uuid.UUID{gdctx.MyUUID},
 			Sync:   true,
 		},
+		{
+			DoFunc: "vol-expand.ValidateBricks",
+			Nodes:  nodes,
+			Skip:   autoProvisioned,
+		},
 		{
 			DoFunc:   "vol-create.InitBricks",
 			UndoFunc: "vol-create.UndoInitBricks",
 			Nodes:    nodes,
 		},
+		{
+			DoFunc: "brick-replace.StopSrcBrick",
+			Nodes:  []uuid.UUID{srcBrickInfo.PeerID},
+			Skip:   !srcOnline,
+		},
 		{
 			DoFunc: "vol-start.StartBricks",
 			Nodes:  nodes,
 		},
 		{
-			DoFunc:   "vol-create.StoreVolume",
-			UndoFunc: "vol-create.UndoStoreVolume",
+			DoFunc:   "brick-replace.StoreVolume",
+			UndoFunc: "brick-replace.StoreVolume.Undo",
 			Nodes:    []uuid.UUID{gdctx.MyUUID},
 			Sync:     true,
 		},
 		{
 			DoFunc: "vol-expand.NotifyClients",
-			Nodes:  allPeerIDs,
+			Nodes:  notifyNodes,
 		},
 	}
 
+	// Bricks provisioned by the planner are fresh mounts, so the brick
+	// checks are only enforced for bricks specified in the request
+	checks := brick.PrepareChecks(autoProvisioned || req.Force, req.Flags)
+
 	if err = txn.Ctx.Set("newBrick", &newBrick); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, nil, http.StatusInternalServerError, err
 	}
 	if err = txn.Ctx.Set("srcBrickInfo", &srcBrickInfo); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, nil, http.StatusInternalServerError, err
 	}
 	if err = txn.Ctx.Set("subVolIndex", &subVolIndex); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, nil, http.StatusInternalServerError, err
 	}
 	if err = txn.Ctx.Set("brickIndex", &brickIndex); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, nil, http.StatusInternalServerError, err
 	}
-	if err = txn.Ctx.Set("volinfo", &vol); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+	if err = txn.Ctx.Set("brick-checks", checks); err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+	if err = txn.Ctx.Set("oldvolinfo", vol); err != nil {
+		return nil, nil, http.StatusInternalServerError, err
 	}
+	if err = txn.Ctx.Set("volinfo", vol); err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+
+	span.AddAttributes(
+		trace.StringAttribute("reqID", txn.Ctx.GetTxnReqID()),
+		trace.StringAttribute("volName", volname),
+		trace.StringAttribute("srcBrick", srcBrickInfo.String()),
+		trace.StringAttribute("newBrick", newBrick.PeerID+":"+newBrick.Path),
+	)
 
 	if err = txn.Do(); err != nil {
 		logger.WithError(err).WithField("volume-name", volname).Error("replace brick transaction failed")
 		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, nil, status, err
 	}
-	resp := createReplaceBrickResp(vol)
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
 
-	return
+	recordVolumeHistory(ctx, volname, volume.EventBrickReplaced, txn.Ctx.GetTxnID(), map[string]string{
+		"src-brick": srcBrickInfo.String(),
+		"new-brick": newBrick.PeerID + ":" + newBrick.Path,
+	})
+
+	vol, err = volume.GetVolume(volname)
+	if err != nil {
+		return nil, nil, http.StatusInternalServerError, err
+	}
+
+	job, err := jobs.Submit(ctx, api.JobTypeBrickCleanup, volname, func(ctx context.Context, h *jobs.Handle) error {
+		return retireReplacedBrick(ctx, h, volname, srcBrickInfo)
+	})
+	if err != nil {
+		logger.WithError(err).Warn("failed to start retiring replaced brick")
+	}
+
+	return vol, job, http.StatusOK, nil
 }
 
 // Replace brick resp
 func createReplaceBrickResp(v *volume.Volinfo) *api.ReplaceBrickResp {
 	return (*api.ReplaceBrickResp)(volume.CreateVolumeInfoResp(v))
 }
+
+// retireReplacedBrick triggers a full self-heal to migrate data on to the
+// brick which replaced srcBrick and releases the storage of srcBrick once the
+// heal completes. Only the storage of auto provisioned bricks is released,
+// data on manually provisioned bricks is left for the administrator.
+// This runs as a job on the node which served the request.
+func retireReplacedBrick(ctx context.Context, h *jobs.Handle, volname string, srcBrick brick.Brickinfo) error {
+	logger := gdctx.GetReqLogger(ctx).WithField("brick", srcBrick.String())
+
+	healTriggered := false
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(replaceBrickHealPollInterval):
+		}
+
+		volinfo, err := volume.GetVolume(volname)
+		if err == gderrors.ErrVolNotFound {
+			h.Logf("volume deleted, not retiring replaced brick %s", srcBrick.String())
+			return nil
+		} else if err != nil {
+			logger.WithError(err).Warn("failed to get volume")
+			continue
+		}
+
+		for _, b := range volinfo.GetBricks() {
+			if uuid.Equal(b.ID, srcBrick.ID) {
+				h.Logf("brick %s is part of the volume again, not retiring it", srcBrick.String())
+				return nil
+			}
+		}
+
+		if volinfo.State != volume.VolStarted {
+			continue
+		}
+
+		if !healTriggered {
+			if err := triggerFullHeal(ctx, volinfo); err != nil {
+				logger.WithError(err).Warn("failed to trigger heal of replaced brick")
+				continue
+			}
+			h.Logf("full heal triggered to migrate data of replaced brick %s", srcBrick.String())
+			healTriggered = true
+			continue
+		}
+
+		pending, err := glustershd.PendingHealEntries(volname)
+		if err != nil {
+			logger.WithError(err).Debug("failed to get heal info")
+			continue
+		}
+		if pending != 0 {
+			logger.WithField("pending", pending).Debug("waiting for heal to complete")
+			continue
+		}
+
+		if !srcBrick.PType.IsAutoProvisioned() {
+			h.Logf("heal completed, data on the replaced brick %s is left in place", srcBrick.String())
+			return nil
+		}
+
+		if err := cleanReplacedBrick(ctx, volinfo, srcBrick); err != nil {
+			logger.WithError(err).Warn("failed to clean up replaced brick")
+			continue
+		}
+
+		h.Logf("heal completed, replaced brick %s retired", srcBrick.String())
+		return nil
+	}
+}
+
+func triggerFullHeal(ctx context.Context, volinfo *volume.Volinfo) error {
+	txn, err := transaction.NewTxnWithLocks(ctx, volinfo.Name)
+	if err != nil {
+		return err
+	}
+	defer txn.Done()
+
+	steps, err := glustershd.FullHealSteps(txn.Ctx, volinfo.Nodes())
+	if err != nil {
+		return err
+	}
+	txn.Steps = steps
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		return err
+	}
+
+	return txn.Do()
+}
+
+// cleanReplacedBrick releases the storage of an auto provisioned brick which
+// is no longer part of the volume
+func cleanReplacedBrick(ctx context.Context, volinfo *volume.Volinfo, srcBrick brick.Brickinfo) error {
+	txn, err := transaction.NewTxnWithLocks(ctx, volinfo.Name)
+	if err != nil {
+		return err
+	}
+	defer txn.Done()
+
+	// Only the replaced brick is passed so that no other brick of the
+	// volume on that peer gets cleaned
+	retired := &volume.Volinfo{
+		ID:              volinfo.ID,
+		Name:            volinfo.Name,
+		ProvisionerType: volinfo.ProvisionerType,
+		Subvols:         []volume.Subvol{{Bricks: []brick.Brickinfo{srcBrick}}},
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:  "vol-delete.CleanBricks",
+			Nodes:   []uuid.UUID{srcBrick.PeerID},
+			Timeout: transaction.BrickCleanTimeout,
+		},
+	}
+
+	if err := txn.Ctx.Set("volinfo", retired); err != nil {
+		return err
+	}
+
+	return txn.Do()
+}
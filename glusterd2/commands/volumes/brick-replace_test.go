@@ -0,0 +1,84 @@
+package volumecommands
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateReplaceBrick validates validateReplaceBrick()
+func TestValidateReplaceBrick(t *testing.T) {
+	peerID := uuid.NewRandom()
+	newBrick := &api.BrickReq{PeerID: peerID.String(), Path: "/bricks/new"}
+
+	newVol := func(volType volume.VolType, state volume.VolState, auto bool) *volume.Volinfo {
+		v := getSampleReplicateVolinfo(peerID, 2)
+		v.Type = volType
+		v.State = state
+		if auto {
+			v.Metadata[brick.ProvisionKey] = string(brick.AutoProvisioned)
+		}
+		return v
+	}
+
+	tests := []struct {
+		name        string
+		vol         *volume.Volinfo
+		req         api.ReplaceBrickReq
+		status      int
+		err         error
+		subVolIndex int
+		brickIndex  int
+	}{
+		{"replace", newVol(volume.DistReplicate, volume.VolStarted, false),
+			api.ReplaceBrickReq{SrcPeerID: peerID.String(), SrcBrickPath: "/bricks/b1", NewBrick: newBrick},
+			http.StatusOK, nil, 1, 1},
+		{"unclean source path", newVol(volume.Replicate, volume.VolStarted, false),
+			api.ReplaceBrickReq{SrcPeerID: peerID.String(), SrcBrickPath: "/bricks/a0/", NewBrick: newBrick},
+			http.StatusOK, nil, 0, 0},
+		{"auto provisioned", newVol(volume.DistReplicate, volume.VolStarted, true),
+			api.ReplaceBrickReq{SrcPeerID: peerID.String(), SrcBrickPath: "/bricks/a1"},
+			http.StatusOK, nil, 0, 1},
+		{"distribute", newVol(volume.Distribute, volume.VolStarted, false),
+			api.ReplaceBrickReq{SrcPeerID: peerID.String(), SrcBrickPath: "/bricks/a0", NewBrick: newBrick},
+			http.StatusBadRequest, gderrors.ErrVolTypeNotInReplicateOrDisperse, 0, 0},
+		{"stopped", newVol(volume.DistReplicate, volume.VolStopped, false),
+			api.ReplaceBrickReq{SrcPeerID: peerID.String(), SrcBrickPath: "/bricks/a0", NewBrick: newBrick},
+			http.StatusBadRequest, gderrors.ErrVolNotStarted, 0, 0},
+		{"unknown source path", newVol(volume.DistReplicate, volume.VolStarted, false),
+			api.ReplaceBrickReq{SrcPeerID: peerID.String(), SrcBrickPath: "/bricks/c0", NewBrick: newBrick},
+			http.StatusNotFound, gderrors.ErrSrcBrickNotFound, 0, 0},
+		{"unknown source peer", newVol(volume.DistReplicate, volume.VolStarted, false),
+			api.ReplaceBrickReq{SrcPeerID: uuid.NewRandom().String(), SrcBrickPath: "/bricks/a0", NewBrick: newBrick},
+			http.StatusNotFound, gderrors.ErrSrcBrickNotFound, 0, 0},
+		{"no new brick", newVol(volume.DistReplicate, volume.VolStarted, false),
+			api.ReplaceBrickReq{SrcPeerID: peerID.String(), SrcBrickPath: "/bricks/a0"},
+			http.StatusBadRequest, gderrors.ErrNewBrickRequired, 0, 0},
+		{"new brick of auto provisioned volume", newVol(volume.DistReplicate, volume.VolStarted, true),
+			api.ReplaceBrickReq{SrcPeerID: peerID.String(), SrcBrickPath: "/bricks/a0", NewBrick: newBrick},
+			http.StatusBadRequest, gderrors.ErrNewBrickNotAllowed, 0, 0},
+		{"new brick in volume", newVol(volume.DistReplicate, volume.VolStarted, false),
+			api.ReplaceBrickReq{SrcPeerID: peerID.String(), SrcBrickPath: "/bricks/a0",
+				NewBrick: &api.BrickReq{PeerID: peerID.String(), Path: "/bricks/b0/"}},
+			http.StatusBadRequest, gderrors.ErrDuplicateBrickPath, 0, 0},
+	}
+
+	for _, tt := range tests {
+		srcBrick, subVolIndex, brickIndex, status, err := validateReplaceBrick(tt.vol, &tt.req)
+		assert.Equal(t, tt.status, status, tt.name)
+		assert.Equal(t, tt.err, err, tt.name)
+		if err != nil {
+			continue
+		}
+		assert.Equal(t, tt.subVolIndex, subVolIndex, tt.name)
+		assert.Equal(t, tt.brickIndex, brickIndex, tt.name)
+		assert.Equal(t, tt.vol.Subvols[subVolIndex].Bricks[brickIndex], srcBrick, tt.name)
+	}
+}
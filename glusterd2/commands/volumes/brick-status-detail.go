@@ -0,0 +1,190 @@
+package volumecommands
+
+import (
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/plugins/glustershd"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+const brickStatusDetailTxnKey = "brickstatusdetail"
+
+func registerBrickStatusDetailStepFuncs() {
+	transaction.RegisterStepFunc(txnBrickStatusDetail, "bricks-status.Detail")
+}
+
+// txnBrickStatusDetail collects the status of a brick on its peer. Only
+// failing to get the brick status fails the step, the details which can't be
+// collected are left out.
+func txnBrickStatusDetail(c transaction.TxnCtx) error {
+	var b brick.Brickinfo
+	if err := c.Get("brick", &b); err != nil {
+		return err
+	}
+	logger := c.Logger().WithField("brick", b.String())
+
+	mtabEntries, err := volume.GetMounts()
+	if err != nil {
+		logger.WithError(err).Error("failed to read mount entries")
+		return err
+	}
+
+	status, err := volume.BrickStatus(b, mtabEntries)
+	if err != nil {
+		logger.WithError(err).Error("failed to get brick status")
+		return err
+	}
+
+	resp := api.BrickStatusDetailResp{
+		BrickStatus: *(brick.CreateBrickStatusRsp([]brick.Brickstatus{status})[0]),
+	}
+
+	if status.Online {
+		if startedAt, err := daemon.ProcessStartTime(status.Pid); err != nil {
+			logger.WithError(err).Warn("failed to get start time of brick process")
+		} else {
+			resp.StartedAt = startedAt
+			resp.Uptime = int64(time.Since(startedAt).Seconds())
+		}
+
+		if resp.Clients, err = getBrickClients(b); err != nil {
+			logger.WithError(err).Warn("failed to get clients of brick")
+		}
+	}
+
+	var fstat syscall.Statfs_t
+	if err := syscall.Statfs(b.Path, &fstat); err != nil {
+		logger.WithError(err).Warn("failed to get inode usage of brick")
+	} else {
+		resp.Inodes = api.InodeInfo{
+			Total: fstat.Files,
+			Used:  fstat.Files - fstat.Ffree,
+			Free:  fstat.Ffree,
+		}
+	}
+
+	if b.TpName != "" {
+		if usage, err := volume.SampleBrickUsage(b); err == nil && usage.TpName != "" {
+			resp.ThinPool = &api.ThinPoolUsage{
+				VgName:   usage.VgName,
+				TpName:   usage.TpName,
+				Capacity: usage.TpCapacity,
+				Used:     usage.TpUsed,
+			}
+		}
+	}
+
+	c.SetNodeResult(gdctx.MyUUID, brickStatusDetailTxnKey, &resp)
+	return nil
+}
+
+// brickHealSummary returns the heal summary of the brick, or nil if the
+// volume isn't healed or the heal info can't be had
+func brickHealSummary(v *volume.Volinfo, b *brick.Brickinfo, logger log.FieldLogger) *api.BrickHealSummary {
+	if v.State != volume.VolStarted {
+		return nil
+	}
+	switch v.Type {
+	case volume.Replicate, volume.Disperse, volume.DistReplicate, volume.DistDisperse:
+	default:
+		return nil
+	}
+
+	info, err := glustershd.BrickHealSummary(v.Name, b.PeerID.String(), b.Path)
+	if err != nil {
+		logger.WithError(err).Warn("failed to get heal info of brick")
+		return nil
+	}
+	if info == nil {
+		return nil
+	}
+	return &api.BrickHealSummary{
+		Status:                 info.Status,
+		TotalEntries:           info.TotalEntries,
+		EntriesInHealPending:   info.EntriesInHealPending,
+		EntriesInSplitBrain:    info.EntriesInSplitBrain,
+		EntriesPossiblyHealing: info.EntriesPossiblyHealing,
+	}
+}
+
+func brickStatusDetailHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, span := trace.StartSpan(ctx, "/brickStatusDetailHandler")
+	defer span.End()
+
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+	brickID := mux.Vars(r)["brickid"]
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, volinfo.GetNamespace()) {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrVolNotFound)
+		return
+	}
+
+	var b *brick.Brickinfo
+	for _, vb := range volinfo.GetBricks() {
+		if vb.ID.String() == brickID {
+			b = &vb
+			break
+		}
+	}
+	if b == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrBrickNotInVolume)
+		return
+	}
+
+	span.AddAttributes(
+		trace.StringAttribute("volName", volname),
+		trace.StringAttribute("brickID", brickID),
+	)
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "bricks-status.Detail",
+			Nodes:  []uuid.UUID{b.PeerID},
+		},
+	}
+	if err := txn.Ctx.Set("brick", b); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	txn.DisableRollback = true
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("brick", b.String()).Error("failed to get brick status")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	var resp api.BrickStatusDetailResp
+	if err := txn.Ctx.GetNodeResult(b.PeerID, brickStatusDetailTxnKey, &resp); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	resp.Heal = brickHealSummary(volinfo, b, logger)
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, &resp)
+}
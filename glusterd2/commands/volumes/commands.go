@@ -2,6 +2,8 @@
 package volumecommands
 
 import (
+	"github.com/gluster/glusterd2/glusterd2/hooks"
+	"github.com/gluster/glusterd2/glusterd2/middleware"
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/utils"
@@ -21,7 +23,81 @@ func (c *Command) Routes() route.Routes {
 			Version:      1,
 			RequestType:  utils.GetTypeString((*api.VolCreateReq)(nil)),
 			ResponseType: utils.GetTypeString((*api.VolumeCreateResp)(nil)),
-			HandlerFunc:  volumeCreateHandler},
+			HandlerFunc:  middleware.Idempotent(middleware.Async(api.JobTypeVolumeCreate, volumeCreateHandler))},
+		route.Route{
+			Name:         "VolumeBulkCreate",
+			Method:       "POST",
+			Pattern:      "/volumes/bulk",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolBulkCreateReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolBulkResp)(nil)),
+			HandlerFunc:  volumeBulkCreateHandler},
+		route.Route{
+			Name:         "VolumeBulkDelete",
+			Method:       "DELETE",
+			Pattern:      "/volumes/bulk",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolBulkDeleteReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolBulkResp)(nil)),
+			HandlerFunc:  volumeBulkDeleteHandler},
+		route.Route{
+			Name:         "VolumeStartAll",
+			Method:       "POST",
+			Pattern:      "/volumes/start-all",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolumeStartReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolBulkResp)(nil)),
+			HandlerFunc:  volumeStartAllHandler},
+		route.Route{
+			Name:         "VolumeStopAll",
+			Method:       "POST",
+			Pattern:      "/volumes/stop-all",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolBulkResp)(nil)),
+			HandlerFunc:  volumeStopAllHandler},
+		route.Route{
+			Name:         "VolumeImport",
+			Method:       "POST",
+			Pattern:      "/volumes/import",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolumeImportReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolumeCreateResp)(nil)),
+			HandlerFunc:  volumeImportHandler},
+		route.Route{
+			Name:         "VolumeExport",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/export",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeExport)(nil)),
+			HandlerFunc:  volumeExportHandler},
+		route.Route{
+			Name:         "VolumeHistory",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/history",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeHistoryResp)(nil)),
+			HandlerFunc:  volumeHistoryHandler},
+		route.Route{
+			Name:         "VolumeUsage",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/usage",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeUsageResp)(nil)),
+			HandlerFunc:  volumeUsageHandler},
+		route.Route{
+			Name:         "VolumeChecksum",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/checksum",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeChecksumResp)(nil)),
+			HandlerFunc:  volumeChecksumHandler},
+		route.Route{
+			Name:         "VolumeVolfile",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/volfiles/{type}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolfileResp)(nil)),
+			HandlerFunc:  volumeVolfileHandler},
 		route.Route{
 			Name:         "VolumeExpand",
 			Method:       "POST",
@@ -29,7 +105,7 @@ func (c *Command) Routes() route.Routes {
 			Version:      1,
 			RequestType:  utils.GetTypeString((*api.VolExpandReq)(nil)),
 			ResponseType: utils.GetTypeString((*api.VolumeExpandResp)(nil)),
-			HandlerFunc:  volumeExpandHandler},
+			HandlerFunc:  middleware.Idempotent(middleware.Async(api.JobTypeVolumeExpand, volumeExpandHandler))},
 		route.Route{
 			Name:         "VolumeOptionGet",
 			Method:       "GET",
@@ -52,6 +128,14 @@ func (c *Command) Routes() route.Routes {
 			RequestType:  utils.GetTypeString((*api.VolOptionReq)(nil)),
 			ResponseType: utils.GetTypeString((*api.VolumeOptionResp)(nil)),
 			HandlerFunc:  volumeOptionsHandler},
+		route.Route{
+			Name:         "VolumeOptionGroup",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/options/group/{groupname}",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolOptionGroupReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolumeOptionResp)(nil)),
+			HandlerFunc:  volumeOptionGroupHandler},
 		route.Route{
 			Name:         "VolumeReset",
 			Method:       "DELETE", // Do DELETE requests have a body? Should this be query param ?
@@ -60,6 +144,29 @@ func (c *Command) Routes() route.Routes {
 			RequestType:  utils.GetTypeString((*api.VolOptionResetReq)(nil)),
 			ResponseType: utils.GetTypeString((*api.VolumeOptionResp)(nil)),
 			HandlerFunc:  volumeResetHandler},
+		route.Route{
+			Name:         "VolumeDefaultOptionsGet",
+			Method:       "GET",
+			Pattern:      "/cluster/volume-options",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeDefaultOptionsResp)(nil)),
+			HandlerFunc:  volumeDefaultOptionsGetHandler},
+		route.Route{
+			Name:         "VolumeDefaultOptionsSet",
+			Method:       "POST",
+			Pattern:      "/cluster/volume-options",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolumeDefaultOptionsReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolumeDefaultOptionsResp)(nil)),
+			HandlerFunc:  volumeDefaultOptionsSetHandler},
+		route.Route{
+			Name:         "VolumeDefaultOptionsReset",
+			Method:       "DELETE",
+			Pattern:      "/cluster/volume-options",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolumeDefaultOptionsResetReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolumeDefaultOptionsResp)(nil)),
+			HandlerFunc:  volumeDefaultOptionsResetHandler},
 		route.Route{
 			Name:         "OptionGroupList",
 			Method:       "GET",
@@ -80,12 +187,69 @@ func (c *Command) Routes() route.Routes {
 			Pattern:     "/volumes/options-group/{groupname}",
 			Version:     1,
 			HandlerFunc: optionGroupDeleteHandler},
+		route.Route{
+			Name:         "VolumeProfileCreate",
+			Method:       "POST",
+			Pattern:      "/volume-profiles",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolumeProfileReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolumeProfile)(nil)),
+			HandlerFunc:  volumeProfileCreateHandler},
+		route.Route{
+			Name:         "VolumeProfileList",
+			Method:       "GET",
+			Pattern:      "/volume-profiles",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeProfileListResp)(nil)),
+			HandlerFunc:  volumeProfileListHandler},
+		route.Route{
+			Name:         "VolumeProfileGet",
+			Method:       "GET",
+			Pattern:      "/volume-profiles/{profilename}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeProfile)(nil)),
+			HandlerFunc:  volumeProfileGetHandler},
+		route.Route{
+			Name:        "VolumeProfileDelete",
+			Method:      "DELETE",
+			Pattern:     "/volume-profiles/{profilename}",
+			Version:     1,
+			HandlerFunc: volumeProfileDeleteHandler},
+		route.Route{
+			Name:         "VolfileTemplateList",
+			Method:       "GET",
+			Pattern:      "/volfile-templates",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolfileTemplateListResp)(nil)),
+			HandlerFunc:  volfileTemplateListHandler},
+		route.Route{
+			Name:         "VolfileTemplateGet",
+			Method:       "GET",
+			Pattern:      "/volfile-templates/{voltype}/{name}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolfileTemplate)(nil)),
+			HandlerFunc:  volfileTemplateGetHandler},
+		route.Route{
+			Name:         "VolfileTemplateSet",
+			Method:       "PUT",
+			Pattern:      "/volfile-templates/{voltype}/{name}",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolfileTemplateReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolfileTemplate)(nil)),
+			HandlerFunc:  volfileTemplateSetHandler,
+			Role:         api.RoleAdmin},
+		route.Route{
+			Name:        "VolfileTemplateDelete",
+			Method:      "DELETE",
+			Pattern:     "/volfile-templates/{voltype}/{name}",
+			Version:     1,
+			HandlerFunc: volfileTemplateDeleteHandler},
 		route.Route{
 			Name:        "VolumeDelete",
 			Method:      "DELETE",
 			Pattern:     "/volumes/{volname}",
 			Version:     1,
-			HandlerFunc: volumeDeleteHandler},
+			HandlerFunc: middleware.Idempotent(volumeDeleteHandler)},
 		route.Route{
 			Name:         "VolumeInfo",
 			Method:       "GET",
@@ -100,6 +264,13 @@ func (c *Command) Routes() route.Routes {
 			Version:      1,
 			ResponseType: utils.GetTypeString((*api.BricksStatusResp)(nil)),
 			HandlerFunc:  volumeBricksStatusHandler},
+		route.Route{
+			Name:         "BrickStatusDetail",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/bricks/{brickid}/status",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.BrickStatusDetailResp)(nil)),
+			HandlerFunc:  brickStatusDetailHandler},
 		route.Route{
 			Name:         "VolumeStatus",
 			Method:       "GET",
@@ -107,6 +278,20 @@ func (c *Command) Routes() route.Routes {
 			Version:      1,
 			ResponseType: utils.GetTypeString((*api.VolumeStatusResp)(nil)),
 			HandlerFunc:  volumeStatusHandler},
+		route.Route{
+			Name:         "VolumeClients",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/clients",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeClientsResp)(nil)),
+			HandlerFunc:  volumeClientsHandler},
+		route.Route{
+			Name:         "VolumeTop",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/top",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolumeTopResp)(nil)),
+			HandlerFunc:  volumeTopHandler},
 		route.Route{
 			Name:         "VolumeList",
 			Method:       "GET",
@@ -136,6 +321,44 @@ func (c *Command) Routes() route.Routes {
 			Version:     1,
 			RequestType: utils.GetTypeString((*api.VolStatedumpReq)(nil)),
 			HandlerFunc: volumeStatedumpHandler},
+		route.Route{
+			Name:         "VolumeReplaceBrick",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/replace-brick",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.ReplaceBrickReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.ReplaceBrickResp)(nil)),
+			HandlerFunc:  replaceBrickHandler},
+		route.Route{
+			Name:         "VolumeRemoveBrickStart",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/remove-brick/start",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.RemoveBrickReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.RemoveBrickStartResp)(nil)),
+			HandlerFunc:  volumeRemoveBrickStartHandler},
+		route.Route{
+			Name:         "VolumeRemoveBrickStatus",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/remove-brick",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.RemoveBrickStatusResp)(nil)),
+			HandlerFunc:  volumeRemoveBrickStatusHandler},
+		route.Route{
+			Name:         "VolumeRemoveBrickCommit",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/remove-brick/commit",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.RemoveBrickCommitReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.RemoveBrickCommitResp)(nil)),
+			HandlerFunc:  volumeRemoveBrickCommitHandler},
+		route.Route{
+			Name:         "VolumeRemoveBrickStop",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/remove-brick/stop",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.RemoveBrickStopResp)(nil)),
+			HandlerFunc:  volumeRemoveBrickStopHandler},
 		route.Route{
 			Name:         "ReplaceBrick",
 			Method:       "POST",
@@ -152,6 +375,37 @@ func (c *Command) Routes() route.Routes {
 			RequestType:  utils.GetTypeString((*api.VolEditReq)(nil)),
 			ResponseType: utils.GetTypeString((*api.VolumeEditResp)(nil)),
 			HandlerFunc:  volumeEditHandler},
+		route.Route{
+			Name:         "VolumeDeletionProtection",
+			Method:       "PATCH",
+			Pattern:      "/volumes/{volname}/deletion-protection",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolDeletionProtectionReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolumeDeletionProtectionResp)(nil)),
+			HandlerFunc:  volumeDeletionProtectionHandler},
+		route.Route{
+			Name:         "VolumeLabels",
+			Method:       "PATCH",
+			Pattern:      "/volumes/{volname}/labels",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolLabelsReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolumeLabelsResp)(nil)),
+			HandlerFunc:  volumeLabelsHandler},
+		route.Route{
+			Name:         "VolumeResourceLimits",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/resource-limits",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.VolResourceLimitsResp)(nil)),
+			HandlerFunc:  volumeResourceLimitsGetHandler},
+		route.Route{
+			Name:         "VolumeResourceLimitsSet",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/resource-limits",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*api.VolResourceLimitsReq)(nil)),
+			ResponseType: utils.GetTypeString((*api.VolResourceLimitsResp)(nil)),
+			HandlerFunc:  volumeResourceLimitsSetHandler},
 		route.Route{
 			Name:         "ProfileVolume",
 			Method:       "GET",
@@ -159,6 +413,18 @@ func (c *Command) Routes() route.Routes {
 			Version:      1,
 			ResponseType: utils.GetTypeString((*api.BrickProfileInfo)(nil)),
 			HandlerFunc:  volumeProfileHandler},
+		route.Route{
+			Name:        "ProfileVolumeStart",
+			Method:      "POST",
+			Pattern:     "/volumes/{volname}/profile/start",
+			Version:     1,
+			HandlerFunc: volumeProfileStartHandler},
+		route.Route{
+			Name:        "ProfileVolumeStop",
+			Method:      "POST",
+			Pattern:     "/volumes/{volname}/profile/stop",
+			Version:     1,
+			HandlerFunc: volumeProfileStopHandler},
 	}
 }
 
@@ -168,11 +434,20 @@ func (c *Command) RegisterStepFuncs() {
 	registerVolDeleteStepFuncs()
 	registerVolStartStepFuncs()
 	registerVolStopStepFuncs()
+	registerVolClientsStepFuncs()
+	registerVolTopStepFuncs()
+	registerVolImportStepFuncs()
 	registerBricksStatusStepFuncs()
 	registerVolExpandStepFuncs()
 	registerVolOptionStepFuncs()
 	registerVolOptionResetStepFuncs()
 	registerVolStatedumpFuncs()
 	registerReplaceBrickStepFuncs()
+	registerRemoveBrickStepFuncs()
+	registerVolChecksumStepFuncs()
 	registerVolProfileStepFuncs()
+	registerVolResourceLimitsStepFuncs()
+	registerBrickStatusDetailStepFuncs()
+	registerVolScheduleOps()
+	hooks.RegisterStepFuncs()
 }
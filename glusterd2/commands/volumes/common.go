@@ -6,14 +6,18 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/bricksplanner"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/options"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc"
 	"github.com/gluster/glusterd2/glusterd2/store"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
@@ -65,9 +69,16 @@ func validateOptions(opts map[string]string, flags api.VolOptionFlags) error {
 	return nil
 }
 
+// validateXlatorOptions checks the options against the current type and state
+// of the volume using the validation functions registered by the xlators, and
+// normalizes the option keys. All the conflicting options are reported.
 func validateXlatorOptions(opts map[string]string, volinfo *volume.Volinfo) error {
 	var toreplace [][]string
+	var conflicts []string
 	for k, v := range opts {
+		if options.IsUserOption(k) {
+			continue
+		}
 		graphName, xl, key := options.SplitKey(k)
 		xltr, err := xlator.Find(xl)
 		if err != nil {
@@ -75,7 +86,8 @@ func validateXlatorOptions(opts map[string]string, volinfo *volume.Volinfo) erro
 		}
 		if xltr.Validate != nil {
 			if err := xltr.Validate(volinfo, key, v); err != nil {
-				return err
+				conflicts = append(conflicts, fmt.Sprintf("%s: %s", k, err))
+				continue
 			}
 		}
 
@@ -91,6 +103,11 @@ func validateXlatorOptions(opts map[string]string, volinfo *volume.Volinfo) erro
 		}
 	}
 
+	if len(conflicts) != 0 {
+		sort.Strings(conflicts)
+		return fmt.Errorf("options conflict with volume %s: %s", volinfo.Name, strings.Join(conflicts, "; "))
+	}
+
 	for _, v := range toreplace {
 		delete(opts, v[0])
 		opts[v[1]] = v[2]
@@ -437,6 +454,18 @@ func txnGenerateBrickVolfiles(c transaction.TxnCtx) error {
 	return nil
 }
 
+// untrackDefaultOptions marks the given options of the volume as no longer
+// inherited from the cluster default volume options
+func untrackDefaultOptions(volinfo *volume.Volinfo, opts map[string]string) {
+	var tracked []string
+	for _, k := range volinfo.DefaultOptions {
+		if _, ok := opts[k]; !ok {
+			tracked = append(tracked, k)
+		}
+	}
+	volinfo.DefaultOptions = tracked
+}
+
 func containsReservedGroupProfile(opts interface{}) bool {
 	pfx := "profile.default."
 	switch value := opts.(type) {
@@ -456,3 +485,16 @@ func containsReservedGroupProfile(opts interface{}) bool {
 
 	return false
 }
+
+// lockDevices obtains the devices lock, which is held by the operations
+// planning bricks on devices until the planned bricks are provisioned, so that
+// the same device space is not handed out twice. The devices lock is obtained
+// before the volume locks.
+func lockDevices() (transaction.Locks, int, error) {
+	locks := transaction.Locks{}
+	if err := locks.Lock(bricksplanner.DevicesLockID); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		return nil, status, err
+	}
+	return locks, http.StatusOK, nil
+}
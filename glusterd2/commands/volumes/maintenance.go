@@ -0,0 +1,33 @@
+package volumecommands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+)
+
+// warnPeersInMaintenance adds a Warning header to the response if any of the
+// peers of the volume is under maintenance, as the operation may not be in
+// effect on those peers or may be undone when they are restarted.
+func warnPeersInMaintenance(ctx context.Context, w http.ResponseWriter, volinfo *volume.Volinfo) {
+	var names []string
+	for _, id := range volinfo.Nodes() {
+		p, err := peer.GetPeer(id.String())
+		if err != nil || !p.InMaintenance() {
+			continue
+		}
+		names = append(names, p.Name)
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	msg := fmt.Sprintf("peers under maintenance: %s", strings.Join(names, ", "))
+	gdctx.GetReqLogger(ctx).WithField("volume", volinfo.Name).Warn(msg)
+	w.Header().Add("Warning", fmt.Sprintf("199 glusterd2 %q", msg))
+}
@@ -0,0 +1,113 @@
+package volumecommands
+
+import (
+	"net/http"
+	"strings"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+// parseVolType parses a volume type like Replicate or distreplicate
+func parseVolType(s string) (volume.VolType, error) {
+	for t := volume.Distribute; t <= volume.DistDisperse; t++ {
+		if strings.EqualFold(t.String(), s) {
+			return t, nil
+		}
+	}
+	return 0, gderrors.ErrInvalidVolType
+}
+
+func volfileTemplateListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	overrides, err := volgen.GetTemplateOverrides()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.VolfileTemplateListResp(overrides))
+}
+
+func volfileTemplateGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	vtype, err := parseVolType(mux.Vars(r)["voltype"])
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	o, err := volgen.GetTemplateOverride(vtype, mux.Vars(r)["name"])
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, o)
+}
+
+func volfileTemplateSetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	vtype, err := parseVolType(mux.Vars(r)["voltype"])
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req api.VolfileTemplateReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	o := api.VolfileTemplate{
+		VolfileTemplateReq: req,
+		Name:               mux.Vars(r)["name"],
+		VolType:            api.VolType(vtype),
+	}
+
+	if err := volgen.ValidateTemplateOverride(&o); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := volgen.SetTemplateOverride(&o); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, o)
+}
+
+func volfileTemplateDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	vtype, err := parseVolType(mux.Vars(r)["voltype"])
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	name := mux.Vars(r)["name"]
+
+	if _, err := volgen.GetTemplateOverride(vtype, name); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if err := volgen.DeleteTemplateOverride(vtype, name); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
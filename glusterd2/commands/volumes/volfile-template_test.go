@@ -0,0 +1,28 @@
+package volumecommands
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVolType(t *testing.T) {
+	for s, expected := range map[string]volume.VolType{
+		"Distribute":    volume.Distribute,
+		"replicate":     volume.Replicate,
+		"DISPERSE":      volume.Disperse,
+		"distreplicate": volume.DistReplicate,
+		"DistDisperse":  volume.DistDisperse,
+	} {
+		vtype, err := parseVolType(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, vtype, s)
+	}
+
+	for _, s := range []string{"", "arbiter", "Distributed-Replicate"} {
+		_, err := parseVolType(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -0,0 +1,62 @@
+package volumecommands
+
+import (
+	"net/http"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+)
+
+func volumeProfileCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req api.VolumeProfileReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	if !volume.IsValidName(req.Name) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid volume profile name")
+		return
+	}
+
+	if req.SnapshotReserveFactor != 0 && req.SnapshotReserveFactor < 1 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid snapshot reserve factor")
+		return
+	}
+
+	if containsReservedGroupProfile(req.Options) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrReservedGroupProfile)
+		return
+	}
+
+	options, err := expandGroupOptions(req.Options)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := validateOptions(options, req.VolOptionFlags); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := getVolumeProfile(req.Name); err == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, gderrors.ErrVolProfileExists)
+		return
+	} else if err != gderrors.ErrVolProfileNotFound {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := addVolumeProfile(&req.VolumeProfile); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SetLocationHeader(r, w, req.Name)
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, req.VolumeProfile)
+}
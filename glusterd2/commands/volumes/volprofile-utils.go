@@ -0,0 +1,99 @@
+package volumecommands
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	volProfilePrefix string = "config/volume-profiles/"
+)
+
+func getVolumeProfile(name string) (*api.VolumeProfile, error) {
+	resp, err := store.Get(context.TODO(), volProfilePrefix+name)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Count != 1 {
+		return nil, gderrors.ErrVolProfileNotFound
+	}
+
+	var profile api.VolumeProfile
+	if err := json.Unmarshal(resp.Kvs[0].Value, &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func getVolumeProfiles() ([]api.VolumeProfile, error) {
+	resp, err := store.Get(context.TODO(), volProfilePrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]api.VolumeProfile, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var profile api.VolumeProfile
+		if err := json.Unmarshal(kv.Value, &profile); err != nil {
+			log.WithError(err).WithField("profile", string(kv.Key)).Error("failed to unmarshal volume profile")
+			continue
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+func addVolumeProfile(profile *api.VolumeProfile) error {
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(context.TODO(), volProfilePrefix+profile.Name, string(data))
+	return err
+}
+
+func deleteVolumeProfile(name string) error {
+	_, err := store.Delete(context.TODO(), volProfilePrefix+name)
+	return err
+}
+
+// applyVolumeProfile fills the fields of the volume create request which are
+// not explicitly set with the values from the given profile
+func applyVolumeProfile(req *api.VolCreateReq, profile *api.VolumeProfile) {
+	if req.Options == nil {
+		req.Options = make(map[string]string)
+	}
+	for k, v := range profile.Options {
+		if _, exists := req.Options[k]; !exists {
+			req.Options[k] = v
+		}
+	}
+
+	if req.SubvolType == "" {
+		req.SubvolType = profile.SubvolType
+	}
+	if req.SnapshotReserveFactor == 0 {
+		req.SnapshotReserveFactor = profile.SnapshotReserveFactor
+	}
+	if len(req.LimitPeers) == 0 {
+		req.LimitPeers = profile.LimitPeers
+	}
+	if len(req.LimitZones) == 0 {
+		req.LimitZones = profile.LimitZones
+	}
+	if len(req.ExcludePeers) == 0 {
+		req.ExcludePeers = profile.ExcludePeers
+	}
+	if len(req.ExcludeZones) == 0 {
+		req.ExcludeZones = profile.ExcludeZones
+	}
+}
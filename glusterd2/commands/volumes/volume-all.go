@@ -0,0 +1,140 @@
+package volumecommands
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"go.opencensus.io/trace"
+)
+
+// defaultAllVolumesConcurrency is the number of volumes started or stopped in
+// parallel by the start-all and stop-all requests
+const defaultAllVolumesConcurrency = 4
+
+func parseConcurrencyParam(r *http.Request) (int, error) {
+	v := r.URL.Query().Get("concurrency")
+	if v == "" {
+		return defaultAllVolumesConcurrency, nil
+	}
+
+	concurrency, err := strconv.Atoi(v)
+	if err != nil || concurrency < 1 {
+		return 0, fmt.Errorf("invalid value for concurrency: %s", v)
+	}
+	return concurrency, nil
+}
+
+// forEachVolume runs fn on the volumes with at most concurrency volumes being
+// processed at a time, and reports the result for each volume
+func forEachVolume(volumes []*volume.Volinfo, concurrency int, fn func(volname string) (int, error)) api.VolBulkResp {
+	resp := make(api.VolBulkResp, len(volumes))
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for idx, v := range volumes {
+		resp[idx].Name = v.Name
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(idx int, volname string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			status, err := fn(volname)
+			resp[idx].Status = status
+			if err != nil {
+				resp[idx].Error = err.Error()
+			}
+		}(idx, v.Name)
+	}
+	wg.Wait()
+
+	return resp
+}
+
+func volumeStartAllHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, span := trace.StartSpan(ctx, "/volumeStartAllHandler")
+	defer span.End()
+
+	var req api.VolumeStartReq
+	// request body is optional
+	if err := restutils.UnmarshalRequest(r, &req); err != nil && err != io.EOF {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	concurrency, err := parseConcurrencyParam(r)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	volumes, err := volume.GetVolumes(ctx)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	volumes = volume.ApplyCustomFilters(volumes, volume.FilterByReqNamespaces(ctx))
+	var stopped []*volume.Volinfo
+	for _, v := range volumes {
+		if v.State != volume.VolStarted {
+			stopped = append(stopped, v)
+		}
+	}
+
+	resp := forEachVolume(stopped, concurrency, func(volname string) (int, error) {
+		volinfo, status, err := StartVolume(ctx, volname, req)
+		if err != nil {
+			return status, err
+		}
+		events.Broadcast(volume.NewEvent(volume.EventVolumeStarted, volinfo))
+		return status, nil
+	})
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func volumeStopAllHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, span := trace.StartSpan(ctx, "/volumeStopAllHandler")
+	defer span.End()
+
+	graceful, timeout, err := parseGracefulStopParams(r)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	concurrency, err := parseConcurrencyParam(r)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	volumes, err := volume.GetVolumes(ctx)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	volumes = volume.ApplyCustomFilters(volumes, volume.FilterByReqNamespaces(ctx), volume.FilterByState("started"))
+
+	resp := forEachVolume(volumes, concurrency, func(volname string) (int, error) {
+		volinfo, status, err := StopVolume(ctx, volname, graceful, timeout)
+		if err != nil {
+			return status, err
+		}
+		events.Broadcast(volume.NewEvent(volume.EventVolumeStopped, volinfo))
+		return status, nil
+	})
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -0,0 +1,129 @@
+package volumecommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/bricksplanner"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"go.opencensus.io/trace"
+)
+
+func volumeBulkCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req api.VolBulkCreateReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	if len(req) == 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "no volumes specified")
+		return
+	}
+
+	ctx, span := trace.StartSpan(ctx, "/volumeBulkCreateHandler")
+	defer span.End()
+
+	// Hold the devices lock for the whole batch so that the space planned
+	// for the bricks of all volumes is not handed out to anyone else before
+	// the bricks are provisioned.
+	locks, status, err := lockDevices()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer locks.UnLock(ctx)
+
+	resp := make(api.VolBulkResp, len(req))
+	reserved := make(bricksplanner.Reservations)
+	names := make(map[string]bool)
+	defaultOpts := make([][]string, len(req))
+
+	// Plan all the volumes in a single pass before running any transaction
+	for idx := range req {
+		resp[idx].Name = req[idx].Name
+		if names[req[idx].Name] {
+			resp[idx].Status = http.StatusBadRequest
+			resp[idx].Error = gderrors.ErrVolExists.Error()
+			continue
+		}
+		names[req[idx].Name] = true
+
+		if status, err := resolveNamespace(ctx, &req[idx]); err != nil {
+			resp[idx].Status = status
+			resp[idx].Error = err.Error()
+			continue
+		}
+		opts, status, err := prepareVolCreateReq(&req[idx], reserved)
+		if err != nil {
+			resp[idx].Status = status
+			resp[idx].Error = err.Error()
+			continue
+		}
+		defaultOpts[idx] = opts
+	}
+
+	for idx := range req {
+		if resp[idx].Error != "" {
+			continue
+		}
+
+		status, err := createVolume(ctx, &req[idx], defaultOpts[idx])
+		resp[idx].Status = status
+		if err != nil {
+			resp[idx].Error = err.Error()
+			continue
+		}
+
+		volinfo, err := volume.GetVolume(req[idx].Name)
+		if err != nil {
+			resp[idx].Status = http.StatusInternalServerError
+			resp[idx].Error = err.Error()
+			continue
+		}
+
+		logger.WithField("volume-name", volinfo.Name).Info("new volume created")
+		events.Broadcast(volume.NewEvent(volume.EventVolumeCreated, volinfo))
+		if volinfo.State == volume.VolStarted {
+			events.Broadcast(volume.NewEvent(volume.EventVolumeStarted, volinfo))
+		}
+		resp[idx].Volume = createVolumeCreateResp(volinfo)
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func volumeBulkDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req api.VolBulkDeleteReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	if len(req) == 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "no volumes specified")
+		return
+	}
+
+	resp := make(api.VolBulkResp, len(req))
+	for idx, volname := range req {
+		resp[idx].Name = volname
+		status, err := DeleteVolume(ctx, volname)
+		resp[idx].Status = status
+		if err != nil {
+			resp[idx].Error = err.Error()
+		}
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
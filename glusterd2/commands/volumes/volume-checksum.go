@@ -0,0 +1,240 @@
+package volumecommands
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/gorilla/mux"
+	config "github.com/spf13/viper"
+)
+
+const (
+	volChecksumTxnKey string = "volchecksum"
+)
+
+func registerVolChecksumStepFuncs() {
+	transaction.RegisterStepFunc(volumeChecksum, "vol-checksum.Collect")
+}
+
+func volfilePath(volfileID string) string {
+	return path.Join(config.GetString("localstatedir"), "volfiles", volfileID+".vol")
+}
+
+// clientVolfileChecksum returns the checksum of the client volfile this node
+// serves for the volume. A volfile saved in the volfiles directory is served
+// instead of the one generated from the volinfo.
+func clientVolfileChecksum(volinfo *volume.Volinfo) (uint64, error) {
+	content, err := ioutil.ReadFile(volfilePath(volinfo.Name))
+	if err == nil {
+		return volgen.Checksum(string(content)), nil
+	} else if !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	tmpl, err := volgen.GetTemplateFromVolinfo(volinfo, "client")
+	if err != nil {
+		return 0, err
+	}
+
+	volfile, err := volgen.VolumeLevelVolfile(tmpl, volinfo)
+	if err != nil {
+		return 0, err
+	}
+
+	return volgen.Checksum(volfile), nil
+}
+
+// brickVolfileChecksum returns the checksum of the volfile of the local brick
+// and whether it matches the volfile generated from the volinfo
+func brickVolfileChecksum(volinfo *volume.Volinfo, b brick.Brickinfo) (uint64, bool, error) {
+	tmpl, err := volgen.GetTemplateFromVolinfo(volinfo, "brick")
+	if err != nil {
+		return 0, false, err
+	}
+
+	expected, err := volgen.BrickLevelVolfile(tmpl, volinfo, b.PeerID.String(), b.Path)
+	if err != nil {
+		return 0, false, err
+	}
+
+	content, err := ioutil.ReadFile(volfilePath(brick.GetVolfileID(b.VolumeName, b.Path)))
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+
+	checksum := volgen.Checksum(string(content))
+	return checksum, checksum == volgen.Checksum(expected), nil
+}
+
+func volumeChecksum(c transaction.TxnCtx) error {
+	var volname string
+	if err := c.Get("volname", &volname); err != nil {
+		return err
+	}
+
+	var nodeChecksum api.VolumeNodeChecksum
+	nodeChecksum.PeerID = gdctx.MyUUID
+
+	// Errors are reported as part of the result so that the node shows up
+	// as out of sync instead of failing the whole check
+	defer func() {
+		c.SetNodeResult(gdctx.MyUUID, volChecksumTxnKey, &nodeChecksum)
+	}()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		nodeChecksum.Error = err.Error()
+		return nil
+	}
+
+	clientChecksum, err := clientVolfileChecksum(volinfo)
+	if err != nil {
+		nodeChecksum.Error = err.Error()
+		return nil
+	}
+	nodeChecksum.Volfiles = append(nodeChecksum.Volfiles, api.VolfileChecksum{
+		VolfileID: volname,
+		Checksum:  clientChecksum,
+	})
+
+	// Brick volfiles are generated when the volume is started
+	if volinfo.State != volume.VolStarted {
+		return nil
+	}
+
+	for _, b := range volinfo.GetLocalBricks() {
+		checksum, inSync, err := brickVolfileChecksum(volinfo, b)
+		if err != nil {
+			nodeChecksum.Error = err.Error()
+			return nil
+		}
+		nodeChecksum.Volfiles = append(nodeChecksum.Volfiles, api.VolfileChecksum{
+			VolfileID: brick.GetVolfileID(b.VolumeName, b.Path),
+			Checksum:  checksum,
+			InSync:    inSync,
+		})
+	}
+
+	return nil
+}
+
+// checkNodeChecksum sets whether the volfiles found on a peer are in sync.
+// The volinfo is the same for all the peers as they all read it from the
+// store, so only the volfiles on the disks of the peers can drift. The client
+// volfile of a peer is in sync if it has the given checksum of the client
+// volfile generated from the volinfo.
+func checkNodeChecksum(n *api.VolumeNodeChecksum, volname string, clientChecksum uint64) {
+	n.InSync = n.Error == ""
+	for i, v := range n.Volfiles {
+		if v.VolfileID == volname {
+			n.Volfiles[i].InSync = v.Checksum == clientChecksum
+		}
+		n.InSync = n.InSync && n.Volfiles[i].InSync
+	}
+}
+
+func volumeChecksumHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	version, checksum, err := volume.GetVolumeChecksum(volname)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	tmpl, err := volgen.GetTemplateFromVolinfo(volinfo, "client")
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	clientVolfile, err := volgen.VolumeLevelVolfile(tmpl, volinfo)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Clients can fetch the volfile from any peer, so all of them are
+	// checked and not only the ones hosting bricks
+	allNodes, err := peer.GetPeerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "vol-checksum.Collect",
+			Nodes:  allNodes,
+		},
+	}
+
+	if err := txn.Ctx.Set("volname", volname); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Peers which are down show up as out of sync
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to collect volume checksum from all peers")
+	}
+
+	resp := &api.VolumeChecksumResp{
+		Volname:  volname,
+		Version:  version,
+		Checksum: checksum,
+		InSync:   true,
+	}
+
+	clientChecksum := volgen.Checksum(clientVolfile)
+	for _, node := range allNodes {
+		var nodeChecksum api.VolumeNodeChecksum
+		if err := txn.Ctx.GetNodeResult(node, volChecksumTxnKey, &nodeChecksum); err != nil {
+			nodeChecksum = api.VolumeNodeChecksum{
+				PeerID: node,
+				Error:  "failed to get checksum from peer",
+			}
+		}
+
+		checkNodeChecksum(&nodeChecksum, volname, clientChecksum)
+		if !nodeChecksum.InSync {
+			resp.InSync = false
+			resp.OutOfSyncPeers = append(resp.OutOfSyncPeers, node)
+		}
+		resp.Nodes = append(resp.Nodes, nodeChecksum)
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
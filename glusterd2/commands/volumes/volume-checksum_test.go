@@ -0,0 +1,50 @@
+package volumecommands
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckNodeChecksum(t *testing.T) {
+	const clientChecksum = 42
+
+	tests := []struct {
+		name   string
+		node   api.VolumeNodeChecksum
+		inSync bool
+	}{
+		{
+			name: "in sync",
+			node: api.VolumeNodeChecksum{Volfiles: []api.VolfileChecksum{
+				{VolfileID: "gv0", Checksum: clientChecksum},
+				{VolfileID: "gv0.node1.bricks-b1", Checksum: 7, InSync: true},
+			}},
+			inSync: true,
+		},
+		{
+			name: "client volfile drifted",
+			node: api.VolumeNodeChecksum{Volfiles: []api.VolfileChecksum{
+				{VolfileID: "gv0", Checksum: 43},
+			}},
+		},
+		{
+			name: "brick volfile drifted",
+			node: api.VolumeNodeChecksum{Volfiles: []api.VolfileChecksum{
+				{VolfileID: "gv0", Checksum: clientChecksum},
+				{VolfileID: "gv0.node1.bricks-b1", Checksum: 8},
+			}},
+		},
+		{
+			name: "error",
+			node: api.VolumeNodeChecksum{Error: "failed to get checksum from peer"},
+		},
+	}
+
+	for _, tt := range tests {
+		checkNodeChecksum(&tt.node, "gv0", clientChecksum)
+		assert.Equal(t, tt.inSync, tt.node.InSync, tt.name)
+	}
+}
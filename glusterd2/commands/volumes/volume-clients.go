@@ -0,0 +1,257 @@
+package volumecommands
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc/dict"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	brickClientsTxnKey string = "brickclients"
+
+	// cliStatusClients is GF_CLI_STATUS_CLIENTS, the brick status command
+	// which dumps the clients connected to the brick
+	cliStatusClients = 0x2
+)
+
+// internalClients are the names of the gluster daemons which connect to the
+// bricks of a volume and are not considered as mounts of the volume
+var internalClients = map[string]bool{
+	"glustershd": true,
+	"quotad":     true,
+	"rebalance":  true,
+	"bitd":       true,
+	"scrubber":   true,
+	"snapd":      true,
+}
+
+func registerVolClientsStepFuncs() {
+	transaction.RegisterStepFunc(txnBrickClients, "vol-clients.Get")
+}
+
+// getBrickClients queries the brick process for the list of connected clients
+func getBrickClients(b brick.Brickinfo) ([]api.ClientInfo, error) {
+	brickDaemon, err := brick.NewGlusterfsd(b)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := daemon.GetRPCClient(brickDaemon)
+	if err != nil {
+		return nil, err
+	}
+
+	reqDict := map[string]string{
+		"cmd":        strconv.Itoa(cliStatusClients),
+		"volname":    b.VolumeName,
+		"brick-name": b.Path,
+	}
+	req := &brick.GfBrickOpReq{
+		Name: b.Path,
+		Op:   int(brick.OpBrickStatus),
+	}
+	if req.Input, err = dict.Serialize(reqDict); err != nil {
+		return nil, err
+	}
+
+	var rsp brick.GfBrickOpRsp
+	if err := client.Call("Brick.OpBrickStatus", req, &rsp); err != nil {
+		return nil, err
+	}
+	if rsp.OpRet != 0 {
+		return nil, fmt.Errorf("brick status failed: %s", rsp.OpErrstr)
+	}
+
+	output, err := dict.Unserialize(rsp.Output)
+	if err != nil {
+		return nil, err
+	}
+
+	count, _ := strconv.Atoi(output["clientcount"])
+	clients := make([]api.ClientInfo, 0, count)
+	for i := 0; i < count; i++ {
+		prefix := fmt.Sprintf("client%d.", i)
+		c := api.ClientInfo{
+			Hostname: output[prefix+"hostname"],
+			Name:     output[prefix+"name"],
+		}
+		c.BytesRead, _ = strconv.ParseUint(output[prefix+"bytesread"], 10, 64)
+		c.BytesWritten, _ = strconv.ParseUint(output[prefix+"byteswrite"], 10, 64)
+		opversion, _ := strconv.ParseUint(output[prefix+"opversion"], 10, 32)
+		c.OpVersion = uint32(opversion)
+		c.PID = clientPID(output[prefix+"client-uid"])
+		c.Internal = internalClients[c.Name]
+		clients = append(clients, c)
+	}
+
+	return clients, nil
+}
+
+// clientPID returns the PID of the client process from its client uid,
+// CTX_ID:<uuid>-GRAPH_ID:<n>-PID:<pid>-HOST:<host>-..., reported by newer
+// bricks. It returns 0 if not known.
+func clientPID(uid string) int {
+	for _, field := range strings.Split(uid, "-") {
+		if strings.HasPrefix(field, "PID:") {
+			pid, _ := strconv.Atoi(strings.TrimPrefix(field, "PID:"))
+			return pid
+		}
+	}
+	return 0
+}
+
+func txnBrickClients(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	var result []api.BrickClients
+	for _, b := range volinfo.GetLocalBricks() {
+		clients, err := getBrickClients(b)
+		if err != nil {
+			c.Logger().WithError(err).WithField(
+				"brick", b.String()).Error("failed to get clients connected to brick")
+			return err
+		}
+		result = append(result, api.BrickClients{
+			Brick:   brick.CreateBrickInfo(&b),
+			Clients: clients,
+		})
+	}
+
+	return c.SetNodeResult(gdctx.MyUUID, brickClientsTxnKey, result)
+}
+
+// getVolumeClients returns the clients connected to each brick of a started
+// volume
+func getVolumeClients(ctx context.Context, volinfo *volume.Volinfo) ([]api.BrickClients, error) {
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "vol-clients.Get",
+			Nodes:  volinfo.Nodes(),
+		},
+	}
+	txn.DisableRollback = true
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		return nil, err
+	}
+
+	if err := txn.Do(); err != nil {
+		return nil, err
+	}
+
+	var result []api.BrickClients
+	for _, node := range volinfo.Nodes() {
+		var tmp []api.BrickClients
+		if err := txn.Ctx.GetNodeResult(node, brickClientsTxnKey, &tmp); err != nil {
+			return nil, err
+		}
+		result = append(result, tmp...)
+	}
+
+	return result, nil
+}
+
+// externalClients returns the unique hostnames of the clients, other than
+// gluster daemons, connected to any of the bricks
+func externalClients(bricks []api.BrickClients) []string {
+	seen := make(map[string]bool)
+	var hosts []string
+	for _, b := range bricks {
+		for _, c := range b.Clients {
+			if c.Internal || seen[c.Hostname] {
+				continue
+			}
+			seen[c.Hostname] = true
+			hosts = append(hosts, c.Hostname)
+		}
+	}
+	return hosts
+}
+
+// aggregateClients merges the connections of a client process to the bricks
+// of the volume. The hostname reported by the bricks is the address of the
+// connection, which has a different port for each brick.
+func aggregateClients(bricks []api.BrickClients) []api.VolumeClient {
+	var clients []api.VolumeClient
+	index := make(map[string]int)
+	for _, b := range bricks {
+		brickName := b.Brick.Hostname + ":" + b.Brick.Path
+		for _, c := range b.Clients {
+			host := c.Hostname
+			if h, _, err := net.SplitHostPort(c.Hostname); err == nil {
+				host = h
+			}
+
+			key := fmt.Sprintf("%s/%d/%s", host, c.PID, c.Name)
+			i, ok := index[key]
+			if !ok {
+				i = len(clients)
+				index[key] = i
+				clients = append(clients, api.VolumeClient{
+					Hostname:      host,
+					PID:           c.PID,
+					MountProtocol: c.Name,
+					OpVersion:     c.OpVersion,
+					Internal:      c.Internal,
+				})
+			}
+			clients[i].BytesRead += c.BytesRead
+			clients[i].BytesWritten += c.BytesWritten
+			clients[i].Bricks = append(clients[i].Bricks, brickName)
+		}
+	}
+	return clients
+}
+
+func volumeClientsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if volinfo.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrVolNotStarted)
+		return
+	}
+
+	bricks, err := getVolumeClients(ctx, volinfo)
+	if err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to get clients of volume")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := &api.VolumeClientsResp{
+		Volume:  volname,
+		Clients: aggregateClients(bricks),
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -0,0 +1,51 @@
+package volumecommands
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientPID(t *testing.T) {
+	assert.Equal(t, 1234, clientPID("CTX_ID:5a6b-GRAPH_ID:0-PID:1234-HOST:client1-PC_NAME:vol-client-0-RECON_NO:-0"))
+	assert.Equal(t, 0, clientPID(""))
+	assert.Equal(t, 0, clientPID("CTX_ID:5a6b-GRAPH_ID:0"))
+}
+
+// TestAggregateClients validates aggregateClients()
+func TestAggregateClients(t *testing.T) {
+	bricks := []api.BrickClients{
+		{
+			Brick: api.BrickInfo{Hostname: "node1", Path: "/bricks/b1"},
+			Clients: []api.ClientInfo{
+				{Hostname: "10.0.0.5:49150", Name: "fuse", PID: 100, BytesRead: 10, BytesWritten: 1, OpVersion: 40100},
+				{Hostname: "10.0.0.1:49149", Name: "glustershd", PID: 200, Internal: true},
+			},
+		},
+		{
+			Brick: api.BrickInfo{Hostname: "node2", Path: "/bricks/b2"},
+			Clients: []api.ClientInfo{
+				{Hostname: "10.0.0.5:49148", Name: "fuse", PID: 100, BytesRead: 5, BytesWritten: 2, OpVersion: 40100},
+				// another mount from the same host
+				{Hostname: "10.0.0.5:49147", Name: "gfapi", PID: 300},
+			},
+		},
+	}
+
+	clients := aggregateClients(bricks)
+	require.Len(t, clients, 3)
+
+	assert.Equal(t, "10.0.0.5", clients[0].Hostname)
+	assert.Equal(t, 100, clients[0].PID)
+	assert.Equal(t, "fuse", clients[0].MountProtocol)
+	assert.Equal(t, uint64(15), clients[0].BytesRead)
+	assert.Equal(t, uint64(3), clients[0].BytesWritten)
+	assert.Equal(t, []string{"node1:/bricks/b1", "node2:/bricks/b2"}, clients[0].Bricks)
+
+	assert.True(t, clients[1].Internal)
+	assert.Equal(t, "gfapi", clients[2].MountProtocol)
+	assert.Equal(t, []string{"node2:/bricks/b2"}, clients[2].Bricks)
+}
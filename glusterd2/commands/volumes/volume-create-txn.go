@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
@@ -125,10 +126,13 @@ func populateSubvols(volinfo *volume.Volinfo, req *api.VolCreateReq) error {
 	return nil
 }
 
-func newVolinfo(req *api.VolCreateReq) (*volume.Volinfo, error) {
+// newVolinfo returns the volinfo of the volume to create for the request, to
+// which the given cluster default volume options were applied
+func newVolinfo(req *api.VolCreateReq, defaultOpts []string) (*volume.Volinfo, error) {
 	volinfo := &volume.Volinfo{
 		ID:                    uuid.NewRandom(),
 		Name:                  req.Name,
+		Namespace:             req.Namespace,
 		VolfileID:             req.Name,
 		State:                 volume.VolCreated,
 		Type:                  voltypeFromSubvols(req),
@@ -136,6 +140,7 @@ func newVolinfo(req *api.VolCreateReq) (*volume.Volinfo, error) {
 		SnapList:              []string{},
 		SnapshotReserveFactor: req.SnapshotReserveFactor,
 		ProvisionerType:       req.ProvisionerType,
+		CreatedAt:             time.Now(),
 		Auth: volume.VolAuth{
 			Username: uuid.NewRandom().String(),
 			Password: uuid.NewRandom().String(),
@@ -148,6 +153,10 @@ func newVolinfo(req *api.VolCreateReq) (*volume.Volinfo, error) {
 		volinfo.Options = make(map[string]string)
 	}
 
+	// Keep track of the options which were inherited from the cluster wide
+	// default volume options
+	volinfo.DefaultOptions = defaultOpts
+
 	if req.Size != 0 {
 		volinfo.Capacity = req.Size
 	}
@@ -164,6 +173,12 @@ func newVolinfo(req *api.VolCreateReq) (*volume.Volinfo, error) {
 		volinfo.Metadata = make(map[string]string)
 	}
 
+	if req.Labels != nil {
+		volinfo.Labels = req.Labels
+	} else {
+		volinfo.Labels = make(map[string]string)
+	}
+
 	if req.Size > 0 {
 		//AutoProvisioned volume
 		volinfo.Metadata[brick.ProvisionKey] = string(brick.AutoProvisioned)
@@ -178,13 +193,10 @@ func newVolinfo(req *api.VolCreateReq) (*volume.Volinfo, error) {
 	return volinfo, nil
 }
 
-func createVolinfo(c transaction.TxnCtx) error {
-
-	var req api.VolCreateReq
-	if err := c.Get("req", &req); err != nil {
-		return err
-	}
-
+// prepareVolinfo returns the volinfo of the volume to create for a resolved
+// request, after checking the options of the request against it. The
+// options of the request are completed and normalized in place.
+func prepareVolinfo(req *api.VolCreateReq, defaultOpts []string) (*volume.Volinfo, error) {
 	if len(req.Subvols) > 0 && req.Subvols[0].ArbiterCount > 0 {
 		if req.Options == nil {
 			req.Options = make(map[string]string)
@@ -192,12 +204,37 @@ func createVolinfo(c transaction.TxnCtx) error {
 		req.Options["replicate.arbiter-count"] = fmt.Sprintf("%d", req.Subvols[0].ArbiterCount)
 	}
 
-	volinfo, err := newVolinfo(&req)
+	volinfo, err := newVolinfo(req, defaultOpts)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	// The volume is stored as started, the bricks are started by the steps
+	// which follow in the same transaction.
+	if req.StartVolume {
+		volinfo.State = volume.VolStarted
 	}
 
 	if err := validateXlatorOptions(req.Options, volinfo); err != nil {
+		return nil, err
+	}
+	return volinfo, nil
+}
+
+func createVolinfo(c transaction.TxnCtx) error {
+
+	var req api.VolCreateReq
+	if err := c.Get("req", &req); err != nil {
+		return err
+	}
+
+	var defaultOpts []string
+	if err := c.Get("default-options", &defaultOpts); err != nil {
+		return err
+	}
+
+	volinfo, err := prepareVolinfo(&req, defaultOpts)
+	if err != nil {
 		return err
 	}
 
@@ -5,14 +5,18 @@ import (
 	"errors"
 	"net/http"
 	"path/filepath"
+	"sort"
 	"strconv"
 
 	"github.com/gluster/glusterd2/glusterd2/bricksplanner"
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/hooks"
+	"github.com/gluster/glusterd2/glusterd2/options"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/glusterd2/users"
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/pkg/api"
 	gderrors "github.com/gluster/glusterd2/pkg/errors"
@@ -64,6 +68,10 @@ func validateVolCreateReq(req *api.VolCreateReq) error {
 		return gderrors.ErrMetadataSizeOutOfBounds
 	}
 
+	if err := volume.ValidateLabels(req.Labels); err != nil {
+		return err
+	}
+
 	return validateVolumeFlags(req.Flags)
 }
 
@@ -112,6 +120,15 @@ func volumeCreateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if validateOnly, _ := strconv.ParseBool(r.URL.Query().Get("validate")); validateOnly {
+		if status, err := ValidateVolumeCreate(ctx, &req); err != nil {
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, req)
+		return
+	}
+
 	if status, err := CreateVolume(ctx, req); err != nil {
 		restutils.SendHTTPError(ctx, w, status, err)
 		return
@@ -128,6 +145,9 @@ func volumeCreateHandler(w http.ResponseWriter, r *http.Request) {
 
 	logger.WithField("volume-name", volinfo.Name).Info("new volume created")
 	events.Broadcast(volume.NewEvent(volume.EventVolumeCreated, volinfo))
+	if volinfo.State == volume.VolStarted {
+		events.Broadcast(volume.NewEvent(volume.EventVolumeStarted, volinfo))
+	}
 
 	resp := createVolumeCreateResp(volinfo)
 	restutils.SetLocationHeader(r, w, volinfo.Name)
@@ -138,17 +158,29 @@ func createVolumeCreateResp(v *volume.Volinfo) *api.VolumeCreateResp {
 	return (*api.VolumeCreateResp)(volume.CreateVolumeInfoResp(v))
 }
 
-// CreateVolume creates a volume
-func CreateVolume(ctx context.Context, req api.VolCreateReq) (status int, err error) {
-	ctx, span := trace.StartSpan(ctx, "/volumeCreateHandler")
-	defer span.End()
+// prepareVolCreateReq validates the volume create request and resolves it in
+// place: the referenced volume profile is applied, bricks are planned for
+// smart volumes, group options are expanded and the cluster default volume
+// options and the default options profile for the volume type are applied.
+// It returns the names of the cluster default volume options which were
+// applied, as the request didn't set them.
+func prepareVolCreateReq(req *api.VolCreateReq, reserved bricksplanner.Reservations) ([]string, int, error) {
+	if req.Profile != "" {
+		profile, err := getVolumeProfile(req.Profile)
+		if err == gderrors.ErrVolProfileNotFound {
+			return nil, http.StatusBadRequest, err
+		} else if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		applyVolumeProfile(req, profile)
+	}
 
-	if err := validateVolCreateReq(&req); err != nil {
-		return http.StatusBadRequest, err
+	if err := validateVolCreateReq(req); err != nil {
+		return nil, http.StatusBadRequest, err
 	}
 
 	if containsReservedGroupProfile(req.Options) {
-		return http.StatusBadRequest, gderrors.ErrReservedGroupProfile
+		return nil, http.StatusBadRequest, gderrors.ErrReservedGroupProfile
 	}
 
 	if req.ProvisionerType == "" {
@@ -156,29 +188,38 @@ func CreateVolume(ctx context.Context, req api.VolCreateReq) (status int, err er
 	}
 
 	if req.Size > 0 {
-		applyDefaults(&req)
+		applyDefaults(req)
 
 		if req.SnapshotReserveFactor < 1 {
-			return http.StatusBadRequest, errors.New("invalid snapshot reserve factor")
+			return nil, http.StatusBadRequest, errors.New("invalid snapshot reserve factor")
 		}
 
-		if err := bricksplanner.PlanBricks(&req); err != nil {
-			return http.StatusInternalServerError, err
+		if err := bricksplanner.PlanBricksWithReservations(req, reserved); err != nil {
+			return nil, http.StatusInternalServerError, err
 		}
 	} else {
-		if err := checkDupBrickEntryVolCreate(req); err != nil {
-			return http.StatusBadRequest, err
+		if err := checkDupBrickEntryVolCreate(*req); err != nil {
+			return nil, http.StatusBadRequest, err
 		}
 	}
 
+	var err error
 	req.Options, err = expandGroupOptions(req.Options)
 	if err != nil {
-		return http.StatusInternalServerError, err
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if err := validateOptions(req.Options, req.VolOptionFlags); err != nil {
-		return http.StatusBadRequest, err
+		return nil, http.StatusBadRequest, err
+	}
+
+	// Include cluster wide default volume options. These take precedence
+	// over the default Volume Options profile of the volume type.
+	defaults, err := options.GetVolumeDefaultOptions()
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
 	}
+	defaultOpts := applyDefaultVolumeOptions(req.Options, defaults)
 
 	// Include default Volume Options profile
 	if len(req.Subvols) > 0 {
@@ -194,6 +235,111 @@ func CreateVolume(ctx context.Context, req api.VolCreateReq) (status int, err er
 		}
 	}
 
+	return defaultOpts, http.StatusOK, nil
+}
+
+// applyDefaultVolumeOptions sets the cluster default volume options which
+// are not set in opts, and returns the names of the options it set
+func applyDefaultVolumeOptions(opts, defaults map[string]string) []string {
+	var applied []string
+	for k, v := range defaults {
+		if _, exists := opts[k]; !exists {
+			opts[k] = v
+			applied = append(applied, k)
+		}
+	}
+	sort.Strings(applied)
+	return applied
+}
+
+// ValidateVolumeCreate runs all the checks done by CreateVolume, including
+// namespace access, option validation and brick planning, without executing
+// the transaction. On success req is left fully resolved with the computed
+// brick layout.
+func ValidateVolumeCreate(ctx context.Context, req *api.VolCreateReq) (int, error) {
+	if status, err := resolveNamespace(ctx, req); err != nil {
+		return status, err
+	}
+
+	defaultOpts, status, err := prepareVolCreateReq(req, nil)
+	if err != nil {
+		return status, err
+	}
+
+	if _, err := req.Nodes(); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	// The options are checked against the volume the transaction would
+	// create, on a copy of the request as the check completes its options
+	volReq := *req
+	volReq.Options = make(map[string]string, len(req.Options))
+	for k, v := range req.Options {
+		volReq.Options[k] = v
+	}
+	if _, err := prepareVolinfo(&volReq, defaultOpts); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	if volume.Exists(req.Name) {
+		return http.StatusBadRequest, gderrors.ErrVolExists
+	}
+
+	return http.StatusOK, nil
+}
+
+// resolveNamespace sets the namespace of the volume to create, which
+// defaults to the namespace the requester is bound to, if any, or else to
+// the default namespace. The requester must be able to access it.
+func resolveNamespace(ctx context.Context, req *api.VolCreateReq) (int, error) {
+	if req.Namespace == "" {
+		req.Namespace = api.DefaultNamespace
+		if namespaces := gdctx.GetReqNamespaces(ctx); len(namespaces) == 1 {
+			req.Namespace = namespaces[0]
+		}
+	}
+
+	if !users.IsValidNamespace(req.Namespace) {
+		return http.StatusBadRequest, gderrors.ErrInvalidNamespace
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, req.Namespace) {
+		return http.StatusForbidden, gderrors.ErrNamespaceNotAllowed
+	}
+	return http.StatusOK, nil
+}
+
+// CreateVolume creates a volume
+func CreateVolume(ctx context.Context, req api.VolCreateReq) (status int, err error) {
+	ctx, span := trace.StartSpan(ctx, "/volumeCreateHandler")
+	defer span.End()
+
+	if status, err := resolveNamespace(ctx, &req); err != nil {
+		return status, err
+	}
+
+	// Bricks of smart volumes are planned on the devices
+	if req.Size > 0 {
+		locks, status, err := lockDevices()
+		if err != nil {
+			return status, err
+		}
+		defer locks.UnLock(ctx)
+	}
+
+	defaultOpts, status, err := prepareVolCreateReq(&req, nil)
+	if err != nil {
+		return status, err
+	}
+
+	return createVolume(ctx, &req, defaultOpts)
+}
+
+// createVolume runs the volume create transaction for a request which has
+// already been resolved by prepareVolCreateReq, which applied the given
+// cluster default volume options
+func createVolume(ctx context.Context, req *api.VolCreateReq, defaultOpts []string) (int, error) {
+	span := trace.FromContext(ctx)
+
 	nodes, err := req.Nodes()
 	if err != nil {
 		return http.StatusBadRequest, err
@@ -215,6 +361,7 @@ func CreateVolume(ctx context.Context, req api.VolCreateReq) (status int, err er
 			UndoFunc: "vol-create.UndoPrepareBricks",
 			Nodes:    nodes,
 			Skip:     (req.Size == 0),
+			Timeout:  transaction.BrickPrepareTimeout,
 		},
 		{
 			DoFunc: "vol-create.CreateVolinfo",
@@ -226,20 +373,47 @@ func CreateVolume(ctx context.Context, req api.VolCreateReq) (status int, err er
 			// Need to wait for volinfo to be created first
 			Sync: true,
 		},
+		hooks.Step(hooks.OpCreate, hooks.Pre, nodes),
 		{
 			DoFunc:   "vol-create.InitBricks",
 			UndoFunc: "vol-create.UndoInitBricks",
 			Nodes:    nodes,
+			// Need to wait for pre hooks to succeed on all nodes
+			Sync: true,
 		},
 		{
 			DoFunc:   "vol-create.StoreVolume",
 			UndoFunc: "vol-create.UndoStoreVolume",
 			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Retry:    transaction.DefaultRetryPolicy,
 			Sync:     true,
 		},
+		hooks.Step(hooks.OpCreate, hooks.Post, nodes),
 	}
 
-	if err := txn.Ctx.Set("req", &req); err != nil {
+	if req.StartVolume {
+		txn.Steps = append(txn.Steps,
+			hooks.Step(hooks.OpStart, hooks.Pre, nodes),
+			&transaction.Step{
+				DoFunc:   "vol-start.StartBricks",
+				UndoFunc: "vol-start.StartBricksUndo",
+				Nodes:    nodes,
+				Sync:     true,
+			},
+			&transaction.Step{
+				DoFunc:   "vol-start.XlatorActionDoVolumeStart",
+				UndoFunc: "vol-start.XlatorActionUndoVolumeStart",
+				Nodes:    nodes,
+			},
+			hooks.Step(hooks.OpStart, hooks.Post, nodes),
+		)
+	}
+
+	if err := txn.Ctx.Set("req", req); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if err := txn.Ctx.Set("default-options", defaultOpts); err != nil {
 		return http.StatusInternalServerError, err
 	}
 
@@ -254,5 +428,10 @@ func CreateVolume(ctx context.Context, req api.VolCreateReq) (status int, err er
 		return restutils.ErrToStatusCode(err)
 	}
 
+	recordVolumeHistory(ctx, req.Name, volume.EventVolumeCreated, txn.Ctx.GetTxnID(), nil)
+	if req.StartVolume {
+		recordVolumeHistory(ctx, req.Name, volume.EventVolumeStarted, txn.Ctx.GetTxnID(), nil)
+	}
+
 	return http.StatusCreated, nil
 }
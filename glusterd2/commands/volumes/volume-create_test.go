@@ -1,13 +1,17 @@
 package volumecommands
 
 import (
+	"context"
 	"errors"
+	"net/http"
 	"testing"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
 	"github.com/gluster/glusterd2/pkg/testutils"
 
 	"github.com/pborman/uuid"
@@ -32,14 +36,83 @@ func TestCreateVolinfo(t *testing.T) {
 	}}}
 	msg.Metadata = make(map[string]string)
 	msg.Metadata["owner"] = "gd2test"
-	vol, e := newVolinfo(msg)
+	vol, e := newVolinfo(msg, []string{"performance.io-cache"})
 	assert.Nil(t, e)
 	assert.NotNil(t, vol)
+	assert.Equal(t, []string{"performance.io-cache"}, vol.DefaultOptions)
 
 	// Mock failure in NewBrickEntries(), createVolume() should fail
 	defer testutils.Patch(&volume.NewBrickEntriesFunc, func(bricks []api.BrickReq, volName, volfileID string, volID uuid.UUID, pT brick.ProvisionType) ([]brick.Brickinfo, error) {
 		return nil, errBad
 	}).Restore()
-	_, e = newVolinfo(msg)
+	_, e = newVolinfo(msg, nil)
 	assert.Equal(t, errBad, e)
 }
+
+// TestValidateVolumeCreateNamespace validates that ValidateVolumeCreate()
+// rejects the namespaces CreateVolume() would reject
+func TestValidateVolumeCreateNamespace(t *testing.T) {
+	ctx := gdctx.WithReqNamespaces(context.Background(), []string{"team1"})
+
+	tests := []struct {
+		namespace string
+		status    int
+		err       error
+	}{
+		{"team2", http.StatusForbidden, gderrors.ErrNamespaceNotAllowed},
+		{"not a namespace", http.StatusBadRequest, gderrors.ErrInvalidNamespace},
+	}
+
+	for _, tt := range tests {
+		req := &api.VolCreateReq{Name: "vol", Namespace: tt.namespace}
+		status, err := ValidateVolumeCreate(ctx, req)
+		assert.Equal(t, tt.status, status, tt.namespace)
+		assert.Equal(t, tt.err, err, tt.namespace)
+	}
+}
+
+// TestApplyDefaultVolumeOptions validates applyDefaultVolumeOptions()
+func TestApplyDefaultVolumeOptions(t *testing.T) {
+	defaults := map[string]string{
+		"performance.io-cache":   "off",
+		"performance.read-ahead": "off",
+		"features.shard":         "on",
+	}
+	// An option set in the request to the value of the default is not
+	// tracked as a default
+	opts := map[string]string{"performance.io-cache": "off", "features.shard": "off"}
+
+	applied := applyDefaultVolumeOptions(opts, defaults)
+	assert.Equal(t, []string{"performance.read-ahead"}, applied)
+	assert.Equal(t, map[string]string{
+		"performance.io-cache":   "off",
+		"performance.read-ahead": "off",
+		"features.shard":         "off",
+	}, opts)
+
+	assert.Empty(t, applyDefaultVolumeOptions(opts, nil))
+}
+
+// TestApplyVolumeProfile validates applyVolumeProfile()
+func TestApplyVolumeProfile(t *testing.T) {
+	profile := &api.VolumeProfile{
+		Name:                  "db",
+		Options:               map[string]string{"performance.io-cache": "off", "performance.read-ahead": "off"},
+		SubvolType:            "replicate",
+		SnapshotReserveFactor: 1.5,
+		LimitZones:            []string{"zone1"},
+	}
+
+	req := &api.VolCreateReq{
+		Name:       "vol",
+		LimitZones: []string{"zone2"},
+	}
+	req.Options = map[string]string{"performance.io-cache": "on"}
+
+	applyVolumeProfile(req, profile)
+	assert.Equal(t, "on", req.Options["performance.io-cache"])
+	assert.Equal(t, "off", req.Options["performance.read-ahead"])
+	assert.Equal(t, "replicate", req.SubvolType)
+	assert.Equal(t, 1.5, req.SnapshotReserveFactor)
+	assert.Equal(t, []string{"zone2"}, req.LimitZones)
+}
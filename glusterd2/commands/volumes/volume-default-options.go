@@ -0,0 +1,132 @@
+package volumecommands
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+)
+
+func getClusterOptionsOrNew() (*options.ClusterOptions, error) {
+	c, err := options.GetClusterOptions()
+	if err == errors.ErrClusterOptionsNotFound {
+		c = &options.ClusterOptions{Options: make(map[string]string)}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if c.VolumeOptions == nil {
+		c.VolumeOptions = make(map[string]string)
+	}
+	return c, nil
+}
+
+func volumeDefaultOptionsGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	defaults, err := options.GetVolumeDefaultOptions()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.VolumeDefaultOptionsResp(defaults))
+}
+
+func volumeDefaultOptionsSetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req api.VolumeDefaultOptionsReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	if containsReservedGroupProfile(req.Options) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrReservedGroupProfile)
+		return
+	}
+
+	opts, err := expandGroupOptions(req.Options)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := validateOptions(opts, req.VolOptionFlags); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, options.ClusterOptionsLockID)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	c, err := getClusterOptionsOrNew()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	for k, v := range opts {
+		c.VolumeOptions[k] = v
+	}
+
+	if err := options.UpdateClusterOptions(c); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.VolumeDefaultOptionsResp(c.VolumeOptions))
+}
+
+func volumeDefaultOptionsResetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req api.VolumeDefaultOptionsResetReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, options.ClusterOptionsLockID)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	c, err := getClusterOptionsOrNew()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if req.All {
+		c.VolumeOptions = make(map[string]string)
+	}
+	for _, k := range req.Options {
+		if _, ok := c.VolumeOptions[k]; !ok && !req.All {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest,
+				fmt.Sprintf("option %s is not a default volume option", k))
+			return
+		}
+		delete(c.VolumeOptions, k)
+	}
+
+	if err := options.UpdateClusterOptions(c); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.VolumeDefaultOptionsResp(c.VolumeOptions))
+}
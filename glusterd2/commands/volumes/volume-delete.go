@@ -1,6 +1,8 @@
 package volumecommands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
 	"github.com/gluster/glusterd2/glusterd2/volume"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
 
 	"github.com/gorilla/mux"
 	"github.com/pborman/uuid"
@@ -39,45 +42,56 @@ func registerVolDeleteStepFuncs() {
 func volumeDeleteHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
 	volname := mux.Vars(r)["volname"]
 
+	if status, err := DeleteVolume(ctx, volname); err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+// DeleteVolume deletes a stopped volume which has no snapshots
+func DeleteVolume(ctx context.Context, volname string) (int, error) {
+	logger := gdctx.GetReqLogger(ctx)
+
 	ctx, span := trace.StartSpan(ctx, "/volumeDeleteHandler")
 	defer span.End()
 
 	txn, err := transactionv2.NewTxnWithLocks(ctx, volname)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return restutils.ErrToStatusCode(err)
 	}
 	defer txn.Done()
 
 	volinfo, err := volume.GetVolume(volname)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return restutils.ErrToStatusCode(err)
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, volinfo.GetNamespace()) {
+		return http.StatusNotFound, gderrors.ErrVolNotFound
 	}
 
 	if volinfo.State == volume.VolStarted {
-		errMsg := "Volume must be in stopped state before deleting."
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errMsg)
-		return
+		return http.StatusBadRequest, errors.New("volume must be in stopped state before deleting")
+	}
+
+	if volinfo.IsDeletionProtected() {
+		return http.StatusForbidden, gderrors.ErrVolDeletionProtected
 	}
 
 	if len(volinfo.SnapList) > 0 {
-		errMsg := fmt.Sprintf("Cannot delete Volume %s ,as it has %d snapshots.", volname, len(volinfo.SnapList))
-		restutils.SendHTTPError(ctx, w, http.StatusFailedDependency, errMsg)
-		return
+		return http.StatusFailedDependency, fmt.Errorf("cannot delete volume %s, as it has %d snapshots", volname, len(volinfo.SnapList))
 	}
 
 	bricksAutoProvisioned := volinfo.IsAutoProvisioned() || volinfo.IsSnapshotProvisioned()
 	txn.Steps = []*transaction.Step{
 		{
-			DoFunc: "vol-delete.CleanBricks",
-			Nodes:  volinfo.Nodes(),
-			Skip:   !bricksAutoProvisioned,
+			DoFunc:  "vol-delete.CleanBricks",
+			Nodes:   volinfo.Nodes(),
+			Skip:    !bricksAutoProvisioned,
+			Timeout: transaction.BrickCleanTimeout,
 		},
 		{
 			DoFunc: "vol-delete.Store",
@@ -87,8 +101,7 @@ func volumeDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return http.StatusInternalServerError, err
 	}
 
 	span.AddAttributes(
@@ -99,11 +112,20 @@ func volumeDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	if err := txn.Do(); err != nil {
 		logger.WithError(err).WithField(
 			"volume", volname).Error("transaction to delete volume failed")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return http.StatusInternalServerError, err
+	}
+
+	if err := volume.DeleteHistory(volname); err != nil {
+		logger.WithError(err).WithField(
+			"volume", volname).Warn("failed to delete volume history")
+	}
+
+	if err := volume.DeleteUsage(volname); err != nil {
+		logger.WithError(err).WithField(
+			"volume", volname).Warn("failed to delete volume usage")
 	}
 
 	events.Broadcast(volume.NewEvent(volume.EventVolumeDeleted, volinfo))
 
-	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+	return http.StatusNoContent, nil
 }
@@ -0,0 +1,92 @@
+package volumecommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"go.opencensus.io/trace"
+)
+
+// validateDeletionProtectionEdit ensures that a metadata edit request doesn't
+// clear the deletion protection of a volume. Protection can be enabled using
+// volume edit but has to be cleared explicitly with the deletion-protection API.
+func validateDeletionProtectionEdit(req *api.VolEditReq) error {
+	value, ok := req.Metadata[volume.DeletionProtection]
+	if !ok {
+		return nil
+	}
+
+	if req.DeleteMetadata {
+		return errors.ErrDeletionProtectionEdit
+	}
+
+	if protected, err := options.StringToBoolean(value); err != nil || !protected {
+		return errors.ErrDeletionProtectionEdit
+	}
+	return nil
+}
+
+func volumeDeletionProtectionHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	ctx, span := trace.StartSpan(ctx, "/volumeDeletionProtectionHandler")
+	defer span.End()
+
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req api.VolDeletionProtectionReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if volinfo.Metadata == nil {
+		volinfo.Metadata = make(map[string]string)
+	}
+	if req.Enabled {
+		volinfo.Metadata[volume.DeletionProtection] = "yes"
+	} else {
+		delete(volinfo.Metadata, volume.DeletionProtection)
+	}
+
+	span.AddAttributes(
+		trace.StringAttribute("volName", volname),
+		trace.BoolAttribute("enabled", req.Enabled),
+	)
+
+	if err := volume.AddOrUpdateVolumeFunc(volinfo); err != nil {
+		logger.WithError(err).WithField(
+			"volume", volinfo.Name).Debug("failed to store volume info")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "failed to store volume info")
+		return
+	}
+
+	logger.WithField("volume", volname).WithField("enabled", req.Enabled).Info("volume deletion protection updated")
+
+	resp := (*api.VolumeDeletionProtectionResp)(volume.CreateVolumeInfoResp(volinfo))
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
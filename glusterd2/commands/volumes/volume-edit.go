@@ -46,6 +46,11 @@ func volumeEditHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := validateDeletionProtectionEdit(&req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
 	reqMetadataSize := req.MetadataSize()
 	if reqMetadataSize > maxMetadataSizeLimit {
 		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrMetadataSizeOutOfBounds)
@@ -10,6 +10,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/brickmux"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/lvmutils"
@@ -113,6 +114,11 @@ func startBricksOnExpand(c transaction.TxnCtx) error {
 		return err
 	}
 
+	// the peer may not have run bricks of the volume before
+	if err := volume.ApplyResourceLimits(&volinfo); err != nil {
+		return err
+	}
+
 	// check if bmux is enabled
 	bmuxEnabled, err := brickmux.Enabled()
 	if err != nil {
@@ -182,7 +188,7 @@ func undoStartBricksOnExpand(c transaction.TxnCtx) error {
 			"brick":  b.String(),
 		}).Info("volume expand failed, stopping brick")
 
-		if err := b.StopBrick(c.Logger()); err != nil {
+		if err := brickmux.StopBrick(b, c.Logger()); err != nil {
 			c.Logger().WithError(err).WithFields(log.Fields{
 				"volume": b.VolumeName,
 				"brick":  b.String(),
@@ -196,6 +202,47 @@ func undoStartBricksOnExpand(c transaction.TxnCtx) error {
 	return nil
 }
 
+// undoGenerateBrickVolfilesOnExpand deletes the volfiles of the new bricks and
+// regenerates the volfiles of the existing bricks from the volinfo as it was
+// before the expansion
+func undoGenerateBrickVolfilesOnExpand(c transaction.TxnCtx) error {
+
+	var newBricks []brick.Brickinfo
+	if err := c.Get("bricks", &newBricks); err != nil {
+		return err
+	}
+
+	var oldvolinfo volume.Volinfo
+	if err := c.Get("oldvolinfo", &oldvolinfo); err != nil {
+		return err
+	}
+
+	var localBricks []brick.Brickinfo
+	for _, b := range newBricks {
+		if uuid.Equal(b.PeerID, gdctx.MyUUID) {
+			localBricks = append(localBricks, b)
+		}
+	}
+
+	if err := volgen.DeleteBricksVolfiles(localBricks); err != nil {
+		c.Logger().WithError(err).WithFields(log.Fields{
+			"template": "brick",
+			"volume":   oldvolinfo.Name,
+		}).Error("failed to delete brick volfile")
+		return err
+	}
+
+	if err := volgen.GenerateBricksVolfiles(&oldvolinfo, oldvolinfo.GetLocalBricks()); err != nil {
+		c.Logger().WithError(err).WithFields(log.Fields{
+			"template": "brick",
+			"volume":   oldvolinfo.Name,
+		}).Error("failed to generate volfile")
+		return err
+	}
+
+	return nil
+}
+
 func updateVolinfoOnExpand(c transaction.TxnCtx) error {
 
 	var newBricks []brick.Brickinfo
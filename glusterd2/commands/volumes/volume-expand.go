@@ -1,8 +1,11 @@
 package volumecommands
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"path/filepath"
+	"strings"
 
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
@@ -14,6 +17,8 @@ import (
 	"github.com/gluster/glusterd2/pkg/errors"
 	"github.com/gluster/glusterd2/pkg/lvmutils"
 	"github.com/gluster/glusterd2/plugins/device/deviceutils"
+	"github.com/gluster/glusterd2/plugins/rebalance"
+	rebalanceapi "github.com/gluster/glusterd2/plugins/rebalance/api"
 
 	"github.com/gorilla/mux"
 	"github.com/pborman/uuid"
@@ -30,10 +35,11 @@ func registerVolExpandStepFuncs() {
 		{"vol-expand.InitBricks", initBricks},
 		{"vol-expand.UndoInitBricks", undoInitBricks},
 		{"vol-expand.GenerateBrickVolfiles", txnGenerateBrickVolfiles},
-		{"vol-expand.GenerateBrickVolfiles.Undo", txnDeleteBrickVolfiles},
+		{"vol-expand.GenerateBrickVolfiles.Undo", undoGenerateBrickVolfilesOnExpand},
 		{"vol-expand.StartBrick", startBricksOnExpand},
 		{"vol-expand.UndoStartBrick", undoStartBricksOnExpand},
 		{"vol-expand.UpdateVolinfo", updateVolinfoOnExpand},
+		{"vol-expand.UpdateVolinfo.Undo", undoStoreVolume},
 		{"vol-expand.NotifyClients", notifyVolfileChange},
 		{"vol-expand.LvmResize", resizeLVM},
 	}
@@ -65,12 +71,41 @@ func checkForLvmResize(req api.VolExpandReq, volinfo *volume.Volinfo) bool {
 	return false
 }
 
+// parseExpandRebalanceParam returns the rebalance start request for the
+// optional `rebalance` query parameter of the expand request. A nil request
+// is returned if rebalance wasn't asked for.
+func parseExpandRebalanceParam(r *http.Request) (*rebalanceapi.StartReq, error) {
+	switch r.URL.Query().Get("rebalance") {
+	case "":
+		return nil, nil
+	case "start":
+		return &rebalanceapi.StartReq{}, nil
+	case rebalanceapi.OptionFixLayout:
+		return &rebalanceapi.StartReq{Option: rebalanceapi.OptionFixLayout}, nil
+	case rebalanceapi.OptionForce:
+		return &rebalanceapi.StartReq{Option: rebalanceapi.OptionForce}, nil
+	default:
+		return nil, rebalance.ErrRebalanceInvalidOption
+	}
+}
+
+// rebalanceNodes returns the nodes hosting the bricks of the volume once the
+// new bricks have been added
+func rebalanceNodes(volinfo *volume.Volinfo, newNodes []uuid.UUID) []uuid.UUID {
+	seen := make(map[string]bool)
+	var nodes []uuid.UUID
+	for _, node := range append(volinfo.Nodes(), newNodes...) {
+		if !seen[node.String()] {
+			seen[node.String()] = true
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
 func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
-	ctx, span := trace.StartSpan(ctx, "/volumeExpandHandler")
-	defer span.End()
-	logger := gdctx.GetReqLogger(ctx)
 	volname := mux.Vars(r)["volname"]
 
 	var req api.VolExpandReq
@@ -79,23 +114,59 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := validateVolumeExpandReq(req); err != nil {
+	rebalReq, err := parseExpandRebalanceParam(r)
+	if err != nil {
 		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
 		return
 	}
 
-	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	volinfo, rebalinfo, status, err := ExpandVolume(ctx, volname, req, rebalReq)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
 		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
+
+	events.Broadcast(volume.NewEvent(volume.EventVolumeExpanded, volinfo))
+
+	warnPeersInMaintenance(ctx, w, volinfo)
+	resp := createVolumeExpandResp(volinfo, rebalinfo)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// ExpandVolume expands a volume by adding bricks, increasing its replica
+// count or, for smart volumes, resizing its bricks. If rebalReq is set,
+// rebalance of the volume is started as part of the expansion.
+func ExpandVolume(ctx context.Context, volname string, req api.VolExpandReq, rebalReq *rebalanceapi.StartReq) (*volume.Volinfo, *rebalanceapi.RebalInfo, int, error) {
+	ctx, span := trace.StartSpan(ctx, "/volumeExpandHandler")
+	defer span.End()
+	logger := gdctx.GetReqLogger(ctx)
+
+	if err := validateVolumeExpandReq(req); err != nil {
+		return nil, nil, http.StatusBadRequest, err
+	}
+
+	// Bricks of smart volumes are resized on their devices
+	if req.Size != 0 {
+		locks, status, err := lockDevices()
+		if err != nil {
+			return nil, nil, status, err
+		}
+		defer locks.UnLock(ctx)
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		return nil, nil, status, err
+	}
 	defer txn.Done()
 
 	volinfo, err := volume.GetVolume(volname)
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, nil, http.StatusInternalServerError, err
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, volinfo.GetNamespace()) {
+		return nil, nil, http.StatusNotFound, errors.ErrVolNotFound
 	}
 
 	var expansionSizePerBrick uint64
@@ -104,6 +175,17 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 	var brickVgMapping map[string]string
 	var ok bool
 	lvmResizeOp := checkForLvmResize(req, volinfo)
+
+	if rebalReq != nil {
+		if status, err := rebalance.ValidateStart(volinfo, rebalReq); err != nil {
+			return nil, nil, status, err
+		}
+		// Resizing bricks or increasing the replica count doesn't add
+		// new distribute subvolumes, so there is nothing to rebalance
+		if lvmResizeOp || req.ReplicaCount != 0 {
+			return nil, nil, http.StatusBadRequest, rebalance.ErrVolNotDistribute
+		}
+	}
 	// continue normal volume expand by adding new bricks or subvols
 	if !lvmResizeOp {
 		for index := range volinfo.Subvols {
@@ -112,8 +194,7 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 				for _, b := range req.Bricks {
 
 					if brick.PeerID.String() == b.PeerID && brick.Path == filepath.Clean(b.Path) {
-						restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrDuplicateBrickPath)
-						return
+						return nil, nil, http.StatusBadRequest, errors.ErrDuplicateBrickPath
 					}
 				}
 
@@ -138,13 +219,11 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 		bricksInfo := volinfo.GetBricks()
 		brickVgMapping, ok, err = deviceutils.CheckForAvailableVgSize(totalExpansionSizePerBrick, bricksInfo)
 		if !ok && err == nil {
-			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Space not sufficient on device")
-			return
+			return nil, nil, http.StatusBadRequest, fmt.Errorf("Space not sufficient on device")
 		}
 
 		if err != nil {
-			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-			return
+			return nil, nil, http.StatusInternalServerError, err
 		}
 
 	}
@@ -152,14 +231,12 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 	nodes, err := req.Nodes()
 	if err != nil {
 		logger.WithError(err).Error("could not prepare node list")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, nil, http.StatusInternalServerError, err
 	}
 
 	allNodes, err := peer.GetPeerIDs()
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, nil, http.StatusInternalServerError, err
 	}
 
 	txn.Nodes = allNodes
@@ -186,22 +263,24 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 			Skip:     lvmResizeOp,
 		},
 		{
-			DoFunc: "vol-expand.LvmResize",
-			Nodes:  volinfo.Nodes(),
-			Skip:   !lvmResizeOp,
+			DoFunc:  "vol-expand.LvmResize",
+			Nodes:   volinfo.Nodes(),
+			Skip:    !lvmResizeOp,
+			Timeout: transaction.BrickPrepareTimeout,
 		},
 		{
 			DoFunc:   "vol-create.StoreVolume",
-			UndoFunc: "vol-create.UndoStoreVolume",
+			UndoFunc: "vol-expand.UpdateVolinfo.Undo",
 			Nodes:    []uuid.UUID{gdctx.MyUUID},
 			Skip:     !lvmResizeOp,
 			Sync:     true,
 		},
 		{
-			DoFunc: "vol-expand.UpdateVolinfo",
-			Nodes:  []uuid.UUID{gdctx.MyUUID},
-			Skip:   lvmResizeOp,
-			Sync:   true,
+			DoFunc:   "vol-expand.UpdateVolinfo",
+			UndoFunc: "vol-expand.UpdateVolinfo.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Skip:     lvmResizeOp,
+			Sync:     true,
 		},
 		{
 			DoFunc:   "vol-expand.GenerateBrickVolfiles",
@@ -215,36 +294,50 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 			UndoFunc: "vol-expand.UndoStartBrick",
 			Skip:     lvmResizeOp,
 		},
-		{
-			DoFunc: "vol-expand.NotifyClients",
-			Nodes:  allNodes,
-			Skip:   lvmResizeOp,
-		},
+	}
+
+	// Rebalance is started as part of the expand transaction so that both
+	// are tracked as a single job identified by the rebalance ID. A failure
+	// to start rebalance rolls back the expansion as well.
+	var rebalinfo *rebalanceapi.RebalInfo
+	if rebalReq != nil {
+		rebalinfo = rebalance.NewRebalanceInfo(volname, rebalReq)
+		if err := txn.Ctx.Set("rinfo", rebalinfo); err != nil {
+			return nil, nil, http.StatusInternalServerError, err
+		}
+		txn.Steps = append(txn.Steps, rebalance.StartSteps(rebalanceNodes(volinfo, nodes))...)
+	}
+
+	// Clients are notified last as there is no undoing it, so that a
+	// failure in any of the earlier steps can be rolled back completely
+	txn.Steps = append(txn.Steps, &transaction.Step{
+		DoFunc: "vol-expand.NotifyClients",
+		Nodes:  allNodes,
+		Skip:   lvmResizeOp,
+	})
+
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
+		return nil, nil, http.StatusInternalServerError, err
 	}
 
 	if err := txn.Ctx.Set("req", &req); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, nil, http.StatusInternalServerError, err
 	}
 
 	if err := txn.Ctx.Set("volname", volname); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, nil, http.StatusInternalServerError, err
 	}
 
 	if err := txn.Ctx.Set("expansionTpSizePerBrick", expansionTpSizePerBrick); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, nil, http.StatusInternalServerError, err
 	}
 
 	if err := txn.Ctx.Set("expansionMetadataSizePerBrick", expansionMetadataSizePerBrick); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, nil, http.StatusInternalServerError, err
 	}
 
 	if err := txn.Ctx.Set("brickVgMapping", brickVgMapping); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, nil, http.StatusInternalServerError, err
 	}
 
 	// Add relevant attributes to the root span
@@ -262,23 +355,28 @@ func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
 	if err = txn.Do(); err != nil {
 		logger.WithError(err).WithField("volume-name", volname).Error("volume expand transaction failed")
 		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, nil, status, err
+	}
+
+	details := map[string]string{"bricks": strings.TrimSuffix(bricksToAdd, ",")}
+	if rebalinfo != nil {
+		details["rebalance-id"] = rebalinfo.RebalanceID.String()
 	}
+	recordVolumeHistory(ctx, volname, volume.EventVolumeExpanded, txn.Ctx.GetTxnID(), details)
 
 	volinfo, err = volume.GetVolume(volname)
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, nil, http.StatusInternalServerError, err
 	}
 
 	logger.WithField("volume-name", volinfo.Name).Info("volume expanded")
-	events.Broadcast(volume.NewEvent(volume.EventVolumeExpanded, volinfo))
-
-	resp := createVolumeExpandResp(volinfo)
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+	return volinfo, rebalinfo, http.StatusOK, nil
 }
 
-func createVolumeExpandResp(v *volume.Volinfo) *api.VolumeExpandResp {
-	return (*api.VolumeExpandResp)(volume.CreateVolumeInfoResp(v))
+func createVolumeExpandResp(v *volume.Volinfo, rebalinfo *rebalanceapi.RebalInfo) *api.VolumeExpandResp {
+	resp := &api.VolumeExpandResp{VolumeInfo: *volume.CreateVolumeInfoResp(v)}
+	if rebalinfo != nil {
+		resp.RebalanceID = rebalinfo.RebalanceID
+	}
+	return resp
 }
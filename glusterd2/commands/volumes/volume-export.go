@@ -0,0 +1,209 @@
+package volumecommands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	"go.opencensus.io/trace"
+	"golang.org/x/sys/unix"
+)
+
+func registerVolImportStepFuncs() {
+	transaction.RegisterStepFunc(validateImportedBricks, "vol-import.ValidateBricks")
+}
+
+// validateImportedBricks checks that the local bricks of an imported volume
+// are present and belong to the volume
+func validateImportedBricks(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	for _, b := range volinfo.GetLocalBricks() {
+		if _, err := os.Stat(b.Path); err != nil {
+			return fmt.Errorf("brick %s not found: %s", b.String(), err)
+		}
+
+		volID := make([]byte, len(volinfo.ID))
+		if _, err := unix.Getxattr(b.Path, volumeIDXattrKey, volID); err != nil {
+			return fmt.Errorf("failed to get volume id of brick %s: %s", b.String(), err)
+		}
+		if !bytes.Equal(volID, []byte(volinfo.ID)) {
+			return fmt.Errorf("brick %s belongs to volume %s", b.String(), uuid.UUID(volID).String())
+		}
+	}
+
+	return nil
+}
+
+func volumeExportHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, volume.CreateVolumeExport(volinfo))
+}
+
+// mapImportedBricks assigns the bricks of an imported volume to the peers of
+// this cluster. Bricks whose peer is not part of the cluster, as is the case
+// when the cluster has been rebuilt, are matched with a peer by hostname.
+func mapImportedBricks(volinfo *volume.Volinfo) error {
+	for sidx := range volinfo.Subvols {
+		for bidx := range volinfo.Subvols[sidx].Bricks {
+			b := &volinfo.Subvols[sidx].Bricks[bidx]
+			if peer.Exists(b.PeerID.String()) {
+				continue
+			}
+			p, err := peer.GetPeerByAddr(b.Hostname)
+			if err != nil {
+				return fmt.Errorf("no peer found for brick %s", b.String())
+			}
+			b.PeerID = p.ID
+		}
+	}
+	return nil
+}
+
+func volumeImportHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, span := trace.StartSpan(ctx, "/volumeImportHandler")
+	defer span.End()
+
+	var req api.VolumeImportReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	volinfo, err := volume.NewVolinfoFromExport(&req)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if status, err := ImportVolume(ctx, volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := createVolumeCreateResp(volinfo)
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
+}
+
+// ValidateVolumeImport runs the checks done by ImportVolume without
+// importing the volume. The bricks of volinfo are mapped to the peers of the
+// cluster.
+func ValidateVolumeImport(volinfo *volume.Volinfo) (int, error) {
+	if err := mapImportedBricks(volinfo); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	volumes, err := volume.GetVolumesList()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	for name, id := range volumes {
+		if name == volinfo.Name {
+			return http.StatusBadRequest, gderrors.ErrVolExists
+		}
+		if uuid.Equal(id, volinfo.ID) {
+			return http.StatusBadRequest, fmt.Errorf("volume %s has the same volume id", name)
+		}
+	}
+
+	allBricks, err := volume.GetAllBricksInCluster()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	for _, b := range volinfo.GetBricks() {
+		for _, existing := range allBricks {
+			if uuid.Equal(b.PeerID, existing.PeerID) && b.Path == existing.Path {
+				return http.StatusBadRequest,
+					fmt.Errorf("brick %s is already part of volume %s", b.String(), existing.VolumeName)
+			}
+		}
+	}
+
+	return http.StatusOK, nil
+}
+
+// ImportVolume stores a volume whose bricks already exist, like a volume
+// recreated from an export. The volume must be in the created state. It is
+// given new credentials for its trusted clients unless it has some.
+func ImportVolume(ctx context.Context, volinfo *volume.Volinfo) (int, error) {
+	logger := gdctx.GetReqLogger(ctx)
+	span := trace.FromContext(ctx)
+
+	if volinfo.Auth.Username == "" || volinfo.Auth.Password == "" {
+		volinfo.Auth = volume.VolAuth{
+			Username: uuid.NewRandom().String(),
+			Password: uuid.NewRandom().String(),
+		}
+	}
+
+	txn, err := transactionv2.NewTxnWithLocks(ctx, volinfo.Name)
+	if err != nil {
+		return restutils.ErrToStatusCode(err)
+	}
+	defer txn.Done()
+
+	if status, err := ValidateVolumeImport(volinfo); err != nil {
+		return status, err
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "vol-import.ValidateBricks",
+			Nodes:  volinfo.Nodes(),
+		},
+		{
+			DoFunc:   "vol-create.StoreVolume",
+			UndoFunc: "vol-create.UndoStoreVolume",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Sync:     true,
+		},
+	}
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	span.AddAttributes(
+		trace.StringAttribute("reqID", txn.Ctx.GetTxnReqID()),
+		trace.StringAttribute("volName", volinfo.Name),
+	)
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField(
+			"volume", volinfo.Name).Error("transaction to import volume failed")
+		return restutils.ErrToStatusCode(err)
+	}
+
+	logger.WithField("volume-name", volinfo.Name).Info("volume imported")
+	recordVolumeHistory(ctx, volinfo.Name, volume.EventVolumeImported, txn.Ctx.GetTxnID(), nil)
+	events.Broadcast(volume.NewEvent(volume.EventVolumeImported, volinfo))
+
+	return http.StatusCreated, nil
+}
@@ -0,0 +1,74 @@
+package volumecommands
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+// recordVolumeHistory adds an entry to the history of the volume. Failing to
+// record the history is logged but doesn't fail the operation which has
+// already been committed.
+func recordVolumeHistory(ctx context.Context, volname string, event volume.Event, txnID string, details map[string]string) {
+	entry := &volume.HistoryEntry{
+		Event:     event,
+		Requester: gdctx.GetReqUser(ctx),
+		ReqID:     gdctx.GetReqID(ctx).String(),
+		TxnID:     txnID,
+		Details:   details,
+	}
+
+	if err := volume.AddHistoryEntry(volname, entry); err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"volume": volname,
+			"event":  event,
+		}).Warn("failed to record volume history")
+	}
+}
+
+func volumeHistoryHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	ctx, span := trace.StartSpan(ctx, "/volumeHistoryHandler")
+	defer span.End()
+
+	volname := mux.Vars(r)["volname"]
+	span.AddAttributes(trace.StringAttribute("volName", volname))
+
+	if _, err := volume.GetVolume(volname); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	entries, err := volume.GetHistory(volname)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createVolumeHistoryResp(entries))
+}
+
+func createVolumeHistoryResp(entries []*volume.HistoryEntry) api.VolumeHistoryResp {
+	resp := make(api.VolumeHistoryResp, 0, len(entries))
+	for _, e := range entries {
+		resp = append(resp, api.VolumeHistoryEntry{
+			Time:      e.Time,
+			Event:     string(e.Event),
+			Requester: e.Requester,
+			ReqID:     e.ReqID,
+			TxnID:     e.TxnID,
+			Details:   e.Details,
+		})
+	}
+	return resp
+}
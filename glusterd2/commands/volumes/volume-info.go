@@ -15,6 +15,15 @@ func volumeInfoHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	volname := mux.Vars(r)["volname"]
+
+	// The version is taken before the volume, so that a response with a
+	// volume changed in between isn't tagged as the latest version
+	if version, err := volume.GetVolumeVersion(volname); err == nil {
+		if restutils.CheckNotModified(w, r, version) {
+			return
+		}
+	}
+
 	v, err := volume.GetVolume(volname)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
@@ -22,10 +31,20 @@ func volumeInfoHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := createVolumeGetResp(v)
+	resp, err := restutils.SelectFields(createVolumeGetResp(v), restutils.GetFieldsParam(r), volumeFieldAliases)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
 }
 
+// volumeFieldAliases maps the field names accepted in the "fields" query
+// parameter of volume info and list requests to the JSON field names
+var volumeFieldAliases = map[string]string{
+	"size": "capacity",
+}
+
 func createVolumeGetResp(v *volume.Volinfo) *api.VolumeGetResp {
 	return (*api.VolumeGetResp)(volume.CreateVolumeInfoResp(v))
 }
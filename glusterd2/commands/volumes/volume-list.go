@@ -2,6 +2,7 @@ package volumecommands
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -34,12 +35,22 @@ func volumeListHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	volumes, err = applyVolumeListParams(w, r, volumes)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
 	// Add the count of volumes being listed as an attribute in the span
 	span.AddAttributes(
 		trace.StringAttribute("numVols", strconv.Itoa(len(volumes))),
 	)
 
-	resp := createVolumeListResp(ctx, volumes)
+	resp, err := restutils.SelectFields(createVolumeListResp(ctx, volumes), restutils.GetFieldsParam(r), volumeFieldAliases)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
 }
 
@@ -55,3 +66,50 @@ func createVolumeListResp(ctx context.Context, volumes []*volume.Volinfo) *api.V
 
 	return &resp
 }
+
+// applyVolumeListParams filters, sorts and paginates the volumes as per the
+// query parameters of the list request. The pagination headers of the
+// response are set by restutils.Page.Apply.
+func applyVolumeListParams(w http.ResponseWriter, r *http.Request, volumes []*volume.Volinfo) ([]*volume.Volinfo, error) {
+	query := r.URL.Query()
+
+	// Volumes of the namespaces the requester can't access are never listed
+	filters := []volume.Filter{volume.FilterByReqNamespaces(r.Context())}
+	if namespace := query.Get("namespace"); namespace != "" {
+		filters = append(filters, volume.FilterByNamespace(namespace))
+	}
+	if state := query.Get("state"); state != "" {
+		filters = append(filters, volume.FilterByState(state))
+	}
+	if voltype := query.Get("type"); voltype != "" {
+		filters = append(filters, volume.FilterByType(voltype))
+	}
+	if selector := query.Get("labels"); selector != "" {
+		ls, err := volume.ParseLabelSelector(selector)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, volume.FilterByLabels(ls))
+	}
+	volumes = volume.ApplyCustomFilters(volumes, filters...)
+
+	descending := false
+	switch query.Get("order") {
+	case "", "asc":
+	case "desc":
+		descending = true
+	default:
+		return nil, errors.New("invalid order, supported values: asc, desc")
+	}
+
+	if err := volume.SortVolumes(volumes, query.Get("sort"), descending); err != nil {
+		return nil, err
+	}
+
+	page, err := restutils.GetPage(r)
+	if err != nil {
+		return nil, err
+	}
+	start, end := page.Apply(w, r, len(volumes), func(i int) string { return volumes[i].Name })
+	return volumes[start:end], nil
+}
@@ -0,0 +1,77 @@
+package volumecommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"go.opencensus.io/trace"
+)
+
+func volumeOptionGroupHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	ctx, span := trace.StartSpan(ctx, "/volumeOptionGroupHandler")
+	defer span.End()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+	groupname := mux.Vars(r)["groupname"]
+
+	var req api.VolOptionGroupReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	if containsReservedGroupProfile([]string{groupname}) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrReservedGroupProfile)
+		return
+	}
+
+	groupOptions, err := getGroupOptionsFromStore()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if _, ok := groupOptions[groupname]; !ok {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrOptionGroupNotFound)
+		return
+	}
+
+	value := "on"
+	if req.Unapply {
+		value = "off"
+	}
+
+	// Expand the group here so that all the options of the group are set
+	// in a single transaction and the xlator actors of each option are run
+	opts, err := expandGroupOptions(map[string]string{groupname: value})
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	optReq := api.VolOptionReq{
+		Options:        opts,
+		VolOptionFlags: req.VolOptionFlags,
+	}
+
+	span.AddAttributes(
+		trace.StringAttribute("groupName", groupname),
+		trace.BoolAttribute("unapply", req.Unapply),
+	)
+
+	volinfo, status, err := setVolumeOptions(ctx, volname, &optReq)
+	if err != nil {
+		logger.WithError(err).WithField("group", groupname).Error("volume option group transaction failed")
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := createVolumeOptionResp(volinfo)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
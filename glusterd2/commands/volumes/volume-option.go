@@ -1,14 +1,18 @@
 package volumecommands
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
+	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/opversion"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	"github.com/gluster/glusterd2/glusterd2/xlator"
 	"github.com/gluster/glusterd2/pkg/api"
@@ -32,22 +36,38 @@ func optionSetValidate(c transaction.TxnCtx) error {
 		return err
 	}
 
-	// TODO: Validate op versions of the options. Either here or inside
-	// validateOptions.
-
 	if err := validateOptions(options, req.VolOptionFlags); err != nil {
 		return fmt.Errorf("validation failed for volume option: %s", err.Error())
 	}
 
+	if err := validateOptionsOpVersion(options); err != nil {
+		return err
+	}
+
 	var volinfo volume.Volinfo
 	if err := c.Get("volinfo", &volinfo); err != nil {
 		return err
 	}
 
-	if err := validateXlatorOptions(options, &volinfo); err != nil {
+	// Validate against the volume as it would be after the options are set,
+	// so that options which depend on each other can be set together
+	proposed := volinfo
+	proposed.Options = make(map[string]string)
+	for k, v := range volinfo.Options {
+		proposed.Options[k] = v
+	}
+	for k, v := range options {
+		proposed.Options[k] = v
+	}
+
+	if err := validateXlatorOptions(options, &proposed); err != nil {
 		return fmt.Errorf("validation failed for volume option:: %s", err.Error())
 	}
 
+	if err := volgen.ValidateGraphHooks(&proposed); err != nil {
+		return fmt.Errorf("validation failed for volume option: %s", err.Error())
+	}
+
 	for k, v := range options {
 		// TODO: Normalize <graph>.<xlator>.<option> and just
 		// <xlator>.<option> to avoid ambiguity and duplication.
@@ -56,12 +76,33 @@ func optionSetValidate(c transaction.TxnCtx) error {
 		// {"afr.eager-lock":"on","gfproxy.afr.eager-lock":"on"}
 		volinfo.Options[k] = v
 	}
+	untrackDefaultOptions(&volinfo, options)
 
 	err = c.Set("volinfo", volinfo)
 
 	return err
 }
 
+// validateOptionsOpVersion refuses options introduced in an op-version higher
+// than that of the cluster, which would be left out of the volfiles
+func validateOptionsOpVersion(opts map[string]string) error {
+	current, err := opversion.Get()
+	if err != nil {
+		return err
+	}
+
+	for k := range opts {
+		o, err := xlator.FindOption(k)
+		if err != nil {
+			continue
+		}
+		if len(o.OpVersion) > 0 && int(o.OpVersion[0]) > current {
+			return fmt.Errorf("option %s requires op-version %d: %s", k, o.OpVersion[0], errors.ErrOpVersionTooLow)
+		}
+	}
+	return nil
+}
+
 type txnOpType uint8
 
 const (
@@ -153,6 +194,10 @@ func xlatorAction(c transaction.TxnCtx, txnOp txnOpType, volOp xlator.VolumeOpTy
 	}
 	//The code below applies only for xlator.VolumeSet and xlator.VolumeReset operations.
 	for k, v := range reqOptions {
+		if options.IsUserOption(k) {
+			// User options are not used by any xlator
+			continue
+		}
 		_, xl, key := options.SplitKey(k)
 		xltr, err := xlator.Find(xl)
 		if err != nil {
@@ -211,30 +256,40 @@ func volumeOptionsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	volinfo, status, err := setVolumeOptions(ctx, volname, &req)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
+		logger.WithError(err).Error("volume option transaction failed")
 		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
+
+	resp := createVolumeOptionResp(volinfo)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// setVolumeOptions sets the options in the request on the volume and
+// regenerates the volfiles of the volume. It returns the updated volinfo.
+func setVolumeOptions(ctx context.Context, volname string, req *api.VolOptionReq) (*volume.Volinfo, int, error) {
+	span := trace.FromContext(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		return nil, restutils.ErrToStatusCode(err)
+	}
 	defer txn.Done()
 
 	volinfo, err := volume.GetVolume(volname)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, restutils.ErrToStatusCode(err)
 	}
 
 	//save volume information for transaction failure scenario
 	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 	allNodes, err := peer.GetPeerIDs()
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	txn.Steps = []*transaction.Step{
@@ -246,6 +301,7 @@ func volumeOptionsHandler(w http.ResponseWriter, r *http.Request) {
 			DoFunc:   "vol-option.UpdateVolinfo",
 			UndoFunc: "vol-option.UpdateVolinfo.Undo",
 			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Retry:    transaction.DefaultRetryPolicy,
 			Sync:     true,
 		},
 		{
@@ -258,21 +314,21 @@ func volumeOptionsHandler(w http.ResponseWriter, r *http.Request) {
 			DoFunc:   "vol-option.GenerateBrickVolfiles",
 			UndoFunc: "vol-option.GenerateBrickvolfiles.Undo",
 			Nodes:    volinfo.Nodes(),
+			Retry:    transaction.DefaultRetryPolicy,
 		},
 		{
 			DoFunc: "vol-option.NotifyVolfileChange",
 			Nodes:  allNodes,
+			Retry:  transaction.DefaultRetryPolicy,
 		},
 	}
 
-	if err := txn.Ctx.Set("req", &req); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+	if err := txn.Ctx.Set("req", req); err != nil {
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	// Add relevant attributes to the span
@@ -288,21 +344,18 @@ func volumeOptionsHandler(w http.ResponseWriter, r *http.Request) {
 	)
 
 	if err := txn.Do(); err != nil {
-		logger.WithError(err).Error("volume option transaction failed")
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, restutils.ErrToStatusCode(err)
 	}
 
+	recordVolumeHistory(ctx, volname, volume.EventVolumeOptionsSet, txn.Ctx.GetTxnID(), req.Options)
+
 	volinfo, err = volume.GetVolume(volname)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, restutils.ErrToStatusCode(err)
 	}
+	events.Broadcast(volume.NewEvent(volume.EventVolumeOptionsSet, volinfo))
 
-	resp := createVolumeOptionResp(volinfo)
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+	return volinfo, http.StatusOK, nil
 }
 
 func createVolumeOptionResp(v *volume.Volinfo) *api.VolumeOptionResp {
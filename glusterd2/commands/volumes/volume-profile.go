@@ -14,6 +14,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc/dict"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
 
 	"github.com/gorilla/mux"
 )
@@ -125,6 +126,63 @@ func volumeProfileHandler(w http.ResponseWriter, r *http.Request) {
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, &volumeProfileInfo)
 }
 
+func volumeProfileStartHandler(w http.ResponseWriter, r *http.Request) {
+	volumeProfileSession(w, r, true)
+}
+
+func volumeProfileStopHandler(w http.ResponseWriter, r *http.Request) {
+	volumeProfileSession(w, r, false)
+}
+
+// volumeProfileSession starts or stops the collection of the stats of the
+// bricks of the volume by turning the io-stats options on or off
+func volumeProfileSession(w http.ResponseWriter, r *http.Request, start bool) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	value := "off"
+	if start {
+		value = "on"
+		if volinfo.State != volume.VolStarted {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "volume must be in start state")
+			return
+		}
+		if getActiveProfileSession(volinfo) {
+			restutils.SendHTTPError(ctx, w, http.StatusConflict, "profile session is already running")
+			return
+		}
+	} else if !getActiveProfileSession(volinfo) {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, "there are no active profile sessions running")
+		return
+	}
+
+	req := api.VolOptionReq{
+		Options: make(map[string]string),
+		VolOptionFlags: api.VolOptionFlags{
+			AllowAdvanced: true,
+		},
+	}
+	for _, key := range profileSessionKeys {
+		req.Options[key] = value
+	}
+
+	if _, status, err := setVolumeOptions(ctx, volname, &req); err != nil {
+		logger.WithError(err).WithField("volname", volname).Error("failed to set volume profile options")
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+}
+
 // Calculate Percentage latency for each fop in cumulative/interval stats
 func calculatePercentageLatencyForEachFop(stats StatType) StatType {
 	// Calculate sum of (hits * avgLatency) of all fops in Cumulative Stats. Used Later to calculate
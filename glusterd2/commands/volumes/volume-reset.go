@@ -1,10 +1,14 @@
 package volumecommands
 
 import (
+	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 
+	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
@@ -15,6 +19,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/pborman/uuid"
+	"go.opencensus.io/trace"
 )
 
 func registerVolOptionResetStepFuncs() {
@@ -30,11 +35,66 @@ func registerVolOptionResetStepFuncs() {
 	}
 }
 
+// resetScope returns the options of the volume which are to be reset by the
+// request: all the resettable options which are set, the options of an option
+// group which are set, or the options named in the request.
+func resetScope(req *api.VolOptionResetReq, volinfo *volume.Volinfo) ([]string, int, error) {
+	var keys []string
+
+	switch {
+	case req.All:
+		for key := range volinfo.Options {
+			op, err := xlator.FindOption(key)
+			if err != nil {
+				return nil, http.StatusInternalServerError, err
+			}
+			if !op.IsNeverReset() {
+				keys = append(keys, key)
+			}
+		}
+
+	case req.Group != "":
+		groupOptions, err := getGroupOptionsFromStore()
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		optionSet, ok := groupOptions[req.Group]
+		if !ok {
+			return nil, http.StatusNotFound, errors.ErrOptionGroupNotFound
+		}
+		// options of the group which were never set or were already reset
+		// are skipped
+		for _, option := range optionSet.Options {
+			if _, ok := volinfo.Options[option.Name]; ok {
+				keys = append(keys, option.Name)
+			}
+		}
+
+	default:
+		var err error
+		if keys, err = expandGroupOptionsReset(req.Options); err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		if len(keys) == 0 {
+			return nil, http.StatusBadRequest, fmt.Errorf("no options specified to reset")
+		}
+		for _, key := range keys {
+			if _, ok := volinfo.Options[key]; !ok {
+				return nil, http.StatusBadRequest, fmt.Errorf("option %s is not set or is invalid", key)
+			}
+		}
+	}
+
+	return keys, http.StatusOK, nil
+}
+
 func volumeResetHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
+	ctx, span := trace.StartSpan(ctx, "/volumeResetHandler")
+	defer span.End()
 	logger := gdctx.GetReqLogger(ctx)
-	var err error
+	volname := mux.Vars(r)["volname"]
 
 	var req api.VolOptionResetReq
 	if err := restutils.UnmarshalRequest(r, &req); err != nil {
@@ -42,112 +102,86 @@ func volumeResetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if containsReservedGroupProfile(req.Options) {
+	if containsReservedGroupProfile(req.Options) || containsReservedGroupProfile([]string{req.Group}) {
 		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrReservedGroupProfile)
 		return
 	}
 
-	volname := mux.Vars(r)["volname"]
-	volinfo, err := volume.GetVolume(volname)
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
 		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
+	defer txn.Done()
 
-	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	volinfo, err := volume.GetVolume(volname)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
 		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
-	defer txn.Done()
 
 	// store volinfo to revert changes if transaction fails
-	oldvolinfo := volinfo
-	if err := txn.Ctx.Set("oldvolinfo", oldvolinfo); err != nil {
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
 		return
 	}
 
-	req.Options, err = expandGroupOptionsReset(req.Options)
+	keys, status, err := resetScope(&req, volinfo)
 	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
 
-	if req.All {
-		req.Options = []string{}
-		for key := range volinfo.Options {
-			op, err := xlator.FindOption(key)
-			if err != nil {
-				restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-				return
-			}
-			if !op.IsNeverReset() {
-				req.Options = append(req.Options, key)
-			}
-		}
+	clusterDefaults, err := options.GetVolumeDefaultOptions()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
 	}
 
-	// If reset All is called or if anything reseted from the
-	// default group profile, remove from reset list and
-	// reassign the default value
-	var newopts []string
+	var profileDefaults map[string]string
 	if len(volinfo.Subvols) > 0 {
+		profileDefaults = make(map[string]string)
 		optGrp, exists := defaultGroupOptions["profile.default."+strings.ToLower(volinfo.Subvols[0].Type.String())]
 		if exists {
-		REQLOOP:
-			for _, k := range req.Options {
-				for _, opt := range optGrp.Options {
-					if k == opt.Name {
-						// Reset the default value as mentioned in profile
-						volinfo.Options[k] = opt.OnValue
-						continue REQLOOP
-					}
-				}
-				// Not in default profile, continue to reset
-				newopts = append(newopts, k)
+			for _, opt := range optGrp.Options {
+				profileDefaults[opt.Name] = opt.OnValue
 			}
 		}
 	}
-	req.Options = newopts
 
-	for _, k := range req.Options {
-		// Check if the key is set or not
-		if _, ok := volinfo.Options[k]; ok {
-			op, err := xlator.FindOption(k)
-			// If key exists, check for NEVER_RESET and FORCE flags
-			if err != nil {
-				restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
-				return
-			}
-			if op.IsNeverReset() {
-				errMsg := "Reserved option, can't be reset"
-				restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errMsg)
-				return
-			}
-			if op.IsForceRequired() {
-				if req.Force {
-					delete(volinfo.Options, k)
-				} else {
-					errMsg := "Option needs force flag to be reset"
-					restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errMsg)
-					return
-				}
-			}
-			delete(volinfo.Options, k)
-		} else {
-			errMsg := "Option trying to reset is not set or invalid option"
-			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errMsg)
+	// Options which are part of the default group profile of the volume
+	// type or the cluster default volume options are reset to those values,
+	// the rest are removed from the volume so that the xlator defaults apply.
+	req.Options = nil
+	for _, k := range keys {
+		op, err := xlator.FindOption(k)
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+		if op.IsNeverReset() {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest,
+				fmt.Sprintf("option %s is reserved and can't be reset", k))
+			return
+		}
+		if op.IsForceRequired() && !req.Force {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest,
+				fmt.Sprintf("option %s needs force flag to be reset", k))
 			return
 		}
-	}
 
-	allNodes, err := peer.GetPeerIDs()
-	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		if v, ok := profileDefaults[k]; ok {
+			volinfo.Options[k] = v
+			continue
+		}
+		if v, ok := clusterDefaults[k]; ok {
+			volinfo.Options[k] = v
+			continue
+		}
+		delete(volinfo.Options, k)
+		req.Options = append(req.Options, k)
 	}
 
 	// doing this to reuse isActionStepRequired() which takes a map
@@ -156,6 +190,24 @@ func volumeResetHandler(w http.ResponseWriter, r *http.Request) {
 		opt[key] = ""
 	}
 
+	resetKeys := make(map[string]string)
+	for _, k := range keys {
+		resetKeys[k] = ""
+	}
+	untrackDefaultOptions(volinfo, resetKeys)
+	for _, k := range keys {
+		if v, ok := clusterDefaults[k]; ok && volinfo.Options[k] == v {
+			volinfo.DefaultOptions = append(volinfo.DefaultOptions, k)
+		}
+	}
+	sort.Strings(volinfo.DefaultOptions)
+
+	allNodes, err := peer.GetPeerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
 	txn.Steps = []*transaction.Step{
 		{
 			DoFunc:   "vol-option.XlatorActionDoReset",
@@ -167,17 +219,20 @@ func volumeResetHandler(w http.ResponseWriter, r *http.Request) {
 			DoFunc:   "vol-option.UpdateVolinfo",
 			UndoFunc: "vol-option.UpdateVolinfo.Undo",
 			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Retry:    transaction.DefaultRetryPolicy,
 			Sync:     true,
 		},
 		{
 			DoFunc:   "vol-option.GenerateBrickVolfiles",
 			UndoFunc: "vol-option.GenerateBrickvolfiles.Undo",
 			Nodes:    volinfo.Nodes(),
+			Retry:    transaction.DefaultRetryPolicy,
 			Sync:     true,
 		},
 		{
 			DoFunc: "vol-option.NotifyVolfileChange",
 			Nodes:  allNodes,
+			Retry:  transaction.DefaultRetryPolicy,
 		},
 	}
 
@@ -191,6 +246,12 @@ func volumeResetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	span.AddAttributes(
+		trace.StringAttribute("reqID", txn.Ctx.GetTxnReqID()),
+		trace.StringAttribute("volName", volname),
+		trace.StringAttribute("optionsToReset", strings.Join(keys, ",")),
+	)
+
 	if err := txn.Do(); err != nil {
 		logger.WithError(err).Error("volume option transaction failed")
 		status, err := restutils.ErrToStatusCode(err)
@@ -198,5 +259,10 @@ func volumeResetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, volinfo)
+	recordVolumeHistory(ctx, volname, volume.EventVolumeOptionsReset, txn.Ctx.GetTxnID(),
+		map[string]string{"options": strings.Join(keys, ",")})
+	events.Broadcast(volume.NewEvent(volume.EventVolumeOptionsReset, volinfo))
+
+	resp := createVolumeOptionResp(volinfo)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
 }
@@ -0,0 +1,157 @@
+package volumecommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	"go.opencensus.io/trace"
+)
+
+func applyResourceLimits(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	if err := volume.ApplyResourceLimits(&volinfo); err != nil {
+		c.Logger().WithError(err).WithField("volume", volinfo.Name).Error("failed to set resource limits of brick processes")
+		return err
+	}
+	return nil
+}
+
+func undoApplyResourceLimits(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("oldvolinfo", &volinfo); err != nil {
+		return err
+	}
+	return volume.ApplyResourceLimits(&volinfo)
+}
+
+func registerVolResourceLimitsStepFuncs() {
+	transaction.RegisterStepFunc(applyResourceLimits, "vol-resource-limits.Apply")
+	transaction.RegisterStepFunc(undoApplyResourceLimits, "vol-resource-limits.Apply.Undo")
+	transaction.RegisterStepFunc(storeVolume, "vol-resource-limits.UpdateVolinfo")
+	transaction.RegisterStepFunc(undoStoreVolume, "vol-resource-limits.UpdateVolinfo.Undo")
+}
+
+func volumeResourceLimitsGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	volinfo, err := volume.GetVolume(mux.Vars(r)["volname"])
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, volinfo.GetNamespace()) {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrVolNotFound)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createResourceLimitsResp(volinfo))
+}
+
+func volumeResourceLimitsSetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, span := trace.StartSpan(ctx, "/volumeResourceLimitsSetHandler")
+	defer span.End()
+
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req api.VolResourceLimitsReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, volinfo.GetNamespace()) {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrVolNotFound)
+		return
+	}
+
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	limits := volinfo.ResourceLimits
+	if req.CPUQuota != nil {
+		limits.CPUQuota = *req.CPUQuota
+	}
+	if req.MemoryMax != nil {
+		limits.MemoryMax = *req.MemoryMax
+	}
+	if err := limits.Validate(); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	volinfo.ResourceLimits = limits
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "vol-resource-limits.Apply",
+			UndoFunc: "vol-resource-limits.Apply.Undo",
+			Nodes:    volinfo.Nodes(),
+		},
+		{
+			DoFunc:   "vol-resource-limits.UpdateVolinfo",
+			UndoFunc: "vol-resource-limits.UpdateVolinfo.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Sync:     true,
+		},
+	}
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	span.AddAttributes(
+		trace.StringAttribute("reqID", txn.Ctx.GetTxnReqID()),
+		trace.StringAttribute("volName", volname),
+		trace.Int64Attribute("cpuQuota", int64(limits.CPUQuota)),
+		trace.Int64Attribute("memoryMax", int64(limits.MemoryMax)),
+	)
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to set resource limits of volume")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	logger.WithField("volume", volname).WithField("limits", limits).Info("volume resource limits changed")
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createResourceLimitsResp(volinfo))
+}
+
+func createResourceLimitsResp(v *volume.Volinfo) *api.VolResourceLimitsResp {
+	return &api.VolResourceLimitsResp{
+		VolName:   v.Name,
+		CPUQuota:  v.ResourceLimits.CPUQuota,
+		MemoryMax: v.ResourceLimits.MemoryMax,
+	}
+}
@@ -0,0 +1,56 @@
+package volumecommands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/scheduler"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+)
+
+func validateScheduledVolStop(volname string, args json.RawMessage, recurring bool) error {
+	return nil
+}
+
+func runScheduledVolStop(ctx context.Context, volname string, args json.RawMessage) error {
+	volinfo, _, err := StopVolume(ctx, volname, false, 0)
+	if err != nil {
+		return err
+	}
+
+	events.Broadcast(volume.NewEvent(volume.EventVolumeStopped, volinfo))
+	return nil
+}
+
+func validateScheduledVolSet(volname string, args json.RawMessage, recurring bool) error {
+	var req api.VolOptionReq
+	if err := json.Unmarshal(args, &req); err != nil {
+		return errors.ErrJSONParsingFailed
+	}
+	if len(req.Options) == 0 {
+		return fmt.Errorf("no options given to set")
+	}
+	if containsReservedGroupProfile(req.Options) {
+		return errors.ErrReservedGroupProfile
+	}
+	return nil
+}
+
+func runScheduledVolSet(ctx context.Context, volname string, args json.RawMessage) error {
+	var req api.VolOptionReq
+	if err := json.Unmarshal(args, &req); err != nil {
+		return err
+	}
+
+	_, _, err := setVolumeOptions(ctx, volname, &req)
+	return err
+}
+
+func registerVolScheduleOps() {
+	scheduler.RegisterOp(api.ScheduleOpVolumeStop, validateScheduledVolStop, runScheduledVolStop)
+	scheduler.RegisterOp(api.ScheduleOpVolumeSet, validateScheduledVolSet, runScheduledVolSet)
+}
@@ -155,6 +155,11 @@ func txnUndoPrepareBricksLvm(req api.VolCreateReq, c transaction.TxnCtx) error {
 				c.Logger().WithError(err).WithField("path", mountRoot).Error("brick unmount failed")
 			}
 
+			// Remove the mount directory, only if it is empty
+			if err := os.Remove(mountRoot); err != nil && !os.IsNotExist(err) {
+				c.Logger().WithError(err).WithField("path", mountRoot).Error("failed to remove brick mount directory")
+			}
+
 			// Remove LV
 			err = lvmutils.RemoveLV(b.VgName, b.LvName, true)
 			if err != nil {
@@ -173,11 +178,14 @@ func txnUndoPrepareBricksLvm(req api.VolCreateReq, c transaction.TxnCtx) error {
 				}).Error("thinpool remove failed")
 			}
 
+			// The key is not set if the step failed before reaching
+			// this brick, in which case there is nothing to reset.
+			// Carry on with the rest of the bricks either way.
 			var freesizeSet bool
 			key := "freesizeSet." + b.PeerID + b.Path
 			if err := c.Get(key, &freesizeSet); err != nil {
-				c.Logger().WithError(err).WithField("key", key).Error("failed to get key from store")
-				return err
+				c.Logger().WithError(err).WithField("key", key).Debug("failed to get key from store")
+				continue
 			}
 
 			// Reset Free size only if freeSize is set in transaction
@@ -292,6 +300,11 @@ func txnUndoPrepareBricksLoop(req api.VolCreateReq, c transaction.TxnCtx) error
 				c.Logger().WithError(err).WithField("path", mountRoot).Error("brick unmount failed")
 			}
 
+			// Remove the mount directory, only if it is empty
+			if err := os.Remove(mountRoot); err != nil && !os.IsNotExist(err) {
+				c.Logger().WithError(err).WithField("path", mountRoot).Error("failed to remove brick mount directory")
+			}
+
 			// Remove Loop device
 			err = os.Remove(b.DevicePath)
 			if err != nil {
@@ -304,11 +317,14 @@ func txnUndoPrepareBricksLoop(req api.VolCreateReq, c transaction.TxnCtx) error
 				c.Logger().WithError(err).WithField("device", path.Dir(b.DevicePath)).Error("brick device directory remove failed")
 			}
 
+			// The key is not set if the step failed before reaching
+			// this brick, in which case there is nothing to reset.
+			// Carry on with the rest of the bricks either way.
 			var freesizeSet bool
 			key := "freesizeSet." + b.PeerID + b.Path
 			if err := c.Get(key, &freesizeSet); err != nil {
-				c.Logger().WithError(err).WithField("key", key).Error("failed to get key from store")
-				return err
+				c.Logger().WithError(err).WithField("key", key).Debug("failed to get key from store")
+				continue
 			}
 
 			// Reset Free size only if freeSize is set in transaction
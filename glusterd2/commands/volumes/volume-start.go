@@ -8,6 +8,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/brickmux"
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/hooks"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
@@ -35,6 +36,10 @@ func startAllBricks(c transaction.TxnCtx) error {
 		return err
 	}
 
+	if err := volume.ApplyResourceLimits(&volinfo); err != nil {
+		return err
+	}
+
 	bmuxEnabled, err := brickmux.Enabled()
 	if err != nil {
 		return err
@@ -100,8 +105,13 @@ func stopAllBricks(c transaction.TxnCtx) error {
 			"brick":  b.String(),
 		}).Info("volume start failed, stopping brick")
 
-		if err := b.StopBrick(c.Logger()); err != nil {
-			return err
+		if err := brickmux.StopBrick(b, c.Logger()); err != nil {
+			// can't know here which of the bricks started, so
+			// carry on stopping the rest
+			c.Logger().WithError(err).WithFields(log.Fields{
+				"volume": b.VolumeName,
+				"brick":  b.String(),
+			}).Warn("stopping brick failed")
 		}
 	}
 
@@ -146,6 +156,7 @@ func volumeStartHandler(w http.ResponseWriter, r *http.Request) {
 
 	events.Broadcast(volume.NewEvent(volume.EventVolumeStarted, volinfo))
 
+	warnPeersInMaintenance(ctx, w, volinfo)
 	resp := createVolumeStartResp(volinfo)
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
 }
@@ -174,10 +185,13 @@ func StartVolume(ctx context.Context, volname string, req api.VolumeStartReq) (v
 	}
 
 	txn.Steps = []*transaction.Step{
+		hooks.Step(hooks.OpStart, hooks.Pre, volinfo.Nodes()),
 		{
 			DoFunc:   "vol-start.StartBricks",
 			UndoFunc: "vol-start.StartBricksUndo",
 			Nodes:    volinfo.Nodes(),
+			// Need to wait for pre hooks to succeed on all nodes
+			Sync: true,
 		},
 		{
 			DoFunc:   "vol-start.UpdateVolinfo",
@@ -190,6 +204,7 @@ func StartVolume(ctx context.Context, volname string, req api.VolumeStartReq) (v
 			UndoFunc: "vol-start.XlatorActionUndoVolumeStart",
 			Nodes:    volinfo.Nodes(),
 		},
+		hooks.Step(hooks.OpStart, hooks.Post, volinfo.Nodes()),
 	}
 
 	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
@@ -213,6 +228,8 @@ func StartVolume(ctx context.Context, volname string, req api.VolumeStartReq) (v
 		return nil, http.StatusInternalServerError, err
 	}
 
+	recordVolumeHistory(ctx, volname, volume.EventVolumeStarted, txn.Ctx.GetTxnID(), nil)
+
 	return volinfo, http.StatusOK, nil
 }
 
@@ -1,14 +1,20 @@
 package volumecommands
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/brickmux"
 	"github.com/gluster/glusterd2/glusterd2/daemon"
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/hooks"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	transactionv2 "github.com/gluster/glusterd2/glusterd2/transactionv2"
@@ -36,18 +42,13 @@ func stopBricks(c transaction.TxnCtx) error {
 		return err
 	}
 
-	bmuxEnabled, err := brickmux.Enabled()
-	if err != nil {
-		return err
-	}
-
 	for _, b := range brickinfos {
 		brickDaemon, err := brick.NewGlusterfsd(b)
 		if err != nil {
 			return err
 		}
 
-		if bmuxEnabled && !brickmux.IsLastBrickInProc(b) {
+		if brickmux.IsMultiplexed(b) {
 			c.Logger().WithFields(log.Fields{
 				"volume": volinfo.Name, "brick": b.String()}).Info("Calling demultiplex for the brick")
 			if err := brickmux.Demultiplex(b); err != nil {
@@ -98,6 +99,66 @@ func stopBricks(c transaction.TxnCtx) error {
 	return nil
 }
 
+const (
+	gracefulStopPollInterval = 2 * time.Second
+
+	// maxGracefulStopTimeout bounds the time a graceful stop waits for
+	// clients, as the volume stays locked meanwhile
+	maxGracefulStopTimeout = 10 * time.Minute
+)
+
+// waitForClients waits till no client other than gluster daemons is connected
+// to the bricks of the volume or the timeout expires. It returns the hostnames
+// of the clients which are still connected.
+func waitForClients(ctx context.Context, volinfo *volume.Volinfo, timeout time.Duration) ([]string, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		bricks, err := getVolumeClients(ctx, volinfo)
+		if err != nil {
+			return nil, err
+		}
+
+		hosts := externalClients(bricks)
+		if len(hosts) == 0 || time.Now().Add(gracefulStopPollInterval).After(deadline) {
+			return hosts, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return hosts, ctx.Err()
+		case <-time.After(gracefulStopPollInterval):
+		}
+	}
+}
+
+// parseGracefulStopParams parses the graceful and timeout (in seconds) query
+// parameters of the volume stop request
+func parseGracefulStopParams(r *http.Request) (bool, time.Duration, error) {
+	query := r.URL.Query()
+
+	var graceful bool
+	if v := query.Get("graceful"); v != "" {
+		var err error
+		if graceful, err = strconv.ParseBool(v); err != nil {
+			return false, 0, fmt.Errorf("invalid value for graceful: %s", v)
+		}
+	}
+
+	// by default, a graceful stop fails right away if clients are connected
+	var timeout int
+	if v := query.Get("timeout"); v != "" {
+		var err error
+		if timeout, err = strconv.Atoi(v); err != nil || timeout < 0 {
+			return false, 0, fmt.Errorf("invalid value for timeout: %s", v)
+		}
+		if time.Duration(timeout)*time.Second > maxGracefulStopTimeout {
+			return false, 0, fmt.Errorf("timeout can't be more than %d seconds", int(maxGracefulStopTimeout/time.Second))
+		}
+	}
+
+	return graceful, time.Duration(timeout) * time.Second, nil
+}
+
 func registerVolStopStepFuncs() {
 	var sfs = []struct {
 		name string
@@ -118,41 +179,80 @@ func registerVolStopStepFuncs() {
 func volumeStopHandler(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	graceful, timeout, err := parseGracefulStopParams(r)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	volinfo, status, err := StopVolume(ctx, volname, graceful, timeout)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	events.Broadcast(volume.NewEvent(volume.EventVolumeStopped, volinfo))
+
+	warnPeersInMaintenance(ctx, w, volinfo)
+	resp := createVolumeStopResp(volinfo)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// StopVolume stops a volume. If graceful is set, the volume is stopped only
+// if no clients are connected to it within the timeout, which is capped to
+// maxGracefulStopTimeout.
+func StopVolume(ctx context.Context, volname string, graceful bool, timeout time.Duration) (*volume.Volinfo, int, error) {
 	ctx, span := trace.StartSpan(ctx, "/volumeStopHandler")
 	defer span.End()
 
 	logger := gdctx.GetReqLogger(ctx)
-	volname := mux.Vars(r)["volname"]
 
 	txn, err := transactionv2.NewTxnWithLocks(ctx, volname)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, status, err
 	}
 	defer txn.Done()
 
 	volinfo, err := volume.GetVolume(volname)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, status, err
 	}
 
 	if volinfo.State == volume.VolStopped {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrVolAlreadyStopped)
-		return
+		return nil, http.StatusBadRequest, errors.ErrVolAlreadyStopped
 	}
 
 	if volinfo.State != volume.VolStarted {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrVolNotStarted)
-		return
+		return nil, http.StatusBadRequest, errors.ErrVolNotStarted
+	}
+
+	if graceful {
+		if timeout > maxGracefulStopTimeout {
+			timeout = maxGracefulStopTimeout
+		}
+		hosts, err := waitForClients(ctx, volinfo, timeout)
+		if err != nil {
+			logger.WithError(err).WithField(
+				"volume", volname).Error("failed to get clients connected to volume")
+			return nil, http.StatusInternalServerError, err
+		}
+		if len(hosts) != 0 {
+			return nil, http.StatusConflict,
+				fmt.Errorf("%s: %s", errors.ErrVolHasActiveClients, strings.Join(hosts, ", "))
+		}
 	}
 
 	txn.Steps = []*transaction.Step{
+		hooks.Step(hooks.OpStop, hooks.Pre, volinfo.Nodes()),
 		{
 			DoFunc: "vol-stop.StopBricks",
 			Nodes:  volinfo.Nodes(),
+			// Need to wait for pre hooks to succeed on all nodes
+			Sync: true,
 		},
 		{
 			DoFunc:   "vol-stop.UpdateVolinfo",
@@ -165,18 +265,17 @@ func volumeStopHandler(w http.ResponseWriter, r *http.Request) {
 			UndoFunc: "vol-stop.XlatorActionUndoVolumeStop",
 			Nodes:    volinfo.Nodes(),
 		},
+		hooks.Step(hooks.OpStop, hooks.Post, volinfo.Nodes()),
 	}
 
 	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	volinfo.State = volume.VolStopped
 
 	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	span.AddAttributes(
@@ -187,14 +286,12 @@ func volumeStopHandler(w http.ResponseWriter, r *http.Request) {
 	if err := txn.Do(); err != nil {
 		logger.WithError(err).WithField(
 			"volume", volname).Error("transaction to stop volume failed")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
-	events.Broadcast(volume.NewEvent(volume.EventVolumeStopped, volinfo))
+	recordVolumeHistory(ctx, volname, volume.EventVolumeStopped, txn.Ctx.GetTxnID(), nil)
 
-	resp := createVolumeStopResp(volinfo)
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+	return volinfo, http.StatusOK, nil
 }
 
 func createVolumeStopResp(v *volume.Volinfo) *api.VolumeStopResp {
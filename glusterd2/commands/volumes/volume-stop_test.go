@@ -0,0 +1,39 @@
+package volumecommands
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGracefulStopParams(t *testing.T) {
+	tests := []struct {
+		query    string
+		graceful bool
+		timeout  time.Duration
+		valid    bool
+	}{
+		{"", false, 0, true},
+		{"graceful=true", true, 0, true},
+		{"graceful=true&timeout=30", true, 30 * time.Second, true},
+		{"graceful=true&timeout=600", true, maxGracefulStopTimeout, true},
+		{"graceful=true&timeout=601", false, 0, false},
+		{"graceful=true&timeout=-1", false, 0, false},
+		{"graceful=true&timeout=abc", false, 0, false},
+		{"graceful=maybe", false, 0, false},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("POST", "/v1/volumes/gv0/stop?"+tt.query, nil)
+		graceful, timeout, err := parseGracefulStopParams(r)
+		if !tt.valid {
+			assert.Error(t, err, tt.query)
+			continue
+		}
+		assert.NoError(t, err, tt.query)
+		assert.Equal(t, tt.graceful, graceful, tt.query)
+		assert.Equal(t, tt.timeout, timeout, tt.query)
+	}
+}
@@ -0,0 +1,235 @@
+package volumecommands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc/dict"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	brickTopTxnKey string = "bricktop"
+
+	// cliStatsTop is GF_CLI_STATS_TOP, the io-stats op which reports the
+	// files with the highest counts for a metric
+	cliStatsTop = 4
+
+	defaultTopCount = 10
+	maxTopCount     = 100
+)
+
+// topMetrics maps the top metrics to the io-stats top ops
+// (GF_CLI_TOP_OPEN...GF_CLI_TOP_READDIR)
+var topMetrics = map[string]int{
+	"open":    1,
+	"read":    2,
+	"write":   3,
+	"opendir": 4,
+	"readdir": 5,
+}
+
+func registerVolTopStepFuncs() {
+	transaction.RegisterStepFunc(txnBrickTop, "vol-top.Get")
+}
+
+func parseTopParams(r *http.Request) (string, int, error) {
+	metric := r.URL.Query().Get("metric")
+	if metric == "" {
+		metric = "read"
+	}
+	if _, ok := topMetrics[metric]; !ok {
+		return "", 0, fmt.Errorf("invalid value for metric: %s. Possible values: open, read, write, opendir, readdir", metric)
+	}
+
+	count := defaultTopCount
+	if v := r.URL.Query().Get("count"); v != "" {
+		var err error
+		count, err = strconv.Atoi(v)
+		if err != nil || count < 1 || count > maxTopCount {
+			return "", 0, fmt.Errorf("invalid value for count: %s. Should be between 1 and %d", v, maxTopCount)
+		}
+	}
+	return metric, count, nil
+}
+
+// getBrickTop queries io-stats of the brick for the files with the highest
+// counts for the metric
+func getBrickTop(b brick.Brickinfo, metric string, count int) (*api.BrickTop, error) {
+	brickDaemon, err := brick.NewGlusterfsd(b)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := daemon.GetRPCClient(brickDaemon)
+	if err != nil {
+		return nil, err
+	}
+
+	reqDict := map[string]string{
+		"op":       strconv.Itoa(cliStatsTop),
+		"top-op":   strconv.Itoa(topMetrics[metric]),
+		"list-cnt": strconv.Itoa(count),
+		"volname":  b.VolumeName,
+		"vol-id":   b.VolumeID.String(),
+	}
+	req := &brick.GfBrickOpReq{
+		Name: b.Path,
+		Op:   int(brick.OpBrickXlatorInfo),
+	}
+	if req.Input, err = dict.Serialize(reqDict); err != nil {
+		return nil, err
+	}
+
+	var rsp brick.GfBrickOpRsp
+	if err := client.Call("Brick.OpBrickXlatorInfo", req, &rsp); err != nil {
+		return nil, err
+	}
+	if rsp.OpRet != 0 {
+		return nil, fmt.Errorf("brick top failed: %s", rsp.OpErrstr)
+	}
+
+	output, err := dict.Unserialize(rsp.Output)
+	if err != nil {
+		return nil, err
+	}
+
+	top := &api.BrickTop{
+		Brick: brick.CreateBrickInfo(&b),
+	}
+	if metric == "open" {
+		top.CurrentOpenFds, _ = strconv.ParseUint(output["current-open"], 10, 64)
+		top.MaxOpenFds, _ = strconv.ParseUint(output["max-open"], 10, 64)
+		top.MaxOpenFdsTime = output["max-openfd-time"]
+	}
+
+	members, _ := strconv.Atoi(output["members"])
+	top.Entries = make([]api.TopEntry, 0, members)
+	for i := 1; i <= members; i++ {
+		e := api.TopEntry{
+			Name: output[fmt.Sprintf("filename-%d", i)],
+		}
+		e.Count, _ = strconv.ParseUint(output[fmt.Sprintf("value-%d", i)], 10, 64)
+		top.Entries = append(top.Entries, e)
+	}
+
+	return top, nil
+}
+
+func txnBrickTop(c transaction.TxnCtx) error {
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		return err
+	}
+
+	var metric string
+	if err := c.Get("metric", &metric); err != nil {
+		return err
+	}
+
+	var count int
+	if err := c.Get("count", &count); err != nil {
+		return err
+	}
+
+	var result []api.BrickTop
+	for _, b := range volinfo.GetLocalBricks() {
+		top, err := getBrickTop(b, metric, count)
+		if err != nil {
+			c.Logger().WithError(err).WithField(
+				"brick", b.String()).Error("failed to get top of brick")
+			return err
+		}
+		result = append(result, *top)
+	}
+
+	return c.SetNodeResult(gdctx.MyUUID, brickTopTxnKey, result)
+}
+
+func getVolumeTop(ctx context.Context, volinfo *volume.Volinfo, metric string, count int) ([]api.BrickTop, error) {
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "vol-top.Get",
+			Nodes:  volinfo.Nodes(),
+		},
+	}
+	txn.DisableRollback = true
+
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		return nil, err
+	}
+	if err := txn.Ctx.Set("metric", metric); err != nil {
+		return nil, err
+	}
+	if err := txn.Ctx.Set("count", count); err != nil {
+		return nil, err
+	}
+
+	if err := txn.Do(); err != nil {
+		return nil, err
+	}
+
+	var result []api.BrickTop
+	for _, node := range volinfo.Nodes() {
+		var tmp []api.BrickTop
+		if err := txn.Ctx.GetNodeResult(node, brickTopTxnKey, &tmp); err != nil {
+			return nil, err
+		}
+		result = append(result, tmp...)
+	}
+
+	return result, nil
+}
+
+func volumeTopHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	metric, count, err := parseTopParams(r)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if volinfo.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrVolNotStarted)
+		return
+	}
+
+	bricks, err := getVolumeTop(ctx, volinfo, metric, count)
+	if err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to get top of volume")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := &api.VolumeTopResp{
+		Volume: volname,
+		Metric: metric,
+		Bricks: bricks,
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
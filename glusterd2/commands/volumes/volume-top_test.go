@@ -0,0 +1,30 @@
+package volumecommands
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseTopParams validates parseTopParams()
+func TestParseTopParams(t *testing.T) {
+	r := httptest.NewRequest("GET", "/v1/volumes/vol1/top", nil)
+	metric, count, err := parseTopParams(r)
+	require.Nil(t, err)
+	assert.Equal(t, "read", metric)
+	assert.Equal(t, defaultTopCount, count)
+
+	r = httptest.NewRequest("GET", "/v1/volumes/vol1/top?metric=opendir&count=25", nil)
+	metric, count, err = parseTopParams(r)
+	require.Nil(t, err)
+	assert.Equal(t, "opendir", metric)
+	assert.Equal(t, 25, count)
+
+	for _, q := range []string{"metric=lookup", "count=0", "count=101", "count=ten"} {
+		r = httptest.NewRequest("GET", "/v1/volumes/vol1/top?"+q, nil)
+		_, _, err = parseTopParams(r)
+		assert.NotNil(t, err, q)
+	}
+}
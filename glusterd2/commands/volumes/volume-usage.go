@@ -0,0 +1,96 @@
+package volumecommands
+
+import (
+	"net/http"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	"go.opencensus.io/trace"
+)
+
+func volumeUsageHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	ctx, span := trace.StartSpan(ctx, "/volumeUsageHandler")
+	defer span.End()
+
+	volname := mux.Vars(r)["volname"]
+	span.AddAttributes(trace.StringAttribute("volName", volname))
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	usages, err := volume.GetUsage(volname)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, createVolumeUsageResp(volinfo, usages))
+}
+
+// createVolumeUsageResp reports the latest sample of each brick of the
+// volume. Samples of bricks no longer in the volume are left out.
+func createVolumeUsageResp(v *volume.Volinfo, usages []*volume.PeerUsage) *api.VolumeUsageResp {
+	type brickKey struct {
+		peer string
+		path string
+	}
+
+	samples := make(map[brickKey]api.BrickUsage)
+	for _, pu := range usages {
+		if !uuid.Equal(pu.VolumeID, v.ID) {
+			continue
+		}
+		for _, bu := range pu.Bricks {
+			s := api.BrickUsage{
+				PeerID: pu.PeerID,
+				Path:   bu.Path,
+				Size: api.SizeInfo{
+					Capacity: bu.Capacity,
+					Used:     bu.Used,
+					Free:     bu.Free,
+				},
+				SampledAt: pu.Time,
+			}
+			if bu.TpName != "" {
+				s.ThinPool = &api.ThinPoolUsage{
+					VgName:   bu.VgName,
+					TpName:   bu.TpName,
+					Capacity: bu.TpCapacity,
+					Used:     bu.TpUsed,
+				}
+			}
+			samples[brickKey{pu.PeerID.String(), bu.Path}] = s
+		}
+	}
+
+	resp := &api.VolumeUsageResp{
+		Volname: v.Name,
+		Bricks:  []api.BrickUsage{},
+	}
+	for _, b := range v.GetBricks() {
+		s, ok := samples[brickKey{b.PeerID.String(), b.Path}]
+		if !ok {
+			resp.Bricks = append(resp.Bricks, api.BrickUsage{
+				PeerID: b.PeerID,
+				Path:   b.Path,
+			})
+			continue
+		}
+		resp.Total.Capacity += s.Size.Capacity
+		resp.Total.Used += s.Size.Used
+		resp.Total.Free += s.Size.Free
+		resp.Bricks = append(resp.Bricks, s)
+	}
+
+	return resp
+}
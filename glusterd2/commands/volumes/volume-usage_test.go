@@ -0,0 +1,70 @@
+package volumecommands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCreateVolumeUsageResp validates createVolumeUsageResp()
+func TestCreateVolumeUsageResp(t *testing.T) {
+	p1, p2 := uuid.NewRandom(), uuid.NewRandom()
+	v := getSampleReplicateVolinfo(p1, 1)
+	v.ID = uuid.NewRandom()
+	v.Subvols[0].Bricks[1].PeerID = p2
+
+	now := time.Now()
+	usages := []*volume.PeerUsage{
+		{
+			VolumeID: v.ID,
+			PeerID:   p1,
+			Time:     now,
+			Bricks: []volume.BrickUsage{
+				{
+					Path:       "/bricks/a0",
+					SizeInfo:   volume.SizeInfo{Capacity: 100, Used: 40, Free: 60},
+					VgName:     "vg1",
+					TpName:     "tp1",
+					TpCapacity: 200,
+					TpUsed:     50,
+				},
+				// brick no longer in the volume
+				{Path: "/bricks/old", SizeInfo: volume.SizeInfo{Capacity: 1000}},
+			},
+		},
+		// samples of an earlier volume with the same name
+		{
+			VolumeID: uuid.NewRandom(),
+			PeerID:   p2,
+			Time:     now,
+			Bricks:   []volume.BrickUsage{{Path: "/bricks/a1", SizeInfo: volume.SizeInfo{Capacity: 1000}}},
+		},
+	}
+
+	resp := createVolumeUsageResp(v, usages)
+	assert.Equal(t, "vol", resp.Volname)
+	require.Len(t, resp.Bricks, 2)
+
+	b := resp.Bricks[0]
+	assert.True(t, uuid.Equal(p1, b.PeerID))
+	assert.Equal(t, uint64(40), b.Size.Used)
+	assert.Equal(t, now, b.SampledAt)
+	require.NotNil(t, b.ThinPool)
+	assert.Equal(t, uint64(50), b.ThinPool.Used)
+
+	// the second brick hasn't been sampled
+	b = resp.Bricks[1]
+	assert.True(t, uuid.Equal(p2, b.PeerID))
+	assert.Equal(t, "/bricks/a1", b.Path)
+	assert.True(t, b.SampledAt.IsZero())
+	assert.Nil(t, b.ThinPool)
+
+	assert.Equal(t, uint64(100), resp.Total.Capacity)
+	assert.Equal(t, uint64(40), resp.Total.Used)
+	assert.Equal(t, uint64(60), resp.Total.Free)
+}
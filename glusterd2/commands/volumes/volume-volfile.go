@@ -0,0 +1,113 @@
+package volumecommands
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volgen"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	"github.com/gorilla/mux"
+	"go.opencensus.io/trace"
+)
+
+// volfileTypeAliases are the short names accepted for volfile templates
+var volfileTypeAliases = map[string]string{
+	"shd": utils.SelfHealVolfile,
+}
+
+// generateVolfile generates the volfile of the volume from the template. The
+// brick is only used for brick level templates. Cluster level volfiles are
+// generated with only the graph of the volume, as if it were started.
+func generateVolfile(tmpl *volgen.Template, volinfo *volume.Volinfo, b *brick.Brickinfo) (string, error) {
+	switch tmpl.Level {
+	case volgen.VolfileLevelBrick:
+		if b == nil {
+			return "", errors.ErrVolfileBrickRequired
+		}
+		return volgen.BrickLevelVolfile(tmpl, volinfo, b.PeerID.String(), b.Path)
+
+	case volgen.VolfileLevelCluster:
+		v := *volinfo
+		v.State = volume.VolStarted
+		return volgen.ClusterLevelVolfile(tmpl, []*volume.Volinfo{&v})
+
+	default:
+		return volgen.VolumeLevelVolfile(tmpl, volinfo)
+	}
+}
+
+func volumeVolfileHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx, span := trace.StartSpan(ctx, "/volumeVolfileHandler")
+	defer span.End()
+
+	volname := mux.Vars(r)["volname"]
+	volfileType := mux.Vars(r)["type"]
+	brickID := r.URL.Query().Get("brick")
+
+	span.AddAttributes(
+		trace.StringAttribute("volName", volname),
+		trace.StringAttribute("type", volfileType),
+	)
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, volinfo.GetNamespace()) {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrVolNotFound)
+		return
+	}
+
+	tmplName := volfileType
+	if name, ok := volfileTypeAliases[volfileType]; ok {
+		tmplName = name
+	}
+	tmpl, err := volgen.GetTemplateFromVolinfo(volinfo, tmplName)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+
+	var b *brick.Brickinfo
+	if brickID != "" {
+		for _, vb := range volinfo.GetBricks() {
+			if vb.ID.String() == brickID {
+				b = &vb
+				break
+			}
+		}
+		if b == nil {
+			restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrBrickNotInVolume)
+			return
+		}
+	}
+
+	content, err := generateVolfile(tmpl, volinfo, b)
+	if err == errors.ErrVolfileBrickRequired {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := &api.VolfileResp{
+		VolName:  volname,
+		Type:     tmplName,
+		Checksum: volgen.Checksum(content),
+		Content:  content,
+	}
+	if b != nil && tmpl.Level == volgen.VolfileLevelBrick {
+		resp.BrickID = brickID
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
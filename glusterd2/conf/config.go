@@ -7,8 +7,14 @@ import (
 	"path"
 	"strings"
 
+	"github.com/gluster/glusterd2/glusterd2/audit"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/devicehealth"
+	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/pmap"
 	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/usage"
 	"github.com/gluster/glusterd2/pkg/logging"
 	"github.com/gluster/glusterd2/pkg/tracing"
 
@@ -43,6 +49,7 @@ func initFlags() {
 	flag.String("localstatedir", defaultlocalstatedir, "Directory to store local state information.")
 	flag.String("rundir", defaultrundir, "Directory to store runtime data.")
 	flag.String("config", "", "Configuration file for GlusterD.")
+	flag.String("cluster-id", "", "ID of the cluster a new peer belongs to, and namespace of its keys in the store. Peers sharing an etcd cluster must use the same ID per glusterd2 cluster. (default random)")
 
 	flag.String(logging.DirFlag, defaultlogdir, logging.DirHelp)
 	flag.String(logging.FileFlag, defaultlogfile, logging.FileHelp)
@@ -51,13 +58,37 @@ func initFlags() {
 
 	// TODO: Change default to false (disabled) in future.
 	flag.Bool("statedump", true, "Enable /statedump endpoint for metrics.")
+	flag.Bool("metrics", true, "Enable /metrics endpoint for Prometheus metrics.")
+	flag.Bool("heketi-compat", false, "Enable the Heketi compatible API under /v1/heketi.")
 
 	flag.String("clientaddress", defaultclientaddress, "Address to bind the REST service.")
 	flag.String("peeraddress", defaultpeeraddress, "Address to bind the inter glusterd2 RPC service.")
+	flag.String("localsocket", "", "Unix socket on which to serve the REST API to root and the user running glusterd2, without tokens. Empty to disable.")
 
-	// TODO: SSL/TLS is currently only implemented for REST interface
 	flag.String("cert-file", "", "Certificate used for SSL/TLS connections from clients to glusterd2.")
 	flag.String("key-file", "", "Private key for the SSL/TLS certificate.")
+	flag.String("client-ca-file", "", "CA which must have signed the certificates of clients connecting over SSL/TLS.")
+	flag.String("peer-cert-file", "", "Certificate used for SSL/TLS connections between glusterd2 peers.")
+	flag.String("peer-key-file", "", "Private key for the peer SSL/TLS certificate.")
+	flag.String("peer-ca-file", "", "CA which must have signed the certificates of glusterd2 peers.")
+
+	flag.String(audit.LogFileFlag, "audit.log", "Name of the audit log file in logdir, empty to not write an audit log file.")
+	flag.Bool(audit.StoreFlag, false, "Keep audit records in the store, so that they can be queried with GET /v1/audit.")
+
+	flag.Duration(events.RetentionFlag, events.DefaultRetention, "Time events are kept in the store, so that they can be queried with GET /v1/events, 0 to not keep them.")
+	flag.Duration(usage.IntervalFlag, usage.DefaultInterval, "Interval at which the usage of local bricks is sampled, 0 to disable.")
+	flag.Duration(devicehealth.IntervalFlag, devicehealth.DefaultInterval, "Interval at which the SMART health of local devices is checked, 0 to disable.")
+	flag.Bool(devicehealth.DisableFailingFlag, false, "Disable devices which report that they are failing, so that no new bricks are provisioned on them.")
+	flag.Duration(daemon.CheckIntervalFlag, daemon.DefaultCheckInterval, "Interval at which brick and other gluster processes are checked and restarted if they died, 0 to disable.")
+	flag.Int(daemon.RestartMaxRetriesFlag, daemon.DefaultRestartMaxRetries, "Times a gluster process which died is restarted before it is marked failed.")
+	flag.String(pmap.PortRangeFlag, "", "Range of ports brick processes listen on, as <min>-<max>. Empty to use the cluster.brick-port-range cluster option.")
+	flag.String(daemon.CgroupRootFlag, "", "Cgroup (v2) under which brick and other gluster processes are run, with the resource limits of their volume. Empty to disable.")
+
+	flag.Float64("ratelimit", 0, "Requests per second allowed from a REST client, 0 for no limit.")
+	flag.Int("ratelimitburst", 0, "Requests a REST client may make at once over ratelimit. (default ratelimit rounded up)")
+	flag.Float64("globalratelimit", 0, "Requests per second allowed from all REST clients together, 0 for no limit.")
+	flag.Int("globalratelimitburst", 0, "Requests all REST clients may make at once over globalratelimit. (default globalratelimit rounded up)")
+	flag.Int("maxconcurrentmutations", 0, "Requests changing the cluster served at a time, 0 for no limit.")
 
 	// PID file
 	flag.String("pidfile", "", "PID file path. (default \"rundir/glusterd2.pid)\"")
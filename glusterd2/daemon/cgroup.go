@@ -0,0 +1,83 @@
+package daemon
+
+import (
+	"path"
+
+	"github.com/gluster/glusterd2/pkg/cgroups"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+// CgroupRootFlag is the flag to set the cgroup under which the daemons are
+// run. Daemons are not placed into cgroups when it is empty.
+const CgroupRootFlag = "cgroup-root"
+
+// CgroupDaemon can be implemented by daemons which should be run in a cgroup
+// other than the one named after the daemon, for example to share resource
+// limits with other daemons.
+type CgroupDaemon interface {
+	Daemon
+
+	// Cgroup should return the name of the cgroup, relative to the
+	// cgroup-root, which the daemon is run in.
+	Cgroup() string
+}
+
+// CgroupsEnabled returns true if the daemons are run in cgroups
+func CgroupsEnabled() bool {
+	return config.GetString(CgroupRootFlag) != ""
+}
+
+// CgroupPath returns the absolute path of the cgroup with the given name
+func CgroupPath(name string) string {
+	return path.Join(config.GetString(CgroupRootFlag), name)
+}
+
+// cgroupOf returns the name of the cgroup the daemon is run in
+func cgroupOf(d Daemon) string {
+	if cd, ok := d.(CgroupDaemon); ok {
+		return cd.Cgroup()
+	}
+	return d.Name()
+}
+
+// SetCgroupLimits creates the cgroup with the given name if needed and sets
+// its limits. It does nothing if cgroups are disabled.
+func SetCgroupLimits(name string, l cgroups.Limits) error {
+	if !CgroupsEnabled() {
+		return nil
+	}
+
+	cgroup := CgroupPath(name)
+	if err := cgroups.Create(cgroup); err != nil {
+		return err
+	}
+	return cgroups.SetLimits(cgroup, l)
+}
+
+// GetCgroupUsage returns the resource usage of the cgroup with the given name
+func GetCgroupUsage(name string) (cgroups.Usage, error) {
+	return cgroups.GetUsage(CgroupPath(name))
+}
+
+// placeInCgroup moves the running daemon into its cgroup. Failing to do so
+// isn't fatal, the daemon keeps running without limits.
+func placeInCgroup(d Daemon, pid int, logger log.FieldLogger) {
+	if !CgroupsEnabled() {
+		return
+	}
+
+	cgroup := CgroupPath(cgroupOf(d))
+	err := cgroups.Create(cgroup)
+	if err == nil {
+		err = cgroups.AddProcess(cgroup, pid)
+	}
+	if err != nil {
+		logger.WithError(err).WithFields(log.Fields{
+			"name":   d.Name(),
+			"pid":    pid,
+			"cgroup": cgroup,
+		}).Warn("failed to place daemon into cgroup, it runs without resource limits")
+	}
+}
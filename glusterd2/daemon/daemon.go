@@ -52,6 +52,11 @@ type Daemon interface {
 // When wait == true, this function can be used to spawn short term processes
 // which will be waited on for completion before this function returns.
 func Start(d Daemon, wait bool, logger log.FieldLogger) error {
+	forgetRestarts(d)
+	return start(d, wait, logger)
+}
+
+func start(d Daemon, wait bool, logger log.FieldLogger) error {
 
 	logger.WithFields(log.Fields{
 		"name": d.Name(),
@@ -102,6 +107,8 @@ func Start(d Daemon, wait bool, logger log.FieldLogger) error {
 			return err
 		}
 
+		placeInCgroup(d, pid, logger)
+
 		logger.WithFields(log.Fields{
 			"name": d.Name(),
 			"pid":  pid,
@@ -109,6 +116,8 @@ func Start(d Daemon, wait bool, logger log.FieldLogger) error {
 		events.Broadcast(newEvent(d, daemonStarted, pid))
 
 	} else {
+		placeInCgroup(d, cmd.Process.Pid, logger)
+
 		// If the process exits at some point later, do read it's
 		// exit status. This should not let it be a zombie.
 		go func() {
@@ -166,6 +175,7 @@ func Stop(d Daemon, force bool, logger log.FieldLogger) error {
 	}).Debug("Stopping daemon.")
 	events.Broadcast(newEvent(d, daemonStopping, pid))
 
+	forgetRestarts(d)
 	err = Kill(pid, force)
 
 	// TODO: Do this under some lock ?
@@ -20,6 +20,9 @@ const (
 	daemonStartingAll                = "daemon.startingall"
 	daemonStartedAll                 = "daemon.startedall"
 	daemonStartAllFailed             = "daemon.startallfailed"
+	daemonRestarting                 = "daemon.restarting"
+	daemonRestarted                  = "daemon.restarted"
+	daemonFailed                     = "daemon.failed"
 )
 
 // newEvent returns an event of given type with daemon data filled
@@ -32,5 +35,34 @@ func newEvent(d Daemon, e daemonEvent, pid int) *api.Event {
 		"pid":    strconv.Itoa(pid),
 	}
 
-	return events.New(string(e), data, false)
+	return events.New(string(e), data, false, api.EventObject{
+		Kind: api.EventObjectDaemon,
+		ID:   d.ID(),
+		Name: d.Name(),
+	})
+}
+
+func init() {
+	data := []string{"name", "id", "binary", "args", "pid"}
+	restartData := []string{"name", "id", "binary", "args", "pid", "attempts"}
+	daemon := []string{api.EventObjectDaemon}
+	events.RegisterTypes(
+		api.EventType{Name: string(daemonStarting), Description: "A daemon is being started", Objects: daemon, Data: data},
+		api.EventType{Name: daemonStarted, Description: "A daemon was started", Objects: daemon, Data: data},
+		api.EventType{Name: daemonStartFailed, Severity: api.EventSeverityError,
+			Description: "A daemon failed to start", Objects: daemon, Data: data},
+		api.EventType{Name: daemonStopping, Description: "A daemon is being stopped", Objects: daemon, Data: data},
+		api.EventType{Name: daemonStopped, Description: "A daemon was stopped", Objects: daemon, Data: data},
+		api.EventType{Name: daemonStopFailed, Severity: api.EventSeverityError,
+			Description: "A daemon failed to stop", Objects: daemon, Data: data},
+		api.EventType{Name: daemonStartingAll, Description: "The daemons of the peer are being started"},
+		api.EventType{Name: daemonStartedAll, Description: "The daemons of the peer were started"},
+		api.EventType{Name: daemonStartAllFailed, Severity: api.EventSeverityError,
+			Description: "The daemons of the peer failed to start"},
+		api.EventType{Name: daemonRestarting, Severity: api.EventSeverityWarning,
+			Description: "A daemon which died is being restarted", Objects: daemon, Data: restartData},
+		api.EventType{Name: daemonRestarted, Description: "A daemon which died was restarted", Objects: daemon, Data: restartData},
+		api.EventType{Name: daemonFailed, Severity: api.EventSeverityCritical,
+			Description: "A daemon died too many times and is no longer restarted", Objects: daemon, Data: restartData},
+	)
 }
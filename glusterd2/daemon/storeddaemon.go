@@ -3,7 +3,7 @@ package daemon
 // storedDaemon is used to save/retrieve a daemons information in the store,
 // and also implements the Daemon interface
 type storedDaemon struct {
-	DName, DPath, DSocketFile, DPidFile, DID string
+	DName, DPath, DSocketFile, DPidFile, DID, DCgroup string
 
 	DArgs []string
 }
@@ -16,6 +16,7 @@ func newStoredDaemon(d Daemon) *storedDaemon {
 		DSocketFile: d.SocketFile(),
 		DPidFile:    d.PidFile(),
 		DID:         d.ID(),
+		DCgroup:     cgroupOf(d),
 	}
 }
 
@@ -42,3 +43,10 @@ func (s *storedDaemon) PidFile() string {
 func (s *storedDaemon) ID() string {
 	return s.DID
 }
+
+func (s *storedDaemon) Cgroup() string {
+	if s.DCgroup == "" {
+		return s.DName
+	}
+	return s.DCgroup
+}
@@ -0,0 +1,235 @@
+package daemon
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+const (
+	// CheckIntervalFlag is the flag to set the interval at which the
+	// daemons which should be running are checked
+	CheckIntervalFlag = "daemon-check-interval"
+	// DefaultCheckInterval is the default interval between checks
+	DefaultCheckInterval = 10 * time.Second
+	// RestartMaxRetriesFlag is the flag to set the number of times a daemon
+	// which died is restarted before it is marked failed
+	RestartMaxRetriesFlag = "daemon-restart-max-retries"
+	// DefaultRestartMaxRetries is the default number of restarts
+	DefaultRestartMaxRetries = 5
+)
+
+const (
+	// restartBackoff is the wait before the first restart of a daemon,
+	// which doubles with every following restart up to maxRestartBackoff
+	restartBackoff    = 2 * time.Second
+	maxRestartBackoff = 5 * time.Minute
+	// restartResetAfter is how long a restarted daemon has to stay up for
+	// its restarts to be forgotten
+	restartResetAfter = 10 * time.Minute
+)
+
+// restartState is what the supervisor knows about a daemon which died
+type restartState struct {
+	// downSince is when the daemon was first found not running. It is
+	// only restarted once found not running twice in a row, so that
+	// daemons being stopped aren't restarted.
+	downSince   time.Time
+	attempts    int
+	lastRestart time.Time
+	failed      bool
+}
+
+var supervisor = struct {
+	sync.Mutex
+	stop   chan struct{}
+	done   chan struct{}
+	states map[string]*restartState
+}{
+	states: make(map[string]*restartState),
+}
+
+// StartSupervisor starts checking every daemon-check-interval that the
+// daemons started by glusterd2 are still running. A daemon which died is
+// restarted with an exponential backoff, and marked failed once it has been
+// restarted daemon-restart-max-retries times without staying up. Checking is
+// disabled if the interval is 0.
+func StartSupervisor() {
+	interval := config.GetDuration(CheckIntervalFlag)
+	if interval <= 0 {
+		log.Info("daemon supervision disabled")
+		return
+	}
+
+	supervisor.Lock()
+	defer supervisor.Unlock()
+	if supervisor.stop != nil {
+		return
+	}
+	supervisor.stop = make(chan struct{})
+	supervisor.done = make(chan struct{})
+
+	go supervise(interval, supervisor.stop, supervisor.done)
+}
+
+// StopSupervisor stops checking the daemons and waits for a check being done
+// to finish
+func StopSupervisor() {
+	supervisor.Lock()
+	stop, done := supervisor.stop, supervisor.done
+	supervisor.stop = nil
+	supervisor.done = nil
+	supervisor.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// HasFailed returns true if the daemon died and could not be restarted. It is
+// cleared when the daemon is started again.
+func HasFailed(d Daemon) bool {
+	supervisor.Lock()
+	defer supervisor.Unlock()
+
+	s, ok := supervisor.states[d.ID()]
+	return ok && s.failed
+}
+
+// forgetRestarts clears what the supervisor knows about the daemon, after it
+// has been started or stopped by glusterd2
+func forgetRestarts(d Daemon) {
+	supervisor.Lock()
+	delete(supervisor.states, d.ID())
+	supervisor.Unlock()
+}
+
+func supervise(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		if gdctx.IsTerminating {
+			continue
+		}
+		checkDaemons()
+	}
+}
+
+// restartWait returns how long to wait before restarting a daemon which has
+// already been restarted the given number of times
+func restartWait(attempts int) time.Duration {
+	wait := restartBackoff
+	for i := 0; i < attempts; i++ {
+		wait *= 2
+		if wait >= maxRestartBackoff {
+			return maxRestartBackoff
+		}
+	}
+	return wait
+}
+
+// checkDaemons restarts the daemons saved in the store which aren't running
+func checkDaemons() {
+	ds, err := getDaemons()
+	if err != nil {
+		log.WithError(err).Warn("failed to get saved daemons, not checking if they are running")
+		return
+	}
+
+	maxRetries := config.GetInt(RestartMaxRetriesFlag)
+	for _, d := range ds {
+		running, _ := IsRunning(d)
+		if attempt := nextRestart(d, running, maxRetries, time.Now()); attempt > 0 {
+			restart(d, attempt)
+		}
+	}
+}
+
+// nextRestart updates what the supervisor knows about the daemon and returns
+// the number of the restart attempt to make now, or 0 if the daemon should not
+// be restarted now
+func nextRestart(d Daemon, running bool, maxRetries int, now time.Time) int {
+	supervisor.Lock()
+	defer supervisor.Unlock()
+
+	s, known := supervisor.states[d.ID()]
+	switch {
+	case running:
+		if known && (s.failed || now.Sub(s.lastRestart) > restartResetAfter) {
+			delete(supervisor.states, d.ID())
+		} else if known {
+			s.downSince = time.Time{}
+		}
+		return 0
+
+	case !known:
+		supervisor.states[d.ID()] = &restartState{downSince: now}
+		return 0
+
+	case s.failed:
+		return 0
+
+	case s.downSince.IsZero():
+		s.downSince = now
+		return 0
+
+	case s.attempts > 0 && now.Sub(s.lastRestart) < restartWait(s.attempts-1):
+		return 0
+
+	case s.attempts >= maxRetries:
+		s.failed = true
+		log.WithFields(log.Fields{
+			"name":     d.Name(),
+			"id":       d.ID(),
+			"attempts": s.attempts,
+		}).Error("daemon could not be restarted, marking it failed")
+		events.Broadcast(newRestartEvent(d, daemonFailed, s.attempts))
+		return 0
+	}
+
+	s.attempts++
+	s.lastRestart = now
+	return s.attempts
+}
+
+// restart starts a daemon which died again
+func restart(d Daemon, attempt int) {
+	logger := log.WithFields(log.Fields{
+		"name":    d.Name(),
+		"id":      d.ID(),
+		"attempt": attempt,
+	})
+	logger.Warn("daemon is not running, restarting it")
+	events.Broadcast(newRestartEvent(d, daemonRestarting, attempt))
+
+	err := start(d, true, logger)
+	if err != nil && err != errors.ErrProcessAlreadyRunning {
+		logger.WithError(err).Warn("failed to restart daemon")
+		return
+	}
+	events.Broadcast(newRestartEvent(d, daemonRestarted, attempt))
+}
+
+func newRestartEvent(d Daemon, e daemonEvent, attempts int) *api.Event {
+	ev := newEvent(d, e, 0)
+	ev.Data["attempts"] = strconv.Itoa(attempts)
+	return ev
+}
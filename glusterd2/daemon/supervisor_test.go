@@ -0,0 +1,49 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDaemon struct {
+	id string
+}
+
+func (f *fakeDaemon) Name() string       { return "fake" }
+func (f *fakeDaemon) Path() string       { return "/bin/false" }
+func (f *fakeDaemon) Args() []string     { return nil }
+func (f *fakeDaemon) SocketFile() string { return "" }
+func (f *fakeDaemon) PidFile() string    { return "" }
+func (f *fakeDaemon) ID() string         { return f.id }
+
+func TestRestartWait(t *testing.T) {
+	assert.Equal(t, 2*time.Second, restartWait(0))
+	assert.Equal(t, 8*time.Second, restartWait(2))
+	assert.Equal(t, maxRestartBackoff, restartWait(20))
+}
+
+func TestNextRestart(t *testing.T) {
+	d := &fakeDaemon{id: "b1"}
+	defer forgetRestarts(d)
+	now := time.Now()
+
+	// not restarted before it is found down twice
+	assert.Equal(t, 0, nextRestart(d, false, 2, now))
+	assert.Equal(t, 1, nextRestart(d, false, 2, now.Add(time.Second)))
+
+	// backoff
+	assert.Equal(t, 0, nextRestart(d, false, 2, now.Add(2*time.Second)))
+	assert.Equal(t, 2, nextRestart(d, false, 2, now.Add(4*time.Second)))
+	assert.False(t, HasFailed(d))
+
+	// retries exhausted
+	assert.Equal(t, 0, nextRestart(d, false, 2, now.Add(time.Minute)))
+	assert.True(t, HasFailed(d))
+	assert.Equal(t, 0, nextRestart(d, false, 2, now.Add(time.Hour)))
+
+	// finding the daemon running clears the failure
+	assert.Equal(t, 0, nextRestart(d, true, 2, now.Add(time.Hour)))
+	assert.False(t, HasFailed(d))
+}
@@ -2,11 +2,14 @@ package daemon
 
 import (
 	"bytes"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/gluster/glusterd2/pkg/errors"
 )
@@ -84,3 +87,63 @@ func IsRunning(d Daemon) (bool, int) {
 
 	return true, pid
 }
+
+// clockTicks is the number of clock ticks per second in which the kernel
+// reports process times, which is 100 on all Linux architectures
+const clockTicks = 100
+
+// ProcessStartTime returns the time at which the process with the given pid
+// was started
+func ProcessStartTime(pid int) (time.Time, error) {
+	stat, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return time.Time{}, err
+	}
+	ticks, err := parseStartTicks(string(stat))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	procStat, err := ioutil.ReadFile("/proc/stat")
+	if err != nil {
+		return time.Time{}, err
+	}
+	boot, err := parseBootTime(string(procStat))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return boot.Add(time.Duration(ticks) * time.Second / clockTicks), nil
+}
+
+// parseStartTicks returns the start time, in clock ticks since boot, from
+// the contents of /proc/<pid>/stat
+func parseStartTicks(stat string) (uint64, error) {
+	// The command name may contain spaces and parentheses, the fields
+	// after it start at the state, the third field
+	i := strings.LastIndex(stat, ")")
+	if i < 0 {
+		return 0, fmt.Errorf("malformed process stat")
+	}
+	fields := strings.Fields(stat[i+1:])
+	const startTimeField = 22 - 3
+	if len(fields) <= startTimeField {
+		return 0, fmt.Errorf("malformed process stat")
+	}
+	return strconv.ParseUint(fields[startTimeField], 10, 64)
+}
+
+// parseBootTime returns the boot time from the contents of /proc/stat
+func parseBootTime(stat string) (time.Time, error) {
+	for _, line := range strings.Split(stat, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "btime" {
+			secs, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return time.Unix(secs, 0), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("boot time not found")
+}
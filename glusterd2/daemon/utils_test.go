@@ -0,0 +1,29 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStartTicks(t *testing.T) {
+	stat := "1234 (glusterfsd (a) b) S 1 1234 1234 0 -1 4194560 4660 0 0 0 12 7 0 0 20 0 9 0 424242 1138688 2041 18446744073709551615"
+	ticks, err := parseStartTicks(stat)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(424242), ticks)
+
+	_, err = parseStartTicks("1234 (glusterfsd) S 1")
+	assert.Error(t, err)
+}
+
+func TestParseBootTime(t *testing.T) {
+	stat := "cpu  10 0 20 300 0 0 0 0 0 0\nintr 1 2 3\nbtime 1600000000\nprocesses 42\n"
+	boot, err := parseBootTime(stat)
+	require.NoError(t, err)
+	assert.Equal(t, time.Unix(1600000000, 0), boot)
+
+	_, err = parseBootTime("cpu  10 0 20 300 0 0 0 0 0 0\n")
+	assert.Error(t, err)
+}
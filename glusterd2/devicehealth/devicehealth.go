@@ -0,0 +1,187 @@
+// Package devicehealth periodically checks the SMART health of the devices of
+// this peer and saves it with the devices in the store. Events are sent when a
+// device starts or stops reporting that it is failing, and failing devices can
+// optionally be disabled, so that no new bricks are provisioned on them.
+package devicehealth
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/smartutils"
+	deviceapi "github.com/gluster/glusterd2/plugins/device/api"
+	"github.com/gluster/glusterd2/plugins/device/deviceutils"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+const (
+	// IntervalFlag is the flag to set the interval between health checks
+	IntervalFlag = "device-health-interval"
+	// DefaultInterval is the default interval between health checks
+	DefaultInterval = 30 * time.Minute
+	// DisableFailingFlag is the flag to disable devices which report that
+	// they are failing
+	DisableFailingFlag = "device-health-disable-failing"
+)
+
+const (
+	eventDeviceFailing   = "device.failing"
+	eventDeviceRecovered = "device.recovered"
+)
+
+var collector struct {
+	sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Start starts checking the health of the local devices every
+// device-health-interval. Checking is disabled if the interval is 0.
+func Start() {
+	interval := config.GetDuration(IntervalFlag)
+	if interval <= 0 {
+		log.Info("device health monitoring disabled")
+		return
+	}
+
+	collector.Lock()
+	defer collector.Unlock()
+	if collector.stop != nil {
+		return
+	}
+	collector.stop = make(chan struct{})
+	collector.done = make(chan struct{})
+
+	go run(interval, collector.stop, collector.done)
+}
+
+// Stop stops checking and waits for a check being done to finish
+func Stop() {
+	collector.Lock()
+	defer collector.Unlock()
+	if collector.stop == nil {
+		return
+	}
+	close(collector.stop)
+	<-collector.done
+	collector.stop = nil
+	collector.done = nil
+}
+
+func run(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		collect()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check reads the SMART health of the device
+func check(device string) *deviceapi.Health {
+	health := &deviceapi.Health{CheckedAt: time.Now()}
+
+	r, err := smartutils.Check(device)
+	if err != nil {
+		health.Status = deviceapi.DeviceHealthUnknown
+		health.Error = err.Error()
+		return health
+	}
+
+	health.Status = deviceapi.DeviceHealthOK
+	if r.Failing() {
+		health.Status = deviceapi.DeviceHealthFailing
+	}
+	health.Temperature = r.Temperature
+	health.PowerOnHours = r.PowerOnHours
+	health.ReallocatedSectors = r.ReallocatedSectors
+	health.PendingSectors = r.PendingSectors
+	health.FailingAttributes = r.FailingAttributes
+	return health
+}
+
+// collect checks the health of every local device and saves it in the store
+func collect() {
+	peerID := gdctx.MyUUID.String()
+	devices, err := deviceutils.GetDevices(peerID)
+	if err != nil {
+		log.WithError(err).Warn("failed to get devices to check their health")
+		return
+	}
+
+	disableFailing := config.GetBool(DisableFailingFlag)
+	for _, d := range devices {
+		// Loop devices are files on a file system, which have no SMART
+		// health of their own
+		if d.ProvisionerType == api.ProvisionerTypeLoop {
+			continue
+		}
+
+		health := check(d.Device)
+		failing := health.Status == deviceapi.DeviceHealthFailing
+		wasFailing := d.Health != nil && d.Health.Status == deviceapi.DeviceHealthFailing
+
+		disable := failing && disableFailing && d.State != deviceapi.DeviceDisabled
+		if err := deviceutils.SetDeviceHealth(peerID, d.Device, health, disable); err != nil {
+			log.WithError(err).WithField("device", d.Device).Warn("failed to save device health")
+			continue
+		}
+
+		switch {
+		case failing && !wasFailing:
+			log.WithFields(log.Fields{
+				"device":     d.Device,
+				"attributes": strings.Join(health.FailingAttributes, ","),
+				"disabled":   disable,
+			}).Warn("device reports that it is failing")
+			events.Broadcast(newEvent(d.Device, eventDeviceFailing, health, disable))
+		case health.Status == deviceapi.DeviceHealthOK && wasFailing:
+			log.WithField("device", d.Device).Info("device no longer reports that it is failing")
+			events.Broadcast(newEvent(d.Device, eventDeviceRecovered, health, false))
+		}
+	}
+}
+
+// newEvent returns an event of the given type with the device health filled
+func newEvent(device, name string, health *deviceapi.Health, disabled bool) *api.Event {
+	data := map[string]string{
+		"device":             device,
+		"failing-attributes": strings.Join(health.FailingAttributes, ","),
+		"disabled":           strconv.FormatBool(disabled),
+	}
+	return events.New(name, data, true,
+		api.EventObject{Kind: api.EventObjectDevice, Name: device},
+		api.EventObject{Kind: api.EventObjectPeer, ID: gdctx.MyUUID.String()})
+}
+
+func init() {
+	events.RegisterTypes(
+		api.EventType{
+			Name:        eventDeviceFailing,
+			Severity:    api.EventSeverityCritical,
+			Description: "A device of a peer reports that it is failing",
+			Objects:     []string{api.EventObjectDevice, api.EventObjectPeer},
+			Data:        []string{"device", "failing-attributes", "disabled"},
+		},
+		api.EventType{
+			Name:        eventDeviceRecovered,
+			Description: "A device of a peer no longer reports that it is failing",
+			Objects:     []string{api.EventObjectDevice, api.EventObjectPeer},
+			Data:        []string{"device", "failing-attributes", "disabled"},
+		},
+	)
+}
@@ -10,12 +10,17 @@ import (
 	"github.com/pborman/uuid"
 )
 
-// New returns a new Event with given information
+// New returns a new Event with given information, about the given objects.
+// The severity of the event is the one of its registered type.
 // Set global to true if event should be broadast across cluster
-func New(name string, data map[string]string, global bool) *api.Event {
+func New(name string, data map[string]string, global bool, objects ...api.EventObject) *api.Event {
+	name = strings.ToLower(name)
 	return &api.Event{
 		ID:        uuid.NewRandom(),
-		Name:      strings.ToLower(name),
+		Version:   api.EventSchemaVersion,
+		Name:      name,
+		Severity:  severityOf(name),
+		Objects:   objects,
 		Data:      data,
 		Global:    global,
 		Origin:    gdctx.MyUUID,
@@ -40,6 +45,7 @@ func Broadcast(e *api.Event) error {
 func Start() error {
 	StartGlobal()
 	startEventLogger()
+	startHistory()
 	registerGaneshaHandler()
 	registerHooksHandler()
 	startLivenessWatcher()
@@ -49,6 +55,7 @@ func Start() error {
 // Stop stops the events framework, events will no longer be broadcast
 func Stop() error {
 	stopLivenessWatcher()
+	stopHistory()
 	stopEventLogger()
 	StopGlobal()
 	stopHandlers()
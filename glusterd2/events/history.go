@@ -0,0 +1,127 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+const (
+	historyPrefix = "eventhistory/"
+
+	// RetentionFlag is the flag to set how long events are kept in the
+	// store
+	RetentionFlag = "events-retention"
+	// DefaultRetention is the default time events are kept in the store
+	DefaultRetention = 24 * time.Hour
+)
+
+// historyID is the id of the handler saving events in the store, if started
+var historyID *HandlerID
+
+// historyRetention returns the time events are kept in the store, 0 if they
+// are not kept
+func historyRetention() time.Duration {
+	return config.GetDuration(RetentionFlag)
+}
+
+// historyKey returns the key of an event in the store. Keys sort in the
+// order in which the events were created.
+func historyKey(e *api.Event) string {
+	return historyTimeKey(e.Timestamp) + "-" + e.ID.String()
+}
+
+// historyTimeKey returns the key from which the events created at or after
+// t are kept
+func historyTimeKey(t time.Time) string {
+	return fmt.Sprintf("%s%020d", historyPrefix, t.UnixNano())
+}
+
+// historyHandler saves the events created on this peer in the store, with a
+// lease expiring after the retention time. Failing to save an event is only
+// logged.
+func historyHandler(e *api.Event) {
+	// Global events are broadcast again on every peer, they are only
+	// saved by the peer they come from
+	if !uuid.Equal(e.Origin, gdctx.MyUUID) {
+		return
+	}
+
+	entry := log.WithField("event.id", e.ID.String()).WithField("event.name", e.Name)
+
+	v, err := json.Marshal(e)
+	if err != nil {
+		entry.WithError(err).Error("failed to save event, failed to marshal event")
+		return
+	}
+
+	ttl := int64(historyRetention() / time.Second)
+	if ttl < 1 {
+		ttl = 1
+	}
+	l, err := store.Store.Grant(store.Store.Ctx(), ttl)
+	if err != nil {
+		entry.WithError(err).Error("failed to save event, failed to get lease")
+		return
+	}
+
+	if _, err := store.Put(store.Store.Ctx(), historyKey(e), string(v), clientv3.WithLease(l.ID)); err != nil {
+		entry.WithError(err).Error("failed to save event, failed to write event to store")
+	}
+}
+
+// startHistory starts saving events in the store, unless the retention time
+// is 0
+func startHistory() {
+	if historyRetention() <= 0 {
+		log.Info("events retention is 0, not keeping events in the store")
+		return
+	}
+	id := Register(NewHandler(historyHandler))
+	historyID = &id
+}
+
+func stopHistory() {
+	if historyID == nil {
+		return
+	}
+	Unregister(*historyID)
+	historyID = nil
+}
+
+// History returns the events of all the peers kept in the store, oldest
+// first. Only the events created at or after since are returned, unless
+// since is the zero time.
+func History(since time.Time) ([]*api.Event, error) {
+	key, opts := historyPrefix, []clientv3.OpOption{clientv3.WithPrefix()}
+	if !since.IsZero() {
+		key, opts = historyTimeKey(since), []clientv3.OpOption{clientv3.WithRange(clientv3.GetPrefixRangeEnd(historyPrefix))}
+	}
+	opts = append(opts, clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+
+	resp, err := store.Get(context.TODO(), key, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*api.Event, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var e api.Event
+		if err := json.Unmarshal(kv.Value, &e); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal event")
+			continue
+		}
+		events = append(events, &e)
+	}
+	return events, nil
+}
@@ -14,7 +14,10 @@ import (
 )
 
 const (
-	eventVolumeCreated     = "volume.created"
+	// These events are not broadcast, so the set and reset hook scripts
+	// are not run. Running them on the volume.options-set and
+	// volume.options-reset events would run the glusterd1 Samba hook
+	// scripts along with the SMB plugin.
 	eventVolumeOptionSet   = "volume.option.set"
 	eventVolumeOptionReset = "volume.option.reset"
 	eventVolumeDeleted     = "volume.deleted"
@@ -22,17 +25,14 @@ const (
 	eventBrickRemoved      = "brick.removed"
 )
 
+// hooks runs the post hook scripts of operations on the events they
+// broadcast. Hook scripts of volume create, start and stop are run by their
+// transactions instead, see package glusterd2/hooks.
 type hooks struct{}
 
 func (h *hooks) Handle(e *api.Event) {
 	var cmd string
 	switch e.Name {
-	case eventVolumeCreated:
-		cmd = "create"
-	case eventVolumeStarted:
-		cmd = "start"
-	case eventVolumeStopped:
-		cmd = "stop"
 	case eventVolumeOptionSet:
 		cmd = "set"
 	case eventVolumeOptionReset:
@@ -80,9 +80,6 @@ func (h *hooks) Handle(e *api.Event) {
 
 func (h *hooks) Events() []string {
 	return []string{
-		eventVolumeCreated,
-		eventVolumeStarted,
-		eventVolumeStopped,
 		eventVolumeDeleted,
 		eventVolumeOptionSet,
 		eventVolumeOptionReset,
@@ -4,7 +4,9 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/gluster/glusterd2/glusterd2/peer"
 	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
 
 	"github.com/coreos/etcd/clientv3"
 	log "github.com/sirupsen/logrus"
@@ -53,11 +55,18 @@ func (l *livenessWatcher) Watch() {
 					continue
 				}
 
+				// Peers under maintenance are expected to go down
+				// and come up, which is not worth alerting about
+				if p, err := peer.GetPeer(peerID); err == nil && p.InMaintenance() {
+					log.WithField("id", peerID).Debug("peer under maintenance, not sending liveness event")
+					continue
+				}
+
 				data := map[string]string{
 					"peer.id": peerID,
 				}
 
-				Broadcast(New(evName, data, false))
+				Broadcast(New(evName, data, false, api.EventObject{Kind: api.EventObjectPeer, ID: peerID}))
 			}
 		case <-l.stopCh:
 			return
@@ -75,6 +84,24 @@ func (l *livenessWatcher) Stop() error {
 	return nil
 }
 
+func init() {
+	RegisterTypes(
+		api.EventType{
+			Name:        eventPeerConnectedStore,
+			Description: "A peer connected to the store, seen by every peer",
+			Objects:     []string{api.EventObjectPeer},
+			Data:        []string{"peer.id"},
+		},
+		api.EventType{
+			Name:        eventPeerDisconnectedStore,
+			Severity:    api.EventSeverityError,
+			Description: "A peer disconnected from the store, it is down or can't reach the store; seen by every peer",
+			Objects:     []string{api.EventObjectPeer},
+			Data:        []string{"peer.id"},
+		},
+	)
+}
+
 func startLivenessWatcher() {
 	lWatcher = &livenessWatcher{
 		stopCh: make(chan struct{}),
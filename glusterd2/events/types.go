@@ -0,0 +1,76 @@
+package events
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The event type registry keeps the types of the events created by
+// glusterd2, so that events get the severity of their type and that the
+// types can be listed by consumers
+
+var typeRegistry = struct {
+	sync.RWMutex
+	types map[string]*api.EventType
+}{
+	types: make(map[string]*api.EventType),
+}
+
+// RegisterTypes registers the types of events a package creates. Packages
+// register their event types in init.
+func RegisterTypes(types ...api.EventType) {
+	typeRegistry.Lock()
+	defer typeRegistry.Unlock()
+
+	for _, t := range types {
+		t := t
+		t.Name = strings.ToLower(t.Name)
+		if t.Severity == "" {
+			t.Severity = api.EventSeverityInfo
+		}
+		if _, ok := typeRegistry.types[t.Name]; ok {
+			log.WithField("event", t.Name).Warning("event type with provided name exists in registry and will be overwritten")
+		}
+		typeRegistry.types[t.Name] = &t
+	}
+}
+
+// GetType returns the registered type of the events with the given name
+func GetType(name string) (api.EventType, bool) {
+	typeRegistry.RLock()
+	defer typeRegistry.RUnlock()
+
+	t, ok := typeRegistry.types[strings.ToLower(name)]
+	if !ok {
+		return api.EventType{}, false
+	}
+	return *t, true
+}
+
+// Types returns the registered event types, sorted by name
+func Types() []api.EventType {
+	typeRegistry.RLock()
+	defer typeRegistry.RUnlock()
+
+	types := make([]api.EventType, 0, len(typeRegistry.types))
+	for _, t := range typeRegistry.types {
+		types = append(types, *t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+// severityOf returns the severity of the events with the given name, info
+// if their type is not registered
+func severityOf(name string) api.EventSeverity {
+	if t, ok := GetType(name); ok {
+		return t.Severity
+	}
+	log.WithField("event", name).Debug("event type is not registered")
+	return api.EventSeverityInfo
+}
@@ -0,0 +1,33 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterTypes(t *testing.T) {
+	RegisterTypes(
+		api.EventType{Name: "Test.Registered", Severity: api.EventSeverityError},
+		api.EventType{Name: "test.default"},
+	)
+
+	typ, ok := GetType("test.registered")
+	assert.True(t, ok)
+	assert.Equal(t, "test.registered", typ.Name)
+	assert.Equal(t, api.EventSeverityError, severityOf("test.registered"))
+	assert.Equal(t, api.EventSeverityInfo, severityOf("test.default"))
+	assert.Equal(t, api.EventSeverityInfo, severityOf("test.unregistered"))
+
+	types := Types()
+	for i := 1; i < len(types); i++ {
+		assert.True(t, types[i-1].Name < types[i].Name)
+	}
+
+	e := New("TEST.REGISTERED", nil, false, api.EventObject{Kind: api.EventObjectVolume, Name: "gv0"})
+	assert.Equal(t, api.EventSchemaVersion, e.Version)
+	assert.Equal(t, api.EventSeverityError, e.Severity)
+	assert.Len(t, e.Objects, 1)
+}
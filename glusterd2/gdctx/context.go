@@ -2,6 +2,7 @@ package gdctx
 
 import (
 	"context"
+	"sync"
 
 	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
@@ -12,6 +13,11 @@ type ctxKeyType int
 const (
 	reqIDKey ctxKeyType = iota
 	reqLoggerKey
+	reqUserKey
+	reqRoleKey
+	reqNamespacesKey
+	reqTxnIDsKey
+	reqRequesterKey
 )
 
 // WithReqID returns a new context with provided request id set as a value in the context.
@@ -41,3 +47,126 @@ func GetReqLogger(ctx context.Context) log.FieldLogger {
 	}
 	return reqLogger
 }
+
+// WithReqUser returns a new context with the authenticated requester set as a value in the context. It is also recorded in the requester recorder of the context, if it has one.
+func WithReqUser(ctx context.Context, user string) context.Context {
+	if rec, ok := ctx.Value(reqRequesterKey).(*requesterRecorder); ok {
+		rec.Lock()
+		rec.user = user
+		rec.Unlock()
+	}
+	return context.WithValue(ctx, reqUserKey, user)
+}
+
+// GetReqUser returns the authenticated requester stored in the context provided.
+func GetReqUser(ctx context.Context) string {
+	user, ok := ctx.Value(reqUserKey).(string)
+	if !ok {
+		return ""
+	}
+	return user
+}
+
+// WithReqRole returns a new context with the role of the authenticated requester set as a value in the context. It is also recorded in the requester recorder of the context, if it has one.
+func WithReqRole(ctx context.Context, role string) context.Context {
+	if rec, ok := ctx.Value(reqRequesterKey).(*requesterRecorder); ok {
+		rec.Lock()
+		rec.role = role
+		rec.Unlock()
+	}
+	return context.WithValue(ctx, reqRoleKey, role)
+}
+
+// GetReqRole returns the role of the authenticated requester stored in the context provided.
+func GetReqRole(ctx context.Context) string {
+	role, ok := ctx.Value(reqRoleKey).(string)
+	if !ok {
+		return ""
+	}
+	return role
+}
+
+// WithReqNamespaces returns a new context with the namespaces the authenticated requester can access set as a value in the context.
+func WithReqNamespaces(ctx context.Context, namespaces []string) context.Context {
+	return context.WithValue(ctx, reqNamespacesKey, namespaces)
+}
+
+// GetReqNamespaces returns the namespaces the authenticated requester can access, stored in the context provided. It returns nil if the requester can access all the namespaces.
+func GetReqNamespaces(ctx context.Context) []string {
+	namespaces, ok := ctx.Value(reqNamespacesKey).([]string)
+	if !ok || len(namespaces) == 0 {
+		return nil
+	}
+	return namespaces
+}
+
+// ReqNamespaceAllowed returns true if the authenticated requester stored in the context provided can access the namespace.
+func ReqNamespaceAllowed(ctx context.Context, namespace string) bool {
+	namespaces := GetReqNamespaces(ctx)
+	if namespaces == nil {
+		return true
+	}
+	for _, ns := range namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// txnIDRecorder collects the IDs of the transactions started for a request
+type txnIDRecorder struct {
+	sync.Mutex
+	ids []string
+}
+
+// WithTxnIDRecorder returns a new context in which the IDs of the transactions started with it are recorded.
+func WithTxnIDRecorder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, reqTxnIDsKey, &txnIDRecorder{})
+}
+
+// RecordTxnID records the ID of a transaction started with the context provided, if it has a recorder.
+func RecordTxnID(ctx context.Context, txnID uuid.UUID) {
+	rec, ok := ctx.Value(reqTxnIDsKey).(*txnIDRecorder)
+	if !ok {
+		return
+	}
+	rec.Lock()
+	rec.ids = append(rec.ids, txnID.String())
+	rec.Unlock()
+}
+
+// GetTxnIDs returns the IDs of the transactions recorded in the context provided.
+func GetTxnIDs(ctx context.Context) []string {
+	rec, ok := ctx.Value(reqTxnIDsKey).(*txnIDRecorder)
+	if !ok {
+		return nil
+	}
+	rec.Lock()
+	defer rec.Unlock()
+	return append([]string(nil), rec.ids...)
+}
+
+// requesterRecorder keeps the authenticated requester of a request and its
+// role, for the middlewares which run before the request is authenticated
+type requesterRecorder struct {
+	sync.Mutex
+	user string
+	role string
+}
+
+// WithRequesterRecorder returns a new context in which the authenticated requester and its role are recorded when set in the contexts derived from it.
+func WithRequesterRecorder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, reqRequesterKey, &requesterRecorder{})
+}
+
+// GetRecordedRequester returns the authenticated requester and its role recorded in the context provided.
+func GetRecordedRequester(ctx context.Context) (string, string) {
+	rec, ok := ctx.Value(reqRequesterKey).(*requesterRecorder)
+	if !ok {
+		return "", ""
+	}
+	rec.Lock()
+	defer rec.Unlock()
+	return rec.user, rec.role
+}
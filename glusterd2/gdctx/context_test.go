@@ -30,3 +30,28 @@ func TestWithReqLogger(t *testing.T) {
 	assert.NotNil(t, newlog)
 
 }
+
+func TestTxnIDRecorder(t *testing.T) {
+	txnID := uuid.NewRandom()
+
+	// Recording without a recorder is a no-op
+	RecordTxnID(context.Background(), txnID)
+	assert.Nil(t, GetTxnIDs(context.Background()))
+
+	ctx := WithTxnIDRecorder(context.Background())
+	RecordTxnID(WithReqID(ctx, uuid.NewRandom()), txnID)
+	assert.Equal(t, []string{txnID.String()}, GetTxnIDs(ctx))
+}
+
+func TestReqNamespaces(t *testing.T) {
+	// Requests without namespaces can access all of them
+	ctx := context.Background()
+	assert.Nil(t, GetReqNamespaces(ctx))
+	assert.True(t, ReqNamespaceAllowed(ctx, "team1"))
+	assert.True(t, ReqNamespaceAllowed(WithReqNamespaces(ctx, []string{}), "team1"))
+
+	ctx = WithReqNamespaces(ctx, []string{"team1", "team2"})
+	assert.Equal(t, []string{"team1", "team2"}, GetReqNamespaces(ctx))
+	assert.True(t, ReqNamespaceAllowed(ctx, "team2"))
+	assert.False(t, ReqNamespaceAllowed(ctx, "default"))
+}
@@ -45,6 +45,7 @@ func uuidFilePath() string {
 // from the following sources in order of preference
 // - environment variables (GD2_CLUSTER_ID and GD2_PEER_ID)
 // - the uuid config file ($LOCALSTATEDIR/uuid.toml)
+// - the cluster-id option of glusterd2, for the cluster id
 // - randomly generated uuid
 type uuidConfig struct {
 	*config.Viper
@@ -64,9 +65,15 @@ func newUUIDConfig() *uuidConfig {
 	uc.SetConfigFile(uuidFilePath())
 	uc.SetConfigType("toml")
 
-	// Finally set random uuids as the default
+	// Finally set random uuids as the default. A peer which has not joined
+	// a cluster yet uses the configured cluster id, so that the peers of a
+	// cluster sharing an etcd cluster with others use the same namespace.
 	uc.SetDefault(peerIDKey, uuid.NewRandom().String())
-	uc.SetDefault(clusterIDKey, uuid.NewRandom().String())
+	if clusterID := config.GetString(clusterIDKey); clusterID != "" {
+		uc.SetDefault(clusterIDKey, clusterID)
+	} else {
+		uc.SetDefault(clusterIDKey, uuid.NewRandom().String())
+	}
 
 	return uc
 }
@@ -0,0 +1,161 @@
+// Package hooks runs the hook scripts provided by admins before and after
+// volume operations, like the hook scripts of glusterd1. Integrations like
+// Samba and CTDB use them to set up and tear down shares of a volume.
+//
+// Hook scripts of an operation are the executables with a name starting with
+// "S" in <hooksdir>/<op>/pre and <hooksdir>/<op>/post, and are run in the
+// lexical order of their names. Every script is invoked with glusterd1 style
+// arguments:
+//
+//	--volname=<volume name> --gd-workdir=<localstatedir>
+//
+// The volume info is passed in the environment (GD2_VOLUME_NAME,
+// GD2_VOLUME_ID, GD2_HOOK_OP, GD2_HOOK_PHASE and GD2_PEER_ID) and as JSON on
+// the standard input of the script.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+// Phase is the point of an operation at which hook scripts are run
+type Phase string
+
+const (
+	// Pre hook scripts are run before the operation is done. A failing
+	// pre hook script fails the operation.
+	Pre Phase = "pre"
+	// Post hook scripts are run after the operation is done. Failures of
+	// post hook scripts are only logged.
+	Post Phase = "post"
+)
+
+// Operations which run hook scripts as part of their transaction
+const (
+	OpCreate = "create"
+	OpStart  = "start"
+	OpStop   = "stop"
+)
+
+var ops = []string{OpCreate, OpStart, OpStop}
+
+// scriptTimeout is the time a hook script is given to finish
+const scriptTimeout = 2 * time.Minute
+
+// Dir returns the directory holding the hook scripts of op for phase
+func Dir(op string, phase Phase) string {
+	return path.Join(config.GetString("hooksdir"), op, string(phase))
+}
+
+// Scripts returns the hook scripts of op for phase in the order in which they
+// are run
+func Scripts(op string, phase Phase) ([]string, error) {
+	dir := Dir(op, phase)
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var scripts []string
+	for _, f := range files {
+		// Only regular executables, symbolic links are not followed
+		if strings.HasPrefix(f.Name(), "S") && f.Mode().IsRegular() && f.Mode().Perm()&0111 != 0 {
+			scripts = append(scripts, path.Join(dir, f.Name()))
+		}
+	}
+	sort.Strings(scripts)
+
+	return scripts, nil
+}
+
+// Run runs the hook scripts of op for phase on this peer, one after the
+// other. For the pre phase, it stops at and returns the first failure. For
+// the post phase, all the scripts are run and failures are only logged.
+func Run(ctx context.Context, op string, phase Phase, volinfo *volume.Volinfo, logger log.FieldLogger) error {
+	scripts, err := Scripts(op, phase)
+	if err != nil {
+		logger.WithError(err).WithField("hooks-dir", Dir(op, phase)).Warn("failed to get list of hook scripts")
+		if phase == Pre {
+			return err
+		}
+		return nil
+	}
+	if len(scripts) == 0 {
+		return nil
+	}
+
+	input, err := json.Marshal(volume.CreateVolumeInfoResp(volinfo))
+	if err != nil {
+		return err
+	}
+
+	args := []string{
+		"--volname=" + volinfo.Name,
+		"--gd-workdir=" + config.GetString("localstatedir"),
+	}
+	env := append(os.Environ(),
+		"GD2_VOLUME_NAME="+volinfo.Name,
+		"GD2_VOLUME_ID="+volinfo.ID.String(),
+		"GD2_HOOK_OP="+op,
+		"GD2_HOOK_PHASE="+string(phase),
+		"GD2_PEER_ID="+gdctx.MyUUID.String(),
+	)
+
+	for _, script := range scripts {
+		l := logger.WithFields(log.Fields{
+			"script": script,
+			"volume": volinfo.Name,
+		})
+
+		if err := runScript(ctx, script, args, env, input); err != nil {
+			if phase == Pre {
+				l.WithError(err).Error("pre hook script failed")
+				return fmt.Errorf("pre hook script %s failed: %s", path.Base(script), err)
+			}
+			l.WithError(err).Warn("post hook script failed")
+			continue
+		}
+		l.Debug("hook script succeeded")
+	}
+
+	return nil
+}
+
+func runScript(ctx context.Context, script string, args, env []string, input []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, scriptTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, script, args...)
+	cmd.Env = env
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", scriptTimeout)
+	}
+	if err != nil && stderr.Len() > 0 {
+		return fmt.Errorf("%s: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return err
+}
@@ -0,0 +1,61 @@
+package hooks
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func writeScript(t *testing.T, dir, name, body string, mode os.FileMode) {
+	require.NoError(t, ioutil.WriteFile(path.Join(dir, name), []byte("#!/bin/sh\n"+body+"\n"), mode))
+}
+
+func TestHooks(t *testing.T) {
+	d, err := ioutil.TempDir("", "TestHooks")
+	require.NoError(t, err)
+	defer os.RemoveAll(d)
+	config.Set("hooksdir", d)
+
+	pre := Dir(OpStart, Pre)
+	require.NoError(t, os.MkdirAll(pre, os.ModePerm))
+
+	out := path.Join(d, "out")
+	writeScript(t, pre, "S20second", "echo $GD2_HOOK_PHASE $1 >> "+out, 0755)
+	writeScript(t, pre, "S10first", "cat >/dev/null; echo $GD2_VOLUME_NAME >> "+out, 0755)
+	writeScript(t, pre, "K10skipped", "exit 1", 0755)
+	writeScript(t, pre, "S30notexec", "exit 1", 0644)
+
+	scripts, err := Scripts(OpStart, Pre)
+	require.NoError(t, err)
+	require.Equal(t, []string{path.Join(pre, "S10first"), path.Join(pre, "S20second")}, scripts)
+
+	// Missing hooks directory has no scripts
+	scripts, err = Scripts(OpStop, Post)
+	require.NoError(t, err)
+	require.Empty(t, scripts)
+
+	volinfo := &volume.Volinfo{ID: uuid.NewRandom(), Name: "vol1", Subvols: []volume.Subvol{{}}}
+	require.NoError(t, Run(context.Background(), OpStart, Pre, volinfo, log.StandardLogger()))
+
+	b, err := ioutil.ReadFile(out)
+	require.NoError(t, err)
+	require.Equal(t, "vol1\npre --volname=vol1\n", string(b))
+
+	// Failing pre hook fails, failing post hook is only logged
+	writeScript(t, pre, "S40fail", "exit 1", 0755)
+	require.Error(t, Run(context.Background(), OpStart, Pre, volinfo, log.StandardLogger()))
+
+	post := Dir(OpStart, Post)
+	require.NoError(t, os.MkdirAll(post, os.ModePerm))
+	writeScript(t, post, "S10fail", "exit 1", 0755)
+	require.NoError(t, Run(context.Background(), OpStart, Post, volinfo, log.StandardLogger()))
+}
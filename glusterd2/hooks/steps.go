@@ -0,0 +1,43 @@
+package hooks
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/pborman/uuid"
+)
+
+func stepFuncName(op string, phase Phase) string {
+	return "hooks." + op + "." + string(phase)
+}
+
+func stepFunc(op string, phase Phase) transaction.StepFunc {
+	return func(c transaction.TxnCtx) error {
+		var volinfo volume.Volinfo
+		if err := c.Get("volinfo", &volinfo); err != nil {
+			return err
+		}
+		return Run(c.Context(), op, phase, &volinfo, c.Logger())
+	}
+}
+
+// Step returns a transaction step which runs the hook scripts of op for
+// phase on the given nodes, with the "volinfo" in the transaction context.
+// The step waits for all the earlier steps to be done on all the nodes, so
+// post hook scripts only run once the operation is complete.
+func Step(op string, phase Phase, nodes []uuid.UUID) *transaction.Step {
+	return &transaction.Step{
+		DoFunc: stepFuncName(op, phase),
+		Nodes:  nodes,
+		Sync:   true,
+	}
+}
+
+// RegisterStepFuncs registers the step funcs running hook scripts
+func RegisterStepFuncs() {
+	for _, op := range ops {
+		for _, phase := range []Phase{Pre, Post} {
+			transaction.RegisterStepFunc(stepFunc(op, phase), stepFuncName(op, phase))
+		}
+	}
+}
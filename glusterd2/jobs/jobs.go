@@ -0,0 +1,460 @@
+// Package jobs runs long running operations in the background and keeps
+// track of them in the store, so that their progress can be followed and
+// they can be cancelled from any peer.
+//
+// A job is run by the peer which submitted it. At most maxRunningJobs jobs
+// are run at a time by a peer, the rest wait in the queued state. Jobs are
+// cancelled when glusterd2 stops, and a job whose peer goes down is marked as
+// failed by the cleanup leader.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	jobPrefix      = "jobs/"
+	jobOwnerPrefix = "job-owners/"
+
+	maxRunningJobs = 4
+	// maxJobLogs is the number of log entries kept for a job, older
+	// entries are dropped
+	maxJobLogs = 100
+	// jobMaxAge is the time for which jobs are kept after they end
+	jobMaxAge = 24 * time.Hour
+)
+
+// Job is a long running operation run in the background by a peer
+type Job struct {
+	ID        uuid.UUID         `json:"id"`
+	Type      string            `json:"type"`
+	Volume    string            `json:"volume,omitempty"`
+	State     api.JobState      `json:"state"`
+	Error     string            `json:"error,omitempty"`
+	Initiator uuid.UUID         `json:"initiator"`
+	ReqID     uuid.UUID         `json:"req-id"`
+	Cancelled bool              `json:"cancelled,omitempty"`
+	CreatedAt time.Time         `json:"created-at"`
+	StartedAt time.Time         `json:"started-at,omitempty"`
+	EndedAt   time.Time         `json:"ended-at,omitempty"`
+	Logs      []api.JobLogEntry `json:"logs,omitempty"`
+	Result    json.RawMessage   `json:"result,omitempty"`
+}
+
+// Ended returns true if the job is done or has failed
+func (j *Job) Ended() bool {
+	return j.State == api.JobDone || j.State == api.JobFailed
+}
+
+// Func is the operation run by a job. ctx is cancelled when the job is
+// cancelled, and the operation is expected to return soon after.
+type Func func(ctx context.Context, h *Handle) error
+
+// Handle is given to a running job to report its progress
+type Handle struct {
+	id     uuid.UUID
+	logger log.FieldLogger
+}
+
+// ID returns the ID of the job
+func (h *Handle) ID() uuid.UUID {
+	return h.id
+}
+
+// Logf adds a progress message to the log of the job
+func (h *Handle) Logf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	h.logger.Info(msg)
+
+	_, err := updateJobF(h.id, func(j *Job) bool {
+		j.Logs = append(j.Logs, api.JobLogEntry{Time: time.Now().UTC(), Message: msg})
+		if len(j.Logs) > maxJobLogs {
+			j.Logs = j.Logs[len(j.Logs)-maxJobLogs:]
+		}
+		return true
+	})
+	if err != nil {
+		h.logger.WithError(err).Warn("failed to add to job log")
+	}
+}
+
+// SetResult sets the response of the operation run by the job, which is
+// returned along with the job once it is done
+func (h *Handle) SetResult(v interface{}) error {
+	result, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = updateJobF(h.id, func(j *Job) bool {
+		j.Result = result
+		return true
+	})
+	return err
+}
+
+var (
+	// slots limits the number of jobs run at a time by this peer
+	slots = make(chan struct{}, maxRunningJobs)
+
+	// rootCtx is the context all the jobs run by this peer are derived
+	// from, it is cancelled by Stop
+	rootCtx, stopJobs = context.WithCancel(context.Background())
+
+	// running has the channels closed when the jobs run by this peer end
+	running = struct {
+		sync.Mutex
+		done map[string]chan struct{}
+	}{
+		done: make(map[string]chan struct{}),
+	}
+)
+
+// The following can be replaced in tests
+var (
+	updateJobF      = updateJob
+	watchCancelF    = watchCancel
+	removeJobOwnerF = removeJobOwner
+)
+
+// Stop cancels the jobs run by this peer
+func Stop() {
+	stopJobs()
+}
+
+// Submit adds a job which runs fn in the background on this peer. The
+// request ID, logger and user in ctx are passed on to the job, but the job
+// is not cancelled with ctx: it is cancelled by Cancel or when glusterd2
+// stops.
+func Submit(ctx context.Context, jobType, volname string, fn Func) (*Job, error) {
+	j := &Job{
+		ID:        uuid.NewRandom(),
+		Type:      jobType,
+		Volume:    volname,
+		State:     api.JobQueued,
+		Initiator: gdctx.MyUUID,
+		ReqID:     gdctx.GetReqID(ctx),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	v, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = store.Txn(context.TODO()).Then(
+		clientv3.OpPut(jobPrefix+j.ID.String(), string(v)),
+		clientv3.OpPut(jobOwnerPrefix+j.ID.String(), gdctx.MyUUID.String(), clientv3.WithLease(store.Store.Session.Lease())),
+	).Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	logger := log.WithFields(log.Fields{
+		"reqid":  j.ReqID.String(),
+		"job":    j.ID.String(),
+		"type":   j.Type,
+		"volume": j.Volume,
+	})
+	jobCtx := gdctx.WithReqID(rootCtx, j.ReqID)
+	jobCtx = gdctx.WithReqLogger(jobCtx, logger)
+	jobCtx = gdctx.WithReqUser(jobCtx, gdctx.GetReqUser(ctx))
+
+	done := make(chan struct{})
+	running.Lock()
+	running.done[j.ID.String()] = done
+	running.Unlock()
+
+	go run(jobCtx, j.ID, fn, &Handle{j.ID, logger}, done)
+
+	return j, nil
+}
+
+func run(ctx context.Context, id uuid.UUID, fn Func, h *Handle, done chan struct{}) {
+	defer func() {
+		running.Lock()
+		delete(running.done, id.String())
+		running.Unlock()
+		close(done)
+
+		if err := removeJobOwnerF(id); err != nil {
+			h.logger.WithError(err).Warn("failed to remove job owner from store")
+		}
+	}()
+
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		// glusterd2 is stopping, the job is failed by the cleanup leader
+		return
+	}
+	defer func() { <-slots }()
+
+	ok, err := updateJobF(id, func(j *Job) bool {
+		if j.State != api.JobQueued {
+			return false
+		}
+		j.State = api.JobRunning
+		j.StartedAt = time.Now().UTC()
+		return true
+	})
+	if err != nil || !ok {
+		// The job was cancelled while queued
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go watchCancelF(ctx, id, cancel)
+
+	h.logger.Info("job started")
+	err = fn(ctx, h)
+	if err != nil && ctx.Err() == context.Canceled {
+		err = gderrors.ErrJobCancelled
+	}
+
+	if err != nil {
+		h.logger.WithError(err).Error("job failed")
+	} else {
+		h.logger.Info("job done")
+	}
+
+	if _, uerr := updateJobF(id, func(j *Job) bool {
+		j.State = api.JobDone
+		if err != nil {
+			j.State = api.JobFailed
+			j.Error = err.Error()
+		}
+		j.EndedAt = time.Now().UTC()
+		return true
+	}); uerr != nil {
+		h.logger.WithError(uerr).Error("failed to record end of job")
+	}
+}
+
+// watchCancel cancels the context of a running job once a cancel request is
+// recorded for it
+func watchCancel(ctx context.Context, id uuid.UUID, cancel context.CancelFunc) {
+	key := jobPrefix + id.String()
+	resp, err := store.Get(ctx, key)
+	if err != nil || resp.Count != 1 {
+		return
+	}
+	var j Job
+	if err := json.Unmarshal(resp.Kvs[0].Value, &j); err == nil && j.Cancelled {
+		cancel()
+		return
+	}
+
+	for resp := range store.Store.Watch(ctx, key, clientv3.WithRev(resp.Header.Revision+1)) {
+		for _, ev := range resp.Events {
+			var j Job
+			if ev.Kv == nil || json.Unmarshal(ev.Kv.Value, &j) != nil {
+				continue
+			}
+			if j.Cancelled {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// Wait waits for a job run by this peer to end and returns the ended job.
+// If ctx is done before, the job is left running.
+func Wait(ctx context.Context, id uuid.UUID) (*Job, error) {
+	running.Lock()
+	done, ok := running.done[id.String()]
+	running.Unlock()
+
+	if ok {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return GetJob(id)
+}
+
+// Cancel requests a job to be cancelled. A queued job is failed right away,
+// while a running job is failed once its operation returns.
+func Cancel(id uuid.UUID) error {
+	var ended bool
+	ok, err := updateJobF(id, func(j *Job) bool {
+		if ended = j.Ended(); ended {
+			return false
+		}
+		j.Cancelled = true
+		if j.State == api.JobQueued {
+			j.State = api.JobFailed
+			j.Error = gderrors.ErrJobCancelled.Error()
+			j.EndedAt = time.Now().UTC()
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if ended || !ok {
+		return gderrors.ErrJobNotRunning
+	}
+	return nil
+}
+
+// GetJob returns the job with the given ID
+func GetJob(id uuid.UUID) (*Job, error) {
+	resp, err := store.Get(context.TODO(), jobPrefix+id.String())
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, gderrors.ErrJobNotFound
+	}
+
+	var j Job
+	if err := json.Unmarshal(resp.Kvs[0].Value, &j); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// GetJobs returns all the jobs which are queued, running or have ended
+// recently
+func GetJobs() ([]*Job, error) {
+	resp, err := store.Get(context.TODO(), jobPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*Job, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var j Job
+		if err := json.Unmarshal(kv.Value, &j); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal job")
+			continue
+		}
+		jobs = append(jobs, &j)
+	}
+	return jobs, nil
+}
+
+// removeJobOwner removes the key showing the job as run by this peer
+func removeJobOwner(id uuid.UUID) error {
+	_, err := store.Delete(context.TODO(), jobOwnerPrefix+id.String())
+	return err
+}
+
+// updateJob applies update to the job with the given ID and saves it, unless
+// update returns false. Conflicting updates of the job are retried. It
+// returns whether the job was saved.
+func updateJob(id uuid.UUID, update func(j *Job) bool) (bool, error) {
+	key := jobPrefix + id.String()
+	for {
+		resp, err := store.Get(context.TODO(), key)
+		if err != nil {
+			return false, err
+		}
+		if resp.Count != 1 {
+			return false, gderrors.ErrJobNotFound
+		}
+
+		var j Job
+		if err := json.Unmarshal(resp.Kvs[0].Value, &j); err != nil {
+			return false, err
+		}
+		if !update(&j) {
+			return false, nil
+		}
+
+		v, err := json.Marshal(&j)
+		if err != nil {
+			return false, err
+		}
+
+		tresp, err := store.Txn(context.TODO()).
+			If(clientv3.Compare(clientv3.ModRevision(key), "=", resp.Kvs[0].ModRevision)).
+			Then(clientv3.OpPut(key, string(v))).
+			Commit()
+		if err != nil {
+			return false, err
+		}
+		if tresp.Succeeded {
+			return true, nil
+		}
+	}
+}
+
+// CleanupJobs fails the jobs whose peer went down before they ended and
+// removes the jobs which ended more than jobMaxAge ago
+func CleanupJobs() {
+	jobs, err := GetJobs()
+	if err != nil {
+		log.WithError(err).Error("failed to get jobs")
+		return
+	}
+
+	for _, j := range jobs {
+		logger := log.WithField("job", j.ID.String())
+
+		if j.Ended() {
+			if time.Since(j.EndedAt) > jobMaxAge {
+				if _, err := store.Delete(context.TODO(), jobPrefix+j.ID.String()); err != nil {
+					logger.WithError(err).Error("failed to remove job from store")
+				}
+			}
+			continue
+		}
+
+		owner, err := store.Get(context.TODO(), jobOwnerPrefix+j.ID.String())
+		if err != nil || owner.Count != 0 {
+			continue
+		}
+
+		logger.Warn("peer running the job went down, marking job as failed")
+		if _, err := updateJob(j.ID, func(j *Job) bool {
+			if j.Ended() {
+				return false
+			}
+			j.State = api.JobFailed
+			j.Error = gderrors.ErrJobOrphaned.Error()
+			j.EndedAt = time.Now().UTC()
+			return true
+		}); err != nil {
+			logger.WithError(err).Error("failed to mark job as failed")
+		}
+	}
+}
+
+// CreateJobInfoResp returns the API representation of a job
+func CreateJobInfoResp(j *Job) *api.JobInfo {
+	return &api.JobInfo{
+		ID:        j.ID,
+		Type:      j.Type,
+		Volume:    j.Volume,
+		State:     j.State,
+		Error:     j.Error,
+		Initiator: j.Initiator,
+		ReqID:     j.ReqID,
+		Cancelled: j.Cancelled,
+		CreatedAt: j.CreatedAt,
+		StartedAt: j.StartedAt,
+		EndedAt:   j.EndedAt,
+		Logs:      j.Logs,
+		Result:    j.Result,
+	}
+}
@@ -0,0 +1,185 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/testutils"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeJobs keeps jobs in memory in place of the store
+type fakeJobs struct {
+	sync.Mutex
+	jobs map[string]*Job
+}
+
+func (f *fakeJobs) add(state api.JobState) uuid.UUID {
+	f.Lock()
+	defer f.Unlock()
+	j := &Job{ID: uuid.NewRandom(), State: state}
+	f.jobs[j.ID.String()] = j
+	return j.ID
+}
+
+func (f *fakeJobs) get(id uuid.UUID) Job {
+	f.Lock()
+	defer f.Unlock()
+	return *f.jobs[id.String()]
+}
+
+func (f *fakeJobs) update(id uuid.UUID, update func(j *Job) bool) (bool, error) {
+	f.Lock()
+	defer f.Unlock()
+	j, ok := f.jobs[id.String()]
+	if !ok {
+		return false, gderrors.ErrJobNotFound
+	}
+	c := *j
+	if !update(&c) {
+		return false, nil
+	}
+	f.jobs[id.String()] = &c
+	return true, nil
+}
+
+func patchJobStore() (*fakeJobs, func()) {
+	f := &fakeJobs{jobs: make(map[string]*Job)}
+	r1 := testutils.Patch(&updateJobF, f.update)
+	r2 := testutils.Patch(&removeJobOwnerF, func(uuid.UUID) error { return nil })
+	r3 := testutils.Patch(&watchCancelF, func(ctx context.Context, id uuid.UUID, cancel context.CancelFunc) {})
+	return f, func() {
+		r1.Restore()
+		r2.Restore()
+		r3.Restore()
+	}
+}
+
+func runJob(id uuid.UUID, fn Func) {
+	h := &Handle{id: id, logger: log.WithField("job", id.String())}
+	run(context.Background(), id, fn, h, make(chan struct{}))
+}
+
+func TestRunJob(t *testing.T) {
+	f, restore := patchJobStore()
+	defer restore()
+
+	jobErr := errors.New("brick not found")
+	tests := []struct {
+		name  string
+		err   error
+		state api.JobState
+		msg   string
+	}{
+		{"job done", nil, api.JobDone, ""},
+		{"job failed", jobErr, api.JobFailed, jobErr.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := f.add(api.JobQueued)
+
+			var state api.JobState
+			runJob(id, func(ctx context.Context, h *Handle) error {
+				state = f.get(id).State
+				return tt.err
+			})
+
+			j := f.get(id)
+			assert.Equal(t, api.JobRunning, state)
+			assert.Equal(t, tt.state, j.State)
+			assert.Equal(t, tt.msg, j.Error)
+			assert.False(t, j.StartedAt.IsZero())
+			assert.False(t, j.EndedAt.IsZero())
+		})
+	}
+}
+
+func TestRunJobCancelled(t *testing.T) {
+	f, restore := patchJobStore()
+	defer restore()
+
+	// The job is cancelled once it has started
+	started := make(chan struct{})
+	defer testutils.Patch(&watchCancelF, func(ctx context.Context, id uuid.UUID, cancel context.CancelFunc) {
+		<-started
+		cancel()
+	}).Restore()
+
+	id := f.add(api.JobQueued)
+	done := make(chan struct{})
+	go func() {
+		runJob(id, func(ctx context.Context, h *Handle) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancelled job did not end")
+	}
+
+	j := f.get(id)
+	assert.Equal(t, api.JobFailed, j.State)
+	assert.Equal(t, gderrors.ErrJobCancelled.Error(), j.Error)
+}
+
+func TestRunJobCancelledWhileQueued(t *testing.T) {
+	f, restore := patchJobStore()
+	defer restore()
+
+	id := f.add(api.JobQueued)
+	assert.Nil(t, Cancel(id))
+
+	called := false
+	runJob(id, func(ctx context.Context, h *Handle) error {
+		called = true
+		return nil
+	})
+
+	j := f.get(id)
+	assert.False(t, called)
+	assert.Equal(t, api.JobFailed, j.State)
+	assert.Equal(t, gderrors.ErrJobCancelled.Error(), j.Error)
+}
+
+func TestCancel(t *testing.T) {
+	f, restore := patchJobStore()
+	defer restore()
+
+	tests := []struct {
+		state    api.JobState
+		err      error
+		newState api.JobState
+	}{
+		{api.JobQueued, nil, api.JobFailed},
+		{api.JobRunning, nil, api.JobRunning},
+		{api.JobDone, gderrors.ErrJobNotRunning, api.JobDone},
+		{api.JobFailed, gderrors.ErrJobNotRunning, api.JobFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.state), func(t *testing.T) {
+			id := f.add(tt.state)
+			assert.Equal(t, tt.err, Cancel(id))
+
+			j := f.get(id)
+			assert.Equal(t, tt.newState, j.State)
+			assert.Equal(t, tt.err == nil, j.Cancelled)
+		})
+	}
+
+	assert.Equal(t, gderrors.ErrJobNotFound, Cancel(uuid.NewRandom()))
+}
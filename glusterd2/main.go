@@ -1,24 +1,32 @@
 package main
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"path"
 	"strings"
 	"time"
 
+	"github.com/gluster/glusterd2/glusterd2/audit"
 	"github.com/gluster/glusterd2/glusterd2/brickmux"
+	"github.com/gluster/glusterd2/glusterd2/certs"
 	"github.com/gluster/glusterd2/glusterd2/commands/volumes"
 	"github.com/gluster/glusterd2/glusterd2/conf"
 	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/devicehealth"
 	"github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/jobs"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/opversion"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	"github.com/gluster/glusterd2/glusterd2/pmap"
 	"github.com/gluster/glusterd2/glusterd2/servers"
 	"github.com/gluster/glusterd2/glusterd2/store"
 	"github.com/gluster/glusterd2/glusterd2/transactionv2"
 	"github.com/gluster/glusterd2/glusterd2/transactionv2/cleanuphandler"
+	"github.com/gluster/glusterd2/glusterd2/usage"
 	gdutils "github.com/gluster/glusterd2/glusterd2/utils"
 	"github.com/gluster/glusterd2/glusterd2/volgen"
 	"github.com/gluster/glusterd2/glusterd2/xlator"
@@ -77,16 +85,55 @@ func main() {
 		log.WithError(err).Fatal("Failed to initialize UUID")
 	}
 
+	// Load the SSL/TLS certificates before any server or peer connection
+	// uses them
+	if err := certs.Load(); err != nil {
+		log.WithError(err).Fatal("Failed to load SSL/TLS certificates")
+	}
+
+	if err := audit.Init(); err != nil {
+		log.WithError(err).Fatal("Failed to initialize audit log")
+	}
+
 	// Load all possible xlator options
 	if err := xlator.Load(); err != nil {
 		log.WithError(err).Fatal("Failed to load xlator options")
 	}
 
+	// A backup is restored into the namespace of the cluster it was taken
+	// from, so the peer joins that cluster
+	var backup *store.Backup
+	if file := store.RestoreBackupFile(); file != "" {
+		var err error
+		if backup, err = store.ReadBackupFile(file); err != nil {
+			log.WithError(err).WithField("file", file).Fatal("Failed to read the cluster backup")
+		}
+		if backup.ClusterID != gdctx.MyClusterID.String() {
+			if err := gdctx.UpdateClusterID(backup.ClusterID); err != nil {
+				log.WithError(err).Fatal("Failed to update the cluster ID from the cluster backup")
+			}
+		}
+	}
+
 	// Initialize etcd store (etcd client connection)
 	if err := store.Init(nil); err != nil {
 		log.WithError(err).Fatal("Failed to initialize store (etcd client)")
 	}
 
+	// Migrate the keys of older versions before anything reads the store
+	if store.MigrateKeysAtStartup() {
+		if err := store.Store.MigrateUnprefixedKeys(context.Background()); err != nil {
+			log.WithError(err).Fatal("Failed to migrate unprefixed keys into the namespace of the cluster")
+		}
+	}
+
+	// Restore the backup before anything reads the store
+	if backup != nil {
+		if err := store.Store.Restore(context.Background(), backup); err != nil {
+			log.WithError(err).Fatal("Failed to restore the store from the cluster backup")
+		}
+	}
+
 	transaction.StartTxnEngine()
 	cleanuphandler.StartCleanupLeader()
 	// Start the events framework after store is up
@@ -94,10 +141,19 @@ func main() {
 		log.WithError(err).Fatal("Failed to start internal events framework")
 	}
 
+	// Follow the changes of the cluster options made on any peer
+	options.StartClusterOptionsWatcher()
+
 	if err := peer.AddSelfDetails(); err != nil {
 		log.WithError(err).Fatal("Could not add self details into etcd")
 	}
 
+	// Refuse to join a cluster running at an op-version newer than this
+	// peer supports
+	if err := opversion.Init(); err != nil {
+		log.WithError(err).Fatal("Failed to initialize the op-version of the cluster")
+	}
+
 	// Load the default group option map into the store
 	if err := volumecommands.InitDefaultGroupOptions(); err != nil {
 		log.WithError(err).Fatal("Failed to load the default group options")
@@ -118,6 +174,11 @@ func main() {
 		log.WithError(err).Fatal("failed to load volgen templates")
 	}
 
+	// Regenerate brick volfiles generated by older plugins
+	if err := volgen.RegenerateOnGraphHooksChange(); err != nil {
+		log.WithError(err).Warn("failed to regenerate brick volfiles after graph hooks changed")
+	}
+
 	// Start all servers (rest, peerrpc, sunrpc) managed by suture supervisor
 	super := initGD2Supervisor()
 	super.ServeBackground()
@@ -141,6 +202,15 @@ func main() {
 		log.WithError(err).Fatal("bmux.Reconcile() failed")
 	}
 
+	// Restart daemons which die from now on
+	daemon.StartSupervisor()
+
+	// Start sampling the usage of local bricks
+	usage.Start()
+
+	// Start checking the health of local devices
+	devicehealth.Start()
+
 	// Use the main goroutine as signal handling loop
 	sigCh := make(chan os.Signal)
 	signal.Notify(sigCh)
@@ -152,10 +222,15 @@ func main() {
 		case unix.SIGINT:
 			log.Info("Received SIGTERM. Stopping GlusterD")
 			gdctx.IsTerminating = true
+			jobs.Stop()
 			transaction.StopTxnEngine()
 			cleanuphandler.StopCleanupLeader()
+			daemon.StopSupervisor()
+			usage.Stop()
+			devicehealth.Stop()
 			super.Stop()
 			events.Stop()
+			options.StopClusterOptionsWatcher()
 			store.Close()
 			_ = os.Remove(config.GetString("pidfile"))
 			log.Info("Stopped GlusterD")
@@ -169,6 +244,16 @@ func main() {
 					log.WithError(err).Fatal("Could not re-initialize logging")
 				}
 			}
+			if err := audit.Init(); err != nil {
+				log.WithError(err).Error("Could not reopen audit log file")
+			}
+			// Pick up the changed endpoints and client certificates of
+			// an external etcd cluster
+			if !store.Store.IsEmbedded() {
+				if err := store.Store.Reload(); err != nil {
+					log.WithError(err).Error("Could not reload store configuration")
+				}
+			}
 		case unix.SIGUSR1:
 			log.Info("Received SIGUSR1. Dumping statedump")
 			utils.WriteStatedump(config.GetString("rundir"))
@@ -189,8 +274,11 @@ func createDirectories() error {
 	dirs := []string{config.GetString("localstatedir"),
 		config.GetString("rundir"), config.GetString("logdir"),
 		path.Join(config.GetString("logdir"), "glusterfs/bricks"),
+		path.Join(config.GetString("hooksdir"), "create/pre"),
 		path.Join(config.GetString("hooksdir"), "create/post"),
+		path.Join(config.GetString("hooksdir"), "start/pre"),
 		path.Join(config.GetString("hooksdir"), "start/post"),
+		path.Join(config.GetString("hooksdir"), "stop/pre"),
 		path.Join(config.GetString("hooksdir"), "stop/post"),
 		path.Join(config.GetString("hooksdir"), "set/post"),
 		path.Join(config.GetString("hooksdir"), "reset/post"),
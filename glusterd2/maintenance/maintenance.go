@@ -0,0 +1,66 @@
+// Package maintenance keeps the maintenance mode of the cluster in the
+// store. While the cluster is in maintenance mode, like during an upgrade,
+// the REST servers of all the peers reject the requests changing it.
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+const maintenanceKey = "maintenance"
+
+// Mode is the maintenance mode of the cluster
+type Mode struct {
+	Enabled bool      `json:"enabled"`
+	Reason  string    `json:"reason,omitempty"`
+	User    string    `json:"user,omitempty"`
+	Since   time.Time `json:"since,omitempty"`
+}
+
+// Get returns the maintenance mode of the cluster
+func Get(ctx context.Context) (*Mode, error) {
+	resp, err := store.Get(ctx, maintenanceKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var m Mode
+	if resp.Count != 1 {
+		return &m, nil
+	}
+	if err := json.Unmarshal(resp.Kvs[0].Value, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Set sets the maintenance mode of the cluster
+func Set(m *Mode) error {
+	if !m.Enabled {
+		_, err := store.Delete(context.TODO(), maintenanceKey)
+		return err
+	}
+
+	v, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), maintenanceKey, string(v))
+	return err
+}
+
+// CreateMaintenanceResp returns the API representation of the maintenance
+// mode
+func CreateMaintenanceResp(m *Mode) *api.MaintenanceResp {
+	return &api.MaintenanceResp{
+		Enabled: m.Enabled,
+		Reason:  m.Reason,
+		User:    m.User,
+		Since:   m.Since,
+	}
+}
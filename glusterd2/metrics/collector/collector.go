@@ -0,0 +1,165 @@
+// Package collector gathers the metrics of the resources of the cluster,
+// like volumes, bricks and devices, when they are scraped from the /metrics
+// endpoint.
+package collector
+
+import (
+	"context"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/metrics"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/plugins/device/deviceutils"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// storeProbeTimeout is the time after which the store is reported as down
+const storeProbeTimeout = 2 * time.Second
+
+func newDesc(name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(metrics.Namespace, "", name), help, labels, nil)
+}
+
+var (
+	volumesDesc     = newDesc("volumes", "Volumes of the cluster, by state.", "state")
+	peersDesc       = newDesc("peers", "Peers of the cluster, by whether they are online.", "online")
+	brickUpDesc     = newDesc("brick_up", "Whether the process of a brick of a started volume on this peer is running.", "volume", "brick")
+	deviceSizeDesc  = newDesc("device_size_bytes", "Size of a device of this peer.", "device")
+	deviceUsedDesc  = newDesc("device_used_bytes", "Space of a device of this peer allocated to the thin pools of bricks.", "device")
+	etcdUpDesc      = newDesc("etcd_up", "Whether the store answers requests.")
+	etcdLatencyDesc = newDesc("etcd_request_duration_seconds", "Time taken by the store to answer a probe request.")
+)
+
+// Collector is a prometheus.Collector of the metrics of the cluster. Metrics
+// of the whole cluster, like the volume counts, are the same on all the
+// peers, while the metrics of bricks and devices only cover this peer.
+type Collector struct{}
+
+// New returns a new Collector
+func New() *Collector {
+	return &Collector{}
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		volumesDesc, peersDesc, brickUpDesc, deviceSizeDesc,
+		deviceUsedDesc, etcdUpDesc, etcdLatencyDesc,
+	} {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector. Metrics which can't be gathered
+// are left out, except the health of the store.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	if !c.collectStore(ch) {
+		// Everything else comes from the store
+		return
+	}
+	c.collectVolumes(ch)
+	c.collectPeers(ch)
+	c.collectDevices(ch)
+}
+
+func gauge(desc *prometheus.Desc, value float64, labels ...string) prometheus.Metric {
+	return prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labels...)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// collectStore probes the store and returns true if it is up
+func (c *Collector) collectStore(ch chan<- prometheus.Metric) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), storeProbeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	_, err := store.Get(ctx, "peers/", clientv3.WithPrefix(), clientv3.WithCountOnly())
+	if err != nil {
+		log.WithError(err).Debug("metrics: store probe failed")
+		ch <- gauge(etcdUpDesc, 0)
+		return false
+	}
+	ch <- gauge(etcdUpDesc, 1)
+	ch <- gauge(etcdLatencyDesc, time.Since(start).Seconds())
+	return true
+}
+
+func (c *Collector) collectVolumes(ch chan<- prometheus.Metric) {
+	volumes, err := volume.GetVolumes(context.Background())
+	if err != nil {
+		log.WithError(err).Warn("metrics: failed to get volumes")
+		return
+	}
+
+	counts := make(map[volume.VolState]int)
+	for _, v := range volumes {
+		counts[v.State]++
+		if v.State == volume.VolStarted {
+			for _, b := range v.GetLocalBricks() {
+				ch <- gauge(brickUpDesc, boolValue(brickRunning(b)), v.Name, b.String())
+			}
+		}
+	}
+	for _, state := range []volume.VolState{volume.VolCreated, volume.VolStarted, volume.VolStopped} {
+		ch <- gauge(volumesDesc, float64(counts[state]), api.VolState(state).String())
+	}
+}
+
+// brickRunning returns true if the process of a brick is running
+func brickRunning(b brick.Brickinfo) bool {
+	d, err := brick.NewGlusterfsd(b)
+	if err != nil {
+		return false
+	}
+	pid, err := daemon.ReadPidFromFile(d.PidFile())
+	if err != nil {
+		return false
+	}
+	_, err = daemon.GetProcess(pid)
+	return err == nil
+}
+
+func (c *Collector) collectPeers(ch chan<- prometheus.Metric) {
+	peers, err := peer.GetPeers()
+	if err != nil {
+		log.WithError(err).Warn("metrics: failed to get peers")
+		return
+	}
+
+	online := 0
+	for _, p := range peers {
+		if _, alive := store.Store.IsNodeAlive(p.ID); alive {
+			online++
+		}
+	}
+	ch <- gauge(peersDesc, float64(online), "true")
+	ch <- gauge(peersDesc, float64(len(peers)-online), "false")
+}
+
+func (c *Collector) collectDevices(ch chan<- prometheus.Metric) {
+	devices, err := deviceutils.GetDevices(gdctx.MyUUID.String())
+	if err != nil {
+		log.WithError(err).Warn("metrics: failed to get devices")
+		return
+	}
+
+	for _, d := range devices {
+		ch <- gauge(deviceSizeDesc, float64(d.TotalSize), d.Device)
+		ch <- gauge(deviceUsedDesc, float64(d.UsedSize), d.Device)
+	}
+}
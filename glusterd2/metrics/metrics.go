@@ -0,0 +1,78 @@
+// Package metrics keeps the metrics which glusterd2 exposes in the
+// Prometheus format on its /metrics endpoint. The metrics of the resources
+// of the cluster, which are gathered when they are scraped, are in the
+// collector package.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace prefixes the names of all the metrics of glusterd2
+const Namespace = "glusterd2"
+
+var (
+	// RESTRequests counts the REST requests served by this peer, by method
+	// and status code
+	RESTRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "rest",
+		Name:      "requests_total",
+		Help:      "REST requests served, by method and status code.",
+	}, []string{"method", "code"})
+
+	// RESTRequestDuration observes the time taken to serve REST requests,
+	// by method
+	RESTRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "rest",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken to serve REST requests, by method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	// TxnDuration observes the time taken by the transactions initiated by
+	// this peer, by result
+	TxnDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "transaction",
+		Name:      "duration_seconds",
+		Help:      "Time taken by the transactions initiated by this peer, by result.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 14),
+	}, []string{"result"})
+)
+
+func init() {
+	prometheus.MustRegister(RESTRequests, RESTRequestDuration, TxnDuration)
+}
+
+// ObserveTxn records the duration of a transaction which started at start
+// and ended with err
+func ObserveTxn(start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	TxnDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+}
+
+// Register registers a collector of metrics gathered when they are scraped.
+// Registering the same metrics again, like when the REST server is
+// restarted, is not an error.
+func Register(c prometheus.Collector) error {
+	if err := prometheus.Register(c); err != nil {
+		if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler returns the handler of the /metrics endpoint
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/users"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+)
+
+// AdminOnly is a middleware for handlers of requests which only the cluster
+// administrators are allowed to make, that is the internal user used by
+// glustercli and the users with the admin role.
+func AdminOnly(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if gdctx.RESTAPIAuthEnabled && gdctx.GetReqRole(ctx) != api.RoleAdmin {
+			restutils.SendHTTPError(ctx, w, http.StatusForbidden, gderrors.ErrAdminRequired)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// RequireRole is a middleware for handlers of requests which only the users
+// with at least the given role are allowed to make. Any request is allowed
+// if REST API authentication is disabled or not required for the URL.
+func RequireRole(role string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if gdctx.RESTAPIAuthEnabled && isRestAuthRequired(r.URL.String()) &&
+			!users.RoleAllows(gdctx.GetReqRole(ctx), role) {
+			restutils.SendHTTPError(ctx, w, http.StatusForbidden, gderrors.ErrPermissionDenied)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/jobs"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/gorilla/mux"
+)
+
+// valuesContext has the values of one context, and the deadline and
+// cancellation of another
+type valuesContext struct {
+	context.Context
+	values context.Context
+}
+
+func (c valuesContext) Value(key interface{}) interface{} {
+	return c.values.Value(key)
+}
+
+// jobResponseWriter keeps the response of a request served by a job
+type jobResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newJobResponseWriter() *jobResponseWriter {
+	return &jobResponseWriter{header: make(http.Header)}
+}
+
+func (rw *jobResponseWriter) Header() http.Header {
+	return rw.header
+}
+
+func (rw *jobResponseWriter) WriteHeader(code int) {
+	if rw.status == 0 {
+		rw.status = code
+	}
+}
+
+func (rw *jobResponseWriter) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	return rw.body.Write(b)
+}
+
+// err returns the error of a failed request, made of the messages of its
+// error response
+func (rw *jobResponseWriter) err() error {
+	if rw.status < http.StatusBadRequest {
+		return nil
+	}
+
+	var resp api.ErrorResp
+	if err := json.Unmarshal(rw.body.Bytes(), &resp); err != nil || len(resp.Errors) == 0 {
+		return errors.New(http.StatusText(rw.status))
+	}
+	msgs := make([]string, 0, len(resp.Errors))
+	for _, e := range resp.Errors {
+		if e.Field != "" {
+			msgs = append(msgs, e.Field+": "+e.Message)
+		} else {
+			msgs = append(msgs, e.Message)
+		}
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+// Async is a middleware for handlers of long running requests. If the
+// client asks for the request to be served asynchronously by the
+// async=true query parameter, the request is served by a job of the given
+// type and the job is returned right away with a 202 Accepted status. The
+// response of the request becomes the result of the job once it is done,
+// while an error response fails the job. Requests without the parameter
+// are served as usual. The job counts against the limit of concurrent
+// mutations of the RateLimiter which served the request, if any, while it
+// runs.
+func Async(jobType string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !restutils.IsAsyncRequest(r) {
+			next(w, r)
+			return
+		}
+
+		ctx := r.Context()
+
+		// The body of the request is gone once this handler returns
+		var body []byte
+		if r.Body != nil {
+			var err error
+			body, err = ioutil.ReadAll(r.Body)
+			r.Body.Close()
+			if err != nil {
+				restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		volname := mux.Vars(r)["volname"]
+		job, err := jobs.Submit(ctx, jobType, volname, func(jobCtx context.Context, h *jobs.Handle) error {
+			// The request keeps its values, like the route variables and
+			// the user, but is cancelled with the job
+			req := r.WithContext(valuesContext{Context: jobCtx, values: ctx})
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+			// The slot of the request in the limit of concurrent
+			// mutations is freed once the job is accepted, the job
+			// takes one while it runs
+			release, err := acquireBusySlot(req.Context())
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			rw := newJobResponseWriter()
+			next(rw, req)
+			if err := rw.err(); err != nil {
+				return err
+			}
+			if rw.body.Len() > 0 {
+				if err := h.SetResult(json.RawMessage(rw.body.Bytes())); err != nil {
+					gdctx.GetReqLogger(jobCtx).WithError(err).Warn("failed to set result of job")
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		restutils.SendJobAccepted(ctx, w, jobs.CreateJobInfoResp(job))
+	}
+}
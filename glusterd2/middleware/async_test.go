@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCtxKey struct{}
+
+func TestAsyncNotRequested(t *testing.T) {
+	called := false
+	h := Async("test", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	req := httptest.NewRequest("POST", "/v1/volumes?async=false", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.True(t, called)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestValuesContext(t *testing.T) {
+	values := context.WithValue(context.Background(), testCtxKey{}, "value")
+	values, cancelValues := context.WithCancel(values)
+	jobCtx, cancel := context.WithCancel(context.Background())
+
+	ctx := valuesContext{Context: jobCtx, values: values}
+	assert.Equal(t, "value", ctx.Value(testCtxKey{}))
+
+	// Only the job cancels the context
+	cancelValues()
+	assert.Nil(t, ctx.Err())
+	cancel()
+	assert.Equal(t, context.Canceled, ctx.Err())
+}
+
+func TestJobResponseWriter(t *testing.T) {
+	rw := newJobResponseWriter()
+	rw.Write([]byte(`{"name": "vol1"}`))
+	assert.Equal(t, http.StatusOK, rw.status)
+	assert.Nil(t, rw.err())
+
+	rw = newJobResponseWriter()
+	rw.WriteHeader(http.StatusBadRequest)
+	rw.Write([]byte(`{"errors": [{"code": 1, "message": "volume already exists"}, {"code": 5, "field": "replica", "message": "must be between 0 and 3"}]}`))
+	assert.EqualError(t, rw.err(), "volume already exists; replica: must be between 0 and 3")
+
+	rw = newJobResponseWriter()
+	rw.WriteHeader(http.StatusInternalServerError)
+	assert.EqualError(t, rw.err(), "Internal Server Error")
+}
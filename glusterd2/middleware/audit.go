@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/audit"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// maxAuditBodySize is the largest request body read to be digested, larger
+// requests are rejected
+const maxAuditBodySize = 16 << 20
+
+// auditLog writes audit records, tests replace it to look at the records
+var auditLog = audit.Log
+
+// auditRecorder passes the response through to the client while keeping
+// its status code
+type auditRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (rec *auditRecorder) WriteHeader(code int) {
+	rec.status = code
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *auditRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return rec.ResponseWriter.Write(b)
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// auditOutcome returns the outcome of a request with the given status code
+func auditOutcome(status int) string {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return api.AuditDenied
+	case status >= http.StatusBadRequest:
+		return api.AuditFailure
+	}
+	return api.AuditSuccess
+}
+
+// auditBodyDigest returns the digest of the body of a request, which is
+// replaced with a copy for the handlers to read. Bodies larger than
+// maxAuditBodySize are not read in full and rejected.
+func auditBodyDigest(w http.ResponseWriter, r *http.Request) (string, int, error) {
+	if r.Body == nil {
+		return "", http.StatusOK, nil
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxAuditBodySize))
+	if err != nil {
+		if len(body) >= maxAuditBodySize {
+			return "", http.StatusRequestEntityTooLarge, err
+		}
+		return "", http.StatusBadRequest, err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if len(body) == 0 {
+		return "", http.StatusOK, nil
+	}
+	sum := sha256.Sum256(body)
+	return "sha256:" + hex.EncodeToString(sum[:]), http.StatusOK, nil
+}
+
+// Audit is a middleware which writes an audit record for every mutating
+// request, with the requester, the digest of the request body, the
+// transactions started for the request and its outcome. It comes before the
+// rate limiter and Auth in the chain, so that the requests they reject are
+// audited too, and records the requester once the request is served.
+func Audit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isMutatingMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := gdctx.WithTxnIDRecorder(r.Context())
+		ctx = gdctx.WithRequesterRecorder(ctx)
+		rec := &api.AuditRecord{
+			Time:     time.Now().UTC(),
+			ReqID:    gdctx.GetReqID(ctx).String(),
+			PeerID:   gdctx.MyUUID.String(),
+			SourceIP: r.RemoteAddr,
+			Method:   r.Method,
+			Path:     r.URL.Path,
+		}
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			rec.SourceIP = host
+		}
+
+		sr := &auditRecorder{ResponseWriter: w}
+		digest, status, err := auditBodyDigest(w, r)
+		if err != nil {
+			gdctx.GetReqLogger(ctx).WithError(err).Error("failed to read request body for audit")
+			restutils.SendHTTPError(ctx, sr, status, err)
+		} else {
+			rec.BodyDigest = digest
+			next.ServeHTTP(sr, r.WithContext(ctx))
+		}
+
+		rec.User, rec.Role = gdctx.GetRecordedRequester(ctx)
+		rec.Status = sr.status
+		if rec.Status == 0 {
+			rec.Status = http.StatusOK
+		}
+		rec.Outcome = auditOutcome(rec.Status)
+		rec.TxnIDs = gdctx.GetTxnIDs(ctx)
+		auditLog(rec)
+	})
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudit(t *testing.T) {
+	var records []*api.AuditRecord
+	auditLog = func(rec *api.AuditRecord) {
+		records = append(records, rec)
+	}
+
+	txnID := uuid.NewRandom()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The body is still there for the handler
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) == "bad" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		gdctx.RecordTxnID(r.Context(), txnID)
+		w.WriteHeader(http.StatusCreated)
+	})
+	withUser := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := gdctx.WithReqUser(r.Context(), "operator1")
+			ctx = gdctx.WithReqRole(ctx, api.RoleOperator)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+
+	// The requester is known only once the request is authenticated, after
+	// Audit in the chain
+	ts := httptest.NewServer(ReqIDGenerator(Audit(withUser(handler))))
+	defer ts.Close()
+
+	// Requests which don't change anything are not audited
+	resp, err := http.Get(ts.URL + "/v1/volumes")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Empty(t, records)
+
+	resp, err = http.Post(ts.URL+"/v1/volumes", "application/json", strings.NewReader("{}"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Len(t, records, 1)
+
+	sum := sha256.Sum256([]byte("{}"))
+	rec := records[0]
+	assert.Equal(t, resp.Header.Get("X-Request-Id"), rec.ReqID)
+	assert.Equal(t, "operator1", rec.User)
+	assert.Equal(t, api.RoleOperator, rec.Role)
+	assert.Equal(t, "127.0.0.1", rec.SourceIP)
+	assert.Equal(t, "POST", rec.Method)
+	assert.Equal(t, "/v1/volumes", rec.Path)
+	assert.Equal(t, "sha256:"+hex.EncodeToString(sum[:]), rec.BodyDigest)
+	assert.Equal(t, []string{txnID.String()}, rec.TxnIDs)
+	assert.Equal(t, http.StatusCreated, rec.Status)
+	assert.Equal(t, api.AuditSuccess, rec.Outcome)
+
+	resp, err = http.Post(ts.URL+"/v1/volumes", "application/json", strings.NewReader("bad"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Len(t, records, 2)
+	assert.Equal(t, http.StatusBadRequest, records[1].Status)
+	assert.Equal(t, api.AuditFailure, records[1].Outcome)
+	assert.Empty(t, records[1].TxnIDs)
+}
+
+func TestAuditDenied(t *testing.T) {
+	var records []*api.AuditRecord
+	auditLog = func(rec *api.AuditRecord) {
+		records = append(records, rec)
+	}
+	gdctx.RESTAPIAuthEnabled = true
+	defer func() { gdctx.RESTAPIAuthEnabled = false }()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	ts := httptest.NewServer(ReqIDGenerator(Audit(Auth(handler))))
+	defer ts.Close()
+
+	// Requests without a token are audited, without a requester
+	resp, err := http.Post(ts.URL+"/v1/volumes", "application/json", strings.NewReader("{}"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	require.Len(t, records, 1)
+	assert.Equal(t, http.StatusUnauthorized, records[0].Status)
+	assert.Equal(t, api.AuditDenied, records[0].Outcome)
+	assert.Empty(t, records[0].User)
+	assert.Empty(t, records[0].Role)
+	assert.NotEmpty(t, records[0].BodyDigest)
+}
+
+func TestAuditBodyTooLarge(t *testing.T) {
+	var records []*api.AuditRecord
+	auditLog = func(rec *api.AuditRecord) {
+		records = append(records, rec)
+	}
+
+	called := false
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+	ts := httptest.NewServer(ReqIDGenerator(Audit(handler)))
+	defer ts.Close()
+
+	body := strings.NewReader(strings.Repeat("x", maxAuditBodySize+1))
+	resp, err := http.Post(ts.URL+"/v1/volumes", "application/json", body)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	assert.False(t, called)
+	require.Len(t, records, 1)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, records[0].Status)
+	assert.Equal(t, api.AuditFailure, records[0].Outcome)
+	assert.Empty(t, records[0].BodyDigest)
+}
@@ -8,26 +8,43 @@ import (
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/users"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
 	"github.com/gluster/glusterd2/pkg/utils"
 
 	"github.com/dgrijalva/jwt-go"
 )
 
 const (
-	internalUser = "glustercli"
+	internalUser = users.InternalUser
 )
 
 var (
 	requiredClaims = []string{"iss", "exp", "qsh"}
+
+	// getUser looks up the users added through the users API
+	getUser = users.GetUser
 )
 
-func getAuthSecret(issuer string) string {
+// getAuthUser returns the user who issued a token, or nil if the issuer is
+// not a known user
+func getAuthUser(issuer string) *users.User {
 	if issuer == internalUser {
-		return gdctx.LocalAuthToken
+		return &users.User{Name: internalUser, Role: api.RoleAdmin, Secret: gdctx.LocalAuthToken}
 	}
 
-	// TODO: Look for issuer secret in etcd if not internal user, this depends on User management feature
+	u, err := getUser(issuer)
+	if err != nil {
+		return nil
+	}
+	return u
+}
 
+func getAuthSecret(issuer string) string {
+	if u := getAuthUser(issuer); u != nil {
+		return u.Secret
+	}
 	return ""
 }
 
@@ -37,6 +54,10 @@ func isRestAuthRequired(url string) bool {
 	case "/ping":
 		fallthrough
 	case "/endpoints":
+		fallthrough
+	case "/v1/openapi.json":
+		fallthrough
+	case "/v1/health":
 		return false
 	default:
 		return true
@@ -68,6 +89,7 @@ func Auth(next http.Handler) http.Handler {
 		}
 
 		// Verify JWT token with additional validations for Claims
+		var user *users.User
 		token, err := jwt.Parse(authHeaderParts[1], func(token *jwt.Token) (interface{}, error) {
 			claims, ok := token.Claims.(jwt.MapClaims)
 			if !ok {
@@ -86,8 +108,9 @@ func Auth(next http.Handler) http.Handler {
 				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 			}
 
-			secret := getAuthSecret(claims["iss"].(string))
-			if secret == "" {
+			issuer, _ := claims["iss"].(string)
+			user = getAuthUser(issuer)
+			if user == nil || user.Secret == "" {
 				return nil, fmt.Errorf("invalid App ID: %s", claims["iss"])
 			}
 			// Check qsh claim
@@ -95,7 +118,7 @@ func Auth(next http.Handler) http.Handler {
 				return nil, errors.New("invalid qsh claim in token")
 			}
 			// All checks GOOD, return the Secret to validate
-			return []byte(secret), nil
+			return []byte(user.Secret), nil
 		})
 
 		// Check if token is Valid
@@ -108,9 +131,36 @@ func Auth(next http.Handler) http.Handler {
 			return
 		}
 
-		// TODO: Filter URLs here if any role based control of APIs, this depends on User management feature
+		// A token may ask for a less privileged role than the one of the
+		// user, so that a client can limit what it is able to do. In the
+		// same way, it may be bound to one of the namespaces of the user.
+		role := user.Role
+		namespaces := user.Namespaces
+		if claims, ok := token.Claims.(jwt.MapClaims); ok {
+			if claimed, ok := claims["role"].(string); ok && claimed != "" {
+				if !users.IsValidRole(claimed) || !users.RoleAllows(user.Role, claimed) {
+					restutils.SendHTTPError(ctx, w, http.StatusForbidden, gderrors.ErrRoleNotAllowed)
+					return
+				}
+				role = claimed
+			}
+			if claimed, ok := claims["namespace"].(string); ok && claimed != "" {
+				if !user.NamespaceAllowed(claimed) {
+					restutils.SendHTTPError(ctx, w, http.StatusForbidden, gderrors.ErrNamespaceNotAllowed)
+					return
+				}
+				namespaces = []string{claimed}
+			}
+		}
+
+		// Record the authenticated issuer so that handlers can attribute
+		// changes to the requester, and its role and namespaces so that the
+		// routes can check its permissions
+		ctx = gdctx.WithReqUser(ctx, user.Name)
+		ctx = gdctx.WithReqRole(ctx, role)
+		ctx = gdctx.WithReqNamespaces(ctx, namespaces)
 
 		// Authentication is successful, continue serving the request
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
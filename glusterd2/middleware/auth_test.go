@@ -13,10 +13,28 @@ import (
 
 	jwt "github.com/dgrijalva/jwt-go"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/users"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
 	config "github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
+var testUsers = map[string]*users.User{
+	"viewer1":   {Name: "viewer1", Role: api.RoleViewer, Secret: "viewersecret"},
+	"operator1": {Name: "operator1", Role: api.RoleOperator, Secret: "operatorsecret"},
+	"tenant1":   {Name: "tenant1", Role: api.RoleOperator, Secret: "tenantsecret", Namespaces: []string{"team1", "team2"}},
+}
+
+func init() {
+	getUser = func(name string) (*users.User, error) {
+		if u, ok := testUsers[name]; ok {
+			return u, nil
+		}
+		return nil, gderrors.ErrUserNotFound
+	}
+}
+
 func TestGetAuthSecret(t *testing.T) {
 	secret := getAuthSecret("test")
 	assert.Empty(t, secret)
@@ -32,6 +50,14 @@ func TestGetAuthSecret(t *testing.T) {
 }
 
 func getAuthToken(username string, password string, r *http.Request) {
+	getAuthTokenWithRole(username, password, "", r)
+}
+
+func getAuthTokenWithRole(username, password, role string, r *http.Request) {
+	getAuthTokenWithClaims(username, password, map[string]string{"role": role}, r)
+}
+
+func getAuthTokenWithClaims(username, password string, claims map[string]string, r *http.Request) {
 	// Generate qsh
 	qshstring := "GET&/"
 	hash := sha256.New()
@@ -42,6 +68,11 @@ func getAuthToken(username string, password string, r *http.Request) {
 		"exp": time.Now().Add(time.Second * time.Duration(120)).Unix(),
 		"qsh": hex.EncodeToString(hash.Sum(nil)),
 	})
+	for name, value := range claims {
+		if value != "" {
+			token.Claims.(jwt.MapClaims)[name] = value
+		}
+	}
 	// Sign the token
 	signedtoken, err := token.SignedString([]byte(password))
 	if err != nil {
@@ -96,6 +127,113 @@ func TestAuth(t *testing.T) {
 	os.Remove("auth")
 }
 
+func TestIsRestAuthRequired(t *testing.T) {
+	for _, tc := range []struct {
+		url      string
+		required bool
+	}{
+		{"/ping", false},
+		{"/endpoints", false},
+		{"/v1/openapi.json", false},
+		{"/v1/health", false},
+		{"/metrics", true},
+		{"/statedump", true},
+		{"/v1/volumes", true},
+	} {
+		assert.Equal(t, tc.required, isRestAuthRequired(tc.url), tc.url)
+	}
+}
+
+func TestAuthRoles(t *testing.T) {
+	var role string
+	ts := httptest.NewServer(Auth(RequireRole(api.RoleOperator, func(w http.ResponseWriter, r *http.Request) {
+		role = gdctx.GetReqRole(r.Context())
+	})))
+	defer ts.Close()
+	gdctx.RESTAPIAuthEnabled = true
+	defer func() { gdctx.RESTAPIAuthEnabled = false }()
+
+	for _, tc := range []struct {
+		user, secret, claimed string
+		status                int
+	}{
+		{"operator1", "operatorsecret", "", http.StatusOK},
+		{"viewer1", "viewersecret", "", http.StatusForbidden},
+		// A token may only ask for a role below the one of the user
+		{"operator1", "operatorsecret", api.RoleViewer, http.StatusForbidden},
+		{"viewer1", "viewersecret", api.RoleAdmin, http.StatusForbidden},
+		{"operator1", "operatorsecret", "superuser", http.StatusForbidden},
+		{"operator1", "wrongsecret", "", http.StatusUnauthorized},
+	} {
+		role = ""
+		req, err := http.NewRequest("GET", ts.URL, nil)
+		assert.Nil(t, err)
+		getAuthTokenWithRole(tc.user, tc.secret, tc.claimed, req)
+		resp, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.status, resp.StatusCode, tc)
+		if tc.status == http.StatusOK {
+			assert.Equal(t, api.RoleOperator, role)
+		}
+	}
+}
+
+func TestAuthNamespaces(t *testing.T) {
+	var namespaces []string
+	ts := httptest.NewServer(Auth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		namespaces = gdctx.GetReqNamespaces(r.Context())
+	})))
+	defer ts.Close()
+	gdctx.RESTAPIAuthEnabled = true
+	defer func() { gdctx.RESTAPIAuthEnabled = false }()
+
+	for _, tc := range []struct {
+		user, secret, claimed string
+		status                int
+		namespaces            []string
+	}{
+		{"operator1", "operatorsecret", "", http.StatusOK, nil},
+		{"operator1", "operatorsecret", "team3", http.StatusOK, []string{"team3"}},
+		{"tenant1", "tenantsecret", "", http.StatusOK, []string{"team1", "team2"}},
+		{"tenant1", "tenantsecret", "team2", http.StatusOK, []string{"team2"}},
+		// A token may only be bound to one of the namespaces of the user
+		{"tenant1", "tenantsecret", "team3", http.StatusForbidden, nil},
+	} {
+		namespaces = nil
+		req, err := http.NewRequest("GET", ts.URL, nil)
+		assert.Nil(t, err)
+		getAuthTokenWithClaims(tc.user, tc.secret, map[string]string{"namespace": tc.claimed}, req)
+		resp, err := http.DefaultClient.Do(req)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.status, resp.StatusCode, tc)
+		assert.Equal(t, tc.namespaces, namespaces, tc)
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	gdctx.RESTAPIAuthEnabled = true
+	defer func() { gdctx.RESTAPIAuthEnabled = false }()
+
+	h := RequireRole(api.RoleOperator, GetTestHandler())
+	for role, status := range map[string]int{
+		"":               http.StatusForbidden,
+		api.RoleViewer:   http.StatusForbidden,
+		api.RoleOperator: http.StatusOK,
+		api.RoleAdmin:    http.StatusOK,
+	} {
+		r := httptest.NewRequest("POST", "/v1/volumes/vol1/start", nil)
+		r = r.WithContext(gdctx.WithReqRole(r.Context(), role))
+		w := httptest.NewRecorder()
+		h(w, r)
+		assert.Equal(t, status, w.Code, role)
+	}
+
+	// Routes which don't require authentication are open to everyone
+	w := httptest.NewRecorder()
+	RequireRole(api.RoleViewer, GetTestHandler())(w, httptest.NewRequest("GET", "/ping", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
 func GetTestHandler() http.HandlerFunc {
 	fn := func(rw http.ResponseWriter, req *http.Request) {
 
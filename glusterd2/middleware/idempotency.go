@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	config "github.com/spf13/viper"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the idempotency key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set in the response when it is the
+	// stored result of an earlier request with the same idempotency key
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	idempotencyKeyPrefix           = "idempotency-keys/"
+	maxIdempotencyKeyLen           = 255
+	defaultIdempotencyKeyTTL int64 = 86400
+	// inProgressIdempotencyKeyTTL is the number of seconds the key of a
+	// request being served outlives the glusterd2 serving it, as the lease
+	// of the key is kept alive only while the request is being served
+	inProgressIdempotencyKeyTTL int64 = 60
+)
+
+// idempotencyRecord is what is stored against an idempotency key. Done is
+// false while the request is being served.
+type idempotencyRecord struct {
+	Hash        string `json:"hash"`
+	Done        bool   `json:"done"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content-type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// idempotencyRecorder passes the response through to the client while
+// keeping a copy of it
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *idempotencyRecorder) WriteHeader(code int) {
+	rec.status = code
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *idempotencyRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// idempotencyKeyTTL returns the number of seconds the result of a request is
+// kept against its idempotency key
+func idempotencyKeyTTL() int64 {
+	ttl := config.GetInt64("idempotencykeyttl")
+	if ttl == 0 {
+		ttl = defaultIdempotencyKeyTTL
+	}
+	return ttl
+}
+
+// idempotencyStoreKey returns the store key of the idempotency key of a
+// user, so that users can't see or replay the results of each other's
+// requests
+func idempotencyStoreKey(user, idemKey string) string {
+	h := sha256.Sum256([]byte(user + "\x00" + idemKey))
+	return idempotencyKeyPrefix + hex.EncodeToString(h[:])
+}
+
+// requestHash returns a hash identifying the request, so that an idempotency
+// key being reused for a different request can be caught
+func requestHash(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + "\n" + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Idempotent is a middleware for handlers of mutating requests. If the
+// request carries an Idempotency-Key header, the result of the request is
+// stored against the key and retries of the request with the same key get
+// the stored result instead of the request being served again. Keys are
+// scoped to the authenticated user. Results of requests which fail with a
+// server error or panic are not stored, so that they can be retried.
+func Idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idemKey := r.Header.Get(IdempotencyKeyHeader)
+		if idemKey == "" {
+			next(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		logger := gdctx.GetReqLogger(ctx).WithField("idempotency-key", idemKey)
+
+		if len(idemKey) > maxIdempotencyKeyLen {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrIdempotencyKeyInvalid)
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		key := idempotencyStoreKey(gdctx.GetReqUser(ctx), idemKey)
+		record := idempotencyRecord{Hash: requestHash(r.Method, r.URL.Path, body)}
+
+		l, err := store.Store.Grant(store.Store.Ctx(), inProgressIdempotencyKeyTTL)
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		v, err := json.Marshal(record)
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		// Claim the key, unless an earlier request already has
+		resp, err := store.Txn(ctx).
+			If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+			Then(clientv3.OpPut(key, string(v), clientv3.WithLease(l.ID))).
+			Else(clientv3.OpGet(key)).
+			Commit()
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		if !resp.Succeeded {
+			store.Store.Revoke(store.Store.Ctx(), l.ID)
+
+			kvs := resp.Responses[0].GetResponseRange().Kvs
+			if len(kvs) == 0 {
+				// The key expired in between, let the client retry
+				restutils.SendHTTPError(ctx, w, http.StatusConflict, gderrors.ErrIdempotencyKeyInProgress)
+				return
+			}
+
+			var stored idempotencyRecord
+			if err := json.Unmarshal(kvs[0].Value, &stored); err != nil {
+				restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+				return
+			}
+
+			switch {
+			case stored.Hash != record.Hash:
+				restutils.SendHTTPError(ctx, w, http.StatusUnprocessableEntity, gderrors.ErrIdempotencyKeyReused)
+			case !stored.Done:
+				restutils.SendHTTPError(ctx, w, http.StatusConflict, gderrors.ErrIdempotencyKeyInProgress)
+			default:
+				logger.Info("replaying stored result of request")
+				if stored.ContentType != "" {
+					w.Header().Set("Content-Type", stored.ContentType)
+				}
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				w.WriteHeader(stored.Status)
+				w.Write(stored.Body)
+			}
+			return
+		}
+
+		keepAliveCtx, stopKeepAlive := context.WithCancel(store.Store.Ctx())
+		if ch, err := store.Store.KeepAlive(keepAliveCtx, l.ID); err != nil {
+			logger.WithError(err).Warn("failed to keep idempotency key alive")
+		} else {
+			go func() {
+				for range ch {
+				}
+			}()
+		}
+
+		// Revoking the lease removes the key, unless the result of the
+		// request was stored against it. This is deferred so that the key
+		// is also removed if the handler panics.
+		defer func() {
+			stopKeepAlive()
+			if _, err := store.Store.Revoke(store.Store.Ctx(), l.ID); err != nil {
+				logger.WithError(err).Error("failed to remove idempotency key from store")
+			}
+		}()
+
+		rec := &idempotencyRecorder{ResponseWriter: w}
+		next(rec, r)
+
+		if rec.status == 0 || rec.status >= http.StatusInternalServerError {
+			return
+		}
+
+		record.Done = true
+		record.Status = rec.status
+		record.ContentType = w.Header().Get("Content-Type")
+		record.Body = rec.body.Bytes()
+
+		if err := storeIdempotencyRecord(key, record); err != nil {
+			logger.WithError(err).Error("failed to store result of request")
+		}
+	}
+}
+
+// storeIdempotencyRecord stores the result of a request against its key
+// with a lease of the configured TTL
+func storeIdempotencyRecord(key string, record idempotencyRecord) error {
+	v, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	l, err := store.Store.Grant(store.Store.Ctx(), idempotencyKeyTTL())
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(store.Store.Ctx(), key, string(v), clientv3.WithLease(l.ID))
+	return err
+}
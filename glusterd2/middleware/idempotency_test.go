@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestHash(t *testing.T) {
+	h := requestHash("POST", "/v1/volumes", []byte(`{"name":"vol1"}`))
+	assert.Equal(t, h, requestHash("POST", "/v1/volumes", []byte(`{"name":"vol1"}`)))
+	assert.NotEqual(t, h, requestHash("POST", "/v1/volumes", []byte(`{"name":"vol2"}`)))
+	assert.NotEqual(t, h, requestHash("DELETE", "/v1/volumes", []byte(`{"name":"vol1"}`)))
+	assert.NotEqual(t, h, requestHash("POST", "/v1/volumes/vol1/expand", []byte(`{"name":"vol1"}`)))
+}
+
+func TestIdempotencyStoreKey(t *testing.T) {
+	k := idempotencyStoreKey("alice", "key1")
+	assert.True(t, strings.HasPrefix(k, idempotencyKeyPrefix))
+	assert.Equal(t, k, idempotencyStoreKey("alice", "key1"))
+	assert.NotEqual(t, k, idempotencyStoreKey("bob", "key1"))
+	assert.NotEqual(t, k, idempotencyStoreKey("alice", "key2"))
+	assert.NotEqual(t, idempotencyStoreKey("a/b", "c"), idempotencyStoreKey("a", "b/c"))
+}
+
+func TestIdempotentWithoutKey(t *testing.T) {
+	called := 0
+	h := Idempotent(func(w http.ResponseWriter, r *http.Request) {
+		called++
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		h(rec, httptest.NewRequest("POST", "/v1/volumes", nil))
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Empty(t, rec.Header().Get(IdempotentReplayedHeader))
+	}
+	assert.Equal(t, 2, called)
+}
+
+func TestIdempotencyRecorder(t *testing.T) {
+	w := httptest.NewRecorder()
+	rec := &idempotencyRecorder{ResponseWriter: w}
+
+	rec.Write([]byte("hello"))
+	assert.Equal(t, http.StatusOK, rec.status)
+	assert.Equal(t, "hello", rec.body.String())
+	assert.Equal(t, "hello", w.Body.String())
+}
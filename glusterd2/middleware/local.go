@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// LocalAuth is a middleware which authenticates the HTTP requests received
+// on the local Unix socket. The listener of the socket only accepts the
+// connections of the local admins, so the requests are served as requests
+// of the internal admin user, whatever token they carry.
+func LocalAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := gdctx.WithReqUser(r.Context(), internalUser)
+		ctx = gdctx.WithReqRole(ctx, api.RoleAdmin)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
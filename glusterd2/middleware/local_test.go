@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalAuth(t *testing.T) {
+	gdctx.RESTAPIAuthEnabled = true
+	defer func() { gdctx.RESTAPIAuthEnabled = false }()
+
+	var user string
+	h := LocalAuth(RequireRole(api.RoleAdmin, func(w http.ResponseWriter, r *http.Request) {
+		user = gdctx.GetReqUser(r.Context())
+	}))
+
+	// No token is needed
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("DELETE", "/v1/volumes/vol1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, internalUser, user)
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/maintenance"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+)
+
+// maintenancePath is the path of the requests to get and set the
+// maintenance mode, which are allowed in maintenance mode
+const maintenancePath = "/v1/maintenance"
+
+// getMaintenance returns the maintenance mode of the cluster, tests replace
+// it to set the mode
+var getMaintenance = maintenance.Get
+
+// Maintenance is a middleware which rejects the requests changing the
+// cluster with 503 Service Unavailable while the cluster is in maintenance
+// mode, except those taking it out of maintenance mode. If the maintenance
+// mode can't be read from the store, requests are let through, as they
+// would fail anyway.
+func Maintenance(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isMutatingMethod(r.Method) || r.URL.Path == maintenancePath {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		m, err := getMaintenance(ctx)
+		if err != nil {
+			gdctx.GetReqLogger(ctx).WithError(err).Warn("failed to get maintenance mode")
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if m.Enabled {
+			msg := gderrors.ErrMaintenanceMode.Error()
+			if m.Reason != "" {
+				msg += ": " + m.Reason
+			}
+			restutils.SendHTTPError(ctx, w, http.StatusServiceUnavailable, msg)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
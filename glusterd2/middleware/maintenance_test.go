@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/maintenance"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenance(t *testing.T) {
+	mode := &maintenance.Mode{}
+	getMaintenance = func(ctx context.Context) (*maintenance.Mode, error) {
+		return mode, nil
+	}
+	defer func() { getMaintenance = maintenance.Get }()
+
+	h := Maintenance(GetTestHandler())
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, serve("POST", "/v1/volumes").Code)
+
+	mode = &maintenance.Mode{Enabled: true, Reason: "upgrade to 4.2"}
+	rec := serve("POST", "/v1/volumes")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var resp api.ErrorResp
+	require.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, "upgrade to 4.2")
+
+	assert.Equal(t, http.StatusServiceUnavailable, serve("DELETE", "/v1/volumes/vol1").Code)
+
+	// Requests which don't change anything, and those taking the cluster
+	// out of maintenance mode are allowed
+	assert.Equal(t, http.StatusOK, serve("GET", "/v1/volumes").Code)
+	assert.Equal(t, http.StatusOK, serve("POST", "/v1/maintenance").Code)
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/metrics"
+)
+
+// metricsRecorder records the status code of a response
+type metricsRecorder struct {
+	statusRecorder
+	code int
+}
+
+func (rec *metricsRecorder) WriteHeader(code int) {
+	if rec.code == 0 {
+		rec.code = code
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+// Metrics is a middleware which updates the Prometheus metrics of the
+// requests served
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &metricsRecorder{statusRecorder: statusRecorder{w}}
+		next.ServeHTTP(rec, r)
+
+		code := rec.code
+		if code == 0 {
+			code = http.StatusOK
+		}
+		metrics.RESTRequests.WithLabelValues(r.Method, strconv.Itoa(code)).Inc()
+		metrics.RESTRequestDuration.WithLabelValues(r.Method).Observe(time.Since(start).Seconds())
+	})
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	var m dto.Metric
+	require.Nil(t, c.Write(&m))
+	return m.GetCounter().GetValue()
+}
+
+func TestMetrics(t *testing.T) {
+	notFound := metrics.RESTRequests.WithLabelValues("DELETE", "404")
+	ok := metrics.RESTRequests.WithLabelValues("DELETE", "200")
+	notFoundBefore, okBefore := counterValue(t, notFound), counterValue(t, ok)
+
+	h := Metrics(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/volumes/vol2" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("DELETE", "/v1/volumes/vol1", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("DELETE", "/v1/volumes/vol2", nil))
+
+	assert.Equal(t, notFoundBefore+1, counterValue(t, notFound))
+	// Responses written without a status are 200 OK
+	assert.Equal(t, okBefore+1, counterValue(t, ok))
+}
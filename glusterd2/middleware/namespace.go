@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/snapshot"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+)
+
+var (
+	getVolume   = volume.GetVolume
+	getSnapshot = snapshot.GetSnapshot
+)
+
+// RequireNamespace is a middleware for handlers of requests on a volume or
+// a snapshot, named by the volname or snapname route variable. Volumes and
+// snapshots of namespaces the requester can't access are reported as not
+// found, so that their names are not disclosed to other tenants. Errors
+// looking them up are left to the handler.
+func RequireNamespace(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if gdctx.GetReqNamespaces(ctx) == nil {
+			next(w, r)
+			return
+		}
+
+		vars := mux.Vars(r)
+		if volname, ok := vars["volname"]; ok {
+			v, err := getVolume(volname)
+			if err == nil && !gdctx.ReqNamespaceAllowed(ctx, v.GetNamespace()) {
+				restutils.SendHTTPError(ctx, w, http.StatusNotFound, gderrors.ErrVolNotFound)
+				return
+			}
+		}
+		if snapname, ok := vars["snapname"]; ok {
+			s, err := getSnapshot(snapname)
+			if err == nil && !gdctx.ReqNamespaceAllowed(ctx, s.SnapVolinfo.GetNamespace()) {
+				restutils.SendHTTPError(ctx, w, http.StatusNotFound, gderrors.ErrSnapNotFound)
+				return
+			}
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/snapshot"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireNamespace(t *testing.T) {
+	getVolume = func(name string) (*volume.Volinfo, error) {
+		switch name {
+		case "vol1":
+			return &volume.Volinfo{Name: name}, nil
+		case "vol2":
+			return &volume.Volinfo{Name: name, Namespace: "team1"}, nil
+		}
+		return nil, gderrors.ErrVolNotFound
+	}
+	getSnapshot = func(name string) (*snapshot.Snapinfo, error) {
+		return &snapshot.Snapinfo{SnapVolinfo: volume.Volinfo{Name: name, Namespace: "team1"}}, nil
+	}
+	defer func() {
+		getVolume = volume.GetVolume
+		getSnapshot = snapshot.GetSnapshot
+	}()
+
+	router := mux.NewRouter()
+	router.Handle("/v1/volumes/{volname}", RequireNamespace(GetTestHandler()))
+	router.Handle("/v1/snapshots/{snapname}", RequireNamespace(GetTestHandler()))
+	serve := func(namespaces []string, path string) int {
+		r := httptest.NewRequest("GET", path, nil)
+		if namespaces != nil {
+			r = r.WithContext(gdctx.WithReqNamespaces(context.Background(), namespaces))
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// Requesters without namespaces can access everything
+	assert.Equal(t, http.StatusOK, serve(nil, "/v1/volumes/vol2"))
+
+	assert.Equal(t, http.StatusOK, serve([]string{"team1"}, "/v1/volumes/vol2"))
+	assert.Equal(t, http.StatusNotFound, serve([]string{"team1"}, "/v1/volumes/vol1"))
+	assert.Equal(t, http.StatusOK, serve([]string{"default"}, "/v1/volumes/vol1"))
+	assert.Equal(t, http.StatusOK, serve([]string{"team1"}, "/v1/snapshots/snap1"))
+	assert.Equal(t, http.StatusNotFound, serve([]string{"team2"}, "/v1/snapshots/snap1"))
+
+	// Volumes which can't be looked up are left to the handler
+	assert.Equal(t, http.StatusOK, serve([]string{"team1"}, "/v1/volumes/vol3"))
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/opversion"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+)
+
+// getOpVersion returns the op-version of the cluster, tests replace it to
+// set the op-version
+var getOpVersion = opversion.Get
+
+// RequireOpVersion is a middleware for handlers of routes using features
+// introduced in the given op-version. Requests fail with 409 Conflict until
+// the op-version of the cluster is raised to it, once all peers support the
+// features.
+func RequireOpVersion(required int, next http.HandlerFunc) http.HandlerFunc {
+	if required == 0 {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		current, err := getOpVersion()
+		if err != nil {
+			gdctx.GetReqLogger(ctx).WithError(err).Error("failed to get op-version of the cluster")
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		if current < required {
+			restutils.SendHTTPError(ctx, w, http.StatusConflict, gderrors.ErrOpVersionTooLow)
+			return
+		}
+		next(w, r)
+	}
+}
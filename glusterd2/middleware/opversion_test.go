@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/opversion"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireOpVersion(t *testing.T) {
+	current := 50000
+	getOpVersion = func() (int, error) {
+		return current, nil
+	}
+	defer func() { getOpVersion = opversion.Get }()
+
+	h := RequireOpVersion(50100, GetTestHandler())
+	serve := func() int {
+		rec := httptest.NewRecorder()
+		h(rec, httptest.NewRequest("PATCH", "/v1/peers/abc", nil))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusConflict, serve())
+
+	current = 50100
+	assert.Equal(t, http.StatusOK, serve())
+}
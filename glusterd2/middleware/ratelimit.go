@@ -0,0 +1,189 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	config "github.com/spf13/viper"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// clientIdleTimeout is the time after which the limiter of a client
+	// which hasn't made any request is dropped
+	clientIdleTimeout = 10 * time.Minute
+	// retryAfterBusy is the number of seconds clients are asked to wait
+	// when too many mutating requests are being served
+	retryAfterBusy = 1
+)
+
+// busySlotsKey is the context key of the slots of the concurrent
+// mutations limit which served a request
+type busySlotsKey struct{}
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimiter limits the rate of requests to the REST server, from every
+// client and from all of them together, and the number of mutating requests
+// served at a time. Requests over the limits fail with 429 Too Many Requests
+// and a Retry-After header.
+type RateLimiter struct {
+	clientRate  rate.Limit
+	clientBurst int
+	global      *rate.Limiter
+	// busy has a slot for every mutating request being served
+	busy chan struct{}
+
+	mu        sync.Mutex
+	clients   map[string]*clientLimiter
+	lastPrune time.Time
+}
+
+// burst returns the burst configured as option, which defaults to the rate
+// rounded up
+func burst(option string, limit float64) int {
+	if b := config.GetInt(option); b > 0 {
+		return b
+	}
+	return int(math.Ceil(limit))
+}
+
+// NewRateLimiter returns a RateLimiter with the limits configured by the
+// ratelimit, ratelimitburst, globalratelimit, globalratelimitburst and
+// maxconcurrentmutations options. A limit which is not set is not enforced.
+func NewRateLimiter() *RateLimiter {
+	rl := &RateLimiter{
+		clients: make(map[string]*clientLimiter),
+	}
+
+	if limit := config.GetFloat64("ratelimit"); limit > 0 {
+		rl.clientRate = rate.Limit(limit)
+		rl.clientBurst = burst("ratelimitburst", limit)
+	}
+	if limit := config.GetFloat64("globalratelimit"); limit > 0 {
+		rl.global = rate.NewLimiter(rate.Limit(limit), burst("globalratelimitburst", limit))
+	}
+	if n := config.GetInt("maxconcurrentmutations"); n > 0 {
+		rl.busy = make(chan struct{}, n)
+	}
+	return rl
+}
+
+// clientLimiter returns the limiter of the client with the given address,
+// dropping the limiters of idle clients once in a while
+func (rl *RateLimiter) clientLimiter(client string) *rate.Limiter {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(rl.lastPrune) > clientIdleTimeout {
+		for k, c := range rl.clients {
+			if now.Sub(c.lastSeen) > clientIdleTimeout {
+				delete(rl.clients, k)
+			}
+		}
+		rl.lastPrune = now
+	}
+
+	c, ok := rl.clients[client]
+	if !ok {
+		c = &clientLimiter{limiter: rate.NewLimiter(rl.clientRate, rl.clientBurst)}
+		rl.clients[client] = c
+	}
+	c.lastSeen = now
+	return c.limiter
+}
+
+// reserve takes a token from the limiters of the client and the global
+// limiter. If a token isn't available from any of them, no token is taken
+// and the time after which the request may be retried is returned.
+func (rl *RateLimiter) reserve(client string) time.Duration {
+	now := time.Now()
+	var reservations []*rate.Reservation
+	if rl.clientRate > 0 {
+		reservations = append(reservations, rl.clientLimiter(client).ReserveN(now, 1))
+	}
+	if rl.global != nil {
+		reservations = append(reservations, rl.global.ReserveN(now, 1))
+	}
+
+	var wait time.Duration
+	for _, r := range reservations {
+		if d := r.DelayFrom(now); d > wait {
+			wait = d
+		}
+	}
+	if wait > 0 {
+		for _, r := range reservations {
+			r.CancelAt(now)
+		}
+	}
+	return wait
+}
+
+func sendTooManyRequests(w http.ResponseWriter, r *http.Request, retryAfter int, err error) {
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	restutils.SendHTTPError(r.Context(), w, http.StatusTooManyRequests, err)
+}
+
+// Handler is a middleware enforcing the limits of the RateLimiter. Clients
+// are told apart by their address.
+func (rl *RateLimiter) Handler(next http.Handler) http.Handler {
+	if rl.clientRate == 0 && rl.global == nil && rl.busy == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			client = r.RemoteAddr
+		}
+
+		if wait := rl.reserve(client); wait > 0 {
+			retryAfter := int(math.Ceil(wait.Seconds()))
+			sendTooManyRequests(w, r, retryAfter, gderrors.ErrTooManyRequests)
+			return
+		}
+
+		if rl.busy != nil && isMutatingMethod(r.Method) {
+			select {
+			case rl.busy <- struct{}{}:
+				defer func() { <-rl.busy }()
+				r = r.WithContext(context.WithValue(r.Context(), busySlotsKey{}, rl.busy))
+			default:
+				sendTooManyRequests(w, r, retryAfterBusy, gderrors.ErrTooManyConcurrentRequests)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// acquireBusySlot waits for a slot of the concurrent mutations limit which
+// served the request of ctx, for work of the request going on after it is
+// served. It returns the func freeing the slot, which does nothing if the
+// request was not limited.
+func acquireBusySlot(ctx context.Context) (func(), error) {
+	busy, ok := ctx.Value(busySlotsKey{}).(chan struct{})
+	if !ok {
+		return func() {}, nil
+	}
+	select {
+	case busy <- struct{}{}:
+		return func() { <-busy }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
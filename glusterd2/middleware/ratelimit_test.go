@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	config "github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// setLimits sets the given limits and returns a function to unset them
+func setLimits(limits map[string]interface{}) func() {
+	for k, v := range limits {
+		config.Set(k, v)
+	}
+	return func() {
+		for k := range limits {
+			config.Set(k, 0)
+		}
+	}
+}
+
+func serve(h http.Handler, method, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/v1/volumes", nil)
+	req.RemoteAddr = remoteAddr
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimiterClient(t *testing.T) {
+	defer setLimits(map[string]interface{}{"ratelimit": 0.001, "ratelimitburst": 2})()
+	h := NewRateLimiter().Handler(GetTestHandler())
+
+	assert.Equal(t, http.StatusOK, serve(h, "GET", "192.0.2.1:1000").Code)
+	assert.Equal(t, http.StatusOK, serve(h, "GET", "192.0.2.1:1001").Code)
+
+	rec := serve(h, "GET", "192.0.2.1:1002")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	// Other clients have their own limit
+	assert.Equal(t, http.StatusOK, serve(h, "GET", "192.0.2.2:1000").Code)
+}
+
+func TestRateLimiterGlobal(t *testing.T) {
+	defer setLimits(map[string]interface{}{"ratelimit": 0.001, "ratelimitburst": 2, "globalratelimit": 0.001, "globalratelimitburst": 2})()
+	h := NewRateLimiter().Handler(GetTestHandler())
+
+	assert.Equal(t, http.StatusOK, serve(h, "GET", "192.0.2.1:1000").Code)
+	assert.Equal(t, http.StatusOK, serve(h, "GET", "192.0.2.2:1000").Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve(h, "GET", "192.0.2.3:1000").Code)
+
+	// A request rejected by the global limit doesn't use up the limit of
+	// its client
+	rl := NewRateLimiter()
+	rh := rl.Handler(GetTestHandler())
+	assert.Equal(t, http.StatusOK, serve(rh, "GET", "192.0.2.1:1000").Code)
+	assert.Equal(t, http.StatusOK, serve(rh, "GET", "192.0.2.2:1000").Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve(rh, "GET", "192.0.2.1:1000").Code)
+	rl.global = nil
+	assert.Equal(t, http.StatusOK, serve(rh, "GET", "192.0.2.1:1000").Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve(rh, "GET", "192.0.2.1:1000").Code)
+}
+
+func TestRateLimiterConcurrentMutations(t *testing.T) {
+	defer setLimits(map[string]interface{}{"maxconcurrentmutations": 1})()
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	h := NewRateLimiter().Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			entered <- struct{}{}
+			<-release
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	done := make(chan int)
+	go func() {
+		done <- serve(h, "POST", "192.0.2.1:1000").Code
+	}()
+	<-entered
+
+	rec := serve(h, "POST", "192.0.2.2:1000")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	// Requests which don't change anything are not limited
+	assert.Equal(t, http.StatusOK, serve(h, "GET", "192.0.2.2:1000").Code)
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-done)
+
+	// The slot is free again once the request is served
+	go func() {
+		done <- serve(h, "POST", "192.0.2.2:1000").Code
+	}()
+	<-entered
+	assert.Equal(t, http.StatusOK, <-done)
+}
+
+func TestAcquireBusySlot(t *testing.T) {
+	defer setLimits(map[string]interface{}{"maxconcurrentmutations": 1})()
+
+	// Requests which were not limited have no slot to take
+	release, err := acquireBusySlot(context.Background())
+	assert.NoError(t, err)
+	release()
+
+	var reqCtx context.Context
+	h := NewRateLimiter().Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqCtx = r.Context()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	assert.Equal(t, http.StatusAccepted, serve(h, "POST", "192.0.2.1:1000").Code)
+
+	// Work going on after the request is served takes a slot
+	release, err = acquireBusySlot(reqCtx)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, serve(h, "POST", "192.0.2.2:1000").Code)
+
+	ctx, cancel := context.WithCancel(reqCtx)
+	cancel()
+	_, err = acquireBusySlot(ctx)
+	assert.Equal(t, context.Canceled, err)
+
+	release()
+	assert.Equal(t, http.StatusAccepted, serve(h, "POST", "192.0.2.2:1000").Code)
+}
@@ -2,11 +2,27 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 
 	"go.opencensus.io/plugin/ochttp"
 )
 
-// Tracing is a http middleware to be use for trace incoming http.Request
+// isEventStream returns true if the request asks for a stream of
+// Server-Sent Events
+func isEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// Tracing is a http middleware to be use for trace incoming http.Request.
+// Event streams are not traced, as they last as long as the client wants
+// and need to hijack their connection, which the tracing writer hides.
 func Tracing(next http.Handler) http.Handler {
-	return &ochttp.Handler{Handler: next}
+	traced := &ochttp.Handler{Handler: next}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isEventStream(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		traced.ServeHTTP(w, r)
+	})
 }
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsEventStream(t *testing.T) {
+	tests := []struct {
+		accept string
+		stream bool
+	}{
+		{"", false},
+		{"application/json", false},
+		{"text/event-stream", true},
+		{"text/event-stream, application/json;q=0.5", true},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/v1/events/stream", nil)
+		r.Header.Set("Accept", tt.accept)
+		assert.Equal(t, tt.stream, isEventStream(r), tt.accept)
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/utils"
+	"github.com/gluster/glusterd2/pkg/validation"
+)
+
+// ValidateRequest is a middleware for handlers of requests of the given
+// type, as set in their route. Requests whose fields break the rules set
+// in the `valid` tags of the type fail with an error for every such field,
+// before they reach the handler. Requests with no body or whose body can't
+// be decoded are left to the handler.
+func ValidateRequest(reqType string, next http.HandlerFunc) http.HandlerFunc {
+	if reqType == "" {
+		return next
+	}
+	t := utils.GetType(reqType)
+	if t == nil {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Body == nil {
+			next(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		body, err := ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		if len(bytes.TrimSpace(body)) == 0 {
+			next(w, r)
+			return
+		}
+
+		req := reflect.New(t).Interface()
+		if err := json.Unmarshal(body, req); err != nil {
+			next(w, r)
+			return
+		}
+
+		if err := validation.Validate(req); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRequest(t *testing.T) {
+	var served string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		// The body is still there for the handler
+		body, _ := ioutil.ReadAll(r.Body)
+		served = string(body)
+		w.WriteHeader(http.StatusCreated)
+	}
+	h := ValidateRequest(utils.GetTypeString((*api.VolExpandReq)(nil)), handler)
+
+	serveBody := func(body string) *httptest.ResponseRecorder {
+		served = ""
+		req := httptest.NewRequest("POST", "/v1/volumes/vol1/expand", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serveBody(`{"replica": 3}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, `{"replica": 3}`, served)
+
+	rec = serveBody(`{"replica": 5}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Empty(t, served)
+
+	var resp api.ErrorResp
+	require.Nil(t, json.NewDecoder(rec.Body).Decode(&resp))
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, int(api.ErrFieldOutOfRange), resp.Errors[0].Code)
+	assert.Equal(t, "replica", resp.Errors[0].Field)
+
+	// Bodies which can't be decoded are left to the handler
+	rec = serveBody(`{"replica": "three"}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, `{"replica": "three"}`, served)
+
+	// Routes with no request type are not validated
+	assert.NotNil(t, ValidateRequest("", handler))
+}
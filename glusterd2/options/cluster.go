@@ -3,16 +3,23 @@ package options
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/store"
 	"github.com/gluster/glusterd2/pkg/errors"
-
-	"strconv"
+	"github.com/gluster/glusterd2/version"
 )
 
 const (
 	clusterOptionsKey string = "clusteroptions"
+
+	// ClusterOptionsLockID is the cluster lock held by everything which
+	// updates the cluster options, as they are all stored under one key
+	ClusterOptionsLockID = "clusteroptions"
 )
 
 // ClusterOption reperesents a single cluster wide option
@@ -21,23 +28,73 @@ type ClusterOption struct {
 	DefaultValue string
 	Type         OptionType
 	ValidateFunc validateFunc `json:"-"`
+	Description  string
 }
 
 type validateFunc func(string, string) error
 
 // ClusterOptMap contains list of supported cluster-wide options, default values and value types
 var ClusterOptMap = map[string]*ClusterOption{
-	"cluster.shared-storage":         {"cluster.shared-storage", "off", OptionTypeBool, nil},
-	"cluster.op-version":             {"cluster.op-version", strconv.Itoa(gdctx.OpVersion), OptionTypeInt, nil},
-	"cluster.max-op-version":         {"cluster.max-op-version", strconv.Itoa(gdctx.OpVersion), OptionTypeInt, nil},
-	"cluster.brick-multiplex":        {"cluster.brick-multiplex", "off", OptionTypeBool, nil},
-	"cluster.max-bricks-per-process": {"cluster.max-bricks-per-process", "250", OptionTypeInt, nil},
-	"cluster.localtime-logging":      {"cluster.localtime-logging", "off", OptionTypeBool, nil},
+	"cluster.shared-storage": {"cluster.shared-storage", "off", OptionTypeBool, nil,
+		"Create and mount the shared storage volume on all peers"},
+	"cluster.op-version": {"cluster.op-version", strconv.Itoa(version.MinOpVersion), OptionTypeInt, nil,
+		"Operating version of the cluster, which can only be raised up to the op-version supported by all peers"},
+	"cluster.max-op-version": {"cluster.max-op-version", strconv.Itoa(gdctx.OpVersion), OptionTypeInt, nil,
+		"Highest operating version supported by the peers of the cluster"},
+	"cluster.brick-multiplex": {"cluster.brick-multiplex", "off", OptionTypeBool, nil,
+		"Run the bricks of all volumes of a peer in as few processes as possible"},
+	"cluster.max-bricks-per-process": {"cluster.max-bricks-per-process", "250", OptionTypeInt, nil,
+		"Maximum number of bricks run in a single process when brick multiplexing is on"},
+	"cluster.brick-port-range": {"cluster.brick-port-range", "49152-60999", OptionTypeStr, nil,
+		"Range of ports brick processes listen on, as <min>-<max>, unless set for a peer with brick-port-range"},
+	"cluster.localtime-logging": {"cluster.localtime-logging", "off", OptionTypeBool, nil,
+		"Log time stamps in local time instead of UTC"},
+	// message buses events are published to, see plugins/events
+	"events.bus-events": {"events.bus-events", "", OptionTypeStr, nil,
+		"Comma separated names of the events published to the message buses, or their namespaces like volume, all the events if not set"},
+	"events.kafka.brokers": {"events.kafka.brokers", "", OptionTypeStr, nil,
+		"Comma separated host:port of the Kafka brokers events are published to, events are not published to Kafka if not set"},
+	"events.kafka.topic": {"events.kafka.topic", "gluster-events", OptionTypeStr, nil,
+		"Kafka topic events are published to, keyed by the name of the event"},
+	"events.kafka.tls": {"events.kafka.tls", "off", OptionTypeBool, nil,
+		"Connect to the Kafka brokers over TLS"},
+	"events.nats.url": {"events.nats.url", "", OptionTypeStr, nil,
+		"Comma separated URLs of the NATS servers events are published to, events are not published to NATS if not set"},
+	"events.nats.subject": {"events.nats.subject", "gluster.events", OptionTypeStr, nil,
+		"Prefix of the NATS subjects events are published to, followed by a dot and the name of the event"},
+	"events.amqp.url": {"events.amqp.url", "", OptionTypeStr, nil,
+		"URL of the AMQP 0-9-1 broker events are published to, events are not published to AMQP if not set"},
+	"events.amqp.exchange": {"events.amqp.exchange", "gluster.events", OptionTypeStr, nil,
+		"AMQP topic exchange events are published to, with the name of the event as routing key"},
 	// setting cluster options for block hosting volume
-	"block-hosting-volume-size":          {"block-hosting-volume-size", "5GiB", OptionTypeSizeList, nil},
-	"auto-create-block-hosting-volumes":  {"auto-create-block-hosting-volumes", "true", OptionTypeBool, nil},
-	"block-hosting-volume-replica-count": {"block-hosting-volume-replica-count", "3", OptionTypeInt, nil},
-	"block-hosting-volume-type":          {"block-hosting-volume-type", "Replicate", OptionTypeStr, nil},
+	"block-hosting-volume-size": {"block-hosting-volume-size", "5GiB", OptionTypeSizeList, nil,
+		"Size of the block hosting volumes created automatically"},
+	"auto-create-block-hosting-volumes": {"auto-create-block-hosting-volumes", "true", OptionTypeBool, nil,
+		"Create block hosting volumes automatically when no existing one has room for a block volume"},
+	"block-hosting-volume-replica-count": {"block-hosting-volume-replica-count", "3", OptionTypeInt, nil,
+		"Replica count of the block hosting volumes created automatically"},
+	"block-hosting-volume-type": {"block-hosting-volume-type", "Replicate", OptionTypeStr, nil,
+		"Type of the block hosting volumes created automatically"},
+}
+
+// Validate checks if value can be set as the value of the cluster option,
+// first against the type of the option and then with its validation
+// function, if any.
+func (o *ClusterOption) Validate(value string) error {
+	if o.Type != OptionTypeAny {
+		opt := Option{Key: []string{o.Key}, Type: o.Type}
+		if err := opt.Validate(value); err != nil {
+			return err
+		}
+	}
+	if o.ValidateFunc != nil {
+		return o.ValidateFunc(o.Key, value)
+	}
+	return nil
+}
+
+func validateReadOnly(key, value string) error {
+	return fmt.Errorf("%s can not be set", key)
 }
 
 // RegisterClusterOpValidationFunc registers a validation function for provided
@@ -47,9 +104,50 @@ func RegisterClusterOpValidationFunc(option string, fn validateFunc) {
 	ClusterOptMap[option].ValidateFunc = fn
 }
 
+// secretClusterOptions are the cluster options whose values are URLs which
+// may carry credentials
+var secretClusterOptions = make(map[string]bool)
+
+// redactedPassword replaces the passwords of the URLs of secret cluster
+// options when they are listed
+const redactedPassword = "xxxxx"
+
+// RegisterSecretClusterOption marks the cluster option as holding comma
+// separated URLs which may carry credentials. The passwords in the URLs are
+// redacted when the option is listed.
+func RegisterSecretClusterOption(option string) {
+	secretClusterOptions[option] = true
+}
+
+// RedactClusterOption returns the value of the cluster option as it is shown
+// to users, with the passwords in the URLs of secret options redacted
+func RedactClusterOption(key, value string) string {
+	if !secretClusterOptions[key] || value == "" {
+		return value
+	}
+
+	urls := strings.Split(value, ",")
+	for i, u := range urls {
+		parsed, err := url.Parse(strings.TrimSpace(u))
+		if err != nil {
+			// Don't show what can't be parsed, it may be a password
+			urls[i] = redactedPassword
+			continue
+		}
+		if _, ok := parsed.User.Password(); ok {
+			parsed.User = url.UserPassword(parsed.User.Username(), redactedPassword)
+			urls[i] = parsed.String()
+		}
+	}
+	return strings.Join(urls, ",")
+}
+
 // ClusterOptions contains cluster-wide attributes
 type ClusterOptions struct {
 	Options map[string]string
+	// VolumeOptions are the volume options applied to every new volume
+	// unless the volume create request sets them explicitly
+	VolumeOptions map[string]string
 }
 
 // GetClusterOptions gets cluster options from store.
@@ -80,9 +178,13 @@ func GetClusterOption(key string) (string, error) {
 		return "", errors.ErrInvalidClusterOption
 	}
 
-	c, err := GetClusterOptions()
-	if err != nil && err != errors.ErrClusterOptionsNotFound {
-		return "", err
+	c, ok := cachedClusterOptions()
+	if !ok {
+		var err error
+		c, err = GetClusterOptions()
+		if err != nil && err != errors.ErrClusterOptionsNotFound {
+			return "", err
+		}
 	}
 
 	result := globalopt.DefaultValue
@@ -95,6 +197,21 @@ func GetClusterOption(key string) (string, error) {
 	return result, nil
 }
 
+// GetVolumeDefaultOptions returns the cluster wide default volume options
+func GetVolumeDefaultOptions() (map[string]string, error) {
+	c, err := GetClusterOptions()
+	if err == errors.ErrClusterOptionsNotFound {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if c.VolumeOptions == nil {
+		return map[string]string{}, nil
+	}
+	return c.VolumeOptions, nil
+}
+
 // UpdateClusterOptions stores cluster options in store.
 func UpdateClusterOptions(c *ClusterOptions) error {
 	b, err := json.Marshal(c)
@@ -102,6 +219,17 @@ func UpdateClusterOptions(c *ClusterOptions) error {
 		return err
 	}
 
-	_, err = store.Put(context.TODO(), clusterOptionsKey, string(b))
-	return err
+	resp, err := store.Put(context.TODO(), clusterOptionsKey, string(b))
+	if err != nil {
+		return err
+	}
+
+	// Let the change take effect on this peer right away, without waiting
+	// for the watcher to see it
+	setCache(c, resp.Header.Revision, false)
+	return nil
+}
+
+func init() {
+	RegisterClusterOpValidationFunc("cluster.max-op-version", validateReadOnly)
 }
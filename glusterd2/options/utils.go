@@ -7,6 +7,16 @@ import (
 	"github.com/gluster/glusterd2/pkg/utils"
 )
 
+// UserOptionPrefix is the prefix of free-form volume options which no xlator
+// uses, like user.smb. Integrations like Samba are enabled on volumes with
+// them, and any value can be set.
+const UserOptionPrefix = "user."
+
+// IsUserOption returns true if the key is of a free-form user option
+func IsUserOption(k string) bool {
+	return strings.HasPrefix(k, UserOptionPrefix) && len(k) > len(UserOptionPrefix)
+}
+
 // SplitKey returns three strings by breaking key of the form
 // [<graph>].<xlator>.<option-name> into its constituents. <graph> is optional.
 func SplitKey(k string) (string, string, string) {
@@ -0,0 +1,176 @@
+package options
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+// ClusterOptionWatchFunc is called with the new value of a cluster option
+// when it changes. The default value is passed when the option is reset.
+type ClusterOptionWatchFunc func(key, value string)
+
+var (
+	watchersMutex sync.Mutex
+	watchers      = make(map[string][]ClusterOptionWatchFunc)
+
+	// cache is the local copy of the cluster options kept up to date by the
+	// watcher. It is only used while valid is set, and rev is the store
+	// revision it was last updated at.
+	cache struct {
+		sync.RWMutex
+		valid bool
+		rev   int64
+		opts  ClusterOptions
+	}
+
+	watchStop chan struct{}
+	watchWg   sync.WaitGroup
+)
+
+// RegisterClusterOptionWatcher registers fn to be called on this peer
+// whenever the value of the cluster option changes, on whichever peer it was
+// set.
+func RegisterClusterOptionWatcher(key string, fn ClusterOptionWatchFunc) {
+	watchersMutex.Lock()
+	defer watchersMutex.Unlock()
+	watchers[key] = append(watchers[key], fn)
+}
+
+func effectiveValue(c *ClusterOptions, key string) string {
+	if v, ok := c.Options[key]; ok {
+		return v
+	}
+	if opt, ok := ClusterOptMap[key]; ok {
+		return opt.DefaultValue
+	}
+	return ""
+}
+
+// cachedClusterOptions returns the local copy of the cluster options, and
+// false if there is no up to date copy.
+func cachedClusterOptions() (*ClusterOptions, bool) {
+	cache.RLock()
+	defer cache.RUnlock()
+	if !cache.valid {
+		return nil, false
+	}
+	c := cache.opts
+	return &c, true
+}
+
+// setCache replaces the local copy of the cluster options, unless it is
+// newer than rev, and calls the watchers of the options whose value changed.
+// Unless load is set, the copy is only replaced while the watcher keeps it up
+// to date.
+func setCache(c *ClusterOptions, rev int64, load bool) {
+	cache.Lock()
+	if rev < cache.rev || (!load && !cache.valid) {
+		cache.Unlock()
+		return
+	}
+	old := cache.opts
+	loaded := cache.rev != 0
+	cache.opts = *c
+	cache.rev = rev
+	cache.valid = true
+	cache.Unlock()
+
+	if !loaded {
+		return
+	}
+
+	watchersMutex.Lock()
+	defer watchersMutex.Unlock()
+	for key, fns := range watchers {
+		oldValue, newValue := effectiveValue(&old, key), effectiveValue(c, key)
+		if oldValue == newValue {
+			continue
+		}
+		log.WithFields(log.Fields{
+			"option": key,
+			"value":  newValue,
+		}).Info("cluster option changed")
+		for _, fn := range fns {
+			fn(key, newValue)
+		}
+	}
+}
+
+func invalidateCache() {
+	cache.Lock()
+	cache.valid = false
+	cache.Unlock()
+}
+
+// watchClusterOptions loads the cluster options and follows their changes
+// until the watch fails or the watcher is stopped. It returns true if the
+// watcher was stopped.
+func watchClusterOptions() bool {
+	defer invalidateCache()
+
+	resp, err := store.Get(store.Store.Ctx(), clusterOptionsKey)
+	if err != nil {
+		log.WithError(err).Error("failed to get cluster options")
+		return false
+	}
+	c := ClusterOptions{}
+	if resp.Count == 1 {
+		if err := json.Unmarshal(resp.Kvs[0].Value, &c); err != nil {
+			log.WithError(err).Error("failed to unmarshal cluster options")
+			return false
+		}
+	}
+	setCache(&c, resp.Header.Revision, true)
+
+	wch := store.Store.Watch(store.Store.Ctx(), clusterOptionsKey, clientv3.WithRev(resp.Header.Revision+1))
+	for {
+		select {
+		case wresp, ok := <-wch:
+			if !ok || wresp.Canceled {
+				return false
+			}
+			for _, ev := range wresp.Events {
+				c := ClusterOptions{}
+				if ev.Type == clientv3.EventTypePut {
+					if err := json.Unmarshal(ev.Kv.Value, &c); err != nil {
+						log.WithError(err).Error("failed to unmarshal cluster options")
+						return false
+					}
+				}
+				setCache(&c, ev.Kv.ModRevision, true)
+			}
+		case <-watchStop:
+			return true
+		}
+	}
+}
+
+// StartClusterOptionsWatcher starts watching the cluster options in the
+// store, so that changes made on any peer take effect on this peer without a
+// restart. Should only be called after store is up.
+func StartClusterOptionsWatcher() {
+	watchStop = make(chan struct{})
+	watchWg.Add(1)
+	go func() {
+		defer watchWg.Done()
+		for !watchClusterOptions() {
+			select {
+			case <-time.After(time.Second):
+			case <-watchStop:
+				return
+			}
+		}
+	}()
+}
+
+// StopClusterOptionsWatcher stops watching the cluster options
+func StopClusterOptionsWatcher() {
+	close(watchStop)
+	watchWg.Wait()
+}
@@ -0,0 +1,160 @@
+// Package opversion keeps track of the op-version of the cluster, the
+// version of the features the peers of the cluster agree on. It is raised
+// once all peers have been upgraded, and requests using features newer than
+// it are refused, so that peers running an older release are never handed
+// state they don't understand during a rolling upgrade.
+package opversion
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	opVersionKey = "cluster.op-version"
+)
+
+// Get returns the op-version of the cluster
+func Get() (int, error) {
+	value, err := options.GetClusterOption(opVersionKey)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(value)
+}
+
+// Max returns the highest op-version supported by all the peers of the
+// cluster, which the op-version of the cluster can be raised to
+func Max() (int, error) {
+	peers, err := peer.GetPeers()
+	if err != nil {
+		return 0, err
+	}
+
+	max := gdctx.OpVersion
+	for _, p := range peers {
+		if v := p.GetOpVersion(); v < max {
+			max = v
+		}
+	}
+	return max, nil
+}
+
+// Check returns ErrOpVersionTooLow if the op-version of the cluster is lower
+// than required
+func Check(required int) error {
+	current, err := Get()
+	if err != nil {
+		return err
+	}
+	if current < required {
+		return errors.ErrOpVersionTooLow
+	}
+	return nil
+}
+
+func validate(key, value string) error {
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return errors.ErrInvalidIntValue
+	}
+
+	current, err := Get()
+	if err != nil {
+		return err
+	}
+	if v < current {
+		return errors.ErrOpVersionLowered
+	}
+
+	max, err := Max()
+	if err != nil {
+		return err
+	}
+	if v > max {
+		return errors.ErrOpVersionNotSupported
+	}
+	return nil
+}
+
+// Set raises the op-version of the cluster. The op-version can't be lowered,
+// and can't be raised above the op-version supported by all peers.
+func Set(ctx context.Context, v int) error {
+	txn, err := transaction.NewTxnWithLocks(ctx, options.ClusterOptionsLockID)
+	if err != nil {
+		return err
+	}
+	defer txn.Done()
+
+	if err := validate(opVersionKey, strconv.Itoa(v)); err != nil {
+		return err
+	}
+
+	return setLocked(v)
+}
+
+func setLocked(v int) error {
+	c, err := options.GetClusterOptions()
+	if err == errors.ErrClusterOptionsNotFound {
+		c = &options.ClusterOptions{}
+	} else if err != nil {
+		return err
+	}
+	if c.Options == nil {
+		c.Options = make(map[string]string)
+	}
+
+	c.Options[opVersionKey] = strconv.Itoa(v)
+	return options.UpdateClusterOptions(c)
+}
+
+// Init records the op-version of a new cluster, which is the highest
+// op-version supported by its peers. It fails if this peer does not support
+// the op-version of the cluster it is part of. Should only be called after
+// the store is up and the details of this peer are in it.
+func Init() error {
+	txn, err := transaction.NewTxnWithLocks(context.Background(), options.ClusterOptionsLockID)
+	if err != nil {
+		return err
+	}
+	defer txn.Done()
+
+	c, err := options.GetClusterOptions()
+	if err != nil && err != errors.ErrClusterOptionsNotFound {
+		return err
+	}
+
+	if c != nil {
+		if value, ok := c.Options[opVersionKey]; ok {
+			current, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+			if current > gdctx.OpVersion {
+				return errors.ErrOpVersionNotSupported
+			}
+			return nil
+		}
+	}
+
+	// Clusters created before the op-version was recorded run at the
+	// op-version of their oldest peer
+	max, err := Max()
+	if err != nil {
+		return err
+	}
+	log.WithField("op-version", max).Info("setting op-version of the cluster")
+	return setLocked(max)
+}
+
+func init() {
+	options.RegisterClusterOpValidationFunc(opVersionKey, validate)
+}
@@ -4,9 +4,23 @@ package peer
 import (
 	"strings"
 
+	"github.com/gluster/glusterd2/version"
+
 	"github.com/pborman/uuid"
 )
 
+const (
+	// MaintenanceKey is the metadata key set to the reason of the
+	// maintenance while a peer is under maintenance
+	MaintenanceKey = "_maintenance"
+	// MaintenanceSinceKey is the metadata key set to the time the peer
+	// was put under maintenance
+	MaintenanceSinceKey = "_maintenance-since"
+	// legacyZoneKey is the metadata key the zone of a peer was kept in
+	// before peers had a zone of their own
+	legacyZoneKey = "_zone"
+)
+
 // Peer reperesents a GlusterD
 type Peer struct {
 	ID              uuid.UUID
@@ -14,6 +28,11 @@ type Peer struct {
 	PeerAddresses   []string
 	ClientAddresses []string
 	Metadata        map[string]string
+	Zone            string
+	Rack            string
+	Tags            map[string]string
+	// OpVersion is the highest op-version supported by the peer
+	OpVersion int
 }
 
 // ETCDConfig represents the structure which holds the ETCD env variables &
@@ -37,3 +56,36 @@ func (p *Peer) MetadataSize() int {
 	}
 	return size
 }
+
+// InMaintenance returns true if the peer is under maintenance
+func (p *Peer) InMaintenance() bool {
+	_, ok := p.Metadata[MaintenanceKey]
+	return ok
+}
+
+// GetZone returns the zone of the peer. Peers with no zone are in a zone of
+// their own, named after their ID.
+func (p *Peer) GetZone() string {
+	zone := strings.TrimSpace(p.Zone)
+	if zone == "" {
+		zone = strings.TrimSpace(p.Metadata[legacyZoneKey])
+	}
+	if zone == "" {
+		zone = p.ID.String()
+	}
+	return zone
+}
+
+// SetZone sets the zone of the peer
+func (p *Peer) SetZone(zone string) {
+	p.Zone = zone
+	delete(p.Metadata, legacyZoneKey)
+}
+
+// GetOpVersion returns the highest op-version supported by the peer
+func (p *Peer) GetOpVersion() int {
+	if p.OpVersion == 0 {
+		return version.MinOpVersion
+	}
+	return p.OpVersion
+}
@@ -47,6 +47,7 @@ func AddSelfDetails() error {
 		ID:            gdctx.MyUUID,
 		Name:          gdctx.HostName,
 		PeerAddresses: []string{config.GetString("peeraddress")},
+		OpVersion:     gdctx.OpVersion,
 	}
 
 	p.ClientAddresses, err = normalizeAddrs()
@@ -57,10 +58,12 @@ func AddSelfDetails() error {
 	peerInfo, err := GetPeer(gdctx.MyUUID.String())
 	if err == errors.ErrPeerNotFound {
 		p.Metadata = make(map[string]string)
-		p.Metadata["_zone"] = p.ID.String()
 
 	} else if err == nil && peerInfo != nil {
 		p.Metadata = peerInfo.Metadata
+		p.Zone = peerInfo.Zone
+		p.Rack = peerInfo.Rack
+		p.Tags = peerInfo.Tags
 
 		found := utils.StringInSlice(p.PeerAddresses[0], peerInfo.PeerAddresses)
 		if !found {
@@ -18,6 +18,9 @@ import (
 
 const (
 	peerPrefix string = "peers/"
+	// StorePrefix is the prefix of the keys of the peers in the store,
+	// whose changes can be followed with store.WatchPrefix
+	StorePrefix = peerPrefix
 )
 
 // metadataFilter is a filter type
@@ -159,6 +162,20 @@ func GetPeers(filterParams ...map[string]string) ([]*Peer, error) {
 	return peers, nil
 }
 
+// GetPeersVersion returns a version of the peers, which changes whenever a
+// peer is added, changed or deleted, or comes up or goes down
+func GetPeersVersion() (string, error) {
+	peersVersion, err := store.PrefixVersion(context.TODO(), peerPrefix)
+	if err != nil {
+		return "", err
+	}
+	aliveVersion, err := store.PrefixVersion(context.TODO(), store.LivenessKeyPrefix)
+	if err != nil {
+		return "", err
+	}
+	return peersVersion + "-" + aliveVersion, nil
+}
+
 // GetPeerIDs returns peer id (uuid) of all peers in the store
 func GetPeerIDs() ([]uuid.UUID, error) {
 	resp, err := store.Get(context.TODO(), peerPrefix, clientv3.WithPrefix())
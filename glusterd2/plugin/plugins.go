@@ -5,17 +5,22 @@ package plugin
 import (
 	"github.com/gluster/glusterd2/plugins/bitrot"
 	"github.com/gluster/glusterd2/plugins/blockvolume"
+	"github.com/gluster/glusterd2/plugins/csi"
 	"github.com/gluster/glusterd2/plugins/device"
 	"github.com/gluster/glusterd2/plugins/events"
+	"github.com/gluster/glusterd2/plugins/ganesha"
 	"github.com/gluster/glusterd2/plugins/georeplication"
 	"github.com/gluster/glusterd2/plugins/glustershd"
+	"github.com/gluster/glusterd2/plugins/heketi"
 	"github.com/gluster/glusterd2/plugins/quota"
 	"github.com/gluster/glusterd2/plugins/rebalance"
+	"github.com/gluster/glusterd2/plugins/smb"
 	"github.com/gluster/glusterd2/plugins/tracemgmt"
 
 	// ensure init() of non-plugins also gets executed
 	_ "github.com/gluster/glusterd2/plugins/afr"
 	_ "github.com/gluster/glusterd2/plugins/dht"
+	_ "github.com/gluster/glusterd2/plugins/disperse"
 )
 
 // PluginsList is a list of plugins which implements GlusterdPlugin interface
@@ -29,4 +34,8 @@ var PluginsList = []GlusterdPlugin{
 	&rebalance.Plugin{},
 	&blockvolume.BlockVolume{},
 	&tracemgmt.Plugin{},
+	&ganesha.Plugin{},
+	&smb.Plugin{},
+	&csi.Plugin{},
+	&heketi.Plugin{},
 }
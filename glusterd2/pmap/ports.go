@@ -0,0 +1,187 @@
+package pmap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/store"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+const (
+	// PortRangeFlag is the flag to set the range of ports allocated to
+	// the brick processes of this peer, overriding the cluster option
+	PortRangeFlag = "brick-port-range"
+
+	portRangeOpKey   = "cluster.brick-port-range"
+	portsStorePrefix = "pmap/ports"
+)
+
+// ErrNoFreePort is returned when all the ports of the brick port range are
+// in use
+var ErrNoFreePort = errors.New("no free port left in the brick port range")
+
+// allocations serializes allocating ports on this peer
+var allocations sync.Mutex
+
+// parsePortRange parses a port range of the form <min>-<max>
+func parsePortRange(r string) (int, int, error) {
+	parts := strings.Split(r, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid port range %q, must be <min>-<max>", r)
+	}
+
+	min, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid port range %q: %s", r, err)
+	}
+	max, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid port range %q: %s", r, err)
+	}
+
+	if min < portMin || max > portMax || min > max {
+		return 0, 0, fmt.Errorf("invalid port range %q, must be within %d-%d", r, portMin, portMax)
+	}
+	return min, max, nil
+}
+
+// portRange returns the range of ports allocated to brick processes on this
+// peer, set for the peer or else for the cluster
+func portRange() (int, int, error) {
+	r := config.GetString(PortRangeFlag)
+	if r == "" {
+		var err error
+		if r, err = options.GetClusterOption(portRangeOpKey); err != nil {
+			return 0, 0, err
+		}
+	}
+	return parsePortRange(r)
+}
+
+func portsKey() string {
+	return path.Join(portsStorePrefix, gdctx.MyUUID.String())
+}
+
+// loadPorts returns the ports allocated to the bricks of this peer
+func loadPorts() (map[string]int, error) {
+	ports := make(map[string]int)
+
+	resp, err := store.Get(context.TODO(), portsKey())
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count == 0 {
+		return ports, nil
+	}
+
+	if err := json.Unmarshal(resp.Kvs[0].Value, &ports); err != nil {
+		return nil, err
+	}
+	return ports, nil
+}
+
+func savePorts(ports map[string]int) error {
+	data, err := json.Marshal(ports)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), portsKey(), string(data))
+	return err
+}
+
+// pickPort returns the port to start the brick on. The port the brick had
+// before is kept if it is still free, so that the brick is reachable on the
+// same port across restarts. Otherwise the first free port of the range which
+// isn't kept for another brick is used, or, if there is none, the first free
+// port of the range. Ports are kept for bricks which are stopped or deleted
+// until they are needed.
+func pickPort(brickpath string, ports map[string]int, min, max int, free func(int) bool) (int, error) {
+	if port, ok := ports[brickpath]; ok && port >= min && port <= max {
+		if free(port) {
+			return port, nil
+		}
+		log.WithFields(log.Fields{
+			"brick": brickpath,
+			"port":  port,
+		}).Warn("port of brick is in use by another process, allocating a new one")
+	}
+
+	kept := make(map[int]bool)
+	for brick, port := range ports {
+		if brick != brickpath {
+			kept[port] = true
+		}
+	}
+
+	for port := min; port <= max; port++ {
+		if !kept[port] && free(port) {
+			return port, nil
+		}
+	}
+
+	// The bricks the other ports were kept for may be stopped or gone
+	for port := min; port <= max; port++ {
+		if free(port) {
+			return port, nil
+		}
+	}
+	return 0, ErrNoFreePort
+}
+
+// AllocatePort returns a free port of the brick port range to start the
+// brick process of the brick on, and saves it in the store.
+func AllocatePort(brickpath string) (int, error) {
+	min, max, err := portRange()
+	if err != nil {
+		return 0, err
+	}
+
+	allocations.Lock()
+	defer allocations.Unlock()
+
+	ports, err := loadPorts()
+	if err != nil {
+		return 0, err
+	}
+
+	port, err := pickPort(brickpath, ports, min, max, isPortFree)
+	if err != nil {
+		return 0, err
+	}
+
+	if ports[brickpath] == port {
+		return port, nil
+	}
+
+	// A port now given to this brick isn't kept for any other one
+	for brick, p := range ports {
+		if p == port {
+			delete(ports, brick)
+		}
+	}
+	ports[brickpath] = port
+	if err := savePorts(ports); err != nil {
+		return 0, err
+	}
+	return port, nil
+}
+
+func validatePortRange(key, value string) error {
+	_, _, err := parsePortRange(value)
+	return err
+}
+
+func init() {
+	options.RegisterClusterOpValidationFunc(portRangeOpKey, validatePortRange)
+}
@@ -0,0 +1,49 @@
+package pmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePortRange(t *testing.T) {
+	assert := require.New(t)
+
+	min, max, err := parsePortRange("49152-60999")
+	assert.NoError(err)
+	assert.Equal(49152, min)
+	assert.Equal(60999, max)
+
+	for _, r := range []string{"49152", "a-b", "60999-49152", "80-90", "49152-70000"} {
+		_, _, err := parsePortRange(r)
+		assert.Error(err, r)
+	}
+}
+
+func TestPickPort(t *testing.T) {
+	assert := require.New(t)
+
+	busy := map[int]bool{49153: true}
+	free := func(port int) bool { return !busy[port] }
+	ports := map[string]int{"/b1": 49152, "/b2": 49153}
+
+	// a brick keeps its port while it is free
+	port, err := pickPort("/b1", ports, 49152, 49155, free)
+	assert.NoError(err)
+	assert.Equal(49152, port)
+
+	// a brick whose port was taken gets a port not kept for another brick
+	port, err = pickPort("/b2", ports, 49152, 49155, free)
+	assert.NoError(err)
+	assert.Equal(49154, port)
+
+	// new bricks get the ports kept for other bricks once the range is used up
+	busy[49154], busy[49155] = true, true
+	port, err = pickPort("/b3", ports, 49152, 49155, free)
+	assert.NoError(err)
+	assert.Equal(49152, port)
+
+	busy[49152] = true
+	_, err = pickPort("/b3", ports, 49152, 49155, free)
+	assert.Equal(ErrNoFreePort, err)
+}
@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	} {
+		_, err := parseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// Wednesday
+	from := time.Date(2018, time.October, 17, 10, 30, 45, 0, time.UTC)
+
+	for expr, want := range map[string]time.Time{
+		"* * * * *":     time.Date(2018, time.October, 17, 10, 31, 0, 0, time.UTC),
+		"*/15 * * * *":  time.Date(2018, time.October, 17, 10, 45, 0, 0, time.UTC),
+		"0 2 * * *":     time.Date(2018, time.October, 18, 2, 0, 0, 0, time.UTC),
+		"30 22 * * 1-5": time.Date(2018, time.October, 17, 22, 30, 0, 0, time.UTC),
+		"0 0 * * 7":     time.Date(2018, time.October, 21, 0, 0, 0, 0, time.UTC),
+		"0 0 1 * *":     time.Date(2018, time.November, 1, 0, 0, 0, 0, time.UTC),
+		"0 0 1 1 *":     time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC),
+		"0 0 29 2 *":    time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC),
+		"0 0 1,20 * 6":  time.Date(2018, time.October, 20, 0, 0, 0, 0, time.UTC),
+	} {
+		s, err := parseCron(expr)
+		require.NoError(t, err, expr)
+		assert.Equal(t, want, s.next(from), expr)
+	}
+
+	s, err := parseCron("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, s.next(from).IsZero())
+}
+
+func TestNextRun(t *testing.T) {
+	now := time.Date(2018, 10, 20, 10, 30, 0, 0, time.UTC)
+
+	next, err := NextRun("0 2 * * *", now)
+	require.Nil(t, err)
+	assert.Equal(t, time.Date(2018, 10, 21, 2, 0, 0, 0, time.UTC), next)
+
+	_, err = NextRun("0 2 30 2 *", now)
+	assert.Error(t, err)
+
+	_, err = NextRun("0 2 * *", now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,372 @@
+// Package scheduler runs operations on volumes at a later time, once or
+// repeatedly as per a cron expression. Schedules are kept in the store and
+// are run by the cleanup leader, so that each run of a schedule is done by a
+// single peer in the cluster.
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const schedulePrefix = "schedules/"
+
+// Run results of schedules
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+)
+
+// ValidateFunc checks the args of an operation when it is scheduled.
+// recurring is set if the operation is scheduled to run more than once.
+type ValidateFunc func(volname string, args json.RawMessage, recurring bool) error
+
+// OpFunc runs a scheduled operation on a volume. args is the request body of
+// the operation.
+type OpFunc func(ctx context.Context, volname string, args json.RawMessage) error
+
+type op struct {
+	validate ValidateFunc
+	run      OpFunc
+}
+
+var opRegistry = struct {
+	sync.RWMutex
+	ops map[string]op
+}{
+	ops: make(map[string]op),
+}
+
+// RegisterOp registers an operation which can be scheduled
+func RegisterOp(name string, validate ValidateFunc, run OpFunc) {
+	opRegistry.Lock()
+	defer opRegistry.Unlock()
+
+	opRegistry.ops[name] = op{validate, run}
+}
+
+// dueFuncs are run along with the schedules, for subsystems which keep
+// schedules of their own
+var dueFuncs = struct {
+	sync.Mutex
+	fns []func()
+}{}
+
+// RegisterDueFunc registers a function to be called by the leader every time
+// the schedules are checked. fn runs anything of its own which is due.
+func RegisterDueFunc(fn func()) {
+	dueFuncs.Lock()
+	defer dueFuncs.Unlock()
+
+	dueFuncs.fns = append(dueFuncs.fns, fn)
+}
+
+func getOp(name string) (op, bool) {
+	opRegistry.RLock()
+	defer opRegistry.RUnlock()
+
+	o, ok := opRegistry.ops[name]
+	return o, ok
+}
+
+// Schedule is an operation scheduled to run on a volume. All times are in
+// UTC. NextRun is the zero time once a one time schedule has run.
+type Schedule struct {
+	Name       string          `json:"name"`
+	Operation  string          `json:"operation"`
+	Volume     string          `json:"volume"`
+	Args       json.RawMessage `json:"args,omitempty"`
+	At         time.Time       `json:"at,omitempty"`
+	Cron       string          `json:"cron,omitempty"`
+	CreatedBy  string          `json:"created-by,omitempty"`
+	CreatedAt  time.Time       `json:"created-at"`
+	NextRun    time.Time       `json:"next-run,omitempty"`
+	LastRun    time.Time       `json:"last-run,omitempty"`
+	LastStatus string          `json:"last-status,omitempty"`
+	LastError  string          `json:"last-error,omitempty"`
+}
+
+// NewSchedule validates the request and returns a new schedule for it
+func NewSchedule(req *api.ScheduleCreateReq, user string) (*Schedule, error) {
+	if !volume.IsValidName(req.Name) {
+		return nil, fmt.Errorf("invalid schedule name: %s", req.Name)
+	}
+
+	o, ok := getOp(req.Operation)
+	if !ok {
+		return nil, gderrors.ErrScheduleOpNotSupported
+	}
+
+	if req.At.IsZero() == (req.Cron == "") {
+		return nil, gderrors.ErrScheduleTimeRequired
+	}
+
+	now := time.Now().UTC()
+	s := &Schedule{
+		Name:      req.Name,
+		Operation: req.Operation,
+		Volume:    req.Volume,
+		Args:      req.Args,
+		At:        req.At.UTC(),
+		Cron:      req.Cron,
+		CreatedBy: user,
+		CreatedAt: now,
+	}
+
+	if s.Cron == "" {
+		if !s.At.After(now) {
+			return nil, gderrors.ErrScheduleTimeInPast
+		}
+		s.NextRun = s.At
+	} else {
+		cs, err := parseCron(s.Cron)
+		if err != nil {
+			return nil, err
+		}
+		s.NextRun = cs.next(now)
+		if s.NextRun.IsZero() {
+			return nil, gderrors.ErrScheduleNeverRuns
+		}
+	}
+
+	if err := o.validate(s.Volume, s.Args, s.Cron != ""); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// nextRunAfter returns the time at which the schedule is to be run after t
+func (s *Schedule) nextRunAfter(t time.Time) time.Time {
+	if s.Cron == "" {
+		return time.Time{}
+	}
+	cs, err := parseCron(s.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+	return cs.next(t)
+}
+
+// AddSchedule saves a new schedule in the store
+func AddSchedule(s *Schedule) error {
+	v, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	key := schedulePrefix + s.Name
+	resp, err := store.Txn(context.TODO()).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, string(v))).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		return gderrors.ErrScheduleExists
+	}
+	return nil
+}
+
+// GetSchedule returns the schedule with the given name
+func GetSchedule(name string) (*Schedule, error) {
+	resp, err := store.Get(context.TODO(), schedulePrefix+name)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, gderrors.ErrScheduleNotFound
+	}
+
+	var s Schedule
+	if err := json.Unmarshal(resp.Kvs[0].Value, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// GetSchedules returns all the schedules
+func GetSchedules() ([]*Schedule, error) {
+	resp, err := store.Get(context.TODO(), schedulePrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]*Schedule, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var s Schedule
+		if err := json.Unmarshal(kv.Value, &s); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal schedule")
+			continue
+		}
+		schedules = append(schedules, &s)
+	}
+	return schedules, nil
+}
+
+// DeleteSchedule removes the schedule with the given name. A run of the
+// schedule which is in progress is not stopped.
+func DeleteSchedule(name string) error {
+	resp, err := store.Delete(context.TODO(), schedulePrefix+name)
+	if err != nil {
+		return err
+	}
+	if resp.Deleted == 0 {
+		return gderrors.ErrScheduleNotFound
+	}
+	return nil
+}
+
+// updateSchedule applies update to the schedule with the given name and
+// saves it, unless update returns false or the schedule was changed in the
+// meantime. It returns whether the schedule was saved.
+func updateSchedule(name string, update func(s *Schedule) bool) (bool, error) {
+	key := schedulePrefix + name
+	resp, err := store.Get(context.TODO(), key)
+	if err != nil || resp.Count != 1 {
+		return false, err
+	}
+
+	var s Schedule
+	if err := json.Unmarshal(resp.Kvs[0].Value, &s); err != nil {
+		return false, err
+	}
+	if !update(&s) {
+		return false, nil
+	}
+
+	v, err := json.Marshal(&s)
+	if err != nil {
+		return false, err
+	}
+
+	tresp, err := store.Txn(context.TODO()).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", resp.Kvs[0].ModRevision)).
+		Then(clientv3.OpPut(key, string(v))).
+		Commit()
+	if err != nil {
+		return false, err
+	}
+	return tresp.Succeeded, nil
+}
+
+// RunDue calls the registered due functions and starts the runs of the
+// schedules which are due. A schedule is moved to its next run before it is
+// run, so that a run is not repeated if the leader changes. Runs which were
+// missed while there was no leader are made up for by a single run.
+func RunDue() {
+	dueFuncs.Lock()
+	fns := dueFuncs.fns
+	dueFuncs.Unlock()
+	for _, fn := range fns {
+		fn()
+	}
+
+	schedules, err := GetSchedules()
+	if err != nil {
+		log.WithError(err).Error("failed to get schedules")
+		return
+	}
+
+	now := time.Now().UTC()
+	for _, s := range schedules {
+		if s.NextRun.IsZero() || s.NextRun.After(now) {
+			continue
+		}
+
+		var due Schedule
+		ok, err := updateSchedule(s.Name, func(s *Schedule) bool {
+			if s.NextRun.IsZero() || s.NextRun.After(now) {
+				return false
+			}
+			s.NextRun = s.nextRunAfter(now)
+			due = *s
+			return true
+		})
+		if err != nil {
+			log.WithError(err).WithField("schedule", s.Name).Error("failed to update schedule")
+		}
+		if ok {
+			go due.run()
+		}
+	}
+}
+
+func (s *Schedule) run() {
+	reqID := uuid.NewRandom()
+	logger := log.WithFields(log.Fields{
+		"reqid":     reqID.String(),
+		"schedule":  s.Name,
+		"operation": s.Operation,
+		"volume":    s.Volume,
+	})
+	ctx := gdctx.WithReqID(context.Background(), reqID)
+	ctx = gdctx.WithReqLogger(ctx, logger)
+	ctx = gdctx.WithReqUser(ctx, s.CreatedBy)
+
+	logger.Info("running scheduled operation")
+
+	start := time.Now().UTC()
+	err := gderrors.ErrScheduleOpNotSupported
+	if o, ok := getOp(s.Operation); ok {
+		err = o.run(ctx, s.Volume, s.Args)
+	}
+
+	if err != nil {
+		logger.WithError(err).Error("scheduled operation failed")
+	} else {
+		logger.Info("scheduled operation succeeded")
+	}
+
+	for i := 0; i < 3; i++ {
+		ok, uerr := updateSchedule(s.Name, func(s *Schedule) bool {
+			s.LastRun = start
+			s.LastStatus = StatusSuccess
+			s.LastError = ""
+			if err != nil {
+				s.LastStatus = StatusFailed
+				s.LastError = err.Error()
+			}
+			return true
+		})
+		if uerr != nil {
+			logger.WithError(uerr).Error("failed to record result of scheduled operation")
+			return
+		}
+		if ok {
+			return
+		}
+	}
+}
+
+// CreateScheduleInfoResp returns the API representation of a schedule
+func CreateScheduleInfoResp(s *Schedule) *api.ScheduleInfo {
+	return &api.ScheduleInfo{
+		Name:       s.Name,
+		Operation:  s.Operation,
+		Volume:     s.Volume,
+		Args:       s.Args,
+		At:         s.At,
+		Cron:       s.Cron,
+		CreatedBy:  s.CreatedBy,
+		CreatedAt:  s.CreatedAt,
+		NextRun:    s.NextRun,
+		LastRun:    s.LastRun,
+		LastStatus: s.LastStatus,
+		LastError:  s.LastError,
+	}
+}
@@ -1,5 +1,14 @@
 package eventlistener
 
+import (
+	"strings"
+
+	gd2events "github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// eventtypes are the types of the events sent by the gluster processes,
+// indexed by their code
 var eventtypes = []string{
 	"PEER_ADD",
 	"PEER_REMOVE",
@@ -144,3 +153,44 @@ var eventtypes = []string{
 	"DHT_DISK_USAGE",
 	"DHT_INODES_USAGE",
 }
+
+// Parts of the names of the events sent by the gluster processes which tell
+// their severity
+var (
+	criticalEventTypes = []string{"SPLIT_BRAIN", "BITROT_BAD_FILE", "QUORUM_LOST", "AFR_SUBVOLS_DOWN", "EC_MIN_BRICKS_NOT_UP"}
+	errorEventTypes    = []string{"FAIL", "FAULTY", "REJECT"}
+	warningEventTypes  = []string{"DISCONNECT", "LIMIT_REACHED", "CROSSED_SOFT_LIMIT", "NOT_FOUND", "UNKNOWN", "WATERMARK_HI", "DHT_DISK_USAGE", "DHT_INODES_USAGE", "SAME_GFID", "ALREADY_PART_OF_VOLUME", "NOT_IN_VOLUME", "NOT_SUPPORTED"}
+)
+
+func containsAny(name string, parts []string) bool {
+	for _, p := range parts {
+		if strings.Contains(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// eventSeverity returns the severity of an event sent by the gluster
+// processes, from its name
+func eventSeverity(name string) api.EventSeverity {
+	switch {
+	case containsAny(name, criticalEventTypes):
+		return api.EventSeverityCritical
+	case containsAny(name, errorEventTypes):
+		return api.EventSeverityError
+	case containsAny(name, warningEventTypes):
+		return api.EventSeverityWarning
+	}
+	return api.EventSeverityInfo
+}
+
+func init() {
+	for _, name := range eventtypes {
+		gd2events.RegisterTypes(api.EventType{
+			Name:        name,
+			Severity:    eventSeverity(name),
+			Description: "Sent by the gluster processes, with the data they attach to it",
+		})
+	}
+}
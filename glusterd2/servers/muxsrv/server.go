@@ -2,8 +2,12 @@
 package muxsrv
 
 import (
+	"crypto/tls"
 	"net"
 
+	"github.com/gluster/glusterd2/glusterd2/certs"
+	"github.com/gluster/glusterd2/pkg/tlsmatcher"
+
 	"github.com/cockroachdb/cmux"
 	log "github.com/sirupsen/logrus"
 	config "github.com/spf13/viper"
@@ -13,6 +17,9 @@ import (
 type muxSrv struct {
 	l net.Listener
 	m cmux.CMux
+	// tlsM multiplexes the connections over SSL/TLS, once they are
+	// decrypted. It is nil if SSL/TLS is not enabled.
+	tlsM cmux.CMux
 }
 
 // newMuxSrv returns a multiplexed server with the multiplexed listeners already setup
@@ -26,11 +33,23 @@ func newMuxSrv() *muxSrv {
 	mux.l = l
 	mux.m = cmux.New(l)
 
+	if certs.Server.Enabled() {
+		tlsL := tls.NewListener(mux.m.Match(tlsmatcher.TLS12, tlsmatcher.TLS11, tlsmatcher.TLS10), certs.Server.ServerConfig())
+		mux.tlsM = cmux.New(tlsL)
+	}
+
 	return mux
 }
 
 // Serve starts the handlers and the multiplexed listener
 func (m *muxSrv) Serve() {
+	if m.tlsM != nil {
+		go func() {
+			if err := m.tlsM.Serve(); err != nil && err != cmux.ErrListenerClosed {
+				log.WithError(err).Warn("SSL/TLS mux listener failed")
+			}
+		}()
+	}
 	if err := m.m.Serve(); err != nil && err != cmux.ErrListenerClosed {
 		log.WithError(err).Warn("mux listener failed")
 	}
@@ -5,6 +5,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc"
 
 	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
 	"github.com/thejerf/suture"
 )
 
@@ -17,8 +18,12 @@ func New() *suture.Supervisor {
 
 	m := newMuxSrv()
 
-	s.Add(rest.NewMuxed(m.m))
-	s.Add(sunrpc.NewMuxed(m.m))
+	r := rest.NewMuxed(m.m, m.tlsM)
+	s.Add(r)
+	if path := config.GetString("localsocket"); path != "" {
+		s.Add(rest.NewLocal(r, path))
+	}
+	s.Add(sunrpc.NewMuxed(m.m, m.tlsM))
 	s.Add(m)
 
 	return s
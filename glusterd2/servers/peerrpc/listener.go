@@ -4,6 +4,8 @@ package peerrpc
 import (
 	"net"
 
+	"github.com/gluster/glusterd2/glusterd2/certs"
+
 	log "github.com/sirupsen/logrus"
 	config "github.com/spf13/viper"
 	"go.opencensus.io/plugin/ocgrpc"
@@ -18,8 +20,9 @@ type Server struct {
 
 // New returns a new peerrpc.Server with registered gRPC services
 func New() *Server {
+	opts := append(certs.GrpcServerOptions(), grpc.StatsHandler(&ocgrpc.ServerHandler{}))
 	s := &Server{
-		grpc.NewServer(grpc.StatsHandler(&ocgrpc.ServerHandler{})),
+		grpc.NewServer(opts...),
 	}
 	registerServices(s.server)
 
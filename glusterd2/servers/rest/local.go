@@ -0,0 +1,104 @@
+package rest
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/middleware"
+
+	"github.com/justinas/alice"
+	log "github.com/sirupsen/logrus"
+)
+
+// LocalRest serves the REST API on a Unix socket to the admins of the local
+// node. Clients are authenticated by the credentials of their process when
+// they connect, instead of by tokens, so that the local CLI keeps working
+// when SSL/TLS or REST API authentication is misconfigured.
+type LocalRest struct {
+	path     string
+	listener net.Listener
+	server   *http.Server
+	stopCh   chan struct{}
+}
+
+// NewLocal returns a LocalRest serving the routes of rest on the Unix
+// socket at path
+func NewLocal(rest *GDRest, path string) *LocalRest {
+	// Remove the socket left behind by a previous run
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.WithError(err).WithField("path", path).Fatal("failed to remove stale local REST socket")
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		log.WithError(err).WithField("path", path).Fatal("failed to create local REST listener")
+	}
+	if err := os.Chmod(path, 0660); err != nil {
+		log.WithError(err).WithField("path", path).Fatal("failed to set permissions of local REST socket")
+	}
+
+	local := &LocalRest{
+		path:     path,
+		listener: &peerCredListener{Listener: l, allowed: allowedLocalUID},
+		server: &http.Server{
+			ReadTimeout:    httpReadTimeout * time.Second,
+			WriteTimeout:   httpWriteTimeout * time.Second,
+			MaxHeaderBytes: maxHeaderBytes,
+		},
+		stopCh: make(chan struct{}),
+	}
+
+	// Same chain as the TCP server, except that the requests are not rate
+	// limited and are authenticated by the listener
+	local.server.Handler = alice.New(
+		middleware.Recover,
+		middleware.Tracing,
+		middleware.Expvar,
+		middleware.Metrics,
+		middleware.ReqIDGenerator,
+		middleware.LogRequest,
+		middleware.Audit,
+		middleware.LocalAuth,
+		middleware.Maintenance,
+	).Then(rest.Routes)
+
+	return local
+}
+
+// allowedLocalUID returns true if the user with the given ID may use the
+// local REST API, that is root and the user running glusterd2
+func allowedLocalUID(uid uint32) bool {
+	return uid == 0 || uid == uint32(os.Geteuid())
+}
+
+// Serve begins serving client HTTP requests on the Unix socket
+func (r *LocalRest) Serve() {
+	log.WithField("path", r.path).Info("Started GlusterD local ReST server")
+	if err := r.server.Serve(r.listener); err != nil {
+		if err == http.ErrServerClosed {
+			<-r.stopCh
+		} else {
+			log.WithError(err).Error("glusterd local ReST server failed")
+		}
+	}
+}
+
+// Stop stops the local REST server gracefully
+func (r *LocalRest) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	log.Debug("stopping glusterd local ReST server gracefully")
+	if err := r.server.Shutdown(ctx); err != nil {
+		log.WithError(err).Error("failed to gracefully stop glusterd local ReST server")
+		if err == context.DeadlineExceeded {
+			r.server.Close() // forcefully close connections
+		}
+	}
+	log.Info("stopped glusterd local ReST server")
+
+	r.stopCh <- struct{}{}
+}
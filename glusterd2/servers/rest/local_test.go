@@ -0,0 +1,62 @@
+package rest
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerCredListener(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("peer credentials are only supported on linux")
+	}
+
+	dir, err := ioutil.TempDir("", "gd2-local")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	l, err := net.Listen("unix", filepath.Join(dir, "gd2.sock"))
+	require.Nil(t, err)
+	defer l.Close()
+
+	accept := func(allowed bool) (net.Conn, error) {
+		pl := &peerCredListener{Listener: l, allowed: func(uid uint32) bool { return allowed }}
+		conns := make(chan net.Conn, 1)
+		go func() {
+			conn, err := pl.Accept()
+			if err == nil {
+				conns <- conn
+			}
+			close(conns)
+		}()
+
+		client, err := net.Dial("unix", filepath.Join(dir, "gd2.sock"))
+		if err != nil {
+			return nil, err
+		}
+		defer client.Close()
+		if !allowed {
+			// The connection is closed by the listener
+			_, err := client.Read(make([]byte, 1))
+			l.Close()
+			return nil, err
+		}
+		return <-conns, nil
+	}
+
+	conn, err := accept(true)
+	require.Nil(t, err)
+	defer conn.Close()
+	assert.Contains(t, conn.RemoteAddr().String(), "uid="+strconv.Itoa(os.Geteuid())+",")
+	assert.Contains(t, conn.RemoteAddr().String(), "pid="+strconv.Itoa(os.Getpid()))
+
+	_, err = accept(false)
+	assert.NotNil(t, err)
+}
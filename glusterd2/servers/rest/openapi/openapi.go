@@ -0,0 +1,336 @@
+// Package openapi generates an OpenAPI 3 document describing the REST API
+// from the registered routes and the types of their requests and responses.
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	"github.com/pborman/uuid"
+)
+
+const (
+	openAPIVersion = "3.0.0"
+	modulePath     = "github.com/gluster/glusterd2/"
+)
+
+// Document is an OpenAPI document
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []map[string][]string `json:"security,omitempty"`
+}
+
+// Info describes the API
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem has the operations on a path, by their method in lower case
+type PathItem map[string]*Operation
+
+// Operation describes a route
+type Operation struct {
+	OperationID  string              `json:"operationId"`
+	Summary      string              `json:"summary,omitempty"`
+	Description  string              `json:"description,omitempty"`
+	Tags         []string            `json:"tags,omitempty"`
+	Parameters   []Parameter         `json:"parameters,omitempty"`
+	RequestBody  *RequestBody        `json:"requestBody,omitempty"`
+	Responses    map[string]Response `json:"responses"`
+	RequiredRole string              `json:"x-required-role,omitempty"`
+}
+
+// Parameter describes a path parameter of an operation
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody describes the body of the requests of an operation
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes a response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType has the schema of a request or response body
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components has the schemas referred to by the operations
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes how requests are authenticated
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Schema describes a JSON value
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	timeType             = reflect.TypeOf(time.Time{})
+	uuidType             = reflect.TypeOf(uuid.UUID{})
+	rawMessageType       = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType    = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType    = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	pathParamRE          = regexp.MustCompile(`{([^}:]+)(:[^}]*)?}`)
+	operationIDInvalidRE = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+)
+
+// generator builds the schemas of the components of a document
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+// schemaName returns the name of the component of a named type, qualified
+// by its package path so that types of the same name in different packages
+// don't collide
+func schemaName(t reflect.Type) string {
+	pkg := strings.TrimPrefix(t.PkgPath(), modulePath)
+	return strings.Replace(pkg, "/", ".", -1) + "." + t.Name()
+}
+
+// implements returns true if values or pointers of type t implement iface
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PtrTo(t).Implements(iface)
+}
+
+// schema returns the schema of the JSON encoding of values of type t. Named
+// struct types are added to the components and referred to.
+func (g *generator) schema(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schema(t.Elem())
+	case reflect.Struct:
+		return g.structSchema(t)
+	case reflect.Interface:
+		return &Schema{}
+	}
+
+	// Types with their own encoding, like the enums of the api package, are
+	// encoded as strings
+	if implements(t, jsonMarshalerType) || implements(t, textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	}
+	return &Schema{}
+}
+
+// structSchema returns the schema of a struct type, which is a reference to
+// a component for named types
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return g.objectSchema(t)
+	}
+
+	name, ok := g.names[t]
+	if !ok {
+		name = schemaName(t)
+		g.names[t] = name
+		// Registered before the fields, so that types referring to
+		// themselves refer to the component
+		g.schemas[name] = &Schema{}
+		*g.schemas[name] = *g.objectSchema(t)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// objectSchema returns the schema of the fields of a struct type, following
+// the rules of encoding/json for field names and embedded structs
+func (g *generator) objectSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for k, v := range g.objectSchema(ft).Properties {
+				if _, ok := s.Properties[k]; !ok {
+					s.Properties[k] = v
+				}
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			// unexported
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = g.schema(f.Type)
+	}
+	return s
+}
+
+// typeSchema returns the schema of the type with the given name, as set in
+// routes, or nil if the type is not known
+func (g *generator) typeSchema(name string) *Schema {
+	if name == "" {
+		return nil
+	}
+	t := utils.GetType(name)
+	if t == nil {
+		return nil
+	}
+	return g.schema(t)
+}
+
+func jsonContent(s *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: s}}
+}
+
+// routePath returns the path of a route, with the regular expressions of
+// its variables removed, and the names of the variables
+func routePath(r route.Route) (string, []string) {
+	p := r.Pattern
+	if r.Version != 0 {
+		p = fmt.Sprintf("/v%d%s", r.Version, r.Pattern)
+	}
+
+	var params []string
+	for _, m := range pathParamRE.FindAllStringSubmatch(p, -1) {
+		params = append(params, m[1])
+	}
+	return pathParamRE.ReplaceAllString(p, "{$1}"), params
+}
+
+// routeTag returns the tag grouping a route with the others on the same
+// resource, the first element of its pattern
+func routeTag(r route.Route) string {
+	parts := strings.SplitN(strings.TrimPrefix(r.Pattern, "/"), "/", 2)
+	return parts[0]
+}
+
+// Generate returns the OpenAPI document of the given routes
+func Generate(routes route.Routes, version string) *Document {
+	g := &generator{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+	errorSchema := g.schema(reflect.TypeOf(api.ErrorResp{}))
+
+	doc := &Document{
+		OpenAPI: openAPIVersion,
+		Info:    Info{Title: "GlusterD2 REST API", Version: version},
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas: g.schemas,
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+		Security: []map[string][]string{{"bearerAuth": {}}},
+	}
+
+	for _, r := range routes {
+		p, params := routePath(r)
+
+		op := &Operation{
+			OperationID:  operationIDInvalidRE.ReplaceAllString(r.Name, ""),
+			Summary:      r.Name,
+			Description:  r.Description,
+			Tags:         []string{routeTag(r)},
+			RequiredRole: r.RequiredRole(),
+			Responses: map[string]Response{
+				"2XX":     {Description: "success"},
+				"default": {Description: "error", Content: jsonContent(errorSchema)},
+			},
+		}
+		for _, name := range params {
+			op.Parameters = append(op.Parameters, Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+		if s := g.typeSchema(r.RequestType); s != nil {
+			op.RequestBody = &RequestBody{Required: true, Content: jsonContent(s)}
+		}
+		if s := g.typeSchema(r.ResponseType); s != nil {
+			op.Responses["2XX"] = Response{Description: "success", Content: jsonContent(s)}
+		}
+
+		item, ok := doc.Paths[p]
+		if !ok {
+			item = make(PathItem)
+			doc.Paths[p] = item
+		}
+		item[strings.ToLower(r.Method)] = op
+	}
+	return doc
+}
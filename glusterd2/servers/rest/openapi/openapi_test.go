@@ -0,0 +1,88 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testInner struct {
+	Size uint64 `json:"size"`
+}
+
+type testReq struct {
+	testInner
+	Name    string            `json:"name"`
+	ID      uuid.UUID         `json:"id"`
+	Created time.Time         `json:"created-at"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Next    *testReq          `json:"next,omitempty"`
+	Ignored string            `json:"-"`
+	hidden  string
+}
+
+type testResp []testReq
+
+func TestGenerate(t *testing.T) {
+	routes := route.Routes{
+		{
+			Name:         "TestCreate",
+			Method:       "POST",
+			Pattern:      "/tests/{name}/items/{item:.*}",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*testReq)(nil)),
+			ResponseType: utils.GetTypeString((*testResp)(nil)),
+		},
+		{
+			Name:    "List Endpoints",
+			Method:  "GET",
+			Pattern: "/endpoints",
+		},
+	}
+
+	doc := Generate(routes, "v1.0")
+	assert.Equal(t, "3.0.0", doc.OpenAPI)
+	assert.Equal(t, "v1.0", doc.Info.Version)
+
+	op := doc.Paths["/v1/tests/{name}/items/{item}"]["post"]
+	require.NotNil(t, op)
+	assert.Equal(t, "TestCreate", op.OperationID)
+	assert.Equal(t, []string{"tests"}, op.Tags)
+	assert.Equal(t, api.RoleOperator, op.RequiredRole)
+	require.Len(t, op.Parameters, 2)
+	assert.Equal(t, "name", op.Parameters[0].Name)
+	assert.Equal(t, "item", op.Parameters[1].Name)
+
+	ref := "#/components/schemas/glusterd2.servers.rest.openapi.testReq"
+	assert.Equal(t, ref, op.RequestBody.Content["application/json"].Schema.Ref)
+	resp := op.Responses["2XX"].Content["application/json"].Schema
+	assert.Equal(t, "array", resp.Type)
+	assert.Equal(t, ref, resp.Items.Ref)
+
+	s := doc.Components.Schemas["glusterd2.servers.rest.openapi.testReq"]
+	require.NotNil(t, s)
+	assert.Equal(t, &Schema{Type: "integer", Format: "int64"}, s.Properties["size"])
+	assert.Equal(t, &Schema{Type: "string"}, s.Properties["name"])
+	assert.Equal(t, &Schema{Type: "string", Format: "uuid"}, s.Properties["id"])
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, s.Properties["created-at"])
+	assert.Equal(t, &Schema{Type: "string"}, s.Properties["labels"].AdditionalProperties)
+	assert.Equal(t, ref, s.Properties["next"].Ref)
+	assert.Len(t, s.Properties, 6)
+
+	op = doc.Paths["/endpoints"]["get"]
+	require.NotNil(t, op)
+	assert.Equal(t, "ListEndpoints", op.OperationID)
+	assert.Nil(t, op.RequestBody)
+	assert.NotNil(t, op.Responses["default"].Content)
+
+	_, err := json.Marshal(doc)
+	assert.NoError(t, err)
+}
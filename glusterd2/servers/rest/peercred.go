@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"fmt"
+	"net"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// peerCred are the credentials of the process at the other end of a Unix
+// socket connection
+type peerCred struct {
+	pid int32
+	uid uint32
+	gid uint32
+}
+
+// Network implements net.Addr
+func (c peerCred) Network() string {
+	return "unix"
+}
+
+// String implements net.Addr. It is seen as the remote address of the
+// requests, so that request logs and audit records show who made them.
+func (c peerCred) String() string {
+	return fmt.Sprintf("uid=%d,gid=%d,pid=%d", c.uid, c.gid, c.pid)
+}
+
+// peerCredConn is a connection whose remote address is the credentials of
+// its peer
+type peerCredConn struct {
+	net.Conn
+	cred peerCred
+}
+
+func (c *peerCredConn) RemoteAddr() net.Addr {
+	return c.cred
+}
+
+// peerCredListener is a Unix socket listener which only accepts the
+// connections of the users it allows
+type peerCredListener struct {
+	net.Listener
+	allowed func(uid uint32) bool
+}
+
+func (l *peerCredListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		cred, err := getPeerCred(conn)
+		if err != nil {
+			log.WithError(err).Warn("failed to get credentials of local REST client")
+			conn.Close()
+			continue
+		}
+		if !l.allowed(cred.uid) {
+			log.WithField("peer", cred.String()).Warn("rejected local REST client")
+			conn.Close()
+			continue
+		}
+		return &peerCredConn{Conn: conn, cred: cred}, nil
+	}
+}
@@ -0,0 +1,33 @@
+package rest
+
+import (
+	"errors"
+	"net"
+	"syscall"
+)
+
+// getPeerCred returns the credentials of the peer of a Unix socket
+// connection, as given by SO_PEERCRED
+func getPeerCred(conn net.Conn) (peerCred, error) {
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return peerCred{}, errors.New("not a unix socket connection")
+	}
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return peerCred{}, err
+	}
+
+	var ucred *syscall.Ucred
+	var credErr error
+	err = raw.Control(func(fd uintptr) {
+		ucred, credErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	})
+	if err != nil {
+		return peerCred{}, err
+	}
+	if credErr != nil {
+		return peerCred{}, credErr
+	}
+	return peerCred{pid: ucred.Pid, uid: ucred.Uid, gid: ucred.Gid}, nil
+}
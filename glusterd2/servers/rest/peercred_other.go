@@ -0,0 +1,15 @@
+//go:build !linux
+// +build !linux
+
+package rest
+
+import (
+	"errors"
+	"net"
+)
+
+// getPeerCred is not supported on this platform, so all the clients of the
+// local REST server are rejected
+func getPeerCred(conn net.Conn) (peerCred, error) {
+	return peerCred{}, errors.New("peer credentials are not supported on this platform")
+}
@@ -3,17 +3,16 @@ package rest
 
 import (
 	"context"
-	"crypto/rand"
-	"crypto/tls"
 	"net"
 	"net/http"
 	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/middleware"
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/openapi"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	gdutils "github.com/gluster/glusterd2/glusterd2/utils"
 	"github.com/gluster/glusterd2/pkg/api"
-	"github.com/gluster/glusterd2/pkg/tlsmatcher"
+	"github.com/gluster/glusterd2/version"
 
 	"github.com/cockroachdb/cmux"
 	"github.com/gorilla/mux"
@@ -36,24 +35,10 @@ type GDRest struct {
 	stopCh   chan struct{}
 }
 
-func tlsListener(l net.Listener, certfile, keyfile string) (net.Listener, error) {
-
-	certificate, err := tls.LoadX509KeyPair(certfile, keyfile)
-	if err != nil {
-		return nil, err
-	}
-
-	config := &tls.Config{
-		MinVersion:   tls.VersionTLS12, // force TLS 1.2
-		Certificates: []tls.Certificate{certificate},
-		Rand:         rand.Reader,
-	}
-
-	return tls.NewListener(l, config), nil
-}
-
-// NewMuxed returns a GDRest object which listens on a CMux multiplexed connection
-func NewMuxed(m cmux.CMux) *GDRest {
+// NewMuxed returns a GDRest object which listens on a CMux multiplexed
+// connection. If SSL/TLS is enabled, it listens on tlsM, which multiplexes
+// the decrypted connections, instead.
+func NewMuxed(m cmux.CMux, tlsM cmux.CMux) *GDRest {
 
 	rest := &GDRest{
 		Routes: mux.NewRouter(),
@@ -65,20 +50,8 @@ func NewMuxed(m cmux.CMux) *GDRest {
 		stopCh: make(chan struct{}),
 	}
 
-	certfile := config.GetString("cert-file")
-	keyfile := config.GetString("key-file")
-
-	if certfile != "" && keyfile != "" {
-		if l, err := tlsListener(m.Match(tlsmatcher.TLS12, tlsmatcher.TLS11, tlsmatcher.TLS10), certfile, keyfile); err != nil {
-			// TODO: Don't use Fatal(), bubble up error till main()
-			// NOTE: Methods of suture.Service interface do not return error
-			log.WithError(err).WithFields(log.Fields{
-				"cert-file": certfile,
-				"key-file":  keyfile,
-			}).Fatal("Failed to create SSL/TLS listener")
-		} else {
-			rest.listener = l
-		}
+	if tlsM != nil {
+		rest.listener = tlsM.Match(cmux.HTTP1Fast())
 	} else {
 		rest.listener = m.Match(cmux.HTTP1Fast())
 	}
@@ -96,9 +69,13 @@ func NewMuxed(m cmux.CMux) *GDRest {
 		middleware.Recover,
 		middleware.Tracing,
 		middleware.Expvar,
+		middleware.Metrics,
 		middleware.ReqIDGenerator,
 		middleware.LogRequest,
+		middleware.Audit,
+		middleware.NewRateLimiter().Handler,
 		middleware.Auth,
+		middleware.Maintenance,
 	).Then(rest.Routes)
 
 	return rest
@@ -154,6 +131,15 @@ func (r *GDRest) listEndpointsHandler() http.HandlerFunc {
 	})
 }
 
+// openAPIHandler returns the OpenAPI document describing all the routes
+func (r *GDRest) openAPIHandler() http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		doc := openapi.Generate(AllRoutes, version.GlusterdVersion)
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, doc)
+	})
+}
+
 //Ping URL for glusterd2
 func (r *GDRest) Ping() http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
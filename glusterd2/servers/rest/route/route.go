@@ -3,6 +3,8 @@ package route
 
 import (
 	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
 )
 
 // Route models a route to be set on the GlusterD Rest server
@@ -17,6 +19,30 @@ type Route struct {
 	RequestType  string
 	ResponseType string // Success
 	HandlerFunc  http.HandlerFunc
+	// Role is the least privileged role allowed to make requests to the
+	// route. If it is not set, it is derived from Method by RequiredRole.
+	Role string
+	// OpVersion is the op-version which introduced the features used by the
+	// route. Requests to the route are refused until the op-version of the
+	// cluster is raised to it.
+	OpVersion int
+}
+
+// RequiredRole returns the least privileged role allowed to make requests
+// to the route. Unless the route sets one, viewers can make GET requests,
+// operators any other request except DELETE, which only admins can make.
+func (r Route) RequiredRole() string {
+	if r.Role != "" {
+		return r.Role
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		return api.RoleViewer
+	case http.MethodDelete:
+		return api.RoleAdmin
+	default:
+		return api.RoleOperator
+	}
 }
 
 // Routes is a table of many Route's
@@ -6,6 +6,9 @@ import (
 	"net/http"
 
 	"github.com/gluster/glusterd2/glusterd2/commands"
+	"github.com/gluster/glusterd2/glusterd2/metrics"
+	"github.com/gluster/glusterd2/glusterd2/metrics/collector"
+	"github.com/gluster/glusterd2/glusterd2/middleware"
 	"github.com/gluster/glusterd2/glusterd2/plugin"
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
 	"github.com/gluster/glusterd2/pkg/api"
@@ -37,7 +40,10 @@ func (r *GDRest) setRoutes(routes route.Routes) {
 			Methods(route.Method).
 			Path(urlPattern).
 			Name(route.Name).
-			Handler(route.HandlerFunc)
+			Handler(middleware.RequireRole(route.RequiredRole(),
+				middleware.RequireNamespace(
+					middleware.RequireOpVersion(route.OpVersion,
+						middleware.ValidateRequest(route.RequestType, route.HandlerFunc)))))
 
 		// Set our global copy of all routes
 		AllRoutes = append(AllRoutes, route)
@@ -62,7 +68,7 @@ func (r *GDRest) registerRoutes() {
 		p.RegisterStepFuncs()
 	}
 
-	// Expose /statedump and /endpoints handlers
+	// Expose /statedump, /metrics and /endpoints handlers
 	var moreRoutes route.Routes
 
 	if ok := config.GetBool("statedump"); ok {
@@ -73,6 +79,17 @@ func (r *GDRest) registerRoutes() {
 			HandlerFunc: expvar.Handler().(http.HandlerFunc)})
 	}
 
+	if ok := config.GetBool("metrics"); ok {
+		if err := metrics.Register(collector.New()); err != nil {
+			log.WithError(err).Error("failed to register metrics collector")
+		}
+		moreRoutes = append(moreRoutes, route.Route{
+			Name:        "Metrics",
+			Method:      "GET",
+			Pattern:     "/metrics",
+			HandlerFunc: metrics.Handler().ServeHTTP})
+	}
+
 	moreRoutes = append(moreRoutes, route.Route{
 		Name:         "List Endpoints",
 		Method:       "GET",
@@ -80,6 +97,13 @@ func (r *GDRest) registerRoutes() {
 		ResponseType: utils.GetTypeString((*api.ListEndpointsResp)(nil)),
 		HandlerFunc:  r.listEndpointsHandler()})
 
+	moreRoutes = append(moreRoutes, route.Route{
+		Name:        "OpenAPI",
+		Method:      "GET",
+		Pattern:     "/openapi.json",
+		Version:     1,
+		HandlerFunc: r.openAPIHandler()})
+
 	moreRoutes = append(moreRoutes, route.Route{
 		Name:        "Glusterd2 service status",
 		Method:      "GET",
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+)
+
+// ETag returns the entity tag of the response to the request, for the given
+// version of the resource. Responses to requests with different query
+// parameters, like fields or filters, have different tags.
+func ETag(r *http.Request, version string) string {
+	h := fnv.New32a()
+	h.Write([]byte(r.URL.RawQuery))
+	return fmt.Sprintf(`"%s-%08x"`, version, h.Sum32())
+}
+
+// matchETag returns true if the If-None-Match header value matches the
+// entity tag, using the weak comparison
+func matchETag(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckNotModified sets the ETag header of the response for the given
+// version of the resource. If the client already has this version, as told
+// by the If-None-Match header of the request, it sends a 304 Not Modified
+// response and returns true.
+func CheckNotModified(w http.ResponseWriter, r *http.Request, version string) bool {
+	etag := ETag(r, version)
+	w.Header().Set("ETag", etag)
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" && matchETag(inm, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckNotModified(t *testing.T) {
+	check := func(url, version, ifNoneMatch string) (bool, *httptest.ResponseRecorder) {
+		r := httptest.NewRequest("GET", url, nil)
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		return CheckNotModified(w, r, version), w
+	}
+
+	notModified, w := check("/v1/volumes/vol1", "1.10", "")
+	assert.False(t, notModified)
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	notModified, w = check("/v1/volumes/vol1", "1.10", etag)
+	assert.True(t, notModified)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+
+	notModified, _ = check("/v1/volumes/vol1", "1.10", `"other", W/`+etag)
+	assert.True(t, notModified)
+	notModified, _ = check("/v1/volumes/vol1", "1.10", "*")
+	assert.True(t, notModified)
+
+	// A new version of the resource has a different tag
+	notModified, w = check("/v1/volumes/vol1", "1.11", etag)
+	assert.False(t, notModified)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+
+	// And so do responses to other query parameters
+	notModified, _ = check("/v1/volumes/vol1?fields=name", "1.10", etag)
+	assert.False(t, notModified)
+}
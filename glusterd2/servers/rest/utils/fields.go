@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// GetFieldsParam returns the list of fields requested by the client using the
+// "fields" query parameter, for example "?fields=name,state". An empty list
+// is returned if the client did not ask for a subset of the fields.
+func GetFieldsParam(r *http.Request) []string {
+	var fields []string
+	for _, val := range r.URL.Query()["fields"] {
+		for _, f := range strings.Split(val, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				fields = append(fields, f)
+			}
+		}
+	}
+	return fields
+}
+
+// SelectFields returns a sparse representation of resp which contains only
+// the given top level JSON fields. resp must marshal either to a JSON object
+// or to a JSON array of objects, in which case the fields are selected from
+// every element. aliases maps alternate field names accepted from the client
+// to the JSON field names.
+func SelectFields(resp interface{}, fields []string, aliases map[string]string) (interface{}, error) {
+	if len(fields) == 0 {
+		return resp, nil
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &list); err == nil {
+		sparse := make([]map[string]json.RawMessage, len(list))
+		for idx, obj := range list {
+			sparse[idx] = selectObjectFields(obj, fields, aliases)
+		}
+		return sparse, nil
+	}
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	return selectObjectFields(obj, fields, aliases), nil
+}
+
+func selectObjectFields(obj map[string]json.RawMessage, fields []string, aliases map[string]string) map[string]json.RawMessage {
+	sparse := make(map[string]json.RawMessage, len(fields))
+	for _, f := range fields {
+		if alias, ok := aliases[f]; ok {
+			f = alias
+		}
+		// Fields tagged with omitempty may legitimately be absent
+		if val, ok := obj[f]; ok {
+			sparse[f] = val
+		}
+	}
+	return sparse
+}
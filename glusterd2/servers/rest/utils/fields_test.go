@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testFieldsBrick struct {
+	Path string `json:"path"`
+}
+
+type testFieldsVol struct {
+	Name    string            `json:"name"`
+	State   string            `json:"state"`
+	Bricks  []testFieldsBrick `json:"bricks"`
+	Options map[string]string `json:"options,omitempty"`
+}
+
+func TestGetFieldsParam(t *testing.T) {
+	tests := []struct {
+		query  string
+		fields []string
+	}{
+		{"", nil},
+		{"fields=", nil},
+		{"fields=,+,", nil},
+		{"fields=name", []string{"name"}},
+		{"fields=name,+state+", []string{"name", "state"}},
+		{"fields=name&fields=bricks.path", []string{"name", "bricks.path"}},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/v1/volumes?"+tt.query, nil)
+		assert.Equal(t, tt.fields, GetFieldsParam(r), tt.query)
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	vol := testFieldsVol{
+		Name:   "vol1",
+		State:  "Started",
+		Bricks: []testFieldsBrick{{Path: "/bricks/b1"}},
+	}
+	aliases := map[string]string{"status": "state"}
+
+	tests := []struct {
+		name   string
+		resp   interface{}
+		fields []string
+		want   string
+	}{
+		{"no selection", vol, nil, `{"name": "vol1", "state": "Started", "bricks": [{"path": "/bricks/b1"}]}`},
+		{"empty selection", vol, []string{}, `{"name": "vol1", "state": "Started", "bricks": [{"path": "/bricks/b1"}]}`},
+		{"top level fields", vol, []string{"name", "state"}, `{"name": "vol1", "state": "Started"}`},
+		{"alias", vol, []string{"status"}, `{"state": "Started"}`},
+		{"unknown field", vol, []string{"name", "size"}, `{"name": "vol1"}`},
+		{"only unknown fields", vol, []string{"size"}, `{}`},
+		{"omitted field", vol, []string{"options"}, `{}`},
+		{"nested object kept whole", vol, []string{"bricks"}, `{"bricks": [{"path": "/bricks/b1"}]}`},
+		{"nested field not selected", vol, []string{"name", "bricks.path"}, `{"name": "vol1"}`},
+		{"list", []testFieldsVol{vol, {Name: "vol2"}}, []string{"name"}, `[{"name": "vol1"}, {"name": "vol2"}]`},
+		{"empty list", []testFieldsVol{}, []string{"name"}, `[]`},
+	}
+
+	for _, tt := range tests {
+		sparse, err := SelectFields(tt.resp, tt.fields, aliases)
+		require.NoError(t, err, tt.name)
+		got, err := json.Marshal(sparse)
+		require.NoError(t, err, tt.name)
+		assert.JSONEq(t, tt.want, string(got), tt.name)
+	}
+
+	// Only JSON objects and lists of objects have fields
+	_, err := SelectFields("vol1", []string{"name"}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Page is the part of a collection asked for by a list request using the
+// "limit" query parameter along with either the "offset" or the "continue"
+// query parameter. A Limit of zero asks for all the items after Offset.
+type Page struct {
+	Offset int
+	Limit  int
+	// after is the key of the last item of the previous page, set if the
+	// page was asked for with a continuation token
+	after string
+}
+
+// pageToken is the content of a continuation token. Clients must treat the
+// token as opaque.
+type pageToken struct {
+	Offset int    `json:"o"`
+	After  string `json:"k"`
+}
+
+// GetPage returns the page asked for by the query parameters of a list
+// request. The continuation token is returned in the X-Continue header and
+// in the "next" link of every page which is not the last one.
+func GetPage(r *http.Request) (*Page, error) {
+	query := r.URL.Query()
+	page := new(Page)
+
+	if val := query.Get("limit"); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 0 {
+			return nil, errors.New("invalid limit")
+		}
+		page.Limit = n
+	}
+
+	if val := query.Get("continue"); val != "" {
+		if query.Get("offset") != "" {
+			return nil, errors.New("offset and continue can not be used together")
+		}
+		var token pageToken
+		data, err := base64.RawURLEncoding.DecodeString(val)
+		if err != nil || json.Unmarshal(data, &token) != nil || token.Offset < 0 {
+			return nil, errors.New("invalid continuation token")
+		}
+		page.Offset = token.Offset
+		page.after = token.After
+		return page, nil
+	}
+
+	if val := query.Get("offset"); val != "" {
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 0 {
+			return nil, errors.New("invalid offset")
+		}
+		page.Offset = n
+	}
+	return page, nil
+}
+
+// Apply returns the bounds [start, end) of the page in an ordered collection
+// of n items, where key returns a key which identifies the item at an index,
+// like its name or ID. It sets the X-Total-Count, X-Continue and Link headers
+// of the response.
+//
+// A page asked for with a continuation token starts right after the last
+// item of the previous page, even if items before it were added or removed
+// in the meantime. If that item is gone, the page starts at the offset at
+// which the previous page ended.
+func (p *Page) Apply(w http.ResponseWriter, r *http.Request, n int, key func(i int) string) (start, end int) {
+	start = p.Offset
+	if p.after != "" && (start == 0 || start > n || key(start-1) != p.after) {
+		for i := 0; i < n; i++ {
+			if key(i) == p.after {
+				start = i + 1
+				break
+			}
+		}
+	}
+	if start > n {
+		start = n
+	}
+
+	end = n
+	if p.Limit > 0 && start+p.Limit < n {
+		end = start + p.Limit
+	}
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(n))
+
+	var links []string
+	if end < n {
+		data, _ := json.Marshal(pageToken{Offset: end, After: key(end - 1)})
+		token := base64.RawURLEncoding.EncodeToString(data)
+		w.Header().Set("X-Continue", token)
+		links = append(links, pageLink(r, "next", map[string]string{"continue": token}))
+	}
+	if p.Limit > 0 && start > 0 {
+		prev := start - p.Limit
+		if prev < 0 {
+			prev = 0
+		}
+		links = append(links, pageLink(r, "prev", map[string]string{"offset": strconv.Itoa(prev)}))
+		links = append(links, pageLink(r, "first", map[string]string{"offset": "0"}))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+
+	return start, end
+}
+
+// pageLink returns a Link header value (RFC 5988) for the request URL with
+// the pagination query parameters replaced by params
+func pageLink(r *http.Request, rel string, params map[string]string) string {
+	query := r.URL.Query()
+	query.Del("offset")
+	query.Del("continue")
+	for k, v := range params {
+		query.Set(k, v)
+	}
+	u := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	return fmt.Sprintf("<%s>; rel=%q", u.String(), rel)
+}
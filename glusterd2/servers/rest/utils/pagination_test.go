@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPagination(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+	key := func(i int) string { return items[i] }
+
+	getPage := func(query string) (start, end int, h http.Header) {
+		r := httptest.NewRequest("GET", "/v1/items?"+query, nil)
+		page, err := GetPage(r)
+		require.NoError(t, err, query)
+		w := httptest.NewRecorder()
+		start, end = page.Apply(w, r, len(items), key)
+		return start, end, w.Header()
+	}
+
+	start, end, h := getPage("")
+	assert.Equal(t, []int{0, 5}, []int{start, end})
+	assert.Equal(t, "5", h.Get("X-Total-Count"))
+	assert.Empty(t, h.Get("X-Continue"))
+	assert.Empty(t, h.Get("Link"))
+
+	start, end, h = getPage("limit=2&offset=1")
+	assert.Equal(t, []int{1, 3}, []int{start, end})
+	assert.Contains(t, h.Get("Link"), `rel="next"`)
+	assert.Contains(t, h.Get("Link"), `</v1/items?limit=2&offset=0>; rel="prev"`)
+
+	start, end, h = getPage("limit=2")
+	assert.Equal(t, []int{0, 2}, []int{start, end})
+	token := h.Get("X-Continue")
+	require.NotEmpty(t, token)
+	assert.Contains(t, h.Get("Link"), `</v1/items?continue=`+token+`&limit=2>; rel="next"`)
+
+	start, end, _ = getPage("limit=2&continue=" + token)
+	assert.Equal(t, []int{2, 4}, []int{start, end})
+
+	// The next page starts after the last item of the previous page even
+	// if an item was added before it
+	items = []string{"0", "a", "b", "c", "d", "e"}
+	start, end, _ = getPage("limit=2&continue=" + token)
+	assert.Equal(t, []int{3, 5}, []int{start, end})
+
+	// or at the same offset if the last item itself was removed
+	items = []string{"a", "c", "d", "e"}
+	start, end, h = getPage("limit=2&continue=" + token)
+	assert.Equal(t, []int{2, 4}, []int{start, end})
+	assert.Empty(t, h.Get("X-Continue"))
+
+	start, end, _ = getPage("offset=10")
+	assert.Equal(t, []int{4, 4}, []int{start, end})
+
+	for _, query := range []string{"limit=-1", "offset=x", "continue=!", "continue=e30&offset=1"} {
+		_, err := GetPage(httptest.NewRequest("GET", "/v1/items?"+query, nil))
+		assert.Error(t, err, query)
+	}
+}
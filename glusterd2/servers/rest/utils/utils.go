@@ -30,6 +30,20 @@ func SetLocationHeader(r *http.Request, w http.ResponseWriter, resourceID string
 	w.Header().Set("Location", relativeURL)
 }
 
+// IsAsyncRequest returns true if the client asked for the request to be
+// served asynchronously, by the async=true query parameter
+func IsAsyncRequest(r *http.Request) bool {
+	return r.URL.Query().Get("async") == "true"
+}
+
+// SendJobAccepted sends the response to a request which is served
+// asynchronously by the given job, which has the URL of the job in its
+// Location header
+func SendJobAccepted(ctx context.Context, w http.ResponseWriter, job *api.JobInfo) {
+	w.Header().Set("Location", "/v1/jobs/"+job.ID.String())
+	SendHTTPResponse(ctx, w, http.StatusAccepted, job)
+}
+
 // SendHTTPResponse sends non-error response to the client.
 func SendHTTPResponse(ctx context.Context, w http.ResponseWriter, statusCode int, resp interface{}) {
 
@@ -100,8 +114,38 @@ func ErrToStatusCode(err error) (int, error) {
 		statuscode = http.StatusNotFound
 	case gderrors.ErrSnapNotFound:
 		statuscode = http.StatusNotFound
+	case gderrors.ErrTxnNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrLockNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrScheduleNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrScheduleExists:
+		statuscode = http.StatusConflict
+	case gderrors.ErrSnapPolicyNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrSnapPolicyExists:
+		statuscode = http.StatusConflict
+	case gderrors.ErrSnapCountLimitReached:
+		statuscode = http.StatusConflict
+	case gderrors.ErrSnapCapacityLimitReached:
+		statuscode = http.StatusConflict
+	case gderrors.ErrJobNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrJobNotRunning:
+		statuscode = http.StatusConflict
+	case gderrors.ErrUserNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrUserExists:
+		statuscode = http.StatusConflict
+	case gderrors.ErrWebhookNotFound:
+		statuscode = http.StatusNotFound
+	case gderrors.ErrVolfileTemplateNotFound:
+		statuscode = http.StatusNotFound
 	case transaction.ErrLockTimeout:
 		statuscode = http.StatusConflict
+	case gderrors.ErrTxnNotRunning:
+		statuscode = http.StatusConflict
 	default:
 		statuscode = http.StatusInternalServerError
 	}
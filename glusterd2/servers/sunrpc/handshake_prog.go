@@ -120,11 +120,15 @@ func (p *GfHandshake) ServerGetspec(args *GfGetspecReq, reply *GfGetspecRsp) err
 		volinfo  *volume.Volinfo
 	)
 
-	_, err = dict.Unserialize(args.Xdata)
+	reqDict, err := dict.Unserialize(args.Xdata)
 	if err != nil {
 		log.WithError(err).Error("ServerGetspec(): dict.Unserialize() failed")
 	}
 
+	// Clients send the highest op-version they support, the volfile is
+	// generated without the xlators and options they don't know about
+	clientOpVersion, _ := strconv.Atoi(reqDict["max-op-version"])
+
 	log.WithFields(log.Fields{
 		"client":     p.GetConn().RemoteAddr().String(),
 		"volfile-id": args.Key,
@@ -176,6 +180,10 @@ func (p *GfHandshake) ServerGetspec(args *GfGetspecReq, reply *GfGetspecRsp) err
 			goto Out
 		}
 
+		if clientOpVersion > 0 {
+			tmpl = tmpl.ForOpVersion(clientOpVersion)
+		}
+
 		reply.Spec, err = volgen.VolumeLevelVolfile(tmpl, volinfo)
 		if err != nil {
 			log.WithError(err).WithField(
@@ -35,6 +35,7 @@ var programsList = []sunrpc.Program{
 // SunRPC implements a suture service
 type SunRPC struct {
 	tcpListener   net.Listener
+	tlsListener   net.Listener
 	tcpStopCh     chan struct{}
 	unixListener  net.Listener
 	unixStopCh    chan struct{}
@@ -52,8 +53,10 @@ var clientsList = struct {
 	c: make(map[net.Conn]struct{}),
 }
 
-// NewMuxed returns a SunRPC server configured to listen on a CMux multiplexed connection
-func NewMuxed(m cmux.CMux) *SunRPC {
+// NewMuxed returns a SunRPC server configured to listen on a CMux multiplexed
+// connection, and also on tlsM, which multiplexes the decrypted SSL/TLS
+// connections, if SSL/TLS is enabled
+func NewMuxed(m cmux.CMux, tlsM cmux.CMux) *SunRPC {
 
 	f := path.Join(config.GetString("rundir"), gd2SocketFile)
 	gd2LockFile := f + ".lock"
@@ -89,6 +92,9 @@ func NewMuxed(m cmux.CMux) *SunRPC {
 		notifyCloseCh: make(chan io.ReadWriteCloser, 10),
 		lockFileFd:    fd,
 	}
+	if tlsM != nil {
+		srv.tlsListener = tlsM.Match(sunrpc.CmuxMatcher())
+	}
 
 	for _, prog := range programsList {
 		err := registerProcedures(prog)
@@ -205,6 +211,11 @@ func (s *SunRPC) Serve() {
 	wg.Add(1)
 	go s.acceptLoop(s.tcpStopCh, s.tcpListener, wg)
 
+	if s.tlsListener != nil {
+		wg.Add(1)
+		go s.acceptLoop(s.tcpStopCh, s.tlsListener, wg)
+	}
+
 	wg.Add(1)
 	go s.acceptLoop(s.unixStopCh, s.unixListener, wg)
 
@@ -0,0 +1,225 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/scheduler"
+	gdstore "github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	policyPrefix = "snapshot-policies/"
+	maxRetention = 256
+)
+
+// Policy takes snapshots of the volumes it selects as per a cron expression,
+// keeping the latest Retention snapshots taken by it of each volume. All
+// times are in UTC.
+type Policy struct {
+	Name          string    `json:"name"`
+	Cron          string    `json:"cron"`
+	Retention     int       `json:"retention"`
+	Volumes       []string  `json:"volumes,omitempty"`
+	LabelSelector string    `json:"label-selector,omitempty"`
+	Description   string    `json:"description,omitempty"`
+	CreatedBy     string    `json:"created-by,omitempty"`
+	CreatedAt     time.Time `json:"created-at"`
+	NextRun       time.Time `json:"next-run"`
+	LastRun       time.Time `json:"last-run,omitempty"`
+	LastStatus    string    `json:"last-status,omitempty"`
+	LastError     string    `json:"last-error,omitempty"`
+}
+
+// NewPolicy validates the request and returns a new policy for it
+func NewPolicy(req *api.SnapPolicyCreateReq, user string) (*Policy, error) {
+	if !volume.IsValidName(req.Name) {
+		return nil, fmt.Errorf("invalid snapshot policy name: %s", req.Name)
+	}
+
+	if req.Retention < 1 || req.Retention > maxRetention {
+		return nil, gderrors.ErrSnapPolicyInvalidRetention
+	}
+
+	if len(req.Volumes) == 0 && req.LabelSelector == "" {
+		return nil, gderrors.ErrSnapPolicyNoSelector
+	}
+	if _, err := volume.ParseLabelSelector(req.LabelSelector); err != nil {
+		return nil, err
+	}
+	for _, pattern := range req.Volumes {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid volume pattern %q", pattern)
+		}
+	}
+
+	now := time.Now().UTC()
+	next, err := scheduler.NextRun(req.Cron, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Policy{
+		Name:          req.Name,
+		Cron:          req.Cron,
+		Retention:     req.Retention,
+		Volumes:       req.Volumes,
+		LabelSelector: req.LabelSelector,
+		Description:   req.Description,
+		CreatedBy:     user,
+		CreatedAt:     now,
+		NextRun:       next,
+	}, nil
+}
+
+// Selects returns true if the policy takes snapshots of the volume
+func (p *Policy) Selects(v *volume.Volinfo) bool {
+	if len(p.Volumes) != 0 {
+		matched := false
+		for _, pattern := range p.Volumes {
+			if ok, _ := path.Match(pattern, v.Name); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	ls, err := volume.ParseLabelSelector(p.LabelSelector)
+	if err != nil {
+		return false
+	}
+	return ls.Matches(v.Labels)
+}
+
+// AddPolicy saves a new snapshot policy in the store
+func AddPolicy(p *Policy) error {
+	v, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	key := policyPrefix + p.Name
+	resp, err := gdstore.Txn(context.TODO()).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, string(v))).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		return gderrors.ErrSnapPolicyExists
+	}
+	return nil
+}
+
+// GetPolicy returns the snapshot policy with the given name
+func GetPolicy(name string) (*Policy, error) {
+	resp, err := gdstore.Get(context.TODO(), policyPrefix+name)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, gderrors.ErrSnapPolicyNotFound
+	}
+
+	var p Policy
+	if err := json.Unmarshal(resp.Kvs[0].Value, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetPolicies returns all the snapshot policies
+func GetPolicies() ([]*Policy, error) {
+	resp, err := gdstore.Get(context.TODO(), policyPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make([]*Policy, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var p Policy
+		if err := json.Unmarshal(kv.Value, &p); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal snapshot policy")
+			continue
+		}
+		policies = append(policies, &p)
+	}
+	return policies, nil
+}
+
+// DeletePolicy removes the snapshot policy with the given name. The
+// snapshots taken by the policy are kept.
+func DeletePolicy(name string) error {
+	resp, err := gdstore.Delete(context.TODO(), policyPrefix+name)
+	if err != nil {
+		return err
+	}
+	if resp.Deleted == 0 {
+		return gderrors.ErrSnapPolicyNotFound
+	}
+	return nil
+}
+
+// UpdatePolicy applies update to the snapshot policy with the given name and
+// saves it, unless update returns false or the policy was changed in the
+// meantime. It returns whether the policy was saved.
+func UpdatePolicy(name string, update func(p *Policy) bool) (bool, error) {
+	key := policyPrefix + name
+	resp, err := gdstore.Get(context.TODO(), key)
+	if err != nil || resp.Count != 1 {
+		return false, err
+	}
+
+	var p Policy
+	if err := json.Unmarshal(resp.Kvs[0].Value, &p); err != nil {
+		return false, err
+	}
+	if !update(&p) {
+		return false, nil
+	}
+
+	v, err := json.Marshal(&p)
+	if err != nil {
+		return false, err
+	}
+
+	tresp, err := gdstore.Txn(context.TODO()).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", resp.Kvs[0].ModRevision)).
+		Then(clientv3.OpPut(key, string(v))).
+		Commit()
+	if err != nil {
+		return false, err
+	}
+	return tresp.Succeeded, nil
+}
+
+// CreatePolicyInfoResp returns the API representation of a snapshot policy
+func CreatePolicyInfoResp(p *Policy) *api.SnapPolicyInfo {
+	return &api.SnapPolicyInfo{
+		Name:          p.Name,
+		Cron:          p.Cron,
+		Retention:     p.Retention,
+		Volumes:       p.Volumes,
+		LabelSelector: p.LabelSelector,
+		Description:   p.Description,
+		CreatedBy:     p.CreatedBy,
+		CreatedAt:     p.CreatedAt,
+		NextRun:       p.NextRun,
+		LastRun:       p.LastRun,
+		LastStatus:    p.LastStatus,
+		LastError:     p.LastError,
+	}
+}
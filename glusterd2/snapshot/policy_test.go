@@ -0,0 +1,55 @@
+package snapshot
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPolicy(t *testing.T) {
+	req := api.SnapPolicyCreateReq{
+		Name:      "nightly",
+		Cron:      "0 2 * * *",
+		Retention: 7,
+		Volumes:   []string{"db-*"},
+	}
+	p, err := NewPolicy(&req, "admin")
+	require.Nil(t, err)
+	assert.False(t, p.NextRun.IsZero())
+	assert.Equal(t, "admin", p.CreatedBy)
+
+	for _, invalid := range []func(r *api.SnapPolicyCreateReq){
+		func(r *api.SnapPolicyCreateReq) { r.Name = "night ly" },
+		func(r *api.SnapPolicyCreateReq) { r.Cron = "0 2 * *" },
+		func(r *api.SnapPolicyCreateReq) { r.Retention = 0 },
+		func(r *api.SnapPolicyCreateReq) { r.Retention = 1000 },
+		func(r *api.SnapPolicyCreateReq) { r.Volumes = nil },
+		func(r *api.SnapPolicyCreateReq) { r.Volumes = []string{"db-["} },
+		func(r *api.SnapPolicyCreateReq) { r.LabelSelector = "tier=gold,=" },
+	} {
+		r := req
+		invalid(&r)
+		_, err := NewPolicy(&r, "admin")
+		assert.Error(t, err)
+	}
+}
+
+func TestPolicySelects(t *testing.T) {
+	p := &Policy{
+		Volumes:       []string{"db-*", "logs"},
+		LabelSelector: "backup=true",
+	}
+
+	backup := map[string]string{"backup": "true"}
+	assert.True(t, p.Selects(&volume.Volinfo{Name: "db-1", Labels: backup}))
+	assert.True(t, p.Selects(&volume.Volinfo{Name: "logs", Labels: backup}))
+	assert.False(t, p.Selects(&volume.Volinfo{Name: "web", Labels: backup}))
+	assert.False(t, p.Selects(&volume.Volinfo{Name: "db-1"}))
+
+	p.Volumes = nil
+	assert.True(t, p.Selects(&volume.Volinfo{Name: "web", Labels: backup}))
+}
@@ -0,0 +1,128 @@
+package snapshot
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/fsutils"
+	"github.com/gluster/glusterd2/pkg/lvmutils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Bricks provisioned by the loop provisioner are filesystems in files, which
+// are mounted through loop devices. A snapshot of such a brick is a copy of
+// its file, taken while its filesystem is frozen, and kept next to it. The
+// copy shares the blocks of the file if the filesystem of the device
+// supports reflinks, and is a full copy otherwise, so these snapshots are
+// meant for development and test clusters.
+
+// isLoop returns true if the volume or snapshot, with the given provisioner
+// type, has bricks provisioned by the loop provisioner
+func isLoop(provisionerType string) bool {
+	return provisionerType == api.ProvisionerTypeLoop
+}
+
+// brickSnapshotSupported returns true if snapshots of the brick can be taken
+func brickSnapshotSupported(provisionerType string, b *brick.Brickinfo) bool {
+	if isLoop(provisionerType) {
+		fi, err := os.Stat(b.MountInfo.DevicePath)
+		return err == nil && fi.Mode().IsRegular()
+	}
+
+	mntInfo, err := getBrickMountInfo(b)
+	if err != nil {
+		return false
+	}
+	return lvmutils.FsCompatibleCheck(mntInfo.FsName)
+}
+
+// brickPoolUsage returns the usage, in percent, of the space snapshots of
+// the brick are taken from. This is the thin pool of the brick, or the
+// filesystem holding the file of a loop brick.
+func brickPoolUsage(provisionerType string, b *brick.Brickinfo) (float32, error) {
+	if isLoop(provisionerType) {
+		stat, err := fsutils.StatFs(path.Dir(b.MountInfo.DevicePath))
+		if err != nil {
+			return 0, err
+		}
+		if stat.Total == 0 {
+			return 0, nil
+		}
+		return float32(stat.Total-stat.Free) * 100 / float32(stat.Total), nil
+	}
+
+	mntInfo, err := getBrickMountInfo(b)
+	if err != nil {
+		return 0, err
+	}
+	return lvmutils.GetPoolDataPercentage(mntInfo.FsName)
+}
+
+func getBrickMountInfo(b *brick.Brickinfo) (*volume.Mntent, error) {
+	mountRoot, err := volume.GetBrickMountRoot(b.Path)
+	if err != nil {
+		return nil, err
+	}
+	return volume.GetBrickMountInfo(mountRoot)
+}
+
+// CreateBrickSnapDevicePath returns the device of a new snapshot brick with
+// the given name, taken of the brick mounted from mntInfo
+func CreateBrickSnapDevicePath(provisionerType string, b *brick.Brickinfo, mntInfo *volume.Mntent, name string) (string, error) {
+	if !isLoop(provisionerType) {
+		return lvmutils.CreateDevicePath(mntInfo.FsName, name)
+	}
+
+	devicePath := path.Join(path.Dir(b.MountInfo.DevicePath), name+".img")
+	if _, err := os.Stat(devicePath); err == nil {
+		return "", fmt.Errorf("failed to create device name %s for brick %s, a file with same name exists", devicePath, b.String())
+	}
+	return devicePath, nil
+}
+
+// TakeBrickSnapshot takes a snapshot of the brick, mounted from mntInfo, on
+// the given device
+func TakeBrickSnapshot(provisionerType string, b *brick.Brickinfo, mntInfo *volume.Mntent, devicePath string) error {
+	if !isLoop(provisionerType) {
+		return lvmutils.LVSnapshot(mntInfo.FsName, devicePath)
+	}
+
+	mountRoot := strings.TrimSuffix(b.Path, b.MountInfo.BrickDirSuffix)
+	if err := fsutils.Freeze(mountRoot); err != nil {
+		return err
+	}
+	defer func() {
+		if err := fsutils.Thaw(mountRoot); err != nil {
+			log.WithError(err).WithField("path", mountRoot).Error("failed to thaw brick filesystem")
+		}
+	}()
+
+	return fsutils.CopySparseFile(b.MountInfo.DevicePath, devicePath)
+}
+
+// RemoveBrickSnapshot removes the device of a snapshot brick
+func RemoveBrickSnapshot(provisionerType, devicePath string) error {
+	if !isLoop(provisionerType) {
+		return lvmutils.RemoveLVSnapshot(devicePath)
+	}
+
+	if err := os.Remove(devicePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// ValidateSnapshotCommands checks if the commands needed to take snapshots
+// of bricks with the given provisioner are installed
+func ValidateSnapshotCommands(provisionerType string) error {
+	if isLoop(provisionerType) {
+		return nil
+	}
+	return lvmutils.CommonPrevalidation(lvmutils.CreateCommand)
+}
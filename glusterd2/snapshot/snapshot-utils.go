@@ -114,14 +114,8 @@ func CheckBricksFsCompatability(volinfo *volume.Volinfo) []string {
 
 	var paths []string
 	for _, brick := range volinfo.GetLocalBricks() {
-		mountRoot, err := volume.GetBrickMountRoot(brick.Path)
-		if err == nil {
-			mntInfo, err := volume.GetBrickMountInfo(mountRoot)
-			if err == nil {
-				if lvmutils.FsCompatibleCheck(mntInfo.FsName) {
-					continue
-				}
-			}
+		if brickSnapshotSupported(volinfo.ProvisionerType, &brick) {
+			continue
 		}
 		paths = append(paths, brick.String())
 	}
@@ -129,19 +123,30 @@ func CheckBricksFsCompatability(volinfo *volume.Volinfo) []string {
 
 }
 
+// BricksPoolUsage returns the highest usage, in percent, of the thin pools
+// of the local bricks of the volume
+func BricksPoolUsage(volinfo *volume.Volinfo) (float32, error) {
+	var usage float32
+	for _, brick := range volinfo.GetLocalBricks() {
+		used, err := brickPoolUsage(volinfo.ProvisionerType, &brick)
+		if err != nil {
+			return 0, err
+		}
+		if used > usage {
+			usage = used
+		}
+	}
+	return usage, nil
+}
+
 //CheckBricksSizeCompatability will verify the device has enough space
 func CheckBricksSizeCompatability(volinfo *volume.Volinfo) []string {
 
 	var paths []string
 	for _, brick := range volinfo.GetLocalBricks() {
-		mountRoot, err := volume.GetBrickMountRoot(brick.Path)
-		if err == nil {
-			mntInfo, err := volume.GetBrickMountInfo(mountRoot)
-			if err == nil {
-				if lvmutils.SizeCompatibleCheck(mntInfo.FsName) {
-					continue
-				}
-			}
+		used, err := brickPoolUsage(volinfo.ProvisionerType, &brick)
+		if err == nil && used < float32(lvmutils.MaxSizePercentage) {
+			continue
 		}
 		paths = append(paths, brick.String())
 	}
@@ -14,4 +14,10 @@ type Snapinfo struct {
 	Description  string
 	OptionChange map[string]string
 	CreatedAt    time.Time
+	// Policy is the name of the snapshot policy which took the snapshot,
+	// if any
+	Policy string
+	// ExpiresAt is the time at which the snapshot is deactivated, if it
+	// was activated with a time-to-live
+	ExpiresAt time.Time
 }
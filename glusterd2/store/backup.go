@@ -0,0 +1,196 @@
+package store
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+const (
+	// BackupVersion is the version of the backup format
+	BackupVersion = 1
+
+	// backupRestoredKey records the backup restored into the store, so
+	// that it is not restored again when glusterd2 is restarted with the
+	// same --restore-backup option
+	backupRestoredKey = "backup/restored"
+
+	// etcd limits the number of operations of a transaction to 128 by
+	// default, and the size of requests to 1.5MiB
+	maxTxnOps   = 128
+	maxTxnBytes = 1024 * 1024
+)
+
+// BackupKey is a key of the store saved in a backup
+type BackupKey struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// Backup is a consistent snapshot of the keys glusterd2 keeps in the store
+// for a cluster. Keys attached to a lease, like the liveness keys of the
+// peers and the locks, belong to running glusterd2 instances and are not
+// saved.
+type Backup struct {
+	Version   int         `json:"version"`
+	ClusterID string      `json:"cluster-id"`
+	Revision  int64       `json:"revision"`
+	CreatedAt time.Time   `json:"created-at"`
+	Keys      []BackupKey `json:"keys"`
+}
+
+// id identifies the backup in the restored marker key
+func (b *Backup) id() string {
+	return fmt.Sprintf("%s/%d", b.ClusterID, b.Revision)
+}
+
+// Backup takes a backup of all the keys of the cluster in the store. The
+// keys are read at a single revision of the store.
+func (s *GDStore) Backup(ctx context.Context) (*Backup, error) {
+	resp, err := s.Get(ctx, "", clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Backup{
+		Version:   BackupVersion,
+		ClusterID: gdctx.MyClusterID.String(),
+		Revision:  resp.Header.Revision,
+		CreatedAt: time.Now().UTC(),
+		Keys:      make([]BackupKey, 0, len(resp.Kvs)),
+	}
+	for _, kv := range resp.Kvs {
+		if kv.Lease != 0 || string(kv.Key) == backupRestoredKey {
+			continue
+		}
+		b.Keys = append(b.Keys, BackupKey{Key: string(kv.Key), Value: kv.Value})
+	}
+	return b, nil
+}
+
+// WriteBackup writes the backup to w as gzip compressed JSON
+func WriteBackup(w io.Writer, b *Backup) error {
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(b); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// ReadBackup reads a backup written by WriteBackup
+func ReadBackup(r io.Reader) (*Backup, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var b Backup
+	if err := json.NewDecoder(zr).Decode(&b); err != nil {
+		return nil, err
+	}
+	if b.Version != BackupVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", b.Version)
+	}
+	if b.ClusterID == "" {
+		return nil, fmt.Errorf("backup has no cluster ID")
+	}
+	return &b, nil
+}
+
+// ReadBackupFile reads the backup saved in the file at path
+func ReadBackupFile(path string) (*Backup, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadBackup(f)
+}
+
+// commitInBatches commits the operations in as many transactions as the
+// limits of etcd require
+func commitInBatches(ctx context.Context, kv clientv3.KV, ops []clientv3.Op, sizes []int) error {
+	for len(ops) > 0 {
+		n, size := 0, 0
+		for n < len(ops) && n < maxTxnOps && (n == 0 || size+sizes[n] <= maxTxnBytes) {
+			size += sizes[n]
+			n++
+		}
+		if _, err := kv.Txn(ctx).Then(ops[:n]...).Commit(); err != nil {
+			return err
+		}
+		ops, sizes = ops[n:], sizes[n:]
+	}
+	return nil
+}
+
+// Restore replaces the keys of the cluster in the store with the keys saved
+// in the backup. Keys attached to a lease are kept. The keys are not replaced
+// atomically, so Restore must only be used while no requests are served.
+// It does nothing if the backup has already been restored.
+func (s *GDStore) Restore(ctx context.Context, b *Backup) error {
+	resp, err := s.Get(ctx, backupRestoredKey)
+	if err != nil {
+		return err
+	}
+	if resp.Count == 1 && string(resp.Kvs[0].Value) == b.id() {
+		log.WithField("backup", b.id()).Info("backup already restored, skipping restore")
+		return nil
+	}
+
+	resp, err = s.Get(ctx, "", clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return err
+	}
+	// etcd rejects transactions changing a key twice, the keys of the
+	// backup are overwritten instead of being deleted
+	restored := make(map[string]bool, len(b.Keys))
+	for _, k := range b.Keys {
+		restored[k.Key] = true
+	}
+	var (
+		ops   []clientv3.Op
+		sizes []int
+	)
+	for _, kv := range resp.Kvs {
+		if kv.Lease != 0 || restored[string(kv.Key)] || string(kv.Key) == backupRestoredKey {
+			continue
+		}
+		ops = append(ops, clientv3.OpDelete(string(kv.Key)))
+		sizes = append(sizes, len(kv.Key))
+	}
+	for _, k := range b.Keys {
+		ops = append(ops, clientv3.OpPut(k.Key, string(k.Value)))
+		sizes = append(sizes, len(k.Key)+len(k.Value))
+	}
+	ops = append(ops, clientv3.OpPut(backupRestoredKey, b.id()))
+	sizes = append(sizes, len(backupRestoredKey)+len(b.id()))
+
+	if err := commitInBatches(ctx, s.KV, ops, sizes); err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"backup":     b.id(),
+		"created-at": b.CreatedAt,
+		"keys":       len(b.Keys),
+	}).Info("restored store from backup")
+	return nil
+}
+
+// RestoreBackupFile returns the path of the backup to restore at startup,
+// set with the --restore-backup option
+func RestoreBackupFile() string {
+	return config.GetString(restoreBackupOpt)
+}
@@ -0,0 +1,56 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupReadWrite(t *testing.T) {
+	b := &Backup{
+		Version:   BackupVersion,
+		ClusterID: "6d3e1c0b-2f4a-4b5c-8d9e-0f1a2b3c4d5e",
+		Revision:  42,
+		CreatedAt: time.Now().UTC().Round(time.Second),
+		Keys: []BackupKey{
+			{Key: "volumes/gv0", Value: []byte(`{"name":"gv0"}`)},
+			{Key: "peers/empty", Value: []byte{}},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteBackup(&buf, b))
+	got, err := ReadBackup(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, b.ClusterID, got.ClusterID)
+	assert.Equal(t, b.Revision, got.Revision)
+	assert.True(t, b.CreatedAt.Equal(got.CreatedAt))
+	assert.Equal(t, b.Keys, got.Keys)
+	assert.Equal(t, b.id(), got.id())
+}
+
+func TestReadBackupInvalid(t *testing.T) {
+	_, err := ReadBackup(bytes.NewBufferString(`{"version":1}`))
+	assert.Error(t, err)
+
+	for _, b := range []*Backup{
+		{Version: BackupVersion + 1, ClusterID: "6d3e1c0b-2f4a-4b5c-8d9e-0f1a2b3c4d5e"},
+		{Version: BackupVersion},
+	} {
+		var buf bytes.Buffer
+		require.NoError(t, WriteBackup(&buf, b))
+		_, err := ReadBackup(&buf)
+		assert.Error(t, err)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte("not json"))
+	zw.Close()
+	_, err = ReadBackup(&buf)
+	assert.Error(t, err)
+}
@@ -37,7 +37,9 @@ const (
 	keyFileOpt  = "key-file"
 
 	// common options
-	storeConfFile = "store.toml"
+	storeConfFile    = "store.toml"
+	restoreBackupOpt = "restore-backup"
+	migrateKeysOpt   = "migrate-unprefixed-keys"
 )
 
 // InitFlags intializes the command line options for the GD2 store
@@ -52,6 +54,9 @@ func InitFlags() {
 	flag.String(etcdClientCertFileOpt, "", "identify secure etcd client using this TLS certificate file")
 	flag.String(etcdClientKeyFileOpt, "", "identify secure etcd client using this TLS key file")
 	flag.String(etcdClientCAFileOpt, "", "verify certificates of TLS-enabled secure etcd servers using this CA bundle")
+
+	flag.String(restoreBackupOpt, "", "Restore the store from this cluster backup at startup, if it has not been restored already.")
+	flag.Bool(migrateKeysOpt, false, "Move the keys written at the root of the store by older glusterd2 versions into the namespace of the cluster at startup.")
 }
 
 // Config is the GD2 store configuration
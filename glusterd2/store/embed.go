@@ -6,6 +6,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/pkg/elasticetcd"
 
+	"github.com/coreos/etcd/clientv3"
 	"github.com/coreos/etcd/pkg/types"
 	log "github.com/sirupsen/logrus"
 	config "github.com/spf13/viper"
@@ -47,6 +48,19 @@ func newEmbedStore(sconf *Config) (*GDStore, error) {
 	return gds, nil
 }
 
+// IsEmbedded returns true if the store is the etcd cluster embedded in
+// glusterd2
+func (s *GDStore) IsEmbedded() bool {
+	return s.ee != nil
+}
+
+// NewMemberClient returns a new un-namespaced client connected only to the
+// given client URLs of a member of the embedded store. The caller must close
+// the client.
+func (s *GDStore) NewMemberClient(clientURLs []string) (*clientv3.Client, error) {
+	return s.ee.NewClient(clientURLs)
+}
+
 func (s *GDStore) closeEmbedStore() {
 	log.Debug("stopping embedded store")
 	s.ee.Stop()
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+// unprefixedKeyExcludes are the prefixes of the keys at the root of the
+// store which are not keys of a cluster: the namespaces of the glusterd2
+// clusters, and the keys of the embedded etcd
+var unprefixedKeyExcludes = []string{"gluster-", "elastic"}
+
+// migration are the operations moving the unprefixed keys into a namespace
+type migration struct {
+	ops   []clientv3.Op
+	sizes []int
+	// moved are the keys moved into the namespace, conflicts the keys left
+	// at the root as a different value is already set in the namespace
+	moved     []string
+	conflicts []string
+}
+
+// newMigration returns the operations moving the keys at the root of the
+// store into the namespace. Keys attached to a lease belong to a running
+// peer and are left alone. A key already in the namespace with the same
+// value, from an interrupted migration, is only deleted from the root.
+func newMigration(namespace string, root, namespaced []*mvccpb.KeyValue) *migration {
+	existing := make(map[string][]byte, len(namespaced))
+	for _, kv := range namespaced {
+		existing[string(kv.Key)] = kv.Value
+	}
+
+	m := &migration{}
+	for _, kv := range root {
+		key := string(kv.Key)
+		if kv.Lease != 0 || isExcludedFromMigration(key) {
+			continue
+		}
+		value, ok := existing[namespace+key]
+		if ok && !bytes.Equal(value, kv.Value) {
+			m.conflicts = append(m.conflicts, key)
+			continue
+		}
+		// The put and the delete of a key are kept in the same
+		// transaction, so that a key is never lost
+		if !ok {
+			m.ops = append(m.ops, clientv3.OpPut(namespace+key, string(kv.Value)))
+			m.sizes = append(m.sizes, len(namespace)+len(kv.Key)+len(kv.Value))
+		}
+		m.ops = append(m.ops, clientv3.OpDelete(key))
+		m.sizes = append(m.sizes, len(kv.Key))
+		m.moved = append(m.moved, key)
+	}
+	return m
+}
+
+func isExcludedFromMigration(key string) bool {
+	for _, prefix := range unprefixedKeyExcludes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MigrateUnprefixedKeys moves the keys written at the root of the store, by
+// glusterd2 versions which didn't prefix the keys with the cluster ID, into
+// the namespace of the cluster. Every key which is not in the namespace of a
+// cluster is moved, so it must only be used on an etcd cluster which has not
+// been shared yet. Like Restore, it must only be used while no requests are
+// served.
+func (s *GDStore) MigrateUnprefixedKeys(ctx context.Context) error {
+	root, err := s.Client.Get(ctx, "", clientv3.WithPrefix())
+	if err != nil {
+		return err
+	}
+	namespaced, err := s.Client.Get(ctx, s.namespace, clientv3.WithPrefix())
+	if err != nil {
+		return err
+	}
+
+	m := newMigration(s.namespace, root.Kvs, namespaced.Kvs)
+	for _, key := range m.conflicts {
+		log.WithField("key", key).WithField("namespace", s.namespace).Warn("key is already set in the namespace with another value, not migrating it")
+	}
+	if len(m.moved) == 0 {
+		log.WithField("conflicts", len(m.conflicts)).Info("no unprefixed keys to migrate")
+		return nil
+	}
+
+	if err := commitInBatches(ctx, s.Client.KV, m.ops, m.sizes); err != nil {
+		return err
+	}
+
+	log.WithField("namespace", s.namespace).
+		WithField("keys", len(m.moved)).
+		WithField("conflicts", len(m.conflicts)).
+		Info("migrated unprefixed keys into the namespace of the cluster")
+	return nil
+}
+
+// MigrateKeysAtStartup returns true if the unprefixed keys are to be migrated
+// at startup, as set with the --migrate-unprefixed-keys option
+func MigrateKeysAtStartup() bool {
+	return config.GetBool(migrateKeysOpt)
+}
@@ -0,0 +1,38 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMigration(t *testing.T) {
+	const namespace = "gluster-6d3e1c0b-2f4a-4b5c-8d9e-0f1a2b3c4d5e/"
+
+	root := []*mvccpb.KeyValue{
+		{Key: []byte("volumes/gv0"), Value: []byte("gv0")},
+		{Key: []byte("volumes/gv1"), Value: []byte("gv1")},
+		{Key: []byte("peers/p1"), Value: []byte("p1")},
+		{Key: []byte("alive/p1"), Value: []byte("p1"), Lease: 1},
+		{Key: []byte("elastic/volunteers/p1"), Value: []byte("p1")},
+		{Key: []byte(namespace + "volumes/gv1"), Value: []byte("gv1")},
+		{Key: []byte(namespace + "peers/p1"), Value: []byte("p2")},
+		{Key: []byte("gluster-0f1a2b3c-4d5e-6d3e-1c0b-2f4a4b5c8d9e/volumes/gv2"), Value: []byte("gv2")},
+	}
+	namespaced := []*mvccpb.KeyValue{
+		{Key: []byte(namespace + "volumes/gv1"), Value: []byte("gv1")},
+		{Key: []byte(namespace + "peers/p1"), Value: []byte("p2")},
+	}
+
+	m := newMigration(namespace, root, namespaced)
+	assert.Equal(t, []string{"volumes/gv0", "volumes/gv1"}, m.moved)
+	assert.Equal(t, []string{"peers/p1"}, m.conflicts)
+	// gv0 is put and deleted, gv1 is already migrated and only deleted
+	assert.Len(t, m.ops, 3)
+	assert.Len(t, m.sizes, 3)
+
+	m = newMigration(namespace, namespaced, namespaced)
+	assert.Empty(t, m.moved)
+	assert.Empty(t, m.ops)
+}
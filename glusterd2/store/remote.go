@@ -3,40 +3,155 @@ package store
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
+	"fmt"
 	"io/ioutil"
+	"net/url"
+	"sync"
 	"time"
 
 	"github.com/coreos/etcd/clientv3"
 	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
 )
 
+// ErrStoreEmbedded is returned when reloading the configuration of the
+// embedded store
+var ErrStoreEmbedded = errors.New("store is the embedded etcd, its configuration can't be reloaded")
+
+// clientTLS keeps the client certificate and the CA used to connect to a
+// remote etcd cluster. They are picked up for every new connection, so that
+// they can be rotated while the client is in use.
+type clientTLS struct {
+	mu   sync.RWMutex
+	cert *tls.Certificate
+	ca   *x509.CertPool
+	// The certificates of the etcd servers must be valid for one of the
+	// configured endpoints, or of the endpoints the client has synced
+	// from the members of the cluster
+	endpoints []string
+	client    *clientv3.Client
+}
+
+// load loads the client certificate and the CA from the files of the store
+// config. They are only replaced if all of them load.
+func (t *clientTLS) load(conf *Config) error {
+	certFile, keyFile, caFile := conf.ClntCertFile, conf.ClntKeyFile, conf.ClntCAFile
+
+	cert := &tls.Certificate{}
+	if certFile != "" && keyFile != "" {
+		tlsCert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			log.WithError(err).Error("failed to load client certificate file")
+			return err
+		}
+		cert = &tlsCert
+	}
+
+	// Without a CA file, the certificates of the servers are verified
+	// with the CAs of the system
+	var ca *x509.CertPool
+	if caFile != "" {
+		caCert, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			log.WithError(err).Error("failed to load client CA file")
+			return err
+		}
+		ca = x509.NewCertPool()
+		if !ca.AppendCertsFromPEM(caCert) {
+			return fmt.Errorf("no certificates found in client CA file %s", caFile)
+		}
+	}
+
+	t.mu.Lock()
+	t.cert, t.ca, t.endpoints = cert, ca, conf.Endpoints
+	t.mu.Unlock()
+	return nil
+}
+
+func (t *clientTLS) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.cert, nil
+}
+
+// verify checks that the certificate chain presented by an etcd server is
+// signed by the CA in use, and that the certificate is valid for one of the
+// endpoints of the client
+func (t *clientTLS) verify(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	t.mu.RLock()
+	ca := t.ca
+	endpoints := t.endpoints
+	if t.client != nil {
+		endpoints = append(t.client.Endpoints(), endpoints...)
+	}
+	t.mu.RUnlock()
+
+	if len(rawCerts) == 0 {
+		return errors.New("no certificate presented")
+	}
+
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certs[i] = cert
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         ca,
+		Intermediates: intermediates,
+	}); err != nil {
+		return err
+	}
+
+	// The host the connection is made to isn't known here
+	var err error
+	for _, endpoint := range endpoints {
+		u, perr := url.Parse(endpoint)
+		if perr != nil {
+			continue
+		}
+		if err = certs[0].VerifyHostname(u.Hostname()); err == nil {
+			return nil
+		}
+	}
+	if err == nil {
+		err = errors.New("no endpoint to verify the server certificate for")
+	}
+	return err
+}
+
+// config returns the TLS config of the client. The server certificates are
+// verified by VerifyPeerCertificate, as RootCAs can't be changed once the
+// config is in use.
+func (t *clientTLS) config() *tls.Config {
+	return &tls.Config{
+		MinVersion:            tls.VersionTLS12,
+		GetClientCertificate:  t.getClientCertificate,
+		InsecureSkipVerify:    true,
+		VerifyPeerCertificate: t.verify,
+	}
+}
+
 func newRemoteStore(conf *Config) (*GDStore, error) {
+	var ctls *clientTLS
 	var tlsConfig *tls.Config
 	var c *clientv3.Client
 	var e error
 	if conf.UseTLS {
-		tlsConfig = &tls.Config{
-			MinVersion: tls.VersionTLS12,
-		}
-		if conf.ClntCertFile != "" && conf.ClntKeyFile != "" {
-			tlsCert, err := tls.LoadX509KeyPair(conf.ClntCertFile, conf.ClntKeyFile)
-			if err != nil {
-				log.WithError(err).Error("failed to load client certificate file")
-				return nil, err
-			}
-			tlsConfig.Certificates = []tls.Certificate{tlsCert}
-			tlsConfig.ClientAuth = tls.RequestClientCert
-		}
-		if conf.ClntCAFile != "" {
-			caCert, err := ioutil.ReadFile(conf.ClntCAFile)
-			if err != nil {
-				log.WithError(err).Error("failed to load client CA file")
-				return nil, err
-			}
-			caCertPool := x509.NewCertPool()
-			caCertPool.AppendCertsFromPEM(caCert)
-			tlsConfig.RootCAs = caCertPool
+		ctls = &clientTLS{}
+		if err := ctls.load(conf); err != nil {
+			return nil, err
 		}
+		tlsConfig = ctls.config()
 	}
 	c, e = clientv3.New(clientv3.Config{
 		Endpoints:        conf.Endpoints,
@@ -50,8 +165,66 @@ func newRemoteStore(conf *Config) (*GDStore, error) {
 		return nil, e
 	}
 	log.Debug("etcd client connection created")
+	if ctls != nil {
+		ctls.mu.Lock()
+		ctls.client = c
+		ctls.mu.Unlock()
+	}
+
+	s, err := newNamespacedStore(c, conf)
+	if err != nil {
+		return nil, err
+	}
+	s.clientTLS = ctls
+	return s, nil
+}
+
+// Reload reads the glusterd2 config file again and applies the changes of
+// the etcd endpoints and of the etcd client certificate, key and CA files to
+// the remote store, without restarting glusterd2. Established connections
+// are kept, new connections use the reloaded certificates.
+func (s *GDStore) Reload() error {
+	if s.ee != nil {
+		return ErrStoreEmbedded
+	}
+
+	err := config.ReadInConfig()
+	if _, ok := err.(config.ConfigFileNotFoundError); err != nil && !ok {
+		return err
+	}
+
+	conf := GetConfig()
+	if err := checkReloadConfig(&s.conf, conf); err != nil {
+		return err
+	}
+	if s.clientTLS != nil {
+		if err := s.clientTLS.load(conf); err != nil {
+			return err
+		}
+	}
+	s.Client.SetEndpoints(conf.Endpoints...)
+	s.conf = *conf
+
+	log.WithFields(log.Fields{
+		"endpoints": conf.Endpoints,
+		"certfile":  conf.ClntCertFile,
+		"cafile":    conf.ClntCAFile,
+	}).Info("reloaded store configuration")
+	return nil
+}
+
+// checkReloadConfig checks that the store configuration can be changed from
+// old to conf while glusterd2 is running
+func checkReloadConfig(old, conf *Config) error {
+	if conf.NoEmbed != old.NoEmbed || conf.UseTLS != old.UseTLS {
+		return fmt.Errorf("%s and %s can't be changed while glusterd2 is running", noEmbedOpt, useTLSOpt)
+	}
+	return nil
+}
 
-	return newNamespacedStore(c, conf)
+// Config returns the configuration of the store in use
+func (s *GDStore) Config() Config {
+	return s.conf
 }
 
 func (s *GDStore) closeRemoteStore() {
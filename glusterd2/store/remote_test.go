@@ -0,0 +1,167 @@
+package store
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCert is a certificate and its key, signed by the CA it was created
+// with or self signed for a CA
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newTestCert(t *testing.T, name string, ca *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	parent, signer := tmpl, key
+	if ca == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		tmpl.DNSNames = []string{name}
+		parent, signer = ca.cert, ca.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, signer)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+// write writes the certificate and its key as PEM files in dir, and returns
+// their paths
+func (c *testCert) write(t *testing.T, dir, name string) (string, string) {
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile
+}
+
+func TestClientTLSLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ca := newTestCert(t, "ca", nil)
+	caFile, _ := ca.write(t, dir, "ca")
+	client1 := newTestCert(t, "client1", ca)
+	certFile1, keyFile1 := client1.write(t, dir, "client1")
+	client2 := newTestCert(t, "client2", ca)
+	certFile2, keyFile2 := client2.write(t, dir, "client2")
+
+	ctls := &clientTLS{}
+	conf := &Config{
+		Endpoints:    []string{"https://etcd1:2379"},
+		ClntCertFile: certFile1,
+		ClntKeyFile:  keyFile1,
+		ClntCAFile:   caFile,
+	}
+	require.NoError(t, ctls.load(conf))
+	cert, err := ctls.getClientCertificate(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, client1.der, cert.Certificate[0])
+	assert.Equal(t, conf.Endpoints, ctls.endpoints)
+
+	// The certificate in use is replaced when the files are reloaded
+	conf.ClntCertFile, conf.ClntKeyFile = certFile2, keyFile2
+	require.NoError(t, ctls.load(conf))
+	cert, _ = ctls.getClientCertificate(nil)
+	assert.Equal(t, client2.der, cert.Certificate[0])
+
+	// and kept if any of the files fails to load
+	noCerts := filepath.Join(dir, "empty.crt")
+	require.NoError(t, ioutil.WriteFile(noCerts, []byte("no certificates"), 0600))
+	for _, bad := range []*Config{
+		{ClntCertFile: certFile1, ClntKeyFile: keyFile2, ClntCAFile: caFile},
+		{ClntCertFile: filepath.Join(dir, "missing.crt"), ClntKeyFile: keyFile1, ClntCAFile: caFile},
+		{ClntCertFile: certFile1, ClntKeyFile: keyFile1, ClntCAFile: filepath.Join(dir, "missing.crt")},
+		{ClntCertFile: certFile1, ClntKeyFile: keyFile1, ClntCAFile: noCerts},
+	} {
+		assert.Error(t, ctls.load(bad))
+		cert, _ = ctls.getClientCertificate(nil)
+		assert.Equal(t, client2.der, cert.Certificate[0])
+		assert.Equal(t, conf.Endpoints, ctls.endpoints)
+	}
+
+	// Without a client certificate, an empty one is sent
+	require.NoError(t, ctls.load(&Config{ClntCAFile: caFile}))
+	cert, _ = ctls.getClientCertificate(nil)
+	assert.Equal(t, &tls.Certificate{}, cert)
+}
+
+func TestClientTLSVerify(t *testing.T) {
+	ca := newTestCert(t, "ca", nil)
+	otherCA := newTestCert(t, "other-ca", nil)
+	server := newTestCert(t, "etcd1", ca)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	ctls := &clientTLS{ca: roots, endpoints: []string{"https://etcd2:2379", "https://etcd1:2379"}}
+
+	assert.NoError(t, ctls.verify([][]byte{server.der}, nil))
+	assert.Error(t, ctls.verify(nil, nil))
+	assert.Error(t, ctls.verify([][]byte{[]byte("not a certificate")}, nil))
+	assert.Error(t, ctls.verify([][]byte{newTestCert(t, "etcd1", otherCA).der}, nil))
+
+	// The certificate must be valid for one of the endpoints
+	ctls.endpoints = []string{"https://etcd2:2379"}
+	assert.Error(t, ctls.verify([][]byte{server.der}, nil))
+	ctls.endpoints = nil
+	assert.Error(t, ctls.verify([][]byte{server.der}, nil))
+
+	// Rotating the CA takes effect for the next connection
+	otherRoots := x509.NewCertPool()
+	otherRoots.AddCert(otherCA.cert)
+	ctls.ca, ctls.endpoints = otherRoots, []string{"https://etcd1:2379"}
+	assert.Error(t, ctls.verify([][]byte{server.der}, nil))
+	assert.NoError(t, ctls.verify([][]byte{newTestCert(t, "etcd1", otherCA).der}, nil))
+}
+
+func TestCheckReloadConfig(t *testing.T) {
+	old := &Config{NoEmbed: true, UseTLS: true, Endpoints: []string{"https://etcd1:2379"}}
+
+	tests := []struct {
+		conf  Config
+		valid bool
+	}{
+		{Config{NoEmbed: true, UseTLS: true, Endpoints: []string{"https://etcd2:2379"}, ClntCertFile: "client.crt"}, true},
+		{Config{NoEmbed: false, UseTLS: true, Endpoints: []string{"https://etcd1:2379"}}, false},
+		{Config{NoEmbed: true, UseTLS: false, Endpoints: []string{"http://etcd1:2379"}}, false},
+	}
+
+	for _, tt := range tests {
+		err := checkReloadConfig(old, &tt.conf)
+		assert.Equal(t, tt.valid, err == nil, "%+v", tt.conf)
+	}
+}
@@ -52,6 +52,7 @@ type GDStore struct {
 	*clientv3.Client
 
 	ee              *elasticetcd.ElasticEtcd
+	clientTLS       *clientTLS
 	namespace       string
 	stop            chan struct{}
 	stopOnce        sync.Once
@@ -314,3 +315,20 @@ func Txn(ctx context.Context) clientv3.Txn {
 	defer storeCounters.Add("txn", 1)
 	return Store.Txn(ctx)
 }
+
+// PrefixVersion returns a version of the keys with the given prefix, which
+// changes whenever one of them is added, changed or deleted. It is made of
+// the number of keys and the latest revision in which one of them changed.
+func PrefixVersion(ctx context.Context, prefix string) (string, error) {
+	resp, err := Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly(),
+		clientv3.WithSort(clientv3.SortByModRevision, clientv3.SortDescend), clientv3.WithLimit(1))
+	if err != nil {
+		return "", err
+	}
+
+	var rev int64
+	if len(resp.Kvs) > 0 {
+		rev = resp.Kvs[0].ModRevision
+	}
+	return fmt.Sprintf("%d.%d", resp.Count, rev), nil
+}
@@ -0,0 +1,182 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+// WatchEventType is the type of a change of a watched key
+type WatchEventType int
+
+const (
+	// WatchPut is a key created or updated
+	WatchPut WatchEventType = iota
+	// WatchDelete is a key deleted
+	WatchDelete
+	// WatchReset tells that changes may have been missed, because the
+	// store compacted the revisions the watch had to resume from. The
+	// watcher should read the keys again, for example by dropping its
+	// cache. Key and Value are not set.
+	WatchReset
+)
+
+// String returns the name of the event type
+func (t WatchEventType) String() string {
+	switch t {
+	case WatchPut:
+		return "put"
+	case WatchDelete:
+		return "delete"
+	case WatchReset:
+		return "reset"
+	}
+	return "unknown"
+}
+
+// WatchEvent is a change of a key under a watched prefix. The key has the
+// watched prefix, in the namespace of the cluster. Value is the new value
+// of the key, and is not set for deletes.
+type WatchEvent struct {
+	Type     WatchEventType
+	Key      string
+	Value    []byte
+	Revision int64
+}
+
+// WatchFunc is called for every change of the keys under a watched prefix
+type WatchFunc func(ev *WatchEvent)
+
+const watchRetryInterval = time.Second
+
+// PrefixWatcher follows the changes of the keys under a prefix, made on
+// any peer
+type PrefixWatcher struct {
+	s      *GDStore
+	prefix string
+	fn     WatchFunc
+
+	// rev is the last revision seen, the watch resumes after it when it
+	// has to be restarted
+	rev int64
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// WatchPrefix calls fn for every change made from now on to the keys with
+// the given prefix, until the watcher is stopped or the store is closed.
+// The changes are passed to fn one at a time, in the order they were made.
+// fn must not block for long, as the next changes wait for it.
+//
+// The watch survives the reconnections to the store and resumes where it
+// stopped; if the changes it missed are no longer available, fn is called
+// with a WatchReset event.
+func (s *GDStore) WatchPrefix(prefix string, fn WatchFunc) (*PrefixWatcher, error) {
+	// The watch starts after the current revision, so that no change made
+	// after WatchPrefix returns is missed
+	resp, err := s.Get(s.Ctx(), prefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+	if err != nil {
+		return nil, err
+	}
+
+	w := &PrefixWatcher{
+		s:      s,
+		prefix: prefix,
+		fn:     fn,
+		rev:    resp.Header.Revision,
+		stop:   make(chan struct{}),
+	}
+	w.wg.Add(1)
+	go w.run()
+	return w, nil
+}
+
+// WatchPrefix calls fn for every change made to the keys with the given
+// prefix in the default store, see GDStore.WatchPrefix
+func WatchPrefix(prefix string, fn WatchFunc) (*PrefixWatcher, error) {
+	return Store.WatchPrefix(prefix, fn)
+}
+
+// Stop stops the watcher. fn is not called once Stop returns, and Stop must
+// not be called from fn.
+func (w *PrefixWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+	w.wg.Wait()
+}
+
+func (w *PrefixWatcher) run() {
+	defer w.wg.Done()
+	for !w.watch() {
+		select {
+		case <-time.After(watchRetryInterval):
+		case <-w.stop:
+			return
+		case <-w.s.stop:
+			return
+		}
+	}
+}
+
+// watch follows the changes after the last revision seen until the watch
+// fails or the watcher is stopped. It returns true if the watcher was
+// stopped.
+func (w *PrefixWatcher) watch() bool {
+	// The watch fails instead of waiting when the member it is connected
+	// to loses the leader, so that it is restarted on another member
+	ctx, cancel := context.WithCancel(clientv3.WithRequireLeader(w.s.Ctx()))
+	defer cancel()
+
+	wch := w.s.Watch(ctx, w.prefix, clientv3.WithPrefix(), clientv3.WithRev(w.rev+1))
+	for {
+		select {
+		case wresp, ok := <-wch:
+			if !ok {
+				return false
+			}
+			if wresp.CompactRevision != 0 {
+				log.WithField("prefix", w.prefix).WithField("revision", w.rev).Warn("watched changes were compacted, resetting watch")
+				w.rev = wresp.CompactRevision - 1
+				w.fn(&WatchEvent{Type: WatchReset, Revision: wresp.CompactRevision})
+				return false
+			}
+			if err := wresp.Err(); err != nil {
+				log.WithError(err).WithField("prefix", w.prefix).Debug("watch failed, restarting it")
+				return false
+			}
+			for _, ev := range wresp.Events {
+				wev := &WatchEvent{
+					Key:      string(ev.Kv.Key),
+					Revision: ev.Kv.ModRevision,
+				}
+				if ev.Type == clientv3.EventTypeDelete {
+					wev.Type = WatchDelete
+				} else {
+					wev.Type = WatchPut
+					wev.Value = ev.Kv.Value
+				}
+				select {
+				case <-w.stop:
+					return true
+				default:
+				}
+				w.fn(wev)
+			}
+			// The events of a revision all come in the same response,
+			// the watch can only be resumed after a whole response
+			if n := len(wresp.Events); n > 0 {
+				w.rev = wresp.Events[n-1].Kv.ModRevision
+			}
+		case <-w.stop:
+			return true
+		case <-w.s.stop:
+			return true
+		}
+	}
+}
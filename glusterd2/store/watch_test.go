@@ -0,0 +1,180 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/pkg/elasticetcd"
+
+	"github.com/pborman/uuid"
+	config "github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// freeURL returns a local URL on a port nobody listens on
+func freeURL(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return fmt.Sprintf("http://%s", l.Addr().String())
+}
+
+// newTestStore starts a store with its own embedded etcd server. The
+// returned func stops the store and deletes its data.
+func newTestStore(t *testing.T) (*GDStore, func()) {
+	dir, err := ioutil.TempDir("", "gd2-store")
+	require.NoError(t, err)
+
+	gdctx.MyUUID = uuid.NewRandom()
+	gdctx.MyClusterID = uuid.NewRandom()
+	config.Set("logdir", dir)
+
+	s, err := New(&Config{
+		Endpoints: []string{elasticetcd.DefaultEndpoint},
+		CURLs:     []string{freeURL(t)},
+		PURLs:     []string{freeURL(t)},
+		Dir:       path.Join(dir, "store"),
+	})
+	if err != nil {
+		os.RemoveAll(dir)
+	}
+	require.NoError(t, err)
+
+	return s, func() {
+		s.Destroy(true)
+		os.RemoveAll(dir)
+	}
+}
+
+// resumeWatch starts a watcher which resumes after the given revision, as
+// a watcher restarted by run does
+func resumeWatch(s *GDStore, prefix string, rev int64, fn WatchFunc) *PrefixWatcher {
+	w := &PrefixWatcher{
+		s:      s,
+		prefix: prefix,
+		fn:     fn,
+		rev:    rev,
+		stop:   make(chan struct{}),
+	}
+	w.wg.Add(1)
+	go w.run()
+	return w
+}
+
+func nextEvent(t *testing.T, events <-chan *WatchEvent) *WatchEvent {
+	select {
+	case ev := <-events:
+		return ev
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "timed out waiting for a watch event")
+	}
+	return nil
+}
+
+func TestWatchPrefix(t *testing.T) {
+	s, cleanup := newTestStore(t)
+	defer cleanup()
+
+	events := make(chan *WatchEvent, 10)
+	w, err := s.WatchPrefix("watch/", func(ev *WatchEvent) { events <- ev })
+	require.NoError(t, err)
+	defer w.Stop()
+
+	_, err = s.Put(context.Background(), "watch/a", "1")
+	require.NoError(t, err)
+	_, err = s.Put(context.Background(), "other/a", "1")
+	require.NoError(t, err)
+	_, err = s.Delete(context.Background(), "watch/a")
+	require.NoError(t, err)
+
+	ev := nextEvent(t, events)
+	assert.Equal(t, WatchPut, ev.Type)
+	assert.Equal(t, "watch/a", ev.Key)
+	assert.Equal(t, []byte("1"), ev.Value)
+
+	ev = nextEvent(t, events)
+	assert.Equal(t, WatchDelete, ev.Type)
+	assert.Equal(t, "watch/a", ev.Key)
+	assert.Nil(t, ev.Value)
+}
+
+// TestWatchPrefixResume validates that a restarted watch sends the changes
+// made after the last revision seen, and only those
+func TestWatchPrefixResume(t *testing.T) {
+	s, cleanup := newTestStore(t)
+	defer cleanup()
+
+	events := make(chan *WatchEvent, 10)
+	w, err := s.WatchPrefix("watch/", func(ev *WatchEvent) { events <- ev })
+	require.NoError(t, err)
+
+	_, err = s.Put(context.Background(), "watch/a", "1")
+	require.NoError(t, err)
+	ev := nextEvent(t, events)
+	w.Stop()
+	assert.Equal(t, ev.Revision, w.rev)
+
+	// Changes made while the watch is down are not lost
+	_, err = s.Put(context.Background(), "watch/b", "2")
+	require.NoError(t, err)
+	_, err = s.Put(context.Background(), "watch/c", "3")
+	require.NoError(t, err)
+
+	w = resumeWatch(s, "watch/", w.rev, func(ev *WatchEvent) { events <- ev })
+	defer w.Stop()
+
+	ev = nextEvent(t, events)
+	assert.Equal(t, WatchPut, ev.Type)
+	assert.Equal(t, "watch/b", ev.Key)
+	ev = nextEvent(t, events)
+	assert.Equal(t, WatchPut, ev.Type)
+	assert.Equal(t, "watch/c", ev.Key)
+
+	select {
+	case ev := <-events:
+		assert.Fail(t, "unexpected watch event", "%+v", ev)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestWatchPrefixCompacted validates that a watch resuming from a compacted
+// revision sends a WatchReset event, then follows the changes from the
+// compacted revision
+func TestWatchPrefixCompacted(t *testing.T) {
+	s, cleanup := newTestStore(t)
+	defer cleanup()
+
+	resp, err := s.Put(context.Background(), "watch/a", "1")
+	require.NoError(t, err)
+	rev := resp.Header.Revision
+
+	_, err = s.Put(context.Background(), "watch/b", "2")
+	require.NoError(t, err)
+	resp, err = s.Put(context.Background(), "watch/c", "3")
+	require.NoError(t, err)
+	compactRev := resp.Header.Revision
+	_, err = s.Compact(context.Background(), compactRev)
+	require.NoError(t, err)
+
+	events := make(chan *WatchEvent, 10)
+	w := resumeWatch(s, "watch/", rev, func(ev *WatchEvent) { events <- ev })
+	defer w.Stop()
+
+	ev := nextEvent(t, events)
+	assert.Equal(t, WatchReset, ev.Type)
+	assert.Equal(t, compactRev, ev.Revision)
+	assert.Empty(t, ev.Key)
+
+	ev = nextEvent(t, events)
+	assert.Equal(t, WatchPut, ev.Type)
+	assert.Equal(t, "watch/c", ev.Key)
+	assert.Equal(t, compactRev, ev.Revision)
+}
@@ -0,0 +1,63 @@
+package transaction
+
+import (
+	"context"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+)
+
+const txnCancelPrefix = "transaction-cancel/"
+
+// CancelTxn requests the cancellation of a running transaction. The request
+// is written to the store, from where it is picked up by the peer which
+// initiated the transaction.
+func CancelTxn(id uuid.UUID) error {
+	info, err := GetTxnRecord(id)
+	if err != nil {
+		return err
+	}
+
+	if info.State != TxnStatePending && info.State != TxnStateRunning {
+		return gderrors.ErrTxnNotRunning
+	}
+
+	// The request goes away with the record if the initiator never picks
+	// it up
+	l, err := store.Store.Grant(store.Store.Ctx(), txnRecordTTL())
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(context.TODO(), txnCancelPrefix+id.String(), "", clientv3.WithLease(l.ID))
+	return err
+}
+
+// watchCancel cancels the transaction context when a cancellation of the
+// transaction is requested. The watch ends when ctx is done.
+func (t *Txn) watchCancel(ctx context.Context, cancel context.CancelFunc) {
+	key := txnCancelPrefix + t.id.String()
+	wch := store.Store.Watch(ctx, key, clientv3.WithFilterDelete())
+
+	go func() {
+		for resp := range wch {
+			if resp.Canceled {
+				return
+			}
+			if len(resp.Events) == 0 {
+				continue
+			}
+
+			t.Ctx.Logger().Info("transaction cancellation requested")
+			cancel()
+
+			if _, err := store.Delete(context.TODO(), key); err != nil {
+				t.Ctx.Logger().WithError(err).WithField("key", key).Error("failed to remove transaction cancellation request from store")
+			}
+			return
+		}
+	}()
+}
@@ -28,10 +28,17 @@ type TxnCtx interface {
 	GetNodeResult(peerID uuid.UUID, key string, value interface{}) error
 	// GetTxnReqID gets the reqID string saved in the transaction.
 	GetTxnReqID() string
+	// GetTxnID gets the ID string of the transaction.
+	GetTxnID() string
 	// Delete deletes the key and value
 	Delete(key string) error
 	// Logger returns the Logrus logger associated with the context
 	Logger() log.FieldLogger
+	// Context returns the context the step function is being run with. It
+	// is cancelled when the transaction gets cancelled.
+	Context() context.Context
+	// setContext sets the context returned by Context()
+	setContext(ctx context.Context)
 
 	// Commit writes all locally cached keys and values into the store using
 	// a single etcd transaction. This is for internal use by the txn framework
@@ -49,6 +56,7 @@ type Tctx struct {
 	readSet        map[string][]byte // cached responses from store
 	readCacheDirty bool
 	writeSet       map[string]string // to be written to store
+	ctx            context.Context   // context of the running step func
 }
 
 // TxnCtxConfig is marshalled and sent on wire and is used to reconstruct Tctx
@@ -194,6 +202,11 @@ func (c *Tctx) GetTxnReqID() string {
 	return c.config.LogFields["reqid"].(string)
 }
 
+// GetTxnID gets the txnID string saved within the txnCtxConfig.
+func (c *Tctx) GetTxnID() string {
+	return c.config.LogFields["txnid"].(string)
+}
+
 // Delete deletes the key and attached value
 func (c *Tctx) Delete(key string) error {
 
@@ -217,6 +230,18 @@ func (c *Tctx) Logger() log.FieldLogger {
 	return c.logger
 }
 
+// Context returns the context the step function is being run with
+func (c *Tctx) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+func (c *Tctx) setContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
 // MarshalJSON implements the json.Marshaler interface
 func (c *Tctx) MarshalJSON() ([]byte, error) {
 	return json.Marshal(c.config)
@@ -1,7 +1,9 @@
 package transaction
 
 import (
+	"context"
 	"errors"
+
 	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
 )
@@ -9,6 +11,7 @@ import (
 // MockTctx implements a dummy context type that can be used in tests
 type MockTctx struct {
 	data map[string]interface{}
+	ctx  context.Context
 }
 
 // NewMockCtx returns a new instance of MockTctx
@@ -56,6 +59,38 @@ func (m *MockTctx) Logger() log.FieldLogger {
 	return log.New()
 }
 
+// GetTxnReqID returns an empty request ID
+func (m *MockTctx) GetTxnReqID() string {
+	return ""
+}
+
+// GetTxnID returns an empty transaction ID
+func (m *MockTctx) GetTxnID() string {
+	return ""
+}
+
+// Context returns the context set by the transaction, or an empty context
+func (m *MockTctx) Context() context.Context {
+	if m.ctx == nil {
+		return context.Background()
+	}
+	return m.ctx
+}
+
+func (m *MockTctx) setContext(ctx context.Context) {
+	m.ctx = ctx
+}
+
+// Commit does nothing as values are not saved in the store
+func (m *MockTctx) Commit() error {
+	return nil
+}
+
+// SyncCache does nothing as values are not saved in the store
+func (m *MockTctx) SyncCache() error {
+	return nil
+}
+
 // Prefix returns the prefix to be used for storing values
 func (m MockTctx) Prefix() string {
 	return "mock"
@@ -0,0 +1,218 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	txnJournalPrefix = "transaction-journal/"
+	txnOwnerPrefix   = "transaction-owners/"
+	// orphanedTxnMinAge is the time since its journal was last updated
+	// after which a transaction can be taken as orphaned by its initiator
+	orphanedTxnMinAge = 5 * time.Minute
+)
+
+// ErrTxnOrphaned is the error recorded for transactions which were rolled
+// back as their initiator went down while running them
+var ErrTxnOrphaned = errors.New("transaction initiator went down, transaction rolled back")
+
+// txnJournal is the state of a running transaction which is needed to roll
+// it back from any peer. The journal outlives its initiator, while the owner
+// key of the transaction is tied to the store session of the initiator. A
+// journal belongs to a transaction which was orphaned by its initiator going
+// down if it has no owner key, the initiator isn't alive or was restarted,
+// and the journal wasn't updated for orphanedTxnMinAge.
+type txnJournal struct {
+	ID              uuid.UUID `json:"id"`
+	ReqID           uuid.UUID `json:"req-id"`
+	Initiator       uuid.UUID `json:"initiator"`
+	InitiatorPID    int       `json:"initiator-pid"`
+	StorePrefix     string    `json:"store-prefix"`
+	Locks           []string  `json:"locks,omitempty"`
+	Steps           []*Step   `json:"steps"`
+	DisableRollback bool      `json:"disable-rollback"`
+	// LastStep is the index of the last step with an UndoFunc which was
+	// started. Steps till LastStep are undone to roll back the transaction.
+	LastStep  int       `json:"last-step"`
+	UpdatedAt time.Time `json:"updated-at"`
+}
+
+// isNodeAliveF reports the pid of a peer and whether it is alive. It can be
+// replaced in tests.
+var isNodeAliveF = func(peerID uuid.UUID) (int, bool) {
+	return store.Store.IsNodeAlive(peerID)
+}
+
+// startJournal writes the journal of the transaction and claims the
+// transaction for this peer
+func (t *Txn) startJournal() error {
+	var locks []string
+	for id := range t.locks {
+		locks = append(locks, id)
+	}
+
+	t.journal = &txnJournal{
+		ID:              t.id,
+		ReqID:           t.reqID,
+		Initiator:       gdctx.MyUUID,
+		InitiatorPID:    os.Getpid(),
+		StorePrefix:     t.storePrefix,
+		Locks:           locks,
+		Steps:           t.Steps,
+		DisableRollback: t.DisableRollback,
+		LastStep:        -1,
+		UpdatedAt:       time.Now(),
+	}
+
+	v, err := json.Marshal(t.journal)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Txn(context.TODO()).Then(
+		clientv3.OpPut(txnJournalPrefix+t.id.String(), string(v)),
+		clientv3.OpPut(txnOwnerPrefix+t.id.String(), gdctx.MyUUID.String(), clientv3.WithLease(store.Store.Session.Lease())),
+	).Commit()
+	return err
+}
+
+// journalStep records that step i of the transaction was started. Only
+// steps which can be undone change what a rollback does, so the journal is
+// not updated for the others. Failing to record a step only leaves it out
+// of the rollback done on behalf of an initiator which went down, so errors
+// are only logged.
+func (t *Txn) journalStep(i int) {
+	if t.journal == nil || t.Steps[i].UndoFunc == "" {
+		return
+	}
+
+	t.journal.LastStep = i
+	t.journal.UpdatedAt = time.Now()
+	t.journal.save(t.Ctx.Logger().WithField("step", i))
+}
+
+// removeJournal removes the journal of a transaction which has ended
+func removeJournal(id uuid.UUID) error {
+	_, err := store.Txn(context.TODO()).Then(
+		clientv3.OpDelete(txnJournalPrefix+id.String()),
+		clientv3.OpDelete(txnOwnerPrefix+id.String()),
+	).Commit()
+	return err
+}
+
+// RecoverOrphanedTxns rolls back transactions whose initiator went down
+// while running them, so that they don't leave behind partially done
+// changes. A missing owner key alone doesn't make a transaction orphaned, as
+// the store session of a live initiator can expire when it is briefly cut
+// off from the store. A transaction is rolled back with the locks it held, and is left
+// for a later attempt if the locks can't be obtained or if any of its steps
+// fails to be undone with a transient error, like a peer being unreachable.
+func RecoverOrphanedTxns() {
+	resp, err := store.Get(context.TODO(), txnJournalPrefix, clientv3.WithPrefix())
+	if err != nil {
+		log.WithError(err).Error("failed to get transaction journals")
+		return
+	}
+
+	for _, kv := range resp.Kvs {
+		var j txnJournal
+		if err := json.Unmarshal(kv.Value, &j); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal transaction journal")
+			continue
+		}
+
+		owner, err := store.Get(context.TODO(), txnOwnerPrefix+j.ID.String())
+		if err != nil || owner.Count != 0 {
+			continue
+		}
+
+		if !j.isOrphaned(time.Now()) {
+			continue
+		}
+
+		j.recover()
+	}
+}
+
+// isOrphaned reports whether the initiator of a transaction without an owner
+// key is confirmed to have gone down, ie. it isn't alive or was restarted,
+// and the journal wasn't updated for orphanedTxnMinAge
+func (j *txnJournal) isOrphaned(now time.Time) bool {
+	if now.Sub(j.UpdatedAt) < orphanedTxnMinAge {
+		return false
+	}
+
+	pid, alive := isNodeAliveF(j.Initiator)
+	return !alive || pid != j.InitiatorPID
+}
+
+func (j *txnJournal) recover() {
+	config := &TxnCtxConfig{
+		LogFields: log.Fields{
+			"txnid": j.ID.String(),
+			"reqid": j.ReqID.String(),
+		},
+		StorePrefix: j.StorePrefix,
+	}
+	ctx := newCtx(config)
+	logger := ctx.Logger().WithField("initiator", j.Initiator.String())
+
+	locks := make(Locks)
+	if len(j.Locks) > 0 {
+		if err := locks.LockWithOwner(j.ID, j.Locks[0], j.Locks[1:]...); err != nil {
+			logger.WithError(err).Warn("failed to obtain locks of orphaned transaction, will retry")
+			locks.UnLock(context.Background())
+			return
+		}
+	}
+	defer locks.UnLock(context.Background())
+
+	if !j.DisableRollback {
+		logger.Warn("transaction orphaned by initiator, rolling back changes")
+		for i := j.LastStep; i >= 0; i-- {
+			s := j.Steps[i]
+			if s.Skip {
+				continue
+			}
+			if err := s.undo(ctx); err != nil {
+				if IsTransientError(err) || IsUnavailableError(err) {
+					logger.WithError(err).WithField("step", s.UndoFunc).Warn("failed to roll back step of orphaned transaction, will retry")
+					j.LastStep = i
+					j.save(logger)
+					return
+				}
+				logger.WithError(err).WithField("step", s.UndoFunc).Error("failed in rolling back step")
+			}
+		}
+	}
+
+	markTxnRecordFailed(j.ID, ErrTxnOrphaned, logger)
+
+	if _, err := store.Delete(context.TODO(), j.StorePrefix, clientv3.WithPrefix()); err != nil {
+		logger.WithError(err).WithField("key", j.StorePrefix).Error("Failed to remove transaction namespace from store")
+	}
+	if err := removeJournal(j.ID); err != nil {
+		logger.WithError(err).Error("failed to remove transaction journal")
+	}
+}
+
+func (j *txnJournal) save(logger log.FieldLogger) {
+	v, err := json.Marshal(j)
+	if err == nil {
+		_, err = store.Put(context.TODO(), txnJournalPrefix+j.ID.String(), string(v))
+	}
+	if err != nil {
+		logger.WithError(err).Error("failed to update transaction journal")
+	}
+}
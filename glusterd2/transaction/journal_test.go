@@ -0,0 +1,53 @@
+package transaction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/pkg/testutils"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournalIsOrphaned(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-orphanedTxnMinAge - time.Second)
+
+	tests := []struct {
+		name      string
+		updatedAt time.Time
+		pid       int
+		alive     bool
+		orphaned  bool
+	}{
+		{"initiator alive", old, 100, true, false},
+		{"initiator down", old, 0, false, true},
+		{"initiator restarted", old, 200, true, true},
+		{"initiator down but journal recently updated", now.Add(-time.Second), 0, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := &txnJournal{Initiator: uuid.NewRandom(), InitiatorPID: 100, UpdatedAt: tt.updatedAt}
+			defer testutils.Patch(&isNodeAliveF, func(peerID uuid.UUID) (int, bool) {
+				assert.Equal(t, j.Initiator, peerID)
+				return tt.pid, tt.alive
+			}).Restore()
+
+			assert.Equal(t, tt.orphaned, j.isOrphaned(now))
+		})
+	}
+}
+
+func TestJournalStepWithoutUndo(t *testing.T) {
+	txn := &Txn{
+		Ctx:   NewMockCtx(),
+		Steps: []*Step{{DoFunc: "test.Step"}},
+	}
+	txn.journal = &txnJournal{LastStep: -1}
+
+	// Steps which can't be undone don't need to be journaled
+	txn.journalStep(0)
+	assert.Equal(t, -1, txn.journal.LastStep)
+}
@@ -2,19 +2,27 @@ package transaction
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
 
+	"github.com/coreos/etcd/clientv3"
 	"github.com/coreos/etcd/clientv3/concurrency"
+	"github.com/coreos/etcd/mvcc/mvccpb"
 	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
 )
 
 const (
 	lockPrefix        = "locks/"
+	lockOwnerPrefix   = "lock-owners/"
 	lockObtainTimeout = 5 * time.Second
 	lockTTL           = 10
 )
@@ -153,7 +161,15 @@ func CreateLockFuncs(key string) (LockUnlockFunc, LockUnlockFunc) {
 // Locks are the collection of cluster wide transaction lock
 type Locks map[string]*concurrency.Mutex
 
-func (l Locks) lock(lockID string) error {
+// lockOwner is stored alongside a lock held by a transaction, so that the
+// holder of the lock can be told
+type lockOwner struct {
+	TxnID      uuid.UUID `json:"txn-id"`
+	PeerID     uuid.UUID `json:"peer-id"`
+	AcquiredAt time.Time `json:"acquired-at"`
+}
+
+func (l Locks) lock(txnID uuid.UUID, lockID string) error {
 	var logger = log.WithField("lockID", lockID)
 
 	// Ensure that no prior lock exists for the given lockID in this transaction
@@ -180,6 +196,9 @@ func (l Locks) lock(lockID string) error {
 		logger.Debug("lock obtained")
 		// Attach lock to the transaction
 		l[lockID] = locker
+		if txnID != nil {
+			saveLockOwner(s, lockID, txnID)
+		}
 
 	case context.DeadlineExceeded:
 		logger.Debug("timeout: failed to obtain lock")
@@ -196,11 +215,18 @@ func (l Locks) lock(lockID string) error {
 // Lock obtains a cluster wide transaction lock on the given lockID/lockIDs,
 // and attaches the obtained locks to the transaction
 func (l Locks) Lock(lockID string, lockIDs ...string) error {
-	if err := l.lock(lockID); err != nil {
+	return l.LockWithOwner(nil, lockID, lockIDs...)
+}
+
+// LockWithOwner obtains the locks like Lock, and records the transaction
+// with the given ID as their owner, so that it is shown when listing the
+// locks
+func (l Locks) LockWithOwner(txnID uuid.UUID, lockID string, lockIDs ...string) error {
+	if err := l.lock(txnID, lockID); err != nil {
 		return err
 	}
 	for _, id := range lockIDs {
-		if err := l.lock(id); err != nil {
+		if err := l.lock(txnID, id); err != nil {
 			return err
 		}
 	}
@@ -215,3 +241,116 @@ func (l Locks) UnLock(ctx context.Context) {
 		}
 	}
 }
+
+// saveLockOwner records the transaction holding the lock. The record is tied
+// to the session of the lock so that it goes away along with the lock. The
+// record is only informational, so errors are only logged.
+func saveLockOwner(s *concurrency.Session, lockID string, txnID uuid.UUID) {
+	v, err := json.Marshal(lockOwner{
+		TxnID:      txnID,
+		PeerID:     gdctx.MyUUID,
+		AcquiredAt: time.Now(),
+	})
+	if err != nil {
+		return
+	}
+
+	if _, err := store.Put(context.TODO(), lockOwnerPrefix+lockID, string(v), clientv3.WithLease(s.Lease())); err != nil {
+		log.WithError(err).WithField("lockID", lockID).Warn("failed to record lock owner")
+	}
+}
+
+// lockKeys returns the keys of the holder and the waiters of each lock. A lock
+// is held by the key with the lowest create revision under its prefix, which
+// is the first of its keys.
+func lockKeys() (map[string][]*mvccpb.KeyValue, error) {
+	resp, err := store.Get(context.TODO(), lockPrefix, clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByCreateRevision, clientv3.SortAscend))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string][]*mvccpb.KeyValue)
+	for _, kv := range resp.Kvs {
+		name := strings.TrimPrefix(string(kv.Key), lockPrefix)
+		if i := strings.LastIndex(name, "/"); i != -1 {
+			name = name[:i]
+		}
+		keys[name] = append(keys[name], kv)
+	}
+	return keys, nil
+}
+
+// GetLocks returns the cluster wide locks which are currently held
+func GetLocks() ([]api.LockInfo, error) {
+	allKeys, err := lockKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	locks := make([]api.LockInfo, 0, len(allKeys))
+	for name, keys := range allKeys {
+		info := api.LockInfo{
+			Name:    name,
+			Waiters: len(keys) - 1,
+		}
+
+		resp, err := store.Get(context.TODO(), lockOwnerPrefix+name)
+		if err != nil {
+			return nil, err
+		}
+
+		// Locks taken outside of transactions have no owner recorded.
+		// The owner record of a lock is only trusted if it is tied to
+		// the same lease as the key of the lock holder.
+		var owner lockOwner
+		if resp.Count == 1 && resp.Kvs[0].Lease == keys[0].Lease &&
+			json.Unmarshal(resp.Kvs[0].Value, &owner) == nil {
+			info.TxnID = owner.TxnID
+			info.PeerID = owner.PeerID
+			info.AcquiredAt = owner.AcquiredAt
+			info.Age = time.Since(owner.AcquiredAt).Round(time.Second).String()
+		}
+
+		locks = append(locks, info)
+	}
+
+	sort.Slice(locks, func(i, j int) bool {
+		return locks[i].Name < locks[j].Name
+	})
+
+	return locks, nil
+}
+
+// ForceUnlock releases the lock with the given name irrespective of which
+// transaction holds it. This is meant to clean up locks left behind by
+// transactions which never ended. The next waiter for the lock, if any,
+// obtains it.
+func ForceUnlock(name string) error {
+	allKeys, err := lockKeys()
+	if err != nil {
+		return err
+	}
+
+	keys, ok := allKeys[name]
+	if !ok {
+		return gderrors.ErrLockNotFound
+	}
+
+	holder := keys[0]
+	_, err = store.Txn(context.TODO()).
+		If(clientv3.Compare(clientv3.CreateRevision(string(holder.Key)), "=", holder.CreateRevision)).
+		Then(
+			clientv3.OpDelete(string(holder.Key)),
+			clientv3.OpDelete(lockOwnerPrefix+name),
+		).
+		Commit()
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"lockID": name,
+		"key":    string(holder.Key),
+	}).Warn("lock force released")
+	return nil
+}
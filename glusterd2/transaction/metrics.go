@@ -0,0 +1,73 @@
+package transaction
+
+import (
+	"encoding/json"
+	"expvar"
+	"sync"
+	"time"
+)
+
+// expStepFuncs holds the execution stats of each step func run on this peer
+var expStepFuncs = expvar.NewMap("txn_step_funcs")
+
+var stepFuncStatsMu sync.Mutex
+
+// stepFuncStats are the execution stats of a step func. It implements
+// expvar.Var so that the stats show up in the statedump.
+type stepFuncStats struct {
+	sync.Mutex
+	count         int64
+	failures      int64
+	totalDuration time.Duration
+	maxDuration   time.Duration
+}
+
+func (s *stepFuncStats) add(d time.Duration, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.count++
+	if err != nil {
+		s.failures++
+	}
+	s.totalDuration += d
+	if d > s.maxDuration {
+		s.maxDuration = d
+	}
+}
+
+// String returns the stats as JSON
+func (s *stepFuncStats) String() string {
+	s.Lock()
+	defer s.Unlock()
+
+	stats := map[string]interface{}{
+		"count":             s.count,
+		"failures":          s.failures,
+		"failure_rate":      0.0,
+		"total_duration_ms": s.totalDuration.Seconds() * 1000,
+		"avg_duration_ms":   0.0,
+		"max_duration_ms":   s.maxDuration.Seconds() * 1000,
+	}
+	if s.count != 0 {
+		stats["failure_rate"] = float64(s.failures) / float64(s.count)
+		stats["avg_duration_ms"] = s.totalDuration.Seconds() * 1000 / float64(s.count)
+	}
+
+	b, _ := json.Marshal(stats)
+	return string(b)
+}
+
+// recordStepFunc records an execution of the named step func which took d
+// and failed with err, if not nil
+func recordStepFunc(name string, d time.Duration, err error) {
+	stepFuncStatsMu.Lock()
+	stats, ok := expStepFuncs.Get(name).(*stepFuncStats)
+	if !ok {
+		stats = new(stepFuncStats)
+		expStepFuncs.Set(name, stats)
+	}
+	stepFuncStatsMu.Unlock()
+
+	stats.add(d, err)
+}
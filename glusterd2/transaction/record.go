@@ -0,0 +1,232 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+const (
+	txnRecordPrefix           = "transaction-records/"
+	defaultTxnRecordTTL int64 = 3600
+)
+
+// States of a transaction and its steps as shown in the transaction records
+const (
+	TxnStatePending   = "Pending"
+	TxnStateRunning   = "Running"
+	TxnStateSucceeded = "Succeeded"
+	TxnStateFailed    = "Failed"
+	TxnStateSkipped   = "Skipped"
+	TxnStateCancelled = "Cancelled"
+)
+
+// txnRecordTTL returns the number of seconds a transaction record is kept
+func txnRecordTTL() int64 {
+	ttl := config.GetInt64("txnrecordttl")
+	if ttl == 0 {
+		ttl = defaultTxnRecordTTL
+	}
+	return ttl
+}
+
+// txnRecord tracks the progress of a transaction in the store so that it can
+// be looked at from any peer while the transaction runs and for a while
+// after it ends
+type txnRecord struct {
+	info    api.TxnInfo
+	leaseID clientv3.LeaseID
+	logger  log.FieldLogger
+}
+
+func newTxnRecord(t *Txn) *txnRecord {
+	return &txnRecord{
+		info: api.TxnInfo{
+			ID:        t.id,
+			ReqID:     t.reqID,
+			Initiator: gdctx.MyUUID,
+			State:     TxnStatePending,
+		},
+		logger: t.Ctx.Logger(),
+	}
+}
+
+// start records the steps of the transaction and marks it as running
+func (r *txnRecord) start(steps []*Step) {
+	r.info.State = TxnStateRunning
+	r.info.StartTime = time.Now()
+	r.info.Steps = make([]api.TxnStepInfo, len(steps))
+
+	for i, s := range steps {
+		step := api.TxnStepInfo{
+			Name:  s.DoFunc,
+			State: TxnStatePending,
+		}
+		if s.Skip {
+			step.State = TxnStateSkipped
+		}
+		for _, node := range s.Nodes {
+			step.Nodes = append(step.Nodes, api.TxnNodeStatus{
+				PeerID: node,
+				State:  step.State,
+			})
+		}
+		r.info.Steps[i] = step
+	}
+	r.save()
+}
+
+// stepStarted marks the step and all of its nodes as running
+func (r *txnRecord) stepStarted(i int) {
+	step := &r.info.Steps[i]
+	step.State = TxnStateRunning
+	step.StartTime = time.Now()
+	for n := range step.Nodes {
+		step.Nodes[n].State = TxnStateRunning
+	}
+	r.save()
+}
+
+// stepDone records the result of the step on each of its nodes
+func (r *txnRecord) stepDone(i int, err error) {
+	step := &r.info.Steps[i]
+	step.EndTime = time.Now()
+	step.State = TxnStateSucceeded
+	if err != nil {
+		step.State = TxnStateFailed
+	}
+
+	errs := make(map[string]string)
+	if resp, ok := err.(stepResp); ok {
+		for _, peerResp := range resp.Resps {
+			if peerResp.PeerID != nil && peerResp.Error != nil {
+				errs[peerResp.PeerID.String()] = peerResp.Error.Error()
+			}
+		}
+	}
+
+	for n := range step.Nodes {
+		node := &step.Nodes[n]
+		node.State = TxnStateSucceeded
+		if e, ok := errs[node.PeerID.String()]; ok {
+			node.State = TxnStateFailed
+			node.Error = e
+		} else if err != nil && len(errs) == 0 {
+			node.State = TxnStateFailed
+			node.Error = err.Error()
+		}
+	}
+	r.save()
+}
+
+// done marks the end of the transaction
+func (r *txnRecord) done(err error) {
+	r.info.EndTime = time.Now()
+	switch err {
+	case nil:
+		r.info.State = TxnStateSucceeded
+	case ErrTxnCancelled:
+		r.info.State = TxnStateCancelled
+	default:
+		r.info.State = TxnStateFailed
+		r.info.Error = err.Error()
+	}
+	r.save()
+}
+
+// saveTxnRecordF writes transaction records, it is a variable so that tests
+// can keep records out of the store
+var saveTxnRecordF = saveTxnRecord
+
+func (r *txnRecord) save() {
+	saveTxnRecordF(r)
+}
+
+// saveTxnRecord writes the record to the store. Records are written with a
+// TTL so that they go away on their own after a while. Failing to save a
+// record must not fail the transaction, so errors are only logged.
+func saveTxnRecord(r *txnRecord) {
+	if r.leaseID == clientv3.NoLease {
+		l, err := store.Store.Grant(store.Store.Ctx(), txnRecordTTL())
+		if err != nil {
+			r.logger.WithError(err).Error("failed to save transaction record, failed to get lease")
+			return
+		}
+		r.leaseID = l.ID
+	}
+
+	v, err := json.Marshal(r.info)
+	if err != nil {
+		r.logger.WithError(err).Error("failed to save transaction record, failed to marshal record")
+		return
+	}
+
+	if _, err := store.Put(context.TODO(), txnRecordPrefix+r.info.ID.String(), string(v), clientv3.WithLease(r.leaseID)); err != nil {
+		r.logger.WithError(err).Error("failed to save transaction record, failed to write record to store")
+	}
+}
+
+// markTxnRecordFailed marks the record of a transaction which was left
+// running by its initiator as failed
+func markTxnRecordFailed(id uuid.UUID, err error, logger log.FieldLogger) {
+	info, e := GetTxnRecord(id)
+	if e != nil {
+		return
+	}
+
+	r := &txnRecord{info: *info, logger: logger}
+	r.done(err)
+}
+
+// GetTxnRecords returns the records of all the transactions which are running
+// or have recently ended, most recent first
+func GetTxnRecords() ([]api.TxnInfo, error) {
+	resp, err := store.Get(context.TODO(), txnRecordPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]api.TxnInfo, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var info api.TxnInfo
+		if err := json.Unmarshal(kv.Value, &info); err != nil {
+			return nil, err
+		}
+		records = append(records, info)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].StartTime.After(records[j].StartTime)
+	})
+
+	return records, nil
+}
+
+// GetTxnRecord returns the record of the transaction with the given ID
+func GetTxnRecord(id uuid.UUID) (*api.TxnInfo, error) {
+	resp, err := store.Get(context.TODO(), txnRecordPrefix+id.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Count != 1 {
+		return nil, gderrors.ErrTxnNotFound
+	}
+
+	var info api.TxnInfo
+	if err := json.Unmarshal(resp.Kvs[0].Value, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
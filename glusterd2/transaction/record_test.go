@@ -0,0 +1,125 @@
+package transaction
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/testutils"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// patchTxnRecords keeps the saved records in memory, and returns the number
+// of times they were saved
+func patchTxnRecords() (*int, func()) {
+	saves := 0
+	r := testutils.Patch(&saveTxnRecordF, func(*txnRecord) { saves++ })
+	return &saves, r.Restore
+}
+
+func newTestTxnRecord() *txnRecord {
+	return &txnRecord{
+		info:   api.TxnInfo{ID: uuid.NewRandom(), State: TxnStatePending},
+		logger: log.StandardLogger(),
+	}
+}
+
+func TestTxnRecordStart(t *testing.T) {
+	saves, restore := patchTxnRecords()
+	defer restore()
+
+	node1, node2 := uuid.NewRandom(), uuid.NewRandom()
+	r := newTestTxnRecord()
+	r.start([]*Step{
+		{DoFunc: "test.Step1", Nodes: []uuid.UUID{node1, node2}},
+		{DoFunc: "test.Step2", Nodes: []uuid.UUID{node1}, Skip: true},
+	})
+
+	assert.Equal(t, 1, *saves)
+	assert.Equal(t, TxnStateRunning, r.info.State)
+	assert.False(t, r.info.StartTime.IsZero())
+	assert.Len(t, r.info.Steps, 2)
+
+	assert.Equal(t, "test.Step1", r.info.Steps[0].Name)
+	assert.Equal(t, TxnStatePending, r.info.Steps[0].State)
+	assert.Equal(t, []api.TxnNodeStatus{
+		{PeerID: node1, State: TxnStatePending},
+		{PeerID: node2, State: TxnStatePending},
+	}, r.info.Steps[0].Nodes)
+
+	assert.Equal(t, TxnStateSkipped, r.info.Steps[1].State)
+	assert.Equal(t, TxnStateSkipped, r.info.Steps[1].Nodes[0].State)
+}
+
+func TestTxnRecordStepDone(t *testing.T) {
+	_, restore := patchTxnRecords()
+	defer restore()
+
+	node1, node2 := uuid.NewRandom(), uuid.NewRandom()
+	stepErr := errors.New("brick not found")
+
+	tests := []struct {
+		name       string
+		err        error
+		state      string
+		nodeStates []string
+		nodeErrors []string
+	}{
+		{"succeeded", nil, TxnStateSucceeded,
+			[]string{TxnStateSucceeded, TxnStateSucceeded}, []string{"", ""}},
+		{"failed on one node", stepResp{Step: "test.Step", Resps: []stepPeerResp{{node1, nil}, {node2, stepErr}}, errCount: 1},
+			TxnStateFailed, []string{TxnStateSucceeded, TxnStateFailed}, []string{"", stepErr.Error()}},
+		{"failed without node errors", stepErr, TxnStateFailed,
+			[]string{TxnStateFailed, TxnStateFailed}, []string{stepErr.Error(), stepErr.Error()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestTxnRecord()
+			r.start([]*Step{{DoFunc: "test.Step", Nodes: []uuid.UUID{node1, node2}}})
+
+			r.stepStarted(0)
+			step := r.info.Steps[0]
+			assert.Equal(t, TxnStateRunning, step.State)
+			assert.False(t, step.StartTime.IsZero())
+			for _, n := range step.Nodes {
+				assert.Equal(t, TxnStateRunning, n.State)
+			}
+
+			r.stepDone(0, tt.err)
+			step = r.info.Steps[0]
+			assert.Equal(t, tt.state, step.State)
+			assert.False(t, step.EndTime.IsZero())
+			for i, n := range step.Nodes {
+				assert.Equal(t, tt.nodeStates[i], n.State)
+				assert.Equal(t, tt.nodeErrors[i], n.Error)
+			}
+		})
+	}
+}
+
+func TestTxnRecordDone(t *testing.T) {
+	_, restore := patchTxnRecords()
+	defer restore()
+
+	tests := []struct {
+		err   error
+		state string
+		msg   string
+	}{
+		{nil, TxnStateSucceeded, ""},
+		{ErrTxnCancelled, TxnStateCancelled, ""},
+		{errors.New("lock not obtained"), TxnStateFailed, "lock not obtained"},
+	}
+
+	for _, tt := range tests {
+		r := newTestTxnRecord()
+		r.done(tt.err)
+		assert.Equal(t, tt.state, r.info.State)
+		assert.Equal(t, tt.msg, r.info.Error)
+		assert.False(t, r.info.EndTime.IsZero())
+	}
+}
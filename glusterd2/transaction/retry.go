@@ -0,0 +1,113 @@
+package transaction
+
+import (
+	"context"
+	"time"
+
+	"github.com/coreos/etcd/etcdserver/api/v3rpc/rpctypes"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// RetryPolicy controls how a step is retried when it fails with an error
+// which is likely to go away on its own
+type RetryPolicy struct {
+	// Count is the number of times the step is retried
+	Count int
+	// Backoff is the time waited before the first retry. It is doubled on
+	// every retry.
+	Backoff time.Duration
+	// Retryable reports whether the step is to be retried on err. Defaults
+	// to IsTransientError.
+	Retryable func(err error) bool `json:"-"`
+}
+
+var (
+	// DefaultRetryPolicy can be set as the RetryPolicy of steps which are
+	// idempotent, ie. which can safely be run again on a node where they
+	// may have already succeeded
+	DefaultRetryPolicy = &RetryPolicy{Count: 3, Backoff: 500 * time.Millisecond}
+	// NoRetry is used for steps which don't set a RetryPolicy
+	NoRetry = &RetryPolicy{}
+)
+
+// transientErrors are errors which are likely to go away on their own. Errors
+// of step funcs run on remote peers only carry the error message, so these
+// are matched by message.
+var transientErrors = []error{
+	rpctypes.ErrLeaderChanged,
+	rpctypes.ErrNoLeader,
+	rpctypes.ErrTimeoutDueToLeaderFail,
+	rpctypes.ErrTimeoutDueToConnectionLost,
+}
+
+// IsTransientError reports whether err is likely to go away on its own, like
+// errors due to etcd leader elections. A step failure is transient if it
+// failed with a transient error on all the nodes it failed on.
+//
+// Peers being unreachable is not a transient error: the RPC may have been
+// delivered, and the step run, before the connection was lost.
+func IsTransientError(err error) bool {
+	return allPeerErrors(err, func(err error) bool {
+		for _, e := range transientErrors {
+			if err.Error() == e.Error() {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// IsUnavailableError reports whether err is due to the peers the step failed
+// on being unreachable
+func IsUnavailableError(err error) bool {
+	return allPeerErrors(err, func(err error) bool {
+		return grpc.Code(err) == codes.Unavailable
+	})
+}
+
+// allPeerErrors reports whether match is true for err, or for the errors of
+// all the nodes a step failed on if err is a step failure
+func allPeerErrors(err error, match func(error) bool) bool {
+	if err == nil {
+		return false
+	}
+
+	if resp, ok := err.(stepResp); ok {
+		for _, peerResp := range resp.Resps {
+			if peerResp.Error != nil && !match(peerResp.Error) {
+				return false
+			}
+		}
+		return resp.errCount != 0
+	}
+
+	return match(err)
+}
+
+// Do runs f until it succeeds, fails with an error which isn't retryable, the
+// retries run out or ctx is done
+func (p *RetryPolicy) Do(ctx context.Context, logger log.FieldLogger, f func() error) error {
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsTransientError
+	}
+
+	backoff := p.Backoff
+	for attempt := 0; ; attempt++ {
+		err := f()
+		if err == nil || attempt >= p.Count || !retryable(err) {
+			return err
+		}
+
+		logger.WithError(err).WithField("attempt", attempt+1).Warn("step failed with a transient error, retrying")
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,139 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+
+	"github.com/coreos/etcd/etcdserver/api/v3rpc/rpctypes"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsTransientError(t *testing.T) {
+	otherErr := errors.New("brick path already in use")
+	unavailableErr := status.Error(codes.Unavailable, "connection refused")
+
+	tests := []struct {
+		name        string
+		err         error
+		transient   bool
+		unavailable bool
+	}{
+		{"nil", nil, false, false},
+		{"other error", otherErr, false, false},
+		{"leader changed", rpctypes.ErrLeaderChanged, true, false},
+		{"leader changed message from a remote peer", errors.New(rpctypes.ErrLeaderChanged.Error()), true, false},
+		{"peer unavailable", unavailableErr, false, true},
+		{
+			"step failed with transient errors",
+			stepResp{Resps: []stepPeerResp{{Error: rpctypes.ErrNoLeader}, {}}, errCount: 1},
+			true, false,
+		},
+		{
+			"step failed with transient and other errors",
+			stepResp{Resps: []stepPeerResp{{Error: rpctypes.ErrNoLeader}, {Error: otherErr}}, errCount: 2},
+			false, false,
+		},
+		{
+			"step failed on unavailable peers",
+			stepResp{Resps: []stepPeerResp{{Error: unavailableErr}, {}}, errCount: 1},
+			false, true,
+		},
+		{"step didn't fail", stepResp{Resps: []stepPeerResp{{}}}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.transient, IsTransientError(tt.err))
+			assert.Equal(t, tt.unavailable, IsUnavailableError(tt.err))
+		})
+	}
+}
+
+func TestRetryPolicyDo(t *testing.T) {
+	logger := log.NewEntry(log.StandardLogger())
+	policy := &RetryPolicy{Count: 3, Backoff: time.Millisecond}
+
+	// Retried until it succeeds
+	calls := 0
+	err := policy.Do(context.Background(), logger, func() error {
+		calls++
+		if calls < 3 {
+			return rpctypes.ErrLeaderChanged
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, calls)
+
+	// Gives up once the retries run out
+	calls = 0
+	err = policy.Do(context.Background(), logger, func() error {
+		calls++
+		return rpctypes.ErrLeaderChanged
+	})
+	assert.Equal(t, rpctypes.ErrLeaderChanged, err)
+	assert.Equal(t, policy.Count+1, calls)
+
+	// Errors which aren't transient aren't retried
+	calls = 0
+	otherErr := errors.New("brick path already in use")
+	err = policy.Do(context.Background(), logger, func() error {
+		calls++
+		return otherErr
+	})
+	assert.Equal(t, otherErr, err)
+	assert.Equal(t, 1, calls)
+
+	// Not retried once the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	err = (&RetryPolicy{Count: 3, Backoff: time.Minute}).Do(ctx, logger, func() error {
+		calls++
+		return rpctypes.ErrLeaderChanged
+	})
+	assert.Equal(t, rpctypes.ErrLeaderChanged, err)
+	assert.Equal(t, 1, calls)
+
+	// NoRetry runs f once
+	calls = 0
+	err = NoRetry.Do(context.Background(), logger, func() error {
+		calls++
+		return rpctypes.ErrLeaderChanged
+	})
+	assert.Equal(t, rpctypes.ErrLeaderChanged, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestStepRetry(t *testing.T) {
+	defer func(id uuid.UUID) { gdctx.MyUUID = id }(gdctx.MyUUID)
+	gdctx.MyUUID = uuid.NewRandom()
+
+	calls := 0
+	RegisterStepFunc(func(TxnCtx) error {
+		calls++
+		if calls == 1 {
+			return rpctypes.ErrLeaderChanged
+		}
+		return nil
+	}, "test.FlakyStep")
+
+	// Steps aren't retried unless they set a RetryPolicy
+	s := &Step{DoFunc: "test.FlakyStep", Nodes: []uuid.UUID{gdctx.MyUUID}}
+	assert.Equal(t, NoRetry, s.RetryPolicy())
+	assert.NotNil(t, s.do(context.Background(), NewMockCtx()))
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	s.Retry = &RetryPolicy{Count: 1, Backoff: time.Millisecond}
+	assert.Nil(t, s.do(context.Background(), NewMockCtx()))
+	assert.Equal(t, 2, calls)
+}
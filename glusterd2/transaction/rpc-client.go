@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 
+	"github.com/gluster/glusterd2/glusterd2/certs"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	"github.com/gluster/glusterd2/pkg/utils"
 
@@ -35,7 +36,7 @@ func runStepOn(origCtx context.Context, step string, node uuid.UUID, c TxnCtx) e
 
 	conn, err = grpc.Dial(remote,
 		grpc.WithStatsHandler(&ocgrpc.ClientHandler{}),
-		grpc.WithInsecure(),
+		certs.GrpcDialOption(),
 	)
 	if err == nil && conn != nil {
 		logger.WithFields(log.Fields{
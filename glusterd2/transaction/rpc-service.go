@@ -53,6 +53,10 @@ func (p *txnSvc) RunStep(rpcCtx context.Context, req *TxnStepReq) (*TxnStepResp,
 		goto End
 	}
 
+	// The RPC context gets cancelled if the initiator of the transaction
+	// cancels it
+	ctx.setContext(rpcCtx)
+
 	logger.Debug("executing step function")
 	if err = f(&ctx); err != nil {
 		logger.WithError(err).Error("step function failed")
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/pkg/api"
@@ -22,22 +23,72 @@ type StepFunc func(TxnCtx) error
 // DoFunc and UndoFunc are names of StepFuncs registered in the registry
 // DoFunc performs does the action
 // UndoFunc undoes anything done by DoFunc
+// Timeout is the time after which the step is failed if it hasn't completed
+// on all the nodes. A zero Timeout lets the step run until it completes.
+// Retry is the policy used to retry the step on transient failures. Steps
+// which don't set it are not retried, as only idempotent steps can be.
 type Step struct {
 	DoFunc   string
 	UndoFunc string
 	Nodes    []uuid.UUID
 	Skip     bool
 	Sync     bool
+	Timeout  time.Duration
+	Retry    *RetryPolicy
 }
 
+// Default timeouts of steps which are known to take long or to hang on a node
+const (
+	// BrickPrepareTimeout is the timeout of steps provisioning bricks
+	BrickPrepareTimeout = 10 * time.Minute
+	// BrickCleanTimeout is the timeout of steps cleaning up bricks
+	BrickCleanTimeout = 5 * time.Minute
+	// DaemonStartTimeout is the timeout of steps starting daemons, like
+	// the rebalance process
+	DaemonStartTimeout = 2 * time.Minute
+)
+
 var (
 	// ErrStepFuncNotFound is returned if the stepfunc isn't found.
 	ErrStepFuncNotFound = errors.New("stepFunc was not found")
+	// ErrTxnCancelled is returned if the transaction got cancelled.
+	ErrTxnCancelled = errors.New("transaction was cancelled")
+	// ErrTxnTimeout is returned if the transaction didn't complete within
+	// its timeout.
+	ErrTxnTimeout = errors.New("transaction timed out")
 )
 
+// RetryPolicy returns the policy used to retry the step
+func (s *Step) RetryPolicy() *RetryPolicy {
+	if s.Retry == nil {
+		return NoRetry
+	}
+	return s.Retry
+}
+
 // do runs the DoFunc on the nodes
 func (s *Step) do(origCtx context.Context, ctx TxnCtx) error {
-	return runStepFuncOnNodes(origCtx, s.DoFunc, ctx, s.Nodes)
+	stepCtx := origCtx
+	if s.Timeout != 0 {
+		var cancel context.CancelFunc
+		stepCtx, cancel = context.WithTimeout(origCtx, s.Timeout)
+		defer cancel()
+	}
+
+	// Retries are run only on the nodes the step failed on
+	nodes := s.Nodes
+	err := s.RetryPolicy().Do(stepCtx, ctx.Logger().WithField("step", s.DoFunc), func() error {
+		err := runStepFuncOnNodes(stepCtx, s.DoFunc, ctx, nodes)
+		if resp, ok := err.(stepResp); ok {
+			nodes = resp.failedNodes()
+		}
+		return err
+	})
+
+	if err != nil && stepCtx.Err() == context.DeadlineExceeded && origCtx.Err() == nil {
+		return stepTimeoutError{Step: s.DoFunc, Timeout: s.Timeout}
+	}
+	return err
 }
 
 // undo runs the UndoFunc on the nodes
@@ -48,6 +99,17 @@ func (s *Step) undo(ctx TxnCtx) error {
 	return nil
 }
 
+// stepTimeoutError is returned if a step doesn't complete on all the nodes
+// within its timeout
+type stepTimeoutError struct {
+	Step    string
+	Timeout time.Duration
+}
+
+func (e stepTimeoutError) Error() string {
+	return fmt.Sprintf("Step %s timed out after %s", e.Step, e.Timeout)
+}
+
 // stepPeerResp is response from a single peer that runs a step
 type stepPeerResp struct {
 	PeerID uuid.UUID
@@ -62,6 +124,17 @@ type stepResp struct {
 	errCount int
 }
 
+// failedNodes returns the nodes the step failed on
+func (r stepResp) failedNodes() []uuid.UUID {
+	var nodes []uuid.UUID
+	for _, resp := range r.Resps {
+		if resp.Error != nil {
+			nodes = append(nodes, resp.PeerID)
+		}
+	}
+	return nodes
+}
+
 func (r stepResp) Error() string {
 	return fmt.Sprintf("Step %s failed on %d nodes", r.Step, r.errCount)
 }
@@ -107,9 +180,13 @@ func runStepFuncOnNodes(origCtx context.Context, stepName string, ctx TxnCtx, no
 
 	resp := stepResp{
 		Step:  stepName,
-		Resps: make([]stepPeerResp, len(nodes)),
+		Resps: make([]stepPeerResp, 0, len(nodes)),
 	}
 
+	// If the transaction gets cancelled, the responses of all the nodes
+	// are still waited for. The step returns early on the nodes as its
+	// context is cancelled, and it is no longer running anywhere once
+	// this returns, so that it can be undone.
 	var peerResp stepPeerResp
 	for range nodes {
 		peerResp = <-respCh
@@ -122,6 +199,10 @@ func runStepFuncOnNodes(origCtx context.Context, stepName string, ctx TxnCtx, no
 		resp.Resps = append(resp.Resps, peerResp)
 	}
 
+	if err := origCtx.Err(); err != nil {
+		return err
+	}
+
 	if resp.errCount != 0 {
 		return resp
 	}
@@ -164,12 +245,21 @@ func traceStep(f runFunc) runFunc {
 }
 
 // RunStepFuncLocally runs a step func on local node
-func RunStepFuncLocally(origCtx context.Context, stepName string, ctx TxnCtx) error {
+func RunStepFuncLocally(origCtx context.Context, stepName string, ctx TxnCtx) (err error) {
 	stepFunc, ok := getStepFunc(stepName)
 	if !ok {
 		return ErrStepFuncNotFound
 	}
 
+	if origCtx != nil {
+		ctx.setContext(origCtx)
+	}
+
+	start := time.Now()
+	defer func() {
+		recordStepFunc(stepName, time.Since(start), err)
+	}()
+
 	if err := stepFunc(ctx); err != nil {
 		return err
 	}
@@ -0,0 +1,62 @@
+package transaction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunStepFuncOnNodesCancelled(t *testing.T) {
+	defer func(id uuid.UUID) { gdctx.MyUUID = id }(gdctx.MyUUID)
+	gdctx.MyUUID = uuid.NewRandom()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	finished := false
+	RegisterStepFunc(func(c TxnCtx) error {
+		close(started)
+		// The step ignores the cancellation until it is released
+		<-release
+		finished = true
+		return c.Context().Err()
+	}, "test.SlowStep")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- runStepFuncOnNodes(ctx, "test.SlowStep", NewMockCtx(), []uuid.UUID{gdctx.MyUUID})
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case <-errCh:
+		t.Fatal("returned while the step was still running")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	assert.Equal(t, context.Canceled, <-errCh)
+	assert.True(t, finished)
+}
+
+func TestRunStepFuncOnNodesFailed(t *testing.T) {
+	defer func(id uuid.UUID) { gdctx.MyUUID = id }(gdctx.MyUUID)
+	gdctx.MyUUID = uuid.NewRandom()
+
+	RegisterStepFunc(func(c TxnCtx) error {
+		return ErrTxnTimeout
+	}, "test.FailedStep")
+
+	err := runStepFuncOnNodes(context.Background(), "test.FailedStep", NewMockCtx(), []uuid.UUID{gdctx.MyUUID})
+	resp, ok := err.(stepResp)
+	assert.True(t, ok)
+	assert.Equal(t, 1, resp.errCount)
+	assert.Equal(t, []uuid.UUID{gdctx.MyUUID}, resp.failedNodes())
+}
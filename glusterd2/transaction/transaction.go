@@ -5,8 +5,10 @@ import (
 	"context"
 	"expvar"
 	"fmt"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/metrics"
 	"github.com/gluster/glusterd2/glusterd2/store"
 
 	"github.com/coreos/etcd/clientv3"
@@ -29,6 +31,8 @@ type Txn struct {
 	locks       Locks
 	reqID       uuid.UUID
 	storePrefix string
+	record      *txnRecord
+	journal     *txnJournal
 
 	Ctx             TxnCtx
 	Steps           []*Step
@@ -39,6 +43,10 @@ type Txn struct {
 	// nodes before running the transaction steps.
 	Nodes   []uuid.UUID
 	OrigCtx context.Context
+	// Timeout is the time after which the transaction is failed and rolled
+	// back if it hasn't completed. A zero Timeout lets it run until it
+	// completes.
+	Timeout time.Duration
 }
 
 // NewTxn returns an initialized Txn without any steps
@@ -47,6 +55,7 @@ func NewTxn(ctx context.Context) *Txn {
 
 	t.id = uuid.NewRandom()
 	t.reqID = gdctx.GetReqID(ctx)
+	gdctx.RecordTxnID(ctx, t.id)
 	t.locks = make(map[string]*concurrency.Mutex)
 	t.storePrefix = txnPrefix + t.id.String() + "/"
 	config := &TxnCtxConfig{
@@ -57,6 +66,7 @@ func NewTxn(ctx context.Context) *Txn {
 		StorePrefix: t.storePrefix,
 	}
 	t.Ctx = newCtx(config)
+	t.record = newTxnRecord(t)
 
 	t.OrigCtx = ctx
 	t.Ctx.Logger().Debug("new transaction created")
@@ -71,13 +81,14 @@ func NewTxnWithLocks(ctx context.Context, lockIDs ...string) (*Txn, error) {
 		logger := t.Ctx.Logger().WithField("lockID", id)
 		logger.Debug("attempting to obtain lock")
 
-		if err := t.locks.Lock(id); err != nil {
+		if err := t.locks.LockWithOwner(t.id, id); err != nil {
 			logger.WithError(err).Error("failed to obtain lock")
 			t.Done()
 			return nil, err
 		}
 
 		logger.Debug("lock obtained")
+		t.record.info.Locks = append(t.record.info.Locks, id)
 	}
 
 	return t, nil
@@ -97,6 +108,12 @@ func (t *Txn) Done() {
 			t.storePrefix).Error("Failed to remove transaction namespace from store")
 	}
 
+	if t.journal != nil {
+		if err := removeJournal(t.id); err != nil {
+			t.Ctx.Logger().WithError(err).Error("failed to remove transaction journal")
+		}
+	}
+
 	expTxn.Add("initiated_txn_in_progress", -1)
 }
 
@@ -119,8 +136,57 @@ func (t *Txn) checkAlive() error {
 	return nil
 }
 
+// detachedCtx is a context with the values of another context, like the
+// request ID, but without its cancellation and deadline
+type detachedCtx struct {
+	context.Context
+	values context.Context
+}
+
+func (c detachedCtx) Value(key interface{}) interface{} {
+	return c.values.Value(key)
+}
+
+// detach returns a context with the values of ctx, which isn't cancelled
+// along with it
+func detach(ctx context.Context) context.Context {
+	return detachedCtx{Context: context.Background(), values: ctx}
+}
+
 // Do runs the transaction on the cluster
 func (t *Txn) Do() error {
+	// A client going away must not roll back the txn started for its
+	// request, the txn is only cancelled through the cancel API or once
+	// it times out
+	parent := context.Background()
+	if t.OrigCtx != nil {
+		parent = detach(t.OrigCtx)
+	}
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	if t.Timeout != 0 {
+		ctx, cancel = context.WithTimeout(parent, t.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(parent)
+	}
+	defer cancel()
+
+	// the watch is set up before the record is saved, as cancellation can
+	// only be requested once the record shows the txn as running
+	t.watchCancel(ctx, cancel)
+
+	start := time.Now()
+	err := t.do(ctx)
+	t.record.done(err)
+	metrics.ObserveTxn(start, err)
+	return err
+}
+
+func (t *Txn) do(ctx context.Context) error {
+	t.record.start(t.Steps)
+
 	if !t.DontCheckAlive {
 		if err := t.checkAlive(); err != nil {
 			return err
@@ -135,12 +201,30 @@ func (t *Txn) Do() error {
 		return err
 	}
 
+	// the journal lets the txn be rolled back by another peer if this
+	// peer goes down before the txn ends
+	if err := t.startJournal(); err != nil {
+		return err
+	}
+
 	for i, s := range t.Steps {
 		if s.Skip {
 			continue
 		}
 
-		if err := s.do(t.OrigCtx, t.Ctx); err != nil {
+		if ctx.Err() != nil {
+			return t.abort(ctx, i-1)
+		}
+
+		t.journalStep(i)
+		t.record.stepStarted(i)
+		err := s.do(ctx, t.Ctx)
+		t.record.stepDone(i, err)
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return t.abort(ctx, i)
+			}
 			if t.DontCheckAlive && isNodeUnreachable(err) {
 				continue
 			}
@@ -157,7 +241,30 @@ func (t *Txn) Do() error {
 	return nil
 }
 
+// abort undoes the steps run till step n, as the txn got cancelled or timed
+// out
+func (t *Txn) abort(ctx context.Context, n int) error {
+	err := ErrTxnCancelled
+	if ctx.Err() == context.DeadlineExceeded {
+		err = ErrTxnTimeout
+		expTxn.Add("initiated_txn_timeout", 1)
+	} else {
+		expTxn.Add("initiated_txn_cancelled", 1)
+	}
+
+	if !t.DisableRollback {
+		t.Ctx.Logger().WithError(err).Error("Transaction aborted, rolling back changes")
+		t.undo(n)
+	}
+	return err
+}
+
 func isNodeUnreachable(err error) bool {
+	// a step which timed out has reached the nodes
+	if _, ok := err.(stepTimeoutError); ok {
+		return false
+	}
+
 	unreachable := true
 	if s, ok := err.(*stepResp); ok {
 		for _, e := range s.Resps {
@@ -0,0 +1,27 @@
+package transaction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetach(t *testing.T) {
+	reqID := uuid.NewRandom()
+	reqCtx, cancel := context.WithTimeout(gdctx.WithReqID(context.Background(), reqID), time.Minute)
+	ctx := detach(reqCtx)
+	cancel()
+
+	// The values of the request are kept, but not its cancellation
+	assert.Equal(t, reqID, gdctx.GetReqID(ctx))
+	assert.Error(t, reqCtx.Err())
+	assert.NoError(t, ctx.Err())
+	assert.True(t, ctx.Done() == nil)
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+}
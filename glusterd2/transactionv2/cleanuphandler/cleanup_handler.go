@@ -7,7 +7,10 @@ import (
 	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/jobs"
+	"github.com/gluster/glusterd2/glusterd2/scheduler"
 	"github.com/gluster/glusterd2/glusterd2/store"
+	txnv1 "github.com/gluster/glusterd2/glusterd2/transaction"
 	"github.com/gluster/glusterd2/glusterd2/transactionv2"
 
 	"github.com/coreos/etcd/clientv3"
@@ -16,9 +19,11 @@ import (
 )
 
 const (
-	leaderKey       = "cleanup-leader"
-	cleanupTimerDur = time.Minute * 5
-	txnMaxAge       = time.Minute * 5
+	leaderKey        = "cleanup-leader"
+	cleanupTimerDur  = time.Minute * 5
+	recoverTimerDur  = time.Minute
+	scheduleTimerDur = time.Second * 30
+	txnMaxAge        = time.Minute * 5
 )
 
 // CleanupLeader is responsible for performing all cleaning operation
@@ -91,6 +96,9 @@ func (c *CleanupHandler) Run() {
 
 	go transaction.UntilStop(c.HandleStaleTxn, cleanupTimerDur, c.stopChan)
 	go transaction.UntilStop(c.CleanFailedTxn, cleanupTimerDur, c.stopChan)
+	go transaction.UntilStop(c.RecoverOrphanedTxns, recoverTimerDur, c.stopChan)
+	go transaction.UntilStop(c.RunSchedules, scheduleTimerDur, c.stopChan)
+	go transaction.UntilStop(c.CleanupJobs, recoverTimerDur, c.stopChan)
 
 	<-c.stopChan
 	log.Info("cleanup handler stopped")
@@ -119,6 +127,40 @@ func (c *CleanupHandler) CleanFailedTxn() {
 	}
 }
 
+// RecoverOrphanedTxns rolls back the transactions run by the old transaction
+// framework whose initiator went down while running them
+func (c *CleanupHandler) RecoverOrphanedTxns() {
+	c.Lock()
+	isLeader := c.isLeader
+	c.Unlock()
+
+	if isLeader {
+		txnv1.RecoverOrphanedTxns()
+	}
+}
+
+// RunSchedules runs the scheduled operations which are due
+func (c *CleanupHandler) RunSchedules() {
+	c.Lock()
+	isLeader := c.isLeader
+	c.Unlock()
+
+	if isLeader {
+		scheduler.RunDue()
+	}
+}
+
+// CleanupJobs fails the jobs whose peer went down and removes old jobs
+func (c *CleanupHandler) CleanupJobs() {
+	c.Lock()
+	isLeader := c.isLeader
+	c.Unlock()
+
+	if isLeader {
+		jobs.CleanupJobs()
+	}
+}
+
 // StartElecting triggers a new election campaign.
 // If it succeeded then it assumes the leader role and returns
 func (c *CleanupHandler) StartElecting() {
@@ -177,8 +219,10 @@ func init() {
 	}
 	expVar.(*expvar.Map).Set("cleanup_config", expvar.Func(func() interface{} {
 		return map[string]interface{}{
-			"txn_max_age_seconds": txnMaxAge.Seconds(),
-			"cleanup_dur_seconds": cleanupTimerDur.Seconds(),
+			"txn_max_age_seconds":  txnMaxAge.Seconds(),
+			"cleanup_dur_seconds":  cleanupTimerDur.Seconds(),
+			"recover_dur_seconds":  recoverTimerDur.Seconds(),
+			"schedule_dur_seconds": scheduleTimerDur.Seconds(),
 		}
 	}))
 }
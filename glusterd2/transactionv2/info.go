@@ -0,0 +1,160 @@
+package transaction
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/pborman/uuid"
+)
+
+// nodeStepStatus returns the status of the step at index i on a node, given
+// the status of the txn and the last step executed on the node
+func nodeStepStatus(i int, lastStep int, status TxnStatus) api.TxnNodeStatus {
+	switch {
+	case i <= lastStep:
+		return api.TxnNodeStatus{State: transaction.TxnStateSucceeded}
+	case i == lastStep+1 && status.State == txnRunning:
+		return api.TxnNodeStatus{State: transaction.TxnStateRunning}
+	case i == lastStep+1 && status.State == txnFailed:
+		return api.TxnNodeStatus{State: transaction.TxnStateFailed, Error: status.Reason}
+	}
+	return api.TxnNodeStatus{State: transaction.TxnStatePending}
+}
+
+// stepState sums up the state of a step from its state on each node
+func stepState(nodes []api.TxnNodeStatus) string {
+	succeeded := 0
+	for _, n := range nodes {
+		switch n.State {
+		case transaction.TxnStateFailed, transaction.TxnStateRunning:
+			return n.State
+		case transaction.TxnStateSucceeded:
+			succeeded++
+		}
+	}
+	if succeeded == len(nodes) {
+		return transaction.TxnStateSucceeded
+	}
+	return transaction.TxnStatePending
+}
+
+// txnInfo returns the progress of a txn on each of its nodes
+func txnInfo(txn *Txn) api.TxnInfo {
+	info := api.TxnInfo{
+		ID:        txn.ID,
+		ReqID:     txn.ReqID,
+		State:     transaction.TxnStatePending,
+		StartTime: txn.StartTime,
+	}
+
+	statuses := make(map[string]TxnStatus)
+	lastSteps := make(map[string]int)
+	succeeded := 0
+	for _, nodeID := range txn.Nodes {
+		status, err := GlobalTxnManager.GetTxnStatus(txn.ID, nodeID)
+		if err != nil {
+			status = TxnStatus{State: txnUnknown, Reason: err.Error()}
+		}
+		statuses[nodeID.String()] = status
+
+		lastStep, err := GlobalTxnManager.GetLastExecutedStep(txn.ID, nodeID)
+		if err != nil {
+			lastStep = -1
+		}
+		lastSteps[nodeID.String()] = lastStep
+
+		switch status.State {
+		case txnFailed:
+			info.State = transaction.TxnStateFailed
+			info.Error = status.Reason
+			if status.Reason == transaction.ErrTxnCancelled.Error() {
+				info.State = transaction.TxnStateCancelled
+				info.Error = ""
+			}
+		case txnRunning:
+			if info.State == transaction.TxnStatePending {
+				info.State = transaction.TxnStateRunning
+			}
+		case txnSucceeded:
+			succeeded++
+		}
+	}
+	if succeeded == len(txn.Nodes) {
+		info.State = transaction.TxnStateSucceeded
+	}
+
+	for i, s := range txn.Steps {
+		step := api.TxnStepInfo{Name: s.DoFunc}
+		for _, nodeID := range s.Nodes {
+			nodeStatus := api.TxnNodeStatus{State: transaction.TxnStateSkipped}
+			if !s.Skip {
+				nodeStatus = nodeStepStatus(i, lastSteps[nodeID.String()], statuses[nodeID.String()])
+			}
+			nodeStatus.PeerID = nodeID
+			step.Nodes = append(step.Nodes, nodeStatus)
+		}
+		step.State = transaction.TxnStateSkipped
+		if !s.Skip {
+			step.State = stepState(step.Nodes)
+		}
+		info.Steps = append(info.Steps, step)
+	}
+
+	return info
+}
+
+// GetTxnInfos returns the progress of all the txns in the pending-transaction
+// namespace
+func GetTxnInfos() []api.TxnInfo {
+	var infos []api.TxnInfo
+	for _, txn := range GlobalTxnManager.GetTxns() {
+		infos = append(infos, txnInfo(txn))
+	}
+	return infos
+}
+
+// GetTxnInfo returns the progress of the txn with the given ID
+func GetTxnInfo(id uuid.UUID) (*api.TxnInfo, error) {
+	for _, txn := range GlobalTxnManager.GetTxns() {
+		if uuid.Equal(txn.ID, id) {
+			info := txnInfo(txn)
+			return &info, nil
+		}
+	}
+	return nil, gderrors.ErrTxnNotFound
+}
+
+// CancelTxn cancels a running txn by marking it as failed on all of its
+// nodes. The nodes then roll back the steps they have executed and the
+// initiator releases the locks held by the txn.
+func CancelTxn(id uuid.UUID) error {
+	txn, err := GlobalTxnManager.GetTxnByUUID(id)
+	if err != nil {
+		return gderrors.ErrTxnNotFound
+	}
+
+	succeeded := 0
+	for _, nodeID := range txn.Nodes {
+		status, err := GlobalTxnManager.GetTxnStatus(txn.ID, nodeID)
+		if err != nil {
+			return err
+		}
+		if status.State == txnFailed {
+			return gderrors.ErrTxnNotRunning
+		}
+		if status.State == txnSucceeded {
+			succeeded++
+		}
+	}
+
+	if succeeded == len(txn.Nodes) {
+		return gderrors.ErrTxnNotRunning
+	}
+
+	// Nodes on which the txn has succeeded are marked as failed too, so
+	// that they roll back as well
+	txn.Ctx.Logger().Info("txn cancellation requested, marking as failure")
+	status := TxnStatus{State: txnFailed, TxnID: txn.ID, Reason: transaction.ErrTxnCancelled.Error()}
+	return GlobalTxnManager.UpDateTxnStatus(status, txn.ID, txn.Nodes...)
+}
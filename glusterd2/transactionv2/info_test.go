@@ -0,0 +1,219 @@
+package transaction
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/testutils"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTxnManager keeps the status and the last executed step of a txn on
+// each node in memory
+type fakeTxnManager struct {
+	TxnManager
+	txn       *Txn
+	statuses  map[string]TxnStatus
+	lastSteps map[string]int
+}
+
+func newFakeTxnManager(txn *Txn) *fakeTxnManager {
+	return &fakeTxnManager{
+		txn:       txn,
+		statuses:  make(map[string]TxnStatus),
+		lastSteps: make(map[string]int),
+	}
+}
+
+func (m *fakeTxnManager) GetTxns() []*Txn {
+	return []*Txn{m.txn}
+}
+
+func (m *fakeTxnManager) GetTxnByUUID(id uuid.UUID) (*Txn, error) {
+	if !uuid.Equal(id, m.txn.ID) {
+		return nil, errors.New("txn not found")
+	}
+	return m.txn, nil
+}
+
+func (m *fakeTxnManager) GetTxnStatus(txnID uuid.UUID, nodeID uuid.UUID) (TxnStatus, error) {
+	status, ok := m.statuses[nodeID.String()]
+	if !ok {
+		return TxnStatus{}, errors.New("status not found")
+	}
+	return status, nil
+}
+
+func (m *fakeTxnManager) GetLastExecutedStep(txnID uuid.UUID, nodeID uuid.UUID) (int, error) {
+	step, ok := m.lastSteps[nodeID.String()]
+	if !ok {
+		return -1, errors.New("last executed step not found")
+	}
+	return step, nil
+}
+
+func (m *fakeTxnManager) UpDateTxnStatus(status TxnStatus, txnID uuid.UUID, nodeIDs ...uuid.UUID) error {
+	for _, nodeID := range nodeIDs {
+		m.statuses[nodeID.String()] = status
+	}
+	return nil
+}
+
+func TestNodeStepStatus(t *testing.T) {
+	running := TxnStatus{State: txnRunning}
+	failed := TxnStatus{State: txnFailed, Reason: "brick not found"}
+
+	tests := []struct {
+		step     int
+		lastStep int
+		status   TxnStatus
+		state    string
+		err      string
+	}{
+		{0, 1, running, transaction.TxnStateSucceeded, ""},
+		{1, 1, running, transaction.TxnStateSucceeded, ""},
+		{2, 1, running, transaction.TxnStateRunning, ""},
+		{3, 1, running, transaction.TxnStatePending, ""},
+		{0, -1, running, transaction.TxnStateRunning, ""},
+		{2, 1, failed, transaction.TxnStateFailed, "brick not found"},
+		{3, 1, failed, transaction.TxnStatePending, ""},
+		{0, -1, TxnStatus{State: txnPending}, transaction.TxnStatePending, ""},
+	}
+
+	for _, tt := range tests {
+		status := nodeStepStatus(tt.step, tt.lastStep, tt.status)
+		assert.Equal(t, tt.state, status.State, "step %d, last step %d, %s", tt.step, tt.lastStep, tt.status.State)
+		assert.Equal(t, tt.err, status.Error)
+	}
+}
+
+func TestStepState(t *testing.T) {
+	node := func(state string) api.TxnNodeStatus {
+		return api.TxnNodeStatus{State: state}
+	}
+
+	tests := []struct {
+		nodes []api.TxnNodeStatus
+		state string
+	}{
+		{[]api.TxnNodeStatus{node(transaction.TxnStateSucceeded), node(transaction.TxnStateSucceeded)}, transaction.TxnStateSucceeded},
+		{[]api.TxnNodeStatus{node(transaction.TxnStateSucceeded), node(transaction.TxnStatePending)}, transaction.TxnStatePending},
+		{[]api.TxnNodeStatus{node(transaction.TxnStateSucceeded), node(transaction.TxnStateRunning)}, transaction.TxnStateRunning},
+		{[]api.TxnNodeStatus{node(transaction.TxnStateRunning), node(transaction.TxnStateFailed)}, transaction.TxnStateRunning},
+		{[]api.TxnNodeStatus{node(transaction.TxnStateSucceeded), node(transaction.TxnStateFailed)}, transaction.TxnStateFailed},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.state, stepState(tt.nodes), "%v", tt.nodes)
+	}
+}
+
+func TestTxnInfo(t *testing.T) {
+	node1, node2 := uuid.NewRandom(), uuid.NewRandom()
+	txn := &Txn{
+		ID:    uuid.NewRandom(),
+		ReqID: uuid.NewRandom(),
+		Nodes: []uuid.UUID{node1, node2},
+		Steps: []*transaction.Step{
+			{DoFunc: "test.Step1", Nodes: []uuid.UUID{node1, node2}},
+			{DoFunc: "test.Step2", Nodes: []uuid.UUID{node1}, Skip: true},
+			{DoFunc: "test.Step3", Nodes: []uuid.UUID{node1, node2}},
+		},
+	}
+	m := newFakeTxnManager(txn)
+	defer testutils.Patch(&GlobalTxnManager, TxnManager(m)).Restore()
+
+	// node1 has run the first two steps, node2 is running the first one
+	m.statuses[node1.String()] = TxnStatus{State: txnRunning}
+	m.lastSteps[node1.String()] = 1
+	m.statuses[node2.String()] = TxnStatus{State: txnRunning}
+
+	info, err := GetTxnInfo(txn.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, transaction.TxnStateRunning, info.State)
+	assert.Len(t, info.Steps, 3)
+	assert.Equal(t, transaction.TxnStateRunning, info.Steps[0].State)
+	assert.Equal(t, transaction.TxnStateSkipped, info.Steps[1].State)
+	assert.Equal(t, transaction.TxnStateSkipped, info.Steps[1].Nodes[0].State)
+	assert.Equal(t, transaction.TxnStateRunning, info.Steps[2].State)
+	assert.Equal(t, node2, info.Steps[2].Nodes[1].PeerID)
+	assert.Equal(t, transaction.TxnStatePending, info.Steps[2].Nodes[1].State)
+
+	m.statuses[node2.String()] = TxnStatus{State: txnFailed, Reason: "brick not found"}
+	info, err = GetTxnInfo(txn.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, transaction.TxnStateFailed, info.State)
+	assert.Equal(t, "brick not found", info.Error)
+	assert.Equal(t, transaction.TxnStateFailed, info.Steps[0].State)
+
+	m.statuses[node2.String()] = TxnStatus{State: txnFailed, Reason: transaction.ErrTxnCancelled.Error()}
+	info, err = GetTxnInfo(txn.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, transaction.TxnStateCancelled, info.State)
+	assert.Equal(t, "", info.Error)
+
+	m.statuses[node1.String()] = TxnStatus{State: txnSucceeded}
+	m.statuses[node2.String()] = TxnStatus{State: txnSucceeded}
+	m.lastSteps[node2.String()] = 2
+	m.lastSteps[node1.String()] = 2
+	info, err = GetTxnInfo(txn.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, transaction.TxnStateSucceeded, info.State)
+	assert.Equal(t, transaction.TxnStateSucceeded, info.Steps[0].State)
+	assert.Equal(t, transaction.TxnStateSucceeded, info.Steps[2].State)
+
+	assert.Len(t, GetTxnInfos(), 1)
+	_, err = GetTxnInfo(uuid.NewRandom())
+	assert.Equal(t, gderrors.ErrTxnNotFound, err)
+}
+
+func TestCancelTxn(t *testing.T) {
+	node1, node2 := uuid.NewRandom(), uuid.NewRandom()
+	txn := &Txn{
+		ID:    uuid.NewRandom(),
+		Nodes: []uuid.UUID{node1, node2},
+		Ctx:   transaction.NewMockCtx(),
+	}
+
+	tests := []struct {
+		name   string
+		state1 TxnState
+		state2 TxnState
+		err    error
+	}{
+		{"running", txnSucceeded, txnRunning, nil},
+		{"pending", txnPending, txnPending, nil},
+		{"failed", txnRunning, txnFailed, gderrors.ErrTxnNotRunning},
+		{"succeeded", txnSucceeded, txnSucceeded, gderrors.ErrTxnNotRunning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newFakeTxnManager(txn)
+			defer testutils.Patch(&GlobalTxnManager, TxnManager(m)).Restore()
+			m.statuses[node1.String()] = TxnStatus{State: tt.state1}
+			m.statuses[node2.String()] = TxnStatus{State: tt.state2}
+
+			assert.Equal(t, tt.err, CancelTxn(txn.ID))
+			if tt.err != nil {
+				return
+			}
+			// All the nodes roll back, including those the txn
+			// succeeded on
+			for _, node := range txn.Nodes {
+				status := m.statuses[node.String()]
+				assert.Equal(t, txnFailed, status.State)
+				assert.Equal(t, transaction.ErrTxnCancelled.Error(), status.Reason)
+			}
+		})
+	}
+
+	m := newFakeTxnManager(txn)
+	defer testutils.Patch(&GlobalTxnManager, TxnManager(m)).Restore()
+	assert.Equal(t, gderrors.ErrTxnNotFound, CancelTxn(uuid.NewRandom()))
+}
@@ -2,6 +2,7 @@ package transaction
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
@@ -111,5 +112,33 @@ func (sm *stepManager) RunStep(ctx context.Context, step *transaction.Step, txnC
 		txnCtx.Logger().WithField("step", step.DoFunc).Debug("peer is excluded in running this step")
 		return nil
 	}
-	return sm.runStep(ctx, step.DoFunc, txnCtx)
+
+	logger := txnCtx.Logger().WithField("step", step.DoFunc)
+	if step.Timeout == 0 {
+		return step.RetryPolicy().Do(ctx, logger, func() error {
+			return sm.runStep(ctx, step.DoFunc, txnCtx)
+		})
+	}
+
+	stepCtx, cancel := context.WithTimeout(ctx, step.Timeout)
+	defer cancel()
+
+	// the step func is left running if it doesn't return on the context
+	// getting cancelled, so that the step can be failed and rolled back
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- step.RetryPolicy().Do(stepCtx, logger, func() error {
+			return sm.runStep(stepCtx, step.DoFunc, txnCtx)
+		})
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-stepCtx.Done():
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("step %s timed out after %s", step.DoFunc, step.Timeout)
+	}
 }
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/metrics"
 	"github.com/gluster/glusterd2/glusterd2/store"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 
@@ -40,6 +41,7 @@ type Txn struct {
 	DisableRollback bool                `json:"disable_rollback"`
 	StartTime       time.Time           `json:"start_time"`
 	TxnSpanCtx      trace.SpanContext   `json:"txn_span_ctx"`
+	Timeout         time.Duration       `json:"timeout,omitempty"`
 
 	success   chan struct{}
 	error     chan error
@@ -53,6 +55,7 @@ func NewTxn(ctx context.Context) *Txn {
 
 	t.ID = uuid.NewRandom()
 	t.ReqID = gdctx.GetReqID(ctx)
+	gdctx.RecordTxnID(ctx, t.ID)
 	t.locks = transaction.Locks{}
 	t.StorePrefix = txnPrefix + t.ID.String() + "/"
 	config := &transaction.TxnCtxConfig{
@@ -85,7 +88,7 @@ func (t *Txn) acquireClusterLocks(lockIDs ...string) error {
 	for _, id := range lockIDs {
 		logger := t.Ctx.Logger().WithField("lockID", id)
 		logger.Debug("txn attempts to acquire cluster lock")
-		if err := t.locks.Lock(id); err != nil {
+		if err := t.locks.LockWithOwner(t.ID, id); err != nil {
 			logger.WithError(err).Error("failed to obtain lock")
 			t.releaseLocks()
 			return err
@@ -135,9 +138,9 @@ func (t *Txn) checkAlive() error {
 }
 
 // Do runs the transaction on the cluster
-func (t *Txn) Do() error {
+func (t *Txn) Do() (err error) {
 	var (
-		timer = time.NewTimer(txnTimeOut)
+		timer = time.NewTimer(t.timeout())
 	)
 	{
 		t.success = make(chan struct{})
@@ -147,6 +150,7 @@ func (t *Txn) Do() error {
 	}
 	defer timer.Stop()
 	defer close(t.stop)
+	defer func() { metrics.ObserveTxn(t.StartTime, err) }()
 
 	t.Ctx.Logger().Debug("Starting transaction")
 	go t.waitForCompletion()
@@ -172,6 +176,15 @@ func (t *Txn) Do() error {
 	return nil
 }
 
+// timeout returns the time after which the txn is marked as failed if it
+// hasn't completed on all nodes
+func (t *Txn) timeout() time.Duration {
+	if t.Timeout == 0 {
+		return txnTimeOut
+	}
+	return t.Timeout
+}
+
 func (t *Txn) onFailure(err error) error {
 	t.Ctx.Logger().WithError(err).Error("error in executing txn, marking as failure")
 	txnStatus := TxnStatus{State: txnFailed, TxnID: t.ID, Reason: err.Error()}
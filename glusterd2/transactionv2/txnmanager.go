@@ -381,24 +381,41 @@ func (tm *txnManager) WatchLastExecutedStep(stopCh <-chan struct{}, txnID uuid.U
 	return lastExecutedStepChan
 }
 
-// TxnGC will mark all the expired txn as failed based on given maxAge
+// TxnGC will mark all the expired txn as failed based on given maxAge. A txn
+// is not expired before its own timeout. Expired txns which have succeeded on
+// all nodes are left behind only if their initiator went down before
+// cleaning them up, so they are removed instead.
 func (tm *txnManager) TxnGC(maxAge time.Duration) {
 	tm.Lock()
 	defer tm.Unlock()
 
 	txns := tm.GetTxns()
 	for _, txn := range txns {
-		if txn.StartTime.Unix()+int64(maxAge/time.Second) < time.Now().Unix() {
+		age := maxAge
+		if txn.timeout() > age {
+			age = txn.timeout()
+		}
+		if txn.StartTime.Add(age).Before(time.Now()) {
 			nonFailedNodes := []uuid.UUID{}
+			succeeded := 0
 			for _, nodeID := range txn.Nodes {
 				txnStatus, err := tm.GetTxnStatus(txn.ID, nodeID)
 				if err == nil && txnStatus.State != txnFailed {
 					nonFailedNodes = append(nonFailedNodes, nodeID)
 				}
+				if err == nil && txnStatus.State == txnSucceeded {
+					succeeded++
+				}
 			}
 			if len(nonFailedNodes) == 0 {
 				continue
 			}
+			if succeeded == len(txn.Nodes) {
+				txn.Ctx.Logger().Info("txn succeeded on all nodes but was not cleaned up by initiator, cleaning from store")
+				txn.removeContextData()
+				tm.RemoveTransaction(txn.ID)
+				continue
+			}
 			txnStatus := TxnStatus{State: txnFailed, TxnID: txn.ID, Reason: "txn expired"}
 			txn.Ctx.Logger().Info("txn got expired marking as failure")
 			tm.UpDateTxnStatus(txnStatus, txn.ID, nonFailedNodes...)
@@ -0,0 +1,117 @@
+// Package usage periodically samples the utilization of the local bricks of
+// each volume on this peer and saves it in the store, from where the usage of
+// a volume across all its peers can be served by any peer.
+package usage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+const (
+	// IntervalFlag is the flag to set the interval between samples
+	IntervalFlag = "usage-interval"
+	// DefaultInterval is the default interval between samples
+	DefaultInterval = time.Minute
+)
+
+var collector struct {
+	sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Start starts sampling the usage of the local bricks every usage-interval.
+// Sampling is disabled if the interval is 0.
+func Start() {
+	interval := config.GetDuration(IntervalFlag)
+	if interval <= 0 {
+		log.Info("brick usage collection disabled")
+		return
+	}
+
+	collector.Lock()
+	defer collector.Unlock()
+	if collector.stop != nil {
+		return
+	}
+	collector.stop = make(chan struct{})
+	collector.done = make(chan struct{})
+
+	go run(interval, collector.stop, collector.done)
+}
+
+// Stop stops sampling and waits for a sample being taken to finish
+func Stop() {
+	collector.Lock()
+	defer collector.Unlock()
+	if collector.stop == nil {
+		return
+	}
+	close(collector.stop)
+	<-collector.done
+	collector.stop = nil
+	collector.done = nil
+}
+
+func run(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		collect()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// collect samples the usage of the local bricks of every volume and saves it
+// in the store
+func collect() {
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		log.WithError(err).Warn("failed to get volumes to collect brick usage")
+		return
+	}
+
+	for _, v := range volumes {
+		bricks := v.GetLocalBricks()
+		if len(bricks) == 0 {
+			continue
+		}
+
+		u := &volume.PeerUsage{
+			VolumeID: v.ID,
+			PeerID:   gdctx.MyUUID,
+			Time:     time.Now(),
+			Bricks:   make([]volume.BrickUsage, 0, len(bricks)),
+		}
+		for _, b := range bricks {
+			bu, err := volume.SampleBrickUsage(b)
+			if err != nil {
+				log.WithError(err).WithFields(log.Fields{
+					"volume": v.Name,
+					"brick":  b.Path,
+				}).Debug("failed to sample brick usage")
+				continue
+			}
+			u.Bricks = append(u.Bricks, bu)
+		}
+
+		if err := volume.SetPeerUsage(v.Name, u); err != nil {
+			log.WithError(err).WithField("volume", v.Name).Warn("failed to save brick usage")
+		}
+	}
+}
@@ -0,0 +1,278 @@
+// Package users manages the users of the REST API and the roles which limit
+// the requests they can make. Users are kept in the store, along with the
+// secrets with which they sign their tokens.
+package users
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	userPrefix = "users/"
+
+	// InternalUser is the user used by glustercli, whose secret is the
+	// local auth token of the peer. It is always an admin.
+	InternalUser = "glustercli"
+)
+
+var userNameRE = regexp.MustCompile("^[a-zA-Z0-9_.-]+$")
+
+// namespaceRE matches the valid names of namespaces
+var namespaceRE = regexp.MustCompile("^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$")
+
+// roleLevels orders the roles by their privileges, a role is allowed to
+// make the requests of all the roles below it
+var roleLevels = map[string]int{
+	api.RoleViewer:   1,
+	api.RoleOperator: 2,
+	api.RoleAdmin:    3,
+}
+
+// IsValidRole returns true if role is a supported role
+func IsValidRole(role string) bool {
+	_, ok := roleLevels[role]
+	return ok
+}
+
+// RoleAllows returns true if role has at least the privileges of required
+func RoleAllows(role, required string) bool {
+	level, ok := roleLevels[role]
+	return ok && level >= roleLevels[required]
+}
+
+// Roles returns the roles which can be given to users
+func Roles() []api.RoleInfo {
+	return []api.RoleInfo{
+		{Name: api.RoleAdmin, Description: "can make any request, including managing users"},
+		{Name: api.RoleOperator, Description: "can make any request except deleting resources and managing users"},
+		{Name: api.RoleViewer, Description: "can only make GET requests"},
+	}
+}
+
+// IsValidNamespace returns true if namespace is a valid namespace name
+func IsValidNamespace(namespace string) bool {
+	return namespaceRE.MatchString(namespace)
+}
+
+func validateNamespaces(namespaces []string) error {
+	for _, ns := range namespaces {
+		if !IsValidNamespace(ns) {
+			return gderrors.ErrInvalidNamespace
+		}
+	}
+	return nil
+}
+
+// User is a user of the REST API. A user with no namespaces can access the
+// volumes of all the namespaces.
+type User struct {
+	Name       string    `json:"name"`
+	Role       string    `json:"role"`
+	Secret     string    `json:"secret"`
+	Namespaces []string  `json:"namespaces,omitempty"`
+	CreatedAt  time.Time `json:"created-at"`
+	UpdatedAt  time.Time `json:"updated-at"`
+}
+
+// NamespaceAllowed returns true if the user can access the namespace
+func (u *User) NamespaceAllowed(namespace string) bool {
+	if len(u.Namespaces) == 0 {
+		return true
+	}
+	for _, ns := range u.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// NewUser validates the request and returns a new user for it
+func NewUser(req *api.UserCreateReq) (*User, error) {
+	if !userNameRE.MatchString(req.Name) {
+		return nil, fmt.Errorf("invalid user name: %s", req.Name)
+	}
+	if req.Name == InternalUser {
+		return nil, gderrors.ErrUserReserved
+	}
+	if !IsValidRole(req.Role) {
+		return nil, gderrors.ErrInvalidRole
+	}
+	if err := validateNamespaces(req.Namespaces); err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = generateSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now().UTC()
+	return &User{
+		Name:       req.Name,
+		Role:       req.Role,
+		Secret:     secret,
+		Namespaces: req.Namespaces,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+func generateSecret() (string, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", data), nil
+}
+
+// AddUser saves a new user in the store
+func AddUser(u *User) error {
+	v, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+
+	key := userPrefix + u.Name
+	resp, err := store.Txn(context.TODO()).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, string(v))).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if !resp.Succeeded {
+		return gderrors.ErrUserExists
+	}
+	return nil
+}
+
+// GetUser returns the user with the given name
+func GetUser(name string) (*User, error) {
+	resp, err := store.Get(context.TODO(), userPrefix+name)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, gderrors.ErrUserNotFound
+	}
+
+	var u User
+	if err := json.Unmarshal(resp.Kvs[0].Value, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// GetUsers returns all the users
+func GetUsers() ([]*User, error) {
+	resp, err := store.Get(context.TODO(), userPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*User, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var u User
+		if err := json.Unmarshal(kv.Value, &u); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal user")
+			continue
+		}
+		users = append(users, &u)
+	}
+	return users, nil
+}
+
+// EditUser changes the role, the secret or the namespaces of the user with
+// the given name as per the request and returns the changed user
+func EditUser(name string, req *api.UserEditReq) (*User, error) {
+	if req.Role != "" && !IsValidRole(req.Role) {
+		return nil, gderrors.ErrInvalidRole
+	}
+	if req.Namespaces != nil {
+		if err := validateNamespaces(*req.Namespaces); err != nil {
+			return nil, err
+		}
+	}
+
+	key := userPrefix + name
+	for {
+		resp, err := store.Get(context.TODO(), key)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Count != 1 {
+			return nil, gderrors.ErrUserNotFound
+		}
+
+		var u User
+		if err := json.Unmarshal(resp.Kvs[0].Value, &u); err != nil {
+			return nil, err
+		}
+		if req.Role != "" {
+			u.Role = req.Role
+		}
+		if req.Secret != "" {
+			u.Secret = req.Secret
+		}
+		if req.Namespaces != nil {
+			u.Namespaces = *req.Namespaces
+		}
+		u.UpdatedAt = time.Now().UTC()
+
+		v, err := json.Marshal(&u)
+		if err != nil {
+			return nil, err
+		}
+
+		tresp, err := store.Txn(context.TODO()).
+			If(clientv3.Compare(clientv3.ModRevision(key), "=", resp.Kvs[0].ModRevision)).
+			Then(clientv3.OpPut(key, string(v))).
+			Commit()
+		if err != nil {
+			return nil, err
+		}
+		if tresp.Succeeded {
+			return &u, nil
+		}
+	}
+}
+
+// DeleteUser removes the user with the given name
+func DeleteUser(name string) error {
+	resp, err := store.Delete(context.TODO(), userPrefix+name)
+	if err != nil {
+		return err
+	}
+	if resp.Deleted == 0 {
+		return gderrors.ErrUserNotFound
+	}
+	return nil
+}
+
+// CreateUserInfoResp returns the API representation of a user, without its
+// secret
+func CreateUserInfoResp(u *User) *api.UserInfo {
+	return &api.UserInfo{
+		Name:       u.Name,
+		Role:       u.Role,
+		Namespaces: u.Namespaces,
+		CreatedAt:  u.CreatedAt,
+		UpdatedAt:  u.UpdatedAt,
+	}
+}
@@ -7,12 +7,14 @@ import (
 	"os"
 	"path"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/volume"
 
+	"github.com/cespare/xxhash"
 	config "github.com/spf13/viper"
 )
 
@@ -85,6 +87,22 @@ func SaveToFile(filename string, content string) error {
 	return ioutil.WriteFile(filename, []byte(content), 0644)
 }
 
+// Checksum returns the checksum of the volfile content. Options of an xlator
+// are generated in no particular order, so the lines of each xlator are
+// sorted before computing the checksum.
+func Checksum(volfile string) uint64 {
+	var normalized []string
+	for _, xl := range strings.Split(volfile, "end-volume") {
+		lines := strings.Split(strings.TrimSpace(xl), "\n")
+		for i := range lines {
+			lines[i] = strings.TrimSpace(lines[i])
+		}
+		sort.Strings(lines)
+		normalized = append(normalized, strings.Join(lines, "\n"))
+	}
+	return xxhash.Sum64String(strings.Join(normalized, "\nend-volume\n"))
+}
+
 // DeleteFile deletes the given Volfile
 func DeleteFile(volfileID string) error {
 	err := os.Remove(volfileID + ".vol")
@@ -181,19 +199,27 @@ func getExtraStringMaps(volinfo *volume.Volinfo) stringMapVolume {
 	var decommissionedBricks []string
 	clientIdx := 0
 
+	// Bricks of a lone distribute subvol are direct children of the
+	// distribute xlator. Otherwise the children are the subvols and whole
+	// subvols get decommissioned.
+	brickChildren := len(volinfo.Subvols) == 1 && volinfo.Subvols[0].Type == volume.SubvolDistribute
+
 	for sidx, sv := range volinfo.Subvols {
 		var afrPendingXattrs []string
 		data.Subvols[sidx].Bricks = make([]stringMapBrick, len(sv.Bricks))
+		subvolDecommissioned := false
 
 		for bidx, b := range sv.Bricks {
 			data.Subvols[sidx].Bricks[bidx].StringMap = map[string]string{
 				"brick.index": strconv.Itoa(clientIdx),
 			}
-			if b.Decommissioned {
+			if b.Decommissioned && brickChildren {
 				decommissionedBricks = append(
 					decommissionedBricks,
 					fmt.Sprintf("%s-client-%d", sv.Name, bidx),
 				)
+			} else if b.Decommissioned {
+				subvolDecommissioned = true
 			}
 
 			nameFmt := "%s-client-%d"
@@ -208,6 +234,10 @@ func getExtraStringMaps(volinfo *volume.Volinfo) stringMapVolume {
 			clientIdx++
 		}
 
+		if subvolDecommissioned {
+			decommissionedBricks = append(decommissionedBricks, sv.Name)
+		}
+
 		data.Subvols[sidx].StringMap = map[string]string{
 			"subvol.afr-pending-xattr": strings.Join(afrPendingXattrs, ","),
 		}
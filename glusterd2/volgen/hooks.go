@@ -0,0 +1,149 @@
+package volgen
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+// GraphHook is implemented by plugins which modify the graphs of the
+// generated volfiles, for example to insert a caching xlator in the client
+// graph when a volume option is set.
+type GraphHook interface {
+	// Name returns the name of the hook, which is used in errors
+	Name() string
+	// Version returns the version of the hook. It must be changed
+	// whenever the hook changes the graphs it generates, so that the
+	// volfiles generated with the older version are regenerated.
+	Version() int
+	// Apply returns the xlators of the graph of the template for the
+	// volume, from the xlators of the template or of the hooks applied
+	// before. The volinfo is nil for cluster level templates. The xlators
+	// returned must be known to glusterd2, and are enabled and get their
+	// options like the xlators of the template.
+	Apply(tmpl *Template, volinfo *volume.Volinfo, xlators []Xlator) ([]Xlator, error)
+}
+
+var graphHooks []GraphHook
+
+// RegisterGraphHook registers a graph hook, which is applied to the graphs of
+// all the volfiles generated from then on. Hooks are applied in the order
+// they are registered.
+func RegisterGraphHook(h GraphHook) {
+	graphHooks = append(graphHooks, h)
+}
+
+// applyGraphHooks applies the registered graph hooks to the xlators of the
+// graph of the template
+func applyGraphHooks(tmpl *Template, volinfo *volume.Volinfo, xlators []Xlator) ([]Xlator, error) {
+	var err error
+	for _, h := range graphHooks {
+		// Hooks are given a copy so that they can't change the template
+		in := make([]Xlator, len(xlators))
+		copy(in, xlators)
+
+		xlators, err = h.Apply(tmpl, volinfo, in)
+		if err != nil {
+			return nil, fmt.Errorf("graph hook %s: %s", h.Name(), err)
+		}
+
+		for _, xl := range xlators {
+			if xl.Type == "" && xl.TypeTmpl == "" {
+				return nil, fmt.Errorf("graph hook %s: xlator without a type added to the %s graph", h.Name(), tmpl.Name)
+			}
+			if xl.Type == "" {
+				continue
+			}
+			if _, err := xlator.Find(xl.suffix()); err != nil {
+				return nil, fmt.Errorf("graph hook %s: %s", h.Name(), err)
+			}
+		}
+	}
+	return xlators, nil
+}
+
+// ValidateGraphHooks checks that the registered graph hooks can be applied to
+// the graphs of the volfiles of the volume, so that changes to the volume
+// which the hooks don't accept are refused instead of failing volfile
+// generation later.
+func ValidateGraphHooks(volinfo *volume.Volinfo) error {
+	if len(graphHooks) == 0 {
+		return nil
+	}
+
+	tmplNamespace, exists := volinfo.Metadata[TemplateMetadataKey]
+	if !exists {
+		tmplNamespace = DefaultTemplateNamespace
+	}
+
+	for _, tmpl := range namespaces[tmplNamespace] {
+		if tmpl.Level == VolfileLevelCluster {
+			continue
+		}
+		if _, err := tmpl.EnabledXlators(volinfo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GraphHooksVersion returns the names and versions of the registered graph
+// hooks, which changes whenever a hook is added, removed or upgraded
+func GraphHooksVersion() string {
+	var versions []string
+	for _, h := range graphHooks {
+		versions = append(versions, h.Name()+"="+strconv.Itoa(h.Version()))
+	}
+	sort.Strings(versions)
+	return strings.Join(versions, ",")
+}
+
+// RegenerateOnGraphHooksChange regenerates the volfiles of the local bricks if
+// the graph hooks changed since they were generated, for example after a
+// plugin was upgraded. Client volfiles are always generated when fetched and
+// need not be regenerated.
+func RegenerateOnGraphHooksChange() error {
+	versionFile := path.Join(config.GetString("localstatedir"), "volfiles", "graph-hooks.version")
+
+	saved, err := ioutil.ReadFile(versionFile)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	current := GraphHooksVersion()
+	if string(saved) == current {
+		return nil
+	}
+
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		return err
+	}
+
+	for _, v := range volumes {
+		bricks := v.GetLocalBricks()
+		if len(bricks) == 0 {
+			continue
+		}
+		if err := GenerateBricksVolfiles(v, bricks); err != nil {
+			return err
+		}
+		log.WithFields(log.Fields{
+			"volume":      v.Name,
+			"graph-hooks": current,
+		}).Info("regenerated brick volfiles after graph hooks changed")
+	}
+
+	return SaveToFile(versionFile, current)
+}
@@ -0,0 +1,77 @@
+package volgen
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/glusterd2/opversion"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// clusterOpVersion returns the op-version of the cluster, or 0 if it isn't
+// known, in which case no xlator or option is left out of the volfiles
+func clusterOpVersion() int {
+	v, err := opversion.Get()
+	if err != nil {
+		log.WithError(err).Warn("failed to get op-version of the cluster, generating volfile with all xlators")
+		return 0
+	}
+	return v
+}
+
+// ForOpVersion returns a copy of the template which generates volfiles for
+// components supporting at most the given op-version, like clients of an
+// older release. The op-version of the cluster is used instead if it is
+// lower.
+func (tmpl *Template) ForOpVersion(v int) *Template {
+	t := *tmpl
+	t.opVersion = v
+	if c := clusterOpVersion(); c != 0 && (v == 0 || c < v) {
+		t.opVersion = c
+	}
+	return &t
+}
+
+// forClusterOpVersion returns the template to generate volfiles with, which
+// is for the op-version of the cluster unless the template is already for a
+// given op-version
+func (tmpl *Template) forClusterOpVersion() *Template {
+	if tmpl.opVersion != 0 {
+		return tmpl
+	}
+	t := *tmpl
+	t.opVersion = clusterOpVersion()
+	return &t
+}
+
+// introducedIn returns the op-version a xlator or an option was introduced
+// in, from its list of op-versions
+func introducedIn(opVersions []uint32) int {
+	if len(opVersions) == 0 {
+		return 0
+	}
+	return int(opVersions[0])
+}
+
+// xlatorSupported returns true if the xlator can be included in the volfiles
+// generated for the op-version of the template. Xlators are left out if the
+// template requires a higher op-version for them, or if they were introduced
+// in a higher op-version.
+func (tmpl *Template) xlatorSupported(xl *Xlator) bool {
+	if tmpl.opVersion == 0 {
+		return true
+	}
+	if xl.MinOpVersion > tmpl.opVersion {
+		return false
+	}
+	if loaded, err := xlator.Find(xl.suffix()); err == nil && introducedIn(loaded.OpVersion) > tmpl.opVersion {
+		return false
+	}
+	return true
+}
+
+// optionSupported returns true if the option can be set in volfiles generated
+// for the op-version
+func optionSupported(o *options.Option, opVersion int) bool {
+	return opVersion == 0 || introducedIn(o.OpVersion) <= opVersion
+}
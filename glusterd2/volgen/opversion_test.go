@@ -0,0 +1,32 @@
+package volgen
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXlatorSupported(t *testing.T) {
+	xl := Xlator{Type: "performance/new-cache", MinOpVersion: 50100}
+
+	tmpl := Template{Name: "client"}
+	assert.True(t, tmpl.xlatorSupported(&xl))
+
+	tmpl.opVersion = 50000
+	assert.False(t, tmpl.xlatorSupported(&xl))
+
+	tmpl.opVersion = 50100
+	assert.True(t, tmpl.xlatorSupported(&xl))
+}
+
+func TestOptionSupported(t *testing.T) {
+	o := options.Option{Key: []string{"new-option"}, OpVersion: []uint32{50100, 0}}
+	assert.True(t, optionSupported(&o, 0))
+	assert.False(t, optionSupported(&o, 40100))
+	assert.True(t, optionSupported(&o, 50100))
+
+	o.OpVersion = nil
+	assert.True(t, optionSupported(&o, 40100))
+}
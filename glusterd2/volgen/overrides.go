@@ -0,0 +1,217 @@
+package volgen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	templateOverridesPrefix = "config/volfile-templates/"
+)
+
+func templateOverrideKey(vtype volume.VolType, name string) string {
+	return templateOverridesPrefix + vtype.String() + "/" + name
+}
+
+func fromAPIXlators(xls []api.VolfileXlator) []Xlator {
+	var out []Xlator
+	for _, xl := range xls {
+		out = append(out, Xlator{
+			NameTmpl:        xl.NameTmpl,
+			Type:            xl.Type,
+			TypeTmpl:        xl.TypeTmpl,
+			OnlyLocalBricks: xl.OnlyLocalBricks,
+			Disabled:        xl.Disabled,
+			EnableByOption:  xl.EnableByOption,
+			Options:         xl.Options,
+			IgnoreOptions:   xl.IgnoreOptions,
+		})
+	}
+	return out
+}
+
+// templateFromOverride returns the template defined by the override. The
+// level of the template is that of the built-in template it overrides.
+func templateFromOverride(o *api.VolfileTemplate) (*Template, error) {
+	builtin, err := GetTemplate(DefaultTemplateNamespace, o.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Template{
+		Name:               o.Name,
+		Level:              builtin.Level,
+		Xlators:            fromAPIXlators(o.Xlators),
+		SubvolGraphXlators: fromAPIXlators(o.SubvolGraphXlators),
+		BrickGraphXlators:  fromAPIXlators(o.BrickGraphXlators),
+	}, nil
+}
+
+func validateXlators(xls []Xlator, graph string) error {
+	for _, xl := range xls {
+		if xl.Type == "" && xl.TypeTmpl == "" {
+			return fmt.Errorf("xlator without a type in %s", graph)
+		}
+		if xl.Type == "" {
+			continue
+		}
+		if _, err := xlator.Find(xl.suffix()); err != nil {
+			return fmt.Errorf("%s in %s", err, graph)
+		}
+	}
+	return nil
+}
+
+// ValidateTemplateOverride checks that the template override can be used to
+// generate the volfiles of the volumes of its type. Only brick and volume
+// level templates can be overridden, and the xlators of the template must
+// be known to glusterd2. The volfiles of the existing volumes of the type are
+// generated from the template to find errors like unknown variables.
+func ValidateTemplateOverride(o *api.VolfileTemplate) error {
+	tmpl, err := templateFromOverride(o)
+	if err != nil {
+		return err
+	}
+
+	if tmpl.Level == VolfileLevelCluster {
+		return errors.New("only brick and volume level templates can be overridden")
+	}
+	if len(tmpl.Xlators) == 0 {
+		return errors.New("template must have at least one xlator")
+	}
+	if tmpl.Level == VolfileLevelBrick && (len(tmpl.SubvolGraphXlators) > 0 || len(tmpl.BrickGraphXlators) > 0) {
+		return errors.New("subvolume and brick graph xlators can only be set for volume level templates")
+	}
+
+	for graph, xls := range map[string][]Xlator{
+		"xlators":              tmpl.Xlators,
+		"subvol-graph-xlators": tmpl.SubvolGraphXlators,
+		"brick-graph-xlators":  tmpl.BrickGraphXlators,
+	} {
+		if err := validateXlators(xls, graph); err != nil {
+			return err
+		}
+	}
+
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		return err
+	}
+	for _, v := range volumes {
+		if v.Type != volume.VolType(o.VolType) {
+			continue
+		}
+		if _, exists := v.Metadata[TemplateMetadataKey]; exists {
+			continue
+		}
+
+		if tmpl.Level == VolfileLevelBrick {
+			bricks := v.GetBricks()
+			if len(bricks) == 0 {
+				continue
+			}
+			_, err = BrickLevelVolfile(tmpl, v, bricks[0].PeerID.String(), bricks[0].Path)
+		} else {
+			_, err = VolumeLevelVolfile(tmpl, v)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to generate volfile of volume %s: %s", v.Name, err)
+		}
+	}
+	return nil
+}
+
+// GetTemplateOverride returns the override of the template with the given
+// name for the volumes of the type
+func GetTemplateOverride(vtype volume.VolType, name string) (*api.VolfileTemplate, error) {
+	resp, err := store.Get(context.TODO(), templateOverrideKey(vtype, name))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Count != 1 {
+		return nil, gderrors.ErrVolfileTemplateNotFound
+	}
+
+	var o api.VolfileTemplate
+	if err := json.Unmarshal(resp.Kvs[0].Value, &o); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// GetTemplateOverrides returns all the template overrides
+func GetTemplateOverrides() ([]api.VolfileTemplate, error) {
+	resp, err := store.Get(context.TODO(), templateOverridesPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	overrides := make([]api.VolfileTemplate, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var o api.VolfileTemplate
+		if err := json.Unmarshal(kv.Value, &o); err != nil {
+			log.WithError(err).WithField("template", string(kv.Key)).Error("failed to unmarshal volfile template")
+			continue
+		}
+		overrides = append(overrides, o)
+	}
+	return overrides, nil
+}
+
+// SetTemplateOverride saves the template override, which should have been
+// validated with ValidateTemplateOverride
+func SetTemplateOverride(o *api.VolfileTemplate) error {
+	data, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(context.TODO(), templateOverrideKey(volume.VolType(o.VolType), o.Name), string(data))
+	return err
+}
+
+// DeleteTemplateOverride deletes the override of the template with the given
+// name for the volumes of the type
+func DeleteTemplateOverride(vtype volume.VolType, name string) error {
+	_, err := store.Delete(context.TODO(), templateOverrideKey(vtype, name))
+	return err
+}
+
+// templateOverride returns the template overriding the built-in template of
+// the given name for the volume, or nil if there is none. Overrides which
+// can't be used are logged and ignored, so that the built-in template is
+// used instead.
+func templateOverride(volinfo *volume.Volinfo, name string) *Template {
+	o, err := GetTemplateOverride(volinfo.Type, name)
+	if err == gderrors.ErrVolfileTemplateNotFound {
+		return nil
+	}
+
+	var tmpl *Template
+	if err == nil {
+		tmpl, err = templateFromOverride(o)
+	}
+	if err == nil && tmpl.Level == VolfileLevelCluster {
+		err = errors.New("cluster level templates can't be overridden")
+	}
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"volume":   volinfo.Name,
+			"template": name,
+		}).Warn("failed to get volfile template override, using the built-in template")
+		return nil
+	}
+	return tmpl
+}
@@ -49,6 +49,11 @@ type Template struct {
 	// volume volfiles. Brick level volfile will use Xlators itself
 	// to define the list instead of BrickGraphXlators
 	BrickGraphXlators []Xlator `json:"brick-graph-xlators"`
+
+	// opVersion is the op-version the volfiles are generated for, xlators
+	// and options introduced in a higher op-version are left out. It is 0
+	// if all the xlators and options are included.
+	opVersion int
 }
 
 // Xlator represents Xlator in Volfile template
@@ -82,6 +87,11 @@ type Xlator struct {
 	// generated Volfile. For example, bitd and scrubd both uses same xlator, if
 	// scrubber = on, then it becomes scrub daemon else it becomes bitd.
 	IgnoreOptions []string `json:"ignore-options"`
+	// MinOpVersion is the op-version required to include this xlator in
+	// the generated volfile, for xlators which the op-version of the
+	// xlator itself doesn't tell apart, like xlators whose use by older
+	// clients isn't supported
+	MinOpVersion int `json:"min-op-version,omitempty"`
 }
 
 // Templates represents collection of volfile templates
@@ -9,11 +9,14 @@ import (
 
 func (tmpl *Template) addEnabledXlator(volinfo *volume.Volinfo, xlist *[]Xlator, xltr Xlator) error {
 	var err error
+	if !tmpl.xlatorSupported(&xltr) {
+		return nil
+	}
 	xlatorEnabled := xltr.isEnabled(volinfo, tmpl.Name)
 	if xlatorEnabled || xltr.EnableByOption {
 		xl := xltr
 		xl.Options = make(map[string]string)
-		xl.Options, err = xltr.getOptions(tmpl.Name, volinfo)
+		xl.Options, err = xltr.getOptions(tmpl.Name, volinfo, tmpl.opVersion)
 		if err != nil {
 			return err
 		}
@@ -46,11 +49,15 @@ func GetTemplate(namespace string, name string) (*Template, error) {
 }
 
 // GetTemplateFromVolinfo gets template from the namespace set in volinfo
-// If template namespace is not set in volinfo, gets the template
+// If template namespace is not set in volinfo, gets the template overriding
+// the default one for the type of the volume, if any, or else the template
 // from default namespace
 func GetTemplateFromVolinfo(volinfo *volume.Volinfo, name string) (*Template, error) {
 	tmplNamespace, exists := volinfo.Metadata[TemplateMetadataKey]
 	if !exists {
+		if tmpl := templateOverride(volinfo, name); tmpl != nil {
+			return tmpl, nil
+		}
 		tmplNamespace = DefaultTemplateNamespace
 	}
 	return GetTemplate(tmplNamespace, name)
@@ -63,7 +70,12 @@ func (tmpl *Template) EnabledXlators(volinfo *volume.Volinfo) ([]Xlator, error)
 		err   error
 	)
 
-	for _, xl := range tmpl.Xlators {
+	xlators, err := applyGraphHooks(tmpl, volinfo, tmpl.Xlators)
+	if err != nil {
+		return []Xlator{}, err
+	}
+
+	for _, xl := range xlators {
 		err = tmpl.addEnabledXlator(volinfo, &xlist, xl)
 		if err != nil {
 			return []Xlator{}, err
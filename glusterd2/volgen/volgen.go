@@ -212,6 +212,7 @@ VolinfoLoop:
 	}
 
 	// Xlators list from template
+	tmpl = tmpl.forClusterOpVersion()
 	xlators, err := tmpl.EnabledXlators(volinfo)
 	if err != nil {
 		return "", err
@@ -346,6 +347,7 @@ func volumegraph(tmpl *Template, volinfo volume.Volinfo, entry *Entry, varStrDat
 // VolumeLevelVolfile generates volume level volfile
 func VolumeLevelVolfile(tmpl *Template, volinfo *volume.Volinfo) (string, error) {
 	// Xlators list from template
+	tmpl = tmpl.forClusterOpVersion()
 	xlators, err := tmpl.EnabledXlators(volinfo)
 	if err != nil {
 		return "", err
@@ -371,6 +373,7 @@ func VolumeLevelVolfile(tmpl *Template, volinfo *volume.Volinfo) (string, error)
 // ClusterLevelVolfile generates cluster level volfile
 func ClusterLevelVolfile(tmpl *Template, clusterinfo []*volume.Volinfo) (string, error) {
 	// Xlators list from template
+	tmpl = tmpl.forClusterOpVersion()
 	xlators, err := tmpl.EnabledXlators(nil)
 	if err != nil {
 		return "", err
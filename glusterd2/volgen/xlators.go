@@ -62,10 +62,14 @@ func (xl *Xlator) suffix() string {
 	return path.Base(xl.Type)
 }
 
-func (xl *Xlator) getOptions(tmplName string, volinfo *volume.Volinfo) (map[string]string, error) {
+// getOptions returns the options of the xlator in the volfile generated from
+// the template for the op-version. Options introduced in a higher op-version
+// are left out, even if set for the volume.
+func (xl *Xlator) getOptions(tmplName string, volinfo *volume.Volinfo, opVersion int) (map[string]string, error) {
 	var optKey, optVal string
 
 	opts := make(map[string]string)
+	unsupported := make(map[string]bool)
 	// Load default options
 	xlid := xl.suffix()
 	xlopts, err := xlator.Find(xlid)
@@ -84,6 +88,14 @@ func (xl *Xlator) getOptions(tmplName string, volinfo *volume.Volinfo) (map[stri
 			continue
 		}
 
+		if !optionSupported(o, opVersion) {
+			unsupported[optKey] = true
+			for _, k := range o.Key {
+				unsupported[k] = true
+			}
+			continue
+		}
+
 		optVal = o.DefaultValue
 
 		// If option set in template
@@ -139,7 +151,7 @@ func (xl *Xlator) getOptions(tmplName string, volinfo *volume.Volinfo) (map[stri
 	// then add that.(May be virtual option related to enable/disable)
 	for k, v := range xl.Options {
 		_, ok := opts[k]
-		if !ok {
+		if !ok && !unsupported[k] {
 			opts[k] = v
 		}
 	}
@@ -154,7 +166,7 @@ func (xl *Xlator) getOptions(tmplName string, volinfo *volume.Volinfo) (map[stri
 		if strings.HasPrefix(k, xlid+".") || strings.HasPrefix(k, xl.Type+".") {
 			parts := strings.Split(k, ".")
 			k = parts[len(parts)-1]
-			if !utils.StringInSlice(k, xl.IgnoreOptions) {
+			if !utils.StringInSlice(k, xl.IgnoreOptions) && !unsupported[k] {
 				opts[k] = v
 			}
 		}
@@ -166,7 +178,7 @@ func (xl *Xlator) getOptions(tmplName string, volinfo *volume.Volinfo) (map[stri
 		if strings.HasPrefix(k, tmplName+"."+xlid+".") || strings.HasPrefix(k, tmplName+"."+xl.Type+".") {
 			parts := strings.Split(k, ".")
 			k = parts[len(parts)-1]
-			if !utils.StringInSlice(k, xl.IgnoreOptions) {
+			if !utils.StringInSlice(k, xl.IgnoreOptions) && !unsupported[k] {
 				opts[k] = v
 			}
 		}
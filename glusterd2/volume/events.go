@@ -19,6 +19,20 @@ const (
 	EventVolumeStopped = "volume.stopped"
 	// EventVolumeDeleted represents Volume Delete event
 	EventVolumeDeleted = "volume.deleted"
+	// EventVolumeImported represents Volume Import event
+	EventVolumeImported = "volume.imported"
+	// EventVolumeOptionsSet represents Volume Option Set event
+	EventVolumeOptionsSet = "volume.options-set"
+	// EventVolumeOptionsReset represents Volume Option Reset event
+	EventVolumeOptionsReset = "volume.options-reset"
+	// EventBrickReplaced represents Brick Replace event
+	EventBrickReplaced = "volume.brick-replaced"
+	// EventRemoveBrickStarted represents Remove Brick Start event
+	EventRemoveBrickStarted = "volume.remove-brick-started"
+	// EventRemoveBrickCommitted represents Remove Brick Commit event
+	EventRemoveBrickCommitted = "volume.remove-brick-committed"
+	// EventRemoveBrickStopped represents Remove Brick Stop event
+	EventRemoveBrickStopped = "volume.remove-brick-stopped"
 )
 
 // NewEvent adds required details to event based on Volume info
@@ -28,5 +42,37 @@ func NewEvent(e Event, v *Volinfo) *api.Event {
 		"volume.id":   v.ID.String(),
 	}
 
-	return events.New(string(e), data, true)
+	return events.New(string(e), data, true, api.EventObject{
+		Kind: api.EventObjectVolume,
+		ID:   v.ID.String(),
+		Name: v.Name,
+	})
+}
+
+func init() {
+	types := []struct {
+		name        Event
+		description string
+	}{
+		{EventVolumeCreated, "A volume was created"},
+		{EventVolumeExpanded, "Bricks were added to a volume"},
+		{EventVolumeStarted, "A volume was started"},
+		{EventVolumeStopped, "A volume was stopped"},
+		{EventVolumeDeleted, "A volume was deleted"},
+		{EventVolumeImported, "A volume was imported from an export"},
+		{EventVolumeOptionsSet, "Options of a volume were set"},
+		{EventVolumeOptionsReset, "Options of a volume were reset"},
+		{EventBrickReplaced, "A brick of a volume was replaced"},
+		{EventRemoveBrickStarted, "Removal of bricks from a volume was started"},
+		{EventRemoveBrickCommitted, "Removal of bricks from a volume was committed"},
+		{EventRemoveBrickStopped, "Removal of bricks from a volume was stopped"},
+	}
+	for _, t := range types {
+		events.RegisterTypes(api.EventType{
+			Name:        string(t.name),
+			Description: t.description,
+			Objects:     []string{api.EventObjectVolume},
+			Data:        []string{"volume.name", "volume.id"},
+		})
+	}
 }
@@ -0,0 +1,148 @@
+package volume
+
+import (
+	"fmt"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// CreateVolumeExport returns the portable description of the volume which
+// can later be imported to recreate the volume
+func CreateVolumeExport(v *Volinfo) *api.VolumeExport {
+	export := &api.VolumeExport{
+		Version:               api.VolumeExportVersion,
+		ID:                    v.ID,
+		Name:                  v.Name,
+		Type:                  api.VolType(v.Type),
+		Transport:             v.Transport,
+		DistCount:             v.DistCount,
+		Options:               v.Options,
+		DefaultOptions:        v.DefaultOptions,
+		Metadata:              v.Metadata,
+		Labels:                v.Labels,
+		SnapshotReserveFactor: v.SnapshotReserveFactor,
+		Capacity:              v.Capacity,
+		ProvisionerType:       v.ProvisionerType,
+		CreatedAt:             v.CreatedAt,
+	}
+
+	for _, sv := range v.Subvols {
+		s := api.SubvolExport{
+			ID:              sv.ID,
+			Name:            sv.Name,
+			Type:            api.SubvolType(sv.Type),
+			ReplicaCount:    sv.ReplicaCount,
+			ArbiterCount:    sv.ArbiterCount,
+			DisperseCount:   sv.DisperseCount,
+			RedundancyCount: sv.RedundancyCount,
+		}
+		for _, b := range sv.Bricks {
+			s.Bricks = append(s.Bricks, api.BrickExport{
+				ID:             b.ID,
+				Path:           b.Path,
+				PeerID:         b.PeerID,
+				Hostname:       b.Hostname,
+				Type:           api.BrickType(b.Type),
+				ProvisionType:  string(b.PType),
+				BrickDirSuffix: b.BrickDirSuffix,
+				DevicePath:     b.DevicePath,
+				FsType:         b.FsType,
+				MntOpts:        b.MntOpts,
+				ThinPool:       b.TpName,
+				LvName:         b.LvName,
+				VgName:         b.VgName,
+				RootDevice:     b.RootDevice,
+				TotalSize:      b.TotalSize,
+				Decommissioned: b.Decommissioned,
+			})
+		}
+		export.Subvols = append(export.Subvols, s)
+	}
+
+	return export
+}
+
+// NewVolinfoFromExport recreates the volinfo described by a volume export. The
+// volume is always recreated in the created state.
+func NewVolinfoFromExport(e *api.VolumeImportReq) (*Volinfo, error) {
+	if e.Version != api.VolumeExportVersion {
+		return nil, fmt.Errorf("unsupported volume export version %d", e.Version)
+	}
+	if e.ID == nil || e.Name == "" || len(e.Subvols) == 0 {
+		return nil, fmt.Errorf("volume export is missing volume id, name or subvolumes")
+	}
+
+	v := &Volinfo{
+		ID:                    e.ID,
+		Name:                  e.Name,
+		VolfileID:             e.Name,
+		Type:                  VolType(e.Type),
+		Transport:             e.Transport,
+		DistCount:             e.DistCount,
+		State:                 VolCreated,
+		Options:               e.Options,
+		DefaultOptions:        e.DefaultOptions,
+		Metadata:              e.Metadata,
+		Labels:                e.Labels,
+		SnapList:              []string{},
+		SnapshotReserveFactor: e.SnapshotReserveFactor,
+		Capacity:              e.Capacity,
+		ProvisionerType:       e.ProvisionerType,
+		CreatedAt:             e.CreatedAt,
+	}
+	if v.Options == nil {
+		v.Options = make(map[string]string)
+	}
+	if v.Metadata == nil {
+		v.Metadata = make(map[string]string)
+	}
+	if v.Labels == nil {
+		v.Labels = make(map[string]string)
+	}
+
+	for _, es := range e.Subvols {
+		if len(es.Bricks) == 0 {
+			return nil, fmt.Errorf("subvolume %s has no bricks", es.Name)
+		}
+		s := Subvol{
+			ID:              es.ID,
+			Name:            es.Name,
+			Type:            SubvolType(es.Type),
+			ReplicaCount:    es.ReplicaCount,
+			ArbiterCount:    es.ArbiterCount,
+			DisperseCount:   es.DisperseCount,
+			RedundancyCount: es.RedundancyCount,
+		}
+		for _, eb := range es.Bricks {
+			s.Bricks = append(s.Bricks, brick.Brickinfo{
+				ID:             eb.ID,
+				Hostname:       eb.Hostname,
+				PeerID:         eb.PeerID,
+				Path:           eb.Path,
+				VolumeName:     v.Name,
+				VolfileID:      v.VolfileID,
+				VolumeID:       v.ID,
+				Type:           brick.Type(eb.Type),
+				Decommissioned: eb.Decommissioned,
+				PType:          brick.ProvisionType(eb.ProvisionType),
+				MountInfo: brick.MountInfo{
+					BrickDirSuffix: eb.BrickDirSuffix,
+					DevicePath:     eb.DevicePath,
+					FsType:         eb.FsType,
+					MntOpts:        eb.MntOpts,
+				},
+				DeviceInfo: brick.DeviceInfo{
+					TpName:     eb.ThinPool,
+					LvName:     eb.LvName,
+					VgName:     eb.VgName,
+					RootDevice: eb.RootDevice,
+					TotalSize:  eb.TotalSize,
+				},
+			})
+		}
+		v.Subvols = append(v.Subvols, s)
+	}
+
+	return v, nil
+}
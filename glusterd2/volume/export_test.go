@@ -0,0 +1,100 @@
+package volume
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleExportedVolinfo() *Volinfo {
+	v := &Volinfo{
+		ID:        uuid.NewRandom(),
+		Name:      "vol1",
+		VolfileID: "vol1",
+		Type:      Replicate,
+		Transport: "tcp",
+		DistCount: 1,
+		State:     VolStarted,
+		Options:   map[string]string{"cluster.self-heal-daemon": "on"},
+		Metadata:  map[string]string{"owner": "admin"},
+		Labels:    map[string]string{"env": "test"},
+		SnapList:  []string{},
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+
+	s := Subvol{ID: uuid.NewRandom(), Name: "vol1-replicate-0", Type: SubvolReplicate, ReplicaCount: 2}
+	for _, path := range []string{"/bricks/b1", "/bricks/b2"} {
+		s.Bricks = append(s.Bricks, brick.Brickinfo{
+			ID:         uuid.NewRandom(),
+			Hostname:   "host1",
+			PeerID:     uuid.NewRandom(),
+			Path:       path,
+			VolumeName: v.Name,
+			VolfileID:  v.VolfileID,
+			VolumeID:   v.ID,
+			Type:       brick.Brick,
+			MountInfo:  brick.MountInfo{FsType: "xfs"},
+		})
+	}
+	v.Subvols = []Subvol{s}
+	return v
+}
+
+// TestVolumeExportImport validates that a volume recreated from its export
+// is the same volume, in the created state
+func TestVolumeExportImport(t *testing.T) {
+	v := sampleExportedVolinfo()
+
+	export := CreateVolumeExport(v)
+	assert.Equal(t, api.VolumeExportVersion, export.Version)
+	assert.Len(t, export.Subvols, 1)
+	assert.Len(t, export.Subvols[0].Bricks, 2)
+
+	imported, err := NewVolinfoFromExport((*api.VolumeImportReq)(export))
+	assert.Nil(t, err)
+	assert.Equal(t, VolCreated, imported.State)
+
+	v.State = VolCreated
+	assert.Equal(t, v, imported)
+}
+
+func TestNewVolinfoFromExport(t *testing.T) {
+	valid := func() *api.VolumeImportReq {
+		return (*api.VolumeImportReq)(CreateVolumeExport(sampleExportedVolinfo()))
+	}
+
+	tests := []struct {
+		name   string
+		modify func(e *api.VolumeImportReq)
+		valid  bool
+	}{
+		{"valid", func(e *api.VolumeImportReq) {}, true},
+		{"unsupported version", func(e *api.VolumeImportReq) { e.Version = api.VolumeExportVersion + 1 }, false},
+		{"no version", func(e *api.VolumeImportReq) { e.Version = 0 }, false},
+		{"no id", func(e *api.VolumeImportReq) { e.ID = nil }, false},
+		{"no name", func(e *api.VolumeImportReq) { e.Name = "" }, false},
+		{"no subvolumes", func(e *api.VolumeImportReq) { e.Subvols = nil }, false},
+		{"subvolume without bricks", func(e *api.VolumeImportReq) { e.Subvols[0].Bricks = nil }, false},
+	}
+
+	for _, tt := range tests {
+		e := valid()
+		tt.modify(e)
+		_, err := NewVolinfoFromExport(e)
+		assert.Equal(t, tt.valid, err == nil, tt.name)
+	}
+
+	// Exports without options, metadata or labels get empty ones
+	e := valid()
+	e.Options, e.Metadata, e.Labels = nil, nil, nil
+	v, err := NewVolinfoFromExport(e)
+	assert.Nil(t, err)
+	assert.NotNil(t, v.Options)
+	assert.NotNil(t, v.Metadata)
+	assert.NotNil(t, v.Labels)
+}
@@ -0,0 +1,115 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	historyPrefix string = "volume-history/"
+
+	// maxHistoryEntries is the number of entries kept in the history of a
+	// volume, older entries are pruned as new ones are added
+	maxHistoryEntries = 1000
+)
+
+// HistoryEntry records a single state transition or configuration change
+// made to a volume, along with who requested it and in which transaction
+type HistoryEntry struct {
+	Time      time.Time
+	Event     Event
+	Requester string
+	ReqID     string
+	TxnID     string
+	Details   map[string]string
+}
+
+func historyKey(volname string) string {
+	return historyPrefix + volname + "/"
+}
+
+// AddHistoryEntry appends an entry to the history of the given volume
+func AddHistoryEntry(volname string, entry *HistoryEntry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// Zero padded timestamps keep the keys sorted in the order of the changes
+	key := fmt.Sprintf("%s%020d", historyKey(volname), entry.Time.UnixNano())
+	if _, err = store.Put(context.TODO(), key, string(data)); err != nil {
+		log.WithError(err).WithField("volume", volname).Error("Couldn't add volume history entry to store")
+		return err
+	}
+
+	if err := pruneHistory(volname); err != nil {
+		log.WithError(err).WithField("volume", volname).Warn("Couldn't prune volume history")
+	}
+	return nil
+}
+
+// historyPruneEnd returns the key up to which the entries of a history with
+// the given keys, oldest first, are pruned to keep at most max entries, or
+// an empty string if there is nothing to prune
+func historyPruneEnd(kvs []*mvccpb.KeyValue, max int) string {
+	if len(kvs) <= max {
+		return ""
+	}
+	return string(kvs[len(kvs)-max].Key)
+}
+
+// pruneHistory deletes the oldest entries of the history of the given volume
+// beyond maxHistoryEntries
+func pruneHistory(volname string) error {
+	resp, err := store.Get(context.TODO(), historyKey(volname), clientv3.WithPrefix(),
+		clientv3.WithKeysOnly(), clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return err
+	}
+
+	end := historyPruneEnd(resp.Kvs, maxHistoryEntries)
+	if end == "" {
+		return nil
+	}
+	_, err = store.Delete(context.TODO(), historyKey(volname), clientv3.WithRange(end))
+	return err
+}
+
+// GetHistory returns the recorded history of the given volume, oldest first
+func GetHistory(volname string) ([]*HistoryEntry, error) {
+	resp, err := store.Get(context.TODO(), historyKey(volname), clientv3.WithPrefix(),
+		clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*HistoryEntry, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var entry HistoryEntry
+		if err := json.Unmarshal(kv.Value, &entry); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("Failed to unmarshal volume history entry")
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
+
+// DeleteHistory removes all the recorded history of the given volume
+func DeleteHistory(volname string) error {
+	_, err := store.Delete(context.TODO(), historyKey(volname), clientv3.WithPrefix())
+	return err
+}
@@ -0,0 +1,21 @@
+package volume
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryPruneEnd(t *testing.T) {
+	var kvs []*mvccpb.KeyValue
+	for i := 0; i < 5; i++ {
+		kvs = append(kvs, &mvccpb.KeyValue{Key: []byte(fmt.Sprintf("%s%020d", historyKey("gv0"), i))})
+	}
+
+	assert.Equal(t, "", historyPruneEnd(kvs, 5))
+	assert.Equal(t, "", historyPruneEnd(kvs, 10))
+	// the two oldest entries are pruned, up to the third one
+	assert.Equal(t, string(kvs[2].Key), historyPruneEnd(kvs, 3))
+}
@@ -0,0 +1,119 @@
+package volume
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	maxLabelKeyLen   = 63
+	maxLabelValueLen = 63
+)
+
+var (
+	labelRegex = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._/-]*[A-Za-z0-9])?$`)
+
+	// ErrInvalidLabelSelector is returned when a label selector cannot be parsed
+	ErrInvalidLabelSelector = errors.New("invalid label selector")
+)
+
+// ValidateLabels checks if the label keys and values are well formed
+func ValidateLabels(labels map[string]string) error {
+	for k, v := range labels {
+		if len(k) > maxLabelKeyLen || !labelRegex.MatchString(k) {
+			return fmt.Errorf("invalid label key: %s", k)
+		}
+		if v == "" {
+			continue
+		}
+		if len(v) > maxLabelValueLen || !labelRegex.MatchString(v) {
+			return fmt.Errorf("invalid value for label %s: %s", k, v)
+		}
+	}
+	return nil
+}
+
+// labelRequirement is a single condition of a label selector
+type labelRequirement struct {
+	key      string
+	value    string
+	operator string
+}
+
+func (req labelRequirement) matches(labels map[string]string) bool {
+	value, exists := labels[req.key]
+	switch req.operator {
+	case "=":
+		return exists && value == req.value
+	case "!=":
+		return !exists || value != req.value
+	case "!":
+		return !exists
+	default:
+		return exists
+	}
+}
+
+// LabelSelector selects volumes based on their labels
+type LabelSelector []labelRequirement
+
+// ParseLabelSelector parses a comma separated list of label requirements.
+// Supported requirements are "key=value", "key!=value", "key" (label is
+// set) and "!key" (label is not set). All the requirements must be
+// satisfied for a volume to be selected.
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	var ls LabelSelector
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		var req labelRequirement
+		switch {
+		case strings.Contains(term, "!="):
+			parts := strings.SplitN(term, "!=", 2)
+			req = labelRequirement{key: parts[0], value: parts[1], operator: "!="}
+		case strings.Contains(term, "="):
+			parts := strings.SplitN(term, "=", 2)
+			req = labelRequirement{key: parts[0], value: parts[1], operator: "="}
+		case strings.HasPrefix(term, "!"):
+			req = labelRequirement{key: term[1:], operator: "!"}
+		default:
+			req = labelRequirement{key: term}
+		}
+
+		if !labelRegex.MatchString(req.key) {
+			return nil, ErrInvalidLabelSelector
+		}
+		ls = append(ls, req)
+	}
+	return ls, nil
+}
+
+// Matches returns true if the labels satisfy all the requirements of the
+// selector
+func (ls LabelSelector) Matches(labels map[string]string) bool {
+	for _, req := range ls {
+		if !req.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterByLabels returns a Filter which keeps only the volumes whose labels
+// match the selector
+func FilterByLabels(ls LabelSelector) Filter {
+	return func(volumes []*Volinfo) []*Volinfo {
+		var volInfos []*Volinfo
+		for _, volume := range volumes {
+			if ls.Matches(volume.Labels) {
+				volInfos = append(volInfos, volume)
+			}
+		}
+		return volInfos
+	}
+}
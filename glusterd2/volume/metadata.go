@@ -9,4 +9,8 @@ const (
 	BlockHosting = "block-hosting"
 	// BlockPrefix is the prefix of the volume metadata which will contain BlockPrefix + blockname as the key and size of the block as value.
 	BlockPrefix = "block-vol:"
+	// DeletionProtection is a volume metadata which when set to `yes` prevents the volume and its bricks from being deleted.
+	DeletionProtection = "deletion-protection"
+	// RemoveBrickRebalanceID is a volume metadata to store the ID of the rebalance migrating data off the bricks being removed.
+	RemoveBrickRebalanceID = "_remove-brick-rebalance-id"
 )
@@ -0,0 +1,48 @@
+package volume
+
+import (
+	"context"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// GetNamespace returns the namespace of the volume. Volumes created before
+// namespaces were introduced belong to the default namespace.
+func (v *Volinfo) GetNamespace() string {
+	if v.Namespace == "" {
+		return api.DefaultNamespace
+	}
+	return v.Namespace
+}
+
+// FilterByNamespace returns a Filter which keeps only the volumes of the
+// given namespace
+func FilterByNamespace(namespace string) Filter {
+	return func(volumes []*Volinfo) []*Volinfo {
+		var volInfos []*Volinfo
+		for _, volume := range volumes {
+			if volume.GetNamespace() == namespace {
+				volInfos = append(volInfos, volume)
+			}
+		}
+		return volInfos
+	}
+}
+
+// FilterByReqNamespaces returns a Filter which keeps only the volumes of the
+// namespaces the requester of ctx can access
+func FilterByReqNamespaces(ctx context.Context) Filter {
+	return func(volumes []*Volinfo) []*Volinfo {
+		if gdctx.GetReqNamespaces(ctx) == nil {
+			return volumes
+		}
+		var volInfos []*Volinfo
+		for _, volume := range volumes {
+			if gdctx.ReqNamespaceAllowed(ctx, volume.GetNamespace()) {
+				volInfos = append(volInfos, volume)
+			}
+		}
+		return volInfos
+	}
+}
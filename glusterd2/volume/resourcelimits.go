@@ -0,0 +1,36 @@
+package volume
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/daemon"
+	"github.com/gluster/glusterd2/pkg/cgroups"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+)
+
+// ResourceLimits are the limits on the resources used by the brick processes
+// of a volume on each peer. A limit which is zero is not enforced.
+type ResourceLimits struct {
+	// CPUQuota is the CPU time the brick processes may use, in percent of
+	// one CPU
+	CPUQuota int `json:"cpu-quota,omitempty"`
+	// MemoryMax is the memory, in bytes, the brick processes may use
+	MemoryMax uint64 `json:"memory-max,omitempty"`
+}
+
+// Validate checks if the limits are valid
+func (l *ResourceLimits) Validate() error {
+	if l.CPUQuota < 0 {
+		return gderrors.ErrResourceLimitsInvalid
+	}
+	return nil
+}
+
+// ApplyResourceLimits sets the resource limits of the volume on the cgroup
+// which its brick processes on this peer are run in. It does nothing if
+// glusterd2 doesn't run daemons in cgroups.
+func ApplyResourceLimits(v *Volinfo) error {
+	return daemon.SetCgroupLimits(brick.CgroupName(v.Name), cgroups.Limits{
+		CPUQuota:  v.ResourceLimits.CPUQuota,
+		MemoryMax: v.ResourceLimits.MemoryMax,
+	})
+}
@@ -0,0 +1,52 @@
+package volume
+
+import (
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+)
+
+// SnapshotLimits are the limits on the snapshots of a volume. A limit which
+// is zero is not enforced. Capacity limits are percentages of the data of the
+// thin pools of the bricks of the volume which are in use.
+type SnapshotLimits struct {
+	// MaxCount is the number of snapshots of the volume after which new
+	// snapshots are not taken
+	MaxCount int `json:"max-count,omitempty"`
+	// SoftMaxCount is the number of snapshots of the volume after which a
+	// warning is sent
+	SoftMaxCount int `json:"soft-max-count,omitempty"`
+	// CapacityHardLimit is the usage of the thin pools after which new
+	// snapshots are not taken
+	CapacityHardLimit float64 `json:"capacity-hard-limit,omitempty"`
+	// CapacitySoftLimit is the usage of the thin pools after which a
+	// warning is sent
+	CapacitySoftLimit float64 `json:"capacity-soft-limit,omitempty"`
+	// AutoDelete deletes the oldest snapshots of the volume, instead of
+	// refusing new ones, once a hard limit is reached
+	AutoDelete bool `json:"auto-delete,omitempty"`
+}
+
+// Validate checks if the limits are consistent
+func (l *SnapshotLimits) Validate() error {
+	if l.MaxCount < 0 || l.SoftMaxCount < 0 {
+		return gderrors.ErrSnapLimitsInvalid
+	}
+	if l.MaxCount != 0 && l.SoftMaxCount >= l.MaxCount {
+		return gderrors.ErrSnapLimitsInvalid
+	}
+
+	for _, limit := range []float64{l.CapacityHardLimit, l.CapacitySoftLimit} {
+		if limit < 0 || limit > 100 {
+			return gderrors.ErrSnapLimitsInvalid
+		}
+	}
+	if l.CapacityHardLimit != 0 && l.CapacitySoftLimit >= l.CapacityHardLimit {
+		return gderrors.ErrSnapLimitsInvalid
+	}
+	return nil
+}
+
+// CapacityLimited returns true if the snapshots of the volume are limited by
+// the usage of the thin pools
+func (l *SnapshotLimits) CapacityLimited() bool {
+	return l.CapacityHardLimit != 0 || l.CapacitySoftLimit != 0
+}
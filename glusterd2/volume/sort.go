@@ -0,0 +1,84 @@
+package volume
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// Keys by which a list of volumes can be sorted
+const (
+	SortByName    = "name"
+	SortBySize    = "size"
+	SortByCreated = "created"
+)
+
+// ErrInvalidSortKey is returned when volumes are sorted by an unknown key
+var ErrInvalidSortKey = errors.New("invalid sort key, supported keys: name, size, created")
+
+// FilterByState returns a Filter which keeps only the volumes in the given
+// state. The state is matched case insensitively against the state names
+// used in the REST API, for example "started".
+func FilterByState(state string) Filter {
+	return func(volumes []*Volinfo) []*Volinfo {
+		var volInfos []*Volinfo
+		for _, volume := range volumes {
+			if strings.EqualFold(api.VolState(volume.State).String(), state) {
+				volInfos = append(volInfos, volume)
+			}
+		}
+		return volInfos
+	}
+}
+
+// FilterByType returns a Filter which keeps only the volumes of the given
+// type. The type is matched case insensitively against the type names used
+// in the REST API, for example "distributed-replicate".
+func FilterByType(voltype string) Filter {
+	return func(volumes []*Volinfo) []*Volinfo {
+		var volInfos []*Volinfo
+		for _, volume := range volumes {
+			if strings.EqualFold(api.VolType(volume.Type).String(), voltype) {
+				volInfos = append(volInfos, volume)
+			}
+		}
+		return volInfos
+	}
+}
+
+// SortVolumes sorts the volumes in place by the given key. Volumes comparing
+// equal are ordered by name so that the order is stable across requests.
+func SortVolumes(volumes []*Volinfo, key string, descending bool) error {
+	var less func(a, b *Volinfo) bool
+
+	switch key {
+	case "", SortByName:
+		less = func(a, b *Volinfo) bool { return a.Name < b.Name }
+	case SortBySize:
+		less = func(a, b *Volinfo) bool {
+			if a.Capacity == b.Capacity {
+				return a.Name < b.Name
+			}
+			return a.Capacity < b.Capacity
+		}
+	case SortByCreated:
+		less = func(a, b *Volinfo) bool {
+			if a.CreatedAt.Equal(b.CreatedAt) {
+				return a.Name < b.Name
+			}
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+	default:
+		return ErrInvalidSortKey
+	}
+
+	sort.SliceStable(volumes, func(i, j int) bool {
+		if descending {
+			return less(volumes[j], volumes[i])
+		}
+		return less(volumes[i], volumes[j])
+	})
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/store"
 	gderror "github.com/gluster/glusterd2/pkg/errors"
 
+	"github.com/cespare/xxhash"
 	"github.com/coreos/etcd/clientv3"
 	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
@@ -16,6 +17,9 @@ import (
 
 const (
 	volumePrefix string = "volumes/"
+	// StorePrefix is the prefix of the keys of the volumes in the store,
+	// whose changes can be followed with store.WatchPrefix
+	StorePrefix = volumePrefix
 )
 
 // metadataFilter is a filter type
@@ -71,6 +75,29 @@ func GetVolume(name string) (*Volinfo, error) {
 	return &v, nil
 }
 
+// GetVolumeChecksum returns the version and checksum of the volinfo of the
+// volume as seen by this node. The version is the store revision in which the
+// volinfo was last modified.
+func GetVolumeChecksum(name string) (int64, uint64, error) {
+	resp, e := store.Get(context.TODO(), volumePrefix+name)
+	if e != nil {
+		log.WithError(e).Error("Couldn't retrive volume from store")
+		return 0, 0, e
+	}
+
+	if resp.Count != 1 {
+		return 0, 0, gderror.ErrVolNotFound
+	}
+
+	return resp.Kvs[0].ModRevision, xxhash.Sum64(resp.Kvs[0].Value), nil
+}
+
+// GetVolumeVersion returns a version of the volinfo of the volume, which
+// changes whenever the volume is changed
+func GetVolumeVersion(name string) (string, error) {
+	return store.PrefixVersion(context.TODO(), volumePrefix+name)
+}
+
 //DeleteVolume passes the volname to store to delete the volume object
 func DeleteVolume(name string) error {
 	_, e := store.Delete(context.TODO(), volumePrefix+name)
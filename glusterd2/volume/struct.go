@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
@@ -84,11 +85,13 @@ type Subvol struct {
 type Volinfo struct {
 	ID                    uuid.UUID
 	Name                  string
+	Namespace             string
 	VolfileID             string
 	Type                  VolType
 	Transport             string
 	DistCount             int
 	Options               map[string]string
+	DefaultOptions        []string
 	State                 VolState
 	Checksum              uint64
 	Version               uint64
@@ -96,10 +99,14 @@ type Volinfo struct {
 	Auth                  VolAuth
 	GraphMap              map[string]string
 	Metadata              map[string]string
+	Labels                map[string]string
 	SnapList              []string
+	SnapLimits            SnapshotLimits
+	ResourceLimits        ResourceLimits
 	SnapshotReserveFactor float64
 	Capacity              uint64
 	ProvisionerType       string
+	CreatedAt             time.Time
 }
 
 // VolAuth represents username and password used by trusted/internal clients
@@ -0,0 +1,118 @@
+package volume
+
+import (
+	"context"
+	"encoding/json"
+	"syscall"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/lvmutils"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	usagePrefix string = "volume-usage/"
+)
+
+// BrickUsage is the utilization of a brick as sampled by its peer
+type BrickUsage struct {
+	Path string
+	SizeInfo
+	// TpCapacity and TpUsed are set for bricks on an LVM thin pool
+	VgName     string
+	TpName     string
+	TpCapacity uint64
+	TpUsed     uint64
+}
+
+// PeerUsage is the utilization of the bricks of a volume on a peer, as
+// sampled by the peer at Time. VolumeID guards against samples of a deleted
+// volume being taken for those of a new volume with the same name.
+type PeerUsage struct {
+	VolumeID uuid.UUID
+	PeerID   uuid.UUID
+	Time     time.Time
+	Bricks   []BrickUsage
+}
+
+func usageKey(volname string) string {
+	return usagePrefix + volname + "/"
+}
+
+// SampleBrickUsage returns the current utilization of a local brick
+func SampleBrickUsage(b brick.Brickinfo) (BrickUsage, error) {
+	u := BrickUsage{
+		Path: b.Path,
+	}
+
+	var fstat syscall.Statfs_t
+	if err := syscall.Statfs(b.Path, &fstat); err != nil {
+		return u, err
+	}
+	u.SizeInfo = *(createSizeInfo(&fstat))
+
+	if b.VgName != "" && b.TpName != "" {
+		tpSize, tpUsed, err := lvmutils.GetThinpoolUsage(b.VgName, b.TpName)
+		if err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"brick":    b.Path,
+				"thinpool": b.VgName + "/" + b.TpName,
+			}).Warn("failed to get thin pool usage")
+		} else {
+			u.VgName = b.VgName
+			u.TpName = b.TpName
+			u.TpCapacity = tpSize
+			u.TpUsed = tpUsed
+		}
+	}
+
+	return u, nil
+}
+
+// SetPeerUsage saves the utilization of the bricks of the volume on a peer,
+// replacing the earlier sample of the peer
+func SetPeerUsage(volname string, u *PeerUsage) error {
+	if u.Time.IsZero() {
+		u.Time = time.Now()
+	}
+
+	data, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(context.TODO(), usageKey(volname)+u.PeerID.String(), string(data))
+	return err
+}
+
+// GetUsage returns the latest utilization samples of the volume from all
+// the peers having its bricks
+func GetUsage(volname string) ([]*PeerUsage, error) {
+	resp, err := store.Get(context.TODO(), usageKey(volname), clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	usages := make([]*PeerUsage, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var u PeerUsage
+		if err := json.Unmarshal(kv.Value, &u); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("Failed to unmarshal volume usage")
+			continue
+		}
+		usages = append(usages, &u)
+	}
+
+	return usages, nil
+}
+
+// DeleteUsage removes the utilization samples of the given volume
+func DeleteUsage(volname string) error {
+	_, err := store.Delete(context.TODO(), usageKey(volname), clientv3.WithPrefix())
+	return err
+}
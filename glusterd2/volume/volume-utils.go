@@ -14,6 +14,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/brick"
 	"github.com/gluster/glusterd2/glusterd2/daemon"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
 	"github.com/gluster/glusterd2/glusterd2/pmap"
 	"github.com/gluster/glusterd2/pkg/api"
 	gderrors "github.com/gluster/glusterd2/pkg/errors"
@@ -106,9 +107,26 @@ func BrickStatus(binfo brick.Brickinfo, mtabEntries []*Mntent) (brick.Brickstatu
 			s.Online = true
 			s.Pid = pidOnFile
 			s.Port, _ = pmap.RegistrySearch(binfo.Path)
+			// Bricks multiplexed onto the same process share its port
+			if n, err := pmap.GetNumOfBricksOnPort(s.Port); err == nil {
+				s.BricksInProc = n
+			}
+			if daemon.CgroupsEnabled() {
+				s.Cgroup = brickDaemon.Cgroup()
+				if usage, err := daemon.GetCgroupUsage(s.Cgroup); err == nil {
+					s.CgroupUsage = &usage
+				} else {
+					log.WithError(err).WithField("cgroup",
+						s.Cgroup).Debug("failed to read usage of cgroup")
+				}
+			}
 		}
 	}
 
+	if !s.Online {
+		s.Failed = daemon.HasFailed(brickDaemon)
+	}
+
 	var fstat syscall.Statfs_t
 	if err := syscall.Statfs(binfo.Path, &fstat); err != nil {
 		log.WithError(err).WithField("path",
@@ -212,17 +230,21 @@ func CreateSubvolInfo(sv *[]Subvol) []api.Subvol {
 func CreateVolumeInfoResp(v *Volinfo) *api.VolumeInfo {
 
 	resp := &api.VolumeInfo{
-		ID:        v.ID,
-		Name:      v.Name,
-		Type:      api.VolType(v.Type),
-		Transport: v.Transport,
-		DistCount: v.DistCount,
-		Capacity:  v.Capacity,
-		State:     api.VolState(v.State),
-		Options:   v.Options,
-		Subvols:   CreateSubvolInfo(&v.Subvols),
-		Metadata:  v.Metadata,
-		SnapList:  v.SnapList,
+		ID:             v.ID,
+		Name:           v.Name,
+		Namespace:      v.GetNamespace(),
+		Type:           api.VolType(v.Type),
+		Transport:      v.Transport,
+		DistCount:      v.DistCount,
+		Capacity:       v.Capacity,
+		State:          api.VolState(v.State),
+		Options:        v.Options,
+		DefaultOptions: v.DefaultOptions,
+		Subvols:        CreateSubvolInfo(&v.Subvols),
+		Metadata:       v.Metadata,
+		Labels:         v.Labels,
+		SnapList:       v.SnapList,
+		CreatedAt:      v.CreatedAt,
 	}
 
 	// for common use cases, replica count of the volume is usually the
@@ -247,6 +269,23 @@ func (v *Volinfo) IsAutoProvisioned() bool {
 	return (v.GetProvisionType().IsAutoProvisioned())
 }
 
+//IsDeletionProtected will return true if the volume has deletion protection enabled
+func (v *Volinfo) IsDeletionProtected() bool {
+	protected, err := options.StringToBoolean(v.Metadata[DeletionProtection])
+	return err == nil && protected
+}
+
+//DecommissionedBricks returns the bricks which are being removed from the volume
+func (v *Volinfo) DecommissionedBricks() []brick.Brickinfo {
+	var bricks []brick.Brickinfo
+	for _, b := range v.GetBricks() {
+		if b.Decommissioned {
+			bricks = append(bricks, b)
+		}
+	}
+	return bricks
+}
+
 //GetProvisionType will return true the type of provision state
 func (v *Volinfo) GetProvisionType() brick.ProvisionType {
 
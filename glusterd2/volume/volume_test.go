@@ -1,8 +1,10 @@
 package volume
 
 import (
+	"context"
 	"testing"
 
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/errors"
@@ -66,3 +68,102 @@ func TestNewBrickEntry(t *testing.T) {
 	assert.Equal(t, errors.ErrBrickPathConvertFail, err)
 
 }
+
+// TestSortVolumes validates SortVolumes() and FilterByState()
+func TestSortVolumes(t *testing.T) {
+	volumes := []*Volinfo{
+		{Name: "vol2", Capacity: 10, State: VolStarted},
+		{Name: "vol3", Capacity: 30, State: VolStopped},
+		{Name: "vol1", Capacity: 20, State: VolStarted},
+	}
+
+	assert.Nil(t, SortVolumes(volumes, SortByName, false))
+	assert.Equal(t, "vol1", volumes[0].Name)
+	assert.Equal(t, "vol3", volumes[2].Name)
+
+	assert.Nil(t, SortVolumes(volumes, SortBySize, true))
+	assert.Equal(t, "vol3", volumes[0].Name)
+	assert.Equal(t, "vol2", volumes[2].Name)
+
+	assert.Equal(t, ErrInvalidSortKey, SortVolumes(volumes, "bad", false))
+
+	started := FilterByState("started")(volumes)
+	assert.Len(t, started, 2)
+}
+
+// TestLabelSelector validates ParseLabelSelector() and LabelSelector.Matches()
+func TestLabelSelector(t *testing.T) {
+	labels := map[string]string{"tenant": "acme", "app": "db"}
+
+	ls, err := ParseLabelSelector("tenant=acme,app")
+	assert.Nil(t, err)
+	assert.True(t, ls.Matches(labels))
+
+	ls, err = ParseLabelSelector("tenant!=acme")
+	assert.Nil(t, err)
+	assert.False(t, ls.Matches(labels))
+
+	ls, err = ParseLabelSelector("!env")
+	assert.Nil(t, err)
+	assert.True(t, ls.Matches(labels))
+
+	_, err = ParseLabelSelector("=acme")
+	assert.Equal(t, ErrInvalidLabelSelector, err)
+
+	assert.Nil(t, ValidateLabels(labels))
+	assert.NotNil(t, ValidateLabels(map[string]string{"bad key": "x"}))
+}
+
+func TestSnapshotLimitsValidate(t *testing.T) {
+	l := SnapshotLimits{}
+	assert.Nil(t, l.Validate())
+	assert.False(t, l.CapacityLimited())
+
+	l = SnapshotLimits{MaxCount: 10, SoftMaxCount: 8, CapacityHardLimit: 90, CapacitySoftLimit: 80}
+	assert.Nil(t, l.Validate())
+	assert.True(t, l.CapacityLimited())
+
+	for _, l := range []SnapshotLimits{
+		{MaxCount: -1},
+		{MaxCount: 10, SoftMaxCount: 10},
+		{CapacityHardLimit: 101},
+		{CapacityHardLimit: 80, CapacitySoftLimit: 90},
+	} {
+		assert.Equal(t, errors.ErrSnapLimitsInvalid, l.Validate())
+	}
+}
+
+func TestIsDeletionProtected(t *testing.T) {
+	v := &Volinfo{Metadata: map[string]string{}}
+	assert.False(t, v.IsDeletionProtected())
+
+	v.Metadata[DeletionProtection] = "yes"
+	assert.True(t, v.IsDeletionProtected())
+
+	v.Metadata[DeletionProtection] = "off"
+	assert.False(t, v.IsDeletionProtected())
+
+	v.Metadata[DeletionProtection] = "bogus"
+	assert.False(t, v.IsDeletionProtected())
+}
+
+func TestNamespaceFilters(t *testing.T) {
+	volumes := []*Volinfo{
+		{Name: "vol1"},
+		{Name: "vol2", Namespace: "team1"},
+		{Name: "vol3", Namespace: "team2"},
+	}
+	assert.Equal(t, api.DefaultNamespace, volumes[0].GetNamespace())
+
+	team1 := FilterByNamespace("team1")(volumes)
+	assert.Len(t, team1, 1)
+	assert.Equal(t, "vol2", team1[0].Name)
+
+	assert.Len(t, FilterByReqNamespaces(context.Background())(volumes), 3)
+
+	ctx := gdctx.WithReqNamespaces(context.Background(), []string{"default", "team2"})
+	allowed := FilterByReqNamespaces(ctx)(volumes)
+	assert.Len(t, allowed, 2)
+	assert.Equal(t, "vol1", allowed[0].Name)
+	assert.Equal(t, "vol3", allowed[1].Name)
+}
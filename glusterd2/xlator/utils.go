@@ -3,6 +3,7 @@ package xlator
 import (
 	"fmt"
 	"path"
+	"strings"
 
 	"github.com/gluster/glusterd2/glusterd2/options"
 )
@@ -38,8 +39,19 @@ func (e OptionNotFoundError) Error() string {
 
 // FindOption returns an option.Option for the given key if found.
 // key should be in the [<graph>].<xlator>.<name> form.
-// Returns an error otherwise.
+// Returns an error otherwise. User options, like user.smb, are found whatever
+// their name and accept any value.
 func FindOption(k string) (*options.Option, error) {
+	if options.IsUserOption(k) {
+		return &options.Option{
+			Key:         []string{strings.TrimPrefix(k, options.UserOptionPrefix)},
+			Type:        options.OptionTypeAny,
+			Description: "User option",
+			Flags:       options.OptionFlagSettable,
+			Level:       options.OptionStatusBasic,
+		}, nil
+	}
+
 	// Interested only in <xlator>.<name> part of the key as optMap is indexed
 	// using them.
 	_, xl, name := options.SplitKey(k)
@@ -0,0 +1,30 @@
+package api
+
+import "time"
+
+// Outcomes of audited requests
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+	AuditDenied  = "denied"
+)
+
+// AuditRecord describes a mutating request served by a peer
+type AuditRecord struct {
+	Time       time.Time `json:"time"`
+	ReqID      string    `json:"req-id"`
+	PeerID     string    `json:"peer-id"`
+	User       string    `json:"user,omitempty"`
+	Role       string    `json:"role,omitempty"`
+	SourceIP   string    `json:"source-ip"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	BodyDigest string    `json:"body-digest,omitempty"`
+	TxnIDs     []string  `json:"txn-ids,omitempty"`
+	Status     int       `json:"status"`
+	Outcome    string    `json:"outcome"`
+}
+
+// AuditListResp is the response sent for a request to list the audit
+// records
+type AuditListResp []AuditRecord
@@ -0,0 +1,30 @@
+package api
+
+import "time"
+
+// CertInfo describes a certificate in use by a peer
+type CertInfo struct {
+	File          string    `json:"file"`
+	Subject       string    `json:"subject"`
+	Issuer        string    `json:"issuer"`
+	SerialNumber  string    `json:"serial-number"`
+	NotBefore     time.Time `json:"not-before"`
+	NotAfter      time.Time `json:"not-after"`
+	CAFile        string    `json:"ca-file,omitempty"`
+	VerifyClients bool      `json:"verify-clients"`
+}
+
+// CertificatesResp is the response sent for a request to get the
+// certificates in use by a peer. Server is the certificate of the REST and
+// SunRPC servers and Peer the one used between peers, each of them is only
+// set if TLS is enabled for it.
+type CertificatesResp struct {
+	PeerID string    `json:"peer-id"`
+	Server *CertInfo `json:"server,omitempty"`
+	Peer   *CertInfo `json:"peer,omitempty"`
+}
+
+// CertificatesReloadResp is the response sent for a request to reload the
+// certificates of all the peers. It has the certificates in use by the peer
+// which served the request.
+type CertificatesReloadResp CertificatesResp
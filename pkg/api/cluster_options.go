@@ -1,5 +1,9 @@
 package api
 
+import (
+	"github.com/pborman/uuid"
+)
+
 // ClusterOptionReq represents an incoming request to set cluster level options
 type ClusterOptionReq struct {
 	Options map[string]string `json:"options"`
@@ -11,4 +15,51 @@ type ClusterOptionsResp struct {
 	Value        string `json:"value"`
 	DefaultValue string `json:"default"`
 	Modified     bool   `json:"modified"`
+	Description  string `json:"description,omitempty"`
+}
+
+// ClusterOptionResetReq represents a request to reset cluster level options
+// to their default values
+type ClusterOptionResetReq struct {
+	Options []string `json:"options,omitempty"`
+	All     bool     `json:"all,omitempty"`
+}
+
+// ClusterOpVersionReq represents a request to raise the op-version of the
+// cluster. If OpVersion is not set, it is raised to the highest op-version
+// supported by all peers.
+type ClusterOpVersionReq struct {
+	OpVersion int `json:"op-version,omitempty"`
+}
+
+// PeerOpVersion is the op-version supported by a peer
+type PeerOpVersion struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	OpVersion int       `json:"op-version"`
 }
+
+// ClusterOpVersionResp contains the op-version of the cluster, and the
+// highest op-version it can be raised to
+type ClusterOpVersionResp struct {
+	OpVersion    int             `json:"op-version"`
+	MaxOpVersion int             `json:"max-op-version"`
+	Peers        []PeerOpVersion `json:"peers"`
+}
+
+// VolumeDefaultOptionsReq represents a request to set cluster wide default
+// volume options, which are applied to all the volumes created thereafter
+type VolumeDefaultOptionsReq struct {
+	Options map[string]string `json:"options"`
+	VolOptionFlags
+}
+
+// VolumeDefaultOptionsResetReq represents a request to remove cluster wide
+// default volume options
+type VolumeDefaultOptionsResetReq struct {
+	Options []string `json:"options,omitempty"`
+	All     bool     `json:"all,omitempty"`
+}
+
+// VolumeDefaultOptionsResp contains the cluster wide default volume options
+type VolumeDefaultOptionsResp map[string]string
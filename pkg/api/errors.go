@@ -5,6 +5,7 @@ package api
 type HTTPError struct {
 	Code    int               `json:"code"`
 	Message string            `json:"message"`
+	Field   string            `json:"field,omitempty"`
 	Fields  map[string]string `json:"fields,omitempty"`
 }
 
@@ -21,12 +22,23 @@ const (
 	ErrCodeGeneric ErrorCode = iota + 1
 	// ErrTxnStepFailed represents failure of a txn step
 	ErrTxnStepFailed
+	// ErrFieldRequired represents a request field which is required but
+	// not set
+	ErrFieldRequired
+	// ErrFieldInvalid represents a request field set to an invalid value
+	ErrFieldInvalid
+	// ErrFieldOutOfRange represents a request field set to a value, or
+	// with a length, out of the allowed range
+	ErrFieldOutOfRange
 )
 
 // ErrorCodeMap maps error code to it's textual message
 var ErrorCodeMap = map[ErrorCode]string{
-	ErrCodeGeneric:   "generic error",
-	ErrTxnStepFailed: "a txn step failed",
+	ErrCodeGeneric:     "generic error",
+	ErrTxnStepFailed:   "a txn step failed",
+	ErrFieldRequired:   "field is required",
+	ErrFieldInvalid:    "field is invalid",
+	ErrFieldOutOfRange: "field is out of range",
 }
 
 // ErrorResponse is an interface that types can implement on custom errors.
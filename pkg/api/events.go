@@ -6,12 +6,75 @@ import (
 	"github.com/pborman/uuid"
 )
 
+// EventSchemaVersion is the version of the schema of events. It is raised
+// when fields of events are changed or removed, not when fields are added.
+const EventSchemaVersion = 1
+
+// EventSeverity is how important an event is for alerting
+type EventSeverity string
+
+const (
+	// EventSeverityInfo is for events which need no action
+	EventSeverityInfo EventSeverity = "info"
+	// EventSeverityWarning is for events which may need an action
+	EventSeverityWarning EventSeverity = "warning"
+	// EventSeverityError is for failures which need an action
+	EventSeverityError EventSeverity = "error"
+	// EventSeverityCritical is for failures putting data or its
+	// availability at risk
+	EventSeverityCritical EventSeverity = "critical"
+)
+
+// Level returns the rank of the severity, from 0 for info to 3 for
+// critical, or -1 for an unknown severity
+func (s EventSeverity) Level() int {
+	switch s {
+	case EventSeverityInfo:
+		return 0
+	case EventSeverityWarning:
+		return 1
+	case EventSeverityError:
+		return 2
+	case EventSeverityCritical:
+		return 3
+	}
+	return -1
+}
+
+// Kinds of the objects events are about
+const (
+	EventObjectPeer           = "peer"
+	EventObjectVolume         = "volume"
+	EventObjectSnapshot       = "snapshot"
+	EventObjectSnapshotPolicy = "snapshot-policy"
+	EventObjectDaemon         = "daemon"
+	EventObjectDevice         = "device"
+	EventObjectGeorepSession  = "georep-session"
+)
+
+// EventObject is a reference to an object an event is about
+type EventObject struct {
+	// Kind is the kind of the object, like volume
+	Kind string `json:"kind"`
+	// ID is the ID of the object, if it has one
+	ID string `json:"id,omitempty"`
+	// Name is the name of the object, if it has one
+	Name string `json:"name,omitempty"`
+}
+
 // Event represents an event in GD2
 type Event struct {
 	// ID is a unique event ID
 	ID uuid.UUID `json:"id"`
-	// Name is the the name of the event
+	// Version is the version of the schema of the event, 0 for events
+	// created before events had a schema
+	Version int `json:"version"`
+	// Name is the the name of the event, which is the name of its type
 	Name string `json:"name"`
+	// Severity is the severity of the type of the event
+	Severity EventSeverity `json:"severity,omitempty"`
+	// Objects are the objects the event is about
+	Objects []EventObject `json:"objects,omitempty"`
 	// Data is any additional data attached to the event.
 	Data map[string]string `json:"data,omitempty"`
 	// global should be set to true to broadcast event to the full GD2 cluster.
@@ -23,3 +86,21 @@ type Event struct {
 	// Timestamp is the time when the event was created
 	Timestamp time.Time `json:"timestamp"`
 }
+
+// EventType describes a type of events. The names of event types are
+// stable: a type is not renamed, and its objects and data keys are only
+// added to.
+type EventType struct {
+	// Name is the name of the events of the type, its namespace is the
+	// part before the first dot, like volume for volume.started
+	Name        string        `json:"name"`
+	Severity    EventSeverity `json:"severity"`
+	Description string        `json:"description"`
+	// Objects are the kinds of the objects the events are about
+	Objects []string `json:"objects,omitempty"`
+	// Data are the keys of the data of the events
+	Data []string `json:"data,omitempty"`
+}
+
+// EventTypeList is the response sent for a request to list the event types
+type EventTypeList []EventType
@@ -0,0 +1,74 @@
+package api
+
+import "github.com/pborman/uuid"
+
+// States of the peers and volumes of a glusterd1 import
+const (
+	// Glusterd1PeerMember is a glusterd1 peer which is a peer of the cluster
+	Glusterd1PeerMember = "member"
+	// Glusterd1PeerAdded is a glusterd1 peer added to the cluster by the
+	// import
+	Glusterd1PeerAdded = "added"
+	// Glusterd1PeerMissing is a glusterd1 peer which is not a peer of the
+	// cluster
+	Glusterd1PeerMissing = "missing"
+	// Glusterd1VolumeImported is a glusterd1 volume imported into the
+	// cluster
+	Glusterd1VolumeImported = "imported"
+	// Glusterd1VolumeImportable is a glusterd1 volume which can be
+	// imported, reported by dry runs
+	Glusterd1VolumeImportable = "importable"
+	// Glusterd1VolumeExists is a glusterd1 volume which has already been
+	// imported
+	Glusterd1VolumeExists = "exists"
+	// Glusterd1ImportFailed is a glusterd1 peer or volume which could not
+	// be imported
+	Glusterd1ImportFailed = "failed"
+)
+
+// Glusterd1ImportReq is the request to import the peers and volumes of a
+// glusterd1 cluster from the working directory of glusterd1 on the peer
+// serving the request
+type Glusterd1ImportReq struct {
+	// Workdir defaults to /var/lib/glusterd
+	Workdir string `json:"workdir,omitempty"`
+	// AddPeers adds the glusterd1 peers which are not peers of the
+	// cluster. glusterd2 must be running on them.
+	AddPeers bool `json:"add-peers,omitempty"`
+	// DryRun only checks what would be imported
+	DryRun bool `json:"dry-run,omitempty"`
+}
+
+// Glusterd1PeerImport is the result of the import of a glusterd1 peer
+type Glusterd1PeerImport struct {
+	UUID      uuid.UUID `json:"uuid"`
+	Hostnames []string  `json:"hostnames"`
+	// PeerID is the ID of the peer in the cluster, if it is a member
+	PeerID uuid.UUID `json:"peer-id,omitempty"`
+	State  string    `json:"state"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// Glusterd1VolumeImport is the result of the import of a glusterd1 volume
+type Glusterd1VolumeImport struct {
+	Name string    `json:"name"`
+	ID   uuid.UUID `json:"id"`
+	// Started is set if the volume was started in glusterd1. Imported
+	// volumes are in the created state, so it has to be started again.
+	Started bool   `json:"started"`
+	State   string `json:"state"`
+	Error   string `json:"error,omitempty"`
+	// SkippedOptions are the options of the volume unknown to glusterd2,
+	// which are not imported
+	SkippedOptions []string `json:"skipped-options,omitempty"`
+}
+
+// Glusterd1ImportResp is the response sent for a glusterd1 import request
+type Glusterd1ImportResp struct {
+	// UUID is the glusterd1 UUID of the peer serving the request
+	UUID      uuid.UUID               `json:"uuid"`
+	OpVersion int                     `json:"op-version"`
+	DryRun    bool                    `json:"dry-run"`
+	Peers     []Glusterd1PeerImport   `json:"peers"`
+	Volumes   []Glusterd1VolumeImport `json:"volumes"`
+}
@@ -0,0 +1,78 @@
+package api
+
+import "github.com/pborman/uuid"
+
+// HealthStatus is the health of a peer or of one of its components
+type HealthStatus string
+
+const (
+	// HealthOK means everything is working
+	HealthOK HealthStatus = "ok"
+	// HealthDegraded means the peer is serving requests, but some of the
+	// bricks or peers need attention
+	HealthDegraded HealthStatus = "degraded"
+	// HealthFailed means the peer can't serve requests
+	HealthFailed HealthStatus = "failed"
+)
+
+// StoreHealth reports whether the store answers requests from the peer
+type StoreHealth struct {
+	Status    HealthStatus `json:"status"`
+	LatencyMs int64        `json:"latency-ms"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// QuorumHealth reports whether more than half of the peers are online
+type QuorumHealth struct {
+	Status HealthStatus `json:"status"`
+	Peers  int          `json:"peers"`
+	Online int          `json:"online"`
+}
+
+// BrickHealth reports whether the process of a brick is running
+type BrickHealth struct {
+	Volume string `json:"volume"`
+	Path   string `json:"path"`
+	Online bool   `json:"online"`
+}
+
+// BricksHealth reports the bricks of started volumes on the peer
+type BricksHealth struct {
+	Status HealthStatus  `json:"status"`
+	Bricks []BrickHealth `json:"bricks"`
+}
+
+// VolumeHealHealth reports the entries of a volume yet to be healed.
+// Pending is -1 if the count isn't known.
+type VolumeHealHealth struct {
+	Volume  string `json:"volume"`
+	Pending int64  `json:"pending"`
+	Error   string `json:"error,omitempty"`
+}
+
+// HealHealth reports the pending heals of the replicated and dispersed
+// volumes having bricks on the peer
+type HealHealth struct {
+	Status  HealthStatus       `json:"status"`
+	Volumes []VolumeHealHealth `json:"volumes"`
+}
+
+// NodeHealth is the health of a peer and its components. Error is set if
+// the health of the peer couldn't be checked.
+type NodeHealth struct {
+	PeerID uuid.UUID    `json:"peer-id"`
+	Status HealthStatus `json:"status"`
+	Store  StoreHealth  `json:"store"`
+	Quorum QuorumHealth `json:"quorum"`
+	Bricks BricksHealth `json:"bricks"`
+	Heal   HealHealth   `json:"heal"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// HealthResp is the response sent for a health check request. Nodes has
+// only the peer serving the request unless the cluster level was requested.
+type HealthResp struct {
+	Status HealthStatus `json:"status"`
+	Level  string       `json:"level"`
+	Nodes  []NodeHealth `json:"nodes"`
+}
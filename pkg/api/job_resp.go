@@ -0,0 +1,67 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// JobState is the state of a job
+type JobState string
+
+// States of a job
+const (
+	JobQueued  JobState = "queued"
+	JobRunning JobState = "running"
+	JobFailed  JobState = "failed"
+	JobDone    JobState = "done"
+)
+
+// Types of jobs
+const (
+	JobTypeRebalance       = "rebalance"
+	JobTypeHealFull        = "heal-full"
+	JobTypeBrickCleanup    = "brick-cleanup"
+	JobTypeSnapshotRestore = "snapshot-restore"
+	JobTypeSnapshotCreate  = "snapshot-create"
+	JobTypeVolumeCreate    = "volume-create"
+	JobTypeVolumeExpand    = "volume-expand"
+	JobTypeDeviceRemove    = "device-remove"
+	JobTypeDeviceDrain     = "device-drain"
+	JobTypePeerReplace     = "peer-replace"
+	JobTypePeerDetach      = "peer-detach"
+	// JobTypeHeketi is the type of the jobs serving the asynchronous
+	// requests of the Heketi compatible API
+	JobTypeHeketi = "heketi"
+)
+
+// JobLogEntry is a progress message logged by a job
+type JobLogEntry struct {
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+}
+
+// JobInfo contains information about a long running operation which is run
+// in the background by a peer
+type JobInfo struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      string          `json:"type"`
+	Volume    string          `json:"volume,omitempty"`
+	State     JobState        `json:"state"`
+	Error     string          `json:"error,omitempty"`
+	Initiator uuid.UUID       `json:"initiator"`
+	ReqID     uuid.UUID       `json:"req-id"`
+	Cancelled bool            `json:"cancelled,omitempty"`
+	CreatedAt time.Time       `json:"created-at"`
+	StartedAt time.Time       `json:"started-at,omitempty"`
+	EndedAt   time.Time       `json:"ended-at,omitempty"`
+	Logs      []JobLogEntry   `json:"logs,omitempty"`
+	Result    json.RawMessage `json:"result,omitempty"`
+}
+
+// JobListResp is the response sent for a job list request.
+type JobListResp []JobInfo
+
+// JobGetResp is the response sent for a job get request.
+type JobGetResp JobInfo
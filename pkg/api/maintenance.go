@@ -0,0 +1,20 @@
+package api
+
+import "time"
+
+// MaintenanceReq represents a request to put the cluster in maintenance
+// mode, in which requests changing the cluster are rejected, or to take it
+// out of maintenance mode
+type MaintenanceReq struct {
+	Enabled bool   `json:"enabled"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// MaintenanceResp is the response sent for a request to get or set the
+// maintenance mode of the cluster
+type MaintenanceResp struct {
+	Enabled bool      `json:"enabled"`
+	Reason  string    `json:"reason,omitempty"`
+	User    string    `json:"user,omitempty"`
+	Since   time.Time `json:"since,omitempty"`
+}
@@ -0,0 +1,5 @@
+package api
+
+// DefaultNamespace is the namespace of the volumes created without one, and
+// of the volumes created before namespaces were introduced
+const DefaultNamespace = "default"
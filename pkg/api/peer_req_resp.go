@@ -13,12 +13,18 @@ type Peer struct {
 	Online          bool              `json:"online"`
 	PID             int               `json:"pid,omitempty"`
 	Metadata        map[string]string `json:"metadata"`
+	Zone            string            `json:"zone"`
+	Rack            string            `json:"rack,omitempty"`
+	Tags            map[string]string `json:"tags,omitempty"`
+	OpVersion       int               `json:"op-version"`
 }
 
 // PeerAddReq represents an incoming request to add a peer to the cluster
 type PeerAddReq struct {
 	Addresses []string          `json:"addresses"`
 	Zone      string            `json:"zone,omitempty"`
+	Rack      string            `json:"rack,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
 	Metadata  map[string]string `json:"metadata,omitempty"`
 }
 
@@ -28,12 +34,55 @@ type PeerEditReq struct {
 	Metadata map[string]string `json:"metadata"`
 }
 
+// PeerPatchReq represents a request to change the placement attributes of a
+// peer. Only the fields which are set are changed. Tags are added to the tags
+// of the peer, and tags set to an empty value are removed.
+type PeerPatchReq struct {
+	Zone *string           `json:"zone,omitempty"`
+	Rack *string           `json:"rack,omitempty"`
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// PeerMaintenanceReq represents a request to put a peer under maintenance
+type PeerMaintenanceReq struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// PeerReplaceReq represents a request to replace a failed peer by another
+// peer. The peer taking its place is added to the cluster from Addresses, or
+// is the peer with PeerID if it is already part of the cluster.
+type PeerReplaceReq struct {
+	Addresses []string `json:"addresses,omitempty"`
+	Zone      string   `json:"zone,omitempty"`
+	PeerID    string   `json:"peer-id,omitempty"`
+	// Force skips the checks of the new bricks of volumes which are not
+	// auto provisioned
+	Force bool `json:"force,omitempty"`
+}
+
+// PeerReplaceBrick is the result of moving a brick of the failed peer to the
+// peer replacing it
+type PeerReplaceBrick struct {
+	Volume   string `json:"volume"`
+	OldBrick string `json:"old-brick"`
+	Error    string `json:"error,omitempty"`
+}
+
+// PeerReplaceResult is the result of the job replacing a peer
+type PeerReplaceResult struct {
+	PeerID uuid.UUID          `json:"peer-id"`
+	Bricks []PeerReplaceBrick `json:"bricks"`
+}
+
 // PeerAddResp is the success response sent to a PeerAddReq request
 type PeerAddResp Peer
 
 // PeerEditResp is the success response sent to a PeerEditReq request
 type PeerEditResp Peer
 
+// PeerPatchResp is the success response sent to a PeerPatchReq request
+type PeerPatchResp Peer
+
 // PeerGetResp is the response sent for a peer get request
 type PeerGetResp Peer
 
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Operations which can be scheduled
+const (
+	ScheduleOpVolumeStop     = "volume-stop"
+	ScheduleOpVolumeSet      = "volume-set"
+	ScheduleOpSnapshotCreate = "snapshot-create"
+)
+
+// ScheduleCreateReq represents a request to schedule an operation on a
+// volume, either once at a given time or repeatedly as per a cron
+// expression. Args is the request body of the operation, like VolOptionReq
+// for volume-set and SnapCreateReq for snapshot-create.
+type ScheduleCreateReq struct {
+	Name      string          `json:"name"`
+	Operation string          `json:"operation"`
+	Volume    string          `json:"volume"`
+	Args      json.RawMessage `json:"args,omitempty"`
+	At        time.Time       `json:"at,omitempty"`
+	Cron      string          `json:"cron,omitempty"`
+}
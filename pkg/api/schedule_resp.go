@@ -0,0 +1,32 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ScheduleInfo contains information about a scheduled operation. NextRun is
+// not set once a one time schedule has run.
+type ScheduleInfo struct {
+	Name       string          `json:"name"`
+	Operation  string          `json:"operation"`
+	Volume     string          `json:"volume"`
+	Args       json.RawMessage `json:"args,omitempty"`
+	At         time.Time       `json:"at,omitempty"`
+	Cron       string          `json:"cron,omitempty"`
+	CreatedBy  string          `json:"created-by,omitempty"`
+	CreatedAt  time.Time       `json:"created-at"`
+	NextRun    time.Time       `json:"next-run,omitempty"`
+	LastRun    time.Time       `json:"last-run,omitempty"`
+	LastStatus string          `json:"last-status,omitempty"`
+	LastError  string          `json:"last-error,omitempty"`
+}
+
+// ScheduleCreateResp is the response sent for a schedule create request.
+type ScheduleCreateResp ScheduleInfo
+
+// ScheduleGetResp is the response sent for a schedule get request.
+type ScheduleGetResp ScheduleInfo
+
+// ScheduleListResp is the response sent for a schedule list request.
+type ScheduleListResp []ScheduleInfo
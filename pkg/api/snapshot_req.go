@@ -9,12 +9,40 @@ type SnapCreateReq struct {
 	Force       bool   `json:"force,omitempty"`
 }
 
-//SnapActivateReq represents a request to activate a snapshot
+//SnapActivateReq represents a request to activate a snapshot. If TTL, in
+//seconds, is set the snapshot is deactivated once it expires.
 type SnapActivateReq struct {
 	Force bool `json:"force,omitempty"`
+	TTL   int  `json:"ttl,omitempty"`
 }
 
 //SnapCloneReq represents a request to clone a snapshot
 type SnapCloneReq struct {
 	CloneName string `json:"clonename"`
 }
+
+// SnapPolicyCreateReq represents a request to create a snapshot policy,
+// which takes snapshots of the selected volumes as per a cron expression and
+// keeps the latest Retention snapshots of each volume. Volumes are selected
+// by name, with shell patterns, and by labels. A volume is selected if its
+// name matches any of Volumes, if set, and its labels match LabelSelector,
+// if set.
+type SnapPolicyCreateReq struct {
+	Name          string   `json:"name"`
+	Cron          string   `json:"cron"`
+	Retention     int      `json:"retention"`
+	Volumes       []string `json:"volumes,omitempty"`
+	LabelSelector string   `json:"label-selector,omitempty"`
+	Description   string   `json:"description,omitempty"`
+}
+
+// SnapLimitsReq represents a request to change the snapshot limits of a
+// volume. Limits which are not set are left unchanged, and a limit of zero
+// is not enforced.
+type SnapLimitsReq struct {
+	MaxCount          *int     `json:"max-count,omitempty"`
+	SoftMaxCount      *int     `json:"soft-max-count,omitempty"`
+	CapacityHardLimit *float64 `json:"capacity-hard-limit,omitempty"`
+	CapacitySoftLimit *float64 `json:"capacity-soft-limit,omitempty"`
+	AutoDelete        *bool    `json:"auto-delete,omitempty"`
+}
@@ -13,6 +13,8 @@ type SnapInfo struct {
 	ParentVolName string     `json:"parentname"`
 	Description   string     `json:"description"`
 	CreatedAt     time.Time  `json:"created-at"`
+	Policy        string     `json:"policy,omitempty"`
+	ExpiresAt     time.Time  `json:"expires-at,omitempty"`
 }
 
 //SnapList contains snapshots information of a volume.
@@ -61,3 +63,43 @@ type SnapshotDeactivateResp SnapInfo
 // SnapshotCloneResp is the response sent for a snapshot clone request.
 // Snapshot clone will create a regular volume
 type SnapshotCloneResp VolumeInfo
+
+// SnapPolicyInfo contains information about a snapshot policy
+type SnapPolicyInfo struct {
+	Name          string    `json:"name"`
+	Cron          string    `json:"cron"`
+	Retention     int       `json:"retention"`
+	Volumes       []string  `json:"volumes,omitempty"`
+	LabelSelector string    `json:"label-selector,omitempty"`
+	Description   string    `json:"description,omitempty"`
+	CreatedBy     string    `json:"created-by,omitempty"`
+	CreatedAt     time.Time `json:"created-at"`
+	NextRun       time.Time `json:"next-run"`
+	LastRun       time.Time `json:"last-run,omitempty"`
+	LastStatus    string    `json:"last-status,omitempty"`
+	LastError     string    `json:"last-error,omitempty"`
+}
+
+// SnapPolicyCreateResp is the response sent for a snapshot policy create
+// request
+type SnapPolicyCreateResp SnapPolicyInfo
+
+// SnapPolicyGetResp is the response sent for a snapshot policy get request
+type SnapPolicyGetResp SnapPolicyInfo
+
+// SnapPolicyListResp is the response sent for a snapshot policy list request
+type SnapPolicyListResp []SnapPolicyInfo
+
+// SnapLimitsResp is the response sent for a request to get or change the
+// snapshot limits of a volume. CapacityUsed is the highest usage of the thin
+// pools of the bricks of the volume, in percent, if capacity limits are set.
+type SnapLimitsResp struct {
+	VolName           string  `json:"volume"`
+	MaxCount          int     `json:"max-count"`
+	SoftMaxCount      int     `json:"soft-max-count"`
+	CapacityHardLimit float64 `json:"capacity-hard-limit"`
+	CapacitySoftLimit float64 `json:"capacity-soft-limit"`
+	AutoDelete        bool    `json:"auto-delete"`
+	SnapCount         int     `json:"snap-count"`
+	CapacityUsed      float64 `json:"capacity-used,omitempty"`
+}
@@ -0,0 +1,84 @@
+package api
+
+// StoreMember is the status of a member of the embedded etcd cluster of the
+// store. The members are named after the ID of their peer. Healthy is false
+// and Error is set if the member didn't answer the status request.
+type StoreMember struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	PeerURLs   []string `json:"peer-urls"`
+	ClientURLs []string `json:"client-urls"`
+	Healthy    bool     `json:"healthy"`
+	Leader     bool     `json:"leader"`
+	Version    string   `json:"version,omitempty"`
+	DBSize     int64    `json:"db-size"`
+	RaftIndex  uint64   `json:"raft-index"`
+	RaftTerm   uint64   `json:"raft-term"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// StoreMembersResp is the response sent for a store members request
+type StoreMembersResp []StoreMember
+
+// StoreDefragReq is the request to defragment the database of members of the
+// store, all the members if none are given
+type StoreDefragReq struct {
+	Members []string `json:"members,omitempty"`
+}
+
+// StoreDefragResult is the result of the defragmentation of a member of the
+// store
+type StoreDefragResult struct {
+	Name         string `json:"name"`
+	DBSizeBefore int64  `json:"db-size-before"`
+	DBSizeAfter  int64  `json:"db-size-after"`
+	Error        string `json:"error,omitempty"`
+}
+
+// StoreDefragResp is the response sent for a store defragmentation request
+type StoreDefragResp []StoreDefragResult
+
+// StoreCompactReq is the request to compact the history of the store up to
+// a revision, the current revision if it is not given
+type StoreCompactReq struct {
+	Revision int64 `json:"revision,omitempty"`
+}
+
+// StoreCompactResp is the response sent for a store compaction request
+type StoreCompactResp struct {
+	Revision int64 `json:"revision"`
+}
+
+// StoreAlarm is an alarm raised by a member of the store, like NOSPACE when
+// its database has reached its quota
+type StoreAlarm struct {
+	MemberID string `json:"member-id"`
+	Name     string `json:"name"`
+	Alarm    string `json:"alarm"`
+}
+
+// StoreAlarmsResp is the response sent for store alarm list and clear
+// requests. It has the alarms which are raised, or which have been cleared.
+type StoreAlarmsResp []StoreAlarm
+
+// StoreLeaderReq is the request to transfer the leadership of the store to
+// one of its members
+type StoreLeaderReq struct {
+	Member string `json:"member"`
+}
+
+// StoreLeaderResp is the response sent for a store leadership transfer
+// request
+type StoreLeaderResp struct {
+	Leader string `json:"leader"`
+}
+
+// StoreConfigResp is the configuration of the external etcd cluster used by
+// the peer serving the request, sent for a store reload request
+type StoreConfigResp struct {
+	PeerID         string   `json:"peer-id"`
+	Endpoints      []string `json:"endpoints"`
+	UseTLS         bool     `json:"use-tls"`
+	ClientCertFile string   `json:"client-cert-file,omitempty"`
+	ClientCAFile   string   `json:"client-ca-file,omitempty"`
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// TxnNodeStatus is the status of a transaction step on a peer
+type TxnNodeStatus struct {
+	PeerID uuid.UUID `json:"peer-id"`
+	State  string    `json:"state"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// TxnStepInfo is the progress of a single transaction step
+type TxnStepInfo struct {
+	Name      string          `json:"name"`
+	State     string          `json:"state"`
+	StartTime time.Time       `json:"start-time,omitempty"`
+	EndTime   time.Time       `json:"end-time,omitempty"`
+	Nodes     []TxnNodeStatus `json:"nodes"`
+}
+
+// TxnInfo contains the progress of a transaction which is running or has
+// recently ended
+type TxnInfo struct {
+	ID        uuid.UUID     `json:"id"`
+	ReqID     uuid.UUID     `json:"req-id"`
+	Initiator uuid.UUID     `json:"initiator"`
+	Locks     []string      `json:"locks,omitempty"`
+	State     string        `json:"state"`
+	Error     string        `json:"error,omitempty"`
+	StartTime time.Time     `json:"start-time"`
+	EndTime   time.Time     `json:"end-time,omitempty"`
+	Steps     []TxnStepInfo `json:"steps"`
+}
+
+// TxnListResp is the response sent for a transaction list request.
+type TxnListResp []TxnInfo
+
+// TxnGetResp is the response sent for a transaction get request.
+type TxnGetResp TxnInfo
+
+// LockInfo is a cluster wide lock which is currently held. The transaction
+// holding the lock is not known for locks taken outside of transactions.
+type LockInfo struct {
+	Name       string    `json:"name"`
+	TxnID      uuid.UUID `json:"txn-id,omitempty"`
+	PeerID     uuid.UUID `json:"peer-id,omitempty"`
+	AcquiredAt time.Time `json:"acquired-at,omitempty"`
+	Age        string    `json:"age,omitempty"`
+	Waiters    int       `json:"waiters"`
+}
+
+// LockListResp is the response sent for a lock list request.
+type LockListResp []LockInfo
@@ -0,0 +1,29 @@
+package api
+
+// Roles of users of the REST API. A viewer can only make GET requests, an
+// operator can also make requests which change resources but not delete
+// them, and an admin can make any request.
+const (
+	RoleAdmin    = "admin"
+	RoleOperator = "operator"
+	RoleViewer   = "viewer"
+)
+
+// UserCreateReq represents a request to add a user of the REST API. A secret
+// is generated for the user if one is not given. A user with no namespaces
+// can access the volumes of all the namespaces.
+type UserCreateReq struct {
+	Name       string   `json:"name"`
+	Role       string   `json:"role"`
+	Secret     string   `json:"secret,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// UserEditReq represents a request to change the role, the secret or the
+// namespaces of a user. Fields which are not given are left unchanged, an
+// empty list of namespaces gives the user access to all the namespaces.
+type UserEditReq struct {
+	Role       string    `json:"role,omitempty"`
+	Secret     string    `json:"secret,omitempty"`
+	Namespaces *[]string `json:"namespaces,omitempty"`
+}
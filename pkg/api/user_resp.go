@@ -0,0 +1,38 @@
+package api
+
+import "time"
+
+// UserInfo represents a user of the REST API. The secret of a user is only
+// returned when the user is added.
+type UserInfo struct {
+	Name       string    `json:"name"`
+	Role       string    `json:"role"`
+	Namespaces []string  `json:"namespaces,omitempty"`
+	CreatedAt  time.Time `json:"created-at"`
+	UpdatedAt  time.Time `json:"updated-at"`
+}
+
+// UserCreateResp is the response sent for a user create request. Secret is
+// the secret with which the user signs its tokens.
+type UserCreateResp struct {
+	UserInfo
+	Secret string `json:"secret"`
+}
+
+// UserGetResp is the response sent for a user get request
+type UserGetResp UserInfo
+
+// UserEditResp is the response sent for a user edit request
+type UserEditResp UserInfo
+
+// UserListResp is the response sent for a user list request
+type UserListResp []UserInfo
+
+// RoleInfo describes a role which can be given to users
+type RoleInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// RoleListResp is the response sent for a role list request
+type RoleListResp []RoleInfo
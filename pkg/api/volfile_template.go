@@ -0,0 +1,35 @@
+package api
+
+// VolfileXlator is an xlator of a volfile template. See the Xlator type of
+// the volgen package for the meaning of the fields.
+type VolfileXlator struct {
+	NameTmpl        string            `json:"name-tmpl,omitempty"`
+	Type            string            `json:"type,omitempty"`
+	TypeTmpl        string            `json:"type-tmpl,omitempty"`
+	OnlyLocalBricks bool              `json:"only-local-bricks,omitempty"`
+	Disabled        bool              `json:"disabled,omitempty"`
+	EnableByOption  bool              `json:"enable-by-option,omitempty"`
+	Options         map[string]string `json:"options,omitempty"`
+	IgnoreOptions   []string          `json:"ignore-options,omitempty"`
+}
+
+// VolfileTemplateReq is the request to override a built-in volfile template
+// for the volumes of a type. SubvolGraphXlators and BrickGraphXlators can
+// only be set for volume level templates like client.
+type VolfileTemplateReq struct {
+	Xlators            []VolfileXlator `json:"xlators"`
+	SubvolGraphXlators []VolfileXlator `json:"subvol-graph-xlators,omitempty"`
+	BrickGraphXlators  []VolfileXlator `json:"brick-graph-xlators,omitempty"`
+}
+
+// VolfileTemplate is a volfile template which overrides the built-in
+// template of the same name for the volumes of a type
+type VolfileTemplate struct {
+	VolfileTemplateReq
+	Name    string  `json:"name"`
+	VolType VolType `json:"volume-type"`
+}
+
+// VolfileTemplateListResp is the response sent for a volfile template list
+// request
+type VolfileTemplateListResp []VolfileTemplate
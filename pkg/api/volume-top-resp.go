@@ -0,0 +1,24 @@
+package api
+
+// TopEntry is a file or directory with its count for a top metric
+type TopEntry struct {
+	Name  string `json:"name"`
+	Count uint64 `json:"count"`
+}
+
+// BrickTop contains the top entries of a brick for a metric
+type BrickTop struct {
+	Brick BrickInfo `json:"brick"`
+	// Open fd counts, reported for the open metric only
+	CurrentOpenFds uint64     `json:"current-open-fds,omitempty"`
+	MaxOpenFds     uint64     `json:"max-open-fds,omitempty"`
+	MaxOpenFdsTime string     `json:"max-open-fds-time,omitempty"`
+	Entries        []TopEntry `json:"entries"`
+}
+
+// VolumeTopResp is the response sent for a volume top request
+type VolumeTopResp struct {
+	Volume string     `json:"volume"`
+	Metric string     `json:"metric"`
+	Bricks []BrickTop `json:"bricks"`
+}
@@ -3,7 +3,7 @@ package api
 // BrickReq represents Brick Request
 type BrickReq struct {
 	Type           string `json:"type"`
-	PeerID         string `json:"peerid"`
+	PeerID         string `json:"peerid" valid:"uuid"`
 	Path           string `json:"path"`
 	TpMetadataSize uint64 `json:"metadata-size,omitempty"`
 	TpSize         uint64 `json:"thinpool-size,omitempty"`
@@ -25,8 +25,8 @@ type SubvolReq struct {
 	Type               string      `json:"type"`
 	Bricks             []BrickReq  `json:"bricks"`
 	Subvols            []SubvolReq `json:"subvols"`
-	ReplicaCount       int         `json:"replica"`
-	ArbiterCount       int         `json:"arbiter"`
+	ReplicaCount       int         `json:"replica" valid:"range(0|3)"`
+	ArbiterCount       int         `json:"arbiter" valid:"range(0|1)"`
 	DisperseCount      int         `json:"disperse-count,omitempty"`
 	DisperseData       int         `json:"disperse-data,omitempty"`
 	DisperseRedundancy int         `json:"disperse-redundancy,omitempty"`
@@ -40,17 +40,19 @@ type SubvolReq struct {
 "create-brick-dir" : if brick dir is not present, create it
 */
 type VolCreateReq struct {
-	Name                    string            `json:"name"`
-	Transport               string            `json:"transport,omitempty"`
+	Name                    string            `json:"name" valid:"required"`
+	Namespace               string            `json:"namespace,omitempty"`
+	Transport               string            `json:"transport,omitempty" valid:"in(tcp|rdma)"`
 	Subvols                 []SubvolReq       `json:"subvols"`
 	Force                   bool              `json:"force,omitempty"`
 	Metadata                map[string]string `json:"metadata,omitempty"`
+	Labels                  map[string]string `json:"labels,omitempty"`
 	Flags                   map[string]bool   `json:"flags,omitempty"`
 	Size                    uint64            `json:"size"`
 	MaxBrickSize            uint64            `json:"max-brick-size,omitempty"`
 	DistributeCount         int               `json:"distribute,omitempty"`
-	ReplicaCount            int               `json:"replica,omitempty"`
-	ArbiterCount            int               `json:"arbiter,omitempty"`
+	ReplicaCount            int               `json:"replica,omitempty" valid:"range(0|3)"`
+	ArbiterCount            int               `json:"arbiter,omitempty" valid:"range(0|1)"`
 	AverageFileSize         uint64            `json:"average-file-size,omitempty"`
 	DisperseCount           int               `json:"disperse,omitempty"`
 	DisperseRedundancyCount int               `json:"disperse-redundancy,omitempty"`
@@ -64,9 +66,17 @@ type VolCreateReq struct {
 	SubvolZonesOverlap      bool              `json:"subvolume-zones-overlap,omitempty"`
 	SubvolType              string            `json:"subvolume-type,omitempty"`
 	ProvisionerType         string            `json:"provisioner"`
+	Profile                 string            `json:"profile,omitempty"`
+	StartVolume             bool              `json:"start-volume,omitempty"`
 	VolOptionReq
 }
 
+// VolBulkCreateReq represents a request to create multiple volumes at once
+type VolBulkCreateReq []VolCreateReq
+
+// VolBulkDeleteReq represents a request to delete multiple volumes at once
+type VolBulkDeleteReq []string
+
 // VolOptionFlags is set of flags that allow/disallow setting certain kinds
 // of volume options.
 type VolOptionFlags struct {
@@ -81,9 +91,20 @@ type VolOptionReq struct {
 	VolOptionFlags
 }
 
+// VolumeImportReq represents a request to recreate a volume from an export
+type VolumeImportReq VolumeExport
+
+// VolOptionGroupReq represents a request to apply or unapply an option group
+// on a volume
+type VolOptionGroupReq struct {
+	Unapply bool `json:"unapply,omitempty"`
+	VolOptionFlags
+}
+
 // VolOptionResetReq represents a request to reset volume options
 type VolOptionResetReq struct {
 	Options []string `json:"options,omitempty"`
+	Group   string   `json:"group,omitempty"`
 	Force   bool     `json:"force,omitempty"`
 	All     bool     `json:"all,omitempty"`
 }
@@ -96,7 +117,7 @@ type VolOptionResetReq struct {
 "create-brick-dir" : if brick dir is not present, create it
 */
 type VolExpandReq struct {
-	ReplicaCount    int             `json:"replica,omitempty"`
+	ReplicaCount    int             `json:"replica,omitempty" valid:"range(0|3)"`
 	Bricks          []BrickReq      `json:"bricks,omitempty"`
 	Force           bool            `json:"force,omitempty"`
 	Flags           map[string]bool `json:"flags,omitempty"`
@@ -123,6 +144,27 @@ type OptionGroupReq struct {
 	VolOptionFlags
 }
 
+// VolumeProfile is a named set of volume create defaults stored in the
+// cluster. Volume create requests can refer to a profile by name, fields set
+// explicitly in the request take precedence over the ones in the profile.
+type VolumeProfile struct {
+	Name                  string            `json:"name"`
+	Description           string            `json:"description,omitempty"`
+	Options               map[string]string `json:"options,omitempty"`
+	SubvolType            string            `json:"subvolume-type,omitempty"`
+	SnapshotReserveFactor float64           `json:"snapshot-reserve-factor,omitempty"`
+	LimitPeers            []string          `json:"limit-peers,omitempty"`
+	LimitZones            []string          `json:"limit-zones,omitempty"`
+	ExcludePeers          []string          `json:"exclude-peers,omitempty"`
+	ExcludeZones          []string          `json:"exclude-zones,omitempty"`
+}
+
+// VolumeProfileReq represents a request to create a volume profile
+type VolumeProfileReq struct {
+	VolumeProfile
+	VolOptionFlags
+}
+
 // ClientStatedump uniquely identifies a client (only gfapi) connected to
 // glusterd2
 type ClientStatedump struct {
@@ -144,6 +186,28 @@ type VolEditReq struct {
 	DeleteMetadata bool              `json:"delete-metadata"`
 }
 
+// VolDeletionProtectionReq represents a request to enable or disable the
+// deletion protection of a volume
+type VolDeletionProtectionReq struct {
+	Enabled bool `json:"enabled"`
+}
+
+// VolResourceLimitsReq represents a request to change the limits on the
+// resources used by the brick processes of a volume on each peer. Limits
+// which are not set are left unchanged, and a limit of zero is not enforced.
+type VolResourceLimitsReq struct {
+	// CPUQuota is in percent of one CPU
+	CPUQuota *int `json:"cpu-quota,omitempty"`
+	// MemoryMax is in bytes
+	MemoryMax *uint64 `json:"memory-max,omitempty"`
+}
+
+// VolLabelsReq represents a request to update the labels of a volume
+type VolLabelsReq struct {
+	Set    map[string]string `json:"set,omitempty"`
+	Remove []string          `json:"remove,omitempty"`
+}
+
 // ReplaceBrickReq represents replace brick request
 type ReplaceBrickReq struct {
 	SrcPeerID          string          `json:"src-peerid"`
@@ -155,6 +219,22 @@ type ReplaceBrickReq struct {
 	SubvolZonesOverlap bool            `json:"subvolume-zones-overlap,omitempty"`
 	Force              bool            `json:"force,omitempty"`
 	Flags              map[string]bool `json:"flags,omitempty"`
+	// NewBrick is the replacement brick for volumes which are not auto
+	// provisioned. It is chosen by the bricks planner otherwise.
+	NewBrick *BrickReq `json:"new-brick,omitempty"`
+}
+
+// RemoveBrickReq represents the request to start removing bricks from a
+// volume. Bricks of replicate and disperse volumes can only be removed as
+// whole subvolumes.
+type RemoveBrickReq struct {
+	Bricks []BrickReq `json:"bricks"`
+}
+
+// RemoveBrickCommitReq represents the request to commit the removal of the
+// bricks. Force commits even if data migration did not complete.
+type RemoveBrickCommitReq struct {
+	Force bool `json:"force,omitempty"`
 }
 
 // VolumeStartReq represents a request to start volume
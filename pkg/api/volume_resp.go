@@ -1,6 +1,10 @@
 package api
 
-import "github.com/pborman/uuid"
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
 
 const (
 	// ProvisionerTypeLoop represents loop device based provisioner
@@ -12,13 +16,14 @@ const (
 // BrickInfo contains the static information about the brick.
 // Clients should NOT use this struct directly.
 type BrickInfo struct {
-	ID         uuid.UUID `json:"id"`
-	Path       string    `json:"path"`
-	VolumeID   uuid.UUID `json:"volume-id"`
-	VolumeName string    `json:"volume-name"`
-	PeerID     uuid.UUID `json:"peer-id"`
-	Hostname   string    `json:"host"`
-	Type       BrickType `json:"type"`
+	ID             uuid.UUID `json:"id"`
+	Path           string    `json:"path"`
+	VolumeID       uuid.UUID `json:"volume-id"`
+	VolumeName     string    `json:"volume-name"`
+	PeerID         uuid.UUID `json:"peer-id"`
+	Hostname       string    `json:"host"`
+	Type           BrickType `json:"type"`
+	Decommissioned bool      `json:"decommissioned,omitempty"`
 }
 
 // Subvol contains static information about sub volume
@@ -45,14 +50,99 @@ type SizeInfo struct {
 // BrickStatus contains the runtime information about the brick.
 // Clients should NOT use this struct directly.
 type BrickStatus struct {
-	Info      BrickInfo `json:"info"`
-	Online    bool      `json:"online"`
-	Pid       int       `json:"pid"`
-	Port      int       `json:"port"`
-	FS        string    `json:"fs-type"`
-	MountOpts string    `json:"mount-opts"`
-	Device    string    `json:"device"`
-	Size      SizeInfo  `json:"size"`
+	Info   BrickInfo `json:"info"`
+	Online bool      `json:"online"`
+	// Failed is set if the brick process died and glusterd2 gave up
+	// restarting it
+	Failed bool `json:"failed,omitempty"`
+	Pid    int  `json:"pid"`
+	Port   int  `json:"port"`
+	// BricksInProc is the number of bricks run by the brick process,
+	// which is more than one when bricks are multiplexed
+	BricksInProc int      `json:"bricks-in-process,omitempty"`
+	FS           string   `json:"fs-type"`
+	MountOpts    string   `json:"mount-opts"`
+	Device       string   `json:"device"`
+	Size         SizeInfo `json:"size"`
+	// Cgroup is set if the brick process is run in a cgroup, which is
+	// shared by the brick processes of the volume on the peer
+	Cgroup *CgroupUsage `json:"cgroup,omitempty"`
+}
+
+// CgroupUsage represents the resources used by the processes of a cgroup
+type CgroupUsage struct {
+	Name string `json:"name"`
+	// CPUTime is the CPU time used, in microseconds
+	CPUTime uint64 `json:"cpu-time-usec"`
+	// Memory is the memory currently used, in bytes
+	Memory uint64 `json:"memory"`
+}
+
+// InodeInfo is the inode utilization of the file system of a brick
+type InodeInfo struct {
+	Total uint64 `json:"total"`
+	Used  uint64 `json:"used"`
+	Free  uint64 `json:"free"`
+}
+
+// BrickHealSummary is the summary of the heal info of a brick. A count is
+// nil if it isn't known, for example when the brick is down.
+type BrickHealSummary struct {
+	Status                 string `json:"status"`
+	TotalEntries           *int64 `json:"total-entries,omitempty"`
+	EntriesInHealPending   *int64 `json:"entries-in-heal-pending,omitempty"`
+	EntriesInSplitBrain    *int64 `json:"entries-in-split-brain,omitempty"`
+	EntriesPossiblyHealing *int64 `json:"entries-possibly-healing,omitempty"`
+}
+
+// BrickStatusDetailResp is the response sent for a detailed brick status
+// request. StartedAt and Uptime are only set if the brick is online. Heal is
+// only set for bricks of started replicate and disperse volumes.
+type BrickStatusDetailResp struct {
+	BrickStatus
+	StartedAt time.Time `json:"started-at,omitempty"`
+	// Uptime is the time the brick process has been running, in seconds
+	Uptime   int64             `json:"uptime-sec,omitempty"`
+	Inodes   InodeInfo         `json:"inodes"`
+	ThinPool *ThinPoolUsage    `json:"thin-pool,omitempty"`
+	Clients  []ClientInfo      `json:"clients,omitempty"`
+	Heal     *BrickHealSummary `json:"heal,omitempty"`
+}
+
+// ClientInfo represents a client connected to a brick
+type ClientInfo struct {
+	Hostname     string `json:"hostname"`
+	Name         string `json:"name,omitempty"`
+	PID          int    `json:"pid,omitempty"`
+	BytesRead    uint64 `json:"bytes-read"`
+	BytesWritten uint64 `json:"bytes-written"`
+	OpVersion    uint32 `json:"op-version"`
+	Internal     bool   `json:"internal"`
+}
+
+// BrickClients contains the clients connected to a brick
+type BrickClients struct {
+	Brick   BrickInfo    `json:"brick"`
+	Clients []ClientInfo `json:"clients"`
+}
+
+// VolumeClient represents a client process connected to one or more bricks
+// of a volume
+type VolumeClient struct {
+	Hostname      string   `json:"hostname"`
+	PID           int      `json:"pid,omitempty"`
+	MountProtocol string   `json:"mount-protocol,omitempty"`
+	OpVersion     uint32   `json:"op-version"`
+	BytesRead     uint64   `json:"bytes-read"`
+	BytesWritten  uint64   `json:"bytes-written"`
+	Internal      bool     `json:"internal"`
+	Bricks        []string `json:"bricks"`
+}
+
+// VolumeClientsResp is the response sent for a volume clients request
+type VolumeClientsResp struct {
+	Volume  string         `json:"volume"`
+	Clients []VolumeClient `json:"clients"`
 }
 
 // BricksStatusResp contains statuses of bricks belonging to one
@@ -64,6 +154,7 @@ type BricksStatusResp []BrickStatus
 type VolumeInfo struct {
 	ID                      uuid.UUID         `json:"id"`
 	Name                    string            `json:"name"`
+	Namespace               string            `json:"namespace"`
 	Type                    VolType           `json:"type"`
 	Transport               string            `json:"transport"`
 	DistCount               int               `json:"distribute-count"`
@@ -75,9 +166,12 @@ type VolumeInfo struct {
 	Options                 map[string]string `json:"options"`
 	State                   VolState          `json:"state"`
 	Subvols                 []Subvol          `json:"subvols"`
+	DefaultOptions          []string          `json:"default-options,omitempty"`
 	Metadata                map[string]string `json:"metadata"`
+	Labels                  map[string]string `json:"labels,omitempty"`
 	SnapList                []string          `json:"snap-list"`
 	Capacity                uint64            `json:"capacity,omitempty"`
+	CreatedAt               time.Time         `json:"created-at"`
 }
 
 // VolumeStatusResp response contains the statuses of all bricks of the volume.
@@ -116,7 +210,24 @@ type VolumeGetResp VolumeInfo
 type ReplaceBrickResp VolumeInfo
 
 // VolumeExpandResp is the response sent for a volume expand request.
-type VolumeExpandResp VolumeInfo
+// RebalanceID is set when rebalance was started along with the expansion.
+type VolumeExpandResp struct {
+	VolumeInfo
+	RebalanceID uuid.UUID `json:"rebalance-id,omitempty"`
+}
+
+// VolBulkResult is the result of the operation on a single volume of a bulk
+// request
+type VolBulkResult struct {
+	Name   string            `json:"name"`
+	Status int               `json:"status"`
+	Error  string            `json:"error,omitempty"`
+	Volume *VolumeCreateResp `json:"volume,omitempty"`
+}
+
+// VolBulkResp is the response sent for bulk volume create and delete requests
+// and for the start-all and stop-all requests.
+type VolBulkResp []VolBulkResult
 
 // VolumeStartResp is the response sent for a volume start request.
 type VolumeStartResp VolumeInfo
@@ -142,8 +253,192 @@ type VolumeListResp []VolumeGetResp
 // OptionGroupListResp is the response sent for a group list request.
 type OptionGroupListResp []OptionGroup
 
+// VolumeProfileListResp is the response sent for a volume profile list request.
+type VolumeProfileListResp []VolumeProfile
+
+// VolumeLabelsResp is the response sent for a volume labels update request
+type VolumeLabelsResp VolumeInfo
+
 // VolumeEditResp is the response sent for a edit volume request
 type VolumeEditResp VolumeInfo
 
+// RemoveBrickStartResp is the response sent for a remove-brick start
+// request. RebalanceID identifies the data migration off the bricks.
+type RemoveBrickStartResp struct {
+	VolumeInfo
+	RebalanceID uuid.UUID `json:"rebalance-id"`
+}
+
+// RemoveBrickNodeStatus represents the data migration status on a node
+type RemoveBrickNodeStatus struct {
+	PeerID        uuid.UUID `json:"peerid"`
+	Status        string    `json:"status"`
+	MigratedFiles string    `json:"migrated-files"`
+	MigratedSize  string    `json:"size"`
+	ScannedFiles  string    `json:"scanned"`
+	SkippedFiles  string    `json:"skipped"`
+	Failures      string    `json:"failed"`
+	ElapsedTime   string    `json:"run-time"`
+	TimeLeft      string    `json:"time-left"`
+}
+
+// RemoveBrickStatusResp is the response sent for a remove-brick status request
+type RemoveBrickStatusResp struct {
+	Volname     string                  `json:"volume"`
+	RebalanceID uuid.UUID               `json:"rebalance-id"`
+	Status      string                  `json:"status"`
+	Bricks      []BrickInfo             `json:"bricks"`
+	Nodes       []RemoveBrickNodeStatus `json:"nodes-status"`
+}
+
+// RemoveBrickCommitResp is the response sent for a remove-brick commit request
+type RemoveBrickCommitResp VolumeInfo
+
+// RemoveBrickStopResp is the response sent for a remove-brick stop request
+type RemoveBrickStopResp VolumeInfo
+
+// VolResourceLimitsResp is the response sent for a request to get or change
+// the resource limits of the brick processes of a volume
+type VolResourceLimitsResp struct {
+	VolName   string `json:"volume"`
+	CPUQuota  int    `json:"cpu-quota"`
+	MemoryMax uint64 `json:"memory-max"`
+}
+
+// VolumeDeletionProtectionResp is the response sent for a volume deletion
+// protection update request
+type VolumeDeletionProtectionResp VolumeInfo
+
 // VolumeOptionsGetResp is the response sent for a volume get request for all options
 type VolumeOptionsGetResp []VolumeOptionGetResp
+
+// VolumeExportVersion is the version of the volume export format
+const VolumeExportVersion = 1
+
+// BrickExport is the portable description of a brick in a volume export
+type BrickExport struct {
+	ID             uuid.UUID `json:"id"`
+	Path           string    `json:"path"`
+	PeerID         uuid.UUID `json:"peer-id"`
+	Hostname       string    `json:"host"`
+	Type           BrickType `json:"type"`
+	ProvisionType  string    `json:"provision-type,omitempty"`
+	BrickDirSuffix string    `json:"brick-dir-suffix,omitempty"`
+	DevicePath     string    `json:"device-path,omitempty"`
+	FsType         string    `json:"fs-type,omitempty"`
+	MntOpts        string    `json:"mount-opts,omitempty"`
+	ThinPool       string    `json:"thin-pool,omitempty"`
+	LvName         string    `json:"lv-name,omitempty"`
+	VgName         string    `json:"vg-name,omitempty"`
+	RootDevice     string    `json:"root-device,omitempty"`
+	TotalSize      uint64    `json:"total-size,omitempty"`
+	Decommissioned bool      `json:"decommissioned,omitempty"`
+}
+
+// SubvolExport is the portable description of a sub volume in a volume export
+type SubvolExport struct {
+	ID              uuid.UUID     `json:"id"`
+	Name            string        `json:"name"`
+	Type            SubvolType    `json:"type"`
+	Bricks          []BrickExport `json:"bricks"`
+	ReplicaCount    int           `json:"replica-count"`
+	ArbiterCount    int           `json:"arbiter-count"`
+	DisperseCount   int           `json:"disperse-count"`
+	RedundancyCount int           `json:"redundancy-count"`
+}
+
+// VolumeExport is a portable description of a volume, its options and brick
+// layout which can be imported into a cluster to recreate the volume
+type VolumeExport struct {
+	Version               int               `json:"version"`
+	ID                    uuid.UUID         `json:"id"`
+	Name                  string            `json:"name"`
+	Type                  VolType           `json:"type"`
+	Transport             string            `json:"transport"`
+	DistCount             int               `json:"distribute-count"`
+	Options               map[string]string `json:"options"`
+	DefaultOptions        []string          `json:"default-options,omitempty"`
+	Metadata              map[string]string `json:"metadata"`
+	Labels                map[string]string `json:"labels,omitempty"`
+	SnapshotReserveFactor float64           `json:"snapshot-reserve-factor"`
+	Capacity              uint64            `json:"capacity,omitempty"`
+	ProvisionerType       string            `json:"provisioner-type,omitempty"`
+	CreatedAt             time.Time         `json:"created-at"`
+	Subvols               []SubvolExport    `json:"subvols"`
+}
+
+// VolfileChecksum represents the checksum of a volfile of the volume on a peer
+type VolfileChecksum struct {
+	VolfileID string `json:"volfile-id"`
+	Checksum  uint64 `json:"checksum"`
+	InSync    bool   `json:"in-sync"`
+}
+
+// VolumeNodeChecksum represents the volfiles of the volume found on a peer
+type VolumeNodeChecksum struct {
+	PeerID   uuid.UUID         `json:"peer-id"`
+	Volfiles []VolfileChecksum `json:"volfiles"`
+	InSync   bool              `json:"in-sync"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// VolfileResp is the response sent for a volfile request. Content is the
+// volfile as generated from the current volinfo.
+type VolfileResp struct {
+	VolName  string `json:"volume"`
+	Type     string `json:"type"`
+	BrickID  string `json:"brick-id,omitempty"`
+	Checksum uint64 `json:"checksum"`
+	Content  string `json:"content"`
+}
+
+// VolumeChecksumResp is the response sent for a volume checksum request.
+// Version and Checksum are those of the volinfo in the store.
+type VolumeChecksumResp struct {
+	Volname        string               `json:"volume"`
+	Version        int64                `json:"version"`
+	Checksum       uint64               `json:"checksum"`
+	InSync         bool                 `json:"in-sync"`
+	OutOfSyncPeers []uuid.UUID          `json:"out-of-sync-peers,omitempty"`
+	Nodes          []VolumeNodeChecksum `json:"nodes"`
+}
+
+// VolumeHistoryEntry records a single change made to a volume
+type VolumeHistoryEntry struct {
+	Time      time.Time         `json:"time"`
+	Event     string            `json:"event"`
+	Requester string            `json:"requester,omitempty"`
+	ReqID     string            `json:"req-id,omitempty"`
+	TxnID     string            `json:"txn-id,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+}
+
+// VolumeHistoryResp is the response sent for a volume history request,
+// ordered from oldest to newest change
+type VolumeHistoryResp []VolumeHistoryEntry
+
+// ThinPoolUsage is the utilization of the LVM thin pool of a brick
+type ThinPoolUsage struct {
+	VgName   string `json:"vg-name"`
+	TpName   string `json:"tp-name"`
+	Capacity uint64 `json:"capacity"`
+	Used     uint64 `json:"used"`
+}
+
+// BrickUsage is the utilization of a brick as last sampled by its peer.
+// SampledAt is zero if the brick hasn't been sampled yet.
+type BrickUsage struct {
+	PeerID    uuid.UUID      `json:"peer-id"`
+	Path      string         `json:"path"`
+	Size      SizeInfo       `json:"size"`
+	ThinPool  *ThinPoolUsage `json:"thin-pool,omitempty"`
+	SampledAt time.Time      `json:"sampled-at,omitempty"`
+}
+
+// VolumeUsageResp is the response sent for a volume usage request. Total is
+// the sum of the sizes of the sampled bricks.
+type VolumeUsageResp struct {
+	Volname string       `json:"volume"`
+	Total   SizeInfo     `json:"total"`
+	Bricks  []BrickUsage `json:"bricks"`
+}
@@ -0,0 +1,131 @@
+// Package cgroups places processes into cgroups of the unified (v2) cgroup
+// hierarchy and limits their CPU and memory usage.
+package cgroups
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// cpuPeriod is the period, in microseconds, over which the CPU quota of a
+// cgroup is enforced
+const cpuPeriod = 100000
+
+// Limits are the resource limits of a cgroup. A limit which is zero is not
+// enforced.
+type Limits struct {
+	// CPUQuota is the CPU time the processes of the cgroup may use, in
+	// percent of one CPU. It is above 100 to allow more than one CPU.
+	CPUQuota int
+	// MemoryMax is the memory, in bytes, the processes of the cgroup may
+	// use before being reclaimed from or killed
+	MemoryMax uint64
+}
+
+// Usage is the resource usage of a cgroup
+type Usage struct {
+	// CPUTime is the CPU time, in microseconds, used by the processes of
+	// the cgroup since it was created
+	CPUTime uint64
+	// Memory is the memory, in bytes, currently used by the processes of
+	// the cgroup
+	Memory uint64
+}
+
+// Create creates the cgroup at the given path, and its parents, enabling the
+// cpu and memory controllers for it on the way.
+func Create(cgroup string) error {
+	if _, err := os.Stat(cgroup); err == nil {
+		return nil
+	}
+
+	parent := path.Dir(cgroup)
+	if err := Create(parent); err != nil {
+		return err
+	}
+	// The controllers may already be enabled, or not be delegated to
+	// glusterd2, in which case setting the limits fails later
+	ioutil.WriteFile(path.Join(parent, "cgroup.subtree_control"), []byte("+cpu +memory"), 0644)
+
+	return os.Mkdir(cgroup, 0755)
+}
+
+// SetLimits sets the limits of the cgroup at the given path
+func SetLimits(cgroup string, l Limits) error {
+	if err := writeFile(cgroup, "cpu.max", formatCPUMax(l.CPUQuota)); err != nil {
+		return err
+	}
+
+	memMax := "max"
+	if l.MemoryMax != 0 {
+		memMax = strconv.FormatUint(l.MemoryMax, 10)
+	}
+	return writeFile(cgroup, "memory.max", memMax)
+}
+
+// AddProcess moves the process with the given pid into the cgroup at the
+// given path. Processes it spawns later are in the same cgroup.
+func AddProcess(cgroup string, pid int) error {
+	return writeFile(cgroup, "cgroup.procs", strconv.Itoa(pid))
+}
+
+// GetUsage returns the resource usage of the cgroup at the given path
+func GetUsage(cgroup string) (Usage, error) {
+	var u Usage
+
+	mem, err := ioutil.ReadFile(path.Join(cgroup, "memory.current"))
+	if err != nil {
+		return u, err
+	}
+	if u.Memory, err = strconv.ParseUint(string(bytes.TrimSpace(mem)), 10, 64); err != nil {
+		return u, err
+	}
+
+	stat, err := ioutil.ReadFile(path.Join(cgroup, "cpu.stat"))
+	if err != nil {
+		return u, err
+	}
+	u.CPUTime, err = parseCPUStat(stat)
+	return u, err
+}
+
+// Remove removes the cgroup at the given path. A cgroup can only be removed
+// once none of its processes are running.
+func Remove(cgroup string) error {
+	err := os.Remove(cgroup)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func writeFile(cgroup, file, value string) error {
+	return ioutil.WriteFile(path.Join(cgroup, file), []byte(value), 0644)
+}
+
+func formatCPUMax(quota int) string {
+	if quota <= 0 {
+		return fmt.Sprintf("max %d", cpuPeriod)
+	}
+	return fmt.Sprintf("%d %d", quota*cpuPeriod/100, cpuPeriod)
+}
+
+func parseCPUStat(stat []byte) (uint64, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(stat))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "usage_usec" {
+			return strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("usage_usec not found in cpu.stat")
+}
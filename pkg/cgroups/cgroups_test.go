@@ -0,0 +1,46 @@
+package cgroups
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatCPUMax(t *testing.T) {
+	assert.Equal(t, "max 100000", formatCPUMax(0))
+	assert.Equal(t, "50000 100000", formatCPUMax(50))
+	assert.Equal(t, "200000 100000", formatCPUMax(200))
+}
+
+func TestParseCPUStat(t *testing.T) {
+	usage, err := parseCPUStat([]byte("usage_usec 123456\nuser_usec 100000\nsystem_usec 23456\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(123456), usage)
+
+	_, err = parseCPUStat([]byte("user_usec 100000\n"))
+	assert.NotNil(t, err)
+}
+
+func TestSetLimitsAndGetUsage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cgroups")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	cgroup := path.Join(dir, "bricks", "vol1")
+	assert.Nil(t, Create(cgroup))
+
+	assert.Nil(t, SetLimits(cgroup, Limits{CPUQuota: 150, MemoryMax: 1 << 30}))
+	cpuMax, _ := ioutil.ReadFile(path.Join(cgroup, "cpu.max"))
+	assert.Equal(t, "150000 100000", string(cpuMax))
+	memMax, _ := ioutil.ReadFile(path.Join(cgroup, "memory.max"))
+	assert.Equal(t, "1073741824", string(memMax))
+
+	ioutil.WriteFile(path.Join(cgroup, "memory.current"), []byte("4096\n"), 0644)
+	ioutil.WriteFile(path.Join(cgroup, "cpu.stat"), []byte("usage_usec 42\n"), 0644)
+	u, err := GetUsage(cgroup)
+	assert.Nil(t, err)
+	assert.Equal(t, Usage{CPUTime: 42, Memory: 4096}, u)
+}
@@ -26,6 +26,20 @@ func (ee *ElasticEtcd) Session() *concurrency.Session {
 	return ee.session
 }
 
+// NewClient returns a new etcd client connected only to the given endpoints,
+// configured like the client of ElasticEtcd. It is used for requests which
+// have to be sent to a specific member, like moving the leadership of the
+// cluster. The caller must close the client.
+func (ee *ElasticEtcd) NewClient(endpoints []string) (*clientv3.Client, error) {
+	ee.lock.RLock()
+	conf := ee.newClientConfig()
+	ee.lock.RUnlock()
+
+	conf.Endpoints = endpoints
+	conf.AutoSyncInterval = 0
+	return clientv3.New(conf)
+}
+
 // startClient starts the etcd client and connects the ElasticEtcd instance to the elastic cluster.
 func (ee *ElasticEtcd) startClient() error {
 	if ee.cli != nil {
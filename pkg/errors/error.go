@@ -18,6 +18,9 @@ var (
 	ErrVolExists                       = errors.New("volume already exists")
 	ErrVolAlreadyStarted               = errors.New("volume already started")
 	ErrVolAlreadyStopped               = errors.New("volume already stopped")
+	ErrVolHasActiveClients             = errors.New("volume has active clients")
+	ErrVolDeletionProtected            = errors.New("volume has deletion protection enabled")
+	ErrDeletionProtectionEdit          = errors.New("deletion protection can only be cleared using the deletion-protection API")
 	ErrWrongGraphType                  = errors.New("graph: incorrect graph type")
 	ErrDeviceIDNotFound                = errors.New("failed to get device id")
 	ErrBrickIsMountPoint               = errors.New("brick path is already a mount point")
@@ -60,6 +63,10 @@ var (
 	ErrInvalidVolFileTmplName          = errors.New("invalid template name")
 	ErrDeviceNameNotFound              = errors.New("device name not found")
 	ErrInvalidSplitBrainOp             = errors.New("invalid split-brain operation specified")
+	ErrInvalidSourceBrick              = errors.New("source brick must be given as <hostname>:<brickpath>")
+	ErrInvalidHealInfoOpt              = errors.New("invalid heal info option, must be info-summary or split-brain-info")
+	ErrSelfHealAlreadyEnabled          = errors.New("self-heal daemon is already enabled")
+	ErrSelfHealAlreadyDisabled         = errors.New("self-heal daemon is already disabled")
 	ErrInvalidHostName                 = errors.New("hostname doesn't exist")
 	ErrInvalidBrickName                = errors.New("brick doesn't exist on this host")
 	ErrFilenameNotFound                = errors.New("please specify filename for split-brain operation")
@@ -67,12 +74,81 @@ var (
 	ErrHostOrBrickNotFound             = errors.New("please specify hostname and brick path to resolve split-brain")
 	ErrVolTypeNotInReplicateOrDisperse = errors.New("invalid operation: the volume is not a replicate or disperse volume")
 	ErrDeviceNotFound                  = errors.New("device does not exist in the given peer")
+	ErrDeviceInUse                     = errors.New("device has bricks on it")
 	ErrVolumeBricksMountFailed         = errors.New("failed to get mount point entries for the volume bricks")
 	ErrBrickMountFailed                = errors.New("failed to mount brick")
 	ErrReservedGroupProfile            = errors.New("reserved group profile")
+	ErrOptionGroupNotFound             = errors.New("option group not found")
 	ErrInvalidIntValue                 = errors.New("error parsing the value. Make sure the value is a valid integer")
 	ErrConnectingHost                  = errors.New("could not connect to host. Make sure host address is valid, network connection is active and gd2 is up and running")
 	ErrBlockVolNotFound                = errors.New("block volume not found")
 	ErrBlockHostVolNotFound            = errors.New("block hosting volume not found")
 	ErrSnapNotSupported                = errors.New("snapshot not supported")
+	ErrVolProfileNotFound              = errors.New("volume profile not found")
+	ErrVolProfileExists                = errors.New("volume profile already exists")
+	ErrSrcBrickNotFound                = errors.New("source brick not found in the volume")
+	ErrNewBrickRequired                = errors.New("new brick must be specified for volumes which are not auto provisioned")
+	ErrNewBrickNotAllowed              = errors.New("new brick can't be specified for auto provisioned volumes")
+	ErrRemoveBrickInProgress           = errors.New("remove-brick is in progress on the volume")
+	ErrRemoveBrickNotStarted           = errors.New("remove-brick not started on the volume")
+	ErrRemoveBrickNotComplete          = errors.New("data migration from the bricks being removed is not complete")
+	ErrRemoveBrickMigrationFailed      = errors.New("data migration failed for some files on the bricks being removed")
+	ErrRemoveBrickPartialSubvol        = errors.New("bricks being removed must make up whole subvolumes")
+	ErrRemoveBrickAllBricks            = errors.New("can't remove all the bricks of the volume")
+	ErrBrickNotInVolume                = errors.New("brick is not part of the volume")
+	ErrTxnNotFound                     = errors.New("transaction not found")
+	ErrTxnNotRunning                   = errors.New("transaction is not running")
+	ErrIdempotencyKeyInvalid           = errors.New("idempotency key must not be longer than 255 characters")
+	ErrIdempotencyKeyInProgress        = errors.New("a request with the same idempotency key is in progress")
+	ErrIdempotencyKeyReused            = errors.New("idempotency key has already been used for a different request")
+	ErrLockNotFound                    = errors.New("lock not found")
+	ErrAdminRequired                   = errors.New("operation requires admin privileges")
+	ErrScheduleNotFound                = errors.New("schedule not found")
+	ErrScheduleExists                  = errors.New("schedule already exists")
+	ErrScheduleOpNotSupported          = errors.New("operation can't be scheduled")
+	ErrScheduleTimeRequired            = errors.New("exactly one of at and cron must be given")
+	ErrScheduleTimeInPast              = errors.New("scheduled time is in the past")
+	ErrScheduleNeverRuns               = errors.New("cron expression never matches")
+	ErrJobNotFound                     = errors.New("job not found")
+	ErrJobNotRunning                   = errors.New("job has already ended")
+	ErrJobCancelled                    = errors.New("job cancelled")
+	ErrJobOrphaned                     = errors.New("peer running the job went down")
+	ErrUserNotFound                    = errors.New("user not found")
+	ErrUserExists                      = errors.New("user already exists")
+	ErrUserReserved                    = errors.New("user name is reserved")
+	ErrInvalidRole                     = errors.New("invalid role, supported values: admin, operator, viewer")
+	ErrRoleNotAllowed                  = errors.New("role in token is not allowed for the user")
+	ErrPermissionDenied                = errors.New("operation is not permitted for the role of the user")
+	ErrAuditStoreDisabled              = errors.New("audit records are not kept in the store, auditstore is not enabled")
+	ErrInvalidTime                     = errors.New("invalid time, must be in RFC 3339 format")
+	ErrTooManyRequests                 = errors.New("too many requests, retry later")
+	ErrTooManyConcurrentRequests       = errors.New("too many requests changing the cluster are being served, retry later")
+	ErrEventStreamAccept               = errors.New("event stream requests must accept text/event-stream")
+	ErrInvalidEventSeverity            = errors.New("invalid event severity, supported values: info, warning, error, critical")
+	ErrWebhookNotFound                 = errors.New("webhook not found")
+	ErrWebhookInvalidURL               = errors.New("webhook url must be an absolute http or https url")
+	ErrWebhookInvalidRetry             = errors.New("invalid retry policy, max-retries must be between 0 and 20 and backoffs must not be negative")
+	ErrMaintenanceMode                 = errors.New("cluster is in maintenance mode, requests changing it are not allowed")
+	ErrInvalidNamespace                = errors.New("invalid namespace name")
+	ErrNamespaceNotAllowed             = errors.New("namespace is not allowed for the user")
+	ErrInvalidHealthLevel              = errors.New("invalid health check level, must be node or cluster")
+	ErrOpVersionTooLow                 = errors.New("the op-version of the cluster is too low for the request, raise it once all peers are upgraded")
+	ErrOpVersionNotSupported           = errors.New("op-version is not supported by all peers of the cluster")
+	ErrOpVersionLowered                = errors.New("the op-version of the cluster can not be lowered")
+	ErrSnapPolicyNotFound              = errors.New("snapshot policy not found")
+	ErrSnapPolicyExists                = errors.New("snapshot policy already exists")
+	ErrSnapPolicyNoSelector            = errors.New("snapshot policy must select volumes by name or labels")
+	ErrSnapPolicyInvalidRetention      = errors.New("retention of a snapshot policy must be between 1 and 256")
+	ErrSnapCountLimitReached           = errors.New("volume has reached its limit on the number of snapshots")
+	ErrSnapCapacityLimitReached        = errors.New("thin pools of the volume have reached their capacity limit for snapshots")
+	ErrSnapNotExpired                  = errors.New("snapshot activation has not expired")
+	ErrSnapInvalidTTL                  = errors.New("time-to-live of a snapshot activation can not be negative")
+	ErrSnapLimitsInvalid               = errors.New("invalid snapshot limits, soft limits must be lower than hard limits and capacity limits must be between 0 and 100")
+	ErrResourceLimitsInvalid           = errors.New("invalid resource limits, cpu quota must not be negative")
+	ErrVolfileBrickRequired            = errors.New("brick must be given to get a brick volfile")
+	ErrVolfileTemplateNotFound         = errors.New("volfile template not found")
+	ErrInvalidVolType                  = errors.New("invalid volume type")
+	ErrStoreNotEmbedded                = errors.New("store is not the embedded etcd, maintain the etcd cluster with its own tools")
+	ErrStoreMemberNotFound             = errors.New("store member not found")
+	ErrStoreMemberRequired             = errors.New("store member must be given")
 )
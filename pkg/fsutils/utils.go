@@ -83,3 +83,20 @@ func Mount(dev, mountdir, mountOpts string) error {
 func Unmount(mountdir string) error {
 	return syscall.Unmount(mountdir, syscall.MNT_FORCE)
 }
+
+// Freeze suspends writes to the filesystem mounted at mountdir and flushes it
+// to its device, until it is thawed with Thaw
+func Freeze(mountdir string) error {
+	return utils.ExecuteCommandRun("fsfreeze", "--freeze", mountdir)
+}
+
+// Thaw resumes writes to a filesystem frozen with Freeze
+func Thaw(mountdir string) error {
+	return utils.ExecuteCommandRun("fsfreeze", "--unfreeze", mountdir)
+}
+
+// CopySparseFile copies a file, keeping it sparse. The copy shares the
+// blocks of the file if the filesystem supports reflinks.
+func CopySparseFile(src, dst string) error {
+	return utils.ExecuteCommandRun("cp", "--reflink=auto", "--sparse=always", src, dst)
+}
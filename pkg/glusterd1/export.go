@@ -0,0 +1,118 @@
+package glusterd1
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+)
+
+// Export returns the volume export describing the glusterd1 volume, which
+// can be imported into glusterd2 with the volume import API. The bricks keep
+// the glusterd1 UUID of their peer, if it is known, and their hostname; they
+// have to be mapped to the peers of the glusterd2 cluster when the volume is
+// imported. The volume keeps all its glusterd1 options.
+func (v *Volume) Export() (*api.VolumeExport, error) {
+	if len(v.Bricks) == 0 {
+		return nil, fmt.Errorf("volume %s has no bricks", v.Name)
+	}
+	if v.Transport != TransportTCP {
+		return nil, fmt.Errorf("volume %s: only the tcp transport is supported", v.Name)
+	}
+
+	e := &api.VolumeExport{
+		Version:   api.VolumeExportVersion,
+		ID:        v.ID,
+		Name:      v.Name,
+		Transport: "tcp",
+		Options:   make(map[string]string),
+		Metadata:  make(map[string]string),
+	}
+	for k, val := range v.Options {
+		e.Options[k] = val
+	}
+
+	// Bricks are grouped in subvolumes of the same size, in their order
+	var (
+		subvolSize int
+		subvolType api.SubvolType
+		typeName   string
+	)
+	switch v.Type {
+	case TypeNone:
+		subvolSize, subvolType, typeName = 1, api.SubvolDistribute, "distribute"
+	case TypeReplicate:
+		subvolSize, subvolType, typeName = v.ReplicaCount, api.SubvolReplicate, "replicate"
+	case TypeDisperse:
+		subvolSize, subvolType, typeName = v.DisperseCount, api.SubvolDisperse, "disperse"
+	default:
+		return nil, fmt.Errorf("volume %s: volume type %d is not supported", v.Name, v.Type)
+	}
+	if subvolSize < 1 || len(v.Bricks)%subvolSize != 0 {
+		return nil, fmt.Errorf("volume %s: %d bricks can't be grouped in subvolumes of %d bricks",
+			v.Name, len(v.Bricks), subvolSize)
+	}
+	if v.ArbiterCount > 0 {
+		if v.Type != TypeReplicate || v.ReplicaCount != 3 || v.ArbiterCount != 1 {
+			return nil, fmt.Errorf("volume %s: only replica 3 arbiter 1 is supported", v.Name)
+		}
+		e.Options["replicate.arbiter-count"] = strconv.Itoa(v.ArbiterCount)
+	}
+
+	for i := 0; i < len(v.Bricks); i += subvolSize {
+		s := api.SubvolExport{
+			ID:   uuid.NewRandom(),
+			Name: fmt.Sprintf("%s-%s-%d", v.Name, typeName, len(e.Subvols)),
+			Type: subvolType,
+			// The replica count of the subvolumes of distribute volumes
+			// is 1, like for the volumes created by glusterd2
+			ReplicaCount: 1,
+		}
+		switch v.Type {
+		case TypeReplicate:
+			// The arbiter brick is counted in the replica count of
+			// glusterd1, not in the one of glusterd2
+			s.ReplicaCount = v.ReplicaCount - v.ArbiterCount
+			s.ArbiterCount = v.ArbiterCount
+		case TypeDisperse:
+			s.DisperseCount = v.DisperseCount
+			s.RedundancyCount = v.RedundancyCount
+		}
+
+		for j, b := range v.Bricks[i : i+subvolSize] {
+			eb := api.BrickExport{
+				ID:             uuid.NewRandom(),
+				Path:           b.Path,
+				PeerID:         b.PeerUUID,
+				Hostname:       b.Hostname,
+				Type:           api.Brick,
+				Decommissioned: b.Decommissioned,
+			}
+			// The last brick of every replica set is the arbiter
+			if v.ArbiterCount > 0 && j == subvolSize-1 {
+				eb.Type = api.Arbiter
+			}
+			s.Bricks = append(s.Bricks, eb)
+		}
+		e.Subvols = append(e.Subvols, s)
+	}
+
+	e.DistCount = len(e.Subvols)
+	distributed := e.DistCount > 1
+	switch {
+	case v.Type == TypeReplicate && distributed:
+		e.Type = api.DistReplicate
+	case v.Type == TypeReplicate:
+		e.Type = api.Replicate
+	case v.Type == TypeDisperse && distributed:
+		e.Type = api.DistDisperse
+	case v.Type == TypeDisperse:
+		e.Type = api.Disperse
+	default:
+		e.Type = api.Distribute
+	}
+
+	return e, nil
+}
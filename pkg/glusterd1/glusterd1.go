@@ -0,0 +1,277 @@
+// Package glusterd1 reads the configuration of a glusterd1 (legacy) peer from
+// its working directory, so that its peers and volumes can be imported into
+// glusterd2.
+package glusterd1
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pborman/uuid"
+)
+
+// DefaultWorkdir is the working directory of glusterd1
+const DefaultWorkdir = "/var/lib/glusterd"
+
+// Volume types of glusterd1. Distributed volumes have the type of their
+// subvolumes.
+const (
+	TypeNone            = 0
+	TypeStripe          = 1
+	TypeReplicate       = 2
+	TypeStripeReplicate = 3
+	TypeDisperse        = 4
+	TypeTier            = 5
+)
+
+// Volume states of glusterd1
+const (
+	StatusCreated = 0
+	StatusStarted = 1
+	StatusStopped = 2
+)
+
+// Transport types of glusterd1
+const (
+	TransportTCP     = 0
+	TransportRDMA    = 1
+	TransportTCPRDMA = 2
+)
+
+// Peer is a peer of the glusterd1 peer
+type Peer struct {
+	UUID      uuid.UUID
+	Hostnames []string
+}
+
+// Brick is a brick of a glusterd1 volume
+type Brick struct {
+	Hostname string
+	Path     string
+	// PeerUUID is the UUID of the peer of the brick, which is only stored
+	// by recent versions of glusterd1
+	PeerUUID       uuid.UUID
+	Decommissioned bool
+}
+
+// Volume is a glusterd1 volume
+type Volume struct {
+	Name            string
+	ID              uuid.UUID
+	Type            int
+	Status          int
+	Transport       int
+	ReplicaCount    int
+	ArbiterCount    int
+	DisperseCount   int
+	RedundancyCount int
+	Username        string
+	Password        string
+	Options         map[string]string
+	Bricks          []Brick
+}
+
+// Workdir is the configuration found in the working directory of a
+// glusterd1 peer
+type Workdir struct {
+	// UUID is the UUID of the glusterd1 peer itself
+	UUID      uuid.UUID
+	OpVersion int
+	Peers     []Peer
+	Volumes   []Volume
+}
+
+// readStore reads a glusterd1 store file, which has a key=value pair on
+// every line
+func readStore(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("%s: invalid line %q", path, line)
+		}
+		values[kv[0]] = kv[1]
+	}
+	return values, scanner.Err()
+}
+
+// atoi parses the integer value of key, which is 0 if it is not set
+func atoi(values map[string]string, key string) (int, error) {
+	v, ok := values[key]
+	if !ok || v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", key, v)
+	}
+	return n, nil
+}
+
+// Read reads the configuration of the glusterd1 peer from its working
+// directory
+func Read(dir string) (*Workdir, error) {
+	info, err := readStore(filepath.Join(dir, "glusterd.info"))
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Workdir{UUID: uuid.Parse(info["UUID"])}
+	if w.UUID == nil {
+		return nil, fmt.Errorf("invalid UUID %q in glusterd.info", info["UUID"])
+	}
+	if w.OpVersion, err = atoi(info, "operating-version"); err != nil {
+		return nil, err
+	}
+
+	if w.Peers, err = readPeers(filepath.Join(dir, "peers")); err != nil {
+		return nil, err
+	}
+
+	volsDir := filepath.Join(dir, "vols")
+	entries, err := ioutil.ReadDir(volsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		v, err := readVolume(filepath.Join(volsDir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("volume %s: %s", e.Name(), err)
+		}
+		w.Volumes = append(w.Volumes, *v)
+	}
+
+	return w, nil
+}
+
+func readPeers(dir string) ([]Peer, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var peers []Peer
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		values, err := readStore(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		p := Peer{UUID: uuid.Parse(values["uuid"])}
+		if p.UUID == nil {
+			return nil, fmt.Errorf("invalid uuid %q in peer %s", values["uuid"], e.Name())
+		}
+		// The hostnames are stored as hostname1, hostname2...
+		for i := 1; ; i++ {
+			hostname, ok := values["hostname"+strconv.Itoa(i)]
+			if !ok {
+				break
+			}
+			p.Hostnames = append(p.Hostnames, hostname)
+		}
+		peers = append(peers, p)
+	}
+	return peers, nil
+}
+
+func readVolume(dir string) (*Volume, error) {
+	info, err := readStore(filepath.Join(dir, "info"))
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Volume{
+		Name:     filepath.Base(dir),
+		ID:       uuid.Parse(info["volume-id"]),
+		Username: info["username"],
+		Password: info["password"],
+		Options:  make(map[string]string),
+	}
+	if v.ID == nil {
+		return nil, fmt.Errorf("invalid volume-id %q", info["volume-id"])
+	}
+
+	for key, ptr := range map[string]*int{
+		"type":             &v.Type,
+		"status":           &v.Status,
+		"transport-type":   &v.Transport,
+		"replica_count":    &v.ReplicaCount,
+		"arbiter_count":    &v.ArbiterCount,
+		"disperse_count":   &v.DisperseCount,
+		"redundancy_count": &v.RedundancyCount,
+	} {
+		if *ptr, err = atoi(info, key); err != nil {
+			return nil, err
+		}
+	}
+
+	// The bricks are stored as brick-0, brick-1... in the order of the
+	// volume, with the name of the file describing the brick
+	count, err := atoi(info, "count")
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < count; i++ {
+		name, ok := info["brick-"+strconv.Itoa(i)]
+		if !ok {
+			return nil, fmt.Errorf("brick-%d not found", i)
+		}
+		b, err := readBrick(filepath.Join(dir, "bricks", name))
+		if err != nil {
+			return nil, err
+		}
+		v.Bricks = append(v.Bricks, *b)
+	}
+
+	// The volume options are the keys with a dot, like
+	// performance.readdir-ahead, the other keys describe the volume
+	for key, value := range info {
+		if strings.Contains(key, ".") {
+			v.Options[key] = value
+		}
+	}
+
+	return v, nil
+}
+
+func readBrick(path string) (*Brick, error) {
+	values, err := readStore(path)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Brick{
+		Hostname:       values["hostname"],
+		Path:           values["path"],
+		PeerUUID:       uuid.Parse(values["uuid"]),
+		Decommissioned: values["decommissioned"] == "1",
+	}
+	if b.Hostname == "" || b.Path == "" {
+		return nil, fmt.Errorf("%s: hostname or path of brick not found", path)
+	}
+	return b, nil
+}
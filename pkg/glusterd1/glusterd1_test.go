@@ -0,0 +1,177 @@
+package glusterd1
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	localUUID = "8a1c0b5e-1f32-4c8e-9a5b-3f0b9c1d2e40"
+	peerUUID  = "c3f1e2d4-5b6a-4c7d-8e9f-0a1b2c3d4e5f"
+	volumeID  = "0d4b3c2a-1e5f-4a6b-9c8d-7e6f5a4b3c2d"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+}
+
+// testWorkdir creates the working directory of a glusterd1 peer with a peer
+// and a replica 3 arbiter 1 volume of 6 bricks
+func testWorkdir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "glusterd1")
+	require.NoError(t, err)
+
+	writeFile(t, filepath.Join(dir, "glusterd.info"), "UUID="+localUUID+"\noperating-version=31302\n")
+	writeFile(t, filepath.Join(dir, "peers", peerUUID),
+		"uuid="+peerUUID+"\nstate=3\nhostname1=server2\nhostname2=10.0.0.2\n")
+
+	info := "type=2\ncount=6\nstatus=1\nsub_count=3\nstripe_count=1\nreplica_count=3\narbiter_count=1\n" +
+		"disperse_count=0\nredundancy_count=0\nversion=3\ntransport-type=0\nvolume-id=" + volumeID + "\n" +
+		"username=user\npassword=secret\nop-version=31302\nclient-op-version=31302\n"
+	for i, host := range []string{"server1", "server2", "server1", "server2", "server1", "server2"} {
+		name := host + ":-bricks-b" + strconv.Itoa(i)
+		info += "brick-" + strconv.Itoa(i) + "=" + name + "\n"
+		brick := "hostname=" + host + "\npath=/bricks/b" + strconv.Itoa(i) + "\nlisten-port=49152\ndecommissioned=0\n"
+		if host == "server2" {
+			brick += "uuid=" + peerUUID + "\n"
+		}
+		writeFile(t, filepath.Join(dir, "vols", "gv0", "bricks", name), brick)
+	}
+	info += "transport.address-family=inet\nperformance.readdir-ahead=on\nnfs.disable=on\n"
+	writeFile(t, filepath.Join(dir, "vols", "gv0", "info"), info)
+
+	return dir
+}
+
+func TestRead(t *testing.T) {
+	dir := testWorkdir(t)
+	defer os.RemoveAll(dir)
+
+	w, err := Read(dir)
+	require.NoError(t, err)
+	assert.Equal(t, localUUID, w.UUID.String())
+	assert.Equal(t, 31302, w.OpVersion)
+
+	require.Len(t, w.Peers, 1)
+	assert.Equal(t, peerUUID, w.Peers[0].UUID.String())
+	assert.Equal(t, []string{"server2", "10.0.0.2"}, w.Peers[0].Hostnames)
+
+	require.Len(t, w.Volumes, 1)
+	v := w.Volumes[0]
+	assert.Equal(t, "gv0", v.Name)
+	assert.Equal(t, volumeID, v.ID.String())
+	assert.Equal(t, TypeReplicate, v.Type)
+	assert.Equal(t, StatusStarted, v.Status)
+	assert.Equal(t, 3, v.ReplicaCount)
+	assert.Equal(t, 1, v.ArbiterCount)
+	assert.Equal(t, "user", v.Username)
+	assert.Equal(t, "secret", v.Password)
+	assert.Equal(t, map[string]string{
+		"transport.address-family":  "inet",
+		"performance.readdir-ahead": "on",
+		"nfs.disable":               "on",
+	}, v.Options)
+
+	require.Len(t, v.Bricks, 6)
+	assert.Equal(t, Brick{Hostname: "server1", Path: "/bricks/b0"}, v.Bricks[0])
+	assert.Equal(t, "/bricks/b1", v.Bricks[1].Path)
+	assert.Equal(t, peerUUID, v.Bricks[1].PeerUUID.String())
+}
+
+func TestReadInvalid(t *testing.T) {
+	dir := testWorkdir(t)
+	defer os.RemoveAll(dir)
+
+	writeFile(t, filepath.Join(dir, "vols", "gv0", "bricks", "server1:-bricks-b0"), "path=/bricks/b0\n")
+	_, err := Read(dir)
+	assert.Error(t, err)
+
+	_, err = Read(filepath.Join(dir, "missing"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestExportReplicate(t *testing.T) {
+	dir := testWorkdir(t)
+	defer os.RemoveAll(dir)
+
+	w, err := Read(dir)
+	require.NoError(t, err)
+
+	e, err := w.Volumes[0].Export()
+	require.NoError(t, err)
+	assert.Equal(t, api.VolumeExportVersion, e.Version)
+	assert.Equal(t, volumeID, e.ID.String())
+	assert.Equal(t, api.DistReplicate, e.Type)
+	assert.Equal(t, "tcp", e.Transport)
+	assert.Equal(t, 2, e.DistCount)
+	assert.Equal(t, "1", e.Options["replicate.arbiter-count"])
+	assert.Equal(t, "on", e.Options["nfs.disable"])
+
+	require.Len(t, e.Subvols, 2)
+	for i, s := range e.Subvols {
+		assert.Equal(t, api.SubvolReplicate, s.Type)
+		assert.Equal(t, 2, s.ReplicaCount)
+		assert.Equal(t, 1, s.ArbiterCount)
+		require.Len(t, s.Bricks, 3)
+		assert.Equal(t, api.Brick, s.Bricks[0].Type)
+		assert.Equal(t, api.Brick, s.Bricks[1].Type)
+		assert.Equal(t, api.Arbiter, s.Bricks[2].Type)
+		assert.Equal(t, "/bricks/b"+strconv.Itoa(3*i), s.Bricks[0].Path)
+	}
+	assert.Equal(t, "gv0-replicate-1", e.Subvols[1].Name)
+	assert.Equal(t, "server2", e.Subvols[0].Bricks[1].Hostname)
+	assert.Equal(t, peerUUID, e.Subvols[0].Bricks[1].PeerID.String())
+	assert.Nil(t, e.Subvols[0].Bricks[0].PeerID)
+}
+
+func TestExport(t *testing.T) {
+	bricks := func(n int) []Brick {
+		var b []Brick
+		for i := 0; i < n; i++ {
+			b = append(b, Brick{Hostname: "server1", Path: "/bricks/b" + strconv.Itoa(i)})
+		}
+		return b
+	}
+
+	e, err := (&Volume{Name: "dist", ID: uuid.NewRandom(), Bricks: bricks(3)}).Export()
+	require.NoError(t, err)
+	assert.Equal(t, api.Distribute, e.Type)
+	require.Len(t, e.Subvols, 3)
+	assert.Equal(t, api.SubvolDistribute, e.Subvols[0].Type)
+	assert.Equal(t, 1, e.Subvols[0].ReplicaCount)
+
+	e, err = (&Volume{
+		Name:            "ec",
+		ID:              uuid.NewRandom(),
+		Type:            TypeDisperse,
+		DisperseCount:   6,
+		RedundancyCount: 2,
+		Bricks:          bricks(6),
+	}).Export()
+	require.NoError(t, err)
+	assert.Equal(t, api.Disperse, e.Type)
+	require.Len(t, e.Subvols, 1)
+	assert.Equal(t, 6, e.Subvols[0].DisperseCount)
+	assert.Equal(t, 2, e.Subvols[0].RedundancyCount)
+
+	for _, v := range []Volume{
+		{Name: "empty", Type: TypeNone},
+		{Name: "tier", Type: TypeTier, Bricks: bricks(2)},
+		{Name: "rdma", Type: TypeNone, Transport: TransportRDMA, Bricks: bricks(2)},
+		{Name: "uneven", Type: TypeReplicate, ReplicaCount: 3, Bricks: bricks(4)},
+		{Name: "arbiter", Type: TypeReplicate, ReplicaCount: 2, ArbiterCount: 1, Bricks: bricks(2)},
+	} {
+		_, err := v.Export()
+		assert.Error(t, err, v.Name)
+	}
+}
@@ -96,6 +96,38 @@ func GetVgAvailableSize(vgname string) (uint64, uint64, error) {
 	return extentSize * freeExtents, extentSize, nil
 }
 
+// GetVgSize gets the total size of given Vg
+func GetVgSize(vgname string) (uint64, error) {
+	out, err := exec.Command("vgdisplay", "-c", "--readonly", vgname).Output()
+	if err != nil {
+		return 0, err
+	}
+	vgdata := strings.Split(strings.TrimRight(string(out), "\n"), ":")
+
+	if len(vgdata) != 17 {
+		return 0, errors.New("failed to get size of VG: " + vgname)
+	}
+
+	// Physical extent size index is 12
+	extentSize, err := strconv.ParseUint(vgdata[12], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	// Total Extents index is 13
+	totalExtents, err := strconv.ParseUint(vgdata[13], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return extentSize * utils.KiB * totalExtents, nil
+}
+
+//ResizePV is used to grow the physical volume to the size of the device
+func ResizePV(device string) error {
+	return utils.ExecuteCommandRun("pvresize", device)
+}
+
 // GetPoolMetadataSize calculates the thin pool metadata size based on the given thin pool size
 func GetPoolMetadataSize(poolsize uint64) uint64 {
 	// https://access.redhat.com/documentation/en-us/red_hat_gluster_storage/3.3/html-single/administration_guide/#Brick_Configuration
@@ -240,21 +272,31 @@ func FsCompatibleCheck(fsName string) bool {
 
 //SizeCompatibleCheck check for lvm compatibility for a path
 func SizeCompatibleCheck(fsName string) bool {
-	data, err := GetLvsData(fsName)
+	used, err := GetPoolDataPercentage(fsName)
 	if err != nil {
 		return false
 	}
+	if used >= float32(MaxSizePercentage) {
+		return false
+	}
+
+	return true
+}
+
+// GetPoolDataPercentage returns the percentage of the data of the thin pool
+// of the given thin LV which is in use
+func GetPoolDataPercentage(fsName string) (float32, error) {
+	data, err := GetLvsData(fsName)
+	if err != nil {
+		return 0, err
+	}
 
 	thinPool := fmt.Sprintf("/dev/%s/%s", data.VgName, data.PoolLV)
 	thinData, err := GetLvsData(thinPool)
 	if err != nil {
-		return false
+		return 0, err
 	}
-	if thinData.DataPercentage >= float32(MaxSizePercentage) {
-		return false
-	}
-
-	return true
+	return thinData.DataPercentage, nil
 }
 
 //GetVgName creates the device path for lvm snapshot
@@ -355,6 +397,35 @@ func ExtendThinpool(expansionTpSizePerBrick uint64, vgName string, tpName string
 	return err
 }
 
+// GetThinpoolUsage returns the size of the given thin pool and the bytes
+// used in it
+func GetThinpoolUsage(vgname, tpname string) (uint64, uint64, error) {
+	out, err := utils.ExecuteCommandOutput(
+		"lvs", "--no-headings", "--readonly", "--units", "b", "--nosuffix",
+		"--separator", ":", "-o", "lv_size,data_percent",
+		vgname+"/"+tpname,
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	data := strings.Split(strings.TrimSpace(string(out)), ":")
+	if len(data) != 2 {
+		return 0, 0, errors.New("failed to get usage of thin pool: " + vgname + "/" + tpname)
+	}
+
+	size, err := strconv.ParseUint(strings.TrimSpace(data[0]), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	dataPercentage, err := strconv.ParseFloat(strings.TrimSpace(data[1]), 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return size, uint64(float64(size) * dataPercentage / 100), nil
+}
+
 // NormalizeSize converts the value to multiples of 512
 func NormalizeSize(size uint64) uint64 {
 	rem := size % 512
@@ -0,0 +1,21 @@
+package restclient
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// AuditRecords returns the audit records of the mutating requests served by
+// the peers, optionally filtered by the user, method, path, outcome, peer,
+// since and until query parameters
+func (c *Client) AuditRecords(filters map[string]string) (api.AuditListResp, error) {
+	var records api.AuditListResp
+	values := url.Values{}
+	for k, v := range filters {
+		values.Set(k, v)
+	}
+	err := c.get("/v1/audit?"+values.Encode(), nil, http.StatusOK, &records)
+	return records, err
+}
@@ -0,0 +1,30 @@
+package restclient
+
+import (
+	"io"
+	"net/http"
+)
+
+// ClusterBackup takes a backup of the cluster metadata kept in the store and
+// writes the gzip compressed archive to w
+func (c *Client) ClusterBackup(w io.Writer) error {
+	req, err := c.buildRequest("POST", "/v1/cluster/backup", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/gzip")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.lastRespErr = resp
+		return newHTTPErrorResponse(resp)
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
@@ -32,3 +32,9 @@ func (c *Client) BitrotScrubStatus(volname string) (bitrotapi.ScrubStatus, error
 	err := c.get(url, nil, http.StatusOK, &scrubStatus)
 	return scrubStatus, err
 }
+
+// BitrotScrubConfig changes the scrubber settings of a volume
+func (c *Client) BitrotScrubConfig(volname string, req bitrotapi.ScrubConfigReq) error {
+	url := fmt.Sprintf("/v1/volumes/%s/bitrot/config", volname)
+	return c.post(url, req, http.StatusOK, nil)
+}
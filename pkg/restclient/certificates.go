@@ -0,0 +1,23 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// Certificates returns the SSL/TLS certificates in use by the peer serving
+// the request
+func (c *Client) Certificates() (api.CertificatesResp, error) {
+	var resp api.CertificatesResp
+	err := c.get("/v1/certificates", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// CertificatesReload makes all the peers load again their SSL/TLS
+// certificates from their files
+func (c *Client) CertificatesReload() (api.CertificatesReloadResp, error) {
+	var resp api.CertificatesReloadResp
+	err := c.post("/v1/certificates/reload", nil, http.StatusOK, &resp)
+	return resp, err
+}
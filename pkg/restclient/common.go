@@ -2,10 +2,12 @@ package restclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"time"
 
@@ -56,6 +58,25 @@ func WithBaseURL(url string) ClientFunc {
 	}
 }
 
+// WithUnixSocket makes the Client send its requests to the local REST API
+// of glusterd2 on the Unix socket at path. It replaces the Transport of the
+// http Client, so it must be given before WithDebugRoundTripper.
+func WithUnixSocket(path string) ClientFunc {
+	return func(client *Client) error {
+		dialer := &net.Dialer{}
+		client.httpClient.Transport = &http.Transport{
+			DisableCompression: true,
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", path)
+			},
+		}
+		if client.baseURL == "" {
+			client.baseURL = "http://localhost"
+		}
+		return nil
+	}
+}
+
 // WithUsername overrides Client username with specified one
 func WithUsername(username string) ClientFunc {
 	return func(client *Client) error {
@@ -72,6 +93,24 @@ func WithPassword(password string) ClientFunc {
 	}
 }
 
+// WithRole makes the Client ask for the given role in its tokens, which must
+// not be more privileged than the role of its user
+func WithRole(role string) ClientFunc {
+	return func(client *Client) error {
+		client.role = role
+		return nil
+	}
+}
+
+// WithNamespace binds the tokens of the Client to the given namespace, which
+// must be one of the namespaces of its user
+func WithNamespace(namespace string) ClientFunc {
+	return func(client *Client) error {
+		client.namespace = namespace
+		return nil
+	}
+}
+
 // WithTimeOut overrides Client timeout with specified one
 func WithTimeOut(timeout time.Duration) ClientFunc {
 	return func(client *Client) error {
@@ -93,9 +132,12 @@ type Client struct {
 	baseURL     string
 	username    string
 	password    string
+	role        string
+	namespace   string
 	timeout     time.Duration
 	httpClient  *http.Client
 	lastRespErr *http.Response
+	idemKey     string
 }
 
 // NewClientWithOpts initializes a default Glusterd2 REST Client.
@@ -139,6 +181,17 @@ func New(baseURL, username, password, cacert string, insecure bool) (*Client, er
 	)
 }
 
+// WithIdempotencyKey returns a copy of the client which sends the given key in
+// the Idempotency-Key header of its requests. Retrying a request with the same
+// key returns the result of the original request instead of executing it
+// again.
+func (c *Client) WithIdempotencyKey(key string) *Client {
+	client := *c
+	client.idemKey = key
+	client.lastRespErr = nil
+	return &client
+}
+
 // LastErrorResponse returns the last error response received by this
 // client from glusterd2. Please note that the Body of the response has
 // been read and drained.
@@ -164,6 +217,12 @@ func (c *Client) setAuthToken(r *http.Request) {
 		// Set qsh
 		"qsh": utils.GenerateQsh(r),
 	})
+	if c.role != "" {
+		token.Claims.(jwt.MapClaims)["role"] = c.role
+	}
+	if c.namespace != "" {
+		token.Claims.(jwt.MapClaims)["namespace"] = c.namespace
+	}
 	// Sign the token
 	signedtoken, err := token.SignedString([]byte(c.password))
 	if err != nil {
@@ -181,6 +240,10 @@ func (c *Client) put(url string, data interface{}, expectStatusCode int, output
 	return c.do("PUT", url, data, expectStatusCode, output)
 }
 
+func (c *Client) patch(url string, data interface{}, expectStatusCode int, output interface{}) error {
+	return c.do("PATCH", url, data, expectStatusCode, output)
+}
+
 func (c *Client) get(url string, data interface{}, expectStatusCode int, output interface{}) error {
 	return c.do("GET", url, data, expectStatusCode, output)
 }
@@ -242,6 +305,10 @@ func (c *Client) buildRequest(method string, url string, input interface{}) (*ht
 	req.Header.Set("Content-Type", "application/json")
 	req.Close = true
 
+	if c.idemKey != "" {
+		req.Header.Set("Idempotency-Key", c.idemKey)
+	}
+
 	// Set Authorization if username and password is not empty string
 	if c.username != "" && c.password != "" {
 		c.setAuthToken(req)
@@ -0,0 +1,102 @@
+package restclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	csiapi "github.com/gluster/glusterd2/plugins/csi/api"
+)
+
+// csiCreate creates a volume or snapshot. The response has the status code
+// 201 if it was created and 200 if it already existed, both are successful.
+func (c *Client) csiCreate(url string, data interface{}, output interface{}) error {
+	req, err := c.buildRequest("POST", url, data)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		c.lastRespErr = resp
+		return newHTTPErrorResponse(resp)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, output)
+}
+
+// CsiVolumeCreate creates a volume with the given name, or returns the volume
+// with that name if it already exists and is compatible with the request
+func (c *Client) CsiVolumeCreate(req csiapi.VolumeCreateReq) (csiapi.Volume, error) {
+	var vol csiapi.Volume
+	err := c.csiCreate("/v1/csi/volumes", req, &vol)
+	return vol, err
+}
+
+// CsiVolumeList lists the volumes
+func (c *Client) CsiVolumeList() (csiapi.VolumeListResp, error) {
+	var vols csiapi.VolumeListResp
+	err := c.get("/v1/csi/volumes", nil, http.StatusOK, &vols)
+	return vols, err
+}
+
+// CsiVolumeInfo returns a volume
+func (c *Client) CsiVolumeInfo(volname string) (csiapi.Volume, error) {
+	var vol csiapi.Volume
+	url := fmt.Sprintf("/v1/csi/volumes/%s", volname)
+	err := c.get(url, nil, http.StatusOK, &vol)
+	return vol, err
+}
+
+// CsiVolumeExpand expands a volume to at least the required capacity
+func (c *Client) CsiVolumeExpand(volname string, req csiapi.VolumeExpandReq) (csiapi.Volume, error) {
+	var vol csiapi.Volume
+	url := fmt.Sprintf("/v1/csi/volumes/%s/expand", volname)
+	err := c.post(url, req, http.StatusOK, &vol)
+	return vol, err
+}
+
+// CsiVolumeDelete stops and deletes a volume. Deleting a volume which does
+// not exist succeeds.
+func (c *Client) CsiVolumeDelete(volname string) error {
+	url := fmt.Sprintf("/v1/csi/volumes/%s", volname)
+	return c.del(url, nil, http.StatusNoContent, nil)
+}
+
+// CsiSnapshotCreate takes a snapshot with the given name of the source
+// volume, or returns the snapshot with that name if it already exists
+func (c *Client) CsiSnapshotCreate(req csiapi.SnapshotCreateReq) (csiapi.Snapshot, error) {
+	var snap csiapi.Snapshot
+	err := c.csiCreate("/v1/csi/snapshots", req, &snap)
+	return snap, err
+}
+
+// CsiSnapshotList lists the snapshots, of the source volume if it is not
+// empty
+func (c *Client) CsiSnapshotList(source string) (csiapi.SnapshotListResp, error) {
+	var snaps csiapi.SnapshotListResp
+	path := "/v1/csi/snapshots"
+	if source != "" {
+		path += "?" + url.Values{"source-volume": []string{source}}.Encode()
+	}
+	err := c.get(path, nil, http.StatusOK, &snaps)
+	return snaps, err
+}
+
+// CsiSnapshotDelete deletes a snapshot. Deleting a snapshot which does not
+// exist succeeds.
+func (c *Client) CsiSnapshotDelete(snapname string) error {
+	url := fmt.Sprintf("/v1/csi/snapshots/%s", snapname)
+	return c.del(url, nil, http.StatusNoContent, nil)
+}
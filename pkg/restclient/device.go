@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/gluster/glusterd2/pkg/api"
 	deviceapi "github.com/gluster/glusterd2/plugins/device/api"
 )
 
@@ -19,6 +20,13 @@ func (c *Client) DeviceAdd(peerid, device, provType string) (deviceapi.AddDevice
 	return deviceinfo, err
 }
 
+// DeviceBulkAdd adds multiple devices of any peers with a single request
+func (c *Client) DeviceBulkAdd(req deviceapi.BulkAddDeviceReq) (deviceapi.BulkAddDeviceResp, error) {
+	var resp deviceapi.BulkAddDeviceResp
+	err := c.post("/v1/devices/bulk", req, http.StatusOK, &resp)
+	return resp, err
+}
+
 // DeviceList lists the devices
 func (c *Client) DeviceList(peerid, device string) ([]deviceapi.Info, error) {
 	var deviceList deviceapi.ListDeviceResp
@@ -34,6 +42,15 @@ func (c *Client) DeviceList(peerid, device string) ([]deviceapi.Info, error) {
 	return deviceList, err
 }
 
+// DevicesAvailable lists the block devices of a peer which can be
+// registered as devices
+func (c *Client) DevicesAvailable(peerid string) (deviceapi.AvailableDevicesResp, error) {
+	var devices deviceapi.AvailableDevicesResp
+	url := fmt.Sprintf("/v1/peers/%s/devices/available", peerid)
+	err := c.get(url, nil, http.StatusOK, &devices)
+	return devices, err
+}
+
 // DeviceEdit edits device
 func (c *Client) DeviceEdit(peerid, device, state string) error {
 	req := deviceapi.EditDeviceReq{
@@ -44,3 +61,40 @@ func (c *Client) DeviceEdit(peerid, device, state string) error {
 	err := c.post(url, req, http.StatusOK, nil)
 	return err
 }
+
+// DeviceRemove removes a device which has no bricks on it
+func (c *Client) DeviceRemove(peerid, device string) error {
+	device = strings.TrimLeft(device, "/")
+	url := fmt.Sprintf("/v1/devices/%s/%s", peerid, device)
+	return c.del(url, nil, http.StatusNoContent, nil)
+}
+
+// DeviceRemoveMigrate migrates the bricks on a device to other devices and
+// removes the device. It returns the job which runs the migration.
+func (c *Client) DeviceRemoveMigrate(peerid, device string) (api.JobGetResp, error) {
+	var job api.JobGetResp
+	device = strings.TrimLeft(device, "/")
+	url := fmt.Sprintf("/v1/devices/%s/%s?migrate=true", peerid, device)
+	err := c.del(url, nil, http.StatusAccepted, &job)
+	return job, err
+}
+
+// DeviceDrain disables a device and migrates the bricks on it to other
+// devices. It returns the job which runs the migration.
+func (c *Client) DeviceDrain(peerid, device string) (api.JobGetResp, error) {
+	var job api.JobGetResp
+	device = strings.TrimLeft(device, "/")
+	url := fmt.Sprintf("/v1/devices/%s/%s/drain", peerid, device)
+	err := c.post(url, nil, http.StatusAccepted, &job)
+	return job, err
+}
+
+// DeviceResize updates the size of a device after the underlying disk has
+// grown
+func (c *Client) DeviceResize(peerid, device string) (deviceapi.Info, error) {
+	var deviceinfo deviceapi.Info
+	device = strings.TrimLeft(device, "/")
+	url := fmt.Sprintf("/v1/devices/%s/%s/resize", peerid, device)
+	err := c.post(url, nil, http.StatusOK, &deviceinfo)
+	return deviceinfo, err
+}
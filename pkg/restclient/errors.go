@@ -42,6 +42,8 @@ func newHTTPErrorResponse(resp *http.Response) error {
 			buffer.WriteString(fmt.Sprintf(
 				"Transaction step %s failed on peer %s with error: %s\n",
 				apiErr.Fields["step"], apiErr.Fields["peer-id"], apiErr.Fields["error"]))
+		case api.ErrFieldRequired, api.ErrFieldInvalid, api.ErrFieldOutOfRange:
+			buffer.WriteString(fmt.Sprintf("%s: %s\n", apiErr.Field, apiErr.Message))
 		default:
 			buffer.WriteString(apiErr.Message)
 		}
@@ -1,7 +1,11 @@
 package restclient
 
 import (
+	"bufio"
+	"encoding/json"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/gluster/glusterd2/pkg/api"
 	eventsapi "github.com/gluster/glusterd2/plugins/events/api"
@@ -35,8 +39,27 @@ func (c *Client) Webhooks() (eventsapi.WebhookList, error) {
 
 // ListEvents returns the list of Gluster Events
 func (c *Client) ListEvents() ([]*api.Event, error) {
+	return c.ListEventsFiltered(nil)
+}
+
+// ListEventsFiltered returns the list of Gluster Events kept in the store,
+// oldest first, filtered by the type, volume, severity and since query
+// parameters
+func (c *Client) ListEventsFiltered(filters map[string]string) ([]*api.Event, error) {
 	var resp []*api.Event
-	err := c.get("/v1/events", nil, http.StatusOK, &resp)
+	values := url.Values{}
+	for k, v := range filters {
+		values.Set(k, v)
+	}
+	err := c.get("/v1/events?"+values.Encode(), nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// EventTypes returns the types of the events created by glusterd2 and the
+// gluster processes
+func (c *Client) EventTypes() (api.EventTypeList, error) {
+	var resp api.EventTypeList
+	err := c.get("/v1/events/types", nil, http.StatusOK, &resp)
 	return resp, err
 }
 
@@ -49,3 +72,94 @@ func (c *Client) WebhookTest(url string, token string, secret string) error {
 	}
 	return c.post("/v1/events/webhook/test", req, http.StatusOK, nil)
 }
+
+// EventsStream streams the Gluster Events seen by the peer, optionally
+// filtered by the type and volume query parameters, and calls handle for
+// each of them until handle returns false or the stream ends
+func (c *Client) EventsStream(filters map[string]string, handle func(*api.Event) bool) error {
+	values := url.Values{}
+	for k, v := range filters {
+		values.Set(k, v)
+	}
+	req, err := c.buildRequest("GET", "/v1/events/stream?"+values.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// The stream lasts for as long as the caller wants, the timeout of the
+	// client doesn't apply to it
+	httpClient := *c.httpClient
+	httpClient.Timeout = 0
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		c.lastRespErr = resp
+		return newHTTPErrorResponse(resp)
+	}
+
+	var data []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		case line == "" && len(data) > 0:
+			var e api.Event
+			if err := json.Unmarshal([]byte(strings.Join(data, "\n")), &e); err != nil {
+				return err
+			}
+			data = nil
+			if !handle(&e) {
+				return nil
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// WebhookSinkCreate registers a webhook to which the matching Gluster Events
+// are delivered
+func (c *Client) WebhookSinkCreate(req eventsapi.WebhookCreateReq) (eventsapi.WebhookCreateResp, error) {
+	var resp eventsapi.WebhookCreateResp
+	err := c.post("/v1/events/webhooks", req, http.StatusCreated, &resp)
+	return resp, err
+}
+
+// WebhookSinks returns the registered webhooks
+func (c *Client) WebhookSinks() (eventsapi.WebhookInfoList, error) {
+	var resp eventsapi.WebhookInfoList
+	err := c.get("/v1/events/webhooks", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// WebhookSink returns the registered webhook with the given ID
+func (c *Client) WebhookSink(id string) (eventsapi.WebhookGetResp, error) {
+	var resp eventsapi.WebhookGetResp
+	err := c.get("/v1/events/webhooks/"+id, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// WebhookSinkEdit changes the registered webhook with the given ID
+func (c *Client) WebhookSinkEdit(id string, req eventsapi.WebhookEditReq) (eventsapi.WebhookEditResp, error) {
+	var resp eventsapi.WebhookEditResp
+	err := c.post("/v1/events/webhooks/"+id+"/edit", req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// WebhookSinkDelete removes the registered webhook with the given ID
+func (c *Client) WebhookSinkDelete(id string) error {
+	return c.del("/v1/events/webhooks/"+id, nil, http.StatusNoContent, nil)
+}
+
+// WebhookSinkTest delivers a test event to the registered webhook with the
+// given ID
+func (c *Client) WebhookSinkTest(id string) error {
+	return c.post("/v1/events/webhooks/"+id+"/test", nil, http.StatusOK, nil)
+}
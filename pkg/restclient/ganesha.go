@@ -0,0 +1,49 @@
+package restclient
+
+import (
+	"fmt"
+	"net/http"
+
+	ganeshaapi "github.com/gluster/glusterd2/plugins/ganesha/api"
+)
+
+// NfsGaneshaEnable enables NFS-Ganesha on the given peers
+func (c *Client) NfsGaneshaEnable(req ganeshaapi.EnableReq) (ganeshaapi.ClusterInfo, error) {
+	var cluster ganeshaapi.ClusterInfo
+	err := c.post("/v1/nfs-ganesha", req, http.StatusOK, &cluster)
+	return cluster, err
+}
+
+// NfsGaneshaDisable stops NFS-Ganesha on all the peers it is enabled on
+func (c *Client) NfsGaneshaDisable() error {
+	return c.del("/v1/nfs-ganesha", nil, http.StatusNoContent, nil)
+}
+
+// NfsGaneshaStatus returns the state of NFS-Ganesha and the exported volumes
+func (c *Client) NfsGaneshaStatus() (ganeshaapi.StatusResp, error) {
+	var status ganeshaapi.StatusResp
+	err := c.get("/v1/nfs-ganesha", nil, http.StatusOK, &status)
+	return status, err
+}
+
+// NfsGaneshaExport exports a volume over NFS-Ganesha
+func (c *Client) NfsGaneshaExport(volname string, req ganeshaapi.ExportReq) (ganeshaapi.Export, error) {
+	var export ganeshaapi.Export
+	url := fmt.Sprintf("/v1/volumes/%s/nfs-ganesha", volname)
+	err := c.post(url, req, http.StatusCreated, &export)
+	return export, err
+}
+
+// NfsGaneshaUnexport stops exporting a volume over NFS-Ganesha
+func (c *Client) NfsGaneshaUnexport(volname string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/nfs-ganesha", volname)
+	return c.del(url, nil, http.StatusNoContent, nil)
+}
+
+// NfsGaneshaExportInfo returns the export of a volume
+func (c *Client) NfsGaneshaExportInfo(volname string) (ganeshaapi.Export, error) {
+	var export ganeshaapi.Export
+	url := fmt.Sprintf("/v1/volumes/%s/nfs-ganesha", volname)
+	err := c.get(url, nil, http.StatusOK, &export)
+	return export, err
+}
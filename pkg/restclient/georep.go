@@ -104,6 +104,15 @@ func (c *Client) GeorepSSHKeysPush(volname string, sshkeys []georepapi.GeorepSSH
 	return c.post(url, sshkeys, http.StatusOK, nil)
 }
 
+// GeorepSSHKeysDistribute generates SSH keys in all Volume nodes and pushes
+// them to all nodes of the Remote Volume through the Remote cluster
+func (c *Client) GeorepSSHKeysDistribute(volname string, req georepapi.GeorepSSHKeyDistributeReq) ([]georepapi.GeorepSSHPublicKey, error) {
+	url := "/v1/ssh-key/" + volname + "/distribute"
+	var sshkeys []georepapi.GeorepSSHPublicKey
+	err := c.post(url, req, http.StatusOK, &sshkeys)
+	return sshkeys, err
+}
+
 // GeorepGet gets Geo-replication options
 func (c *Client) GeorepGet(mastervolid string, slavevolid string) ([]georepapi.GeorepOption, error) {
 	var options []georepapi.GeorepOption
@@ -0,0 +1,15 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// Glusterd1Import imports the peers and volumes of a glusterd1 cluster from
+// the working directory of glusterd1 on the peer serving the request
+func (c *Client) Glusterd1Import(req api.Glusterd1ImportReq) (api.Glusterd1ImportResp, error) {
+	var resp api.Glusterd1ImportResp
+	err := c.post("/v1/glusterd1/import", req, http.StatusOK, &resp)
+	return resp, err
+}
@@ -39,6 +39,18 @@ func (c *Client) SelfHeal(volname string, healType string) error {
 	return c.post(url, nil, http.StatusOK, nil)
 }
 
+// SelfHealEnable turns on the self-heal daemon of the volume
+func (c *Client) SelfHealEnable(volname string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/heal-enable", volname)
+	return c.post(url, nil, http.StatusOK, nil)
+}
+
+// SelfHealDisable turns off the self-heal daemon of the volume
+func (c *Client) SelfHealDisable(volname string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/heal-disable", volname)
+	return c.post(url, nil, http.StatusOK, nil)
+}
+
 // SelfHealSplitBrain sends request to start split-brain operations on a volume
 func (c *Client) SelfHealSplitBrain(volname, operation string, req shdapi.SplitBrainReq) error {
 	var url string
@@ -62,3 +74,12 @@ func (c *Client) SelfHealSplitBrain(volname, operation string, req shdapi.SplitB
 	}
 	return c.post(url, req, http.StatusOK, nil)
 }
+
+// SplitBrainResolve resolves the split-brain of files of a volume as per the
+// policies in the request
+func (c *Client) SplitBrainResolve(volname string, req shdapi.SplitBrainResolveReq) (shdapi.SplitBrainResolveResp, error) {
+	var resp shdapi.SplitBrainResolveResp
+	url := fmt.Sprintf("/v1/volumes/%s/heal/split-brain", volname)
+	err := c.post(url, req, http.StatusOK, &resp)
+	return resp, err
+}
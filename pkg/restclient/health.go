@@ -0,0 +1,19 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// Health returns the health of the peer, or of all the peers if cluster is
+// true. An error is returned if the peer or the cluster has failed.
+func (c *Client) Health(cluster bool) (api.HealthResp, error) {
+	var resp api.HealthResp
+	url := "/v1/health"
+	if cluster {
+		url += "?level=cluster"
+	}
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
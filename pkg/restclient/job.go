@@ -0,0 +1,34 @@
+package restclient
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// Jobs returns the jobs which are queued, running or have recently ended.
+// The jobs can be filtered by volume, type and state with the keys of the
+// same name in filters, and paged with the "offset", "limit" and "continue"
+// keys.
+func (c *Client) Jobs(filters map[string]string) (api.JobListResp, error) {
+	var jobs api.JobListResp
+	values := url.Values{}
+	for k, v := range filters {
+		values.Set(k, v)
+	}
+	err := c.get("/v1/jobs?"+values.Encode(), nil, http.StatusOK, &jobs)
+	return jobs, err
+}
+
+// Job returns the progress of the job with the given ID
+func (c *Client) Job(jobID string) (api.JobGetResp, error) {
+	var job api.JobGetResp
+	err := c.get("/v1/jobs/"+jobID, nil, http.StatusOK, &job)
+	return job, err
+}
+
+// JobCancel requests the cancellation of a job
+func (c *Client) JobCancel(jobID string) error {
+	return c.post("/v1/jobs/"+jobID+"/cancel", nil, http.StatusAccepted, nil)
+}
@@ -0,0 +1,22 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// Maintenance returns the maintenance mode of the cluster
+func (c *Client) Maintenance() (api.MaintenanceResp, error) {
+	var resp api.MaintenanceResp
+	err := c.get("/v1/maintenance", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// MaintenanceSet puts the cluster in maintenance mode, in which requests
+// changing the cluster are rejected, or takes it out of maintenance mode
+func (c *Client) MaintenanceSet(req api.MaintenanceReq) (api.MaintenanceResp, error) {
+	var resp api.MaintenanceResp
+	err := c.post("/v1/maintenance", req, http.StatusOK, &resp)
+	return resp, err
+}
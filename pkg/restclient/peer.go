@@ -20,6 +20,15 @@ func (c *Client) PeerRemove(peerid string) error {
 	return c.del(delURL, nil, http.StatusNoContent, nil)
 }
 
+// PeerRemoveMigrate moves the bricks of a peer to other peers and removes
+// the peer from the Cluster. It returns the job which runs the migration.
+func (c *Client) PeerRemoveMigrate(peerid string) (api.JobGetResp, error) {
+	var job api.JobGetResp
+	delURL := fmt.Sprintf("/v1/peers/%s?migrate-bricks=true", peerid)
+	err := c.del(delURL, nil, http.StatusAccepted, &job)
+	return job, err
+}
+
 // GetPeer returns information about a peer
 func (c *Client) GetPeer(peerid string) (api.PeerGetResp, error) {
 	var peer api.PeerGetResp
@@ -37,3 +46,37 @@ func (c *Client) Peers(filterParams ...map[string]string) (api.PeerListResp, err
 	err := c.get("/v1/peers"+queryString, nil, http.StatusOK, &peers)
 	return peers, err
 }
+
+// PeerPatch changes the zone, rack or tags of a peer
+func (c *Client) PeerPatch(peerid string, req api.PeerPatchReq) (api.PeerGetResp, error) {
+	var peer api.PeerGetResp
+	url := fmt.Sprintf("/v1/peers/%s", peerid)
+	err := c.patch(url, req, http.StatusOK, &peer)
+	return peer, err
+}
+
+// PeerMaintenanceStart puts a peer under maintenance
+func (c *Client) PeerMaintenanceStart(peerid, reason string) (api.PeerGetResp, error) {
+	var peer api.PeerGetResp
+	req := api.PeerMaintenanceReq{Reason: reason}
+	url := fmt.Sprintf("/v1/peers/%s/maintenance", peerid)
+	err := c.post(url, req, http.StatusOK, &peer)
+	return peer, err
+}
+
+// PeerMaintenanceStop ends the maintenance of a peer
+func (c *Client) PeerMaintenanceStop(peerid string) (api.PeerGetResp, error) {
+	var peer api.PeerGetResp
+	url := fmt.Sprintf("/v1/peers/%s/maintenance", peerid)
+	err := c.del(url, nil, http.StatusOK, &peer)
+	return peer, err
+}
+
+// PeerReplace replaces a failed peer by another peer, moving its bricks to
+// the new peer. It returns the job which replaces the bricks.
+func (c *Client) PeerReplace(peerid string, req api.PeerReplaceReq) (api.JobGetResp, error) {
+	var job api.JobGetResp
+	url := fmt.Sprintf("/v1/peers/%s/replace", peerid)
+	err := c.post(url, req, http.StatusAccepted, &job)
+	return job, err
+}
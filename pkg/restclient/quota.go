@@ -3,10 +3,50 @@ package restclient
 import (
 	"fmt"
 	"net/http"
+
+	quotaapi "github.com/gluster/glusterd2/plugins/quota/api"
 )
 
-// QuotaEnable starts a Gluster Volume
+// QuotaEnable enables quota on the volume
 func (c *Client) QuotaEnable(volname string) error {
-	url := fmt.Sprintf("/v1/quota/%s", volname)
+	url := fmt.Sprintf("/v1/volumes/%s/quota/enable", volname)
+	return c.post(url, nil, http.StatusOK, nil)
+}
+
+// QuotaDisable disables quota on the volume
+func (c *Client) QuotaDisable(volname string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/quota/disable", volname)
 	return c.post(url, nil, http.StatusOK, nil)
 }
+
+// QuotaLimits returns the usage of the directories of the volume which have
+// size limits set
+func (c *Client) QuotaLimits(volname string) (quotaapi.ListResp, error) {
+	var resp quotaapi.ListResp
+	url := fmt.Sprintf("/v1/volumes/%s/quota/limits", volname)
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// QuotaObjectLimits returns the number of files and directories under the
+// directories of the volume which have object count limits set
+func (c *Client) QuotaObjectLimits(volname string) (quotaapi.ObjectListResp, error) {
+	var resp quotaapi.ObjectListResp
+	url := fmt.Sprintf("/v1/volumes/%s/quota/objects", volname)
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// QuotaSetLimit sets the limits of a directory of the volume
+func (c *Client) QuotaSetLimit(volname string, req quotaapi.SetLimitReq) (quotaapi.Limit, error) {
+	var limit quotaapi.Limit
+	url := fmt.Sprintf("/v1/volumes/%s/quota/limits", volname)
+	err := c.post(url, req, http.StatusOK, &limit)
+	return limit, err
+}
+
+// QuotaRemoveLimit removes the limits of a directory of the volume
+func (c *Client) QuotaRemoveLimit(volname, path string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/quota/limits", volname)
+	return c.del(url, quotaapi.RemoveLimitReq{Path: path}, http.StatusNoContent, nil)
+}
@@ -0,0 +1,42 @@
+package restclient
+
+import (
+	"fmt"
+	"net/http"
+
+	rebalanceapi "github.com/gluster/glusterd2/plugins/rebalance/api"
+
+	"github.com/pborman/uuid"
+)
+
+// RebalanceStart starts rebalance on the volume and returns the ID of the
+// rebalance run
+func (c *Client) RebalanceStart(volname string, req rebalanceapi.StartReq) (uuid.UUID, error) {
+	var id uuid.UUID
+	url := fmt.Sprintf("/v1/volumes/%s/rebalance/start", volname)
+	err := c.post(url, req, http.StatusOK, &id)
+	return id, err
+}
+
+// RebalanceStop stops the rebalance running on the volume
+func (c *Client) RebalanceStop(volname string) (rebalanceapi.RebalInfo, error) {
+	var rinfo rebalanceapi.RebalInfo
+	url := fmt.Sprintf("/v1/volumes/%s/rebalance/stop", volname)
+	err := c.post(url, nil, http.StatusOK, &rinfo)
+	return rinfo, err
+}
+
+// RebalanceStatus returns the status of the rebalance of the volume
+func (c *Client) RebalanceStatus(volname string) (rebalanceapi.RebalStatus, error) {
+	var status rebalanceapi.RebalStatus
+	url := fmt.Sprintf("/v1/volumes/%s/rebalance", volname)
+	err := c.get(url, nil, http.StatusOK, &status)
+	return status, err
+}
+
+// RebalanceThrottle changes the throttle level of the rebalance running on
+// the volume
+func (c *Client) RebalanceThrottle(volname, throttle string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/rebalance/throttle", volname)
+	return c.post(url, rebalanceapi.ThrottleReq{Throttle: throttle}, http.StatusOK, nil)
+}
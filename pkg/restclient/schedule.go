@@ -0,0 +1,33 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// ScheduleCreate schedules an operation on a volume
+func (c *Client) ScheduleCreate(req api.ScheduleCreateReq) (api.ScheduleCreateResp, error) {
+	var schedule api.ScheduleCreateResp
+	err := c.post("/v1/schedules", req, http.StatusCreated, &schedule)
+	return schedule, err
+}
+
+// Schedules returns all the scheduled operations
+func (c *Client) Schedules() (api.ScheduleListResp, error) {
+	var schedules api.ScheduleListResp
+	err := c.get("/v1/schedules", nil, http.StatusOK, &schedules)
+	return schedules, err
+}
+
+// Schedule returns the scheduled operation with the given name
+func (c *Client) Schedule(name string) (api.ScheduleGetResp, error) {
+	var schedule api.ScheduleGetResp
+	err := c.get("/v1/schedules/"+name, nil, http.StatusOK, &schedule)
+	return schedule, err
+}
+
+// ScheduleDelete removes the scheduled operation with the given name
+func (c *Client) ScheduleDelete(name string) error {
+	return c.del("/v1/schedules/"+name, nil, http.StatusNoContent, nil)
+}
@@ -0,0 +1,36 @@
+package restclient
+
+import (
+	"fmt"
+	"net/http"
+
+	smbapi "github.com/gluster/glusterd2/plugins/smb/api"
+)
+
+// SmbEnable shares the volumes with user.smb enabled over SMB from the given
+// peers
+func (c *Client) SmbEnable(req smbapi.EnableReq) (smbapi.ClusterInfo, error) {
+	var cluster smbapi.ClusterInfo
+	err := c.post("/v1/smb", req, http.StatusOK, &cluster)
+	return cluster, err
+}
+
+// SmbDisable removes the shares of the volumes from all the peers
+func (c *Client) SmbDisable() error {
+	return c.del("/v1/smb", nil, http.StatusNoContent, nil)
+}
+
+// SmbStatus returns the peers volumes are shared from and the shares
+func (c *Client) SmbStatus() (smbapi.StatusResp, error) {
+	var status smbapi.StatusResp
+	err := c.get("/v1/smb", nil, http.StatusOK, &status)
+	return status, err
+}
+
+// SmbShareInfo returns the SMB share of a volume
+func (c *Client) SmbShareInfo(volname string) (smbapi.Share, error) {
+	var share smbapi.Share
+	url := fmt.Sprintf("/v1/volumes/%s/smb", volname)
+	err := c.get(url, nil, http.StatusOK, &share)
+	return share, err
+}
@@ -14,6 +14,14 @@ func (c *Client) SnapshotCreate(req api.SnapCreateReq) (api.SnapCreateResp, erro
 	return snap, err
 }
 
+// SnapshotCreateAsync starts creating a Gluster snapshot and returns the job
+// doing it without waiting for it to end
+func (c *Client) SnapshotCreateAsync(req api.SnapCreateReq) (api.JobGetResp, error) {
+	var job api.JobGetResp
+	err := c.post("/v1/snapshots?async=true", req, http.StatusAccepted, &job)
+	return job, err
+}
+
 //SnapshotActivate activate a Gluster snapshot
 func (c *Client) SnapshotActivate(req api.SnapActivateReq, snapname string) error {
 	url := fmt.Sprintf("/v1/snapshots/%s/activate", snapname)
@@ -72,6 +80,25 @@ func (c *Client) SnapshotRestore(snapname string) (api.VolumeGetResp, error) {
 	return resp, err
 }
 
+// SnapshotRestoreAsNewVolume creates a new volume with the given name from
+// the snapshot and starts it, leaving the parent volume of the snapshot as
+// it is
+func (c *Client) SnapshotRestoreAsNewVolume(snapname, volname string) (api.VolumeGetResp, error) {
+	var resp api.VolumeGetResp
+	url := fmt.Sprintf("/v1/snapshots/%s/restore?as-new-volume=%s", snapname, volname)
+	err := c.post(url, nil, http.StatusCreated, &resp)
+	return resp, err
+}
+
+// SnapshotRestoreAsync starts restoring the volume to the given snapshot and
+// returns the job doing it without waiting for it to end
+func (c *Client) SnapshotRestoreAsync(snapname string) (api.JobGetResp, error) {
+	var job api.JobGetResp
+	url := fmt.Sprintf("/v1/snapshots/%s/restore?async=true", snapname)
+	err := c.post(url, nil, http.StatusAccepted, &job)
+	return job, err
+}
+
 // SnapshotClone creates a writable Gluster Snapshot, it will be similar to a volume
 func (c *Client) SnapshotClone(snapname string, req api.SnapCloneReq) (api.VolumeCreateResp, error) {
 	var vol api.VolumeCreateResp
@@ -79,3 +106,45 @@ func (c *Client) SnapshotClone(snapname string, req api.SnapCloneReq) (api.Volum
 	err := c.post(url, req, http.StatusCreated, &vol)
 	return vol, err
 }
+
+// SnapshotPolicyCreate creates a snapshot policy
+func (c *Client) SnapshotPolicyCreate(req api.SnapPolicyCreateReq) (api.SnapPolicyCreateResp, error) {
+	var policy api.SnapPolicyCreateResp
+	err := c.post("/v1/snapshot-policies", req, http.StatusCreated, &policy)
+	return policy, err
+}
+
+// SnapshotPolicies returns all the snapshot policies
+func (c *Client) SnapshotPolicies() (api.SnapPolicyListResp, error) {
+	var policies api.SnapPolicyListResp
+	err := c.get("/v1/snapshot-policies", nil, http.StatusOK, &policies)
+	return policies, err
+}
+
+// SnapshotPolicy returns the snapshot policy with the given name
+func (c *Client) SnapshotPolicy(name string) (api.SnapPolicyGetResp, error) {
+	var policy api.SnapPolicyGetResp
+	err := c.get("/v1/snapshot-policies/"+name, nil, http.StatusOK, &policy)
+	return policy, err
+}
+
+// SnapshotPolicyDelete removes the snapshot policy with the given name
+func (c *Client) SnapshotPolicyDelete(name string) error {
+	return c.del("/v1/snapshot-policies/"+name, nil, http.StatusNoContent, nil)
+}
+
+// SnapshotLimits returns the snapshot limits of a volume
+func (c *Client) SnapshotLimits(volname string) (api.SnapLimitsResp, error) {
+	var limits api.SnapLimitsResp
+	url := fmt.Sprintf("/v1/volumes/%s/snapshot-limits", volname)
+	err := c.get(url, nil, http.StatusOK, &limits)
+	return limits, err
+}
+
+// SnapshotLimitsSet changes the snapshot limits of a volume
+func (c *Client) SnapshotLimitsSet(volname string, req api.SnapLimitsReq) (api.SnapLimitsResp, error) {
+	var limits api.SnapLimitsResp
+	url := fmt.Sprintf("/v1/volumes/%s/snapshot-limits", volname)
+	err := c.post(url, req, http.StatusOK, &limits)
+	return limits, err
+}
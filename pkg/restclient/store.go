@@ -0,0 +1,62 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// StoreMembers returns the status of the members of the embedded store
+func (c *Client) StoreMembers() (api.StoreMembersResp, error) {
+	var resp api.StoreMembersResp
+	err := c.get("/v1/store/members", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// StoreDefrag defragments the database of the given members of the embedded
+// store, or of all of them
+func (c *Client) StoreDefrag(members []string) (api.StoreDefragResp, error) {
+	var resp api.StoreDefragResp
+	err := c.post("/v1/store/defrag", api.StoreDefragReq{Members: members}, http.StatusOK, &resp)
+	return resp, err
+}
+
+// StoreCompact compacts the history of the embedded store up to the given
+// revision, or up to the current revision if it is 0
+func (c *Client) StoreCompact(revision int64) (api.StoreCompactResp, error) {
+	var resp api.StoreCompactResp
+	err := c.post("/v1/store/compact", api.StoreCompactReq{Revision: revision}, http.StatusOK, &resp)
+	return resp, err
+}
+
+// StoreAlarmList lists the alarms raised by the members of the embedded
+// store
+func (c *Client) StoreAlarmList() (api.StoreAlarmsResp, error) {
+	var resp api.StoreAlarmsResp
+	err := c.get("/v1/store/alarms", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// StoreAlarmClear clears all the alarms of the embedded store, and returns
+// the cleared alarms
+func (c *Client) StoreAlarmClear() (api.StoreAlarmsResp, error) {
+	var resp api.StoreAlarmsResp
+	err := c.del("/v1/store/alarms", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// StoreMoveLeader transfers the leadership of the embedded store to the given
+// member
+func (c *Client) StoreMoveLeader(member string) (api.StoreLeaderResp, error) {
+	var resp api.StoreLeaderResp
+	err := c.post("/v1/store/leader", api.StoreLeaderReq{Member: member}, http.StatusOK, &resp)
+	return resp, err
+}
+
+// StoreReload reloads the configuration of the external etcd cluster on all
+// the peers, after their config files have been changed
+func (c *Client) StoreReload() (api.StoreConfigResp, error) {
+	var resp api.StoreConfigResp
+	err := c.post("/v1/store/reload", nil, http.StatusOK, &resp)
+	return resp, err
+}
@@ -8,8 +8,12 @@ import (
 )
 
 // TLSOptions holds the TLS configurations information needed to create GD2 client .
+// CertFile and KeyFile are the client certificate and its private key, which
+// are needed if glusterd2 verifies the certificates of its clients.
 type TLSOptions struct {
 	CaCertFile         string
+	CertFile           string
+	KeyFile            string
 	InsecureSkipVerify bool
 }
 
@@ -29,5 +33,12 @@ func NewTLSConfig(opts *TLSOptions) (*tls.Config, error) {
 		}
 		tlsConfig.RootCAs = caCertPool
 	}
+	if opts.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
 	return tlsConfig, nil
 }
@@ -0,0 +1,38 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// Transactions returns the transactions which are running or have recently ended
+func (c *Client) Transactions() (api.TxnListResp, error) {
+	var txns api.TxnListResp
+	err := c.get("/v1/transactions", nil, http.StatusOK, &txns)
+	return txns, err
+}
+
+// Transaction returns the progress of the transaction with the given ID
+func (c *Client) Transaction(txnID string) (api.TxnGetResp, error) {
+	var txn api.TxnGetResp
+	err := c.get("/v1/transactions/"+txnID, nil, http.StatusOK, &txn)
+	return txn, err
+}
+
+// TransactionCancel requests the cancellation of a running transaction
+func (c *Client) TransactionCancel(txnID string) error {
+	return c.post("/v1/transactions/"+txnID+"/cancel", nil, http.StatusAccepted, nil)
+}
+
+// Locks returns the cluster wide locks which are currently held
+func (c *Client) Locks() (api.LockListResp, error) {
+	var locks api.LockListResp
+	err := c.get("/v1/locks", nil, http.StatusOK, &locks)
+	return locks, err
+}
+
+// LockForceRelease releases a lock irrespective of the transaction holding it
+func (c *Client) LockForceRelease(name string) error {
+	return c.del("/v1/locks/"+name, nil, http.StatusNoContent, nil)
+}
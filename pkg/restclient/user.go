@@ -0,0 +1,48 @@
+package restclient
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/pkg/api"
+)
+
+// UserCreate adds a user of the REST API. The returned secret of the user
+// is not returned by any other request.
+func (c *Client) UserCreate(req api.UserCreateReq) (api.UserCreateResp, error) {
+	var user api.UserCreateResp
+	err := c.post("/v1/users", req, http.StatusCreated, &user)
+	return user, err
+}
+
+// Users returns all the users of the REST API
+func (c *Client) Users() (api.UserListResp, error) {
+	var users api.UserListResp
+	err := c.get("/v1/users", nil, http.StatusOK, &users)
+	return users, err
+}
+
+// User returns the user with the given name
+func (c *Client) User(name string) (api.UserGetResp, error) {
+	var user api.UserGetResp
+	err := c.get("/v1/users/"+name, nil, http.StatusOK, &user)
+	return user, err
+}
+
+// UserEdit changes the role or the secret of a user
+func (c *Client) UserEdit(name string, req api.UserEditReq) (api.UserEditResp, error) {
+	var user api.UserEditResp
+	err := c.post("/v1/users/"+name+"/edit", req, http.StatusOK, &user)
+	return user, err
+}
+
+// UserDelete removes the user with the given name
+func (c *Client) UserDelete(name string) error {
+	return c.del("/v1/users/"+name, nil, http.StatusNoContent, nil)
+}
+
+// Roles returns the roles which can be given to users
+func (c *Client) Roles() (api.RoleListResp, error) {
+	var roles api.RoleListResp
+	err := c.get("/v1/roles", nil, http.StatusOK, &roles)
+	return roles, err
+}
@@ -4,21 +4,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/gluster/glusterd2/pkg/api"
 )
 
-// metadataFilter is a filter type
-type metadataFilter uint32
-
-// GetVolumes Filter Types
-const (
-	noKeyAndValue metadataFilter = iota
-	onlyKey
-	onlyValue
-	keyAndValue
-)
-
 // VolumeCreate creates Gluster Volume
 func (c *Client) VolumeCreate(req api.VolCreateReq) (api.VolumeCreateResp, error) {
 	var vol api.VolumeCreateResp
@@ -26,33 +16,50 @@ func (c *Client) VolumeCreate(req api.VolCreateReq) (api.VolumeCreateResp, error
 	return vol, err
 }
 
-// getFilterType return the filter type for volume list/info
-func getFilterType(filterParams map[string]string) metadataFilter {
-	_, key := filterParams["key"]
-	_, value := filterParams["value"]
-	if key && !value {
-		return onlyKey
-	} else if value && !key {
-		return onlyValue
-	} else if value && key {
-		return keyAndValue
-	}
-	return noKeyAndValue
+// VolumeCreateAsync starts creating a Gluster Volume and returns the job
+// doing it without waiting for it to end. The job has the created volume as
+// result once it is done.
+func (c *Client) VolumeCreateAsync(req api.VolCreateReq) (api.JobGetResp, error) {
+	var job api.JobGetResp
+	err := c.post("/v1/volumes?async=true", req, http.StatusAccepted, &job)
+	return job, err
+}
+
+// VolumeBulkCreate creates multiple Gluster Volumes with a single request
+func (c *Client) VolumeBulkCreate(req api.VolBulkCreateReq) (api.VolBulkResp, error) {
+	var resp api.VolBulkResp
+	err := c.post("/v1/volumes/bulk", req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeBulkDelete deletes multiple Gluster Volumes with a single request
+func (c *Client) VolumeBulkDelete(volnames []string) (api.VolBulkResp, error) {
+	var resp api.VolBulkResp
+	err := c.del("/v1/volumes/bulk", api.VolBulkDeleteReq(volnames), http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeCreateValidate validates a volume create request without creating
+// the volume and returns the fully resolved request
+func (c *Client) VolumeCreateValidate(req api.VolCreateReq) (api.VolCreateReq, error) {
+	var resolved api.VolCreateReq
+	err := c.post("/v1/volumes?validate=true", req, http.StatusOK, &resolved)
+	return resolved, err
 }
 
-// getQueryString returns the query string for filtering volumes
+// getQueryString returns the query string for filtering volumes and peers.
+// Supported parameters are the metadata "key" and "value" filters and, for
+// volumes, "state", "type", "labels", "sort", "order", "offset", "limit",
+// "continue" and "fields".
 func getQueryString(filterParam map[string]string) string {
-	filterType := getFilterType(filterParam)
-	var queryString string
-	switch filterType {
-	case onlyKey:
-		queryString = fmt.Sprintf("?key=%s", url.QueryEscape(filterParam["key"]))
-	case onlyValue:
-		queryString = fmt.Sprintf("?value=%s", url.QueryEscape(filterParam["value"]))
-	case keyAndValue:
-		queryString = fmt.Sprintf("?key=%s&value=%s", url.QueryEscape(filterParam["key"]), url.QueryEscape(filterParam["value"]))
+	query := url.Values{}
+	for k, v := range filterParam {
+		query.Set(k, v)
 	}
-	return queryString
+	if len(query) == 0 {
+		return ""
+	}
+	return "?" + query.Encode()
 }
 
 // Volumes returns list of all volumes
@@ -81,6 +88,15 @@ func (c *Client) BricksStatus(volname string) (api.BricksStatusResp, error) {
 	return resp, err
 }
 
+// BrickStatusDetail returns the detailed status of the brick of a volume with
+// the given ID
+func (c *Client) BrickStatusDetail(volname, brickID string) (api.BrickStatusDetailResp, error) {
+	url := fmt.Sprintf("/v1/volumes/%s/bricks/%s/status", volname, brickID)
+	var resp api.BrickStatusDetailResp
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
 // VolumeStatus returns the status of a Gluster volume
 func (c *Client) VolumeStatus(volname string) (api.VolumeStatusResp, error) {
 	url := fmt.Sprintf("/v1/volumes/%s/status", volname)
@@ -89,6 +105,78 @@ func (c *Client) VolumeStatus(volname string) (api.VolumeStatusResp, error) {
 	return volStatus, err
 }
 
+// VolumeClients returns the clients connected to the bricks of a volume
+func (c *Client) VolumeClients(volname string) (api.VolumeClientsResp, error) {
+	var resp api.VolumeClientsResp
+	url := fmt.Sprintf("/v1/volumes/%s/clients", volname)
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeTop returns the files of each brick of a volume with the highest
+// counts for the metric. A count of 0 uses the default of the server.
+func (c *Client) VolumeTop(volname, metric string, count int) (api.VolumeTopResp, error) {
+	var resp api.VolumeTopResp
+	url := fmt.Sprintf("/v1/volumes/%s/top?metric=%s", volname, metric)
+	if count > 0 {
+		url += fmt.Sprintf("&count=%d", count)
+	}
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeExport returns the portable description of a Gluster Volume
+func (c *Client) VolumeExport(volname string) (api.VolumeExport, error) {
+	var export api.VolumeExport
+	url := fmt.Sprintf("/v1/volumes/%s/export", volname)
+	err := c.get(url, nil, http.StatusOK, &export)
+	return export, err
+}
+
+// VolumeImport recreates a Gluster Volume from its exported description
+func (c *Client) VolumeImport(req api.VolumeImportReq) (api.VolumeCreateResp, error) {
+	var vol api.VolumeCreateResp
+	err := c.post("/v1/volumes/import", req, http.StatusCreated, &vol)
+	return vol, err
+}
+
+// VolumeHistory returns the recorded changes made to a Gluster Volume
+func (c *Client) VolumeHistory(volname string) (api.VolumeHistoryResp, error) {
+	var history api.VolumeHistoryResp
+	url := fmt.Sprintf("/v1/volumes/%s/history", volname)
+	err := c.get(url, nil, http.StatusOK, &history)
+	return history, err
+}
+
+// VolumeUsage returns the capacity and utilization of the bricks of a Gluster Volume
+func (c *Client) VolumeUsage(volname string) (api.VolumeUsageResp, error) {
+	var usage api.VolumeUsageResp
+	url := fmt.Sprintf("/v1/volumes/%s/usage", volname)
+	err := c.get(url, nil, http.StatusOK, &usage)
+	return usage, err
+}
+
+// VolumeChecksum compares the volinfo and volfiles of a Gluster Volume across all peers
+func (c *Client) VolumeChecksum(volname string) (api.VolumeChecksumResp, error) {
+	var resp api.VolumeChecksumResp
+	url := fmt.Sprintf("/v1/volumes/%s/checksum", volname)
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeVolfile returns the volfile of the given type of a Gluster Volume as
+// generated from its current configuration. The brick ID is only needed for
+// brick volfiles.
+func (c *Client) VolumeVolfile(volname, volfileType, brickID string) (api.VolfileResp, error) {
+	var resp api.VolfileResp
+	url := fmt.Sprintf("/v1/volumes/%s/volfiles/%s", volname, volfileType)
+	if brickID != "" {
+		url += "?brick=" + brickID
+	}
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
 // VolumeStart starts a Gluster Volume
 func (c *Client) VolumeStart(volname string, force bool) error {
 	req := api.VolumeStartReq{
@@ -104,6 +192,34 @@ func (c *Client) VolumeStop(volname string) error {
 	return c.post(url, nil, http.StatusOK, nil)
 }
 
+// VolumeStopGraceful stops a Gluster Volume only if no clients are connected
+// to it, waiting up to timeout for the clients to disconnect
+func (c *Client) VolumeStopGraceful(volname string, timeout time.Duration) error {
+	url := fmt.Sprintf("/v1/volumes/%s/stop?graceful=true&timeout=%d", volname, int(timeout.Seconds()))
+	return c.post(url, nil, http.StatusOK, nil)
+}
+
+// VolumeStartAll starts all the volumes which are not started, with at most
+// concurrency volumes being started at a time
+func (c *Client) VolumeStartAll(force bool, concurrency int) (api.VolBulkResp, error) {
+	var resp api.VolBulkResp
+	req := api.VolumeStartReq{
+		ForceStartBricks: force,
+	}
+	url := fmt.Sprintf("/v1/volumes/start-all?concurrency=%d", concurrency)
+	err := c.post(url, req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeStopAll stops all the started volumes, with at most concurrency
+// volumes being stopped at a time
+func (c *Client) VolumeStopAll(concurrency int) (api.VolBulkResp, error) {
+	var resp api.VolBulkResp
+	url := fmt.Sprintf("/v1/volumes/stop-all?concurrency=%d", concurrency)
+	err := c.post(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
 // VolumeDelete deletes a Gluster Volume
 func (c *Client) VolumeDelete(volname string) error {
 	url := fmt.Sprintf("/v1/volumes/%s", volname)
@@ -123,6 +239,55 @@ func (c *Client) ClusterOptionSet(req api.ClusterOptionReq) error {
 	return c.post(url, req, http.StatusOK, nil)
 }
 
+// ClusterOptionsGet returns the cluster level options
+func (c *Client) ClusterOptionsGet() ([]api.ClusterOptionsResp, error) {
+	var resp []api.ClusterOptionsResp
+	err := c.get("/v1/cluster/options", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// ClusterOptionReset resets cluster level options to their default values
+func (c *Client) ClusterOptionReset(req api.ClusterOptionResetReq) error {
+	return c.del("/v1/cluster/options", req, http.StatusOK, nil)
+}
+
+// ClusterOpVersion returns the op-version of the cluster and of its peers
+func (c *Client) ClusterOpVersion() (api.ClusterOpVersionResp, error) {
+	var resp api.ClusterOpVersionResp
+	err := c.get("/v1/cluster/op-version", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// ClusterOpVersionSet raises the op-version of the cluster. If opVersion is
+// 0, it is raised to the highest op-version supported by all peers.
+func (c *Client) ClusterOpVersionSet(opVersion int) (api.ClusterOpVersionResp, error) {
+	var resp api.ClusterOpVersionResp
+	req := api.ClusterOpVersionReq{OpVersion: opVersion}
+	err := c.post("/v1/cluster/op-version", req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeDefaultOptionsGet returns the cluster wide default volume options
+func (c *Client) VolumeDefaultOptionsGet() (api.VolumeDefaultOptionsResp, error) {
+	var resp api.VolumeDefaultOptionsResp
+	err := c.get("/v1/cluster/volume-options", nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeDefaultOptionsSet sets cluster wide default volume options
+func (c *Client) VolumeDefaultOptionsSet(req api.VolumeDefaultOptionsReq) (api.VolumeDefaultOptionsResp, error) {
+	var resp api.VolumeDefaultOptionsResp
+	err := c.post("/v1/cluster/volume-options", req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeDefaultOptionsReset removes cluster wide default volume options
+func (c *Client) VolumeDefaultOptionsReset(req api.VolumeDefaultOptionsResetReq) (api.VolumeDefaultOptionsResp, error) {
+	var resp api.VolumeDefaultOptionsResp
+	err := c.del("/v1/cluster/volume-options", req, http.StatusOK, &resp)
+	return resp, err
+}
+
 // VolumeGet gets volume options for a Gluster Volume
 func (c *Client) VolumeGet(volname string, optname string) (api.VolumeOptionsGetResp, error) {
 	if optname == "all" {
@@ -145,14 +310,65 @@ func (c *Client) VolumeExpand(volname string, req api.VolExpandReq) (api.VolumeE
 	return vol, err
 }
 
+// VolumeExpandAsync starts expanding a Gluster Volume and returns the job
+// doing it without waiting for it to end
+func (c *Client) VolumeExpandAsync(volname string, req api.VolExpandReq) (api.JobGetResp, error) {
+	var job api.JobGetResp
+	url := fmt.Sprintf("/v1/volumes/%s/expand?async=true", volname)
+	err := c.post(url, req, http.StatusAccepted, &job)
+	return job, err
+}
+
+// VolumeExpandWithRebalance expands a Gluster Volume and starts rebalance on
+// it as part of the same operation. rebalance is one of "start", "fix-layout"
+// or "force".
+func (c *Client) VolumeExpandWithRebalance(volname string, req api.VolExpandReq, rebalance string) (api.VolumeExpandResp, error) {
+	var vol api.VolumeExpandResp
+	url := fmt.Sprintf("/v1/volumes/%s/expand?rebalance=%s", volname, rebalance)
+	err := c.post(url, req, http.StatusOK, &vol)
+	return vol, err
+}
+
 // ReplaceBrick replaces the old brick in volume with a new one
 func (c *Client) ReplaceBrick(volname string, req api.ReplaceBrickReq) (api.ReplaceBrickResp, error) {
 	var resp api.ReplaceBrickResp
-	url := fmt.Sprintf("/v1/volumes/%s/replacebrick", volname)
+	url := fmt.Sprintf("/v1/volumes/%s/replace-brick", volname)
+	err := c.post(url, req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// RemoveBrickStart starts migrating data off the bricks to be removed from the volume
+func (c *Client) RemoveBrickStart(volname string, req api.RemoveBrickReq) (api.RemoveBrickStartResp, error) {
+	var resp api.RemoveBrickStartResp
+	url := fmt.Sprintf("/v1/volumes/%s/remove-brick/start", volname)
+	err := c.post(url, req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// RemoveBrickStatus returns the status of the data migration off the bricks being removed
+func (c *Client) RemoveBrickStatus(volname string) (api.RemoveBrickStatusResp, error) {
+	var resp api.RemoveBrickStatusResp
+	url := fmt.Sprintf("/v1/volumes/%s/remove-brick", volname)
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// RemoveBrickCommit removes the bricks from the volume once data is migrated off them
+func (c *Client) RemoveBrickCommit(volname string, req api.RemoveBrickCommitReq) (api.RemoveBrickCommitResp, error) {
+	var resp api.RemoveBrickCommitResp
+	url := fmt.Sprintf("/v1/volumes/%s/remove-brick/commit", volname)
 	err := c.post(url, req, http.StatusOK, &resp)
 	return resp, err
 }
 
+// RemoveBrickStop stops the remove-brick operation, leaving the bricks in the volume
+func (c *Client) RemoveBrickStop(volname string) (api.RemoveBrickStopResp, error) {
+	var resp api.RemoveBrickStopResp
+	url := fmt.Sprintf("/v1/volumes/%s/remove-brick/stop", volname)
+	err := c.post(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
 // VolumeStatedump takes statedump of various daemons
 func (c *Client) VolumeStatedump(volname string, req api.VolStatedumpReq) error {
 	url := fmt.Sprintf("/v1/volumes/%s/statedump", volname)
@@ -178,6 +394,74 @@ func (c *Client) OptionGroupDelete(group string) error {
 	return c.del(url, nil, http.StatusNoContent, nil)
 }
 
+// VolumeOptionGroupApply applies or unapplies an option group on a volume
+func (c *Client) VolumeOptionGroupApply(volname, group string, req api.VolOptionGroupReq) (api.VolumeOptionResp, error) {
+	var resp api.VolumeOptionResp
+	url := fmt.Sprintf("/v1/volumes/%s/options/group/%s", volname, group)
+	err := c.post(url, req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeProfileCreate creates a new volume profile
+func (c *Client) VolumeProfileCreate(req api.VolumeProfileReq) (api.VolumeProfile, error) {
+	var profile api.VolumeProfile
+	err := c.post("/v1/volume-profiles", req, http.StatusCreated, &profile)
+	return profile, err
+}
+
+// VolumeProfileList returns a list of all volume profiles
+func (c *Client) VolumeProfileList() (api.VolumeProfileListResp, error) {
+	var l api.VolumeProfileListResp
+	err := c.get("/v1/volume-profiles", nil, http.StatusOK, &l)
+	return l, err
+}
+
+// VolumeProfileGet returns the specified volume profile
+func (c *Client) VolumeProfileGet(name string) (api.VolumeProfile, error) {
+	var profile api.VolumeProfile
+	url := fmt.Sprintf("/v1/volume-profiles/%s", name)
+	err := c.get(url, nil, http.StatusOK, &profile)
+	return profile, err
+}
+
+// VolumeProfileDelete deletes the specified volume profile
+func (c *Client) VolumeProfileDelete(name string) error {
+	url := fmt.Sprintf("/v1/volume-profiles/%s", name)
+	return c.del(url, nil, http.StatusNoContent, nil)
+}
+
+// VolfileTemplateList returns all the volfile template overrides
+func (c *Client) VolfileTemplateList() (api.VolfileTemplateListResp, error) {
+	var l api.VolfileTemplateListResp
+	err := c.get("/v1/volfile-templates", nil, http.StatusOK, &l)
+	return l, err
+}
+
+// VolfileTemplateGet returns the override of the volfile template with the
+// given name for the volumes of the type
+func (c *Client) VolfileTemplateGet(voltype, name string) (api.VolfileTemplate, error) {
+	var tmpl api.VolfileTemplate
+	url := fmt.Sprintf("/v1/volfile-templates/%s/%s", voltype, name)
+	err := c.get(url, nil, http.StatusOK, &tmpl)
+	return tmpl, err
+}
+
+// VolfileTemplateSet overrides the built-in volfile template with the given
+// name for the volumes of the type
+func (c *Client) VolfileTemplateSet(voltype, name string, req api.VolfileTemplateReq) (api.VolfileTemplate, error) {
+	var tmpl api.VolfileTemplate
+	url := fmt.Sprintf("/v1/volfile-templates/%s/%s", voltype, name)
+	err := c.put(url, req, http.StatusOK, &tmpl)
+	return tmpl, err
+}
+
+// VolfileTemplateDelete deletes the override of the volfile template with
+// the given name for the volumes of the type
+func (c *Client) VolfileTemplateDelete(voltype, name string) error {
+	url := fmt.Sprintf("/v1/volfile-templates/%s/%s", voltype, name)
+	return c.del(url, nil, http.StatusNoContent, nil)
+}
+
 // EditVolume edits the specified keys in volinfo of a volume
 func (c *Client) EditVolume(volname string, req api.VolEditReq) (api.VolumeEditResp, error) {
 	var resp api.VolumeEditResp
@@ -186,6 +470,41 @@ func (c *Client) EditVolume(volname string, req api.VolEditReq) (api.VolumeEditR
 	return resp, err
 }
 
+// VolumeDeletionProtection enables or disables the deletion protection of a volume
+func (c *Client) VolumeDeletionProtection(volname string, enabled bool) (api.VolumeDeletionProtectionResp, error) {
+	var resp api.VolumeDeletionProtectionResp
+	req := api.VolDeletionProtectionReq{Enabled: enabled}
+	url := fmt.Sprintf("/v1/volumes/%s/deletion-protection", volname)
+	err := c.do("PATCH", url, req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeLabels sets and removes labels of a volume
+func (c *Client) VolumeLabels(volname string, req api.VolLabelsReq) (api.VolumeLabelsResp, error) {
+	var resp api.VolumeLabelsResp
+	url := fmt.Sprintf("/v1/volumes/%s/labels", volname)
+	err := c.do("PATCH", url, req, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeResourceLimits returns the resource limits of the brick processes of
+// a volume
+func (c *Client) VolumeResourceLimits(volname string) (api.VolResourceLimitsResp, error) {
+	var resp api.VolResourceLimitsResp
+	url := fmt.Sprintf("/v1/volumes/%s/resource-limits", volname)
+	err := c.get(url, nil, http.StatusOK, &resp)
+	return resp, err
+}
+
+// VolumeResourceLimitsSet changes the resource limits of the brick processes
+// of a volume
+func (c *Client) VolumeResourceLimitsSet(volname string, req api.VolResourceLimitsReq) (api.VolResourceLimitsResp, error) {
+	var resp api.VolResourceLimitsResp
+	url := fmt.Sprintf("/v1/volumes/%s/resource-limits", volname)
+	err := c.post(url, req, http.StatusOK, &resp)
+	return resp, err
+}
+
 // VolumeReset resets volume options to their default values
 func (c *Client) VolumeReset(volname string, req api.VolOptionResetReq) error {
 	url := fmt.Sprintf("/v1/volumes/%s/options", volname)
@@ -199,3 +518,15 @@ func (c *Client) VolumeProfileInfo(volname string, option string) ([]api.BrickPr
 	err := c.get(url, nil, http.StatusOK, &volumeProfileInfo)
 	return volumeProfileInfo, err
 }
+
+// VolumeProfileStart starts collecting the stats of the bricks of a volume
+func (c *Client) VolumeProfileStart(volname string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/profile/start", volname)
+	return c.post(url, nil, http.StatusOK, nil)
+}
+
+// VolumeProfileStop stops collecting the stats of the bricks of a volume
+func (c *Client) VolumeProfileStop(volname string) error {
+	url := fmt.Sprintf("/v1/volumes/%s/profile/stop", volname)
+	return c.post(url, nil, http.StatusOK, nil)
+}
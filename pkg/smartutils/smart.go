@@ -0,0 +1,134 @@
+// Package smartutils reads the SMART health of disks using smartctl.
+package smartutils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// Bits of the exit status of smartctl which mean that the health of the
+// disk could not be read. The other bits report the health itself.
+const (
+	exitCommandLine   = 1 << 0
+	exitDeviceOpen    = 1 << 1
+	exitCommandFailed = 1 << 2
+)
+
+// Report is the SMART health of a disk
+type Report struct {
+	Passed             bool
+	Temperature        int
+	PowerOnHours       uint64
+	ReallocatedSectors uint64
+	PendingSectors     uint64
+	// FailingAttributes are the attributes which are at or below their
+	// failure threshold
+	FailingAttributes []string
+}
+
+// Failing returns true if the disk reports that it is failing or about to
+// fail
+func (r *Report) Failing() bool {
+	return !r.Passed || len(r.FailingAttributes) > 0
+}
+
+type smartctlOutput struct {
+	Smartctl struct {
+		Messages []struct {
+			String   string `json:"string"`
+			Severity string `json:"severity"`
+		} `json:"messages"`
+	} `json:"smartctl"`
+	SmartStatus *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	Temperature struct {
+		Current int `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime struct {
+		Hours uint64 `json:"hours"`
+	} `json:"power_on_time"`
+	ATAAttributes struct {
+		Table []struct {
+			ID         int    `json:"id"`
+			Name       string `json:"name"`
+			WhenFailed string `json:"when_failed"`
+			Raw        struct {
+				Value uint64 `json:"value"`
+			} `json:"raw"`
+		} `json:"table"`
+	} `json:"ata_smart_attributes"`
+	NVMeHealth *struct {
+		CriticalWarning int `json:"critical_warning"`
+	} `json:"nvme_smart_health_information_log"`
+}
+
+// IDs of the ATA attributes counting bad sectors
+const (
+	ataReallocatedSectors = 5
+	ataPendingSectors     = 197
+)
+
+// Check reads the SMART health of the given device
+func Check(device string) (*Report, error) {
+	out, err := utils.ExecuteCommandOutput("smartctl", "--json", "--health", "--attributes", device)
+	if err != nil {
+		execErr, ok := err.(*utils.ExecuteCommandError)
+		if !ok || execErr.ExitStatus&(exitCommandLine|exitDeviceOpen|exitCommandFailed) != 0 {
+			if msg := errorMessage(out); msg != "" {
+				return nil, errors.New(msg)
+			}
+			return nil, err
+		}
+	}
+	return parse(out)
+}
+
+// errorMessage returns the first error reported in the output of smartctl
+func errorMessage(out []byte) string {
+	var o smartctlOutput
+	if json.Unmarshal(out, &o) != nil {
+		return ""
+	}
+	for _, m := range o.Smartctl.Messages {
+		if m.Severity == "error" {
+			return m.String
+		}
+	}
+	return ""
+}
+
+func parse(out []byte) (*Report, error) {
+	var o smartctlOutput
+	if err := json.Unmarshal(out, &o); err != nil {
+		return nil, fmt.Errorf("failed to parse smartctl output: %s", err)
+	}
+	if o.SmartStatus == nil {
+		return nil, errors.New("device does not report SMART status")
+	}
+
+	r := &Report{
+		Passed:       o.SmartStatus.Passed,
+		Temperature:  o.Temperature.Current,
+		PowerOnHours: o.PowerOnTime.Hours,
+	}
+	for _, a := range o.ATAAttributes.Table {
+		switch a.ID {
+		case ataReallocatedSectors:
+			r.ReallocatedSectors = a.Raw.Value
+		case ataPendingSectors:
+			r.PendingSectors = a.Raw.Value
+		}
+		if a.WhenFailed == "now" {
+			r.FailingAttributes = append(r.FailingAttributes, a.Name)
+		}
+	}
+	if o.NVMeHealth != nil && o.NVMeHealth.CriticalWarning != 0 {
+		r.FailingAttributes = append(r.FailingAttributes,
+			fmt.Sprintf("critical_warning(0x%02x)", o.NVMeHealth.CriticalWarning))
+	}
+	return r, nil
+}
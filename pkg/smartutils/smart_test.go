@@ -0,0 +1,56 @@
+package smartutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const ataOutput = `{
+  "smartctl": {"exit_status": 8},
+  "smart_status": {"passed": true},
+  "temperature": {"current": 34},
+  "power_on_time": {"hours": 21537},
+  "ata_smart_attributes": {
+    "table": [
+      {"id": 1, "name": "Raw_Read_Error_Rate", "when_failed": "", "raw": {"value": 0}},
+      {"id": 5, "name": "Reallocated_Sector_Ct", "when_failed": "now", "raw": {"value": 2040}},
+      {"id": 197, "name": "Current_Pending_Sector", "when_failed": "past", "raw": {"value": 16}}
+    ]
+  }
+}`
+
+const nvmeOutput = `{
+  "smart_status": {"passed": false},
+  "temperature": {"current": 41},
+  "power_on_time": {"hours": 120},
+  "nvme_smart_health_information_log": {"critical_warning": 4}
+}`
+
+func TestParseATA(t *testing.T) {
+	r, err := parse([]byte(ataOutput))
+	assert.Nil(t, err)
+	assert.True(t, r.Passed)
+	assert.Equal(t, 34, r.Temperature)
+	assert.Equal(t, uint64(21537), r.PowerOnHours)
+	assert.Equal(t, uint64(2040), r.ReallocatedSectors)
+	assert.Equal(t, uint64(16), r.PendingSectors)
+	assert.Equal(t, []string{"Reallocated_Sector_Ct"}, r.FailingAttributes)
+	assert.True(t, r.Failing())
+}
+
+func TestParseNVMe(t *testing.T) {
+	r, err := parse([]byte(nvmeOutput))
+	assert.Nil(t, err)
+	assert.False(t, r.Passed)
+	assert.Equal(t, []string{"critical_warning(0x04)"}, r.FailingAttributes)
+	assert.True(t, r.Failing())
+}
+
+func TestParseNoSMART(t *testing.T) {
+	_, err := parse([]byte(`{"smartctl": {}}`))
+	assert.NotNil(t, err)
+
+	_, err = parse([]byte("not json"))
+	assert.NotNil(t, err)
+}
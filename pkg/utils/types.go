@@ -1,10 +1,37 @@
 package utils
 
-import "reflect"
+import (
+	"reflect"
+	"sync"
+)
+
+// types has the types whose string was returned by GetTypeString, so that
+// they can be looked up by GetType
+var types struct {
+	sync.RWMutex
+	m map[string]reflect.Type
+}
 
 // GetTypeString returns the type of instance passed, as a string.
 // Go doesn't have type literals. Hence one has to pass (*Type)(nil)
 // as argument to this function.
 func GetTypeString(i interface{}) string {
-	return reflect.TypeOf(i).Elem().String()
+	t := reflect.TypeOf(i).Elem()
+
+	types.Lock()
+	if types.m == nil {
+		types.m = make(map[string]reflect.Type)
+	}
+	types.m[t.String()] = t
+	types.Unlock()
+
+	return t.String()
+}
+
+// GetType returns the type with the given string, as returned earlier by
+// GetTypeString, or nil if GetTypeString hasn't returned it
+func GetType(name string) reflect.Type {
+	types.RLock()
+	defer types.RUnlock()
+	return types.m[name]
 }
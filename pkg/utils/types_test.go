@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/gluster/glusterd2/pkg/api"
@@ -12,3 +13,10 @@ func TestGetTypeString(t *testing.T) {
 	assert.Equal(t, resp, "api.PeerGetResp")
 
 }
+
+func TestGetType(t *testing.T) {
+	assert.Nil(t, GetType("api.PeerAddReq"))
+
+	name := GetTypeString((*api.PeerAddReq)(nil))
+	assert.Equal(t, reflect.TypeOf(api.PeerAddReq{}), GetType(name))
+}
@@ -0,0 +1,305 @@
+// Package validation validates requests against the rules set in the `valid`
+// tags of their fields, and reports the fields which break them in a form
+// clients can act on.
+//
+// The rules of a field are separated by commas, like
+// `valid:"required,range(1|3)"`. The supported rules are:
+//
+//	required        the field must not be empty
+//	range(min|max)  the number must be between min and max
+//	length(min|max) the length of the string, slice or map must be between
+//	                min and max
+//	in(a|b|...)     the string must be one of the values
+//	host            the string must be an IP address or a DNS name
+//	ip              the string must be an IP address
+//	uuid            the string must be a UUID
+//
+// Fields which are empty are only checked by the required rule. Structs
+// nested in a request, and the elements of slices, are validated too, except
+// structs which are empty and not required.
+package validation
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/pborman/uuid"
+)
+
+// Error is a field of a request which breaks a rule
+type Error struct {
+	Code api.ErrorCode
+	// Field is the path to the field in the JSON encoding of the
+	// request, like subvols[0].replica
+	Field   string
+	Message string
+}
+
+// Errors are the fields of a request which break their rules. It implements
+// the api.ErrorResponse interface.
+type Errors []Error
+
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Field+": "+fe.Message)
+	}
+	return strings.Join(msgs, ", ")
+}
+
+// Response returns an error for every field which breaks a rule
+func (e Errors) Response() api.ErrorResp {
+	var resp api.ErrorResp
+	for _, fe := range e {
+		resp.Errors = append(resp.Errors, api.HTTPError{
+			Code:    int(fe.Code),
+			Field:   fe.Field,
+			Message: fe.Message,
+		})
+	}
+	return resp
+}
+
+// Status returns the HTTP status of requests which fail validation
+func (e Errors) Status() int {
+	return http.StatusBadRequest
+}
+
+// stringRules are the rules which check strings with a predicate
+var stringRules = map[string]struct {
+	check   func(string) bool
+	message string
+}{
+	"host": {govalidator.IsHost, "must be an IP address or a host name"},
+	"ip":   {govalidator.IsIP, "must be an IP address"},
+	"uuid": {isUUID, "must be a UUID"},
+}
+
+func isUUID(s string) bool {
+	return uuid.Parse(s) != nil
+}
+
+// Validate validates the request v, which is usually a pointer to a struct.
+// It returns Errors if any field breaks its rules.
+func Validate(v interface{}) error {
+	var errs Errors
+	validateValue(reflect.ValueOf(v), "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String, reflect.Array:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// validateValue validates the fields of the structs in v, which is found at
+// path in the request
+func validateValue(v reflect.Value, path string, errs *Errors) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		validateStruct(v, path, errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+func validateStruct(v reflect.Value, path string, errs *Errors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		// Fields of embedded structs are encoded as fields of the struct
+		// embedding them
+		if f.Anonymous && name == "" {
+			validateValue(v.Field(i), path, errs)
+			continue
+		}
+		if f.PkgPath != "" {
+			// unexported
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fv := v.Field(i)
+		fpath := joinPath(path, name)
+		rules := f.Tag.Get("valid")
+		if isEmpty(fv) {
+			if hasRule(rules, "required") {
+				*errs = append(*errs, Error{api.ErrFieldRequired, fpath, "is required"})
+			}
+			continue
+		}
+		if rules != "" && rules != "-" {
+			if fe := checkRules(fv, rules); fe != nil {
+				fe.Field = fpath
+				*errs = append(*errs, *fe)
+			}
+		}
+		validateValue(fv, fpath, errs)
+	}
+}
+
+func hasRule(rules, rule string) bool {
+	for _, r := range strings.Split(rules, ",") {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRule splits a rule like range(1|3) into its name and arguments
+func parseRule(rule string) (string, []string) {
+	open := strings.Index(rule, "(")
+	if open < 0 || !strings.HasSuffix(rule, ")") {
+		return rule, nil
+	}
+	return rule[:open], strings.Split(rule[open+1:len(rule)-1], "|")
+}
+
+// checkRules returns the error of the first rule the value of a field breaks
+func checkRules(v reflect.Value, rules string) *Error {
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	for _, rule := range strings.Split(rules, ",") {
+		name, args := parseRule(rule)
+		switch name {
+		case "", "required":
+			continue
+		case "range":
+			if fe := checkRange(v, args); fe != nil {
+				return fe
+			}
+		case "length":
+			if fe := checkLength(v, args); fe != nil {
+				return fe
+			}
+		case "in":
+			if v.Kind() != reflect.String {
+				panic(fmt.Sprintf("validation: rule %s on field of kind %s", rule, v.Kind()))
+			}
+			if !stringIn(v.String(), args) {
+				return &Error{Code: api.ErrFieldInvalid, Message: "must be one of " + strings.Join(args, ", ")}
+			}
+		default:
+			sr, ok := stringRules[name]
+			if !ok || v.Kind() != reflect.String {
+				panic(fmt.Sprintf("validation: unsupported rule %s on field of kind %s", rule, v.Kind()))
+			}
+			if !sr.check(v.String()) {
+				return &Error{Code: api.ErrFieldInvalid, Message: sr.message}
+			}
+		}
+	}
+	return nil
+}
+
+func stringIn(s string, values []string) bool {
+	for _, v := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBounds returns the bounds given as arguments to a rule
+func parseBounds(rule string, args []string) (float64, float64) {
+	if len(args) != 2 {
+		panic(fmt.Sprintf("validation: rule %s needs 2 arguments", rule))
+	}
+	min, err := strconv.ParseFloat(args[0], 64)
+	if err != nil {
+		panic(fmt.Sprintf("validation: invalid minimum of rule %s: %s", rule, err))
+	}
+	max, err := strconv.ParseFloat(args[1], 64)
+	if err != nil {
+		panic(fmt.Sprintf("validation: invalid maximum of rule %s: %s", rule, err))
+	}
+	return min, max
+}
+
+func checkRange(v reflect.Value, args []string) *Error {
+	min, max := parseBounds("range", args)
+
+	var n float64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	default:
+		panic(fmt.Sprintf("validation: rule range on field of kind %s", v.Kind()))
+	}
+
+	if n < min || n > max {
+		return &Error{
+			Code:    api.ErrFieldOutOfRange,
+			Message: fmt.Sprintf("must be between %s and %s", args[0], args[1]),
+		}
+	}
+	return nil
+}
+
+func checkLength(v reflect.Value, args []string) *Error {
+	min, max := parseBounds("length", args)
+
+	var n int
+	switch v.Kind() {
+	case reflect.String:
+		n = len([]rune(v.String()))
+	case reflect.Slice, reflect.Array, reflect.Map:
+		n = v.Len()
+	default:
+		panic(fmt.Sprintf("validation: rule length on field of kind %s", v.Kind()))
+	}
+
+	if float64(n) < min || float64(n) > max {
+		return &Error{
+			Code:    api.ErrFieldOutOfRange,
+			Message: fmt.Sprintf("length must be between %s and %s", args[0], args[1]),
+		}
+	}
+	return nil
+}
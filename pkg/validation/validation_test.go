@@ -0,0 +1,99 @@
+package validation
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testBrick struct {
+	PeerID string `json:"peerid" valid:"uuid"`
+	Path   string `json:"path" valid:"required"`
+}
+
+type testClient struct {
+	Host string `json:"host" valid:"host,required"`
+	Pid  int    `json:"pid" valid:"required"`
+}
+
+type testFlags struct {
+	Force bool   `json:"force,omitempty"`
+	Mode  string `json:"mode,omitempty" valid:"in(fast|safe)"`
+}
+
+type testReq struct {
+	Name      string      `json:"name" valid:"required,length(1|8)"`
+	Replica   int         `json:"replica,omitempty" valid:"range(1|3)"`
+	Transport string      `json:"transport,omitempty" valid:"in(tcp|rdma)"`
+	Bricks    []testBrick `json:"bricks"`
+	Client    testClient  `json:"client,omitempty"`
+	Ignored   string      `json:"-" valid:"required"`
+	testFlags
+}
+
+func TestValidateValid(t *testing.T) {
+	req := &testReq{
+		Name:      "vol1",
+		Replica:   3,
+		Transport: "tcp",
+		Bricks: []testBrick{
+			{PeerID: "7d6b7a4c-3c1e-4c1c-9a7b-2f0f6a6b5c4d", Path: "/b1"},
+			{Path: "/b2"},
+		},
+		testFlags: testFlags{Mode: "safe"},
+	}
+	assert.Nil(t, Validate(req))
+
+	// Empty nested structs which are not required are not validated
+	req.Client = testClient{Host: "192.0.2.1", Pid: 10}
+	assert.Nil(t, Validate(req))
+}
+
+func TestValidateInvalid(t *testing.T) {
+	req := &testReq{
+		Replica:   4,
+		Transport: "udp",
+		Bricks: []testBrick{
+			{PeerID: "7d6b7a4c-3c1e-4c1c-9a7b-2f0f6a6b5c4d", Path: "/b1"},
+			{PeerID: "peer2"},
+		},
+		Client:    testClient{Pid: 10},
+		testFlags: testFlags{Mode: "slow"},
+	}
+
+	err := Validate(req)
+	errs, ok := err.(Errors)
+	assert.True(t, ok)
+	assert.Equal(t, Errors{
+		{api.ErrFieldRequired, "name", "is required"},
+		{api.ErrFieldOutOfRange, "replica", "must be between 1 and 3"},
+		{api.ErrFieldInvalid, "transport", "must be one of tcp, rdma"},
+		{api.ErrFieldInvalid, "bricks[1].peerid", "must be a UUID"},
+		{api.ErrFieldRequired, "bricks[1].path", "is required"},
+		{api.ErrFieldRequired, "client.host", "is required"},
+		{api.ErrFieldInvalid, "mode", "must be one of fast, safe"},
+	}, errs)
+
+	var resp api.ErrorResponse = errs
+	assert.Equal(t, http.StatusBadRequest, resp.Status())
+	assert.Equal(t, api.HTTPError{
+		Code:    int(api.ErrFieldOutOfRange),
+		Field:   "replica",
+		Message: "must be between 1 and 3",
+	}, resp.Response().Errors[1])
+
+	req = &testReq{Name: "averylongname"}
+	assert.Equal(t, Errors{
+		{api.ErrFieldOutOfRange, "name", "length must be between 1 and 8"},
+	}, Validate(req))
+}
+
+func TestValidateSlice(t *testing.T) {
+	reqs := []testReq{{Name: "vol1"}, {}}
+	assert.Equal(t, Errors{
+		{api.ErrFieldRequired, "[1].name", "is required"},
+	}, Validate(&reqs))
+}
@@ -21,6 +21,10 @@ func isVolReplicate(vType volume.VolType) bool {
 
 func validateOptions(v *volume.Volinfo, key string, value string) error {
 	switch key {
+	case "arbiter-count":
+		if len(v.Subvols) == 0 || v.Subvols[0].ArbiterCount == 0 {
+			return errors.New("option cannot be set for a non arbiter volume")
+		}
 	case "metadata-self-heal":
 		if v.Subvols[0].ReplicaCount == 1 {
 			return errors.New("option cannot be set for a non replicate volume")
@@ -0,0 +1,66 @@
+package afr
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateOptions(t *testing.T) {
+	newVol := func(volType volume.VolType, replicaCount, arbiterCount int) *volume.Volinfo {
+		return &volume.Volinfo{
+			Type:    volType,
+			Subvols: []volume.Subvol{{ReplicaCount: replicaCount, ArbiterCount: arbiterCount}},
+		}
+	}
+	arbiter := newVol(volume.Replicate, 3, 1)
+	replicate := newVol(volume.DistReplicate, 3, 0)
+	disperse := newVol(volume.Disperse, 0, 0)
+	distribute := newVol(volume.Distribute, 1, 0)
+
+	tests := []struct {
+		name  string
+		vol   *volume.Volinfo
+		key   string
+		valid bool
+	}{
+		{"arbiter count of arbiter volume", arbiter, "arbiter-count", true},
+		{"arbiter count of replicate volume", replicate, "arbiter-count", false},
+		{"arbiter count of disperse volume", disperse, "arbiter-count", false},
+		{"arbiter count without subvolumes", &volume.Volinfo{Type: volume.Replicate}, "arbiter-count", false},
+		{"metadata self heal of replicate volume", replicate, "metadata-self-heal", true},
+		{"metadata self heal of distribute volume", distribute, "metadata-self-heal", false},
+		{"self heal daemon of replicate volume", replicate, "self-heal-daemon", true},
+		{"self heal daemon of disperse volume", disperse, "self-heal-daemon", true},
+		{"self heal daemon of distribute volume", distribute, "self-heal-daemon", false},
+		{"other option of replicate volume", replicate, "eager-lock", true},
+	}
+
+	for _, tt := range tests {
+		err := validateOptions(tt.vol, tt.key, "on")
+		assert.Equal(t, tt.valid, err == nil, tt.name)
+	}
+}
+
+// TestValidateGroupOptions validates that the replicate options of the
+// profile.gluster-block group can be set on any volume the group is applied
+// to, including disperse volumes
+func TestValidateGroupOptions(t *testing.T) {
+	groupOptions := map[string]string{
+		"eager-lock":               "disable",
+		"quorum-type":              "auto",
+		"data-self-heal-algorithm": "full",
+		"locking-scheme":           "granular",
+		"shd-max-threads":          "8",
+		"shd-wait-qlength":         "10000",
+	}
+
+	for _, volType := range []volume.VolType{volume.Replicate, volume.DistReplicate, volume.Disperse, volume.DistDisperse} {
+		v := &volume.Volinfo{Type: volType, Subvols: []volume.Subvol{{ReplicaCount: 3}}}
+		for key, value := range groupOptions {
+			assert.NoError(t, validateOptions(v, key, value), "%s on %s", key, volType)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package api
+
+// ScrubConfigReq represents the scrubber settings of a volume to be changed.
+// Empty fields are left unchanged.
+type ScrubConfigReq struct {
+	Throttle  string `json:"throttle,omitempty"`
+	Frequency string `json:"frequency,omitempty"`
+	State     string `json:"state,omitempty"`
+}
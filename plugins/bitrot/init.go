@@ -43,6 +43,12 @@ func (p *Plugin) RestRoutes() route.Routes {
 			Pattern:     "/volumes/{volname}/bitrot/scrubstatus",
 			Version:     1,
 			HandlerFunc: bitrotScrubStatusHandler},
+		route.Route{
+			Name:        "BitrotScrubConfig",
+			Method:      "POST",
+			Pattern:     "/volumes/{volname}/bitrot/config",
+			Version:     1,
+			HandlerFunc: bitrotScrubConfigHandler},
 	}
 }
 
@@ -0,0 +1,55 @@
+package api
+
+// TopologyZone is the key of the zone in topology segments
+const TopologyZone = "zone"
+
+// Topology represents a CSI topology segment. Only the zone of peers,
+// keyed by TopologyZone, is supported.
+type Topology map[string]string
+
+// TopologyRequirement represents the topologies a volume must be accessible
+// from. Bricks are placed in the zones of the requisite topologies, and in
+// the zones of the preferred ones if they have enough space.
+type TopologyRequirement struct {
+	Requisite []Topology `json:"requisite,omitempty"`
+	Preferred []Topology `json:"preferred,omitempty"`
+}
+
+// CapacityRange represents the range of capacity in bytes a volume can be
+// created or expanded with. A limit of zero is not enforced.
+type CapacityRange struct {
+	RequiredBytes uint64 `json:"required-bytes,omitempty"`
+	LimitBytes    uint64 `json:"limit-bytes,omitempty"`
+}
+
+// VolumeCreateReq represents REST API request to create a volume for the
+// CSI driver. Creating a volume with the same name and compatible parameters
+// again returns the existing volume.
+type VolumeCreateReq struct {
+	Name                    string               `json:"name"`
+	Namespace               string               `json:"namespace,omitempty"`
+	Capacity                CapacityRange        `json:"capacity-range"`
+	Profile                 string               `json:"profile,omitempty"`
+	ReplicaCount            int                  `json:"replica,omitempty"`
+	ArbiterCount            int                  `json:"arbiter,omitempty"`
+	DisperseCount           int                  `json:"disperse,omitempty"`
+	DisperseRedundancyCount int                  `json:"disperse-redundancy,omitempty"`
+	SnapshotEnabled         bool                 `json:"snapshot,omitempty"`
+	SnapshotReserveFactor   float64              `json:"snapshot-reserve-factor,omitempty"`
+	Options                 map[string]string    `json:"options,omitempty"`
+	Topology                *TopologyRequirement `json:"accessibility-requirements,omitempty"`
+}
+
+// VolumeExpandReq represents REST API request to expand a volume to the
+// capacity range
+type VolumeExpandReq struct {
+	Capacity CapacityRange `json:"capacity-range"`
+}
+
+// SnapshotCreateReq represents REST API request to take a snapshot of a
+// volume for the CSI driver. Taking a snapshot with the same name of the
+// same volume again returns the existing snapshot.
+type SnapshotCreateReq struct {
+	Name         string `json:"name"`
+	SourceVolume string `json:"source-volume"`
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// Volume represents a volume as seen by the CSI driver. Servers are the
+// client addresses of the peers hosting the bricks, which can serve the
+// volfile to mount the volume.
+type Volume struct {
+	ID                 uuid.UUID  `json:"id"`
+	Name               string     `json:"name"`
+	Namespace          string     `json:"namespace"`
+	CapacityBytes      uint64     `json:"capacity-bytes"`
+	State              string     `json:"state"`
+	Servers            []string   `json:"servers"`
+	AccessibleTopology []Topology `json:"accessible-topology"`
+}
+
+// Snapshot represents a snapshot as seen by the CSI driver
+type Snapshot struct {
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	SourceVolume string    `json:"source-volume"`
+	SizeBytes    uint64    `json:"size-bytes"`
+	CreatedAt    time.Time `json:"created-at"`
+	ReadyToUse   bool      `json:"ready-to-use"`
+}
+
+// VolumeListResp represents the response to list volumes
+type VolumeListResp []Volume
+
+// SnapshotListResp represents the response to list snapshots
+type SnapshotListResp []Snapshot
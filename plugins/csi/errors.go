@@ -0,0 +1,17 @@
+package csi
+
+import (
+	"errors"
+)
+
+var (
+	errInvalidCapacityRange = errors.New("invalid capacity range, limit-bytes is lower than required-bytes")
+	errCapacityRequired     = errors.New("required-bytes of the capacity range must be set to expand a volume")
+	errCapacityOverLimit    = errors.New("capacity of the volume is over limit-bytes, volumes can not be shrunk")
+	errInvalidTopology      = errors.New("invalid topology, segments must have only a zone")
+	errVolumeIncompatible   = errors.New("volume already exists with an incompatible capacity, layout or topology")
+	errSnapshotIncompatible = errors.New("snapshot already exists of another volume")
+	errSourceVolumeRequired = errors.New("source-volume of the snapshot is required")
+	errExpandNotSupported   = errors.New("only volumes with bricks provisioned from lvm devices can be expanded")
+	errVolumeHasSnapshots   = errors.New("volume has snapshots, they must be deleted first")
+)
@@ -0,0 +1,85 @@
+package csi
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/utils"
+	csiapi "github.com/gluster/glusterd2/plugins/csi/api"
+)
+
+// Plugin is a structure which implements GlusterdPlugin interface
+type Plugin struct {
+}
+
+// Name returns name of plugin
+func (p *Plugin) Name() string {
+	return "csi"
+}
+
+// RestRoutes returns list of REST API routes to register with Glusterd
+func (p *Plugin) RestRoutes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "CsiVolumeCreate",
+			Method:       "POST",
+			Pattern:      "/csi/volumes",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*csiapi.VolumeCreateReq)(nil)),
+			ResponseType: utils.GetTypeString((*csiapi.Volume)(nil)),
+			HandlerFunc:  volumeCreateHandler},
+		route.Route{
+			Name:         "CsiVolumeList",
+			Method:       "GET",
+			Pattern:      "/csi/volumes",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*csiapi.VolumeListResp)(nil)),
+			HandlerFunc:  volumeListHandler},
+		route.Route{
+			Name:         "CsiVolumeGet",
+			Method:       "GET",
+			Pattern:      "/csi/volumes/{volname}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*csiapi.Volume)(nil)),
+			HandlerFunc:  volumeGetHandler},
+		route.Route{
+			Name:         "CsiVolumeExpand",
+			Method:       "POST",
+			Pattern:      "/csi/volumes/{volname}/expand",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*csiapi.VolumeExpandReq)(nil)),
+			ResponseType: utils.GetTypeString((*csiapi.Volume)(nil)),
+			HandlerFunc:  volumeExpandHandler},
+		route.Route{
+			Name:        "CsiVolumeDelete",
+			Method:      "DELETE",
+			Pattern:     "/csi/volumes/{volname}",
+			Version:     1,
+			HandlerFunc: volumeDeleteHandler},
+		route.Route{
+			Name:         "CsiSnapshotCreate",
+			Method:       "POST",
+			Pattern:      "/csi/snapshots",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*csiapi.SnapshotCreateReq)(nil)),
+			ResponseType: utils.GetTypeString((*csiapi.Snapshot)(nil)),
+			HandlerFunc:  snapshotCreateHandler},
+		route.Route{
+			Name:         "CsiSnapshotList",
+			Method:       "GET",
+			Pattern:      "/csi/snapshots",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*csiapi.SnapshotListResp)(nil)),
+			HandlerFunc:  snapshotListHandler},
+		route.Route{
+			Name:        "CsiSnapshotDelete",
+			Method:      "DELETE",
+			Pattern:     "/csi/snapshots/{snapname}",
+			Version:     1,
+			HandlerFunc: snapshotDeleteHandler},
+	}
+}
+
+// RegisterStepFuncs registers transaction step functions with
+// Glusterd Transaction framework. The CSI API uses the transactions of the
+// volume and snapshot commands.
+func (p *Plugin) RegisterStepFuncs() {
+}
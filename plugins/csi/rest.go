@@ -0,0 +1,378 @@
+package csi
+
+import (
+	"net/http"
+
+	snapshotcommands "github.com/gluster/glusterd2/glusterd2/commands/snapshot"
+	"github.com/gluster/glusterd2/glusterd2/commands/volumes"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/snapshot"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	csiapi "github.com/gluster/glusterd2/plugins/csi/api"
+
+	"github.com/gorilla/mux"
+)
+
+func volumeCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req csiapi.VolumeCreateReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	if !volume.IsValidName(req.Name) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrInvalidVolName)
+		return
+	}
+
+	size, err := capacity(req.Capacity)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	preferred, requisite, err := placementZones(req.Topology)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if volume.Exists(req.Name) {
+		v, status, err := existingVolume(ctx, &req, requisite)
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+		sendVolumeResp(ctx, w, http.StatusOK, v)
+		return
+	}
+
+	// Bricks are placed in the preferred zones only if they have enough
+	// space, else anywhere in the requisite zones
+	createReq := newVolCreateReq(&req, size, requisite)
+	if len(preferred) > 0 {
+		validateReq := newVolCreateReq(&req, size, preferred)
+		if _, err := volumecommands.ValidateVolumeCreate(ctx, &validateReq); err == nil {
+			createReq = newVolCreateReq(&req, size, preferred)
+		}
+	}
+
+	if status, err := volumecommands.CreateVolume(ctx, createReq); err == gderrors.ErrVolExists {
+		// Created by a concurrent request with the same name
+		v, status, err := existingVolume(ctx, &req, requisite)
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+		sendVolumeResp(ctx, w, http.StatusOK, v)
+		return
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	v, err := volume.GetVolume(req.Name)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	logger.WithField("volume-name", v.Name).Info("new volume created for csi")
+	events.Broadcast(volume.NewEvent(volume.EventVolumeCreated, v))
+	events.Broadcast(volume.NewEvent(volume.EventVolumeStarted, v))
+
+	sendVolumeResp(ctx, w, http.StatusCreated, v)
+}
+
+func volumeListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	volumes, err := volume.GetVolumes(ctx)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	volumes = volume.ApplyCustomFilters(volumes, volume.FilterByReqNamespaces(ctx))
+
+	resp := make(csiapi.VolumeListResp, 0, len(volumes))
+	for _, v := range volumes {
+		vol, err := createVolumeResp(v)
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		resp = append(resp, *vol)
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func volumeGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	v, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, v.GetNamespace()) {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, gderrors.ErrVolNotFound)
+		return
+	}
+
+	sendVolumeResp(ctx, w, http.StatusOK, v)
+}
+
+func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	var req csiapi.VolumeExpandReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	if req.Capacity.RequiredBytes == 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errCapacityRequired)
+		return
+	}
+	if req.Capacity.LimitBytes != 0 && req.Capacity.LimitBytes < req.Capacity.RequiredBytes {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errInvalidCapacityRange)
+		return
+	}
+
+	v, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, v.GetNamespace()) {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, gderrors.ErrVolNotFound)
+		return
+	}
+
+	// Expanding a volume which already has the capacity succeeds, so that
+	// requests can be retried
+	if v.Capacity >= req.Capacity.RequiredBytes {
+		if !inRange(v.Capacity, req.Capacity) {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errCapacityOverLimit)
+			return
+		}
+		sendVolumeResp(ctx, w, http.StatusOK, v)
+		return
+	}
+
+	lvm := v.ProvisionerType == "" || v.ProvisionerType == api.ProvisionerTypeLvm
+	if !lvm || !v.IsAutoProvisioned() {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errExpandNotSupported)
+		return
+	}
+
+	// Expanding a volume by the size of all of its subvolumes resizes its
+	// bricks instead of adding new ones
+	expandReq := api.VolExpandReq{
+		Size:            req.Capacity.RequiredBytes - v.Capacity,
+		DistributeCount: len(v.Subvols),
+	}
+	v, _, status, err := volumecommands.ExpandVolume(ctx, volname, expandReq, nil)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	events.Broadcast(volume.NewEvent(volume.EventVolumeExpanded, v))
+
+	sendVolumeResp(ctx, w, http.StatusOK, v)
+}
+
+func volumeDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	// Deleting a volume which doesn't exist succeeds, so that requests can
+	// be retried
+	v, err := volume.GetVolume(volname)
+	if err == gderrors.ErrVolNotFound || (err == nil && !gdctx.ReqNamespaceAllowed(ctx, v.GetNamespace())) {
+		restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+		return
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Volumes which can't be deleted are left started
+	if v.IsDeletionProtected() {
+		restutils.SendHTTPError(ctx, w, http.StatusForbidden, gderrors.ErrVolDeletionProtected)
+		return
+	}
+	if len(v.SnapList) > 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusFailedDependency, errVolumeHasSnapshots)
+		return
+	}
+
+	if v.State == volume.VolStarted {
+		v, status, err := volumecommands.StopVolume(ctx, volname, false, 0)
+		if err != nil && err != gderrors.ErrVolAlreadyStopped {
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+		if err == nil {
+			events.Broadcast(volume.NewEvent(volume.EventVolumeStopped, v))
+		}
+	}
+
+	// The volume may have been deleted by a concurrent request
+	if status, err := volumecommands.DeleteVolume(ctx, volname); err != nil && volume.Exists(volname) {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+// createSnapshotResp returns the snapshot as seen by the CSI driver
+func createSnapshotResp(s *snapshot.Snapinfo) *csiapi.Snapshot {
+	return &csiapi.Snapshot{
+		ID:           s.SnapVolinfo.ID,
+		Name:         s.SnapVolinfo.Name,
+		SourceVolume: s.ParentVolume,
+		SizeBytes:    s.SnapVolinfo.Capacity,
+		CreatedAt:    s.CreatedAt,
+		// Snapshots are taken with all bricks barriered, they can be
+		// used as soon as they are created
+		ReadyToUse: true,
+	}
+}
+
+// existingSnapshot returns the snapshot which already exists with the name
+// of the request, if it is of the requested volume
+func existingSnapshot(req *csiapi.SnapshotCreateReq) (*snapshot.Snapinfo, int, error) {
+	s, err := snapshot.GetSnapshot(req.Name)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if s.ParentVolume != req.SourceVolume {
+		return nil, http.StatusConflict, errSnapshotIncompatible
+	}
+	return s, http.StatusOK, nil
+}
+
+func snapshotCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req csiapi.SnapshotCreateReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	if !volume.IsValidName(req.Name) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrInvalidSnapName)
+		return
+	}
+	if req.SourceVolume == "" {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errSourceVolumeRequired)
+		return
+	}
+
+	v, err := volume.GetVolume(req.SourceVolume)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, v.GetNamespace()) {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, gderrors.ErrVolNotFound)
+		return
+	}
+
+	if snapshot.Exists(req.Name) {
+		s, status, err := existingSnapshot(&req)
+		if err != nil {
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, createSnapshotResp(s))
+		return
+	}
+
+	snapReq := api.SnapCreateReq{
+		VolName:  req.SourceVolume,
+		SnapName: req.Name,
+	}
+	s, status, err := snapshotcommands.CreateSnapshot(ctx, &snapReq, "")
+	if err == gderrors.ErrSnapExists {
+		// Taken by a concurrent request with the same name
+		s, status, err = existingSnapshot(&req)
+	}
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, status, createSnapshotResp(s))
+}
+
+func snapshotListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	source := r.URL.Query().Get("source-volume")
+
+	snaps, err := snapshot.GetSnapshots()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := make(csiapi.SnapshotListResp, 0, len(snaps))
+	for _, s := range snaps {
+		if source != "" && s.ParentVolume != source {
+			continue
+		}
+		if !gdctx.ReqNamespaceAllowed(ctx, s.SnapVolinfo.GetNamespace()) {
+			continue
+		}
+		resp = append(resp, *createSnapshotResp(s))
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func snapshotDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	snapname := mux.Vars(r)["snapname"]
+
+	// Deleting a snapshot which doesn't exist succeeds, so that requests can
+	// be retried
+	if !snapshot.Exists(snapname) {
+		restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+		return
+	}
+	s, err := snapshot.GetSnapshot(snapname)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, s.SnapVolinfo.GetNamespace()) {
+		restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+		return
+	}
+
+	// The snapshot may have been deleted by a concurrent request
+	if status, err := snapshotcommands.DeleteSnapshot(ctx, snapname); err != nil && snapshot.Exists(snapname) {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
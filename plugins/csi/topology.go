@@ -0,0 +1,116 @@
+package csi
+
+import (
+	"sort"
+
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/utils"
+	csiapi "github.com/gluster/glusterd2/plugins/csi/api"
+)
+
+const (
+	// defaultCapacity is the capacity of volumes whose capacity range is
+	// not set
+	defaultCapacity = 1 * utils.GiB
+	// minCapacity is the smallest volume which can be created
+	minCapacity = 20 * utils.MiB
+)
+
+// capacity returns the capacity to create a volume with for the range: the
+// required capacity, raised to the smallest volume which can be created
+func capacity(r csiapi.CapacityRange) (uint64, error) {
+	if r.LimitBytes != 0 && r.LimitBytes < r.RequiredBytes {
+		return 0, errInvalidCapacityRange
+	}
+
+	size := r.RequiredBytes
+	if size == 0 {
+		size = defaultCapacity
+		if r.LimitBytes != 0 && r.LimitBytes < size {
+			size = r.LimitBytes
+		}
+	}
+	if size < minCapacity {
+		size = minCapacity
+	}
+
+	if r.LimitBytes != 0 && size > r.LimitBytes {
+		return 0, errInvalidCapacityRange
+	}
+	return size, nil
+}
+
+// inRange returns true if the capacity is within the range
+func inRange(capacity uint64, r csiapi.CapacityRange) bool {
+	return capacity >= r.RequiredBytes && (r.LimitBytes == 0 || capacity <= r.LimitBytes)
+}
+
+// zones returns the sorted zones of the topology segments
+func zones(topologies []csiapi.Topology) ([]string, error) {
+	seen := make(map[string]bool)
+	var zones []string
+	for _, t := range topologies {
+		zone, ok := t[csiapi.TopologyZone]
+		if !ok || zone == "" || len(t) != 1 {
+			return nil, errInvalidTopology
+		}
+		if !seen[zone] {
+			seen[zone] = true
+			zones = append(zones, zone)
+		}
+	}
+	sort.Strings(zones)
+	return zones, nil
+}
+
+// placementZones returns the zones bricks are to be placed in first, if they
+// have enough space, and the zones bricks must be placed in. Preferred zones
+// outside of the requisite ones are ignored.
+func placementZones(t *csiapi.TopologyRequirement) (preferred, requisite []string, err error) {
+	if t == nil {
+		return nil, nil, nil
+	}
+
+	if requisite, err = zones(t.Requisite); err != nil {
+		return nil, nil, err
+	}
+	all, err := zones(t.Preferred)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, zone := range all {
+		if len(requisite) == 0 || utils.StringInSlice(zone, requisite) {
+			preferred = append(preferred, zone)
+		}
+	}
+	return preferred, requisite, nil
+}
+
+// volumePeers returns the peers hosting the bricks of the volume
+func volumePeers(v *volume.Volinfo) ([]*peer.Peer, error) {
+	var peers []*peer.Peer
+	for _, id := range v.Nodes() {
+		p, err := peer.GetPeer(id.String())
+		if err != nil {
+			return nil, err
+		}
+		peers = append(peers, p)
+	}
+	return peers, nil
+}
+
+// peerZones returns the sorted zones of the peers
+func peerZones(peers []*peer.Peer) []string {
+	seen := make(map[string]bool)
+	var zones []string
+	for _, p := range peers {
+		if zone := p.GetZone(); !seen[zone] {
+			seen[zone] = true
+			zones = append(zones, zone)
+		}
+	}
+	sort.Strings(zones)
+	return zones
+}
@@ -0,0 +1,74 @@
+package csi
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/pkg/utils"
+	csiapi "github.com/gluster/glusterd2/plugins/csi/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapacity(t *testing.T) {
+	for _, tc := range []struct {
+		r    csiapi.CapacityRange
+		size uint64
+	}{
+		{csiapi.CapacityRange{}, defaultCapacity},
+		{csiapi.CapacityRange{RequiredBytes: 5 * utils.GiB}, 5 * utils.GiB},
+		{csiapi.CapacityRange{LimitBytes: 512 * utils.MiB}, 512 * utils.MiB},
+		{csiapi.CapacityRange{RequiredBytes: utils.MiB}, minCapacity},
+		{csiapi.CapacityRange{RequiredBytes: utils.GiB, LimitBytes: 2 * utils.GiB}, utils.GiB},
+	} {
+		size, err := capacity(tc.r)
+		require.NoError(t, err)
+		assert.Equal(t, tc.size, size)
+	}
+
+	_, err := capacity(csiapi.CapacityRange{RequiredBytes: 2 * utils.GiB, LimitBytes: utils.GiB})
+	assert.Equal(t, errInvalidCapacityRange, err)
+
+	_, err = capacity(csiapi.CapacityRange{LimitBytes: utils.MiB})
+	assert.Equal(t, errInvalidCapacityRange, err)
+}
+
+func TestPlacementZones(t *testing.T) {
+	preferred, requisite, err := placementZones(nil)
+	require.NoError(t, err)
+	assert.Empty(t, preferred)
+	assert.Empty(t, requisite)
+
+	preferred, requisite, err = placementZones(&csiapi.TopologyRequirement{
+		Requisite: []csiapi.Topology{{"zone": "z2"}, {"zone": "z1"}, {"zone": "z2"}},
+		Preferred: []csiapi.Topology{{"zone": "z1"}, {"zone": "z3"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"z1"}, preferred)
+	assert.Equal(t, []string{"z1", "z2"}, requisite)
+
+	preferred, requisite, err = placementZones(&csiapi.TopologyRequirement{
+		Preferred: []csiapi.Topology{{"zone": "z3"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"z3"}, preferred)
+	assert.Empty(t, requisite)
+
+	_, _, err = placementZones(&csiapi.TopologyRequirement{
+		Requisite: []csiapi.Topology{{"rack": "r1"}},
+	})
+	assert.Equal(t, errInvalidTopology, err)
+}
+
+func TestPeerZones(t *testing.T) {
+	id := uuid.NewRandom()
+	peers := []*peer.Peer{
+		{ID: uuid.NewRandom(), Zone: "z2"},
+		{ID: uuid.NewRandom(), Zone: "z1"},
+		{ID: uuid.NewRandom(), Zone: "z2"},
+		{ID: id},
+	}
+	assert.Equal(t, []string{id.String(), "z1", "z2"}, peerZones(peers))
+}
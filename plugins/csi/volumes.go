@@ -0,0 +1,144 @@
+package csi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/commands/volumes"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/utils"
+	csiapi "github.com/gluster/glusterd2/plugins/csi/api"
+)
+
+// newVolCreateReq returns the request to create the volume with bricks in the
+// zones
+func newVolCreateReq(req *csiapi.VolumeCreateReq, size uint64, zones []string) api.VolCreateReq {
+	options := make(map[string]string)
+	for k, v := range req.Options {
+		options[k] = v
+	}
+
+	return api.VolCreateReq{
+		Name:                    req.Name,
+		Namespace:               req.Namespace,
+		Size:                    size,
+		Profile:                 req.Profile,
+		ReplicaCount:            req.ReplicaCount,
+		ArbiterCount:            req.ArbiterCount,
+		DisperseCount:           req.DisperseCount,
+		DisperseRedundancyCount: req.DisperseRedundancyCount,
+		SnapshotEnabled:         req.SnapshotEnabled,
+		SnapshotReserveFactor:   req.SnapshotReserveFactor,
+		LimitZones:              zones,
+		StartVolume:             true,
+		VolOptionReq:            api.VolOptionReq{Options: options},
+	}
+}
+
+// compatible returns true if the existing volume matches the request, that
+// is if its capacity is in the requested range, it has the requested layout
+// and its bricks are in the requisite zones
+func compatible(v *volume.Volinfo, zones []string, req *csiapi.VolumeCreateReq) bool {
+	if req.Namespace != "" && v.GetNamespace() != req.Namespace {
+		return false
+	}
+	if !inRange(v.Capacity, req.Capacity) || len(v.Subvols) == 0 {
+		return false
+	}
+
+	sv := v.Subvols[0]
+	if req.ReplicaCount != 0 && sv.ReplicaCount != req.ReplicaCount {
+		return false
+	}
+	if req.ArbiterCount != 0 && sv.ArbiterCount != req.ArbiterCount {
+		return false
+	}
+	if req.DisperseCount != 0 && sv.DisperseCount != req.DisperseCount {
+		return false
+	}
+	if req.DisperseRedundancyCount != 0 && sv.RedundancyCount != req.DisperseRedundancyCount {
+		return false
+	}
+
+	if len(zones) == 0 {
+		return true
+	}
+	peers, err := volumePeers(v)
+	if err != nil {
+		return false
+	}
+	for _, zone := range peerZones(peers) {
+		if !utils.StringInSlice(zone, zones) {
+			return false
+		}
+	}
+	return true
+}
+
+// existingVolume returns the volume which already exists with the name of
+// the request, started, if it is compatible with the request
+func existingVolume(ctx context.Context, req *csiapi.VolumeCreateReq, zones []string) (*volume.Volinfo, int, error) {
+	v, err := volume.GetVolume(req.Name)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, v.GetNamespace()) {
+		return nil, http.StatusConflict, gderrors.ErrVolExists
+	}
+	if !compatible(v, zones, req) {
+		return nil, http.StatusConflict, errVolumeIncompatible
+	}
+
+	// The volume may have been created without being started, if the
+	// request creating it failed
+	if v.State != volume.VolStarted {
+		v, status, err := volumecommands.StartVolume(ctx, req.Name, api.VolumeStartReq{})
+		if err != nil {
+			return nil, status, err
+		}
+		events.Broadcast(volume.NewEvent(volume.EventVolumeStarted, v))
+		return v, http.StatusOK, nil
+	}
+	return v, http.StatusOK, nil
+}
+
+// createVolumeResp returns the volume as seen by the CSI driver
+func createVolumeResp(v *volume.Volinfo) (*csiapi.Volume, error) {
+	peers, err := volumePeers(v)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &csiapi.Volume{
+		ID:                 v.ID,
+		Name:               v.Name,
+		Namespace:          v.GetNamespace(),
+		CapacityBytes:      v.Capacity,
+		State:              api.VolState(v.State).String(),
+		Servers:            []string{},
+		AccessibleTopology: []csiapi.Topology{},
+	}
+	for _, p := range peers {
+		if len(p.ClientAddresses) > 0 {
+			resp.Servers = append(resp.Servers, p.ClientAddresses[0])
+		}
+	}
+	for _, zone := range peerZones(peers) {
+		resp.AccessibleTopology = append(resp.AccessibleTopology, csiapi.Topology{csiapi.TopologyZone: zone})
+	}
+	return resp, nil
+}
+
+func sendVolumeResp(ctx context.Context, w http.ResponseWriter, status int, v *volume.Volinfo) {
+	resp, err := createVolumeResp(v)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, status, resp)
+}
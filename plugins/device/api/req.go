@@ -6,6 +6,17 @@ const (
 
 	// DeviceDisabled represents disabled
 	DeviceDisabled = "disabled"
+
+	// DeviceHealthOK represents a device which passes its SMART checks
+	DeviceHealthOK = "ok"
+
+	// DeviceHealthFailing represents a device which reports that it is
+	// failing or about to fail
+	DeviceHealthFailing = "failing"
+
+	// DeviceHealthUnknown represents a device whose SMART health could not
+	// be read
+	DeviceHealthUnknown = "unknown"
 )
 
 // AddDeviceReq structure
@@ -14,7 +25,19 @@ type AddDeviceReq struct {
 	ProvisionerType string `json:"provisioner"`
 }
 
-// EditDeviceReq structure
+// BulkAddDevice is a device to be added to a peer by a bulk request
+type BulkAddDevice struct {
+	PeerID          string `json:"peer-id"`
+	Device          string `json:"device"`
+	ProvisionerType string `json:"provisioner"`
+}
+
+// BulkAddDeviceReq is a request to add multiple devices of any peers at once
+type BulkAddDeviceReq []BulkAddDevice
+
+// EditDeviceReq structure. Schedulable can be set instead of State, with
+// false disabling the device and true enabling it.
 type EditDeviceReq struct {
-	State string `json:"state"`
+	State       string `json:"state"`
+	Schedulable *bool  `json:"schedulable,omitempty"`
 }
@@ -2,6 +2,7 @@ package api
 
 import (
 	"strings"
+	"time"
 
 	"github.com/pborman/uuid"
 )
@@ -17,6 +18,25 @@ type Info struct {
 	ExtentSize      uint64    `json:"extent-size"`
 	Used            bool      `json:"device-used"`
 	PeerID          uuid.UUID `json:"peer-id"`
+	Health          *Health   `json:"health,omitempty"`
+}
+
+// Health is the SMART health of a device, as last checked by the peer of the
+// device
+type Health struct {
+	Status             string    `json:"status"`
+	Temperature        int       `json:"temperature,omitempty"`
+	PowerOnHours       uint64    `json:"power-on-hours,omitempty"`
+	ReallocatedSectors uint64    `json:"reallocated-sectors"`
+	PendingSectors     uint64    `json:"pending-sectors"`
+	FailingAttributes  []string  `json:"failing-attributes,omitempty"`
+	Error              string    `json:"error,omitempty"`
+	CheckedAt          time.Time `json:"checked-at"`
+}
+
+// Schedulable returns true if new bricks can be provisioned on the device
+func (info *Info) Schedulable() bool {
+	return info.State != DeviceDisabled
 }
 
 // VgName returns name for LVM Vg
@@ -29,3 +49,30 @@ type AddDeviceResp Info
 
 // ListDeviceResp is the success response sent to a ListDevice request
 type ListDeviceResp []Info
+
+// BulkAddDeviceResult is the result of adding a single device of a bulk
+// request
+type BulkAddDeviceResult struct {
+	PeerID string `json:"peer-id"`
+	Device string `json:"device"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Info   *Info  `json:"info,omitempty"`
+}
+
+// BulkAddDeviceResp is the response sent for a bulk device add request
+type BulkAddDeviceResp []BulkAddDeviceResult
+
+// AvailableDevice is a block device of a peer which is not in use and can be
+// registered as a device
+type AvailableDevice struct {
+	Device     string `json:"device"`
+	Size       uint64 `json:"size"`
+	Model      string `json:"model,omitempty"`
+	Serial     string `json:"serial,omitempty"`
+	Rotational bool   `json:"rotational"`
+}
+
+// AvailableDevicesResp is the response sent for a request listing the
+// available devices of a peer
+type AvailableDevicesResp []AvailableDevice
@@ -0,0 +1,79 @@
+package device
+
+import (
+	"net/http"
+	"sync"
+
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/errors"
+	deviceapi "github.com/gluster/glusterd2/plugins/device/api"
+
+	"github.com/pborman/uuid"
+)
+
+// bulkAddConcurrency is the number of peers whose devices are prepared in
+// parallel by a bulk add request. The devices of a peer are prepared one after
+// the other, as adding a device locks its peer.
+const bulkAddConcurrency = 8
+
+func deviceBulkAddHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req deviceapi.BulkAddDeviceReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	if len(req) == 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "no devices specified")
+		return
+	}
+
+	resp := make(deviceapi.BulkAddDeviceResp, len(req))
+
+	// Group the devices by peer, keeping the order of the request
+	var peers []string
+	byPeer := make(map[string][]int)
+	for idx, d := range req {
+		resp[idx].PeerID = d.PeerID
+		resp[idx].Device = d.Device
+		if uuid.Parse(d.PeerID) == nil {
+			resp[idx].Status = http.StatusBadRequest
+			resp[idx].Error = "invalid peer-id"
+			continue
+		}
+		if _, ok := byPeer[d.PeerID]; !ok {
+			peers = append(peers, d.PeerID)
+		}
+		byPeer[d.PeerID] = append(byPeer[d.PeerID], idx)
+	}
+
+	sem := make(chan struct{}, bulkAddConcurrency)
+	var wg sync.WaitGroup
+	for _, peerID := range peers {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(peerID string, indices []int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			for _, idx := range indices {
+				addReq := &deviceapi.AddDeviceReq{
+					Device:          req[idx].Device,
+					ProvisionerType: req[idx].ProvisionerType,
+				}
+				info, status, err := AddDevice(ctx, peerID, addReq)
+				resp[idx].Status = status
+				if err != nil {
+					resp[idx].Error = err.Error()
+					continue
+				}
+				resp[idx].Info = info
+			}
+		}(peerID, byPeer[peerID])
+	}
+	wg.Wait()
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
@@ -0,0 +1,75 @@
+package deviceutils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gluster/glusterd2/pkg/utils"
+	deviceapi "github.com/gluster/glusterd2/plugins/device/api"
+)
+
+// lsblkColumns are the columns listed by lsblk for each block device
+const lsblkColumns = "NAME,TYPE,SIZE,RO,MOUNTPOINT,FSTYPE,PKNAME,MODEL,SERIAL,ROTA"
+
+var lsblkPairRE = regexp.MustCompile(`([A-Z:]+)="([^"]*)"`)
+
+// parseLsblk parses the output of lsblk --pairs into a map of columns per
+// block device
+func parseLsblk(out string) []map[string]string {
+	var devices []map[string]string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		dev := make(map[string]string)
+		for _, m := range lsblkPairRE.FindAllStringSubmatch(line, -1) {
+			dev[m[1]] = strings.TrimSpace(m[2])
+		}
+		devices = append(devices, dev)
+	}
+	return devices
+}
+
+// GetAvailableDevices returns the block devices of this peer which can be
+// registered as devices: disks which are writable, have no partitions, are
+// not mounted and have no file system or LVM physical volume on them.
+func GetAvailableDevices() ([]deviceapi.AvailableDevice, error) {
+	out, err := utils.ExecuteCommandOutput("lsblk", "--pairs", "--bytes", "--paths", "--output", lsblkColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := parseLsblk(string(out))
+
+	// Disks which are the parent of another block device, like a partition
+	// or a device mapper device, are in use
+	parents := make(map[string]bool)
+	for _, d := range devices {
+		if d["PKNAME"] != "" {
+			parents[d["PKNAME"]] = true
+		}
+	}
+
+	available := []deviceapi.AvailableDevice{}
+	for _, d := range devices {
+		if d["TYPE"] != "disk" || d["RO"] == "1" || parents[d["NAME"]] {
+			continue
+		}
+		if d["MOUNTPOINT"] != "" || d["FSTYPE"] != "" {
+			continue
+		}
+		size, err := strconv.ParseUint(d["SIZE"], 10, 64)
+		if err != nil || size == 0 {
+			continue
+		}
+		available = append(available, deviceapi.AvailableDevice{
+			Device:     d["NAME"],
+			Size:       size,
+			Model:      d["MODEL"],
+			Serial:     d["SERIAL"],
+			Rotational: d["ROTA"] == "1",
+		})
+	}
+	return available, nil
+}
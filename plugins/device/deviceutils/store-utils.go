@@ -17,6 +17,9 @@ import (
 
 const (
 	devicePrefix string = "devices/"
+	// StorePrefix is the prefix of the keys of the devices in the store,
+	// whose changes can be followed with store.WatchPrefix
+	StorePrefix = devicePrefix
 )
 
 // GetDevices returns devices of specified peer/peers from the store
@@ -50,6 +53,18 @@ func GetDevices(peerIds ...string) ([]deviceapi.Info, error) {
 	return devices, nil
 }
 
+// GetDevicesVersion returns a version of the devices of the given peer, or
+// of all the peers if no peer is given, which changes whenever a device is
+// added or changed. If a device name is given, the version is of that
+// device.
+func GetDevicesVersion(peerID, deviceName string) (string, error) {
+	prefix := devicePrefix
+	if peerID != "" {
+		prefix += peerID + "/" + deviceName
+	}
+	return store.PrefixVersion(context.TODO(), prefix)
+}
+
 // GetDevice returns device of specified peer and device name
 func GetDevice(peerID, deviceName string) (*deviceapi.Info, error) {
 	var device deviceapi.Info
@@ -82,6 +97,27 @@ func SetDeviceState(peerID, deviceName, deviceState string) error {
 	return AddOrUpdateDevice(*dev)
 }
 
+// SetDeviceHealth saves the health of the device, and disables the device if
+// disable is true
+func SetDeviceHealth(peerID, deviceName string, health *deviceapi.Health, disable bool) error {
+	clusterLocks := transaction.Locks{}
+	if err := clusterLocks.Lock(peerID + deviceName); err != nil {
+		return err
+	}
+	defer clusterLocks.UnLock(context.Background())
+
+	dev, err := GetDevice(peerID, deviceName)
+	if err != nil {
+		return err
+	}
+
+	dev.Health = health
+	if disable {
+		dev.State = deviceapi.DeviceDisabled
+	}
+	return AddOrUpdateDevice(*dev)
+}
+
 // AddOrUpdateDevice adds device to peerinfo
 func AddOrUpdateDevice(device deviceapi.Info) error {
 	json, err := json.Marshal(device)
@@ -98,6 +134,12 @@ func AddOrUpdateDevice(device deviceapi.Info) error {
 	return nil
 }
 
+// DeleteDevice removes the device of the peer from the store
+func DeleteDevice(peerID, deviceName string) error {
+	_, err := store.Delete(context.TODO(), devicePrefix+peerID+"/"+deviceName)
+	return err
+}
+
 // UpdateDeviceFreeSize updates the actual available size of VG
 func UpdateDeviceFreeSize(peerID, device string) error {
 	dev, err := GetDevice(peerID, device)
@@ -19,6 +19,14 @@ func (p *Plugin) Name() string {
 // RestRoutes returns list of REST API routes to register with Glusterd.
 func (p *Plugin) RestRoutes() route.Routes {
 	return route.Routes{
+		route.Route{
+			Name:         "DeviceBulkAdd",
+			Method:       "POST",
+			Pattern:      "/devices/bulk",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*deviceapi.BulkAddDeviceReq)(nil)),
+			ResponseType: utils.GetTypeString((*deviceapi.BulkAddDeviceResp)(nil)),
+			HandlerFunc:  deviceBulkAddHandler},
 		route.Route{
 			Name:         "DeviceAdd",
 			Method:       "POST",
@@ -27,6 +35,19 @@ func (p *Plugin) RestRoutes() route.Routes {
 			RequestType:  utils.GetTypeString((*deviceapi.AddDeviceReq)(nil)),
 			ResponseType: utils.GetTypeString((*deviceapi.AddDeviceResp)(nil)),
 			HandlerFunc:  deviceAddHandler},
+		route.Route{
+			Name:         "DeviceResize",
+			Method:       "POST",
+			Pattern:      "/devices/{peerid}/{device:.*}/resize",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*deviceapi.Info)(nil)),
+			HandlerFunc:  deviceResizeHandler},
+		route.Route{
+			Name:        "DeviceDrain",
+			Method:      "POST",
+			Pattern:     "/devices/{peerid}/{device:.*}/drain",
+			Version:     1,
+			HandlerFunc: deviceDrainHandler},
 		route.Route{
 			Name:         "DeviceInfo",
 			Method:       "GET",
@@ -48,6 +69,19 @@ func (p *Plugin) RestRoutes() route.Routes {
 			Version:     1,
 			RequestType: utils.GetTypeString((*deviceapi.EditDeviceReq)(nil)),
 			HandlerFunc: deviceEditHandler},
+		route.Route{
+			Name:        "DeviceRemove",
+			Method:      "DELETE",
+			Pattern:     "/devices/{peerid}/{device:.*}",
+			Version:     1,
+			HandlerFunc: deviceRemoveHandler},
+		route.Route{
+			Name:         "DevicesAvailable",
+			Method:       "GET",
+			Pattern:      "/peers/{peerid}/devices/available",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*deviceapi.AvailableDevicesResp)(nil)),
+			HandlerFunc:  deviceAvailableHandler},
 		route.Route{
 			Name:         "DevicesList",
 			Method:       "GET",
@@ -62,4 +96,7 @@ func (p *Plugin) RestRoutes() route.Routes {
 // Glusterd Transaction framework
 func (p *Plugin) RegisterStepFuncs() {
 	transaction.RegisterStepFunc(txnPrepareDevice, "prepare-device")
+	transaction.RegisterStepFunc(txnRemoveDevice, "remove-device")
+	transaction.RegisterStepFunc(txnResizeDevice, "resize-device")
+	transaction.RegisterStepFunc(txnListAvailableDevices, "list-available-devices")
 }
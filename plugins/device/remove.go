@@ -0,0 +1,284 @@
+package device
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/commands/volumes"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/jobs"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+	deviceapi "github.com/gluster/glusterd2/plugins/device/api"
+	"github.com/gluster/glusterd2/plugins/device/deviceutils"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+// deviceRemovePollInterval is how often the removal of a device is retried
+// while the bricks migrated off it are being retired
+const deviceRemovePollInterval = time.Minute
+
+// BricksOnDevice returns the bricks of all the volumes which are on the
+// device of the peer
+func BricksOnDevice(ctx context.Context, peerID, device string) ([]brick.Brickinfo, error) {
+	volumes, err := volume.GetVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var bricks []brick.Brickinfo
+	for _, v := range volumes {
+		for _, b := range v.GetBricks() {
+			if b.PeerID.String() == peerID && b.RootDevice == device {
+				bricks = append(bricks, b)
+			}
+		}
+	}
+	return bricks, nil
+}
+
+// checkMigratable checks that the bricks can be replaced by bricks on other
+// devices chosen by the bricks planner, with self-heal migrating their data
+func checkMigratable(bricks []brick.Brickinfo) error {
+	for _, b := range bricks {
+		v, err := volume.GetVolume(b.VolumeName)
+		if err != nil {
+			return err
+		}
+		switch v.Type {
+		case volume.Replicate, volume.DistReplicate, volume.Disperse, volume.DistDisperse:
+		default:
+			return fmt.Errorf("brick %s can not be migrated: volume %s is not a replicate or disperse volume", b.String(), v.Name)
+		}
+		if !v.IsAutoProvisioned() {
+			return fmt.Errorf("brick %s can not be migrated: volume %s is not auto provisioned", b.String(), v.Name)
+		}
+		if v.State != volume.VolStarted {
+			return fmt.Errorf("brick %s can not be migrated: volume %s is not started", b.String(), v.Name)
+		}
+	}
+	return nil
+}
+
+// RemoveDevice removes the volume group of the device and the device from
+// the store. It fails if the device still has logical volumes on it.
+func RemoveDevice(ctx context.Context, peerID, device string) error {
+	txn, err := transaction.NewTxnWithLocks(ctx, peerID+device)
+	if err != nil {
+		return err
+	}
+	defer txn.Done()
+
+	txn.Nodes = []uuid.UUID{uuid.Parse(peerID)}
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "remove-device",
+			Nodes:  txn.Nodes,
+		},
+	}
+
+	if err := txn.Ctx.Set("peerid", peerID); err != nil {
+		return err
+	}
+	if err := txn.Ctx.Set("device", device); err != nil {
+		return err
+	}
+
+	return txn.Do()
+}
+
+// migrateBricks replaces the bricks by bricks on other devices chosen by the
+// bricks planner. The replaced bricks are retired once self-heal has migrated
+// their data to the new bricks.
+func migrateBricks(ctx context.Context, h *jobs.Handle, bricks []brick.Brickinfo) error {
+	for _, b := range bricks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		req := api.ReplaceBrickReq{
+			SrcPeerID:    b.PeerID.String(),
+			SrcBrickPath: b.Path,
+		}
+		if _, _, err := volumecommands.ReplaceBrick(ctx, b.VolumeName, req); err != nil {
+			return fmt.Errorf("failed to migrate brick %s: %s", b.String(), err)
+		}
+		h.Logf("brick %s of volume %s replaced", b.String(), b.VolumeName)
+	}
+	return nil
+}
+
+// migrateAndRemoveDevice replaces the bricks on the device and removes the
+// device once the replaced bricks have been retired. Bricks are retired after
+// self-heal has migrated their data, so this can take long. This runs as a
+// job on the node which served the request.
+func migrateAndRemoveDevice(ctx context.Context, h *jobs.Handle, peerID, device string, bricks []brick.Brickinfo) error {
+	logger := gdctx.GetReqLogger(ctx).WithField("device", device)
+
+	if err := migrateBricks(ctx, h, bricks); err != nil {
+		return err
+	}
+
+	h.Logf("waiting for the replaced bricks to be retired")
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(deviceRemovePollInterval):
+		}
+
+		if err := RemoveDevice(ctx, peerID, device); err != nil {
+			logger.WithError(err).Debug("device not removed yet")
+			continue
+		}
+
+		h.Logf("device %s removed", device)
+		return nil
+	}
+}
+
+func deviceRemoveHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	peerID := mux.Vars(r)["peerid"]
+	if uuid.Parse(peerID) == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid peer-id passed in url")
+		return
+	}
+
+	device := mux.Vars(r)["device"]
+	if device == "" {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "device not provided in URL")
+		return
+	}
+
+	// Adding prefix (/) to device
+	device = "/" + device
+
+	migrate := r.URL.Query().Get("migrate") == "true"
+
+	if _, err := deviceutils.GetDevice(peerID, device); err != nil {
+		if err == errors.ErrDeviceNotFound {
+			restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		} else {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	bricks, err := BricksOnDevice(ctx, peerID, device)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if len(bricks) == 0 {
+		if err := RemoveDevice(ctx, peerID, device); err != nil {
+			logger.WithError(err).WithField("device", device).Error("Failed to remove device")
+			status, err := restutils.ErrToStatusCode(err)
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+		restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+		return
+	}
+
+	if !migrate {
+		names := make([]string, 0, len(bricks))
+		for _, b := range bricks {
+			names = append(names, b.String())
+		}
+		errMsg := fmt.Sprintf("%s: %s", errors.ErrDeviceInUse, strings.Join(names, ", "))
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, errMsg)
+		return
+	}
+
+	if err := checkMigratable(bricks); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, err)
+		return
+	}
+
+	// Keep the bricks planner from placing new bricks on the device
+	if err := deviceutils.SetDeviceState(peerID, device, deviceapi.DeviceDisabled); err != nil {
+		logger.WithError(err).WithField("device", device).Error("Failed to disable device")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	job, err := jobs.Submit(ctx, api.JobTypeDeviceRemove, "", func(ctx context.Context, h *jobs.Handle) error {
+		return migrateAndRemoveDevice(ctx, h, peerID, device, bricks)
+	})
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendJobAccepted(ctx, w, jobs.CreateJobInfoResp(job))
+}
+
+// deviceDrainHandler disables the device, so that no new bricks are placed on
+// it, and migrates the bricks on it to other devices. The device is kept, so
+// that its disk can be replaced once its bricks have been retired.
+func deviceDrainHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	peerID := mux.Vars(r)["peerid"]
+	if uuid.Parse(peerID) == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid peer-id passed in url")
+		return
+	}
+
+	device := mux.Vars(r)["device"]
+	if device == "" {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "device not provided in URL")
+		return
+	}
+
+	// Adding prefix (/) to device
+	device = "/" + device
+
+	if _, err := deviceutils.GetDevice(peerID, device); err != nil {
+		if err == errors.ErrDeviceNotFound {
+			restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		} else {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	bricks, err := BricksOnDevice(ctx, peerID, device)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := checkMigratable(bricks); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, err)
+		return
+	}
+
+	if err := deviceutils.SetDeviceState(peerID, device, deviceapi.DeviceDisabled); err != nil {
+		logger.WithError(err).WithField("device", device).Error("Failed to disable device")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	job, err := jobs.Submit(ctx, api.JobTypeDeviceDrain, "", func(ctx context.Context, h *jobs.Handle) error {
+		return migrateBricks(ctx, h, bricks)
+	})
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendJobAccepted(ctx, w, jobs.CreateJobInfoResp(job))
+}
@@ -1,6 +1,7 @@
 package device
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -35,78 +36,83 @@ func deviceAddHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	deviceInfo, status, err := AddDevice(ctx, peerID, req)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SetLocationHeader(r, w, strings.TrimLeft(deviceInfo.Device, "/"))
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, deviceInfo)
+}
+
+// AddDevice prepares the device on the peer and registers it. It returns the
+// registered device, or the HTTP status code and error of the failure.
+func AddDevice(ctx context.Context, peerID string, req *deviceapi.AddDeviceReq) (*deviceapi.Info, int, error) {
+	logger := gdctx.GetReqLogger(ctx)
+
 	if req.ProvisionerType == "" {
 		req.ProvisionerType = api.ProvisionerTypeLvm
 	}
 
 	txn, err := transaction.NewTxnWithLocks(ctx, peerID)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, restutils.ErrToStatusCode(err)
 	}
 	defer txn.Done()
 
 	_, err = deviceutils.GetDevice(peerID, req.Device)
 	if err == nil {
 		logger.WithError(err).WithField("device", req.Device).Error("Device already exists")
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "device already exists")
-		return
+		return nil, http.StatusBadRequest, fmt.Errorf("device already exists")
 	}
 
 	if err != errors.ErrDeviceNotFound {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	peerInfo, err := peer.GetPeer(peerID)
 	if err != nil {
 		logger.WithError(err).WithField("peerid", peerID).Error("Peer ID not found in store")
 		if err == errors.ErrPeerNotFound {
-			restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrPeerNotFound)
-		} else {
-			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "failed to get peer details from store")
+			return nil, http.StatusNotFound, errors.ErrPeerNotFound
 		}
-		return
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get peer details from store")
 	}
 
 	txn.Nodes = []uuid.UUID{peerInfo.ID}
 	txn.Steps = []*transaction.Step{
 		{
-			DoFunc: "prepare-device",
-			Nodes:  txn.Nodes,
+			DoFunc:  "prepare-device",
+			Nodes:   txn.Nodes,
+			Timeout: transaction.BrickPrepareTimeout,
 		},
 	}
 
 	err = txn.Ctx.Set("peerid", &peerID)
 	if err != nil {
 		logger.WithError(err).WithField("key", "peerid").WithField("value", peerID).Error("Failed to set key in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	err = txn.Ctx.Set("req", &req)
 	if err != nil {
 		logger.WithError(err).WithField("key", "req").Error("Failed to set key in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	err = txn.Do()
 	if err != nil {
 		logger.WithError(err).Error("Transaction to prepare device failed")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "transaction to prepare device failed")
-		return
+		return nil, http.StatusInternalServerError, fmt.Errorf("transaction to prepare device failed")
 	}
 	deviceInfo, err := deviceutils.GetDevice(peerID, req.Device)
 	if err != nil {
 		logger.WithError(err).WithField("peerid", peerID).Error("Failed to get device from store")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "failed to get device from store")
-		return
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get device from store")
 	}
 
-	restutils.SetLocationHeader(r, w, strings.TrimLeft(deviceInfo.Device, "/"))
-	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, deviceInfo)
+	return deviceInfo, http.StatusCreated, nil
 }
 
 func deviceListHandler(w http.ResponseWriter, r *http.Request) {
@@ -122,7 +128,22 @@ func deviceListHandler(w http.ResponseWriter, r *http.Request) {
 	devName := mux.Vars(r)["device"]
 	devName = "/" + devName
 
-	var err error
+	page, err := restutils.GetPage(r)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	versionDev := devName
+	if versionDev == "/" {
+		versionDev = ""
+	}
+	if version, err := deviceutils.GetDevicesVersion(peerID, versionDev); err == nil {
+		if restutils.CheckNotModified(w, r, version) {
+			return
+		}
+	}
+
 	var devices []deviceapi.Info
 	if peerID == "" {
 		devices, err = deviceutils.GetDevices()
@@ -145,7 +166,10 @@ func deviceListHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, devices)
+	start, end := page.Apply(w, r, len(devices), func(i int) string {
+		return devices[i].PeerID.String() + devices[i].Device
+	})
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, devices[start:end])
 }
 
 func deviceEditHandler(w http.ResponseWriter, r *http.Request) {
@@ -174,6 +198,17 @@ func deviceEditHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Schedulable != nil {
+		if req.State != "" {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "only one of state and schedulable can be set")
+			return
+		}
+		req.State = deviceapi.DeviceDisabled
+		if *req.Schedulable {
+			req.State = deviceapi.DeviceEnabled
+		}
+	}
+
 	if req.State != deviceapi.DeviceEnabled && req.State != deviceapi.DeviceDisabled {
 		logger.WithField("device-state", req.State).Error("State provided in request does not match any supported state")
 		errMsg := fmt.Sprintf("invalid state. Supported states are %s, %s", deviceapi.DeviceEnabled, deviceapi.DeviceDisabled)
@@ -199,3 +234,123 @@ func deviceEditHandler(w http.ResponseWriter, r *http.Request) {
 
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
 }
+
+func deviceResizeHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	peerID := mux.Vars(r)["peerid"]
+	if uuid.Parse(peerID) == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid peer-id passed in url")
+		return
+	}
+
+	device := mux.Vars(r)["device"]
+	if device == "" {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "device not provided in URL")
+		return
+	}
+
+	// Adding prefix (/) to device
+	device = "/" + device
+
+	// The free size of the device is updated under this lock when bricks
+	// are provisioned on it or removed
+	txn, err := transaction.NewTxnWithLocks(ctx, peerID+device)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	if _, err := deviceutils.GetDevice(peerID, device); err != nil {
+		if err == errors.ErrDeviceNotFound {
+			restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		} else {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	txn.Nodes = []uuid.UUID{uuid.Parse(peerID)}
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "resize-device",
+			Nodes:  txn.Nodes,
+		},
+	}
+
+	if err := txn.Ctx.Set("peerid", peerID); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := txn.Ctx.Set("device", device); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("device", device).Error("Transaction to resize device failed")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	deviceInfo, err := deviceutils.GetDevice(peerID, device)
+	if err != nil {
+		logger.WithError(err).WithField("peerid", peerID).Error("Failed to get device from store")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "failed to get device from store")
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, deviceInfo)
+}
+
+func deviceAvailableHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	peerID := mux.Vars(r)["peerid"]
+	if uuid.Parse(peerID) == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "invalid peer-id passed in url")
+		return
+	}
+
+	peerInfo, err := peer.GetPeer(peerID)
+	if err != nil {
+		if err == errors.ErrPeerNotFound {
+			restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		} else {
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+
+	txn.Nodes = []uuid.UUID{peerInfo.ID}
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "list-available-devices",
+			Nodes:  txn.Nodes,
+		},
+	}
+	txn.DisableRollback = true
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("peerid", peerID).Error("Failed to list available devices")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	var devices deviceapi.AvailableDevicesResp
+	if err := txn.Ctx.GetNodeResult(peerInfo.ID, availableDevicesTxnKey, &devices); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, devices)
+}
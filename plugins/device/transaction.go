@@ -1,8 +1,10 @@
 package device
 
 import (
+	"errors"
 	"os"
 
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/fsutils"
@@ -13,6 +15,8 @@ import (
 	"github.com/pborman/uuid"
 )
 
+var errDeviceHasLvs = errors.New("device has logical volumes on it")
+
 func txnPrepareDevice(c transaction.TxnCtx) error {
 	var req deviceapi.AddDeviceReq
 	if err := c.Get("req", &req); err != nil {
@@ -106,3 +110,105 @@ func txnPrepareDeviceLoop(req deviceapi.AddDeviceReq, c transaction.TxnCtx) erro
 	}
 	return nil
 }
+
+func txnRemoveDevice(c transaction.TxnCtx) error {
+	var peerID, device string
+	if err := c.Get("peerid", &peerID); err != nil {
+		c.Logger().WithError(err).WithField("key", "peerid").Error("Failed to get key from transaction context")
+		return err
+	}
+	if err := c.Get("device", &device); err != nil {
+		c.Logger().WithError(err).WithField("key", "device").Error("Failed to get key from transaction context")
+		return err
+	}
+
+	dev, err := deviceutils.GetDevice(peerID, device)
+	if err != nil {
+		return err
+	}
+
+	if dev.ProvisionerType != api.ProvisionerTypeLoop {
+		// Bricks and snapshots left on the device keep its space in use
+		availableSize, _, err := lvmutils.GetVgAvailableSize(dev.VgName())
+		if err != nil {
+			return err
+		}
+		if availableSize < dev.TotalSize {
+			return errDeviceHasLvs
+		}
+
+		if err := lvmutils.RemoveVG(dev.VgName()); err != nil {
+			c.Logger().WithError(err).WithField("device", device).Error("Failed to remove volume group")
+			return err
+		}
+		if err := lvmutils.RemovePV(dev.Device); err != nil {
+			c.Logger().WithError(err).WithField("device", device).Error("Failed to remove physical volume")
+			return err
+		}
+	}
+
+	return deviceutils.DeleteDevice(peerID, device)
+}
+
+func txnResizeDevice(c transaction.TxnCtx) error {
+	var peerID, device string
+	if err := c.Get("peerid", &peerID); err != nil {
+		c.Logger().WithError(err).WithField("key", "peerid").Error("Failed to get key from transaction context")
+		return err
+	}
+	if err := c.Get("device", &device); err != nil {
+		c.Logger().WithError(err).WithField("key", "device").Error("Failed to get key from transaction context")
+		return err
+	}
+
+	dev, err := deviceutils.GetDevice(peerID, device)
+	if err != nil {
+		return err
+	}
+
+	if dev.ProvisionerType == api.ProvisionerTypeLoop {
+		stat, err := fsutils.StatFs(dev.Device)
+		if err != nil {
+			c.Logger().WithError(err).WithField("device", device).Error("Failed to get size of device")
+			return err
+		}
+		dev.TotalSize = stat.Total
+		dev.AvailableSize = stat.Free
+		dev.UsedSize = stat.Total - stat.Free
+		return deviceutils.AddOrUpdateDevice(*dev)
+	}
+
+	// The volume group grows with the physical volume, which takes up the
+	// new size of the device
+	if err := lvmutils.ResizePV(dev.Device); err != nil {
+		c.Logger().WithError(err).WithField("device", device).Error("Failed to resize physical volume")
+		return err
+	}
+
+	totalSize, err := lvmutils.GetVgSize(dev.VgName())
+	if err != nil {
+		return err
+	}
+	availableSize, extentSize, err := lvmutils.GetVgAvailableSize(dev.VgName())
+	if err != nil {
+		return err
+	}
+	dev.TotalSize = totalSize
+	dev.AvailableSize = availableSize
+	dev.UsedSize = totalSize - availableSize
+	dev.ExtentSize = extentSize
+	return deviceutils.AddOrUpdateDevice(*dev)
+}
+
+// availableDevicesTxnKey is the key of the node result of the
+// list-available-devices step
+const availableDevicesTxnKey = "availabledevices"
+
+func txnListAvailableDevices(c transaction.TxnCtx) error {
+	devices, err := deviceutils.GetAvailableDevices()
+	if err != nil {
+		c.Logger().WithError(err).Error("failed to list available devices")
+		return err
+	}
+	return c.SetNodeResult(gdctx.MyUUID, availableDevicesTxnKey, devices)
+}
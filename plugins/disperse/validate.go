@@ -0,0 +1,23 @@
+package disperse
+
+import (
+	"errors"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+)
+
+var names = [...]string{"disperse", "ec"}
+
+func validateOptions(v *volume.Volinfo, key string, value string) error {
+	if v.Type != volume.Disperse && v.Type != volume.DistDisperse {
+		return errors.New("option cannot be set for a non disperse volume")
+	}
+	return nil
+}
+
+func init() {
+	for _, name := range names {
+		xlator.RegisterValidationFunc(name, validateOptions)
+	}
+}
@@ -0,0 +1,28 @@
+package disperse
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateOptions(t *testing.T) {
+	tests := []struct {
+		volType volume.VolType
+		valid   bool
+	}{
+		{volume.Disperse, true},
+		{volume.DistDisperse, true},
+		{volume.Distribute, false},
+		{volume.Replicate, false},
+		{volume.DistReplicate, false},
+	}
+
+	for _, tt := range tests {
+		v := &volume.Volinfo{Type: tt.volType}
+		err := validateOptions(v, "eager-lock", "on")
+		assert.Equal(t, tt.valid, err == nil, tt.volType.String())
+	}
+}
@@ -0,0 +1,108 @@
+package events
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/streadway/amqp"
+)
+
+const (
+	amqpURLOpt      = "events.amqp.url"
+	amqpExchangeOpt = "events.amqp.exchange"
+)
+
+// amqpSink publishes events to a topic exchange of an AMQP 0-9-1 broker,
+// with the name of the event as routing key. The exchange is declared if it
+// doesn't exist, and the events are published with publisher confirms.
+type amqpSink struct {
+	exchange string
+	conn     *amqp.Connection
+	ch       *amqp.Channel
+	confirms chan amqp.Confirmation
+}
+
+func newAMQPSink(conf []string) (busSink, error) {
+	conn, err := amqp.Dial(conf[0])
+	if err != nil {
+		return nil, err
+	}
+
+	s := &amqpSink{exchange: conf[1], conn: conn}
+	if err := s.setup(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *amqpSink) setup() error {
+	var err error
+	if s.ch, err = s.conn.Channel(); err != nil {
+		return err
+	}
+	if err := s.ch.ExchangeDeclare(s.exchange, "topic", true, false, false, false, nil); err != nil {
+		return err
+	}
+	if err := s.ch.Confirm(false); err != nil {
+		return err
+	}
+	s.confirms = s.ch.NotifyPublish(make(chan amqp.Confirmation, 1))
+	return nil
+}
+
+func (s *amqpSink) publish(e *api.Event, body []byte) error {
+	headers := amqp.Table{}
+	for k, v := range busHeaders(e) {
+		headers[k] = v
+	}
+
+	err := s.ch.Publish(s.exchange, e.Name, false, false, amqp.Publishing{
+		Headers:      headers,
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    e.ID.String(),
+		Timestamp:    e.Timestamp,
+		Type:         e.Name,
+		AppId:        "glusterd2",
+		Body:         body,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Events are published one at a time, the next confirmation is the
+	// one of this event
+	select {
+	case c, ok := <-s.confirms:
+		if !ok {
+			return errors.New("channel closed before the event was confirmed")
+		}
+		if !c.Ack {
+			return errors.New("event rejected by the broker")
+		}
+		return nil
+	case <-time.After(busPublishTimeout):
+		return errors.New("timed out waiting for the broker to confirm the event")
+	}
+}
+
+func (s *amqpSink) close() error {
+	return s.conn.Close()
+}
+
+func validateAMQPOption(key, value string) error {
+	if key == amqpURLOpt {
+		_, err := amqp.ParseURI(value)
+		return err
+	}
+	return nil
+}
+
+func init() {
+	options.RegisterClusterOpValidationFunc(amqpURLOpt, validateAMQPOption)
+	options.RegisterSecretClusterOption(amqpURLOpt)
+}
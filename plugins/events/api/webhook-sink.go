@@ -0,0 +1,64 @@
+package api
+
+import (
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// WebhookRetryPolicy decides how many times and how often the delivery of
+// an event to a webhook is retried when it fails
+type WebhookRetryPolicy struct {
+	// MaxRetries is the number of times a failed delivery is retried
+	MaxRetries int `json:"max-retries"`
+	// Backoff is the number of seconds before the first retry, doubled for
+	// every further retry up to MaxBackoff seconds
+	Backoff    int `json:"backoff"`
+	MaxBackoff int `json:"max-backoff"`
+}
+
+// WebhookCreateReq is the request to register a webhook to which matching
+// events are delivered. An event matches if its name or namespace, like
+// volume for volume.started, is one of Events, or if Events is empty.
+// Deliveries are signed with Secret, which is generated if not given.
+type WebhookCreateReq struct {
+	URL    string              `json:"url"`
+	Events []string            `json:"events,omitempty"`
+	Secret string              `json:"secret,omitempty"`
+	Retry  *WebhookRetryPolicy `json:"retry,omitempty"`
+}
+
+// WebhookEditReq is the request to change a webhook. Only the fields which
+// are set are changed.
+type WebhookEditReq struct {
+	URL    string              `json:"url,omitempty"`
+	Events *[]string           `json:"events,omitempty"`
+	Secret string              `json:"secret,omitempty"`
+	Retry  *WebhookRetryPolicy `json:"retry,omitempty"`
+}
+
+// WebhookInfo describes a registered webhook, without its secret
+type WebhookInfo struct {
+	ID        uuid.UUID          `json:"id"`
+	URL       string             `json:"url"`
+	Events    []string           `json:"events,omitempty"`
+	Retry     WebhookRetryPolicy `json:"retry"`
+	CreatedAt time.Time          `json:"created-at"`
+	UpdatedAt time.Time          `json:"updated-at"`
+}
+
+// WebhookCreateResp is the response sent for a request to register a
+// webhook. It is the only response which has the secret of the webhook.
+type WebhookCreateResp struct {
+	WebhookInfo
+	Secret string `json:"secret"`
+}
+
+// WebhookGetResp is the response sent for a request to get a webhook
+type WebhookGetResp WebhookInfo
+
+// WebhookEditResp is the response sent for a request to change a webhook
+type WebhookEditResp WebhookInfo
+
+// WebhookInfoList is the response sent for a request to list the webhooks
+type WebhookInfoList []WebhookInfo
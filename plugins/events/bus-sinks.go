@@ -0,0 +1,240 @@
+package events
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gd2events "github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	busEventsOpt = "events.bus-events"
+
+	// busPublishTimeout is the time a bus has to acknowledge an event
+	busPublishTimeout = 10 * time.Second
+)
+
+// busSink publishes events to a message bus it is connected to
+type busSink interface {
+	// publish publishes the event, whose body is the event as JSON, and
+	// returns once the bus has acknowledged it
+	publish(e *api.Event, body []byte) error
+	close() error
+}
+
+// busSinkKind is a kind of message bus events can be published to,
+// configured with cluster options. A bus is connected to when the first
+// event is published to it, and connected to again when its options change
+// or when publishing fails.
+type busSinkKind struct {
+	name string
+	// options are the cluster options configuring the bus, publishing to
+	// the bus is disabled while the first one is not set
+	options []string
+	connect func(conf []string) (busSink, error)
+
+	mu   sync.Mutex
+	conf []string
+	sink busSink
+}
+
+var busSinkKinds = []*busSinkKind{
+	{
+		name:    "kafka",
+		options: []string{kafkaBrokersOpt, kafkaTopicOpt, kafkaTLSOpt},
+		connect: newKafkaSink,
+	},
+	{
+		name:    "nats",
+		options: []string{natsURLOpt, natsSubjectOpt},
+		connect: newNATSSink,
+	},
+	{
+		name:    "amqp",
+		options: []string{amqpURLOpt, amqpExchangeOpt},
+		connect: newAMQPSink,
+	},
+}
+
+// busHeaders returns the headers sent with an event by the buses which
+// support them, the same as the headers of webhook deliveries except the
+// signature
+func busHeaders(e *api.Event) map[string]string {
+	return map[string]string{
+		webhookEventHeader:     e.Name,
+		webhookDeliveryHeader:  e.ID.String(),
+		webhookTimestampHeader: strconv.FormatInt(time.Now().Unix(), 10),
+	}
+}
+
+// splitOption returns the comma separated values of a cluster option
+func splitOption(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// config returns the values of the options of the bus, or nil if publishing
+// to the bus is disabled
+func (k *busSinkKind) config() ([]string, error) {
+	conf := make([]string, len(k.options))
+	for i, key := range k.options {
+		value, err := options.GetClusterOption(key)
+		if err != nil {
+			return nil, err
+		}
+		conf[i] = value
+	}
+	if conf[0] == "" {
+		return nil, nil
+	}
+	return conf, nil
+}
+
+func equalConf(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// closeSink closes the connection to the bus, if any. k.mu must be held.
+func (k *busSinkKind) closeSink() {
+	if k.sink == nil {
+		return
+	}
+	if err := k.sink.close(); err != nil {
+		log.WithError(err).WithField("sink", k.name).Warn("failed to close connection to message bus")
+	}
+	k.sink, k.conf = nil, nil
+}
+
+// disable closes the connection to the bus, once publishing to it has been
+// disabled
+func (k *busSinkKind) disable() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.closeSink()
+}
+
+// publish publishes the event to the bus with the given configuration,
+// connecting to it first if needed. Events are published to a bus one at a
+// time. The connection is closed if publishing fails, so that the next
+// event is published on a new connection.
+func (k *busSinkKind) publish(conf []string, e *api.Event, body []byte) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.sink != nil && !equalConf(k.conf, conf) {
+		k.closeSink()
+	}
+	if k.sink == nil {
+		sink, err := k.connect(conf)
+		if err != nil {
+			return err
+		}
+		k.sink, k.conf = sink, conf
+	}
+
+	if err := k.sink.publish(e, body); err != nil {
+		k.closeSink()
+		return err
+	}
+	return nil
+}
+
+// publishWithRetries publishes the event to the bus, retrying failed
+// attempts as per the default retry policy of webhooks
+func (k *busSinkKind) publishWithRetries(conf []string, e *api.Event, body []byte) {
+	logger := log.WithFields(log.Fields{
+		"sink":     k.name,
+		"event":    e.Name,
+		"event.id": e.ID.String(),
+	})
+
+	retry := defaultWebhookRetry
+	backoff := time.Duration(retry.Backoff) * time.Second
+	maxBackoff := time.Duration(retry.MaxBackoff) * time.Second
+	for attempt := 0; ; attempt++ {
+		err := k.publish(conf, e, body)
+		if err == nil {
+			logger.Debug("published event to message bus")
+			return
+		}
+		if attempt >= retry.MaxRetries {
+			logger.WithError(err).WithField("attempts", attempt+1).Error("failed to publish event to message bus")
+			return
+		}
+
+		logger.WithError(err).WithField("attempt", attempt+1).Warn("failed to publish event to message bus, will retry")
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// busSinksNotifier publishes events to the message buses configured with
+// the cluster options. Like for webhooks, events are published only by the
+// peer where they happened.
+type busSinksNotifier struct{}
+
+func (n *busSinksNotifier) Handle(e *api.Event) {
+	if !uuid.Equal(e.Origin, gdctx.MyUUID) {
+		return
+	}
+
+	types, err := options.GetClusterOption(busEventsOpt)
+	if err != nil {
+		log.WithError(err).Error("failed to get events published to message buses")
+		return
+	}
+	if !matchEventType(e.Name, normalizeWebhookEvents(splitOption(types))) {
+		return
+	}
+
+	body, err := json.Marshal(e)
+	if err != nil {
+		log.WithError(err).WithField("event", e.Name).Error("failed to publish event to message buses, failed to marshal event")
+		return
+	}
+
+	for _, k := range busSinkKinds {
+		conf, err := k.config()
+		if err != nil {
+			log.WithError(err).WithField("sink", k.name).Error("failed to get message bus configuration")
+			continue
+		}
+		if conf == nil {
+			k.disable()
+			continue
+		}
+		go k.publishWithRetries(conf, e, body)
+	}
+}
+
+func (n *busSinksNotifier) Events() []string {
+	return []string{}
+}
+
+func init() {
+	gd2events.Register(new(busSinksNotifier))
+}
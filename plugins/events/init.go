@@ -55,6 +55,62 @@ func (p *Plugin) RestRoutes() route.Routes {
 			// FIXME: This type is not in 'eventsapi'
 			ResponseType: utils.GetTypeString((*api.Event)(nil)),
 			HandlerFunc:  eventsListHandler},
+		route.Route{
+			Name:         "WebhookSinkCreate",
+			Method:       "POST",
+			Pattern:      "/events/webhooks",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*eventsapi.WebhookCreateReq)(nil)),
+			ResponseType: utils.GetTypeString((*eventsapi.WebhookCreateResp)(nil)),
+			HandlerFunc:  webhookSinkCreateHandler},
+		route.Route{
+			Name:         "WebhookSinkList",
+			Method:       "GET",
+			Pattern:      "/events/webhooks",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*eventsapi.WebhookInfoList)(nil)),
+			HandlerFunc:  webhookSinkListHandler},
+		route.Route{
+			Name:         "WebhookSinkGet",
+			Method:       "GET",
+			Pattern:      "/events/webhooks/{webhookid}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*eventsapi.WebhookGetResp)(nil)),
+			HandlerFunc:  webhookSinkGetHandler},
+		route.Route{
+			Name:         "WebhookSinkEdit",
+			Method:       "POST",
+			Pattern:      "/events/webhooks/{webhookid}/edit",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*eventsapi.WebhookEditReq)(nil)),
+			ResponseType: utils.GetTypeString((*eventsapi.WebhookEditResp)(nil)),
+			HandlerFunc:  webhookSinkEditHandler},
+		route.Route{
+			Name:        "WebhookSinkDelete",
+			Method:      "DELETE",
+			Pattern:     "/events/webhooks/{webhookid}",
+			Version:     1,
+			HandlerFunc: webhookSinkDeleteHandler},
+		route.Route{
+			Name:        "WebhookSinkTest",
+			Method:      "POST",
+			Pattern:     "/events/webhooks/{webhookid}/test",
+			Version:     1,
+			HandlerFunc: webhookSinkTestHandler},
+		route.Route{
+			Name:         "EventTypesList",
+			Method:       "GET",
+			Pattern:      "/events/types",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.EventTypeList)(nil)),
+			HandlerFunc:  eventTypesListHandler},
+		route.Route{
+			Name:         "EventsStream",
+			Method:       "GET",
+			Pattern:      "/events/stream",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*api.Event)(nil)),
+			HandlerFunc:  eventsStreamHandler},
 	}
 }
 
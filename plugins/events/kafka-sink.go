@@ -0,0 +1,95 @@
+package events
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"regexp"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/Shopify/sarama"
+)
+
+const (
+	kafkaBrokersOpt = "events.kafka.brokers"
+	kafkaTopicOpt   = "events.kafka.topic"
+	kafkaTLSOpt     = "events.kafka.tls"
+)
+
+var kafkaTopicRegexp = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,249}$`)
+
+// kafkaSink publishes events to a Kafka topic, keyed by their name so that
+// the events of a type stay in order
+type kafkaSink struct {
+	topic    string
+	producer sarama.SyncProducer
+}
+
+func newKafkaSink(conf []string) (busSink, error) {
+	brokers, topic := splitOption(conf[0]), conf[1]
+	useTLS, err := options.StringToBoolean(conf[2])
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := sarama.NewConfig()
+	cfg.ClientID = "glusterd2"
+	// Record headers need Kafka 0.11
+	cfg.Version = sarama.V0_11_0_0
+	cfg.Net.DialTimeout = busPublishTimeout
+	cfg.Net.TLS.Enable = useTLS
+	if useTLS {
+		cfg.Net.TLS.Config = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	cfg.Producer.RequiredAcks = sarama.WaitForAll
+	cfg.Producer.Timeout = busPublishTimeout
+	cfg.Producer.Return.Successes = true
+	// Failed events are retried by the sink
+	cfg.Producer.Retry.Max = 0
+
+	producer, err := sarama.NewSyncProducer(brokers, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaSink{topic: topic, producer: producer}, nil
+}
+
+func (s *kafkaSink) publish(e *api.Event, body []byte) error {
+	msg := &sarama.ProducerMessage{
+		Topic: s.topic,
+		Key:   sarama.StringEncoder(e.Name),
+		Value: sarama.ByteEncoder(body),
+	}
+	for k, v := range busHeaders(e) {
+		msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(k), Value: []byte(v)})
+	}
+	_, _, err := s.producer.SendMessage(msg)
+	return err
+}
+
+func (s *kafkaSink) close() error {
+	return s.producer.Close()
+}
+
+func validateKafkaOption(key, value string) error {
+	switch key {
+	case kafkaBrokersOpt:
+		for _, broker := range splitOption(value) {
+			if _, _, err := net.SplitHostPort(broker); err != nil {
+				return fmt.Errorf("invalid broker %s, must be host:port", broker)
+			}
+		}
+	case kafkaTopicOpt:
+		if !kafkaTopicRegexp.MatchString(value) {
+			return fmt.Errorf("invalid topic %s", value)
+		}
+	}
+	return nil
+}
+
+func init() {
+	options.RegisterClusterOpValidationFunc(kafkaBrokersOpt, validateKafkaOption)
+	options.RegisterClusterOpValidationFunc(kafkaTopicOpt, validateKafkaOption)
+}
@@ -0,0 +1,70 @@
+package events
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/options"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	nats "github.com/nats-io/go-nats"
+)
+
+const (
+	natsURLOpt     = "events.nats.url"
+	natsSubjectOpt = "events.nats.subject"
+)
+
+// natsSink publishes events to NATS, on the subject made of the configured
+// subject and the name of the event, like gluster.events.volume.started, so
+// that subscribers can pick events with wildcards
+type natsSink struct {
+	subject string
+	conn    *nats.Conn
+}
+
+func newNATSSink(conf []string) (busSink, error) {
+	conn, err := nats.Connect(conf[0], nats.Name("glusterd2"), nats.Timeout(busPublishTimeout))
+	if err != nil {
+		return nil, err
+	}
+	return &natsSink{subject: conf[1], conn: conn}, nil
+}
+
+// publish publishes the event and waits for the server to have processed
+// it, NATS doesn't acknowledge messages
+func (s *natsSink) publish(e *api.Event, body []byte) error {
+	if err := s.conn.Publish(s.subject+"."+e.Name, body); err != nil {
+		return err
+	}
+	return s.conn.FlushTimeout(busPublishTimeout)
+}
+
+func (s *natsSink) close() error {
+	s.conn.Close()
+	return nil
+}
+
+func validateNATSOption(key, value string) error {
+	switch key {
+	case natsURLOpt:
+		for _, u := range splitOption(value) {
+			parsed, err := url.Parse(u)
+			if err != nil || (parsed.Scheme != "nats" && parsed.Scheme != "tls") || parsed.Host == "" {
+				return fmt.Errorf("invalid url %s, must be a nats:// or tls:// url", u)
+			}
+		}
+	case natsSubjectOpt:
+		if strings.ContainsAny(value, " \t*>") || strings.HasPrefix(value, ".") || strings.HasSuffix(value, ".") || strings.Contains(value, "..") {
+			return fmt.Errorf("invalid subject %s", value)
+		}
+	}
+	return nil
+}
+
+func init() {
+	options.RegisterClusterOpValidationFunc(natsURLOpt, validateNATSOption)
+	options.RegisterClusterOpValidationFunc(natsSubjectOpt, validateNATSOption)
+	options.RegisterSecretClusterOption(natsURLOpt)
+}
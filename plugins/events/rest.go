@@ -1,19 +1,27 @@
 package events
 
 import (
+	"encoding/json"
 	"net/http"
+	"time"
 
 	gd2events "github.com/gluster/glusterd2/glusterd2/events"
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
 	"github.com/gluster/glusterd2/glusterd2/peer"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/errors"
 	eventsapi "github.com/gluster/glusterd2/plugins/events/api"
+
+	"github.com/gorilla/mux"
 )
 
 const (
 	eventsapiPrefix string = "events/"
+
+	// eventTest is sent to webhooks to test them
+	eventTest = "test"
 )
 
 func webhookAddHandler(w http.ResponseWriter, r *http.Request) {
@@ -112,10 +120,34 @@ func webhookListHandler(w http.ResponseWriter, r *http.Request) {
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
 }
 
+// eventsListHandler lists the events of all the peers kept in the store,
+// oldest first, optionally filtered by the types, volumes and severity given
+// as query parameters like for the event stream, and by the time since which
+// they happened
 func eventsListHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	events, err := GetEventsList()
+	page, err := restutils.GetPage(r)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	var since time.Time
+	if v := r.URL.Query().Get("since"); v != "" {
+		if since, err = time.Parse(time.RFC3339, v); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrInvalidTime)
+			return
+		}
+	}
+
+	filter, err := newStreamFilter(r)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	history, err := gd2events.History(since)
 	if err != nil {
 		restutils.SendHTTPError(
 			ctx, w, http.StatusInternalServerError,
@@ -123,7 +155,22 @@ func eventsListHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, events)
+	events := make([]*api.Event, 0, len(history))
+	for _, e := range history {
+		if filter.match(e) {
+			events = append(events, e)
+		}
+	}
+
+	start, end := page.Apply(w, r, len(events), func(i int) string { return events[i].ID.String() })
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, events[start:end])
+}
+
+// eventTypesListHandler lists the types of the events created by glusterd2
+// and the gluster processes
+func eventTypesListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, api.EventTypeList(gd2events.Types()))
 }
 
 func checkConnection(c transaction.TxnCtx) error {
@@ -132,7 +179,7 @@ func checkConnection(c transaction.TxnCtx) error {
 	if err := c.Get("req", &req); err != nil {
 		return err
 	}
-	e := gd2events.New("TEST", map[string]string{}, false)
+	e := gd2events.New(eventTest, map[string]string{}, false)
 	return gd2events.WebhookPublish(&req, e)
 }
 
@@ -194,3 +241,142 @@ func webhookTestHandler(w http.ResponseWriter, r *http.Request) {
 
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
 }
+
+func webhookSinkCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req eventsapi.WebhookCreateReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	s, err := newWebhookSink(&req)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := saveWebhookSink(s); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	logger.WithField("webhook", s.ID.String()).WithField("url", s.URL).Info("webhook registered")
+
+	resp := &eventsapi.WebhookCreateResp{
+		WebhookInfo: *createWebhookInfoResp(s),
+		Secret:      s.Secret,
+	}
+	restutils.SetLocationHeader(r, w, s.ID.String())
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, resp)
+}
+
+func webhookSinkListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	page, err := restutils.GetPage(r)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	sinks, err := getWebhookSinks()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := make(eventsapi.WebhookInfoList, len(sinks))
+	for i, s := range sinks {
+		resp[i] = *createWebhookInfoResp(s)
+	}
+
+	start, end := page.Apply(w, r, len(resp), func(i int) string { return resp[i].ID.String() })
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp[start:end])
+}
+
+func webhookSinkGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	s, err := getWebhookSink(mux.Vars(r)["webhookid"])
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := (*eventsapi.WebhookGetResp)(createWebhookInfoResp(s))
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func webhookSinkEditHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req eventsapi.WebhookEditReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	s, err := editWebhookSink(mux.Vars(r)["webhookid"], &req)
+	switch err {
+	case nil:
+	case errors.ErrWebhookInvalidURL, errors.ErrWebhookInvalidRetry:
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	default:
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	resp := (*eventsapi.WebhookEditResp)(createWebhookInfoResp(s))
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func webhookSinkDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := deleteWebhookSink(mux.Vars(r)["webhookid"]); err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+// webhookSinkTestHandler delivers a test event to the webhook, once and
+// without retries, and reports whether the delivery succeeded
+func webhookSinkTestHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	s, err := getWebhookSink(mux.Vars(r)["webhookid"])
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	e := gd2events.New(eventTest, map[string]string{}, false)
+	body, err := json.Marshal(e)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if _, err := s.deliver(e, body); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadGateway, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+}
+
+func init() {
+	gd2events.RegisterTypes(api.EventType{
+		Name:        eventTest,
+		Description: "Sent to a webhook when it is tested",
+	})
+}
@@ -3,12 +3,10 @@ package events
 import (
 	"context"
 	"encoding/json"
-	"sort"
 	"strings"
 
 	"github.com/coreos/etcd/clientv3"
 	"github.com/gluster/glusterd2/glusterd2/store"
-	"github.com/gluster/glusterd2/pkg/api"
 	eventsapi "github.com/gluster/glusterd2/plugins/events/api"
 
 	log "github.com/sirupsen/logrus"
@@ -16,7 +14,6 @@ import (
 
 const (
 	webhookPrefix string = "config/events/webhooks/"
-	eventsPrefix         = "events/"
 )
 
 func webhookExists(webhookURL string) (bool, error) {
@@ -73,29 +70,3 @@ func deleteWebhook(webhookURL string) error {
 	_, e := store.Delete(context.TODO(), webhookPrefix+strings.Replace(webhookURL, "/", "|", -1))
 	return e
 }
-
-// GetEventsList returns list of Events recorded in last few minutes
-func GetEventsList() ([]*api.Event, error) {
-	resp, e := store.Get(context.TODO(), eventsPrefix, clientv3.WithPrefix())
-	if e != nil {
-		return nil, e
-	}
-
-	events := make([]*api.Event, len(resp.Kvs))
-
-	for i, kv := range resp.Kvs {
-		var ev api.Event
-
-		if err := json.Unmarshal(kv.Value, &ev); err != nil {
-			log.WithError(err).WithField(
-				"event", string(kv.Key)).Error("Failed to unmarshal event")
-			continue
-		}
-
-		events[i] = &ev
-	}
-	// Sort based on Event Timestamp
-	sort.Slice(events, func(i, j int) bool { return int64(events[j].Timestamp.Sub(events[i].Timestamp)) > 0 })
-
-	return events, nil
-}
@@ -0,0 +1,206 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	gd2events "github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+)
+
+const (
+	// streamBufferSize is the number of events kept for a stream whose
+	// client is slow to read them, further events are dropped
+	streamBufferSize = 64
+	// streamKeepAlive is the interval at which a comment is sent on an idle
+	// stream, so that proxies and clients don't close it
+	streamKeepAlive = 15 * time.Second
+)
+
+// streamFilter selects the events sent on a stream
+type streamFilter struct {
+	types   []string
+	volumes []string
+	// severity is the lowest severity of the events selected
+	severity api.EventSeverity
+}
+
+// newStreamFilter returns the filter given by the type, volume and severity
+// query parameters of the request
+func newStreamFilter(r *http.Request) (*streamFilter, error) {
+	f := &streamFilter{
+		types:   splitParam(r, "type"),
+		volumes: splitParam(r, "volume"),
+	}
+	if s := r.URL.Query().Get("severity"); s != "" {
+		f.severity = api.EventSeverity(strings.ToLower(s))
+		if f.severity.Level() < 0 {
+			return nil, errors.ErrInvalidEventSeverity
+		}
+	}
+	return f, nil
+}
+
+// splitParam returns the comma separated values of all the query parameters
+// with the given name
+func splitParam(r *http.Request, name string) []string {
+	var values []string
+	for _, v := range r.URL.Query()[name] {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				values = append(values, strings.ToLower(s))
+			}
+		}
+	}
+	return values
+}
+
+// matchEventType returns true if the event name is one of the types or in
+// the namespace of one of them, like volume.started in volume, or if there
+// are no types
+func matchEventType(name string, types []string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if name == t || strings.HasPrefix(name, t+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns true if the event is of one of the types, about one of the
+// volumes and at least as severe as the severity of the filter. Events
+// created before events had a severity are taken as info.
+func (f *streamFilter) match(e *api.Event) bool {
+	if !matchEventType(e.Name, f.types) {
+		return false
+	}
+
+	if f.severity != "" {
+		severity := e.Severity
+		if severity == "" {
+			severity = api.EventSeverityInfo
+		}
+		if severity.Level() < f.severity.Level() {
+			return false
+		}
+	}
+
+	if len(f.volumes) > 0 {
+		volname := e.Data["volume.name"]
+		if volname == "" {
+			volname = e.Data["volume"]
+		}
+		for _, v := range f.volumes {
+			if strings.EqualFold(volname, v) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// eventsStreamHandler streams the events seen by the peer, which include
+// the global events of all the peers, as Server-Sent Events, optionally
+// filtered by the types, volumes and severity given as query parameters.
+// The stream takes over the connection, as it must outlive the write
+// timeout of the REST server.
+func eventsStreamHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		restutils.SendHTTPError(ctx, w, http.StatusNotAcceptable, errors.ErrEventStreamAccept)
+		return
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, "streaming is not supported by the server")
+		return
+	}
+
+	filter, err := newStreamFilter(r)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		logger.WithError(err).Error("failed to take over connection for event stream")
+		return
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Time{})
+
+	// Send the headers set so far, like the request ID, along with the ones
+	// of the stream
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "close")
+	fmt.Fprintf(rw, "HTTP/1.1 200 OK\r\n")
+	header.Write(rw)
+	fmt.Fprintf(rw, "\r\n")
+	if err := rw.Flush(); err != nil {
+		return
+	}
+
+	// Events are handled concurrently by the events framework, a slow
+	// client must not hold it up
+	ch := make(chan *api.Event, streamBufferSize)
+	id := gd2events.Register(gd2events.NewHandler(func(e *api.Event) {
+		if !filter.match(e) {
+			return
+		}
+		select {
+		case ch <- e:
+		default:
+			logger.WithField("event", e.Name).Warn("event stream client is too slow, dropped event")
+		}
+	}))
+	defer gd2events.Unregister(id)
+
+	// The client doesn't send anything on the stream, a read returns only
+	// when the connection is closed
+	closed := make(chan struct{})
+	go func() {
+		rw.ReadByte()
+		close(closed)
+	}()
+
+	logger.WithField("filter", fmt.Sprintf("%+v", *filter)).Info("event stream started")
+	defer logger.Info("event stream ended")
+
+	ticker := time.NewTicker(streamKeepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case e := <-ch:
+			data, err := json.Marshal(e)
+			if err != nil {
+				logger.WithError(err).WithField("event", e.Name).Error("failed to marshal event")
+				continue
+			}
+			fmt.Fprintf(rw, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Name, data)
+		case <-ticker.C:
+			fmt.Fprintf(rw, ": keep-alive\n\n")
+		case <-closed:
+			return
+		}
+		if err := rw.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package events
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStreamFilter(t *testing.T) {
+	tests := []struct {
+		query  string
+		filter *streamFilter
+		err    error
+	}{
+		{"", &streamFilter{}, nil},
+		{"type=Volume,peer.added&type=brick", &streamFilter{types: []string{"volume", "peer.added", "brick"}}, nil},
+		{"volume=vol1,+,Vol2&volume=", &streamFilter{volumes: []string{"vol1", "vol2"}}, nil},
+		{"severity=Warning", &streamFilter{severity: api.EventSeverityWarning}, nil},
+		{"type=volume&volume=vol1&severity=critical",
+			&streamFilter{types: []string{"volume"}, volumes: []string{"vol1"}, severity: api.EventSeverityCritical}, nil},
+		{"severity=fatal", nil, gderrors.ErrInvalidEventSeverity},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/v1/events/stream?"+tt.query, nil)
+		filter, err := newStreamFilter(r)
+		assert.Equal(t, tt.err, err, tt.query)
+		assert.Equal(t, tt.filter, filter, tt.query)
+	}
+}
+
+func TestMatchEventType(t *testing.T) {
+	tests := []struct {
+		name  string
+		types []string
+		match bool
+	}{
+		{"volume.started", nil, true},
+		{"volume.started", []string{"volume"}, true},
+		{"volume.started", []string{"peer", "volume.started"}, true},
+		{"volume.started", []string{"volume.stopped"}, false},
+		{"volume.started", []string{"vol"}, false},
+		{"volumes.started", []string{"volume"}, false},
+		{"volume", []string{"volume.started"}, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.match, matchEventType(tt.name, tt.types), "%s in %v", tt.name, tt.types)
+	}
+}
+
+func TestStreamFilterMatch(t *testing.T) {
+	event := func(name string, severity api.EventSeverity, data map[string]string) *api.Event {
+		return &api.Event{Name: name, Severity: severity, Data: data}
+	}
+	started := event("volume.started", api.EventSeverityInfo, map[string]string{"volume.name": "Vol1"})
+
+	tests := []struct {
+		name   string
+		filter streamFilter
+		event  *api.Event
+		match  bool
+	}{
+		{"no filter", streamFilter{}, started, true},
+		{"type", streamFilter{types: []string{"volume"}}, started, true},
+		{"other type", streamFilter{types: []string{"peer"}}, started, false},
+		{"more severe", streamFilter{severity: api.EventSeverityInfo},
+			event("brick.disconnected", api.EventSeverityError, nil), true},
+		{"less severe", streamFilter{severity: api.EventSeverityWarning}, started, false},
+		{"no severity taken as info", streamFilter{severity: api.EventSeverityInfo},
+			event("volume.started", "", nil), true},
+		{"no severity below warning", streamFilter{severity: api.EventSeverityWarning},
+			event("volume.started", "", nil), false},
+		{"volume name ignoring case", streamFilter{volumes: []string{"vol1"}}, started, true},
+		{"volume in old data key", streamFilter{volumes: []string{"vol2"}},
+			event("volume.started", api.EventSeverityInfo, map[string]string{"volume": "vol2"}), true},
+		{"other volume", streamFilter{volumes: []string{"vol2"}}, started, false},
+		{"no volume", streamFilter{volumes: []string{"vol1"}},
+			event("peer.added", api.EventSeverityInfo, nil), false},
+		{"all of the filter", streamFilter{types: []string{"volume"}, volumes: []string{"vol1"}, severity: api.EventSeverityInfo},
+			started, true},
+		{"not all of the filter", streamFilter{types: []string{"volume"}, volumes: []string{"vol1"}, severity: api.EventSeverityError},
+			started, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.match, tt.filter.match(tt.event), tt.name)
+	}
+}
+
+// hijackRecorder is a ResponseRecorder for a connection which can't be
+// taken over
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (r hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, errors.New("connection can't be taken over")
+}
+
+// TestEventsStreamHandlerRejects validates the checks done by
+// eventsStreamHandler before it takes over the connection
+func TestEventsStreamHandlerRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		accept   string
+		query    string
+		hijacker bool
+		status   int
+	}{
+		{"not an event stream", "application/json", "", true, http.StatusNotAcceptable},
+		{"no accept", "", "", true, http.StatusNotAcceptable},
+		{"no hijacker", "text/event-stream", "", false, http.StatusInternalServerError},
+		{"invalid severity", "text/event-stream", "?severity=fatal", true, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/v1/events/stream"+tt.query, nil)
+		if tt.accept != "" {
+			r.Header.Set("Accept", tt.accept)
+		}
+		rec := httptest.NewRecorder()
+		var w http.ResponseWriter = rec
+		if tt.hijacker {
+			w = hijackRecorder{rec}
+		}
+
+		eventsStreamHandler(w, r)
+		assert.Equal(t, tt.status, rec.Code, tt.name)
+	}
+}
@@ -0,0 +1,359 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	gd2events "github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+	eventsapi "github.com/gluster/glusterd2/plugins/events/api"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	webhookSinkPrefix = "config/events/webhook-sinks/"
+
+	// Headers sent with every delivery to a webhook sink
+	webhookEventHeader     = "X-Gluster-Event"
+	webhookDeliveryHeader  = "X-Gluster-Delivery"
+	webhookTimestampHeader = "X-Gluster-Timestamp"
+	webhookSignatureHeader = "X-Gluster-Signature"
+
+	maxWebhookRetries      = 20
+	webhookDeliveryTimeout = 10 * time.Second
+)
+
+var defaultWebhookRetry = eventsapi.WebhookRetryPolicy{
+	MaxRetries: 5,
+	Backoff:    1,
+	MaxBackoff: 60,
+}
+
+// webhookSink is a webhook registered with /v1/events/webhooks, to which
+// the matching events are delivered
+type webhookSink struct {
+	ID        uuid.UUID                    `json:"id"`
+	URL       string                       `json:"url"`
+	Events    []string                     `json:"events,omitempty"`
+	Secret    string                       `json:"secret"`
+	Retry     eventsapi.WebhookRetryPolicy `json:"retry"`
+	CreatedAt time.Time                    `json:"created-at"`
+	UpdatedAt time.Time                    `json:"updated-at"`
+}
+
+func validateWebhookURL(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil || !parsed.IsAbs() || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return errors.ErrWebhookInvalidURL
+	}
+	return nil
+}
+
+func validateWebhookRetry(p *eventsapi.WebhookRetryPolicy) error {
+	if p.MaxRetries < 0 || p.MaxRetries > maxWebhookRetries || p.Backoff < 0 || p.MaxBackoff < 0 {
+		return errors.ErrWebhookInvalidRetry
+	}
+	return nil
+}
+
+func normalizeWebhookEvents(events []string) []string {
+	var normalized []string
+	for _, e := range events {
+		if e = strings.ToLower(strings.TrimSpace(e)); e != "" {
+			normalized = append(normalized, e)
+		}
+	}
+	return normalized
+}
+
+func generateWebhookSecret() (string, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(data), nil
+}
+
+// newWebhookSink validates the request and returns a new webhook sink for it
+func newWebhookSink(req *eventsapi.WebhookCreateReq) (*webhookSink, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	retry := defaultWebhookRetry
+	if req.Retry != nil {
+		retry = *req.Retry
+	}
+	if err := validateWebhookRetry(&retry); err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now().UTC()
+	return &webhookSink{
+		ID:        uuid.NewRandom(),
+		URL:       req.URL,
+		Events:    normalizeWebhookEvents(req.Events),
+		Secret:    secret,
+		Retry:     retry,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+func saveWebhookSink(s *webhookSink) error {
+	v, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), webhookSinkPrefix+s.ID.String(), string(v))
+	return err
+}
+
+func getWebhookSink(id string) (*webhookSink, error) {
+	resp, err := store.Get(context.TODO(), webhookSinkPrefix+id)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, errors.ErrWebhookNotFound
+	}
+
+	var s webhookSink
+	if err := json.Unmarshal(resp.Kvs[0].Value, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func getWebhookSinks() ([]*webhookSink, error) {
+	resp, err := store.Get(context.TODO(), webhookSinkPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	sinks := make([]*webhookSink, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var s webhookSink
+		if err := json.Unmarshal(kv.Value, &s); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("failed to unmarshal webhook sink")
+			continue
+		}
+		sinks = append(sinks, &s)
+	}
+	return sinks, nil
+}
+
+// editWebhookSink changes the webhook sink with the given ID as per the
+// request and returns the changed sink
+func editWebhookSink(id string, req *eventsapi.WebhookEditReq) (*webhookSink, error) {
+	if req.URL != "" {
+		if err := validateWebhookURL(req.URL); err != nil {
+			return nil, err
+		}
+	}
+	if req.Retry != nil {
+		if err := validateWebhookRetry(req.Retry); err != nil {
+			return nil, err
+		}
+	}
+
+	key := webhookSinkPrefix + id
+	for {
+		resp, err := store.Get(context.TODO(), key)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Count != 1 {
+			return nil, errors.ErrWebhookNotFound
+		}
+
+		var s webhookSink
+		if err := json.Unmarshal(resp.Kvs[0].Value, &s); err != nil {
+			return nil, err
+		}
+		if req.URL != "" {
+			s.URL = req.URL
+		}
+		if req.Events != nil {
+			s.Events = normalizeWebhookEvents(*req.Events)
+		}
+		if req.Secret != "" {
+			s.Secret = req.Secret
+		}
+		if req.Retry != nil {
+			s.Retry = *req.Retry
+		}
+		s.UpdatedAt = time.Now().UTC()
+
+		v, err := json.Marshal(&s)
+		if err != nil {
+			return nil, err
+		}
+
+		tresp, err := store.Txn(context.TODO()).
+			If(clientv3.Compare(clientv3.ModRevision(key), "=", resp.Kvs[0].ModRevision)).
+			Then(clientv3.OpPut(key, string(v))).
+			Commit()
+		if err != nil {
+			return nil, err
+		}
+		if tresp.Succeeded {
+			return &s, nil
+		}
+	}
+}
+
+func deleteWebhookSink(id string) error {
+	resp, err := store.Delete(context.TODO(), webhookSinkPrefix+id)
+	if err != nil {
+		return err
+	}
+	if resp.Deleted == 0 {
+		return errors.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func createWebhookInfoResp(s *webhookSink) *eventsapi.WebhookInfo {
+	return &eventsapi.WebhookInfo{
+		ID:        s.ID,
+		URL:       s.URL,
+		Events:    s.Events,
+		Retry:     s.Retry,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+// webhookSignature returns the signature of a delivery, the hex encoded
+// HMAC-SHA256 of the timestamp and the body joined by a dot
+func webhookSignature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliver posts the event to the webhook sink once. It returns whether a
+// failed delivery may be retried.
+func (s *webhookSink) deliver(e *api.Event, body []byte) (retry bool, err error) {
+	req, err := http.NewRequest("POST", s.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, e.Name)
+	req.Header.Set(webhookDeliveryHeader, e.ID.String())
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	req.Header.Set(webhookSignatureHeader, webhookSignature(s.Secret, timestamp, body))
+
+	client := &http.Client{Timeout: webhookDeliveryTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+}
+
+// deliverWithRetries delivers the event to the webhook sink, retrying
+// failed deliveries as per the retry policy of the sink
+func (s *webhookSink) deliverWithRetries(e *api.Event) {
+	logger := log.WithFields(log.Fields{
+		"webhook":  s.ID.String(),
+		"event":    e.Name,
+		"event.id": e.ID.String(),
+	})
+
+	body, err := json.Marshal(e)
+	if err != nil {
+		logger.WithError(err).Error("failed to deliver event to webhook, failed to marshal event")
+		return
+	}
+
+	backoff := time.Duration(s.Retry.Backoff) * time.Second
+	maxBackoff := time.Duration(s.Retry.MaxBackoff) * time.Second
+	for attempt := 0; ; attempt++ {
+		retry, err := s.deliver(e, body)
+		if err == nil {
+			logger.Debug("delivered event to webhook")
+			return
+		}
+		if !retry || attempt >= s.Retry.MaxRetries {
+			logger.WithError(err).WithField("attempts", attempt+1).Error("failed to deliver event to webhook")
+			return
+		}
+
+		logger.WithError(err).WithField("attempt", attempt+1).Warn("failed to deliver event to webhook, will retry")
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// webhookSinksNotifier delivers events to the webhook sinks which match
+// them. Events are delivered only by the peer where they happened, so that
+// global events are not delivered by every peer.
+type webhookSinksNotifier struct{}
+
+func (n *webhookSinksNotifier) Handle(e *api.Event) {
+	if !uuid.Equal(e.Origin, gdctx.MyUUID) {
+		return
+	}
+
+	sinks, err := getWebhookSinks()
+	if err != nil {
+		log.WithError(err).Error("failed to get webhook sinks from store")
+		return
+	}
+
+	for _, s := range sinks {
+		if matchEventType(e.Name, s.Events) {
+			go s.deliverWithRetries(e)
+		}
+	}
+}
+
+func (n *webhookSinksNotifier) Events() []string {
+	return []string{}
+}
+
+func init() {
+	gd2events.Register(new(webhookSinksNotifier))
+}
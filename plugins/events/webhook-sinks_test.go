@@ -0,0 +1,212 @@
+package events
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+	eventsapi "github.com/gluster/glusterd2/plugins/events/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"http://example.com/hook", true},
+		{"https://example.com:8443/hook?token=x", true},
+		{"", false},
+		{"/hook", false},
+		{"example.com/hook", false},
+		{"ftp://example.com/hook", false},
+		{"http://exa mple.com", false},
+	}
+
+	for _, tt := range tests {
+		err := validateWebhookURL(tt.url)
+		if tt.valid {
+			assert.Nil(t, err, tt.url)
+		} else {
+			assert.Equal(t, errors.ErrWebhookInvalidURL, err, tt.url)
+		}
+	}
+}
+
+func TestValidateWebhookRetry(t *testing.T) {
+	tests := []struct {
+		retry eventsapi.WebhookRetryPolicy
+		valid bool
+	}{
+		{defaultWebhookRetry, true},
+		{eventsapi.WebhookRetryPolicy{}, true},
+		{eventsapi.WebhookRetryPolicy{MaxRetries: maxWebhookRetries}, true},
+		{eventsapi.WebhookRetryPolicy{MaxRetries: maxWebhookRetries + 1}, false},
+		{eventsapi.WebhookRetryPolicy{MaxRetries: -1}, false},
+		{eventsapi.WebhookRetryPolicy{Backoff: -1}, false},
+		{eventsapi.WebhookRetryPolicy{MaxBackoff: -1}, false},
+	}
+
+	for _, tt := range tests {
+		err := validateWebhookRetry(&tt.retry)
+		if tt.valid {
+			assert.Nil(t, err, "%+v", tt.retry)
+		} else {
+			assert.Equal(t, errors.ErrWebhookInvalidRetry, err, "%+v", tt.retry)
+		}
+	}
+}
+
+func TestNormalizeWebhookEvents(t *testing.T) {
+	assert.Nil(t, normalizeWebhookEvents(nil))
+	assert.Nil(t, normalizeWebhookEvents([]string{"", " "}))
+	assert.Equal(t, []string{"volume", "peer.added"}, normalizeWebhookEvents([]string{" Volume", "", "PEER.added "}))
+}
+
+func TestNewWebhookSink(t *testing.T) {
+	s, err := newWebhookSink(&eventsapi.WebhookCreateReq{
+		URL:    "http://example.com/hook",
+		Events: []string{"Volume"},
+	})
+	assert.Nil(t, err)
+	assert.NotNil(t, s.ID)
+	assert.Equal(t, []string{"volume"}, s.Events)
+	assert.Equal(t, defaultWebhookRetry, s.Retry)
+	assert.Len(t, s.Secret, 64)
+	assert.Equal(t, s.CreatedAt, s.UpdatedAt)
+
+	retry := eventsapi.WebhookRetryPolicy{MaxRetries: 1}
+	s, err = newWebhookSink(&eventsapi.WebhookCreateReq{
+		URL:    "http://example.com/hook",
+		Secret: "secret",
+		Retry:  &retry,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "secret", s.Secret)
+	assert.Equal(t, retry, s.Retry)
+
+	_, err = newWebhookSink(&eventsapi.WebhookCreateReq{URL: "example.com"})
+	assert.Equal(t, errors.ErrWebhookInvalidURL, err)
+
+	retry.MaxRetries = -1
+	_, err = newWebhookSink(&eventsapi.WebhookCreateReq{URL: "http://example.com/hook", Retry: &retry})
+	assert.Equal(t, errors.ErrWebhookInvalidRetry, err)
+}
+
+func TestCreateWebhookInfoResp(t *testing.T) {
+	s := &webhookSink{ID: uuid.NewRandom(), URL: "http://example.com/hook", Secret: "secret"}
+	b, err := json.Marshal(createWebhookInfoResp(s))
+	assert.Nil(t, err)
+	assert.NotContains(t, string(b), "secret")
+}
+
+func TestWebhookSignature(t *testing.T) {
+	body := []byte(`{"name":"volume.started"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1539685230." + string(body)))
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	assert.Equal(t, expected, webhookSignature("secret", "1539685230", body))
+	assert.NotEqual(t, expected, webhookSignature("secret", "1539685231", body))
+	assert.NotEqual(t, expected, webhookSignature("other", "1539685230", body))
+}
+
+func TestWebhookDeliver(t *testing.T) {
+	e := &api.Event{ID: uuid.NewRandom(), Name: "volume.started"}
+	body, _ := json.Marshal(e)
+
+	tests := []struct {
+		status int
+		retry  bool
+		failed bool
+	}{
+		{http.StatusOK, false, false},
+		{http.StatusNoContent, false, false},
+		{http.StatusBadRequest, false, true},
+		{http.StatusNotFound, false, true},
+		{http.StatusTooManyRequests, true, true},
+		{http.StatusInternalServerError, true, true},
+		{http.StatusServiceUnavailable, true, true},
+	}
+
+	for _, tt := range tests {
+		var req *http.Request
+		var reqBody []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req = r
+			reqBody, _ = ioutil.ReadAll(r.Body)
+			w.WriteHeader(tt.status)
+		}))
+
+		s := &webhookSink{URL: server.URL, Secret: "secret"}
+		retry, err := s.deliver(e, body)
+		server.Close()
+
+		assert.Equal(t, tt.retry, retry, "status %d", tt.status)
+		assert.Equal(t, tt.failed, err != nil, "status %d", tt.status)
+
+		assert.Equal(t, "POST", req.Method)
+		assert.Equal(t, body, reqBody)
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		assert.Equal(t, e.Name, req.Header.Get(webhookEventHeader))
+		assert.Equal(t, e.ID.String(), req.Header.Get(webhookDeliveryHeader))
+		timestamp := req.Header.Get(webhookTimestampHeader)
+		assert.Equal(t, webhookSignature("secret", timestamp, body), req.Header.Get(webhookSignatureHeader))
+	}
+
+	// Deliveries which can't connect are retried
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	s := &webhookSink{URL: server.URL}
+	retry, err := s.deliver(e, body)
+	assert.True(t, retry)
+	assert.NotNil(t, err)
+}
+
+func TestWebhookDeliverWithRetries(t *testing.T) {
+	e := &api.Event{ID: uuid.NewRandom(), Name: "volume.started"}
+
+	tests := []struct {
+		name       string
+		statuses   []int
+		maxRetries int
+		attempts   int32
+	}{
+		{"delivered", []int{http.StatusOK}, 2, 1},
+		{"delivered after retries", []int{http.StatusServiceUnavailable, http.StatusInternalServerError, http.StatusOK}, 2, 3},
+		{"retries exhausted", []int{http.StatusServiceUnavailable}, 2, 3},
+		{"not retried", []int{http.StatusBadRequest}, 2, 1},
+		{"no retries", []int{http.StatusServiceUnavailable}, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(atomic.AddInt32(&attempts, 1)) - 1
+				if n >= len(tt.statuses) {
+					n = len(tt.statuses) - 1
+				}
+				w.WriteHeader(tt.statuses[n])
+			}))
+			defer server.Close()
+
+			s := &webhookSink{
+				URL:   server.URL,
+				Retry: eventsapi.WebhookRetryPolicy{MaxRetries: tt.maxRetries},
+			}
+			s.deliverWithRetries(e)
+			assert.Equal(t, tt.attempts, atomic.LoadInt32(&attempts))
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package api
+
+// EnableReq represents REST API request to enable NFS-Ganesha on a set of
+// peers, given by their IDs
+type EnableReq struct {
+	Peers []string `json:"peers"`
+}
+
+// ExportReq represents REST API request to export a volume over NFS-Ganesha.
+// Pseudo is the path of the export in the NFSv4 pseudo filesystem, /<volname>
+// by default. AccessType is one of RW (the default), RO or None, and Squash
+// one of No_Root_Squash (the default), Root_Squash, Root_Id_Squash or
+// All_Squash.
+type ExportReq struct {
+	Pseudo     string `json:"pseudo,omitempty"`
+	AccessType string `json:"access-type,omitempty"`
+	Squash     string `json:"squash,omitempty"`
+}
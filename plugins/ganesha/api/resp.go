@@ -0,0 +1,34 @@
+package api
+
+import (
+	"github.com/pborman/uuid"
+)
+
+// ClusterInfo represents the peers NFS-Ganesha is enabled on
+type ClusterInfo struct {
+	Peers []uuid.UUID `json:"peers"`
+}
+
+// Export represents a volume exported over NFS-Ganesha
+type Export struct {
+	Volume     string `json:"volume"`
+	ExportID   uint16 `json:"export-id"`
+	Pseudo     string `json:"pseudo"`
+	AccessType string `json:"access-type"`
+	Squash     string `json:"squash"`
+}
+
+// PeerStatus represents the state of the NFS-Ganesha service on a peer.
+// Error is set if the state couldn't be found.
+type PeerStatus struct {
+	PeerID  uuid.UUID `json:"peer-id"`
+	Running bool      `json:"running"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// StatusResp represents the state of NFS-Ganesha in the cluster
+type StatusResp struct {
+	Enabled bool         `json:"enabled"`
+	Peers   []PeerStatus `json:"peers"`
+	Exports []Export     `json:"exports"`
+}
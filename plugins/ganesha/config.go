@@ -0,0 +1,197 @@
+package ganesha
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	ganeshaapi "github.com/gluster/glusterd2/plugins/ganesha/api"
+
+	config "github.com/spf13/viper"
+)
+
+const (
+	// defaultConfDir is the directory the export files are written to,
+	// unless the ganesha-confdir setting is set
+	defaultConfDir = "/etc/ganesha"
+	// includeFileName is the file including the export files of all the
+	// exported volumes, which ganesha.conf must include
+	includeFileName = "gluster-exports.conf"
+
+	// Export IDs 0 and 1 are reserved by NFS-Ganesha for the pseudo root
+	minExportID = 2
+	maxExportID = 65535
+)
+
+var (
+	accessTypes = []string{"RW", "RO", "None"}
+	squashTypes = []string{"No_Root_Squash", "Root_Squash", "Root_Id_Squash", "All_Squash"}
+
+	// pseudoRegex matches the pseudo paths which can be written as is in
+	// the export files
+	pseudoRegex = regexp.MustCompile(`^/[A-Za-z0-9._/-]+$`)
+)
+
+func confDir() string {
+	if dir := config.GetString("ganesha-confdir"); dir != "" {
+		return dir
+	}
+	return defaultConfDir
+}
+
+func exportsDir() string {
+	return path.Join(confDir(), "exports")
+}
+
+func exportFile(volname string) string {
+	return path.Join(exportsDir(), "export."+volname+".conf")
+}
+
+// normalize returns the value of the list equal to s ignoring case, or "" if
+// there is none
+func normalize(s string, values []string) string {
+	for _, v := range values {
+		if strings.EqualFold(s, v) {
+			return v
+		}
+	}
+	return ""
+}
+
+// newExport returns the export of the volume for the request, with defaults
+// filled in and the first export ID not used by the existing exports
+func newExport(volname string, req *ganeshaapi.ExportReq, existing []ganeshaapi.Export) (*ganeshaapi.Export, error) {
+	e := &ganeshaapi.Export{
+		Volume:     volname,
+		Pseudo:     req.Pseudo,
+		AccessType: accessTypes[0],
+		Squash:     squashTypes[0],
+	}
+
+	if e.Pseudo == "" {
+		e.Pseudo = "/" + volname
+	}
+	if !pseudoRegex.MatchString(e.Pseudo) {
+		return nil, errInvalidPseudo
+	}
+	// The root of the pseudo filesystem is the export of NFS-Ganesha itself
+	if e.Pseudo = path.Clean(e.Pseudo); e.Pseudo == "/" {
+		return nil, errInvalidPseudo
+	}
+
+	if req.AccessType != "" {
+		if e.AccessType = normalize(req.AccessType, accessTypes); e.AccessType == "" {
+			return nil, errInvalidAccessType
+		}
+	}
+	if req.Squash != "" {
+		if e.Squash = normalize(req.Squash, squashTypes); e.Squash == "" {
+			return nil, errInvalidSquash
+		}
+	}
+
+	used := make(map[uint16]bool)
+	for _, x := range existing {
+		if x.Volume == volname {
+			return nil, errVolumeExported
+		}
+		if x.Pseudo == e.Pseudo {
+			return nil, errPseudoInUse
+		}
+		used[x.ExportID] = true
+	}
+
+	for id := minExportID; id <= maxExportID; id++ {
+		if !used[uint16(id)] {
+			e.ExportID = uint16(id)
+			return e, nil
+		}
+	}
+	return nil, errNoExportID
+}
+
+// exportConfig returns the EXPORT block of NFS-Ganesha configuration for the
+// export, served by the gluster FSAL from the volume of the local glusterd2
+func exportConfig(e *ganeshaapi.Export) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Generated by glusterd2, do not edit\n")
+	fmt.Fprintf(&b, "EXPORT {\n")
+	fmt.Fprintf(&b, "\tExport_Id = %d;\n", e.ExportID)
+	fmt.Fprintf(&b, "\tPath = \"/%s\";\n", e.Volume)
+	fmt.Fprintf(&b, "\tPseudo = \"%s\";\n", e.Pseudo)
+	fmt.Fprintf(&b, "\tAccess_Type = %s;\n", e.AccessType)
+	fmt.Fprintf(&b, "\tSquash = \"%s\";\n", e.Squash)
+	fmt.Fprintf(&b, "\tDisable_ACL = true;\n")
+	fmt.Fprintf(&b, "\tProtocols = \"3\", \"4\";\n")
+	fmt.Fprintf(&b, "\tTransports = \"UDP\", \"TCP\";\n")
+	fmt.Fprintf(&b, "\tSecType = \"sys\";\n")
+	fmt.Fprintf(&b, "\tFSAL {\n")
+	fmt.Fprintf(&b, "\t\tName = GLUSTER;\n")
+	fmt.Fprintf(&b, "\t\tHostname = \"localhost\";\n")
+	fmt.Fprintf(&b, "\t\tVolume = \"%s\";\n", e.Volume)
+	fmt.Fprintf(&b, "\t}\n")
+	fmt.Fprintf(&b, "}\n")
+	return b.String()
+}
+
+// writeIncludeFile writes the file including the export files of all the
+// volumes exported on this peer
+func writeIncludeFile() error {
+	files, err := filepath.Glob(path.Join(exportsDir(), "export.*.conf"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Generated by glusterd2, do not edit\n")
+	for _, f := range files {
+		fmt.Fprintf(&b, "%%include \"%s\"\n", f)
+	}
+	if err := os.MkdirAll(confDir(), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path.Join(confDir(), includeFileName), b.Bytes(), 0644)
+}
+
+// writeExportFile writes the export file of the volume and includes it in
+// the NFS-Ganesha configuration
+func writeExportFile(e *ganeshaapi.Export) error {
+	if err := os.MkdirAll(exportsDir(), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(exportFile(e.Volume), []byte(exportConfig(e)), 0644); err != nil {
+		return err
+	}
+	return writeIncludeFile()
+}
+
+// removeExportFile removes the export file of the volume from the NFS-Ganesha
+// configuration
+func removeExportFile(volname string) error {
+	if err := os.Remove(exportFile(volname)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return writeIncludeFile()
+}
+
+// removeExportFiles removes the export files of all the volumes from the
+// NFS-Ganesha configuration
+func removeExportFiles() error {
+	files, err := filepath.Glob(path.Join(exportsDir(), "export.*.conf"))
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return writeIncludeFile()
+}
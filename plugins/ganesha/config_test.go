@@ -0,0 +1,84 @@
+package ganesha
+
+import (
+	"strings"
+	"testing"
+
+	ganeshaapi "github.com/gluster/glusterd2/plugins/ganesha/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExport(t *testing.T) {
+	e, err := newExport("vol1", &ganeshaapi.ExportReq{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &ganeshaapi.Export{
+		Volume:     "vol1",
+		ExportID:   minExportID,
+		Pseudo:     "/vol1",
+		AccessType: "RW",
+		Squash:     "No_Root_Squash",
+	}, e)
+
+	existing := []ganeshaapi.Export{
+		{Volume: "vol1", ExportID: 2, Pseudo: "/vol1"},
+		{Volume: "vol2", ExportID: 4, Pseudo: "/data/"},
+	}
+
+	e, err = newExport("vol3", &ganeshaapi.ExportReq{Pseudo: "/vol3/", AccessType: "ro", Squash: "all_squash"}, existing)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(3), e.ExportID)
+	assert.Equal(t, "/vol3", e.Pseudo)
+	assert.Equal(t, "RO", e.AccessType)
+	assert.Equal(t, "All_Squash", e.Squash)
+
+	_, err = newExport("vol1", &ganeshaapi.ExportReq{Pseudo: "/other"}, existing)
+	assert.Equal(t, errVolumeExported, err)
+
+	_, err = newExport("vol3", &ganeshaapi.ExportReq{Pseudo: "/vol1"}, existing)
+	assert.Equal(t, errPseudoInUse, err)
+
+	for _, pseudo := range []string{
+		"vol3",
+		"/",
+		"/..",
+		"/vol 3",
+		`/vol3"; Path = "/`,
+		"/vol3;",
+		"/vol3\nEXPORT",
+		"/vol3{}",
+	} {
+		_, err = newExport("vol3", &ganeshaapi.ExportReq{Pseudo: pseudo}, existing)
+		assert.Equal(t, errInvalidPseudo, err, pseudo)
+	}
+
+	_, err = newExport("vol3", &ganeshaapi.ExportReq{AccessType: "rw-only"}, existing)
+	assert.Equal(t, errInvalidAccessType, err)
+
+	_, err = newExport("vol3", &ganeshaapi.ExportReq{Squash: "none"}, existing)
+	assert.Equal(t, errInvalidSquash, err)
+}
+
+func TestExportConfig(t *testing.T) {
+	conf := exportConfig(&ganeshaapi.Export{
+		Volume:     "vol1",
+		ExportID:   7,
+		Pseudo:     "/data",
+		AccessType: "RO",
+		Squash:     "Root_Squash",
+	})
+
+	for _, line := range []string{
+		"Export_Id = 7;",
+		"Path = \"/vol1\";",
+		"Pseudo = \"/data\";",
+		"Access_Type = RO;",
+		"Squash = \"Root_Squash\";",
+		"Name = GLUSTER;",
+		"Volume = \"vol1\";",
+	} {
+		assert.Contains(t, conf, line)
+	}
+	assert.Equal(t, strings.Count(conf, "{"), strings.Count(conf, "}"))
+}
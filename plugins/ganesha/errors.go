@@ -0,0 +1,18 @@
+package ganesha
+
+import (
+	"errors"
+)
+
+var (
+	errGaneshaEnabled    = errors.New("nfs-ganesha is already enabled")
+	errGaneshaNotEnabled = errors.New("nfs-ganesha is not enabled")
+	errNoPeers           = errors.New("at least one peer is required to run nfs-ganesha on")
+	errVolumeExported    = errors.New("volume is already exported")
+	errVolumeNotExported = errors.New("volume is not exported")
+	errInvalidPseudo     = errors.New("pseudo must be an absolute path other than / made of letters, digits, '.', '_', '-' and '/'")
+	errPseudoInUse       = errors.New("pseudo path is already used by another export")
+	errInvalidAccessType = errors.New("access-type must be one of RW, RO or None")
+	errInvalidSquash     = errors.New("squash must be one of No_Root_Squash, Root_Squash, Root_Id_Squash or All_Squash")
+	errNoExportID        = errors.New("no export ID left to export the volume with")
+)
@@ -0,0 +1,82 @@
+package ganesha
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+	ganeshaapi "github.com/gluster/glusterd2/plugins/ganesha/api"
+)
+
+// Plugin is a structure which implements GlusterdPlugin interface
+type Plugin struct {
+}
+
+// Name returns name of plugin
+func (p *Plugin) Name() string {
+	return "ganesha"
+}
+
+// RestRoutes returns list of REST API routes to register with Glusterd
+func (p *Plugin) RestRoutes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "NfsGaneshaEnable",
+			Method:       "POST",
+			Pattern:      "/nfs-ganesha",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*ganeshaapi.EnableReq)(nil)),
+			ResponseType: utils.GetTypeString((*ganeshaapi.ClusterInfo)(nil)),
+			HandlerFunc:  ganeshaEnableHandler,
+			Role:         api.RoleAdmin},
+		route.Route{
+			Name:        "NfsGaneshaDisable",
+			Method:      "DELETE",
+			Pattern:     "/nfs-ganesha",
+			Version:     1,
+			HandlerFunc: ganeshaDisableHandler},
+		route.Route{
+			Name:         "NfsGaneshaStatus",
+			Method:       "GET",
+			Pattern:      "/nfs-ganesha",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*ganeshaapi.StatusResp)(nil)),
+			HandlerFunc:  ganeshaStatusHandler},
+		route.Route{
+			Name:         "NfsGaneshaExport",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/nfs-ganesha",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*ganeshaapi.ExportReq)(nil)),
+			ResponseType: utils.GetTypeString((*ganeshaapi.Export)(nil)),
+			HandlerFunc:  ganeshaExportHandler},
+		route.Route{
+			Name:        "NfsGaneshaUnexport",
+			Method:      "DELETE",
+			Pattern:     "/volumes/{volname}/nfs-ganesha",
+			Version:     1,
+			HandlerFunc: ganeshaUnexportHandler,
+			Role:        api.RoleOperator},
+		route.Route{
+			Name:         "NfsGaneshaExportGet",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/nfs-ganesha",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*ganeshaapi.Export)(nil)),
+			HandlerFunc:  ganeshaExportGetHandler},
+	}
+}
+
+// RegisterStepFuncs registers transaction step functions with
+// Glusterd Transaction framework
+func (p *Plugin) RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnStart, "ganesha.Start")
+	transaction.RegisterStepFunc(txnStop, "ganesha.Stop")
+	transaction.RegisterStepFunc(txnStoreCluster, "ganesha.StoreCluster")
+	transaction.RegisterStepFunc(txnDeleteCluster, "ganesha.DeleteCluster")
+	transaction.RegisterStepFunc(txnAddExport, "ganesha.AddExport")
+	transaction.RegisterStepFunc(txnRemoveExport, "ganesha.RemoveExport")
+	transaction.RegisterStepFunc(txnStoreExport, "ganesha.StoreExport")
+	transaction.RegisterStepFunc(txnDeleteExport, "ganesha.DeleteExport")
+	transaction.RegisterStepFunc(txnStatus, "ganesha.Status")
+}
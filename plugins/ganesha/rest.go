@@ -0,0 +1,385 @@
+package ganesha
+
+import (
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/errors"
+	ganeshaapi "github.com/gluster/glusterd2/plugins/ganesha/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+// lockID is locked by all the transactions changing NFS-Ganesha, so that
+// export IDs and the set of peers running it are changed one at a time
+const lockID = "nfs-ganesha"
+
+func ganeshaEnableHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req ganeshaapi.EnableReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	if len(req.Peers) == 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errNoPeers)
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, lockID)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	if _, err := getCluster(); err == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, errGaneshaEnabled)
+		return
+	} else if err != errGaneshaNotEnabled {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var cluster ganeshaapi.ClusterInfo
+	for _, id := range req.Peers {
+		p, err := peer.GetPeer(id)
+		if err != nil {
+			status, err := restutils.ErrToStatusCode(err)
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+		cluster.Peers = append(cluster.Peers, p.ID)
+	}
+
+	if err := txn.Ctx.Set("cluster", &cluster); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "ganesha.Start",
+			UndoFunc: "ganesha.Stop",
+			Nodes:    cluster.Peers,
+		},
+		{
+			DoFunc:   "ganesha.StoreCluster",
+			UndoFunc: "ganesha.DeleteCluster",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Sync:     true,
+		},
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).Error("failed to enable nfs-ganesha")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, cluster)
+}
+
+func ganeshaDisableHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, lockID)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	cluster, err := getCluster()
+	if err == errGaneshaNotEnabled {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := txn.Ctx.Set("cluster", cluster); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "ganesha.Stop",
+			UndoFunc: "ganesha.Start",
+			Nodes:    cluster.Peers,
+		},
+		{
+			DoFunc:   "ganesha.DeleteCluster",
+			UndoFunc: "ganesha.StoreCluster",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Sync:     true,
+		},
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).Error("failed to disable nfs-ganesha")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+func ganeshaStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	exports, err := getExports()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	resp := ganeshaapi.StatusResp{
+		Peers:   []ganeshaapi.PeerStatus{},
+		Exports: exports,
+	}
+
+	cluster, err := getCluster()
+	if err == errGaneshaNotEnabled {
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+		return
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	resp.Enabled = true
+
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+
+	// The status of the peers which are down is reported as unknown
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "ganesha.Status",
+			Nodes:  cluster.Peers,
+		},
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).Error("failed to get nfs-ganesha status")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	for _, id := range cluster.Peers {
+		var s ganeshaapi.PeerStatus
+		if err := txn.Ctx.GetNodeResult(id, statusTxnKey, &s); err != nil {
+			s = ganeshaapi.PeerStatus{PeerID: id, Error: "peer is unreachable"}
+		}
+		resp.Peers = append(resp.Peers, s)
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func ganeshaExportHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req ganeshaapi.ExportReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname, lockID)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, volinfo.GetNamespace()) {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrVolNotFound)
+		return
+	}
+
+	if volinfo.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrVolNotStarted)
+		return
+	}
+
+	cluster, err := getCluster()
+	if err == errGaneshaNotEnabled {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	exports, err := getExports()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	export, err := newExport(volname, &req, exports)
+	if err == errVolumeExported || err == errPseudoInUse {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, err)
+		return
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := txn.Ctx.Set("export", export); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "ganesha.AddExport",
+			UndoFunc: "ganesha.RemoveExport",
+			Nodes:    cluster.Peers,
+		},
+		{
+			DoFunc:   "ganesha.StoreExport",
+			UndoFunc: "ganesha.DeleteExport",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Sync:     true,
+		},
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to export volume over nfs-ganesha")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusCreated, export)
+}
+
+func ganeshaUnexportHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname, lockID)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, volinfo.GetNamespace()) {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrVolNotFound)
+		return
+	}
+
+	export, err := getExport(volname)
+	if err == errVolumeNotExported {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := txn.Ctx.Set("export", export); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var nodes []uuid.UUID
+	if cluster, err := getCluster(); err == nil {
+		nodes = cluster.Peers
+	} else if err != errGaneshaNotEnabled {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "ganesha.DeleteExport",
+			UndoFunc: "ganesha.StoreExport",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Sync:     true,
+		},
+	}
+	// While NFS-Ganesha is disabled there are no export files to remove
+	if len(nodes) > 0 {
+		txn.Steps = append([]*transaction.Step{
+			{
+				DoFunc:   "ganesha.RemoveExport",
+				UndoFunc: "ganesha.AddExport",
+				Nodes:    nodes,
+			},
+		}, txn.Steps...)
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to unexport volume from nfs-ganesha")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+func ganeshaExportGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, volinfo.GetNamespace()) {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrVolNotFound)
+		return
+	}
+
+	export, err := getExport(volname)
+	if err == errVolumeNotExported {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, export)
+}
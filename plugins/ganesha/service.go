@@ -0,0 +1,48 @@
+package ganesha
+
+import (
+	"fmt"
+
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+const (
+	serviceName = "nfs-ganesha"
+
+	dbusDest      = "org.ganesha.nfsd"
+	dbusExportMgr = "/org/ganesha/nfsd/ExportMgr"
+	dbusInterface = "org.ganesha.nfsd.exportmgr"
+)
+
+func startService() error {
+	return utils.ExecuteCommandRun("systemctl", "start", serviceName)
+}
+
+func stopService() error {
+	return utils.ExecuteCommandRun("systemctl", "stop", serviceName)
+}
+
+// serviceRunning returns true if the NFS-Ganesha service is active on this
+// peer
+func serviceRunning() bool {
+	return utils.ExecuteCommandRun("systemctl", "is-active", "--quiet", serviceName) == nil
+}
+
+func dbusExportMgrCall(method string, args ...string) error {
+	cmdArgs := []string{"--system", "--print-reply", "--dest=" + dbusDest,
+		dbusExportMgr, dbusInterface + "." + method}
+	return utils.ExecuteCommandRun("dbus-send", append(cmdArgs, args...)...)
+}
+
+// addExport asks the running NFS-Ganesha to export the volume from its export
+// file, without restarting it
+func addExport(volname string, exportID uint16) error {
+	return dbusExportMgrCall("AddExport",
+		"string:"+exportFile(volname),
+		fmt.Sprintf("string:EXPORT(Export_Id=%d)", exportID))
+}
+
+// removeExport asks the running NFS-Ganesha to stop exporting the export
+func removeExport(exportID uint16) error {
+	return dbusExportMgrCall("RemoveExport", fmt.Sprintf("uint16:%d", exportID))
+}
@@ -0,0 +1,109 @@
+package ganesha
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	ganeshaapi "github.com/gluster/glusterd2/plugins/ganesha/api"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	ganeshaClusterKey    string = "ganesha/cluster"
+	ganeshaExportsPrefix string = "ganesha/exports/"
+)
+
+// getCluster returns the peers NFS-Ganesha is enabled on, or
+// errGaneshaNotEnabled if it isn't enabled
+func getCluster() (*ganeshaapi.ClusterInfo, error) {
+	resp, err := store.Get(context.TODO(), ganeshaClusterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Count != 1 {
+		return nil, errGaneshaNotEnabled
+	}
+
+	var c ganeshaapi.ClusterInfo
+	if err := json.Unmarshal(resp.Kvs[0].Value, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// storeCluster saves the peers NFS-Ganesha is enabled on
+func storeCluster(c *ganeshaapi.ClusterInfo) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(context.TODO(), ganeshaClusterKey, string(data))
+	return err
+}
+
+// deleteCluster deletes the peers NFS-Ganesha is enabled on. The exports are
+// kept, so that they are exported again when NFS-Ganesha is enabled again.
+func deleteCluster() error {
+	_, err := store.Delete(context.TODO(), ganeshaClusterKey)
+	return err
+}
+
+// getExport returns the export of the volume, or errVolumeNotExported if the
+// volume isn't exported
+func getExport(volname string) (*ganeshaapi.Export, error) {
+	resp, err := store.Get(context.TODO(), ganeshaExportsPrefix+volname)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Count != 1 {
+		return nil, errVolumeNotExported
+	}
+
+	var e ganeshaapi.Export
+	if err := json.Unmarshal(resp.Kvs[0].Value, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// getExports returns the exports of all the volumes
+func getExports() ([]ganeshaapi.Export, error) {
+	resp, err := store.Get(context.TODO(), ganeshaExportsPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]ganeshaapi.Export, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var e ganeshaapi.Export
+		if err := json.Unmarshal(kv.Value, &e); err != nil {
+			log.WithError(err).WithField("export", string(kv.Key)).Error("failed to unmarshal nfs-ganesha export")
+			continue
+		}
+		exports = append(exports, e)
+	}
+	return exports, nil
+}
+
+// storeExport saves the export of a volume
+func storeExport(e *ganeshaapi.Export) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(context.TODO(), ganeshaExportsPrefix+e.Volume, string(data))
+	return err
+}
+
+// deleteExport deletes the export of a volume
+func deleteExport(volname string) error {
+	_, err := store.Delete(context.TODO(), ganeshaExportsPrefix+volname)
+	return err
+}
@@ -0,0 +1,130 @@
+package ganesha
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	ganeshaapi "github.com/gluster/glusterd2/plugins/ganesha/api"
+)
+
+const (
+	statusTxnKey string = "ganesha-status"
+)
+
+// txnStart writes the export files of all the exported volumes and starts the
+// NFS-Ganesha service on this peer
+func txnStart(c transaction.TxnCtx) error {
+	exports, err := getExports()
+	if err != nil {
+		return err
+	}
+
+	for i := range exports {
+		if err := writeExportFile(&exports[i]); err != nil {
+			c.Logger().WithError(err).WithField("volume", exports[i].Volume).Error("failed to write nfs-ganesha export file")
+			return err
+		}
+	}
+	if err := writeIncludeFile(); err != nil {
+		return err
+	}
+
+	if err := startService(); err != nil {
+		c.Logger().WithError(err).Error("failed to start nfs-ganesha")
+		return err
+	}
+	return nil
+}
+
+// txnStop stops the NFS-Ganesha service on this peer and removes the export
+// files
+func txnStop(c transaction.TxnCtx) error {
+	if err := stopService(); err != nil {
+		c.Logger().WithError(err).Error("failed to stop nfs-ganesha")
+		return err
+	}
+	return removeExportFiles()
+}
+
+func txnStoreCluster(c transaction.TxnCtx) error {
+	var cluster ganeshaapi.ClusterInfo
+	if err := c.Get("cluster", &cluster); err != nil {
+		return err
+	}
+	return storeCluster(&cluster)
+}
+
+func txnDeleteCluster(c transaction.TxnCtx) error {
+	return deleteCluster()
+}
+
+// txnAddExport writes the export file of the volume and exports it from the
+// running NFS-Ganesha on this peer
+func txnAddExport(c transaction.TxnCtx) error {
+	var e ganeshaapi.Export
+	if err := c.Get("export", &e); err != nil {
+		return err
+	}
+
+	if err := writeExportFile(&e); err != nil {
+		c.Logger().WithError(err).WithField("volume", e.Volume).Error("failed to write nfs-ganesha export file")
+		return err
+	}
+
+	if !serviceRunning() {
+		// The export is loaded from the file when NFS-Ganesha starts
+		return nil
+	}
+	if err := addExport(e.Volume, e.ExportID); err != nil {
+		c.Logger().WithError(err).WithField("volume", e.Volume).Error("failed to add nfs-ganesha export")
+		return err
+	}
+	return nil
+}
+
+// txnRemoveExport stops exporting the volume from the running NFS-Ganesha on
+// this peer and removes its export file
+func txnRemoveExport(c transaction.TxnCtx) error {
+	var e ganeshaapi.Export
+	if err := c.Get("export", &e); err != nil {
+		return err
+	}
+
+	if serviceRunning() {
+		if err := removeExport(e.ExportID); err != nil {
+			// The export may not have been added, like when undoing a
+			// failed export
+			c.Logger().WithError(err).WithField("volume", e.Volume).Warn("failed to remove nfs-ganesha export")
+		}
+	}
+
+	if err := removeExportFile(e.Volume); err != nil {
+		c.Logger().WithError(err).WithField("volume", e.Volume).Error("failed to remove nfs-ganesha export file")
+		return err
+	}
+	return nil
+}
+
+func txnStoreExport(c transaction.TxnCtx) error {
+	var e ganeshaapi.Export
+	if err := c.Get("export", &e); err != nil {
+		return err
+	}
+	return storeExport(&e)
+}
+
+func txnDeleteExport(c transaction.TxnCtx) error {
+	var e ganeshaapi.Export
+	if err := c.Get("export", &e); err != nil {
+		return err
+	}
+	return deleteExport(e.Volume)
+}
+
+// txnStatus records whether the NFS-Ganesha service is running on this peer
+func txnStatus(c transaction.TxnCtx) error {
+	status := ganeshaapi.PeerStatus{
+		PeerID:  gdctx.MyUUID,
+		Running: serviceRunning(),
+	}
+	return c.SetNodeResult(gdctx.MyUUID, statusTxnKey, status)
+}
@@ -6,13 +6,32 @@ type GeorepRemoteHostReq struct {
 	Hostname string `json:"host"`
 }
 
-// GeorepCreateReq represents REST API request to create Geo-rep session
+// GeorepRemoteEndpoint represents the REST endpoint of Glusterd2 in the
+// Remote cluster and the credentials to use it
+type GeorepRemoteEndpoint struct {
+	URL      string `json:"url"`
+	User     string `json:"user,omitempty"`
+	Secret   string `json:"secret,omitempty"`
+	Insecure bool   `json:"insecure,omitempty"`
+}
+
+// GeorepCreateReq represents REST API request to create Geo-rep session.
+// If RemoteEndpoint is set, SSH keys are generated on the Master nodes and
+// pushed to the Remote cluster once the session is created.
 type GeorepCreateReq struct {
-	MasterVol   string                `json:"mastervol"`
-	RemoteUser  string                `json:"remoteuser"`
-	RemoteHosts []GeorepRemoteHostReq `json:"remotehosts"`
-	RemoteVol   string                `json:"remotevol"`
-	Force       bool                  `json:"force"`
+	MasterVol      string                `json:"mastervol"`
+	RemoteUser     string                `json:"remoteuser"`
+	RemoteHosts    []GeorepRemoteHostReq `json:"remotehosts"`
+	RemoteVol      string                `json:"remotevol"`
+	Force          bool                  `json:"force"`
+	RemoteEndpoint *GeorepRemoteEndpoint `json:"remote-endpoint,omitempty"`
+}
+
+// GeorepSSHKeyDistributeReq represents REST API request to generate SSH
+// keys on the Master nodes and push them to the nodes of the Remote volume
+type GeorepSSHKeyDistributeReq struct {
+	RemoteVol      string               `json:"remotevol"`
+	RemoteEndpoint GeorepRemoteEndpoint `json:"remote-endpoint"`
 }
 
 // GeorepCommandsReq represents extra arguments to Geo-rep APIs
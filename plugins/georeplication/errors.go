@@ -1,5 +1,12 @@
 package georeplication
 
+import "errors"
+
+var (
+	errRemoteEndpointRequired = errors.New("remote endpoint URL is required")
+	errInvalidRemoteEndpoint  = errors.New("invalid remote endpoint URL, must be a http or https URL")
+)
+
 // ErrGeorepSessionNotFound custom error to represent georep session not exists
 // in store
 type ErrGeorepSessionNotFound struct{}
@@ -21,8 +21,20 @@ const (
 
 func newGeorepEvent(e georepEvent, session *georepapi.GeorepSession, extra *map[string]string) *api.Event {
 	data := make(map[string]string)
+	var objects []api.EventObject
 
 	if session != nil {
+		objects = []api.EventObject{
+			{
+				Kind: api.EventObjectGeorepSession,
+				ID:   session.MasterID.String() + "/" + session.RemoteID.String(),
+			},
+			{
+				Kind: api.EventObjectVolume,
+				ID:   session.MasterID.String(),
+				Name: session.MasterVol,
+			},
+		}
 		data = map[string]string{
 			"master.name":   session.MasterVol,
 			"master.id":     session.MasterID.String(),
@@ -40,5 +52,29 @@ func newGeorepEvent(e georepEvent, session *georepapi.GeorepSession, extra *map[
 		}
 	}
 
-	return events.New(string(e), data, true)
+	return events.New(string(e), data, true, objects...)
+}
+
+func init() {
+	types := []struct {
+		name        georepEvent
+		description string
+	}{
+		{eventGeorepCreated, "A geo-replication session was created"},
+		{eventGeorepStarted, "A geo-replication session was started"},
+		{eventGeorepStopped, "A geo-replication session was stopped"},
+		{eventGeorepDeleted, "A geo-replication session was deleted"},
+		{eventGeorepPaused, "A geo-replication session was paused"},
+		{eventGeorepResumed, "A geo-replication session was resumed"},
+		{eventGeorepConfigSet, "Options of a geo-replication session were set"},
+		{eventGeorepConfigReset, "Options of a geo-replication session were reset"},
+	}
+	for _, t := range types {
+		events.RegisterTypes(api.EventType{
+			Name:        string(t.name),
+			Description: t.description,
+			Objects:     []string{api.EventObjectGeorepSession, api.EventObjectVolume},
+			Data:        []string{"master.name", "master.id", "remote.name", "remote.id", "remote.host", "remote.peerid", "remote.user"},
+		})
+	}
 }
@@ -117,6 +117,14 @@ func (p *Plugin) RestRoutes() route.Routes {
 			Version:     1,
 			RequestType: utils.GetTypeString((*georepapi.GeorepSSHPublicKey)(nil)),
 			HandlerFunc: georepSSHKeyPushHandler},
+		route.Route{
+			Name:         "GeoReplicationSshKeyDistribute",
+			Method:       "POST",
+			Pattern:      "/ssh-key/{volname}/distribute",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*georepapi.GeorepSSHKeyDistributeReq)(nil)),
+			ResponseType: utils.GetTypeString((*georepapi.GeorepSSHPublicKey)(nil)),
+			HandlerFunc:  georepSSHKeyDistributeHandler},
 		route.Route{
 			Name:         "GeoReplicationSshKeyGet",
 			Method:       "GET",
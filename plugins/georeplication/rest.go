@@ -24,6 +24,12 @@ import (
 	config "github.com/spf13/viper"
 )
 
+var (
+	// createGeorepSessionF and distributeSSHKeysF are replaced by tests
+	createGeorepSessionF = createGeorepSession
+	distributeSSHKeysF   = distributeSSHKeys
+)
+
 // newGeorepSession creates new instance of GeorepSession
 func newGeorepSession(mastervolid, remotevolid uuid.UUID, req georepapi.GeorepCreateReq) *georepapi.GeorepSession {
 	remoteUser := req.RemoteUser
@@ -110,26 +116,58 @@ func georepCreateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	txn, err := transaction.NewTxnWithLocks(ctx, req.MasterVol)
+	if req.RemoteEndpoint != nil {
+		if err := validateRemoteEndpoint(req.RemoteEndpoint); err != nil {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	geoSession, status, err := createGeorepSessionF(ctx, masterid, remoteid, &req)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
 		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
+
+	events.Broadcast(newGeorepEvent(eventGeorepCreated, geoSession, nil))
+
+	// The keys are pushed to the Remote cluster once the lock on the Master
+	// volume is released. The session is kept if they can't be pushed, the
+	// failure is reported in a Warning header and the keys can be pushed
+	// again with the ssh-key distribute API.
+	if req.RemoteEndpoint != nil {
+		if err := distributeSSHKeysF(req.MasterVol, req.RemoteEndpoint, req.RemoteVol); err != nil {
+			logger.WithError(err).WithFields(log.Fields{
+				"mastervolid": masterid,
+				"remotevolid": remoteid,
+			}).Warn("geo-replication session created but failed to distribute SSH keys")
+			w.Header().Set("Warning", sshKeysWarning(err))
+		}
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, geoSession)
+}
+
+// createGeorepSession creates the geo-replication session, or updates it if
+// it exists and the request is forced, with the Master volume locked
+func createGeorepSession(ctx context.Context, masterid, remoteid uuid.UUID, req *georepapi.GeorepCreateReq) (*georepapi.GeorepSession, int, error) {
+	logger := gdctx.GetReqLogger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, req.MasterVol)
+	if err != nil {
+		return nil, restutils.ErrToStatusCode(err)
+	}
 	defer txn.Done()
 
 	// Check if Master volume exists and Matches with passed Volume ID
 	vol, err := volume.GetVolume(req.MasterVol)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, restutils.ErrToStatusCode(err)
 	}
 
 	// Check if Master Volume ID from store matches the input Master Volume ID
 	if !uuid.Equal(vol.ID, masterid) {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Master volume ID doesn't match")
-		return
+		return nil, http.StatusBadRequest, errs.New("Master volume ID doesn't match")
 	}
 
 	// Fetch existing session details from Store, if same
@@ -139,8 +177,7 @@ func georepCreateHandler(w http.ResponseWriter, r *http.Request) {
 	if err == nil {
 		sessionExists = true
 		if !req.Force {
-			restutils.SendHTTPError(ctx, w, http.StatusConflict, "Session already exists")
-			return
+			return nil, http.StatusConflict, errs.New("Session already exists")
 		}
 	}
 
@@ -148,14 +185,13 @@ func georepCreateHandler(w http.ResponseWriter, r *http.Request) {
 	// error while fetching from store or JSON marshal errors
 	if err != nil {
 		if _, ok := err.(*ErrGeorepSessionNotFound); !ok {
-			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-			return
+			return nil, http.StatusInternalServerError, err
 		}
 	}
 
 	// Initialize only if New Session
 	if !sessionExists {
-		geoSession = newGeorepSession(masterid, remoteid, req)
+		geoSession = newGeorepSession(masterid, remoteid, *req)
 
 		// Set Required Geo-rep Configurations
 		geoSession.Options["gluster-rundir"] = config.GetString("rundir")
@@ -183,8 +219,7 @@ func georepCreateHandler(w http.ResponseWriter, r *http.Request) {
 	//save volume information for transaction failure scenario
 	if err := txn.Ctx.Set("oldvolinfo", oldvolinfo); err != nil {
 		logger.WithError(err).Error("failed to set oldvolinfo in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	txn.Nodes = vol.Nodes()
@@ -206,15 +241,25 @@ func georepCreateHandler(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
+	// The keys are pushed to the Remote cluster once the session is created
+	if req.RemoteEndpoint != nil {
+		txn.Steps = append(txn.Steps, &transaction.Step{
+			DoFunc: "georeplication-ssh-keygen.Commit",
+			Nodes:  txn.Nodes,
+		})
+		if err = txn.Ctx.Set("volname", req.MasterVol); err != nil {
+			logger.WithError(err).Error("failed to set volname in transaction context")
+			return nil, http.StatusInternalServerError, err
+		}
+	}
+
 	if err = txn.Ctx.Set("geosession", geoSession); err != nil {
 		logger.WithError(err).Error("failed to set geosession in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 	if err = txn.Ctx.Set("volinfo", vol); err != nil {
 		logger.WithError(err).Error("failed to set volinfo in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	if err = txn.Do(); err != nil {
@@ -222,13 +267,10 @@ func georepCreateHandler(w http.ResponseWriter, r *http.Request) {
 			"mastervolid": masterid,
 			"remotevolid": remoteid,
 		}).Error("failed to create geo-replication session")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
-	events.Broadcast(newGeorepEvent(eventGeorepCreated, geoSession, nil))
-
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, geoSession)
+	return geoSession, http.StatusOK, nil
 }
 
 func georepActionHandler(w http.ResponseWriter, r *http.Request, action actionType) {
@@ -987,50 +1029,12 @@ func georepSSHKeyGenerateHandler(w http.ResponseWriter, r *http.Request) {
 	volname := p["volname"]
 
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
 
-	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	sshkeys, status, err := generateSSHKeys(ctx, volname)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
 		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
-	defer txn.Done()
-
-	// Check if Volume exists
-	vol, err := volume.GetVolume(volname)
-	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
-	}
-
-	txn.Nodes = vol.Nodes()
-	txn.Steps = []*transaction.Step{
-		{
-			DoFunc: "georeplication-ssh-keygen.Commit",
-			Nodes:  txn.Nodes,
-		},
-	}
-
-	if err = txn.Ctx.Set("volname", volname); err != nil {
-		logger.WithError(err).Error("failed to set volname in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
-	}
-
-	err = txn.Do()
-	if err != nil {
-		logger.WithError(err).WithField("volname", volname).Error("failed to generate SSH Keys")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
-	}
-
-	sshkeys, err := getSSHPublicKeys(volname)
-	if err != nil {
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
-		return
-	}
 
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, sshkeys)
 }
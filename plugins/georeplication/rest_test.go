@@ -0,0 +1,89 @@
+package georeplication
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/pkg/testutils"
+	georepapi "github.com/gluster/glusterd2/plugins/georeplication/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGeorepCreateHandlerSSHKeys validates that a session is returned when
+// its SSH keys are pushed to the Remote cluster, and when they fail to be
+func TestGeorepCreateHandlerSSHKeys(t *testing.T) {
+	masterid, remoteid := uuid.NewRandom(), uuid.NewRandom()
+
+	sessionCreated := false
+	defer testutils.Patch(&createGeorepSessionF, func(ctx context.Context, mid, rid uuid.UUID, req *georepapi.GeorepCreateReq) (*georepapi.GeorepSession, int, error) {
+		sessionCreated = true
+		return newGeorepSession(mid, rid, *req), http.StatusOK, nil
+	}).Restore()
+
+	var pushErr error
+	var pushedTo *georepapi.GeorepRemoteEndpoint
+	defer testutils.Patch(&distributeSSHKeysF, func(volname string, remote *georepapi.GeorepRemoteEndpoint, remotevol string) error {
+		pushedTo = remote
+		return pushErr
+	}).Restore()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/v1/geo-replication/{mastervolid}/{remotevolid}", func(w http.ResponseWriter, r *http.Request) {
+		ctx := gdctx.WithReqLogger(r.Context(), log.StandardLogger())
+		georepCreateHandler(w, r.WithContext(ctx))
+	})
+
+	create := func(body string) *httptest.ResponseRecorder {
+		sessionCreated, pushedTo = false, nil
+		r := httptest.NewRequest("POST", "/v1/geo-replication/"+masterid.String()+"/"+remoteid.String(), strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+	const (
+		withoutEndpoint = `{"mastervol": "gv1", "remotevol": "gv2", "remotehosts": [{"host": "rnode1"}]}`
+		withEndpoint    = `{"mastervol": "gv1", "remotevol": "gv2", "remotehosts": [{"host": "rnode1"}],
+			"remote-endpoint": {"url": "https://rnode1:24007", "secret": "s"}}`
+		invalidEndpoint = `{"mastervol": "gv1", "remotevol": "gv2", "remotehosts": [{"host": "rnode1"}],
+			"remote-endpoint": {"url": "rnode1:24007"}}`
+	)
+
+	// Keys are pushed only if the Remote endpoint is given
+	w := create(withoutEndpoint)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, sessionCreated)
+	assert.Nil(t, pushedTo)
+	assert.Empty(t, w.Header().Get("Warning"))
+
+	w = create(withEndpoint)
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, pushedTo)
+	assert.Equal(t, "https://rnode1:24007", pushedTo.URL)
+	assert.Empty(t, w.Header().Get("Warning"))
+
+	// The session is returned even if the keys fail to be pushed
+	pushErr = errors.New("connection refused")
+	w = create(withEndpoint)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Warning"), "connection refused")
+	var session georepapi.GeorepSession
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&session))
+	assert.Equal(t, masterid, session.MasterID)
+	assert.Equal(t, remoteid, session.RemoteID)
+
+	// An invalid endpoint is rejected before the session is created
+	w = create(invalidEndpoint)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.False(t, sessionCreated)
+}
@@ -0,0 +1,146 @@
+package georeplication
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/restclient"
+	georepapi "github.com/gluster/glusterd2/plugins/georeplication/api"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+// generateSSHKeys generates the SSH keys of gsyncd on all the nodes of the
+// volume, if not generated already, and returns their public keys
+func generateSSHKeys(ctx context.Context, volname string) ([]georepapi.GeorepSSHPublicKey, int, error) {
+	logger := gdctx.GetReqLogger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		return nil, restutils.ErrToStatusCode(err)
+	}
+	defer txn.Done()
+
+	// Check if Volume exists
+	vol, err := volume.GetVolume(volname)
+	if err != nil {
+		return nil, restutils.ErrToStatusCode(err)
+	}
+
+	txn.Nodes = vol.Nodes()
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "georeplication-ssh-keygen.Commit",
+			Nodes:  txn.Nodes,
+		},
+	}
+
+	if err = txn.Ctx.Set("volname", volname); err != nil {
+		logger.WithError(err).Error("failed to set volname in transaction context")
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if err = txn.Do(); err != nil {
+		logger.WithError(err).WithField("volname", volname).Error("failed to generate SSH Keys")
+		return nil, http.StatusInternalServerError, err
+	}
+
+	sshkeys, err := getSSHPublicKeys(volname)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return sshkeys, http.StatusOK, nil
+}
+
+func validateRemoteEndpoint(remote *georepapi.GeorepRemoteEndpoint) error {
+	if remote.URL == "" {
+		return errRemoteEndpointRequired
+	}
+	u, err := url.Parse(remote.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errInvalidRemoteEndpoint
+	}
+	return nil
+}
+
+// pushSSHKeys adds the public keys of the Master nodes to the authorized
+// keys of all the nodes of the Remote volume, through Glusterd2 of the
+// Remote cluster
+func pushSSHKeys(remote *georepapi.GeorepRemoteEndpoint, remotevol string, sshkeys []georepapi.GeorepSSHPublicKey) error {
+	client, err := restclient.New(remote.URL, remote.User, remote.Secret, "", remote.Insecure)
+	if err != nil {
+		return err
+	}
+
+	if err := client.GeorepSSHKeysPush(remotevol, sshkeys); err != nil {
+		return fmt.Errorf("failed to push SSH keys to remote cluster %s: %s", remote.URL, err)
+	}
+	return nil
+}
+
+// distributeSSHKeys pushes the SSH public keys generated on the nodes of a
+// volume to all the nodes of the Remote volume
+func distributeSSHKeys(volname string, remote *georepapi.GeorepRemoteEndpoint, remotevol string) error {
+	sshkeys, err := getSSHPublicKeys(volname)
+	if err != nil {
+		return err
+	}
+	return pushSSHKeys(remote, remotevol, sshkeys)
+}
+
+// sshKeysWarning returns the Warning header of a response for a session
+// created without its SSH keys pushed to the Remote cluster
+func sshKeysWarning(err error) string {
+	return "199 - " + strconv.Quote("SSH keys not distributed, push them with the ssh-key distribute API: "+err.Error())
+}
+
+func georepSSHKeyDistributeHandler(w http.ResponseWriter, r *http.Request) {
+	// Collect inputs from URL
+	volname := mux.Vars(r)["volname"]
+
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	// Parse the JSON body to get additional details of request
+	var req georepapi.GeorepSSHKeyDistributeReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	if req.RemoteVol == "" {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, "Remote volume name is required field")
+		return
+	}
+
+	if err := validateRemoteEndpoint(&req.RemoteEndpoint); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	sshkeys, status, err := generateSSHKeys(ctx, volname)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if err := pushSSHKeys(&req.RemoteEndpoint, req.RemoteVol, sshkeys); err != nil {
+		logger.WithError(err).WithFields(log.Fields{
+			"volname":   volname,
+			"remotevol": req.RemoteVol,
+		}).Error("failed to distribute SSH Keys")
+		restutils.SendHTTPError(ctx, w, http.StatusBadGateway, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, sshkeys)
+}
@@ -0,0 +1,66 @@
+package georeplication
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	georepapi "github.com/gluster/glusterd2/plugins/georeplication/api"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRemoteEndpoint(t *testing.T) {
+	tests := []struct {
+		url string
+		err error
+	}{
+		{"http://rnode1.example.com:24007", nil},
+		{"https://rnode1.example.com:24007", nil},
+		{"", errRemoteEndpointRequired},
+		{"rnode1.example.com:24007", errInvalidRemoteEndpoint},
+		{"ftp://rnode1.example.com", errInvalidRemoteEndpoint},
+		{"http://", errInvalidRemoteEndpoint},
+	}
+
+	for _, tt := range tests {
+		err := validateRemoteEndpoint(&georepapi.GeorepRemoteEndpoint{URL: tt.url})
+		assert.Equal(t, tt.err, err, tt.url)
+	}
+}
+
+func TestPushSSHKeys(t *testing.T) {
+	sshkeys := []georepapi.GeorepSSHPublicKey{{PeerID: uuid.NewRandom(), GsyncdKey: "gsyncd-key", TarKey: "tar-key"}}
+
+	status := http.StatusOK
+	var path string
+	var pushed []georepapi.GeorepSSHPublicKey
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		pushed = nil
+		json.NewDecoder(r.Body).Decode(&pushed)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status != http.StatusOK {
+			w.Write([]byte(`{"errors": [{"code": 1, "message": "volume not found"}]}`))
+		}
+	}))
+	defer ts.Close()
+
+	remote := &georepapi.GeorepRemoteEndpoint{URL: ts.URL}
+	require.NoError(t, pushSSHKeys(remote, "gv2", sshkeys))
+	assert.Equal(t, "/v1/ssh-key/gv2/push", path)
+	assert.Equal(t, sshkeys, pushed)
+
+	status = http.StatusNotFound
+	assert.Error(t, pushSSHKeys(remote, "gv2", sshkeys))
+}
+
+func TestSSHKeysWarning(t *testing.T) {
+	warning := sshKeysWarning(errors.New(`remote said "no"`))
+	assert.Equal(t, `199 - "SSH keys not distributed, push them with the ssh-key distribute API: remote said \"no\""`, warning)
+}
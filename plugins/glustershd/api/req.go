@@ -6,3 +6,22 @@ type SplitBrainReq struct {
 	HostName  string `json:"hostname,omitempty"`
 	BrickName string `json:"brickname,omitempty"`
 }
+
+// SplitBrainFile is the resolution of the split-brain of a file. Policy is
+// one of bigger-file, latest-mtime or source-brick, and SourceBrick is the
+// brick to take the file from, as <hostname>:<brickpath>, for source-brick.
+type SplitBrainFile struct {
+	FileName    string `json:"filename,omitempty"`
+	Policy      string `json:"policy,omitempty"`
+	SourceBrick string `json:"source-brick,omitempty"`
+}
+
+// SplitBrainResolveReq represents a request to resolve the split-brain of
+// files of a volume. Policy and SourceBrick apply to the files which don't
+// have their own. If Files is empty, all the files in split-brain are
+// resolved, which is only possible with the source-brick policy.
+type SplitBrainResolveReq struct {
+	Policy      string           `json:"policy,omitempty"`
+	SourceBrick string           `json:"source-brick,omitempty"`
+	Files       []SplitBrainFile `json:"files,omitempty"`
+}
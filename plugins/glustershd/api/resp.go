@@ -33,3 +33,18 @@ type HealInfo struct {
 	XMLNAME xml.Name        `xml:"cliOutput"`
 	Bricks  []BrickHealInfo `xml:"healInfo>bricks>brick"`
 }
+
+// SplitBrainFileResult is the result of resolving the split-brain of a file
+type SplitBrainFileResult struct {
+	SplitBrainFile
+	Resolved bool   `json:"resolved"`
+	Error    string `json:"error,omitempty"`
+}
+
+// SplitBrainResolveResp is the response sent for a split-brain resolution
+// request. Files are resolved one by one, and a failure to resolve one of
+// them doesn't stop the others from being resolved.
+type SplitBrainResolveResp struct {
+	Files  []SplitBrainFileResult `json:"files"`
+	Failed int                    `json:"failed"`
+}
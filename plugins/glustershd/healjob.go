@@ -0,0 +1,56 @@
+package glustershd
+
+import (
+	"context"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/jobs"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+)
+
+// healPollInterval is how often the heal status of a volume is checked
+// while a full heal job runs
+const healPollInterval = time.Minute
+
+// waitForFullHeal follows a full heal triggered on the volume until no
+// entries are pending heal. The heal itself is done by the self-heal
+// daemons, cancelling the job only stops following it.
+func waitForFullHeal(ctx context.Context, h *jobs.Handle, volname string) error {
+	logger := gdctx.GetReqLogger(ctx)
+	lastPending := int64(-1)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(healPollInterval):
+		}
+
+		volinfo, err := volume.GetVolume(volname)
+		if err == gderrors.ErrVolNotFound {
+			return err
+		} else if err != nil {
+			logger.WithError(err).Warn("failed to get volume")
+			continue
+		}
+		if volinfo.State != volume.VolStarted {
+			return gderrors.ErrVolNotStarted
+		}
+
+		pending, err := PendingHealEntries(volname)
+		if err != nil {
+			logger.WithError(err).Debug("failed to get heal info")
+			continue
+		}
+		if pending == 0 {
+			h.Logf("heal completed")
+			return nil
+		}
+		if pending != lastPending {
+			h.Logf("%d entries pending heal", pending)
+			lastPending = pending
+		}
+	}
+}
@@ -33,12 +33,32 @@ func (p *Plugin) RestRoutes() route.Routes {
 			Version:      1,
 			ResponseType: utils.GetTypeString(([]glustershdapi.BrickHealInfo)(nil)),
 			HandlerFunc:  selfhealInfoHandler},
+		route.Route{
+			Name:        "SelfHealEnable",
+			Method:      "POST",
+			Pattern:     "/volumes/{volname}/heal-enable",
+			Version:     1,
+			HandlerFunc: selfHealEnableHandler},
+		route.Route{
+			Name:        "SelfHealDisable",
+			Method:      "POST",
+			Pattern:     "/volumes/{volname}/heal-disable",
+			Version:     1,
+			HandlerFunc: selfHealDisableHandler},
 		route.Route{
 			Name:        "SelfHeal",
 			Method:      "POST",
 			Pattern:     "/volumes/{volname}/heal",
 			Version:     1,
 			HandlerFunc: selfHealHandler},
+		route.Route{
+			Name:         "SplitBrainResolve",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/heal/split-brain",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*glustershdapi.SplitBrainResolveReq)(nil)),
+			ResponseType: utils.GetTypeString((*glustershdapi.SplitBrainResolveResp)(nil)),
+			HandlerFunc:  splitBrainResolveHandler},
 		route.Route{
 			Name:        "Split-Brain-Operations",
 			Method:      "POST",
@@ -53,4 +73,6 @@ func (p *Plugin) RestRoutes() route.Routes {
 // Glusterd Transaction framework
 func (p *Plugin) RegisterStepFuncs() {
 	transaction.RegisterStepFunc(txnSelfHeal, "selfheal.Heal")
+	transaction.RegisterStepFunc(txnSetSelfHealDaemon, "selfheal.SetDaemon")
+	transaction.RegisterStepFunc(txnSetSelfHealDaemonUndo, "selfheal.SetDaemon.Undo")
 }
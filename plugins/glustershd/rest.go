@@ -2,6 +2,7 @@ package glustershd
 
 import (
 	"bytes"
+	"context"
 	"encoding/xml"
 	"fmt"
 	"net/http"
@@ -11,10 +12,11 @@ import (
 	"strings"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
-	"github.com/gluster/glusterd2/glusterd2/peer"
+	"github.com/gluster/glusterd2/glusterd2/jobs"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
 	gderrors "github.com/gluster/glusterd2/pkg/errors"
 	glustershdapi "github.com/gluster/glusterd2/plugins/glustershd/api"
 
@@ -124,6 +126,62 @@ func filterHealInfo(healInfo glustershdapi.HealInfo) (glustershdapi.HealInfo, er
 	return healInfo, nil
 }
 
+// PendingHealEntries returns the number of entries which are yet to be healed
+// across all the bricks of the volume. An error is returned if the count
+// isn't known for any of the bricks, for example when a brick is down.
+func PendingHealEntries(volname string) (int64, error) {
+	healInfoOutput, err := getHealInfo(volname, "info-summary")
+	if err != nil {
+		return -1, err
+	}
+
+	var info glustershdapi.HealInfo
+	if err := xml.Unmarshal([]byte(healInfoOutput), &info); err != nil {
+		return -1, err
+	}
+
+	info, err = filterHealInfo(info)
+	if err != nil {
+		return -1, err
+	}
+
+	var pending int64
+	for _, b := range info.Bricks {
+		if b.TotalEntries == nil || *b.TotalEntries < 0 {
+			return -1, fmt.Errorf("heal info not available for brick %s", b.Name)
+		}
+		pending += *b.TotalEntries
+	}
+	return pending, nil
+}
+
+// BrickHealSummary returns the summary of the heal info of a brick of the
+// volume, given by the ID of its peer and its path. It returns nil if the
+// brick isn't listed in the heal info.
+func BrickHealSummary(volname string, peerID string, brickPath string) (*glustershdapi.BrickHealInfo, error) {
+	healInfoOutput, err := getHealInfo(volname, "info-summary")
+	if err != nil {
+		return nil, err
+	}
+
+	var info glustershdapi.HealInfo
+	if err := xml.Unmarshal([]byte(healInfoOutput), &info); err != nil {
+		return nil, err
+	}
+
+	info, err = filterHealInfo(info)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range info.Bricks {
+		if b.HostID == peerID && strings.HasSuffix(b.Name, ":"+brickPath) {
+			return &b, nil
+		}
+	}
+	return nil, nil
+}
+
 func selfhealInfoHandler(w http.ResponseWriter, r *http.Request) {
 	var option string
 	p := mux.Vars(r)
@@ -135,6 +193,12 @@ func selfhealInfoHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	logger := gdctx.GetReqLogger(ctx)
 
+	// The option is passed on to glfsheal
+	if option != "" && option != "info-summary" && option != "split-brain-info" {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrInvalidHealInfoOpt)
+		return
+	}
+
 	txn, err := transaction.NewTxnWithLocks(ctx, volname)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
@@ -265,7 +329,22 @@ func selfHealHandler(w http.ResponseWriter, r *http.Request) {
 		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+
+	if healType != fullHeal {
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+		return
+	}
+
+	// Full heal can take long, it is followed by a job
+	job, err := jobs.Submit(ctx, api.JobTypeHealFull, volname, func(ctx context.Context, h *jobs.Handle) error {
+		return waitForFullHeal(ctx, h, volname)
+	})
+	if err != nil {
+		logger.WithError(err).Warn("failed to start job following full heal")
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, jobs.CreateJobInfoResp(job))
 }
 
 func splitBrainOperationHandler(w http.ResponseWriter, r *http.Request) {
@@ -282,16 +361,6 @@ func splitBrainOperationHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// check if hostname is in form of uuid, if yes, convert uuid to respective ip addr
-	if uuid.Parse(req.HostName) != nil {
-		peers, err := peer.GetPeer(req.HostName)
-		if err != nil {
-			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, gderrors.ErrPeerNotFound)
-			return
-		}
-		req.HostName = strings.Split(peers.PeerAddresses[0], ":")[0]
-	}
-
 	// Validate volume existence
 	volinfo, err := volume.GetVolume(volname)
 	if err != nil {
@@ -312,43 +381,9 @@ func splitBrainOperationHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var options []string
-	glusterdSockpath := path.Join(config.GetString("rundir"), "glusterd2.socket")
-	switch operation {
-	case "latest-mtime":
-		fallthrough
-	case "bigger-file":
-		if req.FileName == "" {
-			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrFilenameNotFound)
-			return
-		}
-		if !strings.HasPrefix(req.FileName, "/") {
-			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrInvalidFilenameFormat)
-			return
-		}
-		options = append(options, operation, req.FileName, "glusterd-sock", glusterdSockpath)
-
-	case "source-brick":
-		if req.HostName == "" || req.BrickName == "" {
-			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrHostOrBrickNotFound)
-			return
-		}
-		if err := volume.CheckBrickExistence(volinfo, req.HostName, req.BrickName); err != nil {
-			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
-			return
-		}
-		brickPath := fmt.Sprintf("%s:%s", req.HostName, req.BrickName)
-		if req.FileName != "" {
-			if !strings.HasPrefix(req.FileName, "/") {
-				restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrInvalidFilenameFormat)
-				return
-			}
-			options = append(options, operation, brickPath, req.FileName, "glusterd-sock", glusterdSockpath)
-		} else {
-			options = append(options, operation, brickPath, "glusterd-sock", glusterdSockpath)
-		}
-	default:
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrInvalidSplitBrainOp)
+	options, err := splitBrainArgs(volinfo, operation, req.FileName, req.HostName, req.BrickName)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
 		return
 	}
 	_, err = runGlfshealBin(volname, options)
@@ -360,3 +395,93 @@ func splitBrainOperationHandler(w http.ResponseWriter, r *http.Request) {
 
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
 }
+
+func selfHealEnableHandler(w http.ResponseWriter, r *http.Request) {
+	setSelfHealDaemon(w, r, true)
+}
+
+func selfHealDisableHandler(w http.ResponseWriter, r *http.Request) {
+	setSelfHealDaemon(w, r, false)
+}
+
+// setSelfHealDaemon turns the self-heal daemon of the volume on or off, and
+// starts or stops glustershd on the peers of the volume accordingly
+func setSelfHealDaemon(w http.ResponseWriter, r *http.Request, enable bool) {
+	// Collect inputs from URL
+	volname := mux.Vars(r)["volname"]
+
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	// Validate volume existence
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	// Validate volume type
+	if !isVolReplicate(volinfo.Type) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrVolTypeNotInReplicateOrDisperse)
+		return
+	}
+
+	// The daemon runs unless it has been turned off, but heal needs it to
+	// be turned on explicitly
+	value := "on"
+	if enable && isHealEnabled(volinfo) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrSelfHealAlreadyEnabled)
+		return
+	}
+	if !enable {
+		if volinfo.Options[shdKey] == "off" {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrSelfHealAlreadyDisabled)
+			return
+		}
+		value = "off"
+	}
+
+	//save volume information for transaction failure scenario
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	volinfo.Options[shdKey] = value
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "vol-option.UpdateVolinfo",
+			UndoFunc: "vol-option.UpdateVolinfo.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Sync:     true,
+		},
+		{
+			DoFunc:   "selfheal.SetDaemon",
+			UndoFunc: "selfheal.SetDaemon.Undo",
+			Nodes:    volinfo.Nodes(),
+		},
+	}
+
+	if err = txn.Do(); err != nil {
+		logger.WithError(err).WithField("volname", volname).Error("failed to set self-heal daemon")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+}
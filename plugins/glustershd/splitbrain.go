@@ -0,0 +1,172 @@
+package glustershd
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	glustershdapi "github.com/gluster/glusterd2/plugins/glustershd/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+)
+
+const (
+	policyBiggerFile  = "bigger-file"
+	policyLatestMtime = "latest-mtime"
+	policySourceBrick = "source-brick"
+)
+
+// splitBrainArgs returns the glfsheal arguments to resolve the split-brain of
+// the file as per the policy. The file may be left out only with the
+// source-brick policy, to resolve all the files in split-brain. The hostname
+// of the source brick may be given as the ID of its peer.
+func splitBrainArgs(volinfo *volume.Volinfo, policy, filename, hostname, brickname string) ([]string, error) {
+	if filename != "" && !strings.HasPrefix(filename, "/") {
+		return nil, gderrors.ErrInvalidFilenameFormat
+	}
+
+	var args []string
+	switch policy {
+	case policyBiggerFile, policyLatestMtime:
+		if filename == "" {
+			return nil, gderrors.ErrFilenameNotFound
+		}
+		args = []string{policy, filename}
+
+	case policySourceBrick:
+		if hostname == "" || brickname == "" {
+			return nil, gderrors.ErrHostOrBrickNotFound
+		}
+		// check if hostname is in form of uuid, if yes, convert uuid to respective ip addr
+		if uuid.Parse(hostname) != nil {
+			p, err := peer.GetPeer(hostname)
+			if err != nil {
+				return nil, gderrors.ErrPeerNotFound
+			}
+			hostname = strings.Split(p.PeerAddresses[0], ":")[0]
+		}
+		if err := volume.CheckBrickExistence(volinfo, hostname, brickname); err != nil {
+			return nil, err
+		}
+		args = []string{policy, fmt.Sprintf("%s:%s", hostname, brickname)}
+		if filename != "" {
+			args = append(args, filename)
+		}
+
+	default:
+		return nil, gderrors.ErrInvalidSplitBrainOp
+	}
+
+	glusterdSockpath := path.Join(config.GetString("rundir"), "glusterd2.socket")
+	return append(args, "glusterd-sock", glusterdSockpath), nil
+}
+
+// splitSourceBrick splits a source brick given as <hostname>:<brickpath>
+func splitSourceBrick(b string) (string, string) {
+	if i := strings.Index(b, ":/"); i > 0 {
+		return b[:i], b[i+1:]
+	}
+	return "", ""
+}
+
+// splitBrainResolutions returns the resolution of each file of the request,
+// with the policy and source brick of the request applied to the files
+// which don't have their own
+func splitBrainResolutions(req *glustershdapi.SplitBrainResolveReq) []glustershdapi.SplitBrainFile {
+	if len(req.Files) == 0 {
+		return []glustershdapi.SplitBrainFile{{
+			Policy:      req.Policy,
+			SourceBrick: req.SourceBrick,
+		}}
+	}
+
+	files := make([]glustershdapi.SplitBrainFile, 0, len(req.Files))
+	for _, f := range req.Files {
+		if f.Policy == "" {
+			f.Policy = req.Policy
+		}
+		if f.Policy == policySourceBrick && f.SourceBrick == "" {
+			f.SourceBrick = req.SourceBrick
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+func splitBrainResolveHandler(w http.ResponseWriter, r *http.Request) {
+	volname := mux.Vars(r)["volname"]
+
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req glustershdapi.SplitBrainResolveReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if !isVolReplicate(volinfo.Type) {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrVolTypeNotInReplicateOrDisperse)
+		return
+	}
+
+	if volinfo.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrVolNotStarted)
+		return
+	}
+
+	// All the files are validated before any of them is resolved
+	files := splitBrainResolutions(&req)
+	args := make([][]string, len(files))
+	for i, f := range files {
+		hostname, brickname := splitSourceBrick(f.SourceBrick)
+		if f.SourceBrick != "" && hostname == "" {
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, gderrors.ErrInvalidSourceBrick)
+			return
+		}
+		args[i], err = splitBrainArgs(volinfo, f.Policy, f.FileName, hostname, brickname)
+		if err != nil {
+			if f.FileName != "" {
+				err = fmt.Errorf("%s: %s", f.FileName, err)
+			}
+			restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	resp := glustershdapi.SplitBrainResolveResp{
+		Files: make([]glustershdapi.SplitBrainFileResult, 0, len(files)),
+	}
+	for i, f := range files {
+		result := glustershdapi.SplitBrainFileResult{SplitBrainFile: f, Resolved: true}
+		if _, err := runGlfshealBin(volname, args[i]); err != nil {
+			logger.WithError(err).WithFields(log.Fields{
+				"volume": volname,
+				"file":   f.FileName,
+				"policy": f.Policy,
+			}).Error("failed to resolve split-brain")
+			result.Resolved = false
+			result.Error = err.Error()
+			resp.Failed++
+		}
+		resp.Files = append(resp.Files, result)
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, &resp)
+}
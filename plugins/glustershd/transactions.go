@@ -11,6 +11,9 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/servers/sunrpc/dict"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/glusterd2/xlator"
+
+	"github.com/pborman/uuid"
 )
 
 func getHxlChildrenCount(volinfo *volume.Volinfo) (int, string) {
@@ -61,6 +64,22 @@ func selectHxlatorsWithBricks(volinfo *volume.Volinfo, healType int) map[string]
 	return reqDict
 }
 
+// FullHealSteps returns the transaction steps which trigger a full self-heal
+// of the volume on the given nodes. The heal type is set in the transaction
+// context, the caller must set "volinfo".
+func FullHealSteps(c transaction.TxnCtx, nodes []uuid.UUID) ([]*transaction.Step, error) {
+	if err := c.Set("healType", fullHeal); err != nil {
+		return nil, err
+	}
+
+	return []*transaction.Step{
+		{
+			DoFunc: "selfheal.Heal",
+			Nodes:  nodes,
+		},
+	}, nil
+}
+
 func txnSelfHeal(c transaction.TxnCtx) error {
 
 	var volinfo volume.Volinfo
@@ -111,3 +130,27 @@ func txnSelfHeal(c transaction.TxnCtx) error {
 
 	return nil
 }
+
+// setSelfHealDaemon starts or stops glustershd as per the self-heal daemon
+// option of the volume. Nothing is done for volumes which aren't started.
+func setSelfHealDaemon(c transaction.TxnCtx, key string) error {
+	var volinfo volume.Volinfo
+	if err := c.Get(key, &volinfo); err != nil {
+		return err
+	}
+
+	if volinfo.State != volume.VolStarted {
+		return nil
+	}
+
+	actor := &shdActor{}
+	return actor.Do(&volinfo, selfHealKey, volinfo.Options[shdKey], xlator.VolumeSet, c.Logger())
+}
+
+func txnSetSelfHealDaemon(c transaction.TxnCtx) error {
+	return setSelfHealDaemon(c, "volinfo")
+}
+
+func txnSetSelfHealDaemonUndo(c transaction.TxnCtx) error {
+	return setSelfHealDaemon(c, "oldvolinfo")
+}
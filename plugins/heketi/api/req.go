@@ -0,0 +1,106 @@
+// Package api has the requests and responses of the Heketi compatible API.
+// They follow the Heketi REST API, so that Heketi clients can be used with
+// glusterd2 as is, and their JSON field names differ from the rest of the
+// glusterd2 API.
+package api
+
+// Durability types of a volume
+const (
+	DurabilityNone      = "none"
+	DurabilityReplicate = "replicate"
+	DurabilityDisperse  = "disperse"
+)
+
+// States of a node or device
+const (
+	EntryStateOnline  = "online"
+	EntryStateOffline = "offline"
+	EntryStateFailed  = "failed"
+)
+
+// ClusterFlags are the kinds of volumes which can be created in a cluster
+type ClusterFlags struct {
+	Block bool `json:"block"`
+	File  bool `json:"file"`
+}
+
+// ClusterCreateRequest represents a request to create a cluster
+type ClusterCreateRequest struct {
+	ClusterFlags
+}
+
+// HostAddresses are the addresses of a node. Manage addresses are used to
+// reach glusterd2 and Storage addresses by clients.
+type HostAddresses struct {
+	Manage  []string `json:"manage"`
+	Storage []string `json:"storage"`
+}
+
+// NodeAddRequest represents a request to add a node to a cluster
+type NodeAddRequest struct {
+	Zone      int               `json:"zone"`
+	Hostnames HostAddresses     `json:"hostnames"`
+	ClusterID string            `json:"cluster"`
+	Tags      map[string]string `json:"tags,omitempty"`
+}
+
+// StateRequest represents a request to change the state of a node or device
+type StateRequest struct {
+	State string `json:"state"`
+}
+
+// Device has the name of a device
+type Device struct {
+	Name string            `json:"name"`
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// DeviceAddRequest represents a request to add a device to a node
+type DeviceAddRequest struct {
+	Device
+	NodeID      string `json:"node"`
+	DestroyData bool   `json:"destroydata,omitempty"`
+}
+
+// ReplicaDurability is the layout of a replicate volume
+type ReplicaDurability struct {
+	Replica int `json:"replica,omitempty"`
+}
+
+// DisperseDurability is the layout of a disperse volume
+type DisperseDurability struct {
+	Data       int `json:"data,omitempty"`
+	Redundancy int `json:"redundancy,omitempty"`
+}
+
+// VolumeDurabilityInfo is the layout of a volume
+type VolumeDurabilityInfo struct {
+	Type      string             `json:"type,omitempty"`
+	Replicate ReplicaDurability  `json:"replicate,omitempty"`
+	Disperse  DisperseDurability `json:"disperse,omitempty"`
+}
+
+// VolumeSnapshot is the snapshot setting of a volume. Factor is the ratio
+// of the size of the bricks to the size of the volume.
+type VolumeSnapshot struct {
+	Enable bool    `json:"enable"`
+	Factor float32 `json:"factor"`
+}
+
+// VolumeCreateRequest represents a request to create a volume. Size is in
+// GiB. GlusterVolumeOptions are volume options in the "<key> <value>" form.
+type VolumeCreateRequest struct {
+	Size                 int                  `json:"size"`
+	Clusters             []string             `json:"clusters,omitempty"`
+	Name                 string               `json:"name"`
+	Durability           VolumeDurabilityInfo `json:"durability,omitempty"`
+	Gid                  int64                `json:"gid,omitempty"`
+	GlusterVolumeOptions []string             `json:"glustervolumeoptions,omitempty"`
+	Block                bool                 `json:"block,omitempty"`
+	Snapshot             VolumeSnapshot       `json:"snapshot"`
+}
+
+// VolumeExpandRequest represents a request to expand a volume by Size GiB
+type VolumeExpandRequest struct {
+	Size int `json:"expand_size"`
+}
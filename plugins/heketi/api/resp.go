@@ -0,0 +1,82 @@
+package api
+
+// ClusterInfoResponse is the response sent for a cluster info request
+type ClusterInfoResponse struct {
+	ID           string   `json:"id"`
+	Nodes        []string `json:"nodes"`
+	Volumes      []string `json:"volumes"`
+	BlockVolumes []string `json:"blockvolumes"`
+	ClusterFlags
+}
+
+// ClusterListResponse is the response sent for a cluster list request
+type ClusterListResponse struct {
+	Clusters []string `json:"clusters"`
+}
+
+// StorageSize is the size of a device in KiB
+type StorageSize struct {
+	Total uint64 `json:"total"`
+	Free  uint64 `json:"free"`
+	Used  uint64 `json:"used"`
+}
+
+// BrickInfo is a brick of a volume. Size is in KiB.
+type BrickInfo struct {
+	ID       string `json:"id"`
+	Path     string `json:"path"`
+	DeviceID string `json:"device"`
+	NodeID   string `json:"node"`
+	VolumeID string `json:"volume"`
+	Size     uint64 `json:"size"`
+}
+
+// DeviceInfo is a device of a node
+type DeviceInfo struct {
+	Device
+	Storage StorageSize `json:"storage"`
+	ID      string      `json:"id"`
+}
+
+// DeviceInfoResponse is the response sent for a device info request
+type DeviceInfoResponse struct {
+	DeviceInfo
+	State  string      `json:"state"`
+	Bricks []BrickInfo `json:"bricks"`
+}
+
+// NodeInfoResponse is the response sent for a node info request
+type NodeInfoResponse struct {
+	NodeAddRequest
+	ID          string               `json:"id"`
+	State       string               `json:"state"`
+	DevicesInfo []DeviceInfoResponse `json:"devices"`
+}
+
+// GlusterFSMount is how a volume is mounted by clients. MountPoint is the
+// <host>:<volname> to mount and Options the mount options, which have the
+// other hosts as backup volfile servers.
+type GlusterFSMount struct {
+	Hosts      []string          `json:"hosts"`
+	MountPoint string            `json:"device"`
+	Options    map[string]string `json:"options"`
+}
+
+// VolumeMount has how a volume is mounted by clients
+type VolumeMount struct {
+	GlusterFS GlusterFSMount `json:"glusterfs"`
+}
+
+// VolumeInfoResponse is the response sent for a volume info request
+type VolumeInfoResponse struct {
+	VolumeCreateRequest
+	ID      string      `json:"id"`
+	Cluster string      `json:"cluster"`
+	Mount   VolumeMount `json:"mount"`
+	Bricks  []BrickInfo `json:"bricks"`
+}
+
+// VolumeListResponse is the response sent for a volume list request
+type VolumeListResponse struct {
+	Volumes []string `json:"volumes"`
+}
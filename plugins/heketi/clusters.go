@@ -0,0 +1,92 @@
+package heketi
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	heketiapi "github.com/gluster/glusterd2/plugins/heketi/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+// A glusterd2 cluster is a single Heketi cluster, with the ID of the
+// cluster.
+
+// isCluster returns true if id is the Heketi ID of the cluster
+func isCluster(id string) bool {
+	return uuid.Equal(parseID(id), gdctx.MyClusterID)
+}
+
+func clusterInfo(ctx context.Context) (*heketiapi.ClusterInfoResponse, error) {
+	peers, err := peer.GetPeers()
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := volume.GetVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &heketiapi.ClusterInfoResponse{
+		ID:           toID(gdctx.MyClusterID),
+		Nodes:        []string{},
+		Volumes:      []string{},
+		BlockVolumes: []string{},
+		ClusterFlags: heketiapi.ClusterFlags{File: true},
+	}
+	for _, p := range peers {
+		resp.Nodes = append(resp.Nodes, toID(p.ID))
+	}
+	for _, v := range volumes {
+		if gdctx.ReqNamespaceAllowed(ctx, v.GetNamespace()) {
+			resp.Volumes = append(resp.Volumes, toID(v.ID))
+		}
+	}
+	sort.Strings(resp.Nodes)
+	sort.Strings(resp.Volumes)
+	return resp, nil
+}
+
+func sendClusterInfo(ctx context.Context, w http.ResponseWriter, status int) {
+	resp, err := clusterInfo(ctx)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, status, resp)
+}
+
+// clusterCreateHandler returns the cluster, as there is only one. Heketi
+// clients loading a topology create a cluster to add the nodes to.
+func clusterCreateHandler(w http.ResponseWriter, r *http.Request) {
+	sendClusterInfo(r.Context(), w, http.StatusCreated)
+}
+
+func clusterListHandler(w http.ResponseWriter, r *http.Request) {
+	resp := &heketiapi.ClusterListResponse{
+		Clusters: []string{toID(gdctx.MyClusterID)},
+	}
+	restutils.SendHTTPResponse(r.Context(), w, http.StatusOK, resp)
+}
+
+func clusterInfoHandler(w http.ResponseWriter, r *http.Request) {
+	if !isCluster(mux.Vars(r)["id"]) {
+		sendError(w, http.StatusNotFound, errClusterNotFound)
+		return
+	}
+	sendClusterInfo(r.Context(), w, http.StatusOK)
+}
+
+func clusterDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	if !isCluster(mux.Vars(r)["id"]) {
+		sendError(w, http.StatusNotFound, errClusterNotFound)
+		return
+	}
+	sendError(w, http.StatusConflict, errClusterNotDeletable)
+}
@@ -0,0 +1,200 @@
+package heketi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/utils"
+	"github.com/gluster/glusterd2/plugins/device"
+	deviceapi "github.com/gluster/glusterd2/plugins/device/api"
+	"github.com/gluster/glusterd2/plugins/device/deviceutils"
+	heketiapi "github.com/gluster/glusterd2/plugins/heketi/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+// brickInfo returns the Heketi brick of a brick
+func brickInfo(b *brick.Brickinfo) heketiapi.BrickInfo {
+	info := heketiapi.BrickInfo{
+		ID:       toID(b.ID),
+		Path:     b.Path,
+		NodeID:   toID(b.PeerID),
+		VolumeID: toID(b.VolumeID),
+		Size:     b.TotalSize / utils.KiB,
+	}
+	if b.RootDevice != "" {
+		info.DeviceID = deviceID(b.PeerID, b.RootDevice)
+	}
+	return info
+}
+
+// deviceInfo returns the Heketi device of a device, with those of the
+// bricks which are on it
+func deviceInfo(d *deviceapi.Info, bricks []brick.Brickinfo) *heketiapi.DeviceInfoResponse {
+	resp := &heketiapi.DeviceInfoResponse{
+		DeviceInfo: heketiapi.DeviceInfo{
+			Device: heketiapi.Device{Name: d.Device},
+			Storage: heketiapi.StorageSize{
+				Total: d.TotalSize / utils.KiB,
+				Free:  d.AvailableSize / utils.KiB,
+				Used:  d.UsedSize / utils.KiB,
+			},
+			ID: deviceID(d.PeerID, d.Device),
+		},
+		State:  heketiapi.EntryStateOnline,
+		Bricks: []heketiapi.BrickInfo{},
+	}
+	if !d.Schedulable() {
+		resp.State = heketiapi.EntryStateOffline
+	}
+	for i := range bricks {
+		b := &bricks[i]
+		if uuid.Equal(b.PeerID, d.PeerID) && b.RootDevice == d.Device {
+			resp.Bricks = append(resp.Bricks, brickInfo(b))
+		}
+	}
+	return resp
+}
+
+// getDevice returns the device with the Heketi ID, and the HTTP status code
+// to respond with if it can't be found
+func getDevice(id string) (*deviceapi.Info, int, error) {
+	devices, err := deviceutils.GetDevices()
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	for i := range devices {
+		if deviceID(devices[i].PeerID, devices[i].Device) == id {
+			return &devices[i], http.StatusOK, nil
+		}
+	}
+	return nil, http.StatusNotFound, errDeviceNotFound
+}
+
+// deviceAddHandler adds a device to a peer. Tags and destroydata are
+// ignored, devices with data on them can't be added.
+func deviceAddHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req heketiapi.DeviceAddRequest
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		sendError(w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	if req.Name == "" {
+		sendError(w, http.StatusBadRequest, errDeviceNameRequired)
+		return
+	}
+
+	p, status, err := getNode(req.NodeID)
+	if err != nil {
+		sendError(w, status, err)
+		return
+	}
+	if _, err := deviceutils.GetDevice(p.ID.String(), req.Name); err == nil {
+		sendError(w, http.StatusConflict, errDeviceExists)
+		return
+	} else if err != gderrors.ErrDeviceNotFound {
+		sendError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	addReq := &deviceapi.AddDeviceReq{Device: req.Name}
+	sendAsync(ctx, w, "", func(ctx context.Context) (string, error) {
+		_, _, err := device.AddDevice(ctx, p.ID.String(), addReq)
+		return "", err
+	})
+}
+
+func deviceInfoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	d, status, err := getDevice(mux.Vars(r)["id"])
+	if err != nil {
+		sendError(w, status, err)
+		return
+	}
+
+	bricks, err := volume.GetAllBricksInCluster()
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, deviceInfo(d, bricks))
+}
+
+// deviceDeleteHandler removes a device. Like with Heketi, the device must
+// not have bricks.
+func deviceDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	d, status, err := getDevice(mux.Vars(r)["id"])
+	if err != nil {
+		sendError(w, status, err)
+		return
+	}
+
+	bricks, err := device.BricksOnDevice(ctx, d.PeerID.String(), d.Device)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if len(bricks) > 0 {
+		sendError(w, http.StatusConflict, errDeviceHasBricks)
+		return
+	}
+
+	sendAsync(ctx, w, "", func(ctx context.Context) (string, error) {
+		return "", device.RemoveDevice(ctx, d.PeerID.String(), d.Device)
+	})
+}
+
+// deviceStateHandler disables the device when it is set offline, so that
+// no new bricks are placed on it, and enables it when it is set online
+func deviceStateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	d, status, err := getDevice(mux.Vars(r)["id"])
+	if err != nil {
+		sendError(w, status, err)
+		return
+	}
+
+	var req heketiapi.StateRequest
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		sendError(w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	var state string
+	switch req.State {
+	case heketiapi.EntryStateOnline:
+		state = deviceapi.DeviceEnabled
+	case heketiapi.EntryStateOffline:
+		state = deviceapi.DeviceDisabled
+	case heketiapi.EntryStateFailed:
+		sendError(w, http.StatusBadRequest, errFailedNotSupported)
+		return
+	default:
+		sendError(w, http.StatusBadRequest, errInvalidState)
+		return
+	}
+
+	peerID := d.PeerID.String()
+	sendAsync(ctx, w, "", func(ctx context.Context) (string, error) {
+		txn, err := transaction.NewTxnWithLocks(ctx, peerID+d.Device)
+		if err != nil {
+			return "", err
+		}
+		defer txn.Done()
+
+		return "", deviceutils.SetDeviceState(peerID, d.Device, state)
+	})
+}
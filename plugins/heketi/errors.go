@@ -0,0 +1,29 @@
+package heketi
+
+import (
+	"errors"
+)
+
+var (
+	errClusterNotFound      = errors.New("cluster not found")
+	errClusterNotDeletable  = errors.New("the cluster of glusterd2 can not be deleted")
+	errNodeNotFound         = errors.New("node not found")
+	errNodeHasDevices       = errors.New("node has devices, they must be deleted first")
+	errNodeHasBricks        = errors.New("node has bricks of volumes, they must be deleted first")
+	errNodeNotAlive         = errors.New("node is not alive")
+	errManageHostRequired   = errors.New("a manage hostname of the node is required")
+	errDeviceNotFound       = errors.New("device not found")
+	errDeviceNameRequired   = errors.New("name of the device is required")
+	errDeviceExists         = errors.New("device already exists")
+	errDeviceHasBricks      = errors.New("device has bricks of volumes, they must be deleted first")
+	errVolumeNotFound       = errors.New("volume not found")
+	errInvalidState         = errors.New("invalid state, it must be online or offline")
+	errFailedNotSupported   = errors.New("setting the state to failed is not supported, drain the device or replace the node with the glusterd2 API")
+	errInvalidSize          = errors.New("invalid size, it must be at least 1 GiB")
+	errInvalidDurability    = errors.New("invalid durability type, it must be none, replicate or disperse")
+	errInvalidVolumeOption  = errors.New("invalid volume option, it must be of the form <key> <value>")
+	errBlockNotSupported    = errors.New("block hosting volumes are not supported, use the block volume API of glusterd2")
+	errExpandNotSupported   = errors.New("only volumes with bricks provisioned from lvm devices can be expanded")
+	errVolumeHasSnapshots   = errors.New("volume has snapshots, they must be deleted first")
+	errSelfDeleteNotAllowed = errors.New("the node serving the request can not be deleted")
+)
@@ -0,0 +1,54 @@
+package heketi
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"net"
+
+	"github.com/pborman/uuid"
+)
+
+// Heketi IDs are 32 hex digits. The cluster, nodes, volumes and bricks have
+// the ID of their UUID without the dashes. Devices have no ID in glusterd2,
+// so they are given a hash of their peer and name.
+
+// toID returns the Heketi ID of a UUID
+func toID(id uuid.UUID) string {
+	return hex.EncodeToString(id)
+}
+
+// parseID returns the UUID of a Heketi ID, or nil if it is not valid.
+// UUIDs with dashes are accepted too.
+func parseID(id string) uuid.UUID {
+	if u := uuid.Parse(id); u != nil {
+		return u
+	}
+	b, err := hex.DecodeString(id)
+	if err != nil || len(b) != 16 {
+		return nil
+	}
+	return uuid.UUID(b)
+}
+
+// deviceID returns the Heketi ID of the device of a peer
+func deviceID(peerID uuid.UUID, device string) string {
+	sum := md5.Sum([]byte(peerID.String() + ":" + device))
+	return hex.EncodeToString(sum[:])
+}
+
+// hostOf returns the host of an address, which may not have a port
+func hostOf(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// hostsOf returns the hosts of the addresses
+func hostsOf(addrs []string) []string {
+	hosts := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		hosts = append(hosts, hostOf(addr))
+	}
+	return hosts
+}
@@ -0,0 +1,35 @@
+package heketi
+
+import (
+	"testing"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseID(t *testing.T) {
+	id := uuid.NewRandom()
+
+	assert.Len(t, toID(id), 32)
+	assert.True(t, uuid.Equal(id, parseID(toID(id))))
+	assert.True(t, uuid.Equal(id, parseID(id.String())))
+
+	for _, s := range []string{"", "node", "0123456789abcdef", toID(id) + "00", "zz" + toID(id)[2:]} {
+		assert.Nil(t, parseID(s), s)
+	}
+}
+
+func TestDeviceID(t *testing.T) {
+	peerID := uuid.NewRandom()
+
+	assert.Len(t, deviceID(peerID, "/dev/sdb"), 32)
+	assert.Equal(t, deviceID(peerID, "/dev/sdb"), deviceID(peerID, "/dev/sdb"))
+	assert.NotEqual(t, deviceID(peerID, "/dev/sdb"), deviceID(peerID, "/dev/sdc"))
+	assert.NotEqual(t, deviceID(peerID, "/dev/sdb"), deviceID(uuid.NewRandom(), "/dev/sdb"))
+}
+
+func TestHostsOf(t *testing.T) {
+	assert.Equal(t, []string{"node1", "10.0.0.2", "::1", "node4"},
+		hostsOf([]string{"node1:24008", "10.0.0.2", "[::1]:24007", "node4"}))
+	assert.Empty(t, hostsOf(nil))
+}
@@ -0,0 +1,173 @@
+package heketi
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+	heketiapi "github.com/gluster/glusterd2/plugins/heketi/api"
+
+	config "github.com/spf13/viper"
+)
+
+// EnableFlag is the flag enabling the Heketi compatible API
+const EnableFlag = "heketi-compat"
+
+// Plugin is a structure which implements GlusterdPlugin interface
+type Plugin struct {
+}
+
+// Name returns name of plugin
+func (p *Plugin) Name() string {
+	return "heketi"
+}
+
+// RestRoutes returns list of REST API routes to register with Glusterd. The
+// routes are registered only if the Heketi compatible API is enabled.
+func (p *Plugin) RestRoutes() route.Routes {
+	if !config.GetBool(EnableFlag) {
+		return nil
+	}
+
+	return route.Routes{
+		route.Route{
+			Name:        "HeketiHello",
+			Method:      "GET",
+			Pattern:     "/heketi/hello",
+			Version:     1,
+			HandlerFunc: helloHandler},
+		route.Route{
+			Name:        "HeketiQueue",
+			Method:      "GET",
+			Pattern:     "/heketi/queue/{id}",
+			Version:     1,
+			HandlerFunc: queueHandler},
+		route.Route{
+			Name:         "HeketiClusterCreate",
+			Method:       "POST",
+			Pattern:      "/heketi/clusters",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*heketiapi.ClusterCreateRequest)(nil)),
+			ResponseType: utils.GetTypeString((*heketiapi.ClusterInfoResponse)(nil)),
+			HandlerFunc:  clusterCreateHandler,
+			Role:         api.RoleAdmin},
+		route.Route{
+			Name:         "HeketiClusterList",
+			Method:       "GET",
+			Pattern:      "/heketi/clusters",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*heketiapi.ClusterListResponse)(nil)),
+			HandlerFunc:  clusterListHandler},
+		route.Route{
+			Name:         "HeketiClusterInfo",
+			Method:       "GET",
+			Pattern:      "/heketi/clusters/{id}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*heketiapi.ClusterInfoResponse)(nil)),
+			HandlerFunc:  clusterInfoHandler},
+		route.Route{
+			Name:        "HeketiClusterDelete",
+			Method:      "DELETE",
+			Pattern:     "/heketi/clusters/{id}",
+			Version:     1,
+			HandlerFunc: clusterDeleteHandler},
+		route.Route{
+			Name:        "HeketiNodeAdd",
+			Method:      "POST",
+			Pattern:     "/heketi/nodes",
+			Version:     1,
+			RequestType: utils.GetTypeString((*heketiapi.NodeAddRequest)(nil)),
+			HandlerFunc: nodeAddHandler,
+			Role:        api.RoleAdmin},
+		route.Route{
+			Name:         "HeketiNodeInfo",
+			Method:       "GET",
+			Pattern:      "/heketi/nodes/{id}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*heketiapi.NodeInfoResponse)(nil)),
+			HandlerFunc:  nodeInfoHandler},
+		route.Route{
+			Name:        "HeketiNodeDelete",
+			Method:      "DELETE",
+			Pattern:     "/heketi/nodes/{id}",
+			Version:     1,
+			HandlerFunc: nodeDeleteHandler},
+		route.Route{
+			Name:        "HeketiNodeState",
+			Method:      "POST",
+			Pattern:     "/heketi/nodes/{id}/state",
+			Version:     1,
+			RequestType: utils.GetTypeString((*heketiapi.StateRequest)(nil)),
+			HandlerFunc: nodeStateHandler,
+			Role:        api.RoleAdmin},
+		route.Route{
+			Name:        "HeketiDeviceAdd",
+			Method:      "POST",
+			Pattern:     "/heketi/devices",
+			Version:     1,
+			RequestType: utils.GetTypeString((*heketiapi.DeviceAddRequest)(nil)),
+			HandlerFunc: deviceAddHandler,
+			Role:        api.RoleAdmin},
+		route.Route{
+			Name:         "HeketiDeviceInfo",
+			Method:       "GET",
+			Pattern:      "/heketi/devices/{id}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*heketiapi.DeviceInfoResponse)(nil)),
+			HandlerFunc:  deviceInfoHandler},
+		route.Route{
+			Name:        "HeketiDeviceDelete",
+			Method:      "DELETE",
+			Pattern:     "/heketi/devices/{id}",
+			Version:     1,
+			HandlerFunc: deviceDeleteHandler},
+		route.Route{
+			Name:        "HeketiDeviceState",
+			Method:      "POST",
+			Pattern:     "/heketi/devices/{id}/state",
+			Version:     1,
+			RequestType: utils.GetTypeString((*heketiapi.StateRequest)(nil)),
+			HandlerFunc: deviceStateHandler,
+			Role:        api.RoleAdmin},
+		route.Route{
+			Name:        "HeketiVolumeCreate",
+			Method:      "POST",
+			Pattern:     "/heketi/volumes",
+			Version:     1,
+			RequestType: utils.GetTypeString((*heketiapi.VolumeCreateRequest)(nil)),
+			HandlerFunc: volumeCreateHandler},
+		route.Route{
+			Name:         "HeketiVolumeList",
+			Method:       "GET",
+			Pattern:      "/heketi/volumes",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*heketiapi.VolumeListResponse)(nil)),
+			HandlerFunc:  volumeListHandler},
+		route.Route{
+			Name:         "HeketiVolumeInfo",
+			Method:       "GET",
+			Pattern:      "/heketi/volumes/{id}",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*heketiapi.VolumeInfoResponse)(nil)),
+			HandlerFunc:  volumeInfoHandler},
+		route.Route{
+			Name:        "HeketiVolumeExpand",
+			Method:      "POST",
+			Pattern:     "/heketi/volumes/{id}/expand",
+			Version:     1,
+			RequestType: utils.GetTypeString((*heketiapi.VolumeExpandRequest)(nil)),
+			HandlerFunc: volumeExpandHandler},
+		// Heketi users which are not admins can delete volumes too
+		route.Route{
+			Name:        "HeketiVolumeDelete",
+			Method:      "DELETE",
+			Pattern:     "/heketi/volumes/{id}",
+			Version:     1,
+			HandlerFunc: volumeDeleteHandler,
+			Role:        api.RoleOperator},
+	}
+}
+
+// RegisterStepFuncs registers transaction step functions with
+// Glusterd Transaction framework
+func (p *Plugin) RegisterStepFuncs() {
+}
@@ -0,0 +1,222 @@
+package heketi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/commands/peers"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/plugins/device/deviceutils"
+	heketiapi "github.com/gluster/glusterd2/plugins/heketi/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+// Nodes are the peers of the cluster. Heketi zones are numbers, so peers
+// whose zone is not a number are in zone 1.
+
+// heketiZone returns the Heketi zone of the peer
+func heketiZone(p *peer.Peer) int {
+	if zone, err := strconv.Atoi(p.Zone); err == nil && zone > 0 {
+		return zone
+	}
+	return 1
+}
+
+// getNode returns the peer with the Heketi ID, and the HTTP status code to
+// respond with if it can't be found
+func getNode(id string) (*peer.Peer, int, error) {
+	peerID := parseID(id)
+	if peerID == nil {
+		return nil, http.StatusNotFound, errNodeNotFound
+	}
+	p, err := peer.GetPeer(peerID.String())
+	if err == gderrors.ErrPeerNotFound {
+		return nil, http.StatusNotFound, errNodeNotFound
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return p, http.StatusOK, nil
+}
+
+func nodeInfo(p *peer.Peer, bricks []brick.Brickinfo) (*heketiapi.NodeInfoResponse, error) {
+	devices, err := deviceutils.GetDevices(p.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &heketiapi.NodeInfoResponse{
+		NodeAddRequest: heketiapi.NodeAddRequest{
+			Zone: heketiZone(p),
+			Hostnames: heketiapi.HostAddresses{
+				Manage:  hostsOf(p.PeerAddresses),
+				Storage: hostsOf(p.ClientAddresses),
+			},
+			ClusterID: toID(gdctx.MyClusterID),
+			Tags:      p.Tags,
+		},
+		ID:          toID(p.ID),
+		State:       heketiapi.EntryStateOnline,
+		DevicesInfo: []heketiapi.DeviceInfoResponse{},
+	}
+	if p.InMaintenance() {
+		resp.State = heketiapi.EntryStateOffline
+	}
+	for i := range devices {
+		resp.DevicesInfo = append(resp.DevicesInfo, *deviceInfo(&devices[i], bricks))
+	}
+	return resp, nil
+}
+
+func nodeAddHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req heketiapi.NodeAddRequest
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		sendError(w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+	if !isCluster(req.ClusterID) {
+		sendError(w, http.StatusNotFound, errClusterNotFound)
+		return
+	}
+	if len(req.Hostnames.Manage) == 0 {
+		sendError(w, http.StatusBadRequest, errManageHostRequired)
+		return
+	}
+
+	peerReq := &api.PeerAddReq{
+		Addresses: req.Hostnames.Manage,
+		Tags:      req.Tags,
+	}
+	if req.Zone > 0 {
+		peerReq.Zone = strconv.Itoa(req.Zone)
+	}
+
+	sendAsync(ctx, w, "", func(ctx context.Context) (string, error) {
+		p, _, err := peercommands.AddPeer(ctx, peerReq)
+		if err != nil {
+			return "", err
+		}
+		return "/nodes/" + toID(p.ID), nil
+	})
+}
+
+func nodeInfoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p, status, err := getNode(mux.Vars(r)["id"])
+	if err != nil {
+		sendError(w, status, err)
+		return
+	}
+
+	bricks, err := volume.GetAllBricksInCluster()
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, err)
+		return
+	}
+	resp, err := nodeInfo(p, bricks)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// nodeDeleteHandler removes the peer from the cluster. Like with Heketi, its
+// devices must have been deleted first.
+func nodeDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p, status, err := getNode(mux.Vars(r)["id"])
+	if err != nil {
+		sendError(w, status, err)
+		return
+	}
+	if uuid.Equal(p.ID, gdctx.MyUUID) {
+		sendError(w, http.StatusConflict, errSelfDeleteNotAllowed)
+		return
+	}
+	if _, alive := store.Store.IsNodeAlive(p.ID); !alive {
+		sendError(w, http.StatusInternalServerError, errNodeNotAlive)
+		return
+	}
+
+	devices, err := deviceutils.GetDevices(p.ID.String())
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if len(devices) > 0 {
+		sendError(w, http.StatusConflict, errNodeHasDevices)
+		return
+	}
+	// Bricks not provisioned from devices
+	bricks, err := peercommands.PeerBricks(ctx, p.ID)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if len(bricks) > 0 {
+		sendError(w, http.StatusConflict, errNodeHasBricks)
+		return
+	}
+
+	sendAsync(ctx, w, "", func(ctx context.Context) (string, error) {
+		_, err := peercommands.DetachPeer(ctx, p)
+		return "", err
+	})
+}
+
+// nodeStateHandler puts the peer under maintenance when it is set offline,
+// and ends its maintenance when it is set online
+func nodeStateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p, status, err := getNode(mux.Vars(r)["id"])
+	if err != nil {
+		sendError(w, status, err)
+		return
+	}
+
+	var req heketiapi.StateRequest
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		sendError(w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	var maintenance bool
+	switch req.State {
+	case heketiapi.EntryStateOnline:
+	case heketiapi.EntryStateOffline:
+		maintenance = true
+	case heketiapi.EntryStateFailed:
+		sendError(w, http.StatusBadRequest, errFailedNotSupported)
+		return
+	default:
+		sendError(w, http.StatusBadRequest, errInvalidState)
+		return
+	}
+
+	sendAsync(ctx, w, "", func(ctx context.Context) (string, error) {
+		current, err := peer.GetPeer(p.ID.String())
+		if err != nil {
+			return "", err
+		}
+		if current.InMaintenance() == maintenance {
+			return "", nil
+		}
+		_, _, err = peercommands.SetPeerMaintenance(ctx, p.ID.String(), maintenance, "set offline through the heketi api")
+		return "", err
+	})
+}
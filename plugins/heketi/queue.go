@@ -0,0 +1,114 @@
+package heketi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/jobs"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+// basePath is the path of the Heketi compatible API. Heketi clients are
+// given the URL of glusterd2 with this path as the URL of the Heketi server.
+const basePath = "/v1/heketi"
+
+// queueResult is the result of a job serving an asynchronous request. It
+// has the path of the resource created or changed by the request, relative
+// to basePath, if there is one to redirect the client to.
+type queueResult struct {
+	Location string `json:"location,omitempty"`
+}
+
+// asyncOp is an operation run in the background for an asynchronous
+// request. It returns the path of the resource it created or changed,
+// relative to basePath, or an empty path if there is none.
+type asyncOp func(ctx context.Context) (string, error)
+
+// sendError sends an error response. Heketi clients show the body of error
+// responses as the error message, so it is sent as plain text.
+func sendError(w http.ResponseWriter, status int, err error) {
+	http.Error(w, err.Error(), status)
+}
+
+// helloHandler answers the requests Heketi clients make to check that the
+// server is up
+func helloHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Hello from Heketi"))
+}
+
+// sendAsync runs op in a job and sends the 202 Accepted response Heketi
+// clients expect, with the URL of the job in the queue in its Location
+// header. The clients poll it until the job ends.
+func sendAsync(ctx context.Context, w http.ResponseWriter, volname string, op asyncOp) {
+	// Jobs keep the user of the request, but not the namespaces it is
+	// bound to
+	namespaces := gdctx.GetReqNamespaces(ctx)
+	job, err := jobs.Submit(ctx, api.JobTypeHeketi, volname, func(ctx context.Context, h *jobs.Handle) error {
+		location, err := op(gdctx.WithReqNamespaces(ctx, namespaces))
+		if err != nil {
+			return err
+		}
+		return h.SetResult(queueResult{Location: location})
+	})
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Location", basePath+"/queue/"+job.ID.String())
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// queueHandler serves the state of a job like Heketi serves the state of
+// the operations in its queue: 200 with the X-Pending header while it runs,
+// then a redirect to the resource created or changed, or 204 if there is
+// none. A failed job is served as a 500 with the error of the job.
+func queueHandler(w http.ResponseWriter, r *http.Request) {
+	id := uuid.Parse(mux.Vars(r)["id"])
+	if id == nil {
+		sendError(w, http.StatusNotFound, gderrors.ErrJobNotFound)
+		return
+	}
+
+	job, err := jobs.GetJob(id)
+	if err == gderrors.ErrJobNotFound {
+		sendError(w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		sendError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if !job.Ended() {
+		w.Header().Set("X-Pending", "true")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if job.State == api.JobFailed {
+		sendError(w, http.StatusInternalServerError, errors.New(job.Error))
+		return
+	}
+
+	var result queueResult
+	if len(job.Result) > 0 {
+		if err := json.Unmarshal(job.Result, &result); err != nil {
+			sendError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	if result.Location == "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Location", basePath+result.Location)
+	w.WriteHeader(http.StatusSeeOther)
+}
@@ -0,0 +1,376 @@
+package heketi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gluster/glusterd2/glusterd2/commands/volumes"
+	"github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/utils"
+	heketiapi "github.com/gluster/glusterd2/plugins/heketi/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+const (
+	// ownerGidOption sets the group owning the root of the bricks, which
+	// is the gid of Heketi volumes
+	ownerGidOption = "storage.owner-gid"
+
+	// Defaults of Heketi for the layouts of volumes
+	defaultReplica            = 3
+	defaultDisperseData       = 4
+	defaultDisperseRedundancy = 2
+	// defaultSnapshotFactor is the default ratio of the size of the thin
+	// pools of the bricks to the size of the volume
+	defaultSnapshotFactor = 1.5
+)
+
+// newVolCreateReq returns the request to create the volume asked for by the
+// Heketi request. Volumes are named vol_<random ID> unless the request has
+// a name.
+func newVolCreateReq(req *heketiapi.VolumeCreateRequest) (*api.VolCreateReq, error) {
+	if req.Size < 1 {
+		return nil, errInvalidSize
+	}
+
+	createReq := &api.VolCreateReq{
+		Name:        req.Name,
+		Size:        uint64(req.Size) * utils.GiB,
+		StartVolume: true,
+		VolOptionReq: api.VolOptionReq{
+			Options: make(map[string]string),
+		},
+	}
+	if createReq.Name == "" {
+		createReq.Name = "vol_" + toID(uuid.NewRandom())
+	}
+
+	switch req.Durability.Type {
+	case "", heketiapi.DurabilityNone:
+	case heketiapi.DurabilityReplicate:
+		createReq.ReplicaCount = req.Durability.Replicate.Replica
+		if createReq.ReplicaCount == 0 {
+			createReq.ReplicaCount = defaultReplica
+		}
+	case heketiapi.DurabilityDisperse:
+		createReq.DisperseDataCount = req.Durability.Disperse.Data
+		if createReq.DisperseDataCount == 0 {
+			createReq.DisperseDataCount = defaultDisperseData
+		}
+		createReq.DisperseRedundancyCount = req.Durability.Disperse.Redundancy
+		if createReq.DisperseRedundancyCount == 0 {
+			createReq.DisperseRedundancyCount = defaultDisperseRedundancy
+		}
+	default:
+		return nil, errInvalidDurability
+	}
+
+	for _, opt := range req.GlusterVolumeOptions {
+		kv := strings.SplitN(strings.TrimSpace(opt), " ", 2)
+		if len(kv) != 2 || kv[0] == "" || strings.TrimSpace(kv[1]) == "" {
+			return nil, errInvalidVolumeOption
+		}
+		createReq.Options[kv[0]] = strings.TrimSpace(kv[1])
+	}
+	if req.Gid != 0 {
+		createReq.Options[ownerGidOption] = strconv.FormatInt(req.Gid, 10)
+	}
+
+	if req.Snapshot.Enable {
+		createReq.SnapshotEnabled = true
+		createReq.SnapshotReserveFactor = float64(req.Snapshot.Factor)
+		if createReq.SnapshotReserveFactor == 0 {
+			createReq.SnapshotReserveFactor = defaultSnapshotFactor
+		}
+	}
+
+	return createReq, nil
+}
+
+// durability returns the Heketi layout of the volume
+func durability(v *volume.Volinfo) heketiapi.VolumeDurabilityInfo {
+	d := heketiapi.VolumeDurabilityInfo{Type: heketiapi.DurabilityNone}
+	if len(v.Subvols) == 0 {
+		return d
+	}
+
+	sv := v.Subvols[0]
+	switch sv.Type {
+	case volume.SubvolReplicate:
+		d.Type = heketiapi.DurabilityReplicate
+		d.Replicate.Replica = sv.ReplicaCount
+	case volume.SubvolDisperse:
+		d.Type = heketiapi.DurabilityDisperse
+		d.Disperse.Data = sv.DisperseCount - sv.RedundancyCount
+		d.Disperse.Redundancy = sv.RedundancyCount
+	}
+	return d
+}
+
+// volumeInfo returns the Heketi volume of the volume. It is mounted from the
+// client address of one of the peers with its bricks, with the others as
+// backup volfile servers.
+func volumeInfo(v *volume.Volinfo) (*heketiapi.VolumeInfoResponse, error) {
+	resp := &heketiapi.VolumeInfoResponse{
+		VolumeCreateRequest: heketiapi.VolumeCreateRequest{
+			Size:                 int(v.Capacity / utils.GiB),
+			Clusters:             []string{toID(gdctx.MyClusterID)},
+			Name:                 v.Name,
+			Durability:           durability(v),
+			GlusterVolumeOptions: []string{},
+		},
+		ID:      toID(v.ID),
+		Cluster: toID(gdctx.MyClusterID),
+		Bricks:  []heketiapi.BrickInfo{},
+	}
+
+	for k, val := range v.Options {
+		resp.GlusterVolumeOptions = append(resp.GlusterVolumeOptions, k+" "+val)
+	}
+	sort.Strings(resp.GlusterVolumeOptions)
+	if gid, err := strconv.ParseInt(v.Options[ownerGidOption], 10, 64); err == nil {
+		resp.Gid = gid
+	}
+	if v.SnapshotReserveFactor > 1 {
+		resp.Snapshot = heketiapi.VolumeSnapshot{
+			Enable: true,
+			Factor: float32(v.SnapshotReserveFactor),
+		}
+	}
+
+	var hosts []string
+	for _, id := range v.Nodes() {
+		p, err := peer.GetPeer(id.String())
+		if err != nil {
+			return nil, err
+		}
+		if len(p.ClientAddresses) > 0 {
+			hosts = append(hosts, hostOf(p.ClientAddresses[0]))
+		}
+	}
+	sort.Strings(hosts)
+	resp.Mount.GlusterFS.Hosts = hosts
+	resp.Mount.GlusterFS.Options = map[string]string{}
+	if len(hosts) > 0 {
+		resp.Mount.GlusterFS.MountPoint = hosts[0] + ":" + v.Name
+		if len(hosts) > 1 {
+			resp.Mount.GlusterFS.Options["backup-volfile-servers"] = strings.Join(hosts[1:], ",")
+		}
+	}
+
+	bricks := v.GetBricks()
+	for i := range bricks {
+		resp.Bricks = append(resp.Bricks, brickInfo(&bricks[i]))
+	}
+	return resp, nil
+}
+
+// getVolume returns the volume with the Heketi ID, and the HTTP status code
+// to respond with if it can't be found
+func getVolume(ctx context.Context, id string) (*volume.Volinfo, int, error) {
+	volID := parseID(id)
+	if volID == nil {
+		return nil, http.StatusNotFound, errVolumeNotFound
+	}
+
+	vols, err := volume.GetVolumes(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	for _, v := range vols {
+		if uuid.Equal(v.ID, volID) && gdctx.ReqNamespaceAllowed(ctx, v.GetNamespace()) {
+			return v, http.StatusOK, nil
+		}
+	}
+	return nil, http.StatusNotFound, errVolumeNotFound
+}
+
+func volumeCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req heketiapi.VolumeCreateRequest
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		sendError(w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+
+	if req.Block {
+		sendError(w, http.StatusBadRequest, errBlockNotSupported)
+		return
+	}
+	if len(req.Clusters) > 0 {
+		found := false
+		for _, id := range req.Clusters {
+			found = found || isCluster(id)
+		}
+		if !found {
+			sendError(w, http.StatusNotFound, errClusterNotFound)
+			return
+		}
+	}
+
+	createReq, err := newVolCreateReq(&req)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+	if volume.Exists(createReq.Name) {
+		sendError(w, http.StatusConflict, gderrors.ErrVolExists)
+		return
+	}
+
+	// Fail right away if the bricks of the volume can't be planned,
+	// instead of in the job
+	validateReq := *createReq
+	validateReq.Options = make(map[string]string)
+	for k, v := range createReq.Options {
+		validateReq.Options[k] = v
+	}
+	if status, err := volumecommands.ValidateVolumeCreate(ctx, &validateReq); err != nil {
+		sendError(w, status, err)
+		return
+	}
+
+	sendAsync(ctx, w, createReq.Name, func(ctx context.Context) (string, error) {
+		if _, err := volumecommands.CreateVolume(ctx, *createReq); err != nil {
+			return "", err
+		}
+
+		v, err := volume.GetVolume(createReq.Name)
+		if err != nil {
+			return "", err
+		}
+		events.Broadcast(volume.NewEvent(volume.EventVolumeCreated, v))
+		events.Broadcast(volume.NewEvent(volume.EventVolumeStarted, v))
+
+		return "/volumes/" + toID(v.ID), nil
+	})
+}
+
+func volumeListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	vols, err := volume.GetVolumes(ctx)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := &heketiapi.VolumeListResponse{Volumes: []string{}}
+	for _, v := range vols {
+		if gdctx.ReqNamespaceAllowed(ctx, v.GetNamespace()) {
+			resp.Volumes = append(resp.Volumes, toID(v.ID))
+		}
+	}
+	sort.Strings(resp.Volumes)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func volumeInfoHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	v, status, err := getVolume(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		sendError(w, status, err)
+		return
+	}
+
+	resp, err := volumeInfo(v)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, err)
+		return
+	}
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// volumeExpandHandler expands the bricks of the volume, instead of adding
+// bricks to it like Heketi does
+func volumeExpandHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	v, status, err := getVolume(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		sendError(w, status, err)
+		return
+	}
+
+	var req heketiapi.VolumeExpandRequest
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		sendError(w, http.StatusBadRequest, gderrors.ErrJSONParsingFailed)
+		return
+	}
+	if req.Size < 1 {
+		sendError(w, http.StatusBadRequest, errInvalidSize)
+		return
+	}
+
+	lvm := v.ProvisionerType == "" || v.ProvisionerType == api.ProvisionerTypeLvm
+	if !lvm || !v.IsAutoProvisioned() {
+		sendError(w, http.StatusBadRequest, errExpandNotSupported)
+		return
+	}
+
+	// Expanding a volume by the size of all of its subvolumes resizes its
+	// bricks instead of adding new ones
+	expandReq := api.VolExpandReq{
+		Size:            uint64(req.Size) * utils.GiB,
+		DistributeCount: len(v.Subvols),
+	}
+	volname := v.Name
+	sendAsync(ctx, w, volname, func(ctx context.Context) (string, error) {
+		v, _, _, err := volumecommands.ExpandVolume(ctx, volname, expandReq, nil)
+		if err != nil {
+			return "", err
+		}
+		events.Broadcast(volume.NewEvent(volume.EventVolumeExpanded, v))
+		return "/volumes/" + toID(v.ID), nil
+	})
+}
+
+// volumeDeleteHandler stops and deletes the volume
+func volumeDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	v, status, err := getVolume(ctx, mux.Vars(r)["id"])
+	if err != nil {
+		sendError(w, status, err)
+		return
+	}
+
+	if v.IsDeletionProtected() {
+		sendError(w, http.StatusForbidden, gderrors.ErrVolDeletionProtected)
+		return
+	}
+	if len(v.SnapList) > 0 {
+		sendError(w, http.StatusConflict, errVolumeHasSnapshots)
+		return
+	}
+
+	volname := v.Name
+	sendAsync(ctx, w, volname, func(ctx context.Context) (string, error) {
+		if v.State == volume.VolStarted {
+			v, _, err := volumecommands.StopVolume(ctx, volname, false, 0)
+			if err != nil && err != gderrors.ErrVolAlreadyStopped {
+				return "", fmt.Errorf("failed to stop volume: %s", err)
+			}
+			if err == nil {
+				events.Broadcast(volume.NewEvent(volume.EventVolumeStopped, v))
+			}
+		}
+
+		_, err := volumecommands.DeleteVolume(ctx, volname)
+		return "", err
+	})
+}
@@ -0,0 +1,67 @@
+package heketi
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/pkg/utils"
+	heketiapi "github.com/gluster/glusterd2/plugins/heketi/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewVolCreateReq(t *testing.T) {
+	req, err := newVolCreateReq(&heketiapi.VolumeCreateRequest{Size: 2})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2*utils.GiB), req.Size)
+	assert.Regexp(t, "^vol_[0-9a-f]{32}$", req.Name)
+	assert.True(t, req.StartVolume)
+	assert.Zero(t, req.ReplicaCount)
+	assert.Zero(t, req.DisperseDataCount)
+	assert.False(t, req.SnapshotEnabled)
+
+	req, err = newVolCreateReq(&heketiapi.VolumeCreateRequest{
+		Size:                 1,
+		Name:                 "myvol",
+		Durability:           heketiapi.VolumeDurabilityInfo{Type: heketiapi.DurabilityReplicate},
+		Gid:                  2000,
+		GlusterVolumeOptions: []string{"performance.read-ahead off", " cluster.lookup-optimize  on "},
+		Snapshot:             heketiapi.VolumeSnapshot{Enable: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "myvol", req.Name)
+	assert.Equal(t, defaultReplica, req.ReplicaCount)
+	assert.Equal(t, map[string]string{
+		"performance.read-ahead":  "off",
+		"cluster.lookup-optimize": "on",
+		ownerGidOption:            "2000",
+	}, req.Options)
+	assert.True(t, req.SnapshotEnabled)
+	assert.Equal(t, defaultSnapshotFactor, req.SnapshotReserveFactor)
+
+	durability := heketiapi.VolumeDurabilityInfo{Type: heketiapi.DurabilityDisperse}
+	durability.Disperse.Data = 8
+	req, err = newVolCreateReq(&heketiapi.VolumeCreateRequest{Size: 1, Durability: durability})
+	require.NoError(t, err)
+	assert.Equal(t, 8, req.DisperseDataCount)
+	assert.Equal(t, defaultDisperseRedundancy, req.DisperseRedundancyCount)
+}
+
+func TestNewVolCreateReqInvalid(t *testing.T) {
+	_, err := newVolCreateReq(&heketiapi.VolumeCreateRequest{})
+	assert.Equal(t, errInvalidSize, err)
+
+	_, err = newVolCreateReq(&heketiapi.VolumeCreateRequest{
+		Size:       1,
+		Durability: heketiapi.VolumeDurabilityInfo{Type: "mirror"},
+	})
+	assert.Equal(t, errInvalidDurability, err)
+
+	for _, opt := range []string{"", "performance.read-ahead", "performance.read-ahead  "} {
+		_, err = newVolCreateReq(&heketiapi.VolumeCreateRequest{
+			Size:                 1,
+			GlusterVolumeOptions: []string{opt},
+		})
+		assert.Equal(t, errInvalidVolumeOption, err, opt)
+	}
+}
@@ -1,10 +1,14 @@
 package api
 
-// SetLimitReq represents REST API request to Limit Usage/objects of a directory
+// SetLimitReq represents REST API request to Limit Usage/objects of a directory.
+// SizeUsageLimit is in bytes. SoftLimitPercent is the percentage of the limit
+// after which the usage is reported as exceeding the soft limit, the
+// default-soft-limit of the volume is used if it isn't given.
 type SetLimitReq struct {
 	Path             string `json:"path"`
-	SizeUsageLimit   int    `json:"size-usage-limit,omitempty"`
-	ObjectCountLimit int    `json:"object-count-limit,omitempty"`
+	SizeUsageLimit   uint64 `json:"size-usage-limit,omitempty"`
+	SoftLimitPercent int    `json:"soft-limit-percent,omitempty"`
+	ObjectCountLimit uint64 `json:"object-count-limit,omitempty"`
 }
 
 // RemoveLimitReq represents REST API request to Remove Usage/objects of a directory
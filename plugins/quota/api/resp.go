@@ -5,24 +5,52 @@ type crawlInfo struct {
 	MountPid int `json:"crawl-mount-pid"`
 }
 
-type list struct {
+// Limit represents the limits set on a directory
+type Limit struct {
+	Path             string `json:"path"`
+	SizeUsageLimit   uint64 `json:"size-usage-limit,omitempty"`
+	SoftLimitPercent int    `json:"soft-limit-percent,omitempty"`
+	ObjectCountLimit uint64 `json:"object-count-limit,omitempty"`
+}
+
+// LimitInfo represents the usage of a directory against its size limit.
+// Error is set if the usage couldn't be read.
+type LimitInfo struct {
 	Path      string `json:"path"`
-	HardLimit int64  `json:"hard-limit"`
-	SoftLimit int64  `json:"soft-limit"`
-	Used      int64  `json:"used"`
-	Available int64  `json:"available"`
+	HardLimit uint64 `json:"hard-limit"`
+	SoftLimit uint64 `json:"soft-limit"`
+	Used      uint64 `json:"used"`
+	Available uint64 `json:"available"`
 	/*
 	 * TODO / Review: "Merge soft limit exceeded and hard limit
 	 * exceeded" flags into a single field called status.
 	 */
-	SoftLimitExceeded bool `json:"soft-limit-exceeded"`
-	HardLimitExceeded bool `json:"hard-limit-exceeded"`
-	// LimitType indicates directory quota/inode-quota/...
-	LimitType int32 `json:"limit-type"`
+	SoftLimitExceeded bool   `json:"soft-limit-exceeded"`
+	HardLimitExceeded bool   `json:"hard-limit-exceeded"`
+	Error             string `json:"error,omitempty"`
 }
 
+// ObjectLimitInfo represents the number of files and directories under a
+// directory against its object count limit. Error is set if the counts
+// couldn't be read.
+type ObjectLimitInfo struct {
+	Path              string `json:"path"`
+	HardLimit         uint64 `json:"hard-limit"`
+	SoftLimit         uint64 `json:"soft-limit"`
+	Files             uint64 `json:"files"`
+	Dirs              uint64 `json:"dirs"`
+	Used              uint64 `json:"used"`
+	Available         uint64 `json:"available"`
+	SoftLimitExceeded bool   `json:"soft-limit-exceeded"`
+	HardLimitExceeded bool   `json:"hard-limit-exceeded"`
+	Error             string `json:"error,omitempty"`
+}
+
+// ObjectListResp is an array of the object count limits of directories
+type ObjectListResp []ObjectLimitInfo
+
 //ListResp is an array of structs representing individual limits.
-type ListResp []list
+type ListResp []LimitInfo
 
 //DisableResp gives the information of disable crawler on success
 type DisableResp crawlInfo
@@ -0,0 +1,14 @@
+package quota
+
+import (
+	"errors"
+)
+
+var (
+	errInvalidLimitPath = errors.New("path must be an absolute path of a directory of the volume")
+	errNoLimit          = errors.New("at least one of size-usage-limit and object-count-limit is required")
+	errInvalidSoftLimit = errors.New("soft-limit-percent must be between 1 and 100")
+	errLimitNotFound    = errors.New("no limit set on the path")
+	errNotDirectory     = errors.New("limits can only be set on directories")
+	errQuotaEnabled     = errors.New("quota is already enabled")
+)
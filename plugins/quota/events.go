@@ -0,0 +1,41 @@
+package quota
+
+import (
+	gd2events "github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// deleteLimitsF can be replaced in tests
+var deleteLimitsF = deleteLimits
+
+// limitsCleaner removes the limits of deleted volumes from the store. Volume
+// events are global, so only the peer which deleted the volume removes them.
+type limitsCleaner struct{}
+
+func (c *limitsCleaner) Handle(e *api.Event) {
+	if !uuid.Equal(e.Origin, gdctx.MyUUID) {
+		return
+	}
+
+	volname := e.Data["volume.name"]
+	if volname == "" {
+		return
+	}
+
+	if err := deleteLimitsF(volname); err != nil {
+		log.WithError(err).WithField("volume", volname).Warn("failed to delete quota limits")
+	}
+}
+
+func (c *limitsCleaner) Events() []string {
+	return []string{string(volume.EventVolumeDeleted)}
+}
+
+func init() {
+	gd2events.Register(new(limitsCleaner))
+}
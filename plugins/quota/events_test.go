@@ -0,0 +1,30 @@
+package quota
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/testutils"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitsCleaner(t *testing.T) {
+	defer func(id uuid.UUID) { gdctx.MyUUID = id }(gdctx.MyUUID)
+	gdctx.MyUUID = uuid.NewRandom()
+
+	var deleted []string
+	defer testutils.Patch(&deleteLimitsF, func(volname string) error {
+		deleted = append(deleted, volname)
+		return nil
+	}).Restore()
+
+	c := new(limitsCleaner)
+	c.Handle(&api.Event{Origin: gdctx.MyUUID, Data: map[string]string{"volume.name": "vol1"}})
+	// Only the peer which deleted the volume removes its limits
+	c.Handle(&api.Event{Origin: uuid.NewRandom(), Data: map[string]string{"volume.name": "vol2"}})
+
+	assert.Equal(t, []string{"vol1"}, deleted)
+}
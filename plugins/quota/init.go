@@ -2,6 +2,8 @@ package quota
 
 import (
 	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/pkg/utils"
+	quotaapi "github.com/gluster/glusterd2/plugins/quota/api"
 )
 
 const name = "quota"
@@ -19,22 +21,45 @@ func (p *Plugin) Name() string {
 func (p *Plugin) RestRoutes() route.Routes {
 	return route.Routes{
 		route.Route{
-			Name:        "QuotaList",
-			Method:      "GET",
-			Pattern:     "/quota/{volname}/limit",
+			Name:        "QuotaEnable",
+			Method:      "POST",
+			Pattern:     "/volumes/{volname}/quota/enable",
 			Version:     1,
-			HandlerFunc: quotaListHandler},
+			HandlerFunc: quotaEnableHandler},
 		route.Route{
-			Name:        "QuotaLimit",
+			Name:        "QuotaDisable",
 			Method:      "POST",
-			Pattern:     "/quota/{volname}/limit",
+			Pattern:     "/volumes/{volname}/quota/disable",
 			Version:     1,
-			HandlerFunc: quotaLimitHandler},
+			HandlerFunc: quotaDisableHandler},
+		route.Route{
+			Name:         "QuotaList",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/quota/limits",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*quotaapi.ListResp)(nil)),
+			HandlerFunc:  quotaListHandler},
+		route.Route{
+			Name:         "QuotaObjectList",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/quota/objects",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*quotaapi.ObjectListResp)(nil)),
+			HandlerFunc:  quotaObjectListHandler},
+		route.Route{
+			Name:         "QuotaLimit",
+			Method:       "POST",
+			Pattern:      "/volumes/{volname}/quota/limits",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*quotaapi.SetLimitReq)(nil)),
+			ResponseType: utils.GetTypeString((*quotaapi.Limit)(nil)),
+			HandlerFunc:  quotaLimitHandler},
 		route.Route{
 			Name:        "QuotaRemove",
 			Method:      "DELETE",
-			Pattern:     "/quota/{volname}/limit",
+			Pattern:     "/volumes/{volname}/quota/limits",
 			Version:     1,
+			RequestType: utils.GetTypeString((*quotaapi.RemoveLimitReq)(nil)),
 			HandlerFunc: quotaRemoveHandler},
 	}
 }
@@ -0,0 +1,301 @@
+package quota
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	quotaapi "github.com/gluster/glusterd2/plugins/quota/api"
+
+	"github.com/coreos/etcd/clientv3"
+	log "github.com/sirupsen/logrus"
+	config "github.com/spf13/viper"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	quotaLimitsPrefix = "quota-limits/"
+
+	// Extended attributes handled by the quota xlators
+	limitSetXattrKey     = "trusted.glusterfs.quota.limit-set"
+	limitObjectsXattrKey = "trusted.glusterfs.quota.limit-objects"
+	sizeXattrKey         = "trusted.glusterfs.quota.size"
+
+	// quotaMounterPid is the client pid which lets the quota xattrs be
+	// set and read through a mount
+	quotaMounterPid = "-5"
+
+	defaultSoftLimitKey     = "quota.default-soft-limit"
+	defaultSoftLimitPercent = 80
+)
+
+func limitKey(volname, dir string) string {
+	return quotaLimitsPrefix + volname + "/" + url.QueryEscape(dir)
+}
+
+// cleanLimitPath returns the path of the directory relative to the root of
+// the volume in its canonical form
+func cleanLimitPath(p string) (string, error) {
+	if !strings.HasPrefix(p, "/") {
+		return "", errInvalidLimitPath
+	}
+	return path.Clean(p), nil
+}
+
+// getLimits returns the limits set on the directories of the volume
+func getLimits(volname string) ([]quotaapi.Limit, error) {
+	resp, err := store.Get(context.TODO(), quotaLimitsPrefix+volname+"/", clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	limits := make([]quotaapi.Limit, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var l quotaapi.Limit
+		if err := json.Unmarshal(kv.Value, &l); err != nil {
+			log.WithError(err).WithField("key", string(kv.Key)).Error("Failed to unmarshal quota limit")
+			continue
+		}
+		limits = append(limits, l)
+	}
+	return limits, nil
+}
+
+func getLimit(volname, dir string) (*quotaapi.Limit, error) {
+	resp, err := store.Get(context.TODO(), limitKey(volname, dir))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Count != 1 {
+		return nil, errLimitNotFound
+	}
+
+	var l quotaapi.Limit
+	if err := json.Unmarshal(resp.Kvs[0].Value, &l); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func saveLimit(volname string, l *quotaapi.Limit) error {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(context.TODO(), limitKey(volname, l.Path), string(data))
+	return err
+}
+
+func deleteLimit(volname, dir string) error {
+	_, err := store.Delete(context.TODO(), limitKey(volname, dir))
+	return err
+}
+
+// deleteLimits removes the limits of the directories of the given volume
+func deleteLimits(volname string) error {
+	_, err := store.Delete(context.TODO(), quotaLimitsPrefix+volname+"/", clientv3.WithPrefix())
+	return err
+}
+
+// newLimit validates the request to set a limit on a directory and returns
+// the limit
+func newLimit(req *quotaapi.SetLimitReq) (*quotaapi.Limit, error) {
+	dir, err := cleanLimitPath(req.Path)
+	if err != nil {
+		return nil, err
+	}
+	if req.SizeUsageLimit == 0 && req.ObjectCountLimit == 0 {
+		return nil, errNoLimit
+	}
+	if req.SoftLimitPercent < 0 || req.SoftLimitPercent > 100 {
+		return nil, errInvalidSoftLimit
+	}
+
+	return &quotaapi.Limit{
+		Path:             dir,
+		SizeUsageLimit:   req.SizeUsageLimit,
+		SoftLimitPercent: req.SoftLimitPercent,
+		ObjectCountLimit: req.ObjectCountLimit,
+	}, nil
+}
+
+// defaultSoftLimit returns the default-soft-limit percentage of the volume,
+// set as "80" or "80%"
+func defaultSoftLimit(v *volume.Volinfo) int {
+	val, ok := v.Options[defaultSoftLimitKey]
+	if !ok {
+		return defaultSoftLimitPercent
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(val, "%"))
+	if err != nil {
+		return defaultSoftLimitPercent
+	}
+	return percent
+}
+
+// quotaMount mounts the volume on a temporary directory with the client pid
+// of the quota mounter. The returned function unmounts it.
+func quotaMount(volname string) (string, func(), error) {
+	mountDir, err := ioutil.TempDir(config.GetString("rundir"), "gd2quota")
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := volume.MountVolume(volname, mountDir, " --client-pid="+quotaMounterPid); err != nil {
+		os.Remove(mountDir)
+		return "", nil, err
+	}
+
+	return mountDir, func() {
+		syscall.Unmount(mountDir, syscall.MNT_FORCE)
+		os.Remove(mountDir)
+	}, nil
+}
+
+// encodeLimit encodes a limit the way the quota xlators expect it: the hard
+// limit and the soft limit percentage as big-endian int64s. A soft limit of
+// -1 makes the xlators use the default-soft-limit of the volume.
+func encodeLimit(hard uint64, softPercent int) []byte {
+	buf := make([]byte, 16)
+	binary.BigEndian.PutUint64(buf[:8], hard)
+	soft := int64(-1)
+	if softPercent > 0 {
+		soft = int64(softPercent)
+	}
+	binary.BigEndian.PutUint64(buf[8:], uint64(soft))
+	return buf
+}
+
+// setLimitXattrs sets or removes the limits on the directory through the
+// mount of the volume
+func setLimitXattrs(mountDir string, l *quotaapi.Limit) error {
+	dir := filepath.Join(mountDir, l.Path)
+
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return errNotDirectory
+	}
+
+	if l.SizeUsageLimit > 0 {
+		if err := unix.Setxattr(dir, limitSetXattrKey, encodeLimit(l.SizeUsageLimit, l.SoftLimitPercent), 0); err != nil {
+			return err
+		}
+	} else if err := removeXattr(dir, limitSetXattrKey); err != nil {
+		return err
+	}
+
+	if l.ObjectCountLimit > 0 {
+		return unix.Setxattr(dir, limitObjectsXattrKey, encodeLimit(l.ObjectCountLimit, l.SoftLimitPercent), 0)
+	}
+	return removeXattr(dir, limitObjectsXattrKey)
+}
+
+// removeLimitXattrs removes the limits on the directory through the mount
+// of the volume. A directory which no longer exists has no limits.
+func removeLimitXattrs(mountDir, p string) error {
+	dir := filepath.Join(mountDir, p)
+	if err := removeXattr(dir, limitSetXattrKey); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := removeXattr(dir, limitObjectsXattrKey); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func removeXattr(p, key string) error {
+	err := unix.Removexattr(p, key)
+	if err == unix.ENODATA {
+		return nil
+	}
+	if err == unix.ENOENT {
+		return os.ErrNotExist
+	}
+	return err
+}
+
+// quotaSize is the usage of a directory as accounted by the quota xlators
+type quotaSize struct {
+	size  uint64
+	files uint64
+	dirs  uint64
+}
+
+// getQuotaSize reads the usage of the directory through the mount of the
+// volume. The value is the size, file count and dir count as big-endian
+// int64s; older versions of the xlators only have the size.
+func getQuotaSize(mountDir, p string) (quotaSize, error) {
+	var s quotaSize
+
+	buf := make([]byte, 24)
+	n, err := unix.Getxattr(filepath.Join(mountDir, p), sizeXattrKey, buf)
+	if err != nil {
+		return s, err
+	}
+	if n >= 8 {
+		s.size = binary.BigEndian.Uint64(buf[:8])
+	}
+	if n >= 24 {
+		s.files = binary.BigEndian.Uint64(buf[8:16])
+		s.dirs = binary.BigEndian.Uint64(buf[16:24])
+	}
+	return s, nil
+}
+
+// softLimit returns the soft limit for the hard limit and the soft limit
+// percentage of the directory or else the volume
+func softLimit(l quotaapi.Limit, hard uint64, defaultPercent int) uint64 {
+	percent := defaultPercent
+	if l.SoftLimitPercent > 0 {
+		percent = l.SoftLimitPercent
+	}
+	return hard * uint64(percent) / 100
+}
+
+// newLimitInfo reports the usage of a directory against its size limit
+func newLimitInfo(l quotaapi.Limit, softPercent int, s quotaSize) quotaapi.LimitInfo {
+	info := quotaapi.LimitInfo{
+		Path:      l.Path,
+		HardLimit: l.SizeUsageLimit,
+		SoftLimit: softLimit(l, l.SizeUsageLimit, softPercent),
+		Used:      s.size,
+	}
+	if info.Used < info.HardLimit {
+		info.Available = info.HardLimit - info.Used
+	}
+	info.SoftLimitExceeded = info.Used > info.SoftLimit
+	info.HardLimitExceeded = info.Used >= info.HardLimit
+	return info
+}
+
+// newObjectLimitInfo reports the files and directories under a directory
+// against its object count limit
+func newObjectLimitInfo(l quotaapi.Limit, softPercent int, s quotaSize) quotaapi.ObjectLimitInfo {
+	info := quotaapi.ObjectLimitInfo{
+		Path:      l.Path,
+		HardLimit: l.ObjectCountLimit,
+		SoftLimit: softLimit(l, l.ObjectCountLimit, softPercent),
+		Files:     s.files,
+		Dirs:      s.dirs,
+		Used:      s.files + s.dirs,
+	}
+	if info.Used < info.HardLimit {
+		info.Available = info.HardLimit - info.Used
+	}
+	info.SoftLimitExceeded = info.Used > info.SoftLimit
+	info.HardLimitExceeded = info.Used >= info.HardLimit
+	return info
+}
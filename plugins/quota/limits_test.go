@@ -0,0 +1,111 @@
+package quota
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	quotaapi "github.com/gluster/glusterd2/plugins/quota/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		req   quotaapi.SetLimitReq
+		limit *quotaapi.Limit
+		err   error
+	}{
+		{
+			"size limit",
+			quotaapi.SetLimitReq{Path: "/dir1/", SizeUsageLimit: 1024, SoftLimitPercent: 70},
+			&quotaapi.Limit{Path: "/dir1", SizeUsageLimit: 1024, SoftLimitPercent: 70},
+			nil,
+		},
+		{
+			"object count limit",
+			quotaapi.SetLimitReq{Path: "/dir1/../dir2", ObjectCountLimit: 100},
+			&quotaapi.Limit{Path: "/dir2", ObjectCountLimit: 100},
+			nil,
+		},
+		{"relative path", quotaapi.SetLimitReq{Path: "dir1", SizeUsageLimit: 1024}, nil, errInvalidLimitPath},
+		{"empty path", quotaapi.SetLimitReq{SizeUsageLimit: 1024}, nil, errInvalidLimitPath},
+		{"no limit", quotaapi.SetLimitReq{Path: "/dir1"}, nil, errNoLimit},
+		{"negative soft limit", quotaapi.SetLimitReq{Path: "/dir1", SizeUsageLimit: 1024, SoftLimitPercent: -1}, nil, errInvalidSoftLimit},
+		{"soft limit over 100", quotaapi.SetLimitReq{Path: "/dir1", SizeUsageLimit: 1024, SoftLimitPercent: 101}, nil, errInvalidSoftLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, err := newLimit(&tt.req)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.limit, limit)
+		})
+	}
+}
+
+func TestLimitKey(t *testing.T) {
+	// Limits are kept under the prefix of their volume, which doesn't
+	// cover the limits of volumes whose name starts with the same name
+	k := limitKey("vol1", "/dir 1/a")
+	assert.True(t, strings.HasPrefix(k, quotaLimitsPrefix+"vol1/"))
+	assert.False(t, strings.HasPrefix(limitKey("vol10", "/"), quotaLimitsPrefix+"vol1/"))
+	assert.Equal(t, quotaLimitsPrefix+"vol1/%2Fdir+1%2Fa", k)
+	assert.NotEqual(t, k, limitKey("vol1", "/dir 1"))
+}
+
+func TestEncodeLimit(t *testing.T) {
+	buf := encodeLimit(1024, 70)
+	assert.Len(t, buf, 16)
+	assert.Equal(t, uint64(1024), binary.BigEndian.Uint64(buf[:8]))
+	assert.Equal(t, uint64(70), binary.BigEndian.Uint64(buf[8:]))
+
+	// No soft limit percentage defers to the default-soft-limit
+	buf = encodeLimit(1024, 0)
+	assert.Equal(t, int64(-1), int64(binary.BigEndian.Uint64(buf[8:])))
+}
+
+func TestDefaultSoftLimit(t *testing.T) {
+	tests := []struct {
+		value   string
+		percent int
+	}{
+		{"", defaultSoftLimitPercent},
+		{"90", 90},
+		{"60%", 60},
+		{"bad", defaultSoftLimitPercent},
+	}
+
+	for _, tt := range tests {
+		v := &volume.Volinfo{Options: map[string]string{}}
+		if tt.value != "" {
+			v.Options[defaultSoftLimitKey] = tt.value
+		}
+		assert.Equal(t, tt.percent, defaultSoftLimit(v), tt.value)
+	}
+}
+
+func TestNewLimitInfo(t *testing.T) {
+	l := quotaapi.Limit{Path: "/dir1", SizeUsageLimit: 1000, ObjectCountLimit: 100}
+
+	info := newLimitInfo(l, 80, quotaSize{size: 850})
+	assert.Equal(t, uint64(800), info.SoftLimit)
+	assert.Equal(t, uint64(150), info.Available)
+	assert.True(t, info.SoftLimitExceeded)
+	assert.False(t, info.HardLimitExceeded)
+
+	// The soft limit percentage of the directory wins over the default
+	l.SoftLimitPercent = 90
+	info = newLimitInfo(l, 80, quotaSize{size: 1200})
+	assert.Equal(t, uint64(900), info.SoftLimit)
+	assert.Equal(t, uint64(0), info.Available)
+	assert.True(t, info.HardLimitExceeded)
+
+	objInfo := newObjectLimitInfo(l, 80, quotaSize{files: 40, dirs: 10})
+	assert.Equal(t, uint64(50), objInfo.Used)
+	assert.Equal(t, uint64(50), objInfo.Available)
+	assert.Equal(t, uint64(90), objInfo.SoftLimit)
+	assert.False(t, objInfo.SoftLimitExceeded)
+}
@@ -1,25 +1,335 @@
 package quota
 
 import (
+	"context"
 	"net/http"
+	"os"
 
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/errors"
+	quotaapi "github.com/gluster/glusterd2/plugins/quota/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
 )
 
+func quotaEnableHandler(w http.ResponseWriter, r *http.Request) {
+	setQuota(w, r, true)
+}
+
+func quotaDisableHandler(w http.ResponseWriter, r *http.Request) {
+	setQuota(w, r, false)
+}
+
+// setQuota enables or disables quota on the volume with the same steps as
+// setting the quota.enable option, which (re)starts or stops quotad on the
+// nodes of the volume
+func setQuota(w http.ResponseWriter, r *http.Request, enable bool) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if volinfo.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrVolNotStarted)
+		return
+	}
+
+	value := "off"
+	if enable {
+		value = "on"
+		if isQuotaEnabled(volinfo) {
+			restutils.SendHTTPError(ctx, w, http.StatusConflict, errQuotaEnabled)
+			return
+		}
+	} else if !isQuotaEnabled(volinfo) {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, errors.ErrQuotadNotEnabled)
+		return
+	}
+
+	allNodes, err := peer.GetPeerIDs()
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	req := api.VolOptionReq{
+		Options: map[string]string{quotaEnabledKey: value},
+		VolOptionFlags: api.VolOptionFlags{
+			AllowAdvanced: true,
+		},
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "vol-option.Validate",
+			Nodes:  []uuid.UUID{gdctx.MyUUID},
+		},
+		{
+			DoFunc:   "vol-option.UpdateVolinfo",
+			UndoFunc: "vol-option.UpdateVolinfo.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Sync:     true,
+		},
+		{
+			DoFunc:   "vol-option.XlatorActionDoSet",
+			UndoFunc: "vol-option.XlatorActionUndoSet",
+			Nodes:    volinfo.Nodes(),
+		},
+		{
+			DoFunc:   "vol-option.GenerateBrickVolfiles",
+			UndoFunc: "vol-option.GenerateBrickvolfiles.Undo",
+			Nodes:    volinfo.Nodes(),
+		},
+		{
+			DoFunc: "vol-option.NotifyVolfileChange",
+			Nodes:  allNodes,
+		},
+	}
+
+	if err := txn.Ctx.Set("oldvolinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := txn.Ctx.Set("volinfo", volinfo); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := txn.Ctx.Set("req", &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to set quota on volume")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithField("volume", volname).Info("quota " + value)
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+}
+
+// getQuotaVolume returns the volume after checking that quota limits can be
+// handled on it
+func getQuotaVolume(volname string) (*volume.Volinfo, int, error) {
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		return nil, status, err
+	}
+	if volinfo.State != volume.VolStarted {
+		return nil, http.StatusBadRequest, errors.ErrVolNotStarted
+	}
+	if !isQuotaEnabled(volinfo) {
+		return nil, http.StatusBadRequest, errors.ErrQuotadNotEnabled
+	}
+	return volinfo, http.StatusOK, nil
+}
+
 func quotaListHandler(w http.ResponseWriter, r *http.Request) {
-	// implement the help logic and send response back as below
 	ctx := r.Context()
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, "todo: quota list")
+	volname := mux.Vars(r)["volname"]
+
+	resp := quotaapi.ListResp{}
+	status, err := reportLimits(ctx, volname, func(l quotaapi.Limit, softPercent int, s quotaSize, err error) {
+		if l.SizeUsageLimit == 0 {
+			return
+		}
+		info := newLimitInfo(l, softPercent, s)
+		if err != nil {
+			info.Error = err.Error()
+		}
+		resp = append(resp, info)
+	})
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func quotaObjectListHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	volname := mux.Vars(r)["volname"]
+
+	resp := quotaapi.ObjectListResp{}
+	status, err := reportLimits(ctx, volname, func(l quotaapi.Limit, softPercent int, s quotaSize, err error) {
+		if l.ObjectCountLimit == 0 {
+			return
+		}
+		info := newObjectLimitInfo(l, softPercent, s)
+		if err != nil {
+			info.Error = err.Error()
+		}
+		resp = append(resp, info)
+	})
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+// reportLimits calls fn with the usage of each directory of the volume
+// which has limits, read through a mount of the volume
+func reportLimits(ctx context.Context, volname string, fn func(l quotaapi.Limit, softPercent int, s quotaSize, err error)) (int, error) {
+	logger := gdctx.GetReqLogger(ctx)
+
+	volinfo, status, err := getQuotaVolume(volname)
+	if err != nil {
+		return status, err
+	}
+
+	limits, err := getLimits(volname)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if len(limits) == 0 {
+		return http.StatusOK, nil
+	}
+
+	mountDir, unmount, err := quotaMount(volname)
+	if err != nil {
+		logger.WithError(err).WithField("volume", volname).Error("failed to mount volume")
+		return http.StatusInternalServerError, err
+	}
+	defer unmount()
+
+	softPercent := defaultSoftLimit(volinfo)
+	for _, l := range limits {
+		s, err := getQuotaSize(mountDir, l.Path)
+		fn(l, softPercent, s, err)
+	}
+	return http.StatusOK, nil
 }
 
 func quotaLimitHandler(w http.ResponseWriter, r *http.Request) {
-	// implement the help logic and send response back as below
 	ctx := r.Context()
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, "Todo: limitusage")
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req quotaapi.SetLimitReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	limit, err := newLimit(&req)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	status, err := updateLimit(ctx, volname, func(mountDir string) error {
+		if err := setLimitXattrs(mountDir, limit); err != nil {
+			return err
+		}
+		return saveLimit(volname, limit)
+	})
+	if err != nil {
+		logger.WithError(err).WithFields(log.Fields{
+			"volume": volname,
+			"path":   limit.Path,
+		}).Error("failed to set quota limit")
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, limit)
 }
 
 func quotaRemoveHandler(w http.ResponseWriter, r *http.Request) {
-	// implement the help logic and send response back as below
 	ctx := r.Context()
-	restutils.SendHTTPResponse(ctx, w, http.StatusOK, "Todo: quota Remove")
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	var req quotaapi.RemoveLimitReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	dir, err := cleanLimitPath(req.Path)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := getLimit(volname, dir); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		return
+	}
+
+	status, err := updateLimit(ctx, volname, func(mountDir string) error {
+		if err := removeLimitXattrs(mountDir, dir); err != nil {
+			return err
+		}
+		return deleteLimit(volname, dir)
+	})
+	if err != nil {
+		logger.WithError(err).WithFields(log.Fields{
+			"volume": volname,
+			"path":   dir,
+		}).Error("failed to remove quota limit")
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+// updateLimit runs fn with the volume mounted, holding the lock of the
+// volume so that the limits of the volume are updated one at a time
+func updateLimit(ctx context.Context, volname string, fn func(mountDir string) error) (int, error) {
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		return status, err
+	}
+	defer txn.Done()
+
+	if _, status, err := getQuotaVolume(volname); err != nil {
+		return status, err
+	}
+
+	mountDir, unmount, err := quotaMount(volname)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	defer unmount()
+
+	if err := fn(mountDir); err != nil {
+		if err == errNotDirectory {
+			return http.StatusBadRequest, err
+		}
+		if os.IsNotExist(err) {
+			return http.StatusNotFound, err
+		}
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
 }
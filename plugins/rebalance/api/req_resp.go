@@ -20,6 +20,47 @@ const (
 	Failed
 )
 
+// String returns the name of the rebalance state
+func (s Status) String() string {
+	switch s {
+	case NotStarted:
+		return "not started"
+	case Started:
+		return "started"
+	case Stopped:
+		return "stopped"
+	case Complete:
+		return "completed"
+	case Failed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+// Throttle levels of rebalance, the number of files migrated in parallel on a
+// node increases from lazy to aggressive
+const (
+	ThrottleLazy       = "lazy"
+	ThrottleNormal     = "normal"
+	ThrottleAggressive = "aggressive"
+)
+
+// Options of the rebalance start request
+const (
+	// OptionFixLayout only fixes the layout of the directories so that new
+	// files are created on the new bricks, without migrating any data
+	OptionFixLayout = "fix-layout"
+	// OptionForce migrates files even to bricks with less free space
+	OptionForce = "force"
+)
+
+// Modes of a rebalance run as reported in its status
+const (
+	ModeMigrate   = "migrate"
+	ModeFixLayout = "fix-layout"
+	ModeForce     = "force"
+)
+
 // Command represents Rebalance Commands
 type Command uint64
 
@@ -38,6 +79,17 @@ const (
 	CmdStartForce
 )
 
+// Mode returns the mode of the rebalance started with the command
+func (c Command) Mode() string {
+	switch c {
+	case CmdFixLayoutStart:
+		return ModeFixLayout
+	case CmdStartForce:
+		return ModeForce
+	}
+	return ModeMigrate
+}
+
 // RebalNodeStatus represents the rebalance status on the Node
 type RebalNodeStatus struct {
 	PeerID            uuid.UUID `json:"peerid"`
@@ -61,14 +113,38 @@ type RebalInfo struct {
 	RebalStats  []RebalNodeStatus
 }
 
+// RebalTotals sums up the rebalance status of all the nodes. TimeLeft is the
+// estimated time in seconds for the slowest node to complete.
+type RebalTotals struct {
+	RebalancedFiles   uint64 `json:"rebalanced-files"`
+	RebalancedSize    uint64 `json:"size"`
+	LookedupFiles     uint64 `json:"lookedup"`
+	SkippedFiles      uint64 `json:"skipped"`
+	RebalanceFailures uint64 `json:"failed"`
+	TimeLeft          uint64 `json:"time-left"`
+}
+
 // RebalStatus represents the rebalance status response
 type RebalStatus struct {
 	Volname     string            `json:"volume"`
 	RebalanceID uuid.UUID         `json:"rebalance-id"`
+	State       string            `json:"state"`
+	Mode        string            `json:"mode"`
+	Throttle    string            `json:"throttle"`
+	Totals      RebalTotals       `json:"totals"`
 	Nodes       []RebalNodeStatus `json:"nodes-status"`
 }
 
-// StartReq contains the options passed to the Rebalance Start Request
+// StartReq contains the options passed to the Rebalance Start Request.
+// Option is empty to migrate files, or one of fix-layout or force. Throttle is one of lazy, normal or aggressive, the throttle of the volume
+// is left as it is if it isn't given.
 type StartReq struct {
-	Option string `json:"option,omitempty"`
+	Option   string `json:"option,omitempty"`
+	Throttle string `json:"throttle,omitempty"`
+}
+
+// ThrottleReq represents a request to change the throttle level of the
+// rebalance of a volume, which is applied to a running rebalance too
+type ThrottleReq struct {
+	Throttle string `json:"throttle"`
 }
@@ -11,4 +11,10 @@ var (
 	ErrRebalanceNotStarted = errors.New("rebalance not started")
 	// ErrRebalanceInvalidOption : Invalid option provided to the rebalance start command
 	ErrRebalanceInvalidOption = errors.New("invalid Rebalance start option")
+	// ErrRebalanceInProgress : Rebalance is running on the volume
+	ErrRebalanceInProgress = errors.New("rebalance is in progress")
+	// ErrRebalanceInvalidThrottle : Invalid throttle level provided for rebalance
+	ErrRebalanceInvalidThrottle = errors.New("invalid rebalance throttle, must be one of lazy, normal or aggressive")
+	// ErrRebalanceFixLayoutThrottle : Throttle changed for a rebalance which only fixes the layout
+	ErrRebalanceFixLayoutThrottle = errors.New("fix-layout rebalance migrates no files and can't be throttled")
 )
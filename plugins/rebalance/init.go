@@ -41,6 +41,13 @@ func (p *Plugin) RestRoutes() route.Routes {
 			Version: 1,
 			//			ResponseType: utils.GetTypeString((*rebalanceapi.RebalInfo)(nil)),
 			HandlerFunc: rebalanceStatusHandler},
+		route.Route{
+			Name:        "RebalanceThrottle",
+			Method:      "POST",
+			Pattern:     "/volumes/{volname}/rebalance/throttle",
+			Version:     1,
+			RequestType: utils.GetTypeString((*rebalanceapi.ThrottleReq)(nil)),
+			HandlerFunc: rebalanceThrottleHandler},
 	}
 }
 
@@ -51,4 +58,5 @@ func (p *Plugin) RegisterStepFuncs() {
 	transaction.RegisterStepFunc(txnRebalanceStop, "rebalance-stop")
 	transaction.RegisterStepFunc(txnRebalanceStatus, "rebalance-status")
 	transaction.RegisterStepFunc(txnRebalanceStoreDetails, "rebalance-store")
+	transaction.RegisterStepFunc(txnRebalanceThrottle, "rebalance-throttle")
 }
@@ -0,0 +1,65 @@
+package rebalance
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/jobs"
+	rebalanceapi "github.com/gluster/glusterd2/plugins/rebalance/api"
+
+	"github.com/pborman/uuid"
+)
+
+// rebalancePollInterval is how often the rebalance info of a volume is
+// checked while a rebalance job runs
+const rebalancePollInterval = 30 * time.Second
+
+// waitForRebalance follows the rebalance run with the given ID on the volume
+// until the rebalance processes on all the nodes complete. Cancelling the job
+// stops the rebalance.
+func waitForRebalance(ctx context.Context, h *jobs.Handle, volname string, rebalanceID uuid.UUID) error {
+	logger := gdctx.GetReqLogger(ctx)
+	completed := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			// ctx is cancelled, stop the rebalance with a fresh context
+			stopCtx := gdctx.WithReqID(context.Background(), gdctx.GetReqID(ctx))
+			stopCtx = gdctx.WithReqLogger(stopCtx, logger)
+			if _, _, err := stopRebalance(stopCtx, volname); err != nil {
+				logger.WithError(err).Warn("failed to stop rebalance on job cancel")
+			} else {
+				h.Logf("rebalance stopped")
+			}
+			return ctx.Err()
+		case <-time.After(rebalancePollInterval):
+		}
+
+		rebalinfo, err := GetRebalanceInfo(volname)
+		if err != nil {
+			logger.WithError(err).Debug("failed to get rebalance info")
+			continue
+		}
+		if !uuid.Equal(rebalinfo.RebalanceID, rebalanceID) {
+			return errors.New("rebalance was restarted")
+		}
+
+		if len(rebalinfo.RebalStats) != completed {
+			completed = len(rebalinfo.RebalStats)
+			h.Logf("rebalance completed on %d nodes", completed)
+		}
+
+		switch rebalinfo.State {
+		case rebalanceapi.Complete:
+			h.Logf("rebalance completed")
+			return nil
+		case rebalanceapi.Stopped:
+			return errors.New("rebalance stopped")
+		case rebalanceapi.Failed:
+			return errors.New("rebalance failed")
+		}
+	}
+}
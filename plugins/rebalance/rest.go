@@ -1,13 +1,16 @@
 package rebalance
 
 import (
+	"context"
 	"io"
 	"net/http"
 
 	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/jobs"
 	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
 	"github.com/gluster/glusterd2/glusterd2/transaction"
 	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
 	"github.com/gluster/glusterd2/pkg/errors"
 
 	rebalanceapi "github.com/gluster/glusterd2/plugins/rebalance/api"
@@ -17,7 +20,9 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-func createRebalanceInfo(volname string, req *rebalanceapi.StartReq) *rebalanceapi.RebalInfo {
+// NewRebalanceInfo returns the rebalance info for a new rebalance run on the
+// volume with the options in the start request
+func NewRebalanceInfo(volname string, req *rebalanceapi.StartReq) *rebalanceapi.RebalInfo {
 	return &rebalanceapi.RebalInfo{
 		Volname:     volname,
 		RebalanceID: uuid.NewRandom(),
@@ -45,18 +50,13 @@ func rebalanceStartHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rebalinfo := createRebalanceInfo(volname, &req)
+	rebalinfo := NewRebalanceInfo(volname, &req)
 	if rebalinfo == nil {
 		logger.WithError(err).Error("failed to create Rebalance info")
 		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
 		return
 	}
 
-	if rebalinfo.Cmd == rebalanceapi.CmdNone {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, ErrRebalanceInvalidOption)
-		return
-	}
-
 	txn, err := transaction.NewTxnWithLocks(ctx, volname)
 	if err != nil {
 		status, err := restutils.ErrToStatusCode(err)
@@ -72,8 +72,8 @@ func rebalanceStartHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if vol.State != volume.VolStarted {
-		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrVolNotStarted)
+	if status, err := ValidateStart(vol, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
 		return
 	}
 
@@ -82,22 +82,28 @@ func rebalanceStartHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// TODO: Check for remove-brick
-
 	// Start the rebalance process on all nodes
 	// Only this node will save the rebalinfo in the store
 
 	txn.Nodes = vol.Nodes()
-	txn.Steps = []*transaction.Step{
-		{
-			DoFunc: "rebalance-start",
-			Nodes:  txn.Nodes,
-		},
-		{
-			DoFunc: "rebalance-store",
-			Nodes:  []uuid.UUID{gdctx.MyUUID},
-			Sync:   true,
-		},
+	txn.Steps = StartSteps(txn.Nodes)
+
+	// The throttle is saved in the volinfo before the rebalance volfile
+	// is generated with it
+	if req.Throttle != "" && req.Throttle != getThrottle(vol.Options) {
+		if err := txn.Ctx.Set("oldvolinfo", vol); err != nil {
+			logger.WithError(err).Error("failed to set oldvolinfo in transaction context")
+			restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+		vol.Options[keyRebalThrottle] = req.Throttle
+		txn.Steps = append([]*transaction.Step{
+			{
+				DoFunc:   "vol-option.UpdateVolinfo",
+				UndoFunc: "vol-option.UpdateVolinfo.Undo",
+				Nodes:    []uuid.UUID{gdctx.MyUUID},
+			},
+		}, txn.Steps...)
 	}
 
 	err = txn.Ctx.Set("volname", volname)
@@ -140,62 +146,65 @@ func rebalanceStartHandler(w http.ResponseWriter, r *http.Request) {
 
 	logger.WithField("volname", rebalinfo.Volname).Info("rebalance started")
 
+	rebalanceID := rebalinfo.RebalanceID
+	_, err = jobs.Submit(ctx, api.JobTypeRebalance, volname, func(ctx context.Context, h *jobs.Handle) error {
+		return waitForRebalance(ctx, h, volname, rebalanceID)
+	})
+	if err != nil {
+		logger.WithError(err).Warn("failed to start job following rebalance")
+	}
+
 	restutils.SendHTTPResponse(ctx, w, http.StatusOK, rebalinfo.RebalanceID)
 }
 
 func rebalanceStopHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	logger := gdctx.GetReqLogger(ctx)
 
 	// collect inputs from url
 	volname := mux.Vars(r)["volname"]
 
-	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	rebalinfo, status, err := stopRebalance(ctx, volname)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
+		restutils.SendHTTPError(ctx, w, status, err.Error())
 		return
 	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, rebalinfo)
+}
+
+// stopRebalance stops the rebalance running on the volume
+func stopRebalance(ctx context.Context, volname string) (*rebalanceapi.RebalInfo, int, error) {
+	logger := gdctx.GetReqLogger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		return nil, restutils.ErrToStatusCode(err)
+	}
 	defer txn.Done()
 
 	// Validate rebalance command
 	vol, err := volume.GetVolume(volname)
 	if err != nil {
-		status, err := restutils.ErrToStatusCode(err)
-		restutils.SendHTTPError(ctx, w, status, err)
-		return
+		return nil, restutils.ErrToStatusCode(err)
 	}
 
 	rebalinfo, err := GetRebalanceInfo(volname)
 	if err != nil {
-		restutils.SendHTTPError(r.Context(), w, http.StatusBadRequest, ErrRebalanceNotStarted)
-		return
+		return nil, http.StatusBadRequest, ErrRebalanceNotStarted
 	}
 
 	// Check whether the rebalance state is started
 	if rebalinfo.State != rebalanceapi.Started {
-		restutils.SendHTTPError(r.Context(), w, http.StatusBadRequest, ErrRebalanceNotStarted)
-		return
+		return nil, http.StatusBadRequest, ErrRebalanceNotStarted
 	}
 
 	txn.Nodes = vol.Nodes()
-	txn.Steps = []*transaction.Step{
-		{
-			DoFunc: "rebalance-stop",
-			Nodes:  txn.Nodes,
-		},
-		{
-			DoFunc: "rebalance-store",
-			Nodes:  []uuid.UUID{gdctx.MyUUID},
-			Sync:   true,
-		},
-	}
+	txn.Steps = StopSteps(txn.Nodes)
 
 	err = txn.Ctx.Set("volname", volname)
 	if err != nil {
 		logger.WithError(err).Error("failed to set volname in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err.Error())
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	rebalinfo.Volname = volname
@@ -205,19 +214,17 @@ func rebalanceStopHandler(w http.ResponseWriter, r *http.Request) {
 	err = txn.Ctx.Set("rinfo", rebalinfo)
 	if err != nil {
 		logger.WithError(err).Error("failed to set rebalance info in transaction context")
-		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err.Error())
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	err = txn.Do()
 	if err != nil {
 		logger.WithError(err).WithField("volname", volname).Error("failed to stop rebalance on volume")
-		restutils.SendHTTPError(r.Context(), w, http.StatusInternalServerError, err.Error())
-		return
+		return nil, http.StatusInternalServerError, err
 	}
 
 	logger.WithField("volname", rebalinfo.Volname).Info("rebalance stopped")
-	restutils.SendHTTPResponse(r.Context(), w, http.StatusOK, rebalinfo)
+	return rebalinfo, http.StatusOK, nil
 }
 
 func rebalanceStatusHandler(w http.ResponseWriter, r *http.Request) {
@@ -261,12 +268,7 @@ func rebalanceStatusHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Get the consolidated status from all the nodes
 	txn.Nodes = vol.Nodes()
-	txn.Steps = []*transaction.Step{
-		{
-			DoFunc: "rebalance-status",
-			Nodes:  txn.Nodes,
-		},
-	}
+	txn.Steps = StatusSteps(txn.Nodes)
 
 	err = txn.Ctx.Set("rinfo", rebalinfo)
 	if err != nil {
@@ -280,7 +282,7 @@ func rebalanceStatusHandler(w http.ResponseWriter, r *http.Request) {
 		logger.WithError(err).WithField("volname", volname).Error("failed to query rebalance status for volume")
 	}
 
-	response, err := createRebalanceStatusResp(txn.Ctx, vol)
+	response, err := CreateRebalanceStatusResp(txn.Ctx, vol)
 	if err != nil {
 		errMsg := "Failed to create rebalance status response"
 		logger.WithError(err).Error("rebalanceStatusHandler:" + errMsg)
@@ -292,7 +294,10 @@ func rebalanceStatusHandler(w http.ResponseWriter, r *http.Request) {
 	restutils.SendHTTPResponse(r.Context(), w, http.StatusOK, response)
 }
 
-func createRebalanceStatusResp(ctx transaction.TxnCtx, volinfo *volume.Volinfo) (*rebalanceapi.RebalStatus, error) {
+// CreateRebalanceStatusResp aggregates the rebalance status saved by the
+// processes which have completed with the status collected by the
+// "rebalance-status" step from the running ones
+func CreateRebalanceStatusResp(ctx transaction.TxnCtx, volinfo *volume.Volinfo) (*rebalanceapi.RebalStatus, error) {
 	var (
 		resp      rebalanceapi.RebalStatus
 		tmp       rebalanceapi.RebalNodeStatus
@@ -308,6 +313,9 @@ func createRebalanceStatusResp(ctx transaction.TxnCtx, volinfo *volume.Volinfo)
 	// Fill common info
 	resp.Volname = volinfo.Name
 	resp.RebalanceID = rebalinfo.RebalanceID
+	resp.State = rebalinfo.State.String()
+	resp.Mode = rebalinfo.Cmd.Mode()
+	resp.Throttle = getThrottle(volinfo.Options)
 
 	// Get the status for the completed processes first
 	for _, tmp := range rebalinfo.RebalStats {
@@ -324,5 +332,84 @@ func createRebalanceStatusResp(ctx transaction.TxnCtx, volinfo *volume.Volinfo)
 
 		resp.Nodes = append(resp.Nodes, tmp)
 	}
+
+	for i := range resp.Nodes {
+		addNodeStatus(&resp.Totals, &resp.Nodes[i])
+	}
 	return &resp, nil
 }
+
+func rebalanceThrottleHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	// collect inputs from url
+	volname := mux.Vars(r)["volname"]
+
+	var req rebalanceapi.ThrottleReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	vol, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	rebalinfo, status, err := validateThrottle(volname, &req)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	if req.Throttle == getThrottle(vol.Options) {
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+		return
+	}
+
+	if err := txn.Ctx.Set("oldvolinfo", vol); err != nil {
+		logger.WithError(err).Error("failed to set oldvolinfo in transaction context")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	vol.Options[keyRebalThrottle] = req.Throttle
+	if err := txn.Ctx.Set("volinfo", vol); err != nil {
+		logger.WithError(err).Error("failed to set volinfo in transaction context")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := txn.Ctx.Set("rinfo", rebalinfo); err != nil {
+		logger.WithError(err).Error("failed to set rebalance info in transaction context")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Nodes = vol.Nodes()
+	txn.Steps = ThrottleSteps(txn.Nodes)
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).WithField("volname", volname).Error("failed to change rebalance throttle of volume")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	logger.WithFields(log.Fields{
+		"volname":  volname,
+		"throttle": req.Throttle,
+	}).Info("rebalance throttle changed")
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, nil)
+}
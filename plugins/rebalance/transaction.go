@@ -12,6 +12,7 @@ import (
 	"github.com/gluster/glusterd2/glusterd2/volume"
 	rebalanceapi "github.com/gluster/glusterd2/plugins/rebalance/api"
 
+	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -26,6 +27,77 @@ const (
 	rebalStatusTxnKey string = "rebalstatus"
 )
 
+// StartSteps returns the transaction steps which start the rebalance process
+// on the given nodes and save the rebalance info. The transaction context must
+// have "volinfo" and "rinfo" set before these steps run.
+func StartSteps(nodes []uuid.UUID) []*transaction.Step {
+	return []*transaction.Step{
+		{
+			DoFunc:  "rebalance-start",
+			Nodes:   nodes,
+			Timeout: transaction.DaemonStartTimeout,
+		},
+		{
+			DoFunc: "rebalance-store",
+			Nodes:  []uuid.UUID{gdctx.MyUUID},
+			Sync:   true,
+		},
+	}
+}
+
+// StopSteps returns the transaction steps which stop the rebalance process
+// on the given nodes and save the rebalance info. The transaction context must
+// have "volname" and "rinfo" set before these steps run.
+func StopSteps(nodes []uuid.UUID) []*transaction.Step {
+	return []*transaction.Step{
+		{
+			DoFunc: "rebalance-stop",
+			Nodes:  nodes,
+		},
+		{
+			DoFunc: "rebalance-store",
+			Nodes:  []uuid.UUID{gdctx.MyUUID},
+			Sync:   true,
+		},
+	}
+}
+
+// StatusSteps returns the transaction steps which query the status of the
+// rebalance process on the given nodes. The transaction context must have
+// "volname" and "rinfo" set before these steps run.
+func StatusSteps(nodes []uuid.UUID) []*transaction.Step {
+	return []*transaction.Step{
+		{
+			DoFunc: "rebalance-status",
+			Nodes:  nodes,
+		},
+	}
+}
+
+// ThrottleSteps returns the transaction steps which save the throttle of the
+// volume and have the rebalance processes on the given nodes reload their
+// volfile with it. The transaction context must have "oldvolinfo", "volinfo"
+// and "rinfo" set before these steps run.
+func ThrottleSteps(nodes []uuid.UUID) []*transaction.Step {
+	return []*transaction.Step{
+		{
+			DoFunc:   "vol-option.UpdateVolinfo",
+			UndoFunc: "vol-option.UpdateVolinfo.Undo",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+		},
+		{
+			DoFunc: "rebalance-throttle",
+			Nodes:  nodes,
+			// Volinfo needs to be updated before the volfile is generated
+			Sync: true,
+		},
+		{
+			DoFunc: "vol-option.NotifyVolfileChange",
+			Nodes:  nodes,
+		},
+	}
+}
+
 func txnRebalanceStart(c transaction.TxnCtx) error {
 	var rinfo rebalanceapi.RebalInfo
 
@@ -134,6 +206,35 @@ func txnRebalanceStop(c transaction.TxnCtx) error {
 	return nil
 }
 
+// txnRebalanceThrottle regenerates the rebalance volfile with the new
+// throttle, which the running rebalance process fetches once notified
+func txnRebalanceThrottle(c transaction.TxnCtx) error {
+	var rinfo rebalanceapi.RebalInfo
+	if err := c.Get("rinfo", &rinfo); err != nil {
+		return err
+	}
+
+	var volinfo volume.Volinfo
+	if err := c.Get("volinfo", &volinfo); err != nil {
+		c.Logger().WithError(err).WithField(
+			"key", "volinfo").Error("failed to get key from store")
+		return err
+	}
+
+	rebalanceProcess, err := NewRebalanceProcess(rinfo)
+	if err != nil {
+		return err
+	}
+	err = volgen.VolumeVolfileToFile(&volinfo, rebalanceProcess.VolfileID, "rebalance")
+	if err != nil {
+		c.Logger().WithError(err).WithField(
+			"volfile", rebalanceProcess.VolfileID).Error("failed to generate volfile")
+		return err
+	}
+
+	return nil
+}
+
 func txnRebalanceStatus(c transaction.TxnCtx) error {
 
 	var (
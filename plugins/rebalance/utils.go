@@ -4,9 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gluster/glusterd2/glusterd2/store"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
 	rebalanceapi "github.com/gluster/glusterd2/plugins/rebalance/api"
 
 	log "github.com/sirupsen/logrus"
@@ -14,9 +18,17 @@ import (
 
 const (
 	rebalancePrefix string = "rebalance/"
+
+	// keyRebalThrottle is the volume option of the distribute xlator
+	// read by the rebalance process
+	keyRebalThrottle string = "cluster/distribute.rebal-throttle"
 )
 
 var (
+	// getRebalanceInfoF returns the stored rebalance details, it can be
+	// patched by tests
+	getRebalanceInfoF = GetRebalanceInfo
+
 	hash uint64
 )
 
@@ -94,9 +106,9 @@ func StoreRebalanceInfo(rinfo *rebalanceapi.RebalInfo) error {
 func getCmd(req *rebalanceapi.StartReq) rebalanceapi.Command {
 
 	switch req.Option {
-	case "fix-layout":
+	case rebalanceapi.OptionFixLayout:
 		return rebalanceapi.CmdFixLayoutStart
-	case "force":
+	case rebalanceapi.OptionForce:
 		return rebalanceapi.CmdStartForce
 	case "":
 		return rebalanceapi.CmdStart
@@ -104,3 +116,93 @@ func getCmd(req *rebalanceapi.StartReq) rebalanceapi.Command {
 		return rebalanceapi.CmdNone
 	}
 }
+
+func isValidThrottle(throttle string) bool {
+	switch throttle {
+	case rebalanceapi.ThrottleLazy, rebalanceapi.ThrottleNormal, rebalanceapi.ThrottleAggressive:
+		return true
+	}
+	return false
+}
+
+// getThrottle returns the rebalance throttle level set on the volume, which
+// is normal unless it has been changed
+func getThrottle(options map[string]string) string {
+	if throttle, ok := options[keyRebalThrottle]; ok {
+		return throttle
+	}
+	return rebalanceapi.ThrottleNormal
+}
+
+// parseCount parses a count reported by the rebalance process, which is
+// empty for nodes where the process hasn't reported yet
+func parseCount(s string) uint64 {
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// addNodeStatus adds the counts of a node to the totals. The time left is
+// that of the slowest node, as the nodes migrate files in parallel.
+func addNodeStatus(t *rebalanceapi.RebalTotals, n *rebalanceapi.RebalNodeStatus) {
+	t.RebalancedFiles += parseCount(n.RebalancedFiles)
+	t.RebalancedSize += parseCount(n.RebalancedSize)
+	t.LookedupFiles += parseCount(n.LookedupFiles)
+	t.SkippedFiles += parseCount(n.SkippedFiles)
+	t.RebalanceFailures += parseCount(n.RebalanceFailures)
+	if left := parseCount(n.TimeLeft); left > t.TimeLeft {
+		t.TimeLeft = left
+	}
+}
+
+// ValidateStart checks that a rebalance can be started on the volume with
+// the given start request. It is called with the volume locked. If the
+// rebalance can't be started, the HTTP status to respond with is returned
+// along with the error.
+func ValidateStart(vol *volume.Volinfo, req *rebalanceapi.StartReq) (int, error) {
+	if getCmd(req) == rebalanceapi.CmdNone {
+		return http.StatusBadRequest, ErrRebalanceInvalidOption
+	}
+
+	if req.Throttle != "" && !isValidThrottle(req.Throttle) {
+		return http.StatusBadRequest, ErrRebalanceInvalidThrottle
+	}
+
+	if vol.State != volume.VolStarted {
+		return http.StatusBadRequest, gderrors.ErrVolNotStarted
+	}
+
+	if len(vol.DecommissionedBricks()) != 0 {
+		return http.StatusConflict, gderrors.ErrRemoveBrickInProgress
+	}
+
+	if oldinfo, err := getRebalanceInfoF(vol.Name); err == nil && oldinfo.State == rebalanceapi.Started {
+		return http.StatusConflict, ErrRebalanceInProgress
+	}
+
+	return http.StatusOK, nil
+}
+
+// validateThrottle checks that the throttle of the rebalance of the volume
+// can be changed as per the request, and returns the info of the running
+// rebalance. It is called with the volume locked. If the throttle can't be
+// changed, the HTTP status to respond with is returned along with the error.
+func validateThrottle(volname string, req *rebalanceapi.ThrottleReq) (*rebalanceapi.RebalInfo, int, error) {
+	if !isValidThrottle(req.Throttle) {
+		return nil, http.StatusBadRequest, ErrRebalanceInvalidThrottle
+	}
+
+	rebalinfo, err := getRebalanceInfoF(volname)
+	if err != nil || rebalinfo.State != rebalanceapi.Started {
+		return nil, http.StatusBadRequest, ErrRebalanceNotStarted
+	}
+
+	// A fix-layout run doesn't migrate files, so there is nothing to throttle
+	if rebalinfo.Cmd == rebalanceapi.CmdFixLayoutStart {
+		return nil, http.StatusBadRequest, ErrRebalanceFixLayoutThrottle
+	}
+
+	return rebalinfo, http.StatusOK, nil
+}
@@ -0,0 +1,144 @@
+package rebalance
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/brick"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	gderrors "github.com/gluster/glusterd2/pkg/errors"
+	"github.com/gluster/glusterd2/pkg/testutils"
+	rebalanceapi "github.com/gluster/glusterd2/plugins/rebalance/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateStart(t *testing.T) {
+	running := map[string]bool{"running": true}
+	defer testutils.Patch(&getRebalanceInfoF, func(volname string) (*rebalanceapi.RebalInfo, error) {
+		if running[volname] {
+			return &rebalanceapi.RebalInfo{Volname: volname, State: rebalanceapi.Started}, nil
+		}
+		return nil, errors.New("rebalance info not found")
+	}).Restore()
+
+	newVol := func(name string, state volume.VolState, decommissioned bool) *volume.Volinfo {
+		return &volume.Volinfo{
+			Name:  name,
+			State: state,
+			Subvols: []volume.Subvol{
+				{Bricks: []brick.Brickinfo{{Path: "/bricks/b1"}}},
+				{Bricks: []brick.Brickinfo{{Path: "/bricks/b2", Decommissioned: decommissioned}}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		vol    *volume.Volinfo
+		req    rebalanceapi.StartReq
+		status int
+		err    error
+	}{
+		{"start", newVol("gv0", volume.VolStarted, false), rebalanceapi.StartReq{}, http.StatusOK, nil},
+		{"fix-layout", newVol("gv0", volume.VolStarted, false), rebalanceapi.StartReq{Option: rebalanceapi.OptionFixLayout}, http.StatusOK, nil},
+		{"throttle", newVol("gv0", volume.VolStarted, false), rebalanceapi.StartReq{Throttle: rebalanceapi.ThrottleLazy}, http.StatusOK, nil},
+		{"invalid option", newVol("gv0", volume.VolStarted, false), rebalanceapi.StartReq{Option: "fast"}, http.StatusBadRequest, ErrRebalanceInvalidOption},
+		{"invalid throttle", newVol("gv0", volume.VolStarted, false), rebalanceapi.StartReq{Throttle: "fast"}, http.StatusBadRequest, ErrRebalanceInvalidThrottle},
+		{"stopped", newVol("gv0", volume.VolStopped, false), rebalanceapi.StartReq{}, http.StatusBadRequest, gderrors.ErrVolNotStarted},
+		{"remove-brick", newVol("gv0", volume.VolStarted, true), rebalanceapi.StartReq{}, http.StatusConflict, gderrors.ErrRemoveBrickInProgress},
+		{"running", newVol("running", volume.VolStarted, false), rebalanceapi.StartReq{}, http.StatusConflict, ErrRebalanceInProgress},
+	}
+
+	for _, tt := range tests {
+		status, err := ValidateStart(tt.vol, &tt.req)
+		assert.Equal(t, tt.status, status, tt.name)
+		assert.Equal(t, tt.err, err, tt.name)
+	}
+}
+
+func TestValidateThrottle(t *testing.T) {
+	infos := map[string]*rebalanceapi.RebalInfo{
+		"running":    {Volname: "running", State: rebalanceapi.Started, Cmd: rebalanceapi.CmdStart},
+		"fix-layout": {Volname: "fix-layout", State: rebalanceapi.Started, Cmd: rebalanceapi.CmdFixLayoutStart},
+		"completed":  {Volname: "completed", State: rebalanceapi.Complete, Cmd: rebalanceapi.CmdStart},
+	}
+	defer testutils.Patch(&getRebalanceInfoF, func(volname string) (*rebalanceapi.RebalInfo, error) {
+		if info, ok := infos[volname]; ok {
+			return info, nil
+		}
+		return nil, errors.New("rebalance info not found")
+	}).Restore()
+
+	tests := []struct {
+		volname  string
+		throttle string
+		status   int
+		err      error
+	}{
+		{"running", rebalanceapi.ThrottleLazy, http.StatusOK, nil},
+		{"running", rebalanceapi.ThrottleAggressive, http.StatusOK, nil},
+		{"running", "", http.StatusBadRequest, ErrRebalanceInvalidThrottle},
+		{"running", "fast", http.StatusBadRequest, ErrRebalanceInvalidThrottle},
+		{"fix-layout", rebalanceapi.ThrottleLazy, http.StatusBadRequest, ErrRebalanceFixLayoutThrottle},
+		{"completed", rebalanceapi.ThrottleLazy, http.StatusBadRequest, ErrRebalanceNotStarted},
+		{"gv0", rebalanceapi.ThrottleLazy, http.StatusBadRequest, ErrRebalanceNotStarted},
+	}
+
+	for _, tt := range tests {
+		rebalinfo, status, err := validateThrottle(tt.volname, &rebalanceapi.ThrottleReq{Throttle: tt.throttle})
+		assert.Equal(t, tt.status, status, "%s %s", tt.volname, tt.throttle)
+		assert.Equal(t, tt.err, err, "%s %s", tt.volname, tt.throttle)
+		if err == nil {
+			assert.Equal(t, infos[tt.volname], rebalinfo)
+		}
+	}
+}
+
+func TestGetThrottle(t *testing.T) {
+	assert.Equal(t, rebalanceapi.ThrottleNormal, getThrottle(nil))
+	assert.Equal(t, rebalanceapi.ThrottleNormal, getThrottle(map[string]string{}))
+	assert.Equal(t, rebalanceapi.ThrottleLazy, getThrottle(map[string]string{keyRebalThrottle: rebalanceapi.ThrottleLazy}))
+}
+
+func TestAddNodeStatus(t *testing.T) {
+	nodes := []rebalanceapi.RebalNodeStatus{
+		{RebalancedFiles: "10", RebalancedSize: "1024", LookedupFiles: "20", SkippedFiles: "1", RebalanceFailures: "0", TimeLeft: "30"},
+		{RebalancedFiles: "5", RebalancedSize: "512", LookedupFiles: "8", SkippedFiles: "0", RebalanceFailures: "2", TimeLeft: "120"},
+		// Nodes which haven't reported yet have empty counts
+		{},
+	}
+
+	var totals rebalanceapi.RebalTotals
+	for i := range nodes {
+		addNodeStatus(&totals, &nodes[i])
+	}
+
+	assert.Equal(t, rebalanceapi.RebalTotals{
+		RebalancedFiles:   15,
+		RebalancedSize:    1536,
+		LookedupFiles:     28,
+		SkippedFiles:      1,
+		RebalanceFailures: 2,
+		TimeLeft:          120,
+	}, totals)
+}
+
+func TestStatusString(t *testing.T) {
+	tests := []struct {
+		status rebalanceapi.Status
+		name   string
+	}{
+		{rebalanceapi.NotStarted, "not started"},
+		{rebalanceapi.Started, "started"},
+		{rebalanceapi.Stopped, "stopped"},
+		{rebalanceapi.Complete, "completed"},
+		{rebalanceapi.Failed, "failed"},
+		{rebalanceapi.Status(100), "unknown"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.name, tt.status.String())
+	}
+}
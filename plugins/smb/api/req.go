@@ -0,0 +1,7 @@
+package api
+
+// EnableReq represents REST API request to share volumes over SMB from a set
+// of peers running Samba, given by their IDs
+type EnableReq struct {
+	Peers []string `json:"peers"`
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"github.com/pborman/uuid"
+)
+
+// ClusterInfo represents the peers volumes are shared over SMB from
+type ClusterInfo struct {
+	Peers []uuid.UUID `json:"peers"`
+}
+
+// PeerStatus represents the state of Samba on a peer and the shares of
+// volumes configured on it. Error is set if the state couldn't be found.
+// Clients should NOT use this struct directly.
+type PeerStatus struct {
+	PeerID  uuid.UUID `json:"peer-id"`
+	Running bool      `json:"running"`
+	Shares  []string  `json:"shares"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// PeerShare represents the state of a share on a peer
+type PeerShare struct {
+	PeerID     uuid.UUID `json:"peer-id"`
+	Configured bool      `json:"configured"`
+	Running    bool      `json:"running"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Share represents the SMB share of a volume
+type Share struct {
+	Volume string      `json:"volume"`
+	Name   string      `json:"name"`
+	Peers  []PeerShare `json:"peers"`
+}
+
+// StatusResp represents the peers volumes are shared over SMB from and the
+// shares of the volumes which have user.smb enabled and are started
+type StatusResp struct {
+	Enabled bool        `json:"enabled"`
+	Peers   []uuid.UUID `json:"peers"`
+	Shares  []Share     `json:"shares"`
+}
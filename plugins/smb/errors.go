@@ -0,0 +1,12 @@
+package smb
+
+import (
+	"errors"
+)
+
+var (
+	errSmbEnabled    = errors.New("smb is already enabled")
+	errSmbNotEnabled = errors.New("smb is not enabled")
+	errNoPeers       = errors.New("at least one peer is required to share volumes from")
+	errShareDisabled = errors.New("volume is not shared over smb, user.smb is not enabled")
+)
@@ -0,0 +1,49 @@
+package smb
+
+import (
+	gd2events "github.com/gluster/glusterd2/glusterd2/events"
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/api"
+
+	"github.com/pborman/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// sharesUpdater updates the shares on the peers volumes are shared from when
+// volumes are started, stopped or deleted, or their options change. Volume
+// events are global, so every peer updates its own shares.
+type sharesUpdater struct{}
+
+func (s *sharesUpdater) Handle(e *api.Event) {
+	cluster, err := getCluster()
+	if err == errSmbNotEnabled {
+		return
+	} else if err != nil {
+		log.WithError(err).Error("failed to get smb peers from store")
+		return
+	}
+
+	for _, id := range cluster.Peers {
+		if uuid.Equal(id, gdctx.MyUUID) {
+			if err := syncShares(); err != nil {
+				log.WithError(err).WithField("event", e.Name).Error("failed to update samba shares")
+			}
+			return
+		}
+	}
+}
+
+func (s *sharesUpdater) Events() []string {
+	return []string{
+		string(volume.EventVolumeStarted),
+		string(volume.EventVolumeStopped),
+		string(volume.EventVolumeDeleted),
+		string(volume.EventVolumeOptionsSet),
+		string(volume.EventVolumeOptionsReset),
+	}
+}
+
+func init() {
+	gd2events.Register(new(sharesUpdater))
+}
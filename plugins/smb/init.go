@@ -0,0 +1,63 @@
+package smb
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/servers/rest/route"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/pkg/api"
+	"github.com/gluster/glusterd2/pkg/utils"
+	smbapi "github.com/gluster/glusterd2/plugins/smb/api"
+)
+
+// Plugin is a structure which implements GlusterdPlugin interface
+type Plugin struct {
+}
+
+// Name returns name of plugin
+func (p *Plugin) Name() string {
+	return "smb"
+}
+
+// RestRoutes returns list of REST API routes to register with Glusterd
+func (p *Plugin) RestRoutes() route.Routes {
+	return route.Routes{
+		route.Route{
+			Name:         "SmbEnable",
+			Method:       "POST",
+			Pattern:      "/smb",
+			Version:      1,
+			RequestType:  utils.GetTypeString((*smbapi.EnableReq)(nil)),
+			ResponseType: utils.GetTypeString((*smbapi.ClusterInfo)(nil)),
+			HandlerFunc:  smbEnableHandler,
+			Role:         api.RoleAdmin},
+		route.Route{
+			Name:        "SmbDisable",
+			Method:      "DELETE",
+			Pattern:     "/smb",
+			Version:     1,
+			HandlerFunc: smbDisableHandler},
+		route.Route{
+			Name:         "SmbStatus",
+			Method:       "GET",
+			Pattern:      "/smb",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*smbapi.StatusResp)(nil)),
+			HandlerFunc:  smbStatusHandler},
+		route.Route{
+			Name:         "SmbShareGet",
+			Method:       "GET",
+			Pattern:      "/volumes/{volname}/smb",
+			Version:      1,
+			ResponseType: utils.GetTypeString((*smbapi.Share)(nil)),
+			HandlerFunc:  smbShareGetHandler},
+	}
+}
+
+// RegisterStepFuncs registers transaction step functions with
+// Glusterd Transaction framework
+func (p *Plugin) RegisterStepFuncs() {
+	transaction.RegisterStepFunc(txnSync, "smb.Sync")
+	transaction.RegisterStepFunc(txnCleanup, "smb.Cleanup")
+	transaction.RegisterStepFunc(txnStoreCluster, "smb.StoreCluster")
+	transaction.RegisterStepFunc(txnDeleteCluster, "smb.DeleteCluster")
+	transaction.RegisterStepFunc(txnStatus, "smb.Status")
+}
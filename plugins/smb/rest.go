@@ -0,0 +1,281 @@
+package smb
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/peer"
+	restutils "github.com/gluster/glusterd2/glusterd2/servers/rest/utils"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	"github.com/gluster/glusterd2/glusterd2/volume"
+	"github.com/gluster/glusterd2/pkg/errors"
+	smbapi "github.com/gluster/glusterd2/plugins/smb/api"
+
+	"github.com/gorilla/mux"
+	"github.com/pborman/uuid"
+)
+
+// lockID is locked by the transactions changing the set of peers volumes are
+// shared from
+const lockID = "smb"
+
+func smbEnableHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	var req smbapi.EnableReq
+	if err := restutils.UnmarshalRequest(r, &req); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errors.ErrJSONParsingFailed)
+		return
+	}
+
+	if len(req.Peers) == 0 {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, errNoPeers)
+		return
+	}
+
+	txn, err := transaction.NewTxnWithLocks(ctx, lockID)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	if _, err := getCluster(); err == nil {
+		restutils.SendHTTPError(ctx, w, http.StatusConflict, errSmbEnabled)
+		return
+	} else if err != errSmbNotEnabled {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var cluster smbapi.ClusterInfo
+	for _, id := range req.Peers {
+		p, err := peer.GetPeer(id)
+		if err != nil {
+			status, err := restutils.ErrToStatusCode(err)
+			restutils.SendHTTPError(ctx, w, status, err)
+			return
+		}
+		cluster.Peers = append(cluster.Peers, p.ID)
+	}
+
+	if err := txn.Ctx.Set("cluster", &cluster); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "smb.Sync",
+			UndoFunc: "smb.Cleanup",
+			Nodes:    cluster.Peers,
+		},
+		{
+			DoFunc:   "smb.StoreCluster",
+			UndoFunc: "smb.DeleteCluster",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Sync:     true,
+		},
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).Error("failed to enable smb")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, cluster)
+}
+
+func smbDisableHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	txn, err := transaction.NewTxnWithLocks(ctx, lockID)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	defer txn.Done()
+
+	cluster, err := getCluster()
+	if err == errSmbNotEnabled {
+		restutils.SendHTTPError(ctx, w, http.StatusBadRequest, err)
+		return
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := txn.Ctx.Set("cluster", cluster); err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc:   "smb.Cleanup",
+			UndoFunc: "smb.Sync",
+			Nodes:    cluster.Peers,
+		},
+		{
+			DoFunc:   "smb.DeleteCluster",
+			UndoFunc: "smb.StoreCluster",
+			Nodes:    []uuid.UUID{gdctx.MyUUID},
+			Sync:     true,
+		},
+	}
+
+	if err := txn.Do(); err != nil {
+		logger.WithError(err).Error("failed to disable smb")
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusNoContent, nil)
+}
+
+// peersStatus returns the state of Samba on the peers volumes are shared from
+func peersStatus(ctx context.Context, cluster *smbapi.ClusterInfo) ([]smbapi.PeerStatus, error) {
+	txn := transaction.NewTxn(ctx)
+	defer txn.Done()
+
+	// The status of the peers which are down is reported as unknown
+	txn.DontCheckAlive = true
+	txn.DisableRollback = true
+	txn.Steps = []*transaction.Step{
+		{
+			DoFunc: "smb.Status",
+			Nodes:  cluster.Peers,
+		},
+	}
+
+	if err := txn.Do(); err != nil {
+		return nil, err
+	}
+
+	var statuses []smbapi.PeerStatus
+	for _, id := range cluster.Peers {
+		var s smbapi.PeerStatus
+		if err := txn.Ctx.GetNodeResult(id, statusTxnKey, &s); err != nil {
+			s = smbapi.PeerStatus{PeerID: id, Error: "peer is unreachable"}
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// newShare returns the share of the volume with its state on the peers
+func newShare(volname string, statuses []smbapi.PeerStatus) smbapi.Share {
+	share := smbapi.Share{
+		Volume: volname,
+		Name:   shareName(volname),
+		Peers:  []smbapi.PeerShare{},
+	}
+
+	for _, s := range statuses {
+		ps := smbapi.PeerShare{
+			PeerID:  s.PeerID,
+			Running: s.Running,
+			Error:   s.Error,
+		}
+		for _, name := range s.Shares {
+			if name == share.Name {
+				ps.Configured = true
+				break
+			}
+		}
+		share.Peers = append(share.Peers, ps)
+	}
+	return share
+}
+
+func smbStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+
+	resp := smbapi.StatusResp{
+		Peers:  []uuid.UUID{},
+		Shares: []smbapi.Share{},
+	}
+
+	cluster, err := getCluster()
+	if err == errSmbNotEnabled {
+		restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+		return
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	resp.Enabled = true
+	resp.Peers = cluster.Peers
+
+	volumes, err := volume.GetVolumes(ctx)
+	if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	statuses, err := peersStatus(ctx, cluster)
+	if err != nil {
+		logger.WithError(err).Error("failed to get smb status")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	for _, volname := range sharedVolumes(volumes) {
+		resp.Shares = append(resp.Shares, newShare(volname, statuses))
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, resp)
+}
+
+func smbShareGetHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := gdctx.GetReqLogger(ctx)
+	volname := mux.Vars(r)["volname"]
+
+	volinfo, err := volume.GetVolume(volname)
+	if err != nil {
+		status, err := restutils.ErrToStatusCode(err)
+		restutils.SendHTTPError(ctx, w, status, err)
+		return
+	}
+	if !gdctx.ReqNamespaceAllowed(ctx, volinfo.GetNamespace()) {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrVolNotFound)
+		return
+	}
+
+	if !shareEnabled(volinfo) {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errShareDisabled)
+		return
+	}
+	if volinfo.State != volume.VolStarted {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, errors.ErrVolNotStarted)
+		return
+	}
+
+	cluster, err := getCluster()
+	if err == errSmbNotEnabled {
+		restutils.SendHTTPError(ctx, w, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	statuses, err := peersStatus(ctx, cluster)
+	if err != nil {
+		logger.WithError(err).Error("failed to get smb status")
+		restutils.SendHTTPError(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	restutils.SendHTTPResponse(ctx, w, http.StatusOK, newShare(volname, statuses))
+}
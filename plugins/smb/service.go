@@ -0,0 +1,16 @@
+package smb
+
+import (
+	"github.com/gluster/glusterd2/pkg/utils"
+)
+
+// smbdRunning returns true if smbd is running on this peer
+func smbdRunning() bool {
+	return utils.ExecuteCommandRun("smbcontrol", "smbd", "ping") == nil
+}
+
+// reloadSmbd has the running smbd reload its configuration, adding and
+// removing shares without disconnecting the clients of the other shares
+func reloadSmbd() error {
+	return utils.ExecuteCommandRun("smbcontrol", "smbd", "reload-config")
+}
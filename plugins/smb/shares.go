@@ -0,0 +1,170 @@
+package smb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	config "github.com/spf13/viper"
+)
+
+const (
+	// defaultConfDir is the directory the shares file is written to, unless
+	// the smb-confdir setting is set
+	defaultConfDir = "/etc/samba"
+	// sharesFileName is the file with the share sections of the volumes,
+	// which smb.conf must include
+	sharesFileName = "gluster-shares.conf"
+	sharePrefix    = "gluster-"
+	sambaLogDir    = "/var/log/samba"
+)
+
+// smbOptions enable sharing a volume over SMB. user.cifs is the older name
+// of the option used by glusterd1.
+var smbOptions = []string{"user.smb", "user.cifs"}
+
+// sharesMutex serializes the updates of the shares file, which can be
+// triggered by transactions and volume events at the same time
+var sharesMutex sync.Mutex
+
+func confDir() string {
+	if dir := config.GetString("smb-confdir"); dir != "" {
+		return dir
+	}
+	return defaultConfDir
+}
+
+func sharesFile() string {
+	return path.Join(confDir(), sharesFileName)
+}
+
+func shareName(volname string) string {
+	return sharePrefix + volname
+}
+
+// shareEnabled returns true if the volume is to be shared over SMB, that is if
+// user.smb or user.cifs is enabled and neither is disabled
+func shareEnabled(v *volume.Volinfo) bool {
+	enabled := false
+	for _, k := range smbOptions {
+		switch strings.ToLower(v.Options[k]) {
+		case "on", "enable":
+			enabled = true
+		case "off", "disable":
+			return false
+		}
+	}
+	return enabled
+}
+
+// shareSection returns the smb.conf section sharing the volume through the
+// glusterfs VFS module of Samba, like the glusterd1 hook scripts did
+func shareSection(volname string) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "[%s]\n", shareName(volname))
+	fmt.Fprintf(&b, "comment = For samba share of volume %s\n", volname)
+	fmt.Fprintf(&b, "vfs objects = glusterfs\n")
+	fmt.Fprintf(&b, "glusterfs:volume = %s\n", volname)
+	fmt.Fprintf(&b, "glusterfs:logfile = %s/glusterfs-%s.%%M.log\n", sambaLogDir, volname)
+	fmt.Fprintf(&b, "glusterfs:loglevel = 7\n")
+	fmt.Fprintf(&b, "path = /\n")
+	fmt.Fprintf(&b, "read only = no\n")
+	fmt.Fprintf(&b, "kernel share modes = no\n")
+	return b.String()
+}
+
+// sharedVolumes returns the names of the volumes which are shared, the started
+// volumes with user.smb enabled
+func sharedVolumes(volumes []*volume.Volinfo) []string {
+	var names []string
+	for _, v := range volumes {
+		if v.State == volume.VolStarted && shareEnabled(v) {
+			names = append(names, v.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sharesConfig returns the content of the shares file for the volumes
+func sharesConfig(volnames []string) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Generated by glusterd2, do not edit\n")
+	for _, name := range volnames {
+		fmt.Fprintf(&b, "\n%s", shareSection(name))
+	}
+	return b.String()
+}
+
+// parseShares returns the names of the shares in the shares file
+func parseShares(conf string) []string {
+	shares := []string{}
+	for _, line := range strings.Split(conf, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			shares = append(shares, line[1:len(line)-1])
+		}
+	}
+	return shares
+}
+
+// configuredShares returns the names of the shares configured on this peer
+func configuredShares() ([]string, error) {
+	data, err := ioutil.ReadFile(sharesFile())
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return parseShares(string(data)), nil
+}
+
+// writeShares writes the shares file and has Samba reload its configuration
+// if the file changed
+func writeShares(conf string) error {
+	sharesMutex.Lock()
+	defer sharesMutex.Unlock()
+
+	old, err := ioutil.ReadFile(sharesFile())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if string(old) == conf {
+		return nil
+	}
+
+	if err := os.MkdirAll(confDir(), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(sharesFile(), []byte(conf), 0644); err != nil {
+		return err
+	}
+
+	if !smbdRunning() {
+		// The shares are loaded from the file when Samba starts
+		return nil
+	}
+	return reloadSmbd()
+}
+
+// syncShares updates the shares on this peer from the volumes in the store
+func syncShares() error {
+	volumes, err := volume.GetVolumes(context.TODO())
+	if err != nil {
+		return err
+	}
+	return writeShares(sharesConfig(sharedVolumes(volumes)))
+}
+
+// removeShares removes all the shares of volumes on this peer
+func removeShares() error {
+	return writeShares(sharesConfig(nil))
+}
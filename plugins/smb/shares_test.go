@@ -0,0 +1,47 @@
+package smb
+
+import (
+	"testing"
+
+	"github.com/gluster/glusterd2/glusterd2/volume"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShareEnabled(t *testing.T) {
+	for _, tc := range []struct {
+		options map[string]string
+		enabled bool
+	}{
+		{map[string]string{}, false},
+		{map[string]string{"user.smb": "on"}, true},
+		{map[string]string{"user.smb": "Enable"}, true},
+		{map[string]string{"user.cifs": "enable"}, true},
+		{map[string]string{"user.smb": "off"}, false},
+		{map[string]string{"user.smb": "on", "user.cifs": "disable"}, false},
+		{map[string]string{"user.smb": "yes"}, false},
+	} {
+		v := &volume.Volinfo{Options: tc.options}
+		assert.Equal(t, tc.enabled, shareEnabled(v), "options: %v", tc.options)
+	}
+}
+
+func TestSharedVolumes(t *testing.T) {
+	volumes := []*volume.Volinfo{
+		{Name: "vol3", State: volume.VolStarted, Options: map[string]string{"user.smb": "on"}},
+		{Name: "vol2", State: volume.VolStopped, Options: map[string]string{"user.smb": "on"}},
+		{Name: "vol1", State: volume.VolStarted, Options: map[string]string{"user.cifs": "on"}},
+		{Name: "vol4", State: volume.VolStarted, Options: map[string]string{}},
+	}
+	assert.Equal(t, []string{"vol1", "vol3"}, sharedVolumes(volumes))
+}
+
+func TestSharesConfig(t *testing.T) {
+	conf := sharesConfig([]string{"vol1", "vol2"})
+	assert.Contains(t, conf, "vfs objects = glusterfs\n")
+	assert.Contains(t, conf, "glusterfs:volume = vol2\n")
+	assert.Contains(t, conf, "glusterfs:logfile = /var/log/samba/glusterfs-vol1.%M.log\n")
+	assert.Equal(t, []string{"gluster-vol1", "gluster-vol2"}, parseShares(conf))
+
+	assert.Empty(t, parseShares(sharesConfig(nil)))
+}
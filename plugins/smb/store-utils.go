@@ -0,0 +1,49 @@
+package smb
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gluster/glusterd2/glusterd2/store"
+	smbapi "github.com/gluster/glusterd2/plugins/smb/api"
+)
+
+const (
+	smbClusterKey string = "smb/cluster"
+)
+
+// getCluster returns the peers volumes are shared over SMB from, or
+// errSmbNotEnabled if SMB isn't enabled
+func getCluster() (*smbapi.ClusterInfo, error) {
+	resp, err := store.Get(context.TODO(), smbClusterKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Count != 1 {
+		return nil, errSmbNotEnabled
+	}
+
+	var c smbapi.ClusterInfo
+	if err := json.Unmarshal(resp.Kvs[0].Value, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// storeCluster saves the peers volumes are shared over SMB from
+func storeCluster(c *smbapi.ClusterInfo) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.Put(context.TODO(), smbClusterKey, string(data))
+	return err
+}
+
+// deleteCluster deletes the peers volumes are shared over SMB from
+func deleteCluster() error {
+	_, err := store.Delete(context.TODO(), smbClusterKey)
+	return err
+}
@@ -0,0 +1,59 @@
+package smb
+
+import (
+	"github.com/gluster/glusterd2/glusterd2/gdctx"
+	"github.com/gluster/glusterd2/glusterd2/transaction"
+	smbapi "github.com/gluster/glusterd2/plugins/smb/api"
+)
+
+const (
+	statusTxnKey string = "smb-status"
+)
+
+// txnSync writes the shares of the shared volumes on this peer and has
+// Samba reload them
+func txnSync(c transaction.TxnCtx) error {
+	if err := syncShares(); err != nil {
+		c.Logger().WithError(err).Error("failed to update samba shares")
+		return err
+	}
+	return nil
+}
+
+// txnCleanup removes the shares of all the volumes on this peer
+func txnCleanup(c transaction.TxnCtx) error {
+	if err := removeShares(); err != nil {
+		c.Logger().WithError(err).Error("failed to remove samba shares")
+		return err
+	}
+	return nil
+}
+
+func txnStoreCluster(c transaction.TxnCtx) error {
+	var cluster smbapi.ClusterInfo
+	if err := c.Get("cluster", &cluster); err != nil {
+		return err
+	}
+	return storeCluster(&cluster)
+}
+
+func txnDeleteCluster(c transaction.TxnCtx) error {
+	return deleteCluster()
+}
+
+// txnStatus records whether Samba is running on this peer and the shares
+// configured on it
+func txnStatus(c transaction.TxnCtx) error {
+	status := smbapi.PeerStatus{
+		PeerID:  gdctx.MyUUID,
+		Running: smbdRunning(),
+	}
+
+	shares, err := configuredShares()
+	if err != nil {
+		status.Error = err.Error()
+	}
+	status.Shares = shares
+
+	return c.SetNodeResult(gdctx.MyUUID, statusTxnKey, status)
+}
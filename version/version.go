@@ -14,10 +14,23 @@ var (
 
 // MaxOpVersion and APIVersion supported
 const (
-	MaxOpVersion = 50000
+	MaxOpVersion = 50100
 	APIVersion   = 1
 )
 
+// MinOpVersion is the op-version of the peers which do not record the
+// op-version they support, as they run a release older than op-version
+// tracking
+const MinOpVersion = 50000
+
+// Op-versions at which features were introduced. Requests using a feature
+// are refused until the op-version of the cluster is raised to it.
+const (
+	// OpVersionPeerPlacement adds the zone, rack and tags of peers, which
+	// peers running older releases drop when they update their details
+	OpVersionPeerPlacement = 50100
+)
+
 // GlusterdVersion and GitSHA
 // These are set as flags during build time. The current values are just placeholders
 var (